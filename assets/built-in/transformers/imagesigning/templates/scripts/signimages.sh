@@ -0,0 +1,43 @@
+#!/usr/bin/env bash
+#   Copyright IBM Corporation 2022
+#
+#   Licensed under the Apache License, Version 2.0 (the "License");
+#   you may not use this file except in compliance with the License.
+#   You may obtain a copy of the License at
+#
+#        http://www.apache.org/licenses/LICENSE-2.0
+#
+#   Unless required by applicable law or agreed to in writing, software
+#   distributed under the License is distributed on an "AS IS" BASIS,
+#   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+#   See the License for the specific language governing permissions and
+#   limitations under the License.
+
+# Signs the images pushed using pushimages.sh with cosign https://github.com/sigstore/cosign
+# Invoke as ./signimages.sh <registry_url> <registry_namespace>
+# Examples:
+# 1) ./signimages.sh
+# 2) ./signimages.sh quay.io your_quay_username
+
+REGISTRY_URL={{ .RegistryURL }}
+REGISTRY_NAMESPACE={{ .RegistryNamespace }}
+if [ "$#" -gt 1 ]; then
+  REGISTRY_URL=$1
+  REGISTRY_NAMESPACE=$2
+fi
+{{- if eq .Mode "key" }}
+# The password of the key is read from the COSIGN_PASSWORD environment variable if it is set
+COSIGN_KEY=${COSIGN_KEY:-{{ .Key }}}
+SIGN_FLAGS=(--key "${COSIGN_KEY}")
+{{- else }}
+# Keyless signing opens a browser to login. In CI, pass an identity token using the --identity-token flag instead.
+export COSIGN_EXPERIMENTAL=1
+SIGN_FLAGS=(--yes)
+{{- end }}
+{{- range $image := .Images }}
+
+echo 'signing image {{ $image }}'
+cosign sign "${SIGN_FLAGS[@]}" ${REGISTRY_URL}/${REGISTRY_NAMESPACE}/{{ $image }} || exit 1
+{{- end }}
+
+echo 'done'
@@ -0,0 +1,46 @@
+#!/usr/bin/env bash
+#   Copyright IBM Corporation 2022
+#
+#   Licensed under the Apache License, Version 2.0 (the "License");
+#   you may not use this file except in compliance with the License.
+#   You may obtain a copy of the License at
+#
+#        http://www.apache.org/licenses/LICENSE-2.0
+#
+#   Unless required by applicable law or agreed to in writing, software
+#   distributed under the License is distributed on an "AS IS" BASIS,
+#   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+#   See the License for the specific language governing permissions and
+#   limitations under the License.
+
+# Signs the generated manifests with cosign https://github.com/sigstore/cosign
+# The signature of each manifest is written next to it with the .sig extension.
+{{- if eq .Mode "keyless" }}
+# The signing certificate is written next to it with the .pem extension.
+{{- end }}
+# Invoke as ./signmanifests.sh <manifests_directory>
+# Examples:
+# 1) ./signmanifests.sh
+# 2) ./signmanifests.sh ../{{ .ManifestsDir }}/yamls
+
+MANIFESTS_DIR=../{{ .ManifestsDir }}
+if [ "$#" -gt 0 ]; then
+  MANIFESTS_DIR=$1
+fi
+{{- if eq .Mode "key" }}
+# The password of the key is read from the COSIGN_PASSWORD environment variable if it is set
+COSIGN_KEY=${COSIGN_KEY:-{{ .Key }}}
+{{- else }}
+export COSIGN_EXPERIMENTAL=1
+{{- end }}
+
+find "${MANIFESTS_DIR}" -type f \( -name '*.yaml' -o -name '*.yml' \) | while read -r manifest; do
+  echo "signing ${manifest}"
+{{- if eq .Mode "key" }}
+  cosign sign-blob --yes --key "${COSIGN_KEY}" --output-signature "${manifest}.sig" "${manifest}" || exit 1
+{{- else }}
+  cosign sign-blob --yes --output-signature "${manifest}.sig" --output-certificate "${manifest}.pem" "${manifest}" || exit 1
+{{- end }}
+done
+
+echo 'done'
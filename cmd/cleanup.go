@@ -0,0 +1,76 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type cleanupFlags struct {
+	// maxAge is the age after which temporary directories whose run cannot be checked are removed
+	maxAge time.Duration
+	// dryRun only lists the temporary directories that would be removed
+	dryRun bool
+}
+
+// cleanupStaleWorkspaces removes the temporary directories left behind by runs that are no longer running
+func cleanupStaleWorkspaces(maxAge time.Duration, dryRun bool) {
+	if dryRun {
+		staleWorkspaces, err := common.GetStaleWorkspaces(os.TempDir(), maxAge)
+		if err != nil {
+			logrus.Fatalf("Failed to find the stale temporary directories. Error: %q", err)
+		}
+		for _, staleWorkspace := range staleWorkspaces {
+			fmt.Printf("%s\t%s\n", staleWorkspace.Path, staleWorkspace.Reason)
+		}
+		return
+	}
+	removed, err := common.CleanupStaleWorkspaces(os.TempDir(), maxAge)
+	if err != nil {
+		logrus.Errorf("Failed to remove the stale temporary directories. Error: %q", err)
+		return
+	}
+	logrus.Infof("Removed %d stale temporary directories", len(removed))
+}
+
+// GetCleanupCommand returns a command to remove the temporary directories left behind by previous runs
+func GetCleanupCommand() *cobra.Command {
+	viper.AutomaticEnv()
+
+	flags := cleanupFlags{}
+	cleanupCmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Remove the temporary directories left behind by previous runs.",
+		Long: `Remove the temporary directories left behind by previous runs that crashed or were killed.
+A temporary directory is only removed if the run that created it is no longer running.
+Directories created by older versions or on other hosts, whose run cannot be checked, are removed once they are older than the max age.`,
+		Args: cobra.NoArgs,
+		Run:  func(*cobra.Command, []string) { cleanupStaleWorkspaces(flags.maxAge, flags.dryRun) },
+	}
+
+	cleanupCmd.Flags().DurationVar(&flags.maxAge, "max-age", common.DefaultStaleWorkspaceAge, "Remove the temporary directories whose run cannot be checked once they are older than this.")
+	cleanupCmd.Flags().BoolVar(&flags.dryRun, "dry-run", false, "Only list the temporary directories that would be removed.")
+
+	return cleanupCmd
+}
@@ -0,0 +1,153 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/assets"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/types/plan"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// builtInTransformersDir is the directory of the built-in transformers inside the embedded assets
+	builtInTransformersDir = "built-in/transformers"
+	// builtInPresetsDir is the directory of the presets inside the embedded assets
+	builtInPresetsDir = "built-in/presets"
+)
+
+// registerFlagCompletions completes the values of the flags that can be looked up, when the command has them
+func registerFlagCompletions(cmd *cobra.Command) error {
+	completions := map[string]func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective){
+		transformerSelectorFlag: completeTransformerSelector,
+		preSetFlag:              completePresets,
+		servicesFlag:            completeServices,
+	}
+	for flagName, completion := range completions {
+		if cmd.Flags().Lookup(flagName) == nil {
+			continue
+		}
+		if err := cmd.RegisterFlagCompletionFunc(flagName, completion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// completeTransformerSelector completes the labels of the built-in transformers and the transformers in the customizations
+func completeTransformerSelector(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	labels := []string{}
+	addLabels := func(data []byte) {
+		transformer := transformertypes.Transformer{}
+		if err := yaml.Unmarshal(data, &transformer); err != nil || transformer.Kind != transformertypes.TransformerKind {
+			return
+		}
+		for key, value := range transformer.Labels {
+			labels = common.AppendIfNotPresent(labels, key+"="+value)
+		}
+	}
+	_ = fs.WalkDir(assets.AssetsDir, builtInTransformersDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !isYamlFile(path) {
+			return nil
+		}
+		if data, err := assets.AssetsDir.ReadFile(path); err == nil {
+			addLabels(data)
+		}
+		return nil
+	})
+	if f := cmd.Flags().Lookup(customizationsFlag); f != nil && f.Value.String() != "" {
+		_ = filepath.WalkDir(f.Value.String(), func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !isYamlFile(path) {
+				return nil
+			}
+			if data, err := os.ReadFile(path); err == nil {
+				addLabels(data)
+			}
+			return nil
+		})
+	}
+	return completeCommaSeparated(toComplete, labels), cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+}
+
+// completePresets completes the names of the built-in presets
+func completePresets(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	presets := []string{}
+	entries, err := assets.AssetsDir.ReadDir(builtInPresetsDir)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && isYamlFile(entry.Name()) {
+			presets = append(presets, strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())))
+		}
+	}
+	return completeCommaSeparated(toComplete, presets), cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeServices completes the names of the services in the plan file given using the plan flag
+func completeServices(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	planPath := common.DefaultPlanFile
+	if f := cmd.Flags().Lookup(planFlag); f != nil && f.Value.String() != "" {
+		planPath = f.Value.String()
+	}
+	if fi, err := os.Stat(planPath); err == nil && fi.IsDir() {
+		planPath = filepath.Join(planPath, common.DefaultPlanFile)
+	}
+	data, err := os.ReadFile(planPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	p := plan.Plan{}
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	services := []string{}
+	for serviceName := range p.Spec.Services {
+		services = append(services, serviceName)
+	}
+	return completeCommaSeparated(toComplete, services), cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCommaSeparated completes the last value of a comma separated list, skipping the values that are already in the list
+func completeCommaSeparated(toComplete string, candidates []string) []string {
+	prefix, last := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, last = toComplete[:i+1], toComplete[i+1:]
+	}
+	existing := strings.Split(prefix, ",")
+	completions := []string{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, last) && !common.IsPresent(existing, candidate) {
+			completions = append(completions, prefix+candidate)
+		}
+	}
+	sort.Strings(completions)
+	return completions
+}
+
+func isYamlFile(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".yaml" || ext == ".yml"
+}
@@ -0,0 +1,90 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+	"github.com/spf13/cobra"
+)
+
+func TestCompleteCommaSeparated(t *testing.T) {
+	candidates := []string{"web", "api", "worker"}
+	testCases := []struct {
+		name       string
+		toComplete string
+		want       []string
+	}{
+		{name: "empty", toComplete: "", want: []string{"api", "web", "worker"}},
+		{name: "prefix of the first value", toComplete: "w", want: []string{"web", "worker"}},
+		{name: "after a comma", toComplete: "api,", want: []string{"api,web", "api,worker"}},
+		{name: "prefix of the last value", toComplete: "api,wo", want: []string{"api,worker"}},
+		{name: "the values already in the list are skipped", toComplete: "web,api,", want: []string{"web,api,worker"}},
+		{name: "no matches", toComplete: "db", want: []string{}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := completeCommaSeparated(testCase.toComplete, candidates); !cmp.Equal(got, testCase.want) {
+				t.Fatalf("the completions are incorrect. Expected: %+v Actual: %+v", testCase.want, got)
+			}
+		})
+	}
+}
+
+func TestCompleteServices(t *testing.T) {
+	planDir := t.TempDir()
+	planYaml := `apiVersion: move2kube.konveyor.io/v1alpha1
+kind: Plan
+metadata:
+  name: app
+spec:
+  sourceDir: src
+  services:
+    web: []
+    api: []
+`
+	if err := os.WriteFile(filepath.Join(planDir, common.DefaultPlanFile), []byte(planYaml), common.DefaultFilePermission); err != nil {
+		t.Fatalf("failed to write the plan. Error: %q", err)
+	}
+	testCases := []struct {
+		name       string
+		planPath   string
+		toComplete string
+		want       []string
+	}{
+		{name: "plan file", planPath: filepath.Join(planDir, common.DefaultPlanFile), want: []string{"api", "web"}},
+		{name: "directory containing the plan file", planPath: planDir, toComplete: "w", want: []string{"web"}},
+		{name: "missing plan file", planPath: filepath.Join(planDir, "missing.plan")},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().String(planFlag, testCase.planPath, "")
+			got, directive := completeServices(cmd, nil, testCase.toComplete)
+			if !cmp.Equal(got, testCase.want) {
+				t.Fatalf("the completions are incorrect. Expected: %+v Actual: %+v", testCase.want, got)
+			}
+			if directive&cobra.ShellCompDirectiveNoFileComp == 0 {
+				t.Fatalf("expected the file completion to be disabled. Actual directive: %d", directive)
+			}
+		})
+	}
+}
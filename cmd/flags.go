@@ -16,6 +16,8 @@
 
 package cmd
 
+import "time"
+
 const (
 	// sourceFlag is the name of the flag that contains path to the source folder
 	sourceFlag = "source"
@@ -31,33 +33,109 @@ const (
 	qaSkipFlag = "qa-skip"
 	// qaPersistPasswords is the name of the flag that lets choose to persist passwords
 	qaPersistPasswords = "qa-persist-passwords"
+	// qaVaultPathTemplateFlag is the name of the flag that contains the template of the paths of the Vault secrets that answer the password questions
+	qaVaultPathTemplateFlag = "qa-vault-path-template"
+	// qaTimeoutFlag is the name of the flag that contains how long a question can stay unanswered before the default answer is used
+	qaTimeoutFlag = "qa-timeout"
+	// qaEncryptionCertFlag is the name of the flag that contains the path of the certificate the config and the cache are encrypted with
+	qaEncryptionCertFlag = "qa-encryption-cert"
+	// qaDecryptionKeyFlag is the name of the flag that contains the path of the private key that decrypts the config and the cache
+	qaDecryptionKeyFlag = "qa-decryption-key"
 	// configOutFlag is the name of the flag that will point the location to output the config file
 	configOutFlag = "config-out"
 	// qaCacheOutFlag is the name of the flag that will point the location to output the cache file
 	qaCacheOutFlag = "qa-cache-out"
+	// qaSessionOutFlag is the name of the flag that contains the path to record the QA session to
+	qaSessionOutFlag = "qa-session-out"
+	// qaReplayFlag is the name of the flag that contains the path of the QA session to replay
+	qaReplayFlag = "qa-replay"
+	// qaReportFlag is the name of the flag that contains the format of the report of the questions and their answers
+	qaReportFlag = "qa-report"
+	// qaEnableCategoriesFlag is the name of the flag that contains the only categories of questions that are asked
+	qaEnableCategoriesFlag = "qa-enable-categories"
+	// qaDisableCategoriesFlag is the name of the flag that contains the categories of questions that are answered using the defaults
+	qaDisableCategoriesFlag = "qa-disable-categories"
 	// configFlag is the name of the flag that contains list of config files
 	configFlag = "config"
 	// setConfigFlag is the name of the flag that contains list of key-value configs
 	setConfigFlag = "set-config"
 	// preSetFlag is the name of the flag that contains list of preset configurations to use
 	preSetFlag = "preset"
+	// profileFlag is the name of the flag that contains the profile in the config files to use
+	profileFlag = "profile"
 	// overwriteFlag is the name of the flag that lets you overwrite the output directory if it exists
 	overwriteFlag = "overwrite"
 	// customizationsFlag is the path to customizations directory
 	customizationsFlag      = "customizations"
 	qadisablecliFlag        = "qa-disable-cli"
 	qaportFlag              = "qa-port"
+	qagrpcFlag              = "qa-grpc"
 	planProgressPortFlag    = "plan-progress-port"
+	progressFileFlag        = "progress-file"
 	transformerSelectorFlag = "transformer-selector"
+	// environmentsFlag is the name of the flag that contains the list of target environments used for parameterization
+	environmentsFlag = "environments"
+	// servicesFlag is the name of the flag that contains the services in the plan that are transformed
+	servicesFlag = "services"
+	// graphFormatFlag is the name of the flag that contains the format to export the graph in
+	graphFormatFlag = "format"
+	// webhookFlag is the name of the flag that contains the urls that receive the lifecycle events
+	webhookFlag = "webhook"
+	// webhookStallTimeoutFlag is the name of the flag that contains how long a question can stay unanswered before an event is sent
+	webhookStallTimeoutFlag = "webhook-stall-timeout"
+	// otlpEndpointFlag is the name of the flag that contains the OTLP collector endpoint the traces are exported to
+	otlpEndpointFlag = "otlp-endpoint"
+	// otlpInsecureFlag is the name of the flag that disables TLS when exporting the traces
+	otlpInsecureFlag = "otlp-insecure"
+	// exportGraphFlag is the name of the flag that contains the path the plan and transformation graph are exported to
+	exportGraphFlag = "export-graph"
+	// reviewFlag is the name of the flag that enables the interactive review of the plan before it is written
+	reviewFlag = "review"
+	// explainFlag is the name of the flag that enables recording why the transformers did or did not detect services
+	explainFlag = "explain"
+	// transformerIndexFlag is the name of the flag that contains the locations of the transformer indexes
+	transformerIndexFlag = "index"
+	// validateAgainstClusterFlag is the name of the flag that contains the kubeconfig context the output is dry run against
+	validateAgainstClusterFlag = "validate-against-cluster"
+	// outputLogFlag is the name of the flag that writes the logs to a file inside the output directory
+	outputLogFlag = "output-log"
+	// pushFlag is the name of the flag that pushes the output to a new branch of a git repo
+	pushFlag = "push"
+	// outputFormatFlag is the name of the flag that contains whether the output is written as a directory or an archive
+	outputFormatFlag = "output-format"
+	// outputLayoutFlag is the name of the flag that contains the layout of the output directory
+	outputLayoutFlag = "output-layout"
+	// stdoutFlag is the name of the flag that writes the generated manifests to stdout
+	stdoutFlag = "stdout"
+	// symlinksFlag is the name of the flag that contains how the symbolic links in the source are handled
+	symlinksFlag = "symlinks"
+	// maxFileSizeFlag is the name of the flag that contains the size above which source files are skipped
+	maxFileSizeFlag = "max-file-size"
+	// maxDirectoryFilesFlag is the name of the flag that contains the number of entries above which source directories are skipped
+	maxDirectoryFilesFlag = "max-directory-files"
+	// maxDetectDepthFlag is the name of the flag that contains the depth after which source directories are not searched for services
+	maxDetectDepthFlag = "max-detect-depth"
+	// excludeDirsFlag is the name of the flag that contains the glob patterns of the source directories that are not searched for services
+	excludeDirsFlag = "exclude-dirs"
+	// debugTransformerFlag is the name of the flag that contains the starlark transformers to debug interactively
+	debugTransformerFlag = "debug-transformer"
+	// watchCustomizationsFlag is the name of the flag that keeps watching the customizations and transforms again when they change
+	watchCustomizationsFlag = "watch-customizations"
 )
 
 type qaflags struct {
 	qadisablecli bool
 	qaport       int
+	// qagrpc serves the questions using the gRPC QA service instead of the HTTP REST service
+	qagrpc bool
 	// configOut contains the location to output the config
 	configOut string
 	// qaCacheOut contains the location to output the cache
 	qaCacheOut string
+	// qaSessionOut contains the location to record the QA session to
+	qaSessionOut string
+	// qaReplay contains the location of the QA session whose answers are replayed
+	qaReplay string
 	// configs contains a list of config files
 	configs []string
 	// Configs contains a list of key-value configs
@@ -66,6 +144,20 @@ type qaflags struct {
 	qaskip bool
 	// preSets contains a list of preset configurations
 	preSets []string
+	// profile is the name of the profile in the config files whose answers override the shared answers
+	profile string
 	// persistPasswords sets whether to persist the password or not
 	persistPasswords bool
+	// qaTimeout is how long a question can stay unanswered before the default answer is used
+	qaTimeout time.Duration
+	// qaEncryptionCert is the path of the PEM encoded certificate the config and the cache are encrypted with
+	qaEncryptionCert string
+	// qaDecryptionKey is the path of the PEM encoded RSA private key that decrypts the config and the cache
+	qaDecryptionKey string
+	// qaEnableCategories are the only categories of questions that are asked
+	qaEnableCategories []string
+	// qaDisableCategories are the categories of questions that are answered using the defaults
+	qaDisableCategories []string
+	// vaultPathTemplate is the template of the paths of the Vault secrets that answer the password questions
+	vaultPathTemplate string
 }
@@ -20,11 +20,14 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/konveyor/move2kube/common"
 	graphutils "github.com/konveyor/move2kube/graph"
+	"github.com/konveyor/move2kube/lib"
 	"github.com/konveyor/move2kube/types"
 	graphtypes "github.com/konveyor/move2kube/types/graph"
+	plantypes "github.com/konveyor/move2kube/types/plan"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -32,21 +35,53 @@ import (
 
 type graphFlags struct {
 	graphFilePath string
+	planFilePath  string
 	port          int32
 	outputPath    string
+	format        string
 }
 
-func graphHandler(flags graphFlags) {
+// getGraph reads the graph from the graph file, or creates it from the plan file if one is given
+func getGraph(flags graphFlags) graphtypes.Graph {
+	if flags.planFilePath != "" {
+		plan, err := plantypes.ReadPlan(flags.planFilePath, "")
+		if err != nil {
+			logrus.Fatalf("failed to read the plan file at path %s . Error: %q", flags.planFilePath, err)
+		}
+		return lib.GetPlanGraph(plan)
+	}
 	graphFilePath := filepath.Clean(flags.graphFilePath)
-	outputPath := filepath.Clean(flags.outputPath)
 	graphFile, err := os.Open(graphFilePath)
 	if err != nil {
 		logrus.Fatalf("failed to the open the graph file at path %s . Error: %q", graphFilePath, err)
 	}
+	defer graphFile.Close()
 	graph := graphtypes.Graph{}
 	if err := json.NewDecoder(graphFile).Decode(&graph); err != nil {
 		logrus.Fatalf("failed to decode the json file at path %s . Error: %q", graphFilePath, err)
 	}
+	return graph
+}
+
+func graphHandler(flags graphFlags) {
+	graph := getGraph(flags)
+	if flags.format != "" {
+		data, err := graphutils.Export(graph, flags.format)
+		if err != nil {
+			logrus.Fatalf("failed to export the graph. Error: %q", err)
+		}
+		if flags.outputPath == "" || flags.outputPath == "-" {
+			if _, err := os.Stdout.Write(data); err != nil {
+				logrus.Fatalf("failed to write the graph to stdout. Error: %q", err)
+			}
+			return
+		}
+		if err := os.WriteFile(filepath.Clean(flags.outputPath), data, common.DefaultFilePermission); err != nil {
+			logrus.Fatalf("failed to write the graph to a file at path %s . Error: %q", flags.outputPath, err)
+		}
+		return
+	}
+	outputPath := filepath.Clean(flags.outputPath)
 	nodes, edges := graphutils.GetNodesAndEdges(graph)
 	graphutils.DfsUpdatePositions(nodes, edges)
 	webGraph := graphtypes.GraphT{Nodes: nodes, Edges: edges}
@@ -68,15 +103,19 @@ func GetGraphCommand() *cobra.Command {
 	viper.AutomaticEnv()
 	flags := graphFlags{}
 	graphCmd := &cobra.Command{
-		Use:   "graph [-g path/to/m2k-graph.json]",
+		Use:   "graph [-f path/to/m2k-graph.json]",
 		Short: "View the graph generated by transform command.",
 		Long: `View the graph generated by transform command. This command starts a server to serve a web UI and display the graph.
 	To see the graph, go to http://localhost:8080/ in a browser.
-	By default, it will look for the m2k-graph.json file in the current working directory.`,
+	By default, it will look for the m2k-graph.json file in the current working directory.
+	Use --format to export the flow of the artifacts between the transformers as json, dot or mermaid instead.
+	Use --plan to see which transformers the services in a plan will be transformed by.`,
 		Run: func(_ *cobra.Command, __ []string) { graphHandler(flags) },
 	}
 	graphCmd.Flags().StringVarP(&flags.graphFilePath, "graph", "f", "m2k-graph.json", "Path to a m2k-graph.json file generated by the transform command.")
 	graphCmd.Flags().Int32VarP(&flags.port, "port", "p", 8080, "Port to start the server on.")
-	graphCmd.Flags().StringVarP(&flags.outputPath, "output", "o", "", "Path where the processed graph json file should be generated. If this flag is used then instead of starting a web server, we will output a file. By default "+types.AppName+" does not output this file.")
+	graphCmd.Flags().StringVar(&flags.planFilePath, planFlag, "", "Path to a plan file to create the graph from, instead of the graph file.")
+	graphCmd.Flags().StringVarP(&flags.outputPath, outputFlag, "o", "", "Path where the processed graph json file should be generated. If this flag is used then instead of starting a web server, we will output a file. By default "+types.AppName+" does not output this file.")
+	graphCmd.Flags().StringVar(&flags.format, graphFormatFlag, "", "Export the graph in one of the formats: "+strings.Join(graphutils.ExportFormats, ", ")+". The graph is written to the --output file, or to stdout if it is not given or is -.")
 	return graphCmd
 }
@@ -18,16 +18,21 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"io/fs"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/i18n"
+	"github.com/konveyor/move2kube/common/webhook"
 	"github.com/konveyor/move2kube/lib"
 	"github.com/konveyor/move2kube/qaengine"
 	plantypes "github.com/konveyor/move2kube/types/plan"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -35,6 +40,11 @@ import (
 
 type planFlags struct {
 	progressServerPort    int
+	progressFile          string
+	webhooks              []string
+	webhookStallTimeout   time.Duration
+	otlpEndpoint          string
+	otlpInsecure          bool
 	planfile              string
 	srcpath               string
 	name                  string
@@ -42,12 +52,36 @@ type planFlags struct {
 	transformerSelector   string
 	disableLocalExecution bool
 	failOnEmptyPlan       bool
+	// environments contains the list of target environments used for parameterization
+	environments []string
 	//Configs contains a list of config files
 	configs []string
 	//Configs contains a list of key-value configs
 	setconfigs []string
 	//PreSets contains a list of preset configurations
 	preSets []string
+	// profile is the name of the profile in the config files whose answers override the shared answers
+	profile string
+	// qaDecryptionKey is the path of the PEM encoded RSA private key that decrypts the config files
+	qaDecryptionKey string
+	// exportGraph is the path the plan is exported to for external tools
+	exportGraph string
+	// review lets the user interactively edit the plan before it is written
+	review bool
+	// explain records why the transformers did or did not detect services in each directory
+	explain bool
+	// symlinks is how the symbolic links in the source are handled
+	symlinks string
+	// maxFileSize is the size above which the source files are skipped
+	maxFileSize string
+	// maxDirectoryFiles is the number of entries above which the source directories are skipped
+	maxDirectoryFiles int
+	// maxDetectDepth is the depth after which the source directories are not searched for services
+	maxDetectDepth int
+	// excludeDirs are the glob patterns of the source directories that are not searched for services
+	excludeDirs []string
+	// debugTransformers are the starlark transformers that pause at their breakpoints
+	debugTransformers []string
 }
 
 func planHandler(cmd *cobra.Command, flags planFlags) {
@@ -62,12 +96,20 @@ func planHandler(cmd *cobra.Command, flags planFlags) {
 		common.Interrupt()
 	}()
 	defer lib.Destroy()
+	shutdownTracing := setupTracing(ctx, flags.otlpEndpoint, flags.otlpInsecure)
+	defer shutdownTracing()
+	webhook.SetURLs(flags.webhooks)
+	logrus.AddHook(webhook.NewFailureHook(webhook.PlanFailed))
 
 	var err error
+	sourceURL := getRemoteURL(flags.srcpath)
+	customizationsURL := getRemoteURL(flags.customizationsPath)
+	flags.srcpath = fetchRemotePathIfRequired(flags.srcpath)
 	planfile := flags.planfile
 	srcpath := flags.srcpath
 	name := flags.name
 
+	flags.customizationsPath = fetchRemotePathIfRequired(flags.customizationsPath)
 	// Check if the default customization folder exists in the working directory.
 	// If not, skip the customization option
 	if !cmd.Flags().Changed(customizationsFlag) {
@@ -76,7 +118,7 @@ func planHandler(cmd *cobra.Command, flags planFlags) {
 			// make all path(s) absolute
 			flags.customizationsPath, err = filepath.Abs(flags.customizationsPath)
 			if err != nil {
-				logrus.Fatalf("Failed to make the customizations directory path %q absolute. Error: %q", flags.customizationsPath, err)
+				logrus.Fatalf(i18n.T("Failed to make the customizations directory path %q absolute. Error: %q"), flags.customizationsPath, err)
 			}
 		}
 	}
@@ -89,6 +131,9 @@ func planHandler(cmd *cobra.Command, flags planFlags) {
 	}
 	// make all path(s) absolute
 	for i, c := range flags.configs {
+		if qatypes.IsRemoteConfigPath(c) {
+			continue
+		}
 		if c, err := filepath.Abs(c); err != nil {
 			logrus.Fatalf("failed to make the config file path %s absolute. Error: %q", c, err)
 		}
@@ -98,24 +143,34 @@ func planHandler(cmd *cobra.Command, flags planFlags) {
 	customizationsPath := flags.customizationsPath
 	// Global settings
 	common.DisableLocalExecution = flags.disableLocalExecution
+	if err := common.SetSymlinkPolicy(common.SymlinkPolicyT(flags.symlinks)); err != nil {
+		logrus.Fatalf("failed to set the symlink policy. Error: %q", err)
+	}
+	common.DebugTransformers = flags.debugTransformers
+	if err := common.SetSourceLimits(flags.maxFileSize, flags.maxDirectoryFiles); err != nil {
+		logrus.Fatalf("failed to set the source limits. Error: %q", err)
+	}
+	if err := common.SetDetectionLimits(flags.maxDetectDepth, flags.excludeDirs); err != nil {
+		logrus.Fatalf("failed to set the detection limits. Error: %q", err)
+	}
 	// Global settings
 
 	planfile, err = filepath.Abs(planfile)
 	if err != nil {
-		logrus.Fatalf("Failed to make the plan file path %q absolute. Error: %q", planfile, err)
+		logrus.Fatalf(i18n.T("Failed to make the plan file path %q absolute. Error: %q"), planfile, err)
 	}
 	var fi fs.FileInfo
 	if srcpath != "" {
 		srcpath, err = filepath.Abs(srcpath)
 		if err != nil {
-			logrus.Fatalf("Failed to make the source directory path %q absolute. Error: %q", srcpath, err)
+			logrus.Fatalf(i18n.T("Failed to make the source directory path %q absolute. Error: %q"), srcpath, err)
 		}
 		fi, err = os.Stat(srcpath)
 		if err != nil {
-			logrus.Fatalf("Unable to access source directory : %s", err)
+			logrus.Fatalf(i18n.T("Unable to access source directory : %s"), err)
 		}
 		if !fi.IsDir() {
-			logrus.Fatalf("Input is a file, expected directory: %s", srcpath)
+			logrus.Fatalf(i18n.T("Input is a file, expected directory: %s"), srcpath)
 		}
 	}
 	fi, err = os.Stat(planfile)
@@ -126,30 +181,63 @@ func planHandler(cmd *cobra.Command, flags planFlags) {
 			planfile = filepath.Join(planfile, common.DefaultPlanFile)
 		}
 	} else if err != nil {
-		logrus.Fatalf("Error while accessing plan file path %s : %s ", planfile, err)
+		logrus.Fatalf(i18n.T("Error while accessing plan file path %s : %s "), planfile, err)
 	} else if fi.IsDir() {
 		planfile = filepath.Join(planfile, common.DefaultPlanFile)
 	}
-	qaengine.StartEngine(true, 0, true)
-	qaengine.SetupConfigFile("", flags.setconfigs, flags.configs, flags.preSets, false)
+	setupQAEncryption("", flags.qaDecryptionKey)
+	qaengine.StartEngine(true, 0, true, false)
+	qaengine.SetupConfigFile("", flags.setconfigs, flags.configs, flags.preSets, flags.profile, false)
+	qaengine.SetupEnvStore()
+	setupWebhooks(flags.webhooks, flags.webhookStallTimeout)
 	if flags.progressServerPort != 0 {
 		startPlanProgressServer(flags.progressServerPort)
 	}
+	if flags.progressFile != "" {
+		common.SetProgressFile(flags.progressFile)
+	}
+	common.SetExplainDetection(flags.explain)
 	p, err := lib.CreatePlan(ctx, srcpath, "", customizationsPath, flags.transformerSelector, name)
 	if err != nil {
 		logrus.Fatalf("failed to create the plan. Error: %q", err)
 	}
+	p.Spec.SourceURL = sourceURL
+	p.Spec.CustomizationsURL = customizationsURL
+	p.Spec.Environments = flags.environments
+	p.Spec.SourceLimits = plantypes.SourceLimits{MaxFileSize: flags.maxFileSize, MaxDirectoryFiles: flags.maxDirectoryFiles}
+	p.Spec.DetectionLimits = plantypes.DetectionLimits{MaxDetectDepth: flags.maxDetectDepth, ExcludeDirs: flags.excludeDirs}
+	if flags.review {
+		if p, err = lib.ReviewPlan(p); err != nil {
+			logrus.Fatalf("failed to review the plan. Error: %q", err)
+		}
+	}
 	if err = plantypes.WritePlan(planfile, p); err != nil {
 		logrus.Fatalf("failed to write the plan to file at path %s . Error: %q", planfile, err)
 	}
 	logrus.Debugf("Plan : %+v", p)
-	logrus.Infof("Plan can be found at [%s].", planfile)
+	logrus.Infof(i18n.T("Plan can be found at [%s]."), planfile)
+	if flags.explain {
+		explanationPath := filepath.Join(filepath.Dir(planfile), common.DetectionExplanationFile)
+		if err := lib.WriteDetectionExplanation(explanationPath); err != nil {
+			logrus.Errorf("failed to write the detection explanation. Error: %q", err)
+		} else {
+			logrus.Infof(i18n.T("The detection explanation can be found at [%s]."), explanationPath)
+		}
+	}
+	if flags.exportGraph != "" {
+		if err := lib.ExportGraph(p, flags.exportGraph); err != nil {
+			logrus.Errorf("failed to export the plan. Error: %q", err)
+		} else {
+			logrus.Infof(i18n.T("Exported the plan to [%s]."), flags.exportGraph)
+		}
+	}
 	if len(p.Spec.Services) == 0 && len(p.Spec.InvokedByDefaultTransformers) == 0 {
 		if flags.failOnEmptyPlan {
-			logrus.Fatalf("Did not detect any services in the directory %s . Also we didn't find any default transformers to run.", srcpath)
+			logrus.Fatalf(i18n.T("Did not detect any services in the directory %s . Also we didn't find any default transformers to run."), srcpath)
 		}
-		logrus.Warnf("Did not detect any services in the directory %s . Also we didn't find any default transformers to run.", srcpath)
+		logrus.Warnf(i18n.T("Did not detect any services in the directory %s . Also we didn't find any default transformers to run."), srcpath)
 	}
+	webhook.Send(webhook.PlanCompleted, fmt.Sprintf("the plan can be found at %s", planfile), map[string]interface{}{"planFile": planfile, "services": len(p.Spec.Services)})
 }
 
 // GetPlanCommand returns a command to do the planning
@@ -169,19 +257,39 @@ func GetPlanCommand() *cobra.Command {
 		Run:   func(cmd *cobra.Command, _ []string) { planHandler(cmd, flags) },
 	}
 
-	planCmd.Flags().StringVarP(&flags.srcpath, sourceFlag, "s", "", "Specify source directory.")
+	planCmd.Flags().StringVarP(&flags.srcpath, sourceFlag, "s", "", "Specify source directory. Can also be a git remote path of the form git+<repo url>[//<path within repo>][?ref=<ref>&depth=<n>&submodules=<bool>&sparsecheckout=<bool>], a git repo url ending in .git of the form <repo url>[#<ref>[:<path within repo>]] which is shallow cloned, or a zip/tar.gz archive, either a local file or a url of the form s3://<bucket>/<key> or https://<host>/<path>, optionally suffixed with #sha256=<checksum>. Archives are extracted into a temp directory and their checksum is recorded in the plan")
 	planCmd.Flags().StringVarP(&flags.planfile, planFlag, "p", common.DefaultPlanFile, "Specify a file path to save plan to.")
 	planCmd.Flags().StringVarP(&flags.name, nameFlag, "n", common.DefaultProjectName, "Specify the project name.")
-	planCmd.Flags().StringVarP(&flags.customizationsPath, customizationsFlag, "c", "", "Specify directory where customizations are stored. By default we look for "+common.DefaultCustomizationDir)
-	planCmd.Flags().StringSliceVarP(&flags.configs, configFlag, "f", []string{}, "Specify config file locations. By default we look for "+common.DefaultConfigFilePath)
+	planCmd.Flags().StringVarP(&flags.customizationsPath, customizationsFlag, "c", "", "Specify directory where customizations are stored. Can also be a git remote path. By default we look for "+common.DefaultCustomizationDir)
+	planCmd.Flags().StringSliceVarP(&flags.configs, configFlag, "f", []string{}, "Specify config file locations. Can also be http(s) urls, optionally suffixed with #sha256=<checksum>. The "+qatypes.RemoteConfigTokenEnvVar+" environment variable is sent as a bearer token. By default we look for "+common.DefaultConfigFilePath)
 	planCmd.Flags().StringVarP(&flags.transformerSelector, transformerSelectorFlag, "t", "", "Specify the transformer selector.")
 	planCmd.Flags().StringSliceVar(&flags.preSets, preSetFlag, []string{}, "Specify preset config to use.")
+	planCmd.Flags().StringVar(&flags.profile, profileFlag, "", "Specify the profile (like dev, stage or prod) under the "+qatypes.ProfilesKey+" key of the config files whose answers override the shared answers.")
+	planCmd.Flags().StringVar(&flags.qaDecryptionKey, qaDecryptionKeyFlag, "", "Decrypt the config files that were encrypted using a certificate with this PEM encoded RSA private key. The config files encrypted using a passphrase are decrypted using the "+qatypes.QAPassphraseEnvVar+" environment variable.")
 	planCmd.Flags().StringArrayVar(&flags.setconfigs, setConfigFlag, []string{}, "Specify config key-value pairs.")
+	planCmd.Flags().StringSliceVar(&flags.environments, environmentsFlag, []string{}, "Specify the target environments (like dev,staging,prod) to generate parameterized output for.")
 	planCmd.Flags().IntVar(&flags.progressServerPort, planProgressPortFlag, 0, "Port for the plan progress server. If not provided, the server won't be started.")
 	planCmd.Flags().BoolVar(&flags.disableLocalExecution, common.DisableLocalExecutionFlag, false, "Allow files to be executed locally.")
 	planCmd.Flags().BoolVar(&flags.failOnEmptyPlan, common.FailOnEmptyPlan, false, "If true, planning will exit with a failure exit code if no services are detected (and no default transformers are found).")
 
+	planCmd.Flags().StringVar(&flags.otlpEndpoint, otlpEndpointFlag, "", "Export the traces to the OTLP collector at this host:port. The OTEL_EXPORTER_OTLP_* environment variables are also supported.")
+	planCmd.Flags().BoolVar(&flags.otlpInsecure, otlpInsecureFlag, false, "Disable TLS when exporting the traces.")
+	planCmd.Flags().StringSliceVar(&flags.webhooks, webhookFlag, []string{}, "Specify the urls that should receive the plan lifecycle events as json.")
+	planCmd.Flags().DurationVar(&flags.webhookStallTimeout, webhookStallTimeoutFlag, 5*time.Minute, "Send an event to the webhooks if a question stays unanswered for this long.")
+	planCmd.Flags().StringVar(&flags.exportGraph, exportGraphFlag, "", "Export the plan to this file for external tools. Files ending with .pb or .binpb are written as protobuf, others as json.")
+	planCmd.Flags().BoolVar(&flags.explain, explainFlag, false, "Record which transformers matched each directory, which did not and why, and write it alongside the plan.")
+	planCmd.Flags().StringVar(&flags.maxFileSize, maxFileSizeFlag, "", "Skip the source files larger than this size (like 100Mi) during detection and copying. The skipped files are listed in the migration report.")
+	planCmd.Flags().IntVar(&flags.maxDirectoryFiles, maxDirectoryFilesFlag, 0, "Skip the source directories with more than this many files during detection and copying. The skipped directories are listed in the migration report.")
+	planCmd.Flags().IntVar(&flags.maxDetectDepth, maxDetectDepthFlag, 0, "Do not search for services in the source directories more than this many levels below the source directory. By default all the levels are searched.")
+	planCmd.Flags().StringSliceVar(&flags.excludeDirs, excludeDirsFlag, []string{}, "Do not search for services in the source directories matching these glob patterns, without requiring "+common.IgnoreFilename+" files. Patterns without a / (like vendor) match the directory name at any depth, others (like third_party/*) match the path relative to the source directory.")
+	planCmd.Flags().StringSliceVar(&flags.debugTransformers, debugTransformerFlag, []string{}, "Open an interactive starlark prompt with the globals, the artifacts and the QA engine of these starlark transformers before they transform and at each m2k.breakpoint() call.")
+	planCmd.Flags().StringVar(&flags.symlinks, symlinksFlag, string(common.FollowSymlinks), "Specify how the symbolic links in the source are handled. One of follow (with cycle detection), preserve (copied as links) or skip (reported as warnings).")
+	planCmd.Flags().BoolVar(&flags.review, reviewFlag, false, "Interactively review the detected services, rename them, deselect their transformers and adjust their source paths before the plan is written.")
+	planCmd.Flags().StringVar(&flags.progressFile, progressFileFlag, "", "File to write the progress of the planning to.")
+
 	must(planCmd.Flags().MarkHidden(planProgressPortFlag))
+	must(planCmd.Flags().MarkHidden(progressFileFlag))
 
+	must(registerFlagCompletions(planCmd))
 	return planCmd
 }
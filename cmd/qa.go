@@ -0,0 +1,109 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type qaDiffFlags struct {
+	// exitCode exits with 1 if the sessions are different
+	exitCode bool
+}
+
+func qaDiffHandler(flags qaDiffFlags, oldSessionPath, newSessionPath string) {
+	oldSession, err := qatypes.ReadSession(oldSessionPath)
+	if err != nil {
+		logrus.Fatalf("failed to read the old session. Error: %q", err)
+	}
+	newSession, err := qatypes.ReadSession(newSessionPath)
+	if err != nil {
+		logrus.Fatalf("failed to read the new session. Error: %q", err)
+	}
+	diffs := qatypes.DiffSessions(oldSession, newSession)
+	if len(diffs) == 0 {
+		fmt.Println("The sessions have the same questions and answers.")
+		return
+	}
+	for _, diff := range diffs {
+		switch diff.Change {
+		case qatypes.SessionQuestionAdded:
+			fmt.Printf("+ %s\n", diff.ID)
+			fmt.Printf("    description: %s\n", diff.Problem.Desc)
+			fmt.Printf("    answer: %s\n", formatSessionValue(diff.Problem.Answer))
+		case qatypes.SessionQuestionRemoved:
+			fmt.Printf("- %s\n", diff.ID)
+			fmt.Printf("    description: %s\n", diff.Problem.Desc)
+			fmt.Printf("    answer: %s\n", formatSessionValue(diff.Problem.Answer))
+		default:
+			fmt.Printf("~ %s\n", diff.ID)
+			for _, field := range diff.Fields {
+				fmt.Printf("    %s: %s -> %s\n", field.Field, formatSessionValue(field.Old), formatSessionValue(field.New))
+			}
+		}
+	}
+	if flags.exitCode {
+		os.Exit(1)
+	}
+}
+
+// formatSessionValue formats the value so that lists and maps fit on one line
+func formatSessionValue(value interface{}) string {
+	if value == nil {
+		return "<none>"
+	}
+	if valueStr, ok := value.(string); ok {
+		return valueStr
+	}
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(valueBytes)
+}
+
+// GetQACommand returns a command to work with the recorded QA sessions
+func GetQACommand() *cobra.Command {
+	viper.AutomaticEnv()
+	diffFlags := qaDiffFlags{}
+	qaCmd := &cobra.Command{
+		Use:   "qa",
+		Short: "Work with the QA sessions recorded by the transform command.",
+		Long: `Work with the QA sessions recorded by the transform command.
+	A session is recorded using the --` + qaSessionOutFlag + ` flag and lists every question asked along with its default and answer.`,
+	}
+	diffCmd := &cobra.Command{
+		Use:   "diff old-session new-session",
+		Short: "Show the questions that changed between two QA sessions.",
+		Long: `Show the questions that changed between two QA sessions.
+	The questions asked only in the new session are prefixed with +, the ones asked only in the old session with -
+	and the ones whose description, options, default or answer changed with ~ .`,
+		Args: cobra.ExactArgs(2),
+		Run:  func(_ *cobra.Command, args []string) { qaDiffHandler(diffFlags, args[0], args[1]) },
+	}
+	diffCmd.Flags().BoolVar(&diffFlags.exitCode, "exit-code", false, "Exit with 1 if the sessions are different.")
+
+	qaCmd.AddCommand(diffCmd)
+	return qaCmd
+}
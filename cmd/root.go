@@ -17,18 +17,40 @@
 package cmd
 
 import (
-	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/i18n"
+	"github.com/konveyor/move2kube/qaengine"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+const (
+	// textLogFormat prints the logs as lines of text
+	textLogFormat = "text"
+	// jsonLogFormat prints the logs as json objects, one per line
+	jsonLogFormat = "json"
+)
+
+// logFileOptions is the level and the rotation of the log files, set using the root command flags
+var logFileOptions common.LogFileOptions
+
 // GetRootCmd returns the root command that contains all the other commands
 func GetRootCmd() *cobra.Command {
 	loglevel := logrus.InfoLevel.String()
+	logFormat := textLogFormat
 	logFile := ""
+	logFileLevel := ""
+	logFileMaxSize := ""
+	logFileMaxBackups := 0
+	locale := ""
+	plain := false
+	keepTemp := false
+	cleanup := false
+	parallel := 1
 
 	// RootCmd root level flags and commands
 	rootCmd := &cobra.Command{
@@ -40,26 +62,73 @@ func GetRootCmd() *cobra.Command {
 	
 	For more documentation and support, visit https://move2kube.konveyor.io/
 	`,
-		PersistentPreRunE: func(*cobra.Command, []string) error {
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
 			logl, err := logrus.ParseLevel(loglevel)
 			if err != nil {
 				logrus.Errorf("the log level '%s' is invalid, using 'info' log level instead. Error: %q", loglevel, err)
 				logl = logrus.InfoLevel
 			}
 			logrus.SetLevel(logl)
+			if plain {
+				logrus.SetFormatter(&common.PlainFormatter{})
+				qaengine.SetPlainMode(true)
+			}
+			switch logFormat {
+			case textLogFormat:
+			case jsonLogFormat:
+				logrus.SetFormatter(&logrus.JSONFormatter{})
+				logrus.AddHook(common.NewLogContextHook())
+			default:
+				logrus.Fatalf("the log format %s is invalid. Valid formats are %s and %s", logFormat, textLogFormat, jsonLogFormat)
+			}
+			if logFileOptions, err = common.GetLogFileOptions(logFileLevel, logFileMaxSize, logFileMaxBackups); err != nil {
+				logrus.Fatalf("the log file options are invalid. Error: %q", err)
+			}
 			if logFile != "" {
-				f, err := os.OpenFile(logFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, common.DefaultFilePermission)
-				if err != nil {
+				console := os.Stdout
+				if writesManifestsToStdout(cmd) {
+					console = os.Stderr
+				}
+				logrus.SetOutput(console)
+				if _, err := common.TeeLogsToFile(logFile, logFileOptions); err != nil {
 					logrus.Fatalf("failed to open the log file at path %s . Error: %q", logFile, err)
 				}
-				logrus.SetOutput(io.MultiWriter(f, os.Stdout))
+			}
+			if err := common.SetParallelism(parallel); err != nil {
+				logrus.Fatalf("the number of parallel workers is invalid. Error: %q", err)
+			}
+			common.KeepTempWorkspace = keepTemp
+			if cleanup {
+				cleanupStaleWorkspaces(common.DefaultStaleWorkspaceAge, false)
+			}
+			if locale == "" {
+				locale = i18n.GetLocaleFromEnv()
+			}
+			if err := i18n.SetLocale(filepath.Join(common.AssetsPath, "built-in", "locales"), locale); err != nil {
+				logrus.Errorf("failed to load the messages for the locale %s . Using the english messages instead. Error: %q", locale, err)
 			}
 			return nil
 		},
 	}
 
 	rootCmd.PersistentFlags().StringVar(&loglevel, "log-level", logrus.InfoLevel.String(), "Set logging levels.")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", textLogFormat, "Set the format of the logs. One of "+textLogFormat+" or "+jsonLogFormat+". The json logs have fields for the phase and the transformer, services and artifacts being processed.")
 	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "File to store the logs in. By default it only prints to console.")
+	rootCmd.PersistentFlags().StringVar(&logFileLevel, "log-file-level", "", "Set the logging level of the log files, like debug or trace, separately from the console. By default the --log-level is used.")
+	rootCmd.PersistentFlags().StringVar(&logFileMaxSize, "log-file-max-size", "100Mi", "Rotate the log files once they grow past this size. Use 0 to never rotate them.")
+	rootCmd.PersistentFlags().IntVar(&logFileMaxBackups, "log-file-max-backups", 3, "Number of rotated log files to keep, named <log file>.1 (the newest) to <log file>.<n>.")
+	rootCmd.PersistentFlags().StringVar(&locale, "locale", "", "Set the language of the messages and questions, like es or es_MX. By default the LC_ALL, LC_MESSAGES and LANG environment variables are used.")
+	// --lang is accepted as another name for --locale
+	rootCmd.SetGlobalNormalizationFunc(func(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "lang" {
+			name = "locale"
+		}
+		return pflag.NormalizedName(name)
+	})
+	rootCmd.PersistentFlags().BoolVar(&keepTemp, "keep-temp", false, "Keep the temporary directory after the command finishes. Useful for debugging.")
+	rootCmd.PersistentFlags().BoolVar(&cleanup, "cleanup", false, "Remove the temporary directories left behind by previous runs that crashed or were killed before running the command.")
+	rootCmd.PersistentFlags().IntVar(&parallel, "parallel", 1, "Number of workers used to detect the services in a directory, build the container images of the transformers and run the transformers at the same time. By default everything runs one after the other.")
+	rootCmd.PersistentFlags().BoolVar(&plain, "plain", false, "Print plain text without colors and ask the questions line by line instead of using interactive prompts. Works better with screen readers.")
 
 	rootCmd.AddCommand(GetVersionCommand())
 	rootCmd.AddCommand(GetCollectCommand())
@@ -67,5 +136,10 @@ func GetRootCmd() *cobra.Command {
 	rootCmd.AddCommand(GetTransformCommand())
 	rootCmd.AddCommand(GetGenerateDocsCommand())
 	rootCmd.AddCommand(GetGraphCommand())
+	rootCmd.AddCommand(GetServeCommand())
+	rootCmd.AddCommand(GetTransformerCommand())
+	rootCmd.AddCommand(GetCleanupCommand())
+	rootCmd.AddCommand(GetQACommand())
+	rootCmd.AddCommand(GetValidateCommand())
 	return rootCmd
 }
@@ -0,0 +1,89 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/server"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type serveFlags struct {
+	// host is the address to listen on
+	host string
+	port int32
+	// authToken is the bearer token that the clients must send
+	authToken string
+	// workspacesPath is the directory where the uploaded sources, plans and outputs are stored
+	workspacesPath string
+	// maxParallelJobs is the number of plan and transform jobs that can run at the same time
+	maxParallelJobs int
+	// jobRetention is how long the finished jobs are kept
+	jobRetention time.Duration
+	// allowLocalExecution lets the jobs execute programs on the server
+	allowLocalExecution bool
+	// allowRemoteURLs lets the uploaded plans fetch from remote urls
+	allowRemoteURLs bool
+}
+
+func serveHandler(flags serveFlags) {
+	workspacesPath, err := filepath.Abs(flags.workspacesPath)
+	if err != nil {
+		logrus.Fatalf("failed to make the workspaces directory path %s absolute. Error: %q", flags.workspacesPath, err)
+	}
+	config := server.ConfigT{
+		Host:                flags.host,
+		Port:                flags.port,
+		AuthToken:           flags.authToken,
+		WorkspacesPath:      workspacesPath,
+		MaxParallelJobs:     flags.maxParallelJobs,
+		JobRetention:        flags.jobRetention,
+		AllowLocalExecution: flags.allowLocalExecution,
+		AllowRemoteURLs:     flags.allowRemoteURLs,
+	}
+	logrus.Fatalf("server stopped. Error: %q", server.StartServer(config))
+}
+
+// GetServeCommand returns a command to serve the plan, transform and QA functionality over a REST API
+func GetServeCommand() *cobra.Command {
+	viper.AutomaticEnv()
+	flags := serveFlags{}
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Start a server exposing plan, transform and QA over a REST API.",
+		Long: `Start a server exposing plan, transform and QA over a REST API.
+	Each project lives in a workspace. Upload the source as an archive, create and edit the plan, run the transformation
+	answering the questions through the API, and download the output as a zip archive.
+	Planning and transformation run as background jobs whose progress can be queried and which can be cancelled.
+	The API is served under /api/v1 .`,
+		Run: func(_ *cobra.Command, __ []string) { serveHandler(flags) },
+	}
+	serveCmd.Flags().StringVar(&flags.host, "host", "localhost", "Address to listen on. Use 0.0.0.0 to accept connections from other machines, along with an auth token.")
+	serveCmd.Flags().StringVar(&flags.authToken, "auth-token", "", "Require this bearer token in the Authorization header of every request.")
+	serveCmd.Flags().Int32VarP(&flags.port, "port", "p", 8080, "Port to start the server on.")
+	serveCmd.Flags().StringVarP(&flags.workspacesPath, "workspaces", "w", "m2k-workspaces", "Directory to store the workspaces in.")
+	serveCmd.Flags().IntVar(&flags.maxParallelJobs, "max-parallel-jobs", 1, "Number of plan and transform jobs that can run at the same time. The rest are queued.")
+	serveCmd.Flags().DurationVar(&flags.jobRetention, "job-retention", 24*time.Hour, "How long the finished jobs can be queried for. Use 0 to keep them forever.")
+	serveCmd.Flags().BoolVar(&flags.allowLocalExecution, "allow-local-execution", false, "Let the jobs execute programs on the server, like the hooks of the uploaded plans. By default the jobs run with --"+common.DisableLocalExecutionFlag+" and plans with hooks are rejected.")
+	serveCmd.Flags().BoolVar(&flags.allowRemoteURLs, "allow-remote-urls", false, "Let the uploaded plans fetch their source and customizations from the remote urls in sourceURL and customizationsURL.")
+	return serveCmd
+}
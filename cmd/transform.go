@@ -18,13 +18,19 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"time"
 
 	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/i18n"
+	"github.com/konveyor/move2kube/common/webhook"
 	"github.com/konveyor/move2kube/lib"
+	"github.com/konveyor/move2kube/qaengine"
 	"github.com/konveyor/move2kube/types/plan"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -49,9 +55,77 @@ type transformFlags struct {
 	// CustomizationsPaths contains the path to the customizations directory
 	customizationsPath  string
 	transformerSelector string
+	// environments contains the list of target environments used for parameterization
+	environments []string
+	// progressFile is the file where the progress is written to
+	progressFile string
+	// webhooks contains the urls that receive the lifecycle events
+	webhooks []string
+	// webhookStallTimeout is how long a question can stay unanswered before an event is sent
+	webhookStallTimeout time.Duration
+	// otlpEndpoint is the OTLP collector the traces are exported to
+	otlpEndpoint string
+	// otlpInsecure disables TLS when exporting the traces
+	otlpInsecure bool
+	// exportGraph is the path the plan and the transformation graph are exported to for external tools
+	exportGraph string
+	// validateAgainstCluster is the kubeconfig context of the cluster the output is validated against using a server-side dry run
+	validateAgainstCluster string
+	// outputLayout is the layout of the output directory
+	outputLayout string
+	// stdout writes only the generated manifests to stdout
+	stdout bool
+	// symlinks is how the symbolic links in the source and the output are handled
+	symlinks string
+	// maxFileSize is the size above which the source files are skipped
+	maxFileSize string
+	// maxDirectoryFiles is the number of entries above which the source directories are skipped
+	maxDirectoryFiles int
+	// maxDetectDepth is the depth after which the source directories are not searched for services
+	maxDetectDepth int
+	// excludeDirs are the glob patterns of the source directories that are not searched for services
+	excludeDirs []string
+	// debugTransformers are the starlark transformers that pause at their breakpoints
+	debugTransformers []string
+	// watchCustomizations keeps watching the customizations and transforms again when the transformers change
+	watchCustomizations bool
+	// qaReport is the format of the report of the questions, their answers and the transformers that asked them
+	qaReport string
+	// services are the services in the plan that are transformed, all of them if empty
+	services []string
+	// outputFormat is whether the output is written as a directory or an archive
+	outputFormat string
+	// push commits the output to a new branch of a git repo and optionally opens a pull request
+	push bool
+	// outputLog writes the logs to a file inside the output directory
+	outputLog bool
 }
 
+const (
+	// directoryOutputFormat writes the output to a directory
+	directoryOutputFormat = "directory"
+	// archiveOutputFormat writes the output to a tar.gz archive
+	archiveOutputFormat = "archive"
+	// tarGzOutputFormat writes the output to a tar.gz archive
+	tarGzOutputFormat = "tar.gz"
+	// zipOutputFormat writes the output to a zip archive
+	zipOutputFormat = "zip"
+)
+
 func transformHandler(cmd *cobra.Command, flags transformFlags) {
+	manifestsOut := os.Stdout
+	if flags.stdout || flags.outpath == "-" {
+		// only the manifests go to stdout, anything else that gets printed goes to stderr
+		flags.stdout = true
+		os.Stdout = os.Stderr
+		flags.qaskip = true
+		stdoutOutputPath, err := os.MkdirTemp(common.TempPath, "stdout")
+		if err != nil {
+			logrus.Fatalf("failed to create a temporary output directory. Error: %q", err)
+		}
+		flags.outpath = stdoutOutputPath
+		flags.overwrite = true
+	}
 	ctx, cancel := context.WithCancel(cmd.Context())
 	logrus.AddHook(common.NewCleanupHook(cancel))
 	logrus.AddHook(common.NewCleanupHook(lib.Destroy))
@@ -63,19 +137,39 @@ func transformHandler(cmd *cobra.Command, flags transformFlags) {
 		common.Interrupt()
 	}()
 	defer lib.Destroy()
+	shutdownTracing := setupTracing(ctx, flags.otlpEndpoint, flags.otlpInsecure)
+	defer shutdownTracing()
+	webhook.SetURLs(flags.webhooks)
+	logrus.AddHook(webhook.NewFailureHook(webhook.TransformFailed))
+
+	if flags.qaReport != "" && flags.qaReport != lib.QAReportMarkdownFormat && flags.qaReport != lib.QAReportHTMLFormat {
+		logrus.Fatalf("the --%s flag must be either %s or %s . Actual: %s", qaReportFlag, lib.QAReportMarkdownFormat, lib.QAReportHTMLFormat, flags.qaReport)
+	}
+
+	archiveExt, err := getOutputArchiveExt(flags.outputFormat)
+	if err != nil {
+		logrus.Fatalf("the --%s flag is invalid. Error: %q", outputFormatFlag, err)
+	}
+	if archiveExt != "" && (flags.stdout || flags.watchCustomizations) {
+		logrus.Fatalf("the --%s flag can't be used with the --%s or --%s flags", outputFormatFlag, stdoutFlag, watchCustomizationsFlag)
+	}
+	if flags.push && flags.stdout {
+		logrus.Fatalf("the --%s flag can't be used with the --%s flag", pushFlag, stdoutFlag)
+	}
 
-	var err error
 	if flags.planfile, err = filepath.Abs(flags.planfile); err != nil {
-		logrus.Fatalf("Failed to make the plan file path %q absolute. Error: %q", flags.planfile, err)
+		logrus.Fatalf(i18n.T("Failed to make the plan file path %q absolute. Error: %q"), flags.planfile, err)
 	}
+	flags.srcpath = fetchRemotePathIfRequired(flags.srcpath)
 	if flags.srcpath != "" {
 		if flags.srcpath, err = filepath.Abs(flags.srcpath); err != nil {
-			logrus.Fatalf("Failed to make the source directory path %q absolute. Error: %q", flags.srcpath, err)
+			logrus.Fatalf(i18n.T("Failed to make the source directory path %q absolute. Error: %q"), flags.srcpath, err)
 		}
 	}
 	if flags.outpath, err = filepath.Abs(flags.outpath); err != nil {
-		logrus.Fatalf("Failed to make the output directory path %q absolute. Error: %q", flags.outpath, err)
+		logrus.Fatalf(i18n.T("Failed to make the output directory path %q absolute. Error: %q"), flags.outpath, err)
 	}
+	flags.customizationsPath = fetchRemotePathIfRequired(flags.customizationsPath)
 	// Check if the default customization folder exists in the working directory.
 	// If not, skip the customization option
 	if !cmd.Flags().Changed(customizationsFlag) {
@@ -84,7 +178,7 @@ func transformHandler(cmd *cobra.Command, flags transformFlags) {
 			// make all path(s) absolute
 			flags.customizationsPath, err = filepath.Abs(flags.customizationsPath)
 			if err != nil {
-				logrus.Fatalf("Failed to make the customizations directory path %q absolute. Error: %q", flags.customizationsPath, err)
+				logrus.Fatalf(i18n.T("Failed to make the customizations directory path %q absolute. Error: %q"), flags.customizationsPath, err)
 			}
 		}
 	}
@@ -97,15 +191,35 @@ func transformHandler(cmd *cobra.Command, flags transformFlags) {
 	}
 	// make all path(s) absolute
 	for i, c := range flags.configs {
+		if qatypes.IsRemoteConfigPath(c) {
+			continue
+		}
 		if c, err := filepath.Abs(c); err != nil {
 			logrus.Fatalf("failed to make the config file path %s absolute. Error: %q", c, err)
 		}
 		flags.configs[i] = c
 	}
 
+	if flags.outputLayout != "" {
+		flags.setconfigs = append(flags.setconfigs, fmt.Sprintf("%s=%q", common.ConfigTargetOutputLayoutKey, flags.outputLayout))
+	}
+
 	// Global settings
 	common.IgnoreEnvironment = flags.ignoreEnv
 	common.DisableLocalExecution = flags.disableLocalExecution
+	if err := common.SetSymlinkPolicy(common.SymlinkPolicyT(flags.symlinks)); err != nil {
+		logrus.Fatalf("failed to set the symlink policy. Error: %q", err)
+	}
+	common.DebugTransformers = flags.debugTransformers
+	if err := common.SetSourceLimits(flags.maxFileSize, flags.maxDirectoryFiles); err != nil {
+		logrus.Fatalf("failed to set the source limits. Error: %q", err)
+	}
+	if err := common.SetDetectionLimits(flags.maxDetectDepth, flags.excludeDirs); err != nil {
+		logrus.Fatalf("failed to set the detection limits. Error: %q", err)
+	}
+	if flags.progressFile != "" {
+		common.SetProgressFile(flags.progressFile)
+	}
 	// Global settings
 
 	// Parameter cleaning and curate plan
@@ -119,7 +233,7 @@ func transformHandler(cmd *cobra.Command, flags transformFlags) {
 	if err != nil {
 		logrus.Debugf("No plan file found.")
 		if cmd.Flags().Changed(planFlag) {
-			logrus.Fatalf("Error while accessing plan file at path %s Error: %q", flags.planfile, err)
+			logrus.Fatalf(i18n.T("Error while accessing plan file at path %s Error: %q"), flags.planfile, err)
 		}
 
 		// Global settings
@@ -128,11 +242,11 @@ func transformHandler(cmd *cobra.Command, flags transformFlags) {
 		if flags.srcpath != "" {
 			checkSourcePath(flags.srcpath)
 			if flags.srcpath == flags.outpath || common.IsParent(flags.outpath, flags.srcpath) || common.IsParent(flags.srcpath, flags.outpath) {
-				logrus.Fatalf("The source path %s and output path %s overlap.", flags.srcpath, flags.outpath)
+				logrus.Fatalf(i18n.T("The source path %s and output path %s overlap."), flags.srcpath, flags.outpath)
 			}
 		}
 		if err := os.MkdirAll(flags.outpath, common.DefaultDirectoryPermission); err != nil {
-			logrus.Fatalf("Failed to create the output directory at path %s Error: %q", flags.outpath, err)
+			logrus.Fatalf(i18n.T("Failed to create the output directory at path %s Error: %q"), flags.outpath, err)
 		}
 		startQA(flags.qaflags)
 		logrus.Debugf("Creating a new plan.")
@@ -140,34 +254,70 @@ func transformHandler(cmd *cobra.Command, flags transformFlags) {
 		if err != nil {
 			logrus.Fatalf("failed to create the plan. Error: %q", err)
 		}
+		transformationPlan.Spec.Environments = flags.environments
+		transformationPlan.Spec.SourceLimits = plan.SourceLimits{MaxFileSize: flags.maxFileSize, MaxDirectoryFiles: flags.maxDirectoryFiles}
+		transformationPlan.Spec.DetectionLimits = plan.DetectionLimits{MaxDetectDepth: flags.maxDetectDepth, ExcludeDirs: flags.excludeDirs}
 		if len(transformationPlan.Spec.Services) == 0 && len(transformationPlan.Spec.InvokedByDefaultTransformers) == 0 {
 			logrus.Debugf("Plan : %+v", transformationPlan)
 			logrus.Fatalf("failed to find any services or default transformers. Aborting.")
 		}
 	} else {
 		preExistingPlan = true
-		logrus.Infof("Detected a plan file at path %s. Will transform using this plan.", flags.planfile)
+		logrus.Infof(i18n.T("Detected a plan file at path %s. Will transform using this plan."), flags.planfile)
 		sourceDir := ""
 		if cmd.Flags().Changed(sourceFlag) {
 			sourceDir = flags.srcpath
-			logrus.Warnf("Using the detected plan with specified source. If you did not want to use the plan file at %s, delete it and rerun the command.", flags.planfile)
+			logrus.Warnf(i18n.T("Using the detected plan with specified source. If you did not want to use the plan file at %s, delete it and rerun the command."), flags.planfile)
 		}
 		if transformationPlan, err = plan.ReadPlan(flags.planfile, sourceDir); err != nil {
-			logrus.Fatalf("Unable to read the plan at path %s Error: %q", flags.planfile, err)
+			logrus.Fatalf(i18n.T("Unable to read the plan at path %s Error: %q"), flags.planfile, err)
+		}
+		if sourceDir == "" && transformationPlan.Spec.SourceURL != "" {
+			// the source of the plan was fetched from a remote path, so it is fetched again and the plan is read relative to it
+			logrus.Infof("Fetching the source of the plan from %s", transformationPlan.Spec.SourceURL)
+			sourceDir = fetchRemotePathIfRequired(transformationPlan.Spec.SourceURL)
+			if transformationPlan, err = plan.ReadPlan(flags.planfile, sourceDir); err != nil {
+				logrus.Fatalf(i18n.T("Unable to read the plan at path %s Error: %q"), flags.planfile, err)
+			}
 		}
 		if len(transformationPlan.Spec.Services) == 0 && len(transformationPlan.Spec.InvokedByDefaultTransformers) == 0 {
 			logrus.Debugf("Plan : %+v", transformationPlan)
-			logrus.Fatalf("Failed to find any services or default transformers. Aborting.")
+			logrus.Fatal(i18n.T("Failed to find any services or default transformers. Aborting."))
 		}
 		if cmd.Flags().Changed(nameFlag) {
 			transformationPlan.Name = flags.name
 		}
+		if cmd.Flags().Changed(environmentsFlag) {
+			transformationPlan.Spec.Environments = flags.environments
+		}
+		if cmd.Flags().Changed(maxFileSizeFlag) {
+			transformationPlan.Spec.SourceLimits.MaxFileSize = flags.maxFileSize
+		}
+		if cmd.Flags().Changed(maxDirectoryFilesFlag) {
+			transformationPlan.Spec.SourceLimits.MaxDirectoryFiles = flags.maxDirectoryFiles
+		}
+		if err := common.SetSourceLimits(transformationPlan.Spec.SourceLimits.MaxFileSize, transformationPlan.Spec.SourceLimits.MaxDirectoryFiles); err != nil {
+			logrus.Fatalf("failed to set the source limits from the plan. Error: %q", err)
+		}
+		if cmd.Flags().Changed(maxDetectDepthFlag) {
+			transformationPlan.Spec.DetectionLimits.MaxDetectDepth = flags.maxDetectDepth
+		}
+		if cmd.Flags().Changed(excludeDirsFlag) {
+			transformationPlan.Spec.DetectionLimits.ExcludeDirs = flags.excludeDirs
+		}
+		if err := common.SetDetectionLimits(transformationPlan.Spec.DetectionLimits.MaxDetectDepth, transformationPlan.Spec.DetectionLimits.ExcludeDirs); err != nil {
+			logrus.Fatalf("failed to set the detection limits from the plan. Error: %q", err)
+		}
 		if cmd.Flags().Changed(customizationsFlag) {
 			if flags.customizationsPath != "" {
 				transformationPlan.Spec.CustomizationsDir = flags.customizationsPath
-				logrus.Warnf("Using the detected plan with specified customization. This might result in undesired results if the customization is different from what was given to plan. If you did not want to use the plan file at %s, delete it and rerun the command.", flags.planfile)
+				logrus.Warnf(i18n.T("Using the detected plan with specified customization. This might result in undesired results if the customization is different from what was given to plan. If you did not want to use the plan file at %s, delete it and rerun the command."), flags.planfile)
 			}
 		}
+		if !cmd.Flags().Changed(customizationsFlag) && transformationPlan.Spec.CustomizationsURL != "" {
+			logrus.Infof("Fetching the customizations of the plan from %s", transformationPlan.Spec.CustomizationsURL)
+			transformationPlan.Spec.CustomizationsDir = fetchRemotePathIfRequired(transformationPlan.Spec.CustomizationsURL)
+		}
 
 		// Global settings
 		if transformationPlan.Spec.SourceDir != "" {
@@ -177,17 +327,127 @@ func transformHandler(cmd *cobra.Command, flags transformFlags) {
 		flags.outpath = filepath.Join(flags.outpath, transformationPlan.Name)
 		checkOutputPath(flags.outpath, flags.overwrite)
 		if transformationPlan.Spec.SourceDir != "" && (transformationPlan.Spec.SourceDir == flags.outpath || common.IsParent(flags.outpath, transformationPlan.Spec.SourceDir) || common.IsParent(transformationPlan.Spec.SourceDir, flags.outpath)) {
-			logrus.Fatalf("The source path %s and output path %s overlap.", transformationPlan.Spec.SourceDir, flags.outpath)
+			logrus.Fatalf(i18n.T("The source path %s and output path %s overlap."), transformationPlan.Spec.SourceDir, flags.outpath)
 		}
 		if err := os.MkdirAll(flags.outpath, common.DefaultDirectoryPermission); err != nil {
-			logrus.Fatalf("Failed to create the output directory at path %s Error: %q", flags.outpath, err)
+			logrus.Fatalf(i18n.T("Failed to create the output directory at path %s Error: %q"), flags.outpath, err)
 		}
 		startQA(flags.qaflags)
 	}
+	if archiveExt != "" {
+		checkOutputArchivePath(flags.outpath+archiveExt, flags.overwrite)
+	}
+	var outputLogFile *common.RotatingFileWriter
+	if flags.outputLog {
+		logFilePath := filepath.Join(flags.outpath, common.DefaultLogFile)
+		var err error
+		if outputLogFile, err = common.TeeLogsToFile(logFilePath, logFileOptions); err != nil {
+			logrus.Fatalf("failed to write the logs to the output directory. Error: %q", err)
+		}
+		defer outputLogFile.Close()
+		logrus.Infof("Writing the logs to %s", logFilePath)
+	}
+	if len(flags.services) != 0 {
+		if err := transformationPlan.SelectServices(flags.services); err != nil {
+			logrus.Fatalf("failed to select the services given using the --%s flag. Error: %q", servicesFlag, err)
+		}
+		logrus.Infof("Transforming only the services %+v", flags.services)
+	}
+	setupWebhooks(flags.webhooks, flags.webhookStallTimeout)
+	if flags.watchCustomizations && transformationPlan.Spec.CustomizationsDir == "" {
+		logrus.Fatalf("the --%s flag requires a customizations directory", watchCustomizationsFlag)
+	}
+	var qaReport *lib.QAReport
+	if flags.qaReport != "" {
+		qaReport = lib.StartQAReport()
+	}
 	if err := lib.Transform(ctx, transformationPlan, preExistingPlan, flags.outpath, flags.transformerSelector); err != nil {
 		logrus.Fatalf("failed to transform. Error: %q", err)
 	}
-	logrus.Infof("Transformed target artifacts can be found at [%s].", flags.outpath)
+	if qaReport != nil {
+		serviceNames := []string{}
+		for serviceName := range transformationPlan.Spec.Services {
+			serviceNames = append(serviceNames, serviceName)
+		}
+		if _, err := qaReport.Write(transformationPlan.Name, serviceNames, flags.outpath, flags.qaReport); err != nil {
+			logrus.Errorf("failed to write the QA report. Error: %q", err)
+		}
+	}
+	if flags.stdout {
+		count, err := lib.WriteManifests(flags.outpath, manifestsOut)
+		if err != nil {
+			logrus.Fatalf("failed to write the manifests to stdout. Error: %q", err)
+		}
+		logrus.Infof("Wrote the manifests from %d files to stdout.", count)
+	} else if archiveExt == "" {
+		logrus.Infof(i18n.T("Transformed target artifacts can be found at [%s]."), flags.outpath)
+	}
+	if flags.exportGraph != "" {
+		if err := lib.ExportGraph(transformationPlan, flags.exportGraph); err != nil {
+			logrus.Errorf("failed to export the plan and the transformation graph. Error: %q", err)
+		} else {
+			logrus.Infof(i18n.T("Exported the plan and the transformation graph to [%s]."), flags.exportGraph)
+		}
+	}
+	if flags.validateAgainstCluster != "" {
+		validateAgainstCluster(flags.outpath, flags.validateAgainstCluster)
+	}
+	if flags.push {
+		prURL, err := lib.PushOutput(flags.outpath, transformationPlan.Name)
+		if err != nil {
+			logrus.Fatalf("failed to push the output to the git repo. Error: %q", err)
+		}
+		if prURL != "" {
+			logrus.Infof(i18n.T("Opened the pull request [%s]."), prURL)
+		}
+	}
+	if archiveExt != "" {
+		archivePath := flags.outpath + archiveExt
+		if outputLogFile != nil {
+			// the log in the output directory is complete before it is archived, and the later logs go next to the archive
+			logFilePath := flags.outpath + "." + common.DefaultLogFile
+			logrus.Infof("Writing the rest of the logs to %s", logFilePath)
+			if err := outputLogFile.MoveTo(logFilePath); err != nil {
+				logrus.Errorf("failed to move the logs out of the output directory %s before archiving it. Error: %q", flags.outpath, err)
+			}
+		}
+		if err := common.CreateArchive(flags.outpath, archivePath); err != nil {
+			logrus.Fatalf("failed to archive the output. Error: %q", err)
+		}
+		if err := os.RemoveAll(flags.outpath); err != nil {
+			logrus.Errorf("failed to remove the output directory %s after archiving it. Error: %q", flags.outpath, err)
+		}
+		flags.outpath = archivePath
+		logrus.Infof(i18n.T("Transformed target artifacts can be found in the archive [%s]."), flags.outpath)
+	}
+	webhook.Send(webhook.TransformCompleted, fmt.Sprintf("the transformed target artifacts can be found at %s", flags.outpath), map[string]interface{}{"outputPath": flags.outpath})
+	if flags.watchCustomizations {
+		if err := lib.WatchCustomizations(ctx, transformationPlan, preExistingPlan, flags.outpath); err != nil {
+			logrus.Fatalf("failed to watch the customizations. Error: %q", err)
+		}
+	}
+}
+
+// validateAgainstCluster does a server-side dry run of the output against the cluster and reports the errors per file
+func validateAgainstCluster(outputPath, kubeContext string) {
+	results, err := lib.ValidateAgainstCluster(outputPath, kubeContext)
+	if err != nil {
+		logrus.Errorf("failed to validate the output against the cluster in the context %s . Error: %q", kubeContext, err)
+		return
+	}
+	failed := 0
+	for _, result := range results {
+		if result.Error == "" {
+			continue
+		}
+		failed++
+		logrus.Errorf("The server-side dry run of %s failed:\n%s", result.Path, result.Error)
+	}
+	if failed > 0 {
+		logrus.Warnf("%d of %d manifest files failed the server-side dry run against the cluster in the context %s", failed, len(results), kubeContext)
+		return
+	}
+	logrus.Infof(i18n.T("All %d manifest files passed the server-side dry run against the cluster in the context %s"), len(results), kubeContext)
 }
 
 // GetTransformCommand returns a command to do the transformation
@@ -211,29 +471,64 @@ func GetTransformCommand() *cobra.Command {
 	// Basic options
 	transformCmd.Flags().StringVarP(&flags.planfile, planFlag, "p", common.DefaultPlanFile, "Specify a plan file to execute.")
 	transformCmd.Flags().BoolVar(&flags.overwrite, overwriteFlag, false, "Overwrite the output directory if it exists. By default we don't overwrite.")
-	transformCmd.Flags().StringVarP(&flags.srcpath, sourceFlag, "s", "", "Specify source directory to transform. If you already have a m2k.plan then this will override the sourceDir value specified in that plan.")
-	transformCmd.Flags().StringVarP(&flags.outpath, outputFlag, "o", ".", "Path for output. Default will be directory with the project name.")
+	transformCmd.Flags().StringVarP(&flags.srcpath, sourceFlag, "s", "", "Specify source directory to transform. If you already have a m2k.plan then this will override the sourceDir value specified in that plan. Can also be a git remote path of the form git+<repo url>[//<path within repo>][?ref=<ref>&depth=<n>&submodules=<bool>&sparsecheckout=<bool>], a git repo url ending in .git of the form <repo url>[#<ref>[:<path within repo>]] which is shallow cloned, or a zip/tar.gz archive, either a local file or a url of the form s3://<bucket>/<key> or https://<host>/<path>, optionally suffixed with #sha256=<checksum>. Archives are extracted into a temp directory and their checksum is recorded in the plan")
+	transformCmd.Flags().StringVarP(&flags.outpath, outputFlag, "o", ".", "Path for output. Default will be directory with the project name. Use - to write only the kubernetes manifests to stdout.")
+	transformCmd.Flags().BoolVar(&flags.stdout, stdoutFlag, false, "Write only the generated kubernetes manifests to stdout as multi-document yaml, with the logs on stderr. The questions are answered with the defaults and the config.")
 	transformCmd.Flags().StringVarP(&flags.name, nameFlag, "n", common.DefaultProjectName, "Specify the project name.")
 	transformCmd.Flags().StringVar(&flags.configOut, configOutFlag, ".", "Specify config file output location.")
 	transformCmd.Flags().StringVar(&flags.qaCacheOut, qaCacheOutFlag, ".", "Specify cache file output location.")
-	transformCmd.Flags().StringSliceVarP(&flags.configs, configFlag, "f", []string{}, "Specify config file locations. By default we look for "+common.DefaultConfigFilePath)
+	transformCmd.Flags().StringVar(&flags.qaSessionOut, qaSessionOutFlag, "", "Record every question along with its default and answer to this session file. Sessions can be compared using the qa diff command.")
+	transformCmd.Flags().StringVar(&flags.qaReport, qaReportFlag, "", "Write a report of every question along with its answer, the transformer that asked it and the services it affects to the output directory. One of "+lib.QAReportMarkdownFormat+" or "+lib.QAReportHTMLFormat+".")
+	transformCmd.Flags().StringVar(&flags.qaReplay, qaReplayFlag, "", "Answer the questions using the answers recorded in this session file. The answers to password questions are not recorded and are not replayed.")
+	transformCmd.Flags().StringSliceVarP(&flags.configs, configFlag, "f", []string{}, "Specify config file locations. Can also be http(s) urls, optionally suffixed with #sha256=<checksum>. The "+qatypes.RemoteConfigTokenEnvVar+" environment variable is sent as a bearer token. By default we look for "+common.DefaultConfigFilePath)
 	transformCmd.Flags().StringSliceVar(&flags.preSets, preSetFlag, []string{}, "Specify preset config to use.")
+	transformCmd.Flags().StringVar(&flags.profile, profileFlag, "", "Specify the profile (like dev, stage or prod) under the "+qatypes.ProfilesKey+" key of the config files whose answers override the shared answers.")
 	transformCmd.Flags().BoolVar(&flags.persistPasswords, qaPersistPasswords, false, "Stores passwords too in the config.")
+	transformCmd.Flags().StringVar(&flags.qaEncryptionCert, qaEncryptionCertFlag, "", "Encrypt the config and the cache files using the RSA public key in this PEM encoded certificate. Without it they are encrypted using the passphrase in the "+qatypes.QAPassphraseEnvVar+" environment variable if it is set.")
+	transformCmd.Flags().StringVar(&flags.qaDecryptionKey, qaDecryptionKeyFlag, "", "Decrypt the config and the cache files that were encrypted using a certificate with this PEM encoded RSA private key.")
+	transformCmd.Flags().StringVar(&flags.vaultPathTemplate, qaVaultPathTemplateFlag, "", "Answer the password questions using the HashiCorp Vault secrets at the paths rendered from this template, like secret/data/move2kube/{{ .Path }} . The address and the token are read from VAULT_ADDR and VAULT_TOKEN. The answers are never written to the config or the cache.")
 	transformCmd.Flags().StringArrayVar(&flags.setconfigs, setConfigFlag, []string{}, "Specify config key-value pairs.")
-	transformCmd.Flags().StringVarP(&flags.customizationsPath, customizationsFlag, "c", "", "Specify directory where customizations are stored. By default we look for "+common.DefaultCustomizationDir)
+	transformCmd.Flags().StringVarP(&flags.customizationsPath, customizationsFlag, "c", "", "Specify directory where customizations are stored. Can also be a git remote path. By default we look for "+common.DefaultCustomizationDir)
 	transformCmd.Flags().StringVarP(&flags.transformerSelector, transformerSelectorFlag, "t", "", "Specify the transformer selector.")
+	transformCmd.Flags().StringSliceVar(&flags.services, servicesFlag, []string{}, "Specify the services in the plan to transform, like svc-a,svc-b. The other services are skipped. By default all the services are transformed.")
+	transformCmd.Flags().StringSliceVar(&flags.environments, environmentsFlag, []string{}, "Specify the target environments (like dev,staging,prod) to generate parameterized output for. If you already have a m2k.plan then this will override the environments specified in that plan.")
+	transformCmd.Flags().StringVar(&flags.otlpEndpoint, otlpEndpointFlag, "", "Export the traces to the OTLP collector at this host:port. The OTEL_EXPORTER_OTLP_* environment variables are also supported.")
+	transformCmd.Flags().BoolVar(&flags.otlpInsecure, otlpInsecureFlag, false, "Disable TLS when exporting the traces.")
+	transformCmd.Flags().StringSliceVar(&flags.webhooks, webhookFlag, []string{}, "Specify the urls that should receive the transform lifecycle events as json.")
+	transformCmd.Flags().DurationVar(&flags.webhookStallTimeout, webhookStallTimeoutFlag, 5*time.Minute, "Send an event to the webhooks if a question stays unanswered for this long.")
+	transformCmd.Flags().BoolVar(&flags.outputLog, outputLogFlag, false, "Also write the logs to the "+common.DefaultLogFile+" file inside the output directory, at the level set by --log-file-level and rotated as set by --log-file-max-size and --log-file-max-backups. When the output is archived, the logs after archiving are written to the <output directory>."+common.DefaultLogFile+" file next to the archive.")
+	transformCmd.Flags().BoolVar(&flags.push, pushFlag, false, "Commit the output to a new branch of a git repo and optionally open a pull request using the GitHub or GitLab APIs. The repo, the branch names and the credentials are asked as questions.")
+	transformCmd.Flags().StringVar(&flags.outputFormat, outputFormatFlag, directoryOutputFormat, "Specify how the output is written. One of "+directoryOutputFormat+", "+archiveOutputFormat+" (same as "+tarGzOutputFormat+") or "+zipOutputFormat+". The archives are written next to the output directory with deterministic file ordering and timestamps, and the output directory is removed.")
+	transformCmd.Flags().StringVar(&flags.outputLayout, outputLayoutFlag, "", "Specify the layout of the output directory. One of monorepo (a single repo with the deployment artifacts, scripts and sources in separate directories), perservice (a directory per service with its source and manifests) or sourceadjacent (the sources at the root with the Dockerfiles next to them).")
+	transformCmd.Flags().DurationVar(&flags.qaTimeout, qaTimeoutFlag, 0, "Use the default answer if a question stays unanswered for this long, like 30s. The timeout of a question overrides it. The questions that timed out are recorded in the cache. By default we wait forever.")
+	transformCmd.Flags().StringSliceVar(&flags.qaEnableCategories, qaEnableCategoriesFlag, []string{}, "Ask only the questions in these categories and use the defaults for the rest. The built-in categories are "+qaengine.ImageRegistryCategory+", "+qaengine.IngressCategory+", "+qaengine.NetworkingCategory+", "+qaengine.StorageCategory+" and "+qaengine.CICDCategory+".")
+	transformCmd.Flags().StringSliceVar(&flags.qaDisableCategories, qaDisableCategoriesFlag, []string{}, "Use the defaults for the questions in these categories instead of asking them.")
 	transformCmd.Flags().BoolVar(&flags.qaskip, qaSkipFlag, false, "Enable/disable the default answers to questions posed in QA Cli sub-system. If disabled, you will have to answer the questions posed by QA during interaction.")
 
 	// Advanced options
 	transformCmd.Flags().BoolVar(&flags.ignoreEnv, ignoreEnvFlag, false, "Ignore data from local machine.")
+	transformCmd.Flags().StringVar(&flags.maxFileSize, maxFileSizeFlag, "", "Skip the source files larger than this size (like 100Mi) during detection and copying. The skipped files are listed in the migration report.")
+	transformCmd.Flags().IntVar(&flags.maxDirectoryFiles, maxDirectoryFilesFlag, 0, "Skip the source directories with more than this many files during detection and copying. The skipped directories are listed in the migration report.")
+	transformCmd.Flags().IntVar(&flags.maxDetectDepth, maxDetectDepthFlag, 0, "Do not search for services in the source directories more than this many levels below the source directory. By default all the levels are searched.")
+	transformCmd.Flags().StringSliceVar(&flags.excludeDirs, excludeDirsFlag, []string{}, "Do not search for services in the source directories matching these glob patterns, without requiring "+common.IgnoreFilename+" files. Patterns without a / (like vendor) match the directory name at any depth, others (like third_party/*) match the path relative to the source directory.")
+	transformCmd.Flags().BoolVar(&flags.watchCustomizations, watchCustomizationsFlag, false, "Keep watching the customizations directory after transforming. Changed transformers are reloaded and the plan is transformed again if they were used.")
+	transformCmd.Flags().StringSliceVar(&flags.debugTransformers, debugTransformerFlag, []string{}, "Open an interactive starlark prompt with the globals, the artifacts and the QA engine of these starlark transformers before they transform and at each m2k.breakpoint() call.")
+	transformCmd.Flags().StringVar(&flags.symlinks, symlinksFlag, string(common.FollowSymlinks), "Specify how the symbolic links in the source are handled. One of follow (with cycle detection), preserve (copied as links) or skip (reported as warnings).")
 	transformCmd.Flags().BoolVar(&flags.disableLocalExecution, common.DisableLocalExecutionFlag, false, "Allow files to be executed locally.")
 
 	// Hidden options
 	transformCmd.Flags().BoolVar(&flags.qadisablecli, qadisablecliFlag, false, "Enable/disable the QA Cli sub-system. Without this system, you will have to use the REST API to interact.")
 	transformCmd.Flags().IntVar(&flags.qaport, qaportFlag, 0, "Port for the QA service. By default it chooses a random free port.")
+	transformCmd.Flags().BoolVar(&flags.qagrpc, qagrpcFlag, false, "Serve the questions using the gRPC QA service in types/qaengine/qagrpc/qaservice.proto instead of the REST API. Used along with --"+qadisablecliFlag+".")
+	transformCmd.Flags().StringVar(&flags.exportGraph, exportGraphFlag, "", "Export the plan and the graph of transformers and artifacts to this file for external tools. Files ending with .pb or .binpb are written as protobuf, others as json.")
+	transformCmd.Flags().StringVar(&flags.validateAgainstCluster, validateAgainstClusterFlag, "", "Do a server-side dry run of the generated manifests against the cluster in this kubeconfig context and report the errors per file.")
+	transformCmd.Flags().StringVar(&flags.progressFile, progressFileFlag, "", "File to write the progress of the transformation to.")
 
 	must(transformCmd.Flags().MarkHidden(qadisablecliFlag))
 	must(transformCmd.Flags().MarkHidden(qaportFlag))
+	must(transformCmd.Flags().MarkHidden(qagrpcFlag))
+	must(transformCmd.Flags().MarkHidden(progressFileFlag))
 
+	must(registerFlagCompletions(transformCmd))
 	return transformCmd
 }
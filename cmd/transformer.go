@@ -0,0 +1,124 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/lib"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// transformerIndexEnvVar is the environment variable that contains the comma separated list of default transformer indexes
+	transformerIndexEnvVar = "MOVE2KUBE_TRANSFORMER_INDEX"
+)
+
+type transformerIndexFlags struct {
+	// indexes contains the locations of the transformer indexes
+	indexes []string
+	// customizationsPath is the directory the transformers are installed into
+	customizationsPath string
+	// overwrite replaces the transformer if it is already installed
+	overwrite bool
+}
+
+// getTransformerIndexes returns the indexes given using the flags or the environment variable
+func getTransformerIndexes(flags transformerIndexFlags) []string {
+	indexes := flags.indexes
+	if len(indexes) == 0 {
+		for _, index := range strings.Split(os.Getenv(transformerIndexEnvVar), ",") {
+			if index = strings.TrimSpace(index); index != "" {
+				indexes = append(indexes, index)
+			}
+		}
+	}
+	if len(indexes) == 0 {
+		logrus.Fatalf("no transformer indexes were given. Use the --%s flag or the %s environment variable.", transformerIndexFlag, transformerIndexEnvVar)
+	}
+	return indexes
+}
+
+func transformerListHandler(flags transformerIndexFlags) {
+	entries, err := lib.ListTransformersInIndexes(getTransformerIndexes(flags))
+	if err != nil {
+		logrus.Fatalf("failed to list the transformers. Error: %q", err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tLATEST VERSION\tDESCRIPTION")
+	for _, entry := range entries {
+		latestVersion := ""
+		if version, err := lib.GetLatestTransformerVersion(entry.TransformerIndexEntry); err == nil {
+			latestVersion = version.Version
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", entry.Name, latestVersion, entry.Description)
+	}
+	if err := w.Flush(); err != nil {
+		logrus.Errorf("failed to print the transformers. Error: %q", err)
+	}
+}
+
+func transformerInstallHandler(flags transformerIndexFlags, transformerName string) {
+	version := ""
+	if idx := strings.LastIndex(transformerName, "@"); idx > 0 {
+		transformerName, version = transformerName[:idx], transformerName[idx+1:]
+	}
+	if _, err := lib.InstallTransformer(getTransformerIndexes(flags), transformerName, version, flags.customizationsPath, flags.overwrite); err != nil {
+		logrus.Fatalf("failed to install the transformer %s . Error: %q", transformerName, err)
+	}
+}
+
+// GetTransformerCommand returns a command to find and install custom transformers from transformer indexes
+func GetTransformerCommand() *cobra.Command {
+	viper.AutomaticEnv()
+	flags := transformerIndexFlags{}
+	transformerCmd := &cobra.Command{
+		Use:   "transformer",
+		Short: "Find and install custom transformers from transformer indexes.",
+		Long: `Find and install custom transformers from transformer indexes.
+	A transformer index is a yaml file of kind TransformerIndex listing the available transformers along with their versions and descriptions.
+	It can be a local file, a http(s) url, or an archive, git remote path or oci://<image> containing an index.yaml file.`,
+	}
+	transformerCmd.PersistentFlags().StringSliceVar(&flags.indexes, transformerIndexFlag, []string{}, "Specify the transformer indexes. By default the comma separated list in the "+transformerIndexEnvVar+" environment variable is used.")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the transformers available in the transformer indexes.",
+		Args:  cobra.NoArgs,
+		Run:   func(_ *cobra.Command, __ []string) { transformerListHandler(flags) },
+	}
+	installCmd := &cobra.Command{
+		Use:   "install name[@version]",
+		Short: "Install a transformer from the transformer indexes into the customizations directory.",
+		Long: `Install a transformer from the transformer indexes into the customizations directory.
+	By default the latest version is installed. The customizations directory can then be used with the plan and transform commands.`,
+		Args: cobra.ExactArgs(1),
+		Run:  func(_ *cobra.Command, args []string) { transformerInstallHandler(flags, args[0]) },
+	}
+	installCmd.Flags().StringVarP(&flags.customizationsPath, customizationsFlag, "c", common.DefaultCustomizationDir, "Specify the customizations directory to install the transformer into.")
+	installCmd.Flags().BoolVar(&flags.overwrite, overwriteFlag, false, "Overwrite the transformer if it is already installed.")
+
+	transformerCmd.AddCommand(listCmd)
+	transformerCmd.AddCommand(installCmd)
+	return transformerCmd
+}
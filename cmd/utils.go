@@ -17,38 +17,83 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/i18n"
+	"github.com/konveyor/move2kube/common/tracing"
+	"github.com/konveyor/move2kube/common/vcs"
+	"github.com/konveyor/move2kube/common/webhook"
 	"github.com/konveyor/move2kube/qaengine"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cast"
+	"github.com/spf13/cobra"
 )
 
+// writesManifestsToStdout returns true if the command writes the generated manifests to stdout, in which case nothing else should be printed there
+func writesManifestsToStdout(cmd *cobra.Command) bool {
+	if f := cmd.Flags().Lookup(stdoutFlag); f != nil && f.Value.String() == "true" {
+		return true
+	}
+	if f := cmd.Flags().Lookup(outputFlag); f != nil && f.Value.String() == "-" {
+		return true
+	}
+	return false
+}
+
 // checkSourcePath checks if the source path is an existing directory.
 func checkSourcePath(srcpath string) {
 	fi, err := os.Stat(srcpath)
 	if os.IsNotExist(err) {
-		logrus.Fatalf("The given source directory %s does not exist. Error: %q", srcpath, err)
+		logrus.Fatalf(i18n.T("The given source directory %s does not exist. Error: %q"), srcpath, err)
 	}
 	if err != nil {
-		logrus.Fatalf("Error while accessing the given source directory %s Error: %q", srcpath, err)
+		logrus.Fatalf(i18n.T("Error while accessing the given source directory %s Error: %q"), srcpath, err)
 	}
 	if !fi.IsDir() {
-		logrus.Fatalf("The given source path %s is a file. Expected a directory. Exiting.", srcpath)
+		logrus.Fatalf(i18n.T("The given source path %s is a file. Expected a directory. Exiting."), srcpath)
 	}
 	pwd, err := os.Getwd()
 	if err != nil {
-		logrus.Fatalf("Failed to get the current working directory. Error: %q", err)
+		logrus.Fatalf(i18n.T("Failed to get the current working directory. Error: %q"), err)
 	}
 	if common.IsParent(pwd, srcpath) {
-		logrus.Fatalf("The given source directory %s is a parent of the current working directory.", srcpath)
+		logrus.Fatalf(i18n.T("The given source directory %s is a parent of the current working directory."), srcpath)
+	}
+}
+
+// fetchRemotePathIfRequired fetches the path into the temp directory if it is a remote path and returns the local path
+func fetchRemotePathIfRequired(path string) string {
+	if !vcs.IsRemotePath(path) {
+		return path
+	}
+	localPath, err := vcs.FetchRemotePath(path, filepath.Join(common.TempPath, common.RemoteDir))
+	if err != nil {
+		logrus.Fatalf(i18n.T("Failed to fetch the remote path %s . Error: %q"), path, err)
+	}
+	return localPath
+}
+
+// getRemoteURL returns the path if it is a git remote path or an archive url, and empty string otherwise.
+// Local archives are returned as an absolute path pinned to the checksum of the archive.
+func getRemoteURL(path string) string {
+	if !vcs.IsRemotePath(path) {
+		return ""
 	}
+	pinnedPath, err := vcs.PinLocalArchivePath(path)
+	if err != nil {
+		logrus.Fatalf(i18n.T("Failed to calculate the checksum of the archive %s . Error: %q"), path, err)
+	}
+	return pinnedPath
 }
 
 // checkOutputPath checks if the output path is already in use.
@@ -59,43 +104,101 @@ func checkOutputPath(outpath string, overwrite bool) {
 		return
 	}
 	if err != nil {
-		logrus.Fatalf("Error while accessing output directory at path %s Error: %q . Exiting", outpath, err)
+		logrus.Fatalf(i18n.T("Error while accessing output directory at path %s Error: %q . Exiting"), outpath, err)
 	}
 	if !overwrite {
-		logrus.Fatalf("Output directory %s exists. Exiting", outpath)
+		logrus.Fatalf(i18n.T("Output directory %s exists. Exiting"), outpath)
 	}
 	if !fi.IsDir() {
-		logrus.Fatalf("Output path %s is a file. Expected a directory. Exiting", outpath)
+		logrus.Fatalf(i18n.T("Output path %s is a file. Expected a directory. Exiting"), outpath)
 	}
 	pwd, err := os.Getwd()
 	if err != nil {
-		logrus.Fatalf("Failed to get the current working directory. Error: %q", err)
+		logrus.Fatalf(i18n.T("Failed to get the current working directory. Error: %q"), err)
 	}
 	if common.IsParent(pwd, outpath) {
-		logrus.Fatalf("The given output directory %s is a parent of the current working directory.", outpath)
+		logrus.Fatalf(i18n.T("The given output directory %s is a parent of the current working directory."), outpath)
+	}
+	logrus.Infof(i18n.T("Output directory %s exists. The contents might get overwritten."), outpath)
+}
+
+// getOutputArchiveExt returns the extension of the archive the output is written to, and empty string if the output is a directory
+func getOutputArchiveExt(outputFormat string) (string, error) {
+	switch outputFormat {
+	case directoryOutputFormat:
+		return "", nil
+	case archiveOutputFormat, tarGzOutputFormat:
+		return ".tar.gz", nil
+	case zipOutputFormat:
+		return ".zip", nil
+	}
+	return "", fmt.Errorf("the output format must be one of %s, %s, %s or %s . Actual: %s", directoryOutputFormat, archiveOutputFormat, tarGzOutputFormat, zipOutputFormat, outputFormat)
+}
+
+// checkOutputArchivePath checks if the path of the output archive is already in use.
+func checkOutputArchivePath(archivePath string, overwrite bool) {
+	fi, err := os.Stat(archivePath)
+	if os.IsNotExist(err) {
+		logrus.Debugf("Transformed artifacts will be archived to %s", archivePath)
+		return
+	}
+	if err != nil {
+		logrus.Fatalf(i18n.T("Error while accessing the output archive at path %s Error: %q . Exiting"), archivePath, err)
+	}
+	if fi.IsDir() {
+		logrus.Fatalf(i18n.T("Output archive path %s is a directory. Expected a file. Exiting"), archivePath)
+	}
+	if !overwrite {
+		logrus.Fatalf(i18n.T("Output archive %s exists. Exiting"), archivePath)
+	}
+	logrus.Infof(i18n.T("Output archive %s exists. It will be overwritten."), archivePath)
+}
+
+// setupQAEncryption sets the passphrase and the keys used to encrypt the config and the cache files at rest.
+// The passphrase is read from the environment so that it doesn't end up in the shell history.
+func setupQAEncryption(certificatePath, privateKeyPath string) {
+	e := qatypes.Encryption{Passphrase: os.Getenv(qatypes.QAPassphraseEnvVar)}
+	if certificatePath != "" {
+		certificate, err := os.ReadFile(certificatePath)
+		if err != nil {
+			logrus.Fatalf("failed to read the certificate at path %s . Error: %q", certificatePath, err)
+		}
+		e.Certificate = string(certificate)
+	}
+	if privateKeyPath != "" {
+		privateKey, err := os.ReadFile(privateKeyPath)
+		if err != nil {
+			logrus.Fatalf("failed to read the private key at path %s . Error: %q", privateKeyPath, err)
+		}
+		e.PrivateKey = string(privateKey)
+	}
+	if err := qatypes.SetEncryption(e); err != nil {
+		logrus.Fatalf("failed to set up the encryption of the config and the cache. Error: %q", err)
 	}
-	logrus.Infof("Output directory %s exists. The contents might get overwritten.", outpath)
 }
 
 func startQA(flags qaflags) {
-	qaengine.StartEngine(flags.qaskip, flags.qaport, flags.qadisablecli)
+	setupQAEncryption(flags.qaEncryptionCert, flags.qaDecryptionKey)
+	qaengine.StartEngine(flags.qaskip, flags.qaport, flags.qadisablecli, flags.qagrpc)
+	qaengine.SetQATimeout(flags.qaTimeout)
+	qaengine.SetCategories(flags.qaEnableCategories, flags.qaDisableCategories)
 	if flags.configOut == "" {
-		qaengine.SetupConfigFile("", flags.setconfigs, flags.configs, flags.preSets, flags.persistPasswords)
+		qaengine.SetupConfigFile("", flags.setconfigs, flags.configs, flags.preSets, flags.profile, flags.persistPasswords)
 	} else {
 		if flags.configOut == "." {
-			qaengine.SetupConfigFile(common.ConfigFile, flags.setconfigs, flags.configs, flags.preSets, flags.persistPasswords)
+			qaengine.SetupConfigFile(common.ConfigFile, flags.setconfigs, flags.configs, flags.preSets, flags.profile, flags.persistPasswords)
 		} else if fi, err := os.Stat(flags.configOut); err == nil {
 			if fi.IsDir() {
-				qaengine.SetupConfigFile(filepath.Join(flags.configOut, common.ConfigFile), flags.setconfigs, flags.configs, flags.preSets, flags.persistPasswords)
+				qaengine.SetupConfigFile(filepath.Join(flags.configOut, common.ConfigFile), flags.setconfigs, flags.configs, flags.preSets, flags.profile, flags.persistPasswords)
 			} else {
-				qaengine.SetupConfigFile(flags.configOut, flags.setconfigs, flags.configs, flags.preSets, flags.persistPasswords)
+				qaengine.SetupConfigFile(flags.configOut, flags.setconfigs, flags.configs, flags.preSets, flags.profile, flags.persistPasswords)
 			}
 		} else if strings.Contains(filepath.Base(flags.configOut), ".") {
 			os.MkdirAll(filepath.Dir(flags.configOut), common.DefaultDirectoryPermission)
-			qaengine.SetupConfigFile(flags.configOut, flags.setconfigs, flags.configs, flags.preSets, flags.persistPasswords)
+			qaengine.SetupConfigFile(flags.configOut, flags.setconfigs, flags.configs, flags.preSets, flags.profile, flags.persistPasswords)
 		} else {
 			os.MkdirAll(flags.configOut, common.DefaultDirectoryPermission)
-			qaengine.SetupConfigFile(filepath.Join(flags.configOut, common.ConfigFile), flags.setconfigs, flags.configs, flags.preSets, flags.persistPasswords)
+			qaengine.SetupConfigFile(filepath.Join(flags.configOut, common.ConfigFile), flags.setconfigs, flags.configs, flags.preSets, flags.profile, flags.persistPasswords)
 		}
 	}
 	if flags.qaCacheOut != "" {
@@ -115,8 +218,21 @@ func startQA(flags qaflags) {
 			qaengine.SetupWriteCacheFile(filepath.Join(flags.qaCacheOut, common.QACacheFile), flags.persistPasswords)
 		}
 	}
+	if flags.qaReplay != "" {
+		qaengine.SetupReplaySessionFile(flags.qaReplay)
+	}
+	if flags.qaSessionOut != "" {
+		if err := os.MkdirAll(filepath.Dir(flags.qaSessionOut), common.DefaultDirectoryPermission); err != nil {
+			logrus.Errorf("Failed to create the directory for the session file %s . Error: %q", flags.qaSessionOut, err)
+		}
+		qaengine.SetupSessionFile(flags.qaSessionOut)
+	}
+	if flags.vaultPathTemplate != "" {
+		qaengine.SetupVaultEngine(flags.vaultPathTemplate)
+	}
+	qaengine.SetupEnvStore()
 	if err := qaengine.WriteStoresToDisk(); err != nil {
-		logrus.Warnf("Failed to write the stores to disk. Error: %q", err)
+		logrus.Warnf(i18n.T("Failed to write the stores to disk. Error: %q"), err)
 	}
 }
 
@@ -139,3 +255,43 @@ func startPlanProgressServer(port int) {
 	}()
 	logrus.Trace("startPlanProgressServer end")
 }
+
+// setupWebhooks sends the lifecycle events to the webhook urls given through the flags and the config.
+// It must be called after the QA engine has been started.
+func setupWebhooks(flagURLs []string, stallTimeout time.Duration) {
+	answer := qaengine.FetchMultilineInputAnswer(
+		common.ConfigWebhookURLsKey,
+		"Enter the webhook urls that should receive the plan and transform lifecycle events (one url per line):",
+		[]string{"The events are posted as json. Leave empty to not send any events."},
+		strings.Join(flagURLs, "\n"),
+		nil,
+	)
+	urls := []string{}
+	for _, line := range strings.Split(answer, "\n") {
+		if url := strings.TrimSpace(line); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	webhook.SetURLs(urls)
+	if len(urls) == 0 {
+		return
+	}
+	qaengine.SetStallHandler(stallTimeout, func(prob qatypes.Problem) {
+		webhook.Send(
+			webhook.QuestionStalled,
+			fmt.Sprintf("the question '%s' has not been answered for %s", prob.Desc, stallTimeout),
+			map[string]interface{}{"id": prob.ID, "description": prob.Desc},
+		)
+	})
+}
+
+// setupTracing exports the traces to the OTLP collector if one is configured and flushes them on exit
+func setupTracing(ctx context.Context, endpoint string, insecure bool) func() {
+	shutdown, err := tracing.Setup(ctx, endpoint, insecure)
+	if err != nil {
+		logrus.Errorf("failed to setup the tracing. Error: %q", err)
+		return func() {}
+	}
+	logrus.AddHook(common.NewCleanupHook(shutdown))
+	return shutdown
+}
@@ -0,0 +1,88 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/lib"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type validateFlags struct {
+	// planfile is the path of the plan file to validate
+	planfile string
+	// customizationsPath is the customizations directory to validate
+	customizationsPath string
+}
+
+func validateHandler(cmd *cobra.Command, flags validateFlags) {
+	validatePlan := cmd.Flags().Changed(planFlag)
+	if !validatePlan {
+		if _, err := os.Stat(flags.planfile); err == nil {
+			validatePlan = true
+		}
+	}
+	if !cmd.Flags().Changed(customizationsFlag) {
+		if _, err := os.Stat(common.DefaultCustomizationDir); err == nil {
+			flags.customizationsPath = common.DefaultCustomizationDir
+		}
+	}
+	flags.customizationsPath = fetchRemotePathIfRequired(flags.customizationsPath)
+	if !validatePlan && flags.customizationsPath == "" {
+		logrus.Fatalf("nothing to validate. Use the --%s flag to validate a plan and the --%s flag to validate the customizations.", planFlag, customizationsFlag)
+	}
+	issues := []lib.ValidationIssue{}
+	if validatePlan {
+		logrus.Infof("Validating the plan file at path %s", flags.planfile)
+		issues = append(issues, lib.ValidatePlanFile(flags.planfile)...)
+	}
+	if flags.customizationsPath != "" {
+		logrus.Infof("Validating the customizations in the directory %s", flags.customizationsPath)
+		issues = append(issues, lib.ValidateCustomizations(flags.customizationsPath)...)
+	}
+	for _, issue := range issues {
+		fmt.Println(issue.String())
+	}
+	if len(issues) != 0 {
+		logrus.Fatalf("found %d problems", len(issues))
+	}
+	logrus.Infof("No problems found")
+}
+
+// GetValidateCommand returns a command to validate the plan and the customizations without running a transform
+func GetValidateCommand() *cobra.Command {
+	viper.AutomaticEnv()
+	flags := validateFlags{}
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the plan and the customizations without running a transform.",
+		Long: `Validate the plan and the customizations without running a transform.
+	The plan file, the transformer yamls, the parameterizer yamls and the syntax of the Starlark files are checked
+	and all the problems are reported along with their file and line.
+	By default the m2k.plan file and the customizations directory in the current working directory are validated if they exist.`,
+		Args: cobra.NoArgs,
+		Run:  func(cmd *cobra.Command, _ []string) { validateHandler(cmd, flags) },
+	}
+	validateCmd.Flags().StringVarP(&flags.planfile, planFlag, "p", common.DefaultPlanFile, "Specify the plan file to validate.")
+	validateCmd.Flags().StringVarP(&flags.customizationsPath, customizationsFlag, "c", "", "Specify the customizations directory to validate. Can also be a git remote path. By default we look for "+common.DefaultCustomizationDir)
+	return validateCmd
+}
@@ -39,6 +39,11 @@ import (
 	cgclientcmd "k8s.io/client-go/tools/clientcmd"
 )
 
+const (
+	defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+	defaultIngressClassAnnotation = "ingressclass.kubernetes.io/is-default-class"
+)
+
 //ClusterCollector Implements Collector interface
 type ClusterCollector struct {
 	clusterCmd string
@@ -70,10 +75,16 @@ func (c *ClusterCollector) Collect(inputPath string, outputPath string) error {
 		return err
 	}
 	clusterMd := collecttypes.NewClusterMetadata(name)
-	if clusterMd.Spec.StorageClasses, err = c.getStorageClasses(); err != nil {
+	if clusterMd.Spec.StorageClasses, clusterMd.Spec.DefaultStorageClass, err = c.getStorageClasses(); err != nil {
 		//If no storage classes, this will be an empty array
 		clusterMd.Spec.StorageClasses = []string{}
 	}
+	if clusterMd.Spec.IngressClasses, clusterMd.Spec.DefaultIngressClass, err = c.getIngressClasses(); err != nil {
+		logrus.Debugf("Failed to collect the ingress classes. Error: %q", err)
+	}
+	if clusterMd.Spec.KubernetesVersion, err = c.getKubernetesVersion(); err != nil {
+		logrus.Debugf("Failed to collect the Kubernetes version of the cluster. Error: %q", err)
+	}
 
 	clusterMd.Spec.APIKindVersionMap, err = c.collectUsingAPI()
 	if err != nil {
@@ -122,39 +133,70 @@ func (c *ClusterCollector) getClusterContextName() (string, error) {
 	return strings.TrimSpace(string(name)), err
 }
 
-func (c *ClusterCollector) getStorageClasses() ([]string, error) {
+func (c *ClusterCollector) getStorageClasses() ([]string, string, error) {
+	return c.getResourceNamesAndDefault("sc", defaultStorageClassAnnotation)
+}
+
+func (c *ClusterCollector) getIngressClasses() ([]string, string, error) {
+	return c.getResourceNamesAndDefault("ingressclass", defaultIngressClassAnnotation)
+}
+
+// getResourceNamesAndDefault returns the names of all the resources of the given type
+// and the name of the one marked as the cluster default using the given annotation.
+func (c *ClusterCollector) getResourceNamesAndDefault(resource string, defaultAnnotation string) ([]string, string, error) {
 	ccmd := c.getClusterCommand()
-	cmd := exec.Command(ccmd, "get", "sc", "-o", "yaml")
+	cmd := exec.Command(ccmd, "get", resource, "-o", "yaml")
 	yamlOutput, err := cmd.CombinedOutput()
 	if err != nil {
 		errDesc := c.interpretError(string(yamlOutput))
 		if errDesc != "" {
 			logrus.Warnf("Error while running %s. %s", ccmd, errDesc)
 		} else {
-			logrus.Warnf("Error while fetching storage classes using command [%s]", cmd)
+			logrus.Warnf("Error while fetching %s using command [%s]", resource, cmd)
 		}
-		return nil, err
+		return nil, "", err
 	}
+	return getNamesAndDefault(yamlOutput, defaultAnnotation)
+}
 
-	fileContents := map[string]interface{}{}
-	err = yaml.Unmarshal(yamlOutput, &fileContents)
-	if err != nil {
+// getNamesAndDefault parses the yaml list output of kubectl get
+func getNamesAndDefault(yamlOutput []byte, defaultAnnotation string) ([]string, string, error) {
+	fileContents := struct {
+		Items []struct {
+			Metadata struct {
+				Name        string            `yaml:"name"`
+				Annotations map[string]string `yaml:"annotations"`
+			} `yaml:"metadata"`
+		} `yaml:"items"`
+	}{}
+	if err := yaml.Unmarshal(yamlOutput, &fileContents); err != nil {
 		logrus.Errorf("Error in unmarshalling yaml: %s. Skipping.", err)
-		return nil, err
+		return nil, "", err
 	}
-
-	scArray := fileContents["items"].([]interface{})
-	storageClasses := []string{}
-
-	for _, sc := range scArray {
-		if mapSC, ok := sc.(map[string]interface{}); ok {
-			storageClasses = append(storageClasses, mapSC["metadata"].(map[string]interface{})["name"].(string))
-		} else {
-			logrus.Warnf("Unknown type detected in cluster metadata [%T]", mapSC)
+	names := []string{}
+	defaultName := ""
+	for _, item := range fileContents.Items {
+		if item.Metadata.Name == "" {
+			continue
+		}
+		names = append(names, item.Metadata.Name)
+		if defaultName == "" && item.Metadata.Annotations[defaultAnnotation] == "true" {
+			defaultName = item.Metadata.Name
 		}
 	}
+	return names, defaultName, nil
+}
 
-	return storageClasses, nil
+func (c *ClusterCollector) getKubernetesVersion() (string, error) {
+	api, err := c.getAPI()
+	if err != nil {
+		return "", err
+	}
+	version, err := api.ServerVersion()
+	if err != nil {
+		return "", err
+	}
+	return version.GitVersion, nil
 }
 
 func (c *ClusterCollector) interpretError(cmdOutput string) string {
@@ -26,6 +26,7 @@ import (
 
 	sourcetypes "github.com/konveyor/move2kube/collector/sourcetypes"
 	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/imagecache"
 	collecttypes "github.com/konveyor/move2kube/types/collection"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cast"
@@ -57,8 +58,12 @@ func (c *ImagesCollector) Collect(inputDirectory string, outputPath string) erro
 	logrus.Debugf("Images : %s", imageNames)
 	for _, imageName := range imageNames {
 		imagedata, err := getDockerInspectResult(imageName)
-		if err != nil {
-			continue
+		if err != nil || imagedata == nil {
+			imagedata, err = getCachedImageInspectResult(imageName)
+			if err != nil {
+				logrus.Debugf("Unable to get the image %s from the image cache : %s", imageName, err)
+				continue
+			}
 		}
 		if imagedata != nil {
 			imageInfo := getImageInfo(imagedata)
@@ -99,6 +104,31 @@ func getDockerInspectResult(imageName string) ([]byte, error) {
 	return jsonOutput, nil
 }
 
+// getCachedImageInspectResult returns the docker inspect output for an image in the image cache
+func getCachedImageInspectResult(imageName string) ([]byte, error) {
+	imageCache := imagecache.Get()
+	if imageCache == nil {
+		return nil, nil
+	}
+	configFile, err := imageCache.ConfigFile(imageName)
+	if err != nil {
+		return nil, err
+	}
+	exposedPorts := map[string]interface{}{}
+	for port := range configFile.Config.ExposedPorts {
+		exposedPorts[port] = struct{}{}
+	}
+	return json.Marshal([]sourcetypes.DockerImage{{
+		RepoTags: []string{imageName},
+		CConfig: sourcetypes.ContainerConfig{
+			EPorts:     exposedPorts,
+			User:       configFile.Config.User,
+			Env:        configFile.Config.Env,
+			WorkingDir: configFile.Config.WorkingDir,
+		},
+	}})
+}
+
 func getImageInfo(data []byte) collecttypes.ImageInfo {
 	imageInfo := collecttypes.NewImageInfo()
 	imgLayerInfo := []sourcetypes.DockerImage{}
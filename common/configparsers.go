@@ -0,0 +1,91 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+	"github.com/magiconair/properties"
+	"gopkg.in/ini.v1"
+)
+
+// ParseProperties parses the contents of a java .properties file into its keys and values.
+// The ${key} references in the values are left as they are.
+func ParseProperties(data string) (map[string]interface{}, error) {
+	loader := properties.Loader{Encoding: properties.UTF8, DisableExpansion: true}
+	props, err := loader.LoadBytes([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the properties. Error: %w", err)
+	}
+	values := map[string]interface{}{}
+	for key, value := range props.Map() {
+		values[key] = value
+	}
+	return values, nil
+}
+
+// ParseTOML parses the contents of a .toml file into maps, lists and scalars
+func ParseTOML(data string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if _, err := toml.Decode(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse the toml. Error: %w", err)
+	}
+	return normalizeTOMLValue(values).(map[string]interface{}), nil
+}
+
+// ParseINI parses the contents of a .ini file into a map of sections to their keys and values.
+// The keys before the first section are in the DEFAULT section.
+func ParseINI(data string) (map[string]interface{}, error) {
+	file, err := ini.Load([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the ini. Error: %w", err)
+	}
+	sections := map[string]interface{}{}
+	for _, section := range file.Sections() {
+		if section.Name() == ini.DefaultSection && len(section.Keys()) == 0 {
+			continue
+		}
+		values := map[string]interface{}{}
+		for _, key := range section.Keys() {
+			values[key.Name()] = key.Value()
+		}
+		sections[section.Name()] = values
+	}
+	return sections, nil
+}
+
+// normalizeTOMLValue converts the arrays of tables into lists of maps
+func normalizeTOMLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			v[key] = normalizeTOMLValue(elem)
+		}
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = normalizeTOMLValue(elem)
+		}
+	case []map[string]interface{}:
+		values := []interface{}{}
+		for _, elem := range v {
+			values = append(values, normalizeTOMLValue(elem))
+		}
+		return values
+	}
+	return value
+}
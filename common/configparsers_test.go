@@ -0,0 +1,101 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseProperties(t *testing.T) {
+	data := `# database
+db.url=jdbc:postgresql://${db.host}:5432/app
+db.host = localhost
+app.name: demo
+`
+	want := map[string]interface{}{
+		"db.url":   "jdbc:postgresql://${db.host}:5432/app",
+		"db.host":  "localhost",
+		"app.name": "demo",
+	}
+	got, err := ParseProperties(data)
+	if err != nil {
+		t.Fatalf("failed to parse the properties. Error: %q", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected properties. Difference:\n%s", diff)
+	}
+}
+
+func TestParseTOML(t *testing.T) {
+	data := `name = "demo"
+port = 8080
+
+[database]
+host = "localhost"
+enabled = true
+
+[[servers]]
+name = "alpha"
+
+[[servers]]
+name = "beta"
+`
+	want := map[string]interface{}{
+		"name":     "demo",
+		"port":     int64(8080),
+		"database": map[string]interface{}{"host": "localhost", "enabled": true},
+		"servers": []interface{}{
+			map[string]interface{}{"name": "alpha"},
+			map[string]interface{}{"name": "beta"},
+		},
+	}
+	got, err := ParseTOML(data)
+	if err != nil {
+		t.Fatalf("failed to parse the toml. Error: %q", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected toml values. Difference:\n%s", diff)
+	}
+	if _, err := ParseTOML("name = "); err == nil {
+		t.Fatalf("expected an error for invalid toml")
+	}
+}
+
+func TestParseINI(t *testing.T) {
+	data := `timeout = 30
+
+[database]
+host = localhost
+port = 5432
+
+[cache]
+`
+	want := map[string]interface{}{
+		"DEFAULT":  map[string]interface{}{"timeout": "30"},
+		"database": map[string]interface{}{"host": "localhost", "port": "5432"},
+		"cache":    map[string]interface{}{},
+	}
+	got, err := ParseINI(data)
+	if err != nil {
+		t.Fatalf("failed to parse the ini. Error: %q", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected ini values. Difference:\n%s", diff)
+	}
+}
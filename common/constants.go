@@ -46,12 +46,42 @@ const (
 	QACacheFile = types.AppNameShort + "qacache.yaml"
 	// ConfigFile defines the location of the config file
 	ConfigFile = types.AppNameShort + "config.yaml"
+	// FidelityReportFile is the name of the file listing the source fields that did not survive the translation
+	FidelityReportFile = types.AppNameShort + "fidelityreport.yaml"
+	// DockerfileLintReportFile is the name of the file listing the problems found in the Dockerfiles
+	DockerfileLintReportFile = types.AppNameShort + "dockerfilelintreport.yaml"
+	// ReadinessReportFile is the name of the file with the migration readiness score of each service
+	ReadinessReportFile = types.AppNameShort + "readinessreport.yaml"
+	// MigrationReportMarkdownFile is the name of the markdown file summarizing the transformation
+	MigrationReportMarkdownFile = types.AppNameShort + "report.md"
+	// MigrationReportHTMLFile is the name of the html file summarizing the transformation
+	MigrationReportHTMLFile = types.AppNameShort + "report.html"
+	// QAReportMarkdownFile is the name of the markdown file listing the questions, their answers and the transformers that asked them
+	QAReportMarkdownFile = types.AppNameShort + "qareport.md"
+	// QAReportHTMLFile is the name of the html file listing the questions, their answers and the transformers that asked them
+	QAReportHTMLFile = types.AppNameShort + "qareport.html"
+	// TODOReportFile is the name of the file listing the manual actions needed to complete the output
+	TODOReportFile = types.AppNameShort + "todo.yaml"
+	// ServiceReadmeFile is the name of the file describing how a service was detected, built and deployed
+	ServiceReadmeFile = "README.md"
+	// ServiceReadmeFallbackFile is the name of the service readme when the service already has a README.md
+	ServiceReadmeFallbackFile = types.AppNameShort + "readme.md"
+	// DetectionExplanationFile is the name of the file explaining why the transformers did or did not detect services in each directory
+	DetectionExplanationFile = types.AppNameShort + ".plan.explain.yaml"
+	// HooksFile is the name of the file in the customizations directory declaring the hooks to run during planning and transformation
+	HooksFile = types.AppNameShort + ".hooks.yaml"
 	// IgnoreFilename is the name of the file containing the ignore rules and exceptions
 	IgnoreFilename = "." + types.AppNameShort + "ignore"
 	// WindowsAnnotation tag is used tag a service to run on windows nodes
 	WindowsAnnotation = types.GroupName + "/containertype.windows"
 	// AnnotationLabelValue represents the value when an annotation is valid
 	AnnotationLabelValue = "true"
+	// PrometheusScrapeAnnotation marks the pods and services whose metrics should be scraped by Prometheus
+	PrometheusScrapeAnnotation = "prometheus.io/scrape"
+	// PrometheusPathAnnotation is the path of the metrics endpoint
+	PrometheusPathAnnotation = "prometheus.io/path"
+	// PrometheusPortAnnotation is the port of the metrics endpoint
+	PrometheusPortAnnotation = "prometheus.io/port"
 	// DefaultServicePort is the default port that will be added to a service.
 	DefaultServicePort int32 = 8080
 	// TODOAnnotation is used to annotate with TODO tasks
@@ -107,6 +137,10 @@ const (
 	ConfigTransformersKey = BaseKey + d + "transformers"
 	//ConfigTargetKey represents Target Key
 	ConfigTargetKey = BaseKey + d + "target"
+	//ConfigTemplatesKey represents the key that enables the evaluation of the templates in the answers of the config files
+	ConfigTemplatesKey = BaseKey + d + "configtemplates"
+	//ConfigWebhookURLsKey represents the key for the urls that receive the lifecycle events
+	ConfigWebhookURLsKey = BaseKey + d + "webhooks" + d + "urls"
 	//ConfigRepoKey represents Repo Key
 	ConfigRepoKey = BaseKey + d + "repo"
 	//ConfigContainerizationKeySegment represents Containerization Key segment
@@ -139,12 +173,90 @@ const (
 	ConfigTargetClusterTypeKey = ConfigTargetKey + d + "clustertype"
 	//ConfigImageRegistryKey represents image registry Key
 	ConfigImageRegistryKey = ConfigTargetKey + d + "imageregistry"
+	//ConfigTargetEnvironmentsKey represents the key for the environments used for parameterization
+	ConfigTargetEnvironmentsKey = ConfigTargetKey + d + "environments"
+	//ConfigTargetNamespacePerEnvironmentKey represents the key for creating a namespace for each environment
+	ConfigTargetNamespacePerEnvironmentKey = ConfigTargetKey + d + "namespaceperenvironment"
+	//ConfigTargetLabelsKey represents the key for the labels added to all the k8s resources
+	ConfigTargetLabelsKey = ConfigTargetKey + d + "labels"
+	//ConfigTargetAnnotationsKey represents the key for the annotations added to all the k8s resources
+	ConfigTargetAnnotationsKey = ConfigTargetKey + d + "annotations"
 	//ConfigTargetExistingVersionUpdate represents key which how to update versions
 	ConfigTargetExistingVersionUpdate = ConfigTargetKey + d + "existingversionupdate"
 	//ConfigImageRegistryURLKey represents image registry url Key
 	ConfigImageRegistryURLKey = ConfigImageRegistryKey + d + "url"
 	//ConfigImageRegistryNamespaceKey represents image registry namespace Key
 	ConfigImageRegistryNamespaceKey = ConfigImageRegistryKey + d + "namespace"
+	//ConfigImageRegistryRewriteRulesKey represents the image registry rewrite rules Key
+	ConfigImageRegistryRewriteRulesKey = ConfigImageRegistryKey + d + "rewriterules"
+	//ConfigImageTagSourceKey represents the key for where the tags of the new images come from
+	ConfigImageTagSourceKey = ConfigTargetKey + d + "imagetagsource"
+	//ConfigSigningKey represents the key for signing the images and manifests with cosign
+	ConfigSigningKey = ConfigTargetKey + d + "signing"
+	//ConfigSigningEnableKey represents the key for enabling the signing
+	ConfigSigningEnableKey = ConfigSigningKey + d + "enable"
+	//ConfigSigningModeKey represents the key for choosing between keyless and key based signing
+	ConfigSigningModeKey = ConfigSigningKey + d + "mode"
+	//ConfigSigningKeyRefKey represents the key for the cosign private key used for key based signing
+	ConfigSigningKeyRefKey = ConfigSigningKey + d + "key"
+	//ConfigSigningIdentityKey represents the key for the identity of the signer used for keyless signing
+	ConfigSigningIdentityKey = ConfigSigningKey + d + "identity"
+	//ConfigSigningIssuerKey represents the key for the OIDC issuer used for keyless signing
+	ConfigSigningIssuerKey = ConfigSigningKey + d + "issuer"
+	//ConfigSchemaValidationKey represents the key for how the generated manifests are validated against the Kubernetes schemas
+	ConfigSchemaValidationKey = ConfigTargetKey + d + "schemavalidation"
+	//ConfigPolicyFailOnViolationKey represents the key for failing the transformation when the generated resources violate the policies
+	ConfigPolicyFailOnViolationKey = ConfigTargetKey + d + "policies" + d + "failonviolation"
+	//ConfigDockerfileLintKey represents the key for linting the Dockerfiles in the output
+	ConfigDockerfileLintKey = ConfigTargetKey + d + "dockerfilelint"
+	//ConfigDockerfileLintEnableKey represents the key for enabling the Dockerfile linter
+	ConfigDockerfileLintEnableKey = ConfigDockerfileLintKey + d + "enable"
+	//ConfigDockerfileLintIncludeSourceKey represents the key for also linting the Dockerfiles copied from the source
+	ConfigDockerfileLintIncludeSourceKey = ConfigDockerfileLintKey + d + "includesource"
+	//ConfigDockerfileLintAutoFixKey represents the key for automatically fixing the trivial problems in the generated Dockerfiles
+	ConfigDockerfileLintAutoFixKey = ConfigDockerfileLintKey + d + "autofix"
+	//ConfigSourceFilesCopyPolicyKey represents the key for the policy on copying the original source files into the output
+	ConfigSourceFilesCopyPolicyKey = ConfigTargetKey + d + "sourcefiles" + d + "copypolicy"
+	//ConfigSourceFilesSelectedKey represents the key for the source directories to copy into the output
+	ConfigSourceFilesSelectedKey = ConfigTargetKey + d + "sourcefiles" + d + "selected"
+	//ConfigComposeExtraFilesKey represents the key for the additional compose files merged into a compose file
+	ConfigComposeExtraFilesKey = BaseKey + d + "compose" + d + "%s" + d + "extrafiles"
+	//ConfigTargetOutputLayoutKey represents the key for the layout of the output directory
+	ConfigTargetOutputLayoutKey = ConfigTargetKey + d + "outputlayout"
+	//ConfigTargetOutputPermissionsKey represents the key for the file modes and ownership of the output
+	ConfigTargetOutputPermissionsKey = ConfigTargetKey + d + "outputpermissions"
+	//ConfigTargetOutputPermissionsModeKey represents the key for the policy on the modes of the output files
+	ConfigTargetOutputPermissionsModeKey = ConfigTargetOutputPermissionsKey + d + "mode"
+	//ConfigTargetOutputPermissionsOwnerKey represents the key for the owner of the output files
+	ConfigTargetOutputPermissionsOwnerKey = ConfigTargetOutputPermissionsKey + d + "owner"
+	//ConfigTargetServiceMeshKey represents the key for the service mesh the output is prepared for
+	ConfigTargetServiceMeshKey = ConfigTargetKey + d + "servicemesh"
+	//ConfigTargetMonitoringStackKey represents the key for the monitoring stack of the target cluster
+	ConfigTargetMonitoringStackKey = ConfigTargetKey + d + "monitoringstack"
+	//ConfigTargetLoggingKey represents the key for how the logs of the services are collected
+	ConfigTargetLoggingKey = ConfigTargetKey + d + "logging"
+	//ConfigTargetLoggingForwardAddressKey represents the key for the address the logging sidecars forward the logs to
+	ConfigTargetLoggingForwardAddressKey = ConfigTargetLoggingKey + d + "forwardaddress"
+	//ConfigTargetVPAKey represents the key for generating VerticalPodAutoscalers in recommendation mode
+	ConfigTargetVPAKey = ConfigTargetKey + d + "verticalpodautoscaler"
+	//ConfigGitPushKey represents the key for pushing the output to a git repo
+	ConfigGitPushKey = ConfigTargetKey + d + "gitpush"
+	//ConfigGitPushRepoURLKey represents the key for the url of the git repo the output is pushed to
+	ConfigGitPushRepoURLKey = ConfigGitPushKey + d + "repourl"
+	//ConfigGitPushBaseBranchKey represents the key for the branch the new branch is created from
+	ConfigGitPushBaseBranchKey = ConfigGitPushKey + d + "basebranch"
+	//ConfigGitPushBranchKey represents the key for the name of the new branch the output is committed to
+	ConfigGitPushBranchKey = ConfigGitPushKey + d + "branch"
+	//ConfigGitPushPathKey represents the key for the directory inside the git repo the output is written to
+	ConfigGitPushPathKey = ConfigGitPushKey + d + "path"
+	//ConfigGitPushUsernameKey represents the key for the username used to push to http(s) git repos
+	ConfigGitPushUsernameKey = ConfigGitPushKey + d + "username"
+	//ConfigGitPushTokenKey represents the key for the password or token used to push and open the pull request
+	ConfigGitPushTokenKey = ConfigGitPushKey + d + "token"
+	//ConfigGitPushPullRequestKey represents the key for opening a pull request for the new branch
+	ConfigGitPushPullRequestKey = ConfigGitPushKey + d + "pullrequest"
+	//ConfigGitPushProviderKey represents the key for the git hosting provider whose API is used to open the pull request
+	ConfigGitPushProviderKey = ConfigGitPushKey + d + "provider"
 	//ConfigImageRegistryLoginTypeKey represents image registry login type Key
 	ConfigImageRegistryLoginTypeKey = ConfigImageRegistryKey + d + "%s" + d + "logintype"
 	//ConfigImageRegistryPullSecretKey represents image registry pull secret Key
@@ -157,10 +269,30 @@ const (
 	ConfigStoragesPVCForHostPathKey = ConfigStoragesKey + d + "pvcforhostpath"
 	//ConfigStoragesPerClaimStorageClassKey represents key for having different storage class for claim
 	ConfigStoragesPerClaimStorageClassKey = ConfigStoragesKey + d + "perclaimstorageclass"
+	//ConfigStoragesTypeKey represents the key for the type of volume a detected volume is converted to
+	ConfigStoragesTypeKey = ConfigStoragesKey + d + "%s" + d + "type"
+	//ConfigStoragesSizeKey represents the key for the size requested by a persistent volume claim
+	ConfigStoragesSizeKey = ConfigStoragesKey + d + "%s" + d + "size"
+	//ConfigStoragesAccessModeKey represents the key for the access mode of a persistent volume claim
+	ConfigStoragesAccessModeKey = ConfigStoragesKey + d + "%s" + d + "accessmode"
+	//ConfigStoragesStorageClassKey represents the key for the storage class of a persistent volume claim
+	ConfigStoragesStorageClassKey = ConfigStoragesKey + d + "%s" + d + "storageclass"
 	//ConfigServicesNamesKey is true if a detected service is enabled for transformation
 	ConfigServicesNamesKey = ConfigServicesKey + d + Special + d + "enable"
+	//ConfigServicesConfigFilesTypeKey represents the key for how a configuration file of a service is provided to the containers
+	ConfigServicesConfigFilesTypeKey = ConfigServicesKey + d + "%s" + d + "configfiles" + d + "%s" + d + "type"
+	//ConfigServicesConfigFilesMountPathKey represents the key for the path a configuration file of a service is mounted at
+	ConfigServicesConfigFilesMountPathKey = ConfigServicesKey + d + "%s" + d + "configfiles" + d + "%s" + d + "mountpath"
+	//ConfigServicesMigrationKey represents the key for how the database migrations of a service are run
+	ConfigServicesMigrationKey = ConfigServicesKey + d + "%s" + d + "migration"
 	//ConfigContainerizationTypesKey represents source type Key
 	ConfigContainerizationTypesKey = ConfigContainerizationKeySegment + d + "types"
+	//ConfigServicesNewNameKey represents the key for the new name of a service whose name collides with another service
+	ConfigServicesNewNameKey = ConfigServicesKey + d + "%s" + d + "newname"
+	//ConfigServicesNewURLPathKey represents the key for the new ingress path of a service port whose path collides with another service port
+	ConfigServicesNewURLPathKey = ConfigServicesKey + d + "%s" + d + "%s" + d + "newurlpath"
+	//ConfigImagesDockerfileKey represents the key for the Dockerfile to use when different Dockerfiles build the same image
+	ConfigImagesDockerfileKey = BaseKey + d + "images" + d + "%s" + d + "dockerfile"
 	//ConfigServicesExposeKey represents Services Expose Key
 	ConfigServicesExposeKey = ConfigServicesKey + d + Special + d + "expose"
 	// ConfigActiveMavenProfilesForServiceKeySegment represents the maven profiles used for service
@@ -17,6 +17,7 @@
 package common
 
 import (
+	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
@@ -30,35 +31,75 @@ import (
 	"golang.org/x/crypto/pbkdf2"
 )
 
+// openSSLSaltedPrefix is the prefix of the data encrypted by OpenSSL with a password and salt
+const openSSLSaltedPrefix = "Salted__"
+
 // EncryptRsaCertWrapper can be used to encrypt the data using RSA PKCS1v15 algorithm with certificate as key
 func EncryptRsaCertWrapper(certificate string, data string) string {
+	out, err := EncryptRsaCert(certificate, []byte(data))
+	if err != nil {
+		logrus.Errorf("failed to encrypt the data with RSA PKCS1v15 algorithm with certificate as key : %q", err)
+	}
+	return string(out)
+}
 
-	rsaPublicKey, err := getRSAPublicKeyFromCertificate([]byte(certificate))
+// EncryptAesCbcWithPbkdfWrapper can be used to encrypt the data using AES 256 CBC mode with Pbkdf key derivation
+func EncryptAesCbcWithPbkdfWrapper(key string, data string) string {
+	out, err := EncryptAesCbcWithPbkdf(key, []byte(data))
 	if err != nil {
-		logrus.Errorf("failed to parse the certificate and get the RSA public key from it. Error: %q", err)
+		logrus.Errorf("failed to encrypt the data using AES 256 CBC mode with Pbkdf key derivation : %q", err)
 	}
+	return string(out)
+}
 
-	out, err := rsaEncrypt(rsaPublicKey, []byte(data))
+// EncryptRsaCert encrypts the data using RSA PKCS1v15 algorithm with the public key in the PEM encoded certificate
+func EncryptRsaCert(certificate string, data []byte) ([]byte, error) {
+	rsaPublicKey, err := getRSAPublicKeyFromCertificate([]byte(certificate))
 	if err != nil {
-		logrus.Errorf("failed to encrypt the data with RSA PKCS1v15 algorithm with certificate as key : %q", err)
+		return nil, fmt.Errorf("failed to parse the certificate and get the RSA public key from it. Error: %w", err)
 	}
+	return rsaEncrypt(rsaPublicKey, data)
+}
 
-	return string(out)
+// DecryptRsaPrivateKey decrypts the data encrypted using RSA PKCS1v15 algorithm with the PEM encoded RSA private key
+func DecryptRsaPrivateKey(privateKey string, data []byte) ([]byte, error) {
+	rsaPrivateKey, err := getRSAPrivateKey([]byte(privateKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the RSA private key. Error: %w", err)
+	}
+	plainText, err := rsa.DecryptPKCS1v15(rand.Reader, rsaPrivateKey, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to RSA decrypt the cipher text. Error: %w", err)
+	}
+	return plainText, nil
 }
 
-// EncryptAesCbcWithPbkdfWrapper can be used to encrypt the data using AES 256 CBC mode with Pbkdf key derivation
-func EncryptAesCbcWithPbkdfWrapper(key string, data string) string {
+// EncryptAesCbcWithPbkdf encrypts the data using AES 256 CBC mode with Pbkdf key derivation.
+// The output is in the OpenSSL salted format with a 16 byte salt.
+func EncryptAesCbcWithPbkdf(key string, data []byte) ([]byte, error) {
 	salt := make([]byte, 16)
-	_, err := rand.Read(salt)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to prepare the salt. Error: %w", err)
+	}
+	out, err := aesCbcEncryptWithPbkdf([]byte(key), salt, data)
 	if err != nil {
-		logrus.Errorf("failed to prepare the salt : %s", err)
+		return nil, err
 	}
-	out, err := aesCbcEncryptWithPbkdf([]byte(key), salt, []byte(data))
+	return toOpenSSLFormat(salt, out), nil
+}
+
+// DecryptAesCbcWithPbkdf decrypts the data in the OpenSSL salted format encrypted using AES 256 CBC mode with Pbkdf key derivation
+func DecryptAesCbcWithPbkdf(key string, data []byte) ([]byte, error) {
+	salt, cipherText, err := fromOpenSSLFormat(data)
 	if err != nil {
-		logrus.Errorf("failed to encrypt the data using AES 256 CBC mode with Pbkdf key derivation : %q", err)
+		return nil, err
 	}
-	opensslOut := toOpenSSLFormat(salt, out)
-	return string(opensslOut)
+	return aesCbcDecryptWithPbkdf([]byte(key), salt, cipherText)
+}
+
+// IsOpenSSLSalted returns true if the data is in the OpenSSL salted format
+func IsOpenSSLSalted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(openSSLSaltedPrefix))
 }
 
 // getRSAPublicKeyFromCertificate gets a RSA public key from a PEM-encoded certificate.crt file.
@@ -78,6 +119,26 @@ func getRSAPublicKeyFromCertificate(certificateInPemFormat []byte) (*rsa.PublicK
 	return pubKey, nil
 }
 
+// getRSAPrivateKey gets a RSA private key from a PEM-encoded PKCS1 or PKCS8 private key.
+func getRSAPrivateKey(privateKeyInPemFormat []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(privateKeyInPemFormat)
+	if block == nil {
+		return nil, fmt.Errorf("invalid private key. Expected a PEM encoded private key")
+	}
+	if privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return privateKey, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the private key as PKCS1 or PKCS8. Error: %q", err)
+	}
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("the private key is not a RSA private key. Actual type: %T", key)
+	}
+	return privateKey, nil
+}
+
 // rsaEncrypt encrypts the plain text using the RSA public key.
 func rsaEncrypt(publicKey *rsa.PublicKey, plainText []byte) ([]byte, error) {
 	cipherText, err := rsa.EncryptPKCS1v15(rand.Reader, publicKey, plainText)
@@ -126,8 +187,45 @@ func aesCbcEncryptWithPbkdf(password, salt, plainText []byte) ([]byte, error) {
 	return cipherText, nil
 }
 
+// aesCbcDecryptWithPbkdf derives an AES key and IV using the given password and salt and then decrypts the cipher text.
+func aesCbcDecryptWithPbkdf(password, salt, cipherText []byte) ([]byte, error) {
+	if len(cipherText) == 0 || len(cipherText)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("the cipher text length %d is not a multiple of the AES block size", len(cipherText))
+	}
+	aesKey, iv := deriveAesKeyAndIv(password, salt)
+	aesCipher, err := aes.NewCipher(aesKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a new AES cipher using the key. Error: %q", err)
+	}
+	paddedPlainText := make([]byte, len(cipherText))
+	aesCbcDecrypter := cipher.NewCBCDecrypter(aesCipher, iv)
+	aesCbcDecrypter.CryptBlocks(paddedPlainText, cipherText)
+
+	// remove the PKCS#5 padding, invalid padding usually means the password is wrong
+
+	paddingLength := int(paddedPlainText[len(paddedPlainText)-1])
+	if paddingLength == 0 || paddingLength > aes.BlockSize {
+		return nil, fmt.Errorf("invalid padding. The password might be incorrect")
+	}
+	for _, b := range paddedPlainText[len(paddedPlainText)-paddingLength:] {
+		if int(b) != paddingLength {
+			return nil, fmt.Errorf("invalid padding. The password might be incorrect")
+		}
+	}
+	return paddedPlainText[:len(paddedPlainText)-paddingLength], nil
+}
+
 // toOpenSSLFormat converts the cipher text to OpenSSL encrypted with password and salt format.
 // http://justsolve.archiveteam.org/wiki/OpenSSL_salted_format
 func toOpenSSLFormat(salt, cipherText []byte) []byte {
-	return append(append([]byte("Salted__"), salt...), cipherText...)
+	return append(append([]byte(openSSLSaltedPrefix), salt...), cipherText...)
+}
+
+// fromOpenSSLFormat splits the data in the OpenSSL salted format into the salt and the cipher text.
+func fromOpenSSLFormat(data []byte) ([]byte, []byte, error) {
+	if !IsOpenSSLSalted(data) || len(data) < len(openSSLSaltedPrefix)+16 {
+		return nil, nil, fmt.Errorf("the data is not in the OpenSSL salted format")
+	}
+	saltEnd := len(openSSLSaltedPrefix) + 16
+	return data[len(openSSLSaltedPrefix):saltEnd], data[saltEnd:], nil
 }
@@ -0,0 +1,80 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+)
+
+func TestAesCbcWithPbkdf(t *testing.T) {
+	data := []byte("registry: quay.io/myorg\ntoken: abc\n")
+	encrypted, err := common.EncryptAesCbcWithPbkdf("passphrase", data)
+	if err != nil {
+		t.Fatalf("failed to encrypt the data. Error: %q", err)
+	}
+	if !common.IsOpenSSLSalted(encrypted) {
+		t.Fatalf("expected the encrypted data to be in the OpenSSL salted format")
+	}
+	decrypted, err := common.DecryptAesCbcWithPbkdf("passphrase", encrypted)
+	if err != nil {
+		t.Fatalf("failed to decrypt the data. Error: %q", err)
+	}
+	if string(decrypted) != string(data) {
+		t.Fatalf("expected the decrypted data to be %q. Actual: %q", data, decrypted)
+	}
+	if decrypted, err := common.DecryptAesCbcWithPbkdf("wrong", encrypted); err == nil && string(decrypted) == string(data) {
+		t.Fatalf("expected the decryption with the wrong passphrase to fail")
+	}
+}
+
+func TestRsaCert(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate the private key. Error: %q", err)
+	}
+	template := x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "move2kube"}, NotAfter: time.Now().Add(time.Hour)}
+	certDER, err := x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatalf("failed to create the certificate. Error: %q", err)
+	}
+	certificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}))
+	privateKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}))
+
+	encrypted, err := common.EncryptRsaCert(certificate, []byte("passphrase"))
+	if err != nil {
+		t.Fatalf("failed to encrypt the data. Error: %q", err)
+	}
+	decrypted, err := common.DecryptRsaPrivateKey(privateKeyPEM, encrypted)
+	if err != nil {
+		t.Fatalf("failed to decrypt the data. Error: %q", err)
+	}
+	if string(decrypted) != "passphrase" {
+		t.Fatalf("expected the decrypted data to be passphrase. Actual: %q", decrypted)
+	}
+	if _, err := common.EncryptRsaCert("not a certificate", []byte("passphrase")); err == nil {
+		t.Fatalf("expected an error for the invalid certificate")
+	}
+}
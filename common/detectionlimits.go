@@ -0,0 +1,100 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var (
+	maxDetectDepth       int
+	excludeDirPatterns   []string
+	detectionLimitsMutex sync.Mutex
+)
+
+// SetDetectionLimits sets the depth below the source root after which directories are not searched for services and the
+// glob patterns of the directories that are skipped during detection. A zero depth disables that limit.
+// Patterns without a / (like node_modules) are matched against the directory name at any depth, while patterns
+// with a / (like third_party/*) are matched against the directory path relative to the source root.
+func SetDetectionLimits(maxDepth int, excludeDirs []string) error {
+	if maxDepth < 0 {
+		return fmt.Errorf("the maximum detection depth %d cannot be negative", maxDepth)
+	}
+	patterns := []string{}
+	for _, pattern := range excludeDirs {
+		pattern = strings.TrimSuffix(filepath.ToSlash(strings.TrimSpace(pattern)), "/")
+		if pattern == "" {
+			continue
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("failed to parse the excluded directory pattern %s . Error: %w", pattern, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	detectionLimitsMutex.Lock()
+	defer detectionLimitsMutex.Unlock()
+	maxDetectDepth = maxDepth
+	excludeDirPatterns = patterns
+	return nil
+}
+
+// GetDetectionLimits returns the maximum detection depth and the glob patterns of the directories excluded from detection
+func GetDetectionLimits() (maxDepth int, excludeDirs []string) {
+	detectionLimitsMutex.Lock()
+	defer detectionLimitsMutex.Unlock()
+	return maxDetectDepth, append([]string{}, excludeDirPatterns...)
+}
+
+// GetExcludedDirPattern returns the first excluded directory pattern that matches the directory below the root, or an empty string
+func GetExcludedDirPattern(root, path string) string {
+	_, patterns := GetDetectionLimits()
+	if len(patterns) == 0 {
+		return ""
+	}
+	relPath, err := filepath.Rel(root, path)
+	if err != nil || relPath == "." {
+		return ""
+	}
+	relPath = filepath.ToSlash(relPath)
+	name := filepath.Base(path)
+	for _, pattern := range patterns {
+		target := name
+		if strings.Contains(pattern, "/") {
+			target = relPath
+		}
+		if matched, _ := filepath.Match(pattern, target); matched {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// IsOverDetectDepth returns true if the directory is deeper below the root than the maximum detection depth
+func IsOverDetectDepth(root, path string) bool {
+	maxDepth, _ := GetDetectionLimits()
+	if maxDepth == 0 {
+		return false
+	}
+	relPath, err := filepath.Rel(root, path)
+	if err != nil || relPath == "." {
+		return false
+	}
+	return len(strings.Split(filepath.ToSlash(relPath), "/")) > maxDepth
+}
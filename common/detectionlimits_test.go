@@ -0,0 +1,64 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectionLimits(t *testing.T) {
+	root := filepath.Join("src", "repo")
+	testcases := []struct {
+		name        string
+		maxDepth    int
+		excludeDirs []string
+		path        string
+		wantPattern string
+		wantOver    bool
+	}{
+		{name: "no limits", path: "a/b/c/d"},
+		{name: "root is never skipped", maxDepth: 1, excludeDirs: []string{"*"}, path: "."},
+		{name: "within depth", maxDepth: 2, path: "a/b"},
+		{name: "over depth", maxDepth: 2, path: "a/b/c", wantOver: true},
+		{name: "name pattern at any depth", excludeDirs: []string{"vendor"}, path: "a/b/vendor", wantPattern: "vendor"},
+		{name: "name pattern with wildcard", excludeDirs: []string{"node_*"}, path: "web/node_modules", wantPattern: "node_*"},
+		{name: "path pattern", excludeDirs: []string{"third_party/*/"}, path: "third_party/lib", wantPattern: "third_party/*"},
+		{name: "path pattern is relative to the root", excludeDirs: []string{"third_party/*"}, path: "a/third_party/lib"},
+	}
+	defer SetDetectionLimits(0, nil)
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := SetDetectionLimits(tc.maxDepth, tc.excludeDirs); err != nil {
+				t.Fatalf("failed to set the detection limits. Error: %q", err)
+			}
+			path := filepath.Join(root, filepath.FromSlash(tc.path))
+			if got := GetExcludedDirPattern(root, path); got != tc.wantPattern {
+				t.Fatalf("expected the excluded pattern of %s to be %q. Actual: %q", tc.path, tc.wantPattern, got)
+			}
+			if got := IsOverDetectDepth(root, path); got != tc.wantOver {
+				t.Fatalf("expected IsOverDetectDepth for %s to be %t. Actual: %t", tc.path, tc.wantOver, got)
+			}
+		})
+	}
+	if err := SetDetectionLimits(-1, nil); err == nil {
+		t.Fatalf("expected an error for a negative depth")
+	}
+	if err := SetDetectionLimits(0, []string{"[a-"}); err == nil {
+		t.Fatalf("expected an error for an invalid pattern")
+	}
+}
@@ -0,0 +1,190 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package eventbus
+
+import (
+	"sync"
+	"time"
+
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/sirupsen/logrus"
+)
+
+// EventTypeT is the type of an event
+type EventTypeT string
+
+const (
+	// QuestionRaised is published when a question needs to be answered
+	QuestionRaised EventTypeT = "question.raised"
+	// AnswerReceived is published when a question has been answered
+	AnswerReceived EventTypeT = "answer.received"
+	// TransformerStarted is published when a transformer starts transforming artifacts
+	TransformerStarted EventTypeT = "transformer.started"
+	// TransformerFinished is published when a transformer finishes transforming artifacts
+	TransformerFinished EventTypeT = "transformer.finished"
+	// PathMappingApplied is published when a path mapping has been applied to a directory
+	PathMappingApplied EventTypeT = "pathmapping.applied"
+	// WarningEmitted is published when a warning or an error is logged
+	WarningEmitted EventTypeT = "warning.emitted"
+)
+
+// Event is implemented by all the events published on the bus
+type Event interface {
+	// GetType returns the type of the event
+	GetType() EventTypeT
+}
+
+// QuestionRaisedEvent contains the question that needs to be answered
+type QuestionRaisedEvent struct {
+	Problem qatypes.Problem
+}
+
+// AnswerReceivedEvent contains the question along with its answer
+type AnswerReceivedEvent struct {
+	Problem qatypes.Problem
+}
+
+// TransformerStartedEvent contains the transformer that started and the number of artifacts it was given
+type TransformerStartedEvent struct {
+	Name      string
+	Class     string
+	Iteration int
+	Artifacts int
+}
+
+// TransformerFinishedEvent contains the transformer that finished and what it produced
+type TransformerFinishedEvent struct {
+	Name         string
+	Class        string
+	Iteration    int
+	PathMappings int
+	Artifacts    int
+	Duration     time.Duration
+	// Error is empty if the transformer succeeded
+	Error string
+}
+
+// PathMappingAppliedEvent contains the path mapping and the directories it was applied to
+type PathMappingAppliedEvent struct {
+	PathMapping transformertypes.PathMapping
+	SourcePath  string
+	OutputPath  string
+}
+
+// WarningEmittedEvent contains a warning or an error that was logged
+type WarningEmittedEvent struct {
+	Level     logrus.Level
+	Message   string
+	Timestamp time.Time
+}
+
+// GetType returns the type of the event
+func (QuestionRaisedEvent) GetType() EventTypeT { return QuestionRaised }
+
+// GetType returns the type of the event
+func (AnswerReceivedEvent) GetType() EventTypeT { return AnswerReceived }
+
+// GetType returns the type of the event
+func (TransformerStartedEvent) GetType() EventTypeT { return TransformerStarted }
+
+// GetType returns the type of the event
+func (TransformerFinishedEvent) GetType() EventTypeT { return TransformerFinished }
+
+// GetType returns the type of the event
+func (PathMappingAppliedEvent) GetType() EventTypeT { return PathMappingApplied }
+
+// GetType returns the type of the event
+func (WarningEmittedEvent) GetType() EventTypeT { return WarningEmitted }
+
+type subscription struct {
+	id      int
+	handler func(Event)
+}
+
+var (
+	subscriptions      = []subscription{}
+	subscriptionsMutex sync.RWMutex
+	nextSubscriptionID = 0
+	warningHookOnce    sync.Once
+)
+
+// Subscribe registers a handler that is called for every event and returns a function to unsubscribe.
+// The handlers are called synchronously in the order they subscribed, so they should return quickly
+// and should not log warnings themselves since that publishes another event.
+func Subscribe(handler func(Event)) (unsubscribe func()) {
+	warningHookOnce.Do(func() { logrus.AddHook(&warningHook{}) })
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+	id := nextSubscriptionID
+	nextSubscriptionID++
+	subscriptions = append(subscriptions, subscription{id: id, handler: handler})
+	return func() {
+		subscriptionsMutex.Lock()
+		defer subscriptionsMutex.Unlock()
+		for i, s := range subscriptions {
+			if s.id == id {
+				subscriptions = append(subscriptions[:i:i], subscriptions[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// SubscribeTo registers a handler that is called for every event of the type E and returns a function to unsubscribe
+func SubscribeTo[E Event](handler func(E)) (unsubscribe func()) {
+	return Subscribe(func(event Event) {
+		if typedEvent, ok := event.(E); ok {
+			handler(typedEvent)
+		}
+	})
+}
+
+// Publish calls all the subscribed handlers with the event
+func Publish(event Event) {
+	subscriptionsMutex.RLock()
+	currentSubscriptions := append([]subscription{}, subscriptions...)
+	subscriptionsMutex.RUnlock()
+	for _, s := range currentSubscriptions {
+		s.handler(event)
+	}
+}
+
+// HasSubscribers returns true if at least one handler is subscribed.
+// It can be used to avoid building events that nobody receives.
+func HasSubscribers() bool {
+	subscriptionsMutex.RLock()
+	defer subscriptionsMutex.RUnlock()
+	return len(subscriptions) > 0
+}
+
+// warningHook publishes the warnings and errors that are logged
+type warningHook struct{}
+
+// Fire publishes the log entry as a warning event
+func (*warningHook) Fire(entry *logrus.Entry) error {
+	Publish(WarningEmittedEvent{Level: entry.Level, Message: entry.Message, Timestamp: entry.Time})
+	return nil
+}
+
+// Levels returns the levels on which the warning hook gets called
+func (*warningHook) Levels() []logrus.Level {
+	return []logrus.Level{
+		logrus.ErrorLevel,
+		logrus.WarnLevel,
+	}
+}
@@ -0,0 +1,64 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package eventbus
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestSubscribe(t *testing.T) {
+	t.Run("handlers receive the events until they unsubscribe", func(t *testing.T) {
+		events := []Event{}
+		unsubscribe := Subscribe(func(event Event) { events = append(events, event) })
+		Publish(TransformerStartedEvent{Name: "t1", Artifacts: 2})
+		Publish(TransformerFinishedEvent{Name: "t1", PathMappings: 3})
+		unsubscribe()
+		Publish(TransformerStartedEvent{Name: "t2"})
+		want := []Event{TransformerStartedEvent{Name: "t1", Artifacts: 2}, TransformerFinishedEvent{Name: "t1", PathMappings: 3}}
+		if !cmp.Equal(events, want) {
+			t.Fatalf("failed to receive the events. Difference:\n%s", cmp.Diff(want, events))
+		}
+		if HasSubscribers() {
+			t.Fatalf("expected no subscribers after unsubscribing")
+		}
+	})
+
+	t.Run("typed handlers only receive the events of their type", func(t *testing.T) {
+		names := []string{}
+		unsubscribe := SubscribeTo(func(event TransformerFinishedEvent) { names = append(names, event.Name) })
+		defer unsubscribe()
+		Publish(TransformerStartedEvent{Name: "t1"})
+		Publish(TransformerFinishedEvent{Name: "t1"})
+		if want := []string{"t1"}; !cmp.Equal(names, want) {
+			t.Fatalf("failed to receive the typed events. Difference:\n%s", cmp.Diff(want, names))
+		}
+	})
+
+	t.Run("warnings that are logged are published", func(t *testing.T) {
+		messages := []string{}
+		unsubscribe := SubscribeTo(func(event WarningEmittedEvent) { messages = append(messages, event.Message) })
+		defer unsubscribe()
+		logrus.Warnf("the file %s is missing", "a.yaml")
+		logrus.Infof("not a warning")
+		if want := []string{"the file a.yaml is missing"}; !cmp.Equal(messages, want) {
+			t.Fatalf("failed to receive the warnings. Difference:\n%s", cmp.Diff(want, messages))
+		}
+	})
+}
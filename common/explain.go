@@ -0,0 +1,62 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	explainDetection      bool
+	detectionReasons      []string
+	detectionReasonsMutex sync.Mutex
+)
+
+// SetExplainDetection enables or disables the recording of the reasons why the transformers did or did not detect services
+func SetExplainDetection(enabled bool) {
+	detectionReasonsMutex.Lock()
+	defer detectionReasonsMutex.Unlock()
+	explainDetection = enabled
+	detectionReasons = nil
+}
+
+// IsExplainDetectionEnabled returns true if the reasons for the detection decisions are being recorded
+func IsExplainDetectionEnabled() bool {
+	detectionReasonsMutex.Lock()
+	defer detectionReasonsMutex.Unlock()
+	return explainDetection
+}
+
+// AddDetectionReason records why the transformer that is currently looking for services in a directory did or did not detect a service
+func AddDetectionReason(format string, args ...interface{}) {
+	detectionReasonsMutex.Lock()
+	defer detectionReasonsMutex.Unlock()
+	if !explainDetection {
+		return
+	}
+	detectionReasons = append(detectionReasons, fmt.Sprintf(format, args...))
+}
+
+// TakeDetectionReasons returns the reasons recorded since the last call and clears them
+func TakeDetectionReasons() []string {
+	detectionReasonsMutex.Lock()
+	defer detectionReasonsMutex.Unlock()
+	reasons := detectionReasons
+	detectionReasons = nil
+	return reasons
+}
@@ -0,0 +1,46 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDetectionReasons(t *testing.T) {
+	t.Run("reasons are ignored when the explanation is disabled", func(t *testing.T) {
+		SetExplainDetection(false)
+		AddDetectionReason("no %s file was found", "go.mod")
+		if reasons := TakeDetectionReasons(); len(reasons) != 0 {
+			t.Fatalf("expected no reasons. Actual: %+v", reasons)
+		}
+	})
+	t.Run("reasons are recorded and cleared when the explanation is enabled", func(t *testing.T) {
+		SetExplainDetection(true)
+		defer SetExplainDetection(false)
+		AddDetectionReason("no %s file was found", "go.mod")
+		AddDetectionReason("no Gemfile was found")
+		want := []string{"no go.mod file was found", "no Gemfile was found"}
+		if reasons := TakeDetectionReasons(); !cmp.Equal(reasons, want) {
+			t.Fatalf("failed to record the reasons. Difference:\n%s", cmp.Diff(want, reasons))
+		}
+		if reasons := TakeDetectionReasons(); len(reasons) != 0 {
+			t.Fatalf("expected the reasons to be cleared. Actual: %+v", reasons)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// FidelityStatusT is how well a field in the source survived the translation
+type FidelityStatusT string
+
+const (
+	// FidelityDropped means the field was ignored and has no equivalent in the output
+	FidelityDropped FidelityStatusT = "dropped"
+	// FidelityApproximated means the field was translated to the closest equivalent, which may behave differently
+	FidelityApproximated FidelityStatusT = "approximated"
+	// FidelityManualAttention means the field was translated, but the output must be reviewed before it is used
+	FidelityManualAttention FidelityStatusT = "manual-attention"
+)
+
+// FidelityItemT is a single source field that did not survive the translation as is
+type FidelityItemT struct {
+	Source  string          `yaml:"source" json:"source"`
+	Service string          `yaml:"service,omitempty" json:"service,omitempty"`
+	Field   string          `yaml:"field" json:"field"`
+	Status  FidelityStatusT `yaml:"status" json:"status"`
+	Message string          `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+// FidelityReportT lists the source fields that were dropped, approximated or require manual attention
+type FidelityReportT struct {
+	Items []FidelityItemT `yaml:"items" json:"items"`
+}
+
+var (
+	fidelityItems = map[FidelityItemT]bool{}
+	fidelityMutex sync.Mutex
+)
+
+// AddFidelityItem records a source field that did not survive the translation as is
+func AddFidelityItem(source, service, field string, status FidelityStatusT, message string) {
+	fidelityMutex.Lock()
+	defer fidelityMutex.Unlock()
+	fidelityItems[FidelityItemT{Source: source, Service: service, Field: field, Status: status, Message: message}] = true
+}
+
+// GetFidelityReport returns the recorded items sorted by source, service and field.
+// Sources inside the source directory are made relative to it.
+func GetFidelityReport(sourceDir string) FidelityReportT {
+	fidelityMutex.Lock()
+	defer fidelityMutex.Unlock()
+	report := FidelityReportT{Items: []FidelityItemT{}}
+	for item := range fidelityItems {
+		if sourceDir != "" && IsParent(item.Source, sourceDir) {
+			if relPath, err := filepath.Rel(sourceDir, item.Source); err == nil {
+				item.Source = relPath
+			}
+		}
+		report.Items = append(report.Items, item)
+	}
+	sort.Slice(report.Items, func(i, j int) bool {
+		a, b := report.Items[i], report.Items[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		return a.Message < b.Message
+	})
+	return report
+}
@@ -0,0 +1,79 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetFidelityReport(t *testing.T) {
+	defer func() { fidelityItems = map[FidelityItemT]bool{} }()
+	sourceDir := t.TempDir()
+	composePath := filepath.Join(sourceDir, "app", "docker-compose.yaml")
+	outsidePath := filepath.Join(t.TempDir(), "docker-compose.yaml")
+	testCases := []struct {
+		name      string
+		sourceDir string
+		want      []FidelityItemT
+	}{
+		{
+			name:      "sources inside the source directory are relative",
+			sourceDir: sourceDir,
+			want: []FidelityItemT{
+				{Source: outsidePath, Service: "web", Field: "privileged", Status: FidelityManualAttention, Message: "privileged"},
+				{Source: filepath.Join("app", "docker-compose.yaml"), Service: "api", Field: "dns", Status: FidelityDropped, Message: "dropped"},
+				{Source: filepath.Join("app", "docker-compose.yaml"), Service: "web", Field: "restart", Status: FidelityApproximated, Message: "always"},
+				{Source: filepath.Join("app", "docker-compose.yaml"), Service: "web", Field: "restart", Status: FidelityApproximated, Message: "unless-stopped"},
+			},
+		},
+		{
+			name: "sources are unchanged without a source directory",
+			want: []FidelityItemT{
+				{Source: composePath, Service: "api", Field: "dns", Status: FidelityDropped, Message: "dropped"},
+				{Source: composePath, Service: "web", Field: "restart", Status: FidelityApproximated, Message: "always"},
+				{Source: composePath, Service: "web", Field: "restart", Status: FidelityApproximated, Message: "unless-stopped"},
+				{Source: outsidePath, Service: "web", Field: "privileged", Status: FidelityManualAttention, Message: "privileged"},
+			},
+		},
+	}
+	fidelityItems = map[FidelityItemT]bool{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 2; i++ {
+		// the same item is recorded once even if it is added by several goroutines
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			AddFidelityItem(composePath, "web", "restart", FidelityApproximated, "unless-stopped")
+			AddFidelityItem(composePath, "web", "restart", FidelityApproximated, "always")
+			AddFidelityItem(composePath, "api", "dns", FidelityDropped, "dropped")
+			AddFidelityItem(outsidePath, "web", "privileged", FidelityManualAttention, "privileged")
+		}()
+	}
+	wg.Wait()
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := GetFidelityReport(testCase.sourceDir)
+			if !cmp.Equal(got.Items, testCase.want) {
+				t.Fatalf("the fidelity report is incorrect. Differences:\n%s", cmp.Diff(testCase.want, got.Items))
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// GitCommit is a commit in the history of a git repo
+type GitCommit struct {
+	Hash    string
+	Author  string
+	Email   string
+	Time    time.Time
+	Message string
+}
+
+// GetGitBranch returns the branch checked out in the git repo containing the path, or an empty string if the HEAD is detached
+func GetGitBranch(path string) (string, error) {
+	repo, err := openGitRepo(path)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the HEAD of the git repo at path %s . Error: %w", path, err)
+	}
+	if !head.Name().IsBranch() {
+		return "", nil
+	}
+	return head.Name().Short(), nil
+}
+
+// GetGitRemotes returns the urls of each remote of the git repo containing the path
+func GetGitRemotes(path string) (map[string][]string, error) {
+	repo, err := openGitRepo(path)
+	if err != nil {
+		return nil, err
+	}
+	remotes, err := repo.Remotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the remotes of the git repo at path %s . Error: %w", path, err)
+	}
+	remoteURLs := map[string][]string{}
+	for _, remote := range remotes {
+		remoteURLs[remote.Config().Name] = remote.Config().URLs
+	}
+	return remoteURLs, nil
+}
+
+// GetGitChangedFiles returns the paths, relative to the repo root, of the files changed in the commits from the ref to the HEAD
+func GetGitChangedFiles(path, ref string) ([]string, error) {
+	repo, err := openGitRepo(path)
+	if err != nil {
+		return nil, err
+	}
+	fromTree, err := getGitTree(repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	toTree, err := getGitTree(repo, string(plumbing.HEAD))
+	if err != nil {
+		return nil, err
+	}
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the changes from %s to HEAD in the git repo at path %s . Error: %w", ref, path, err)
+	}
+	changedFiles := []string{}
+	for _, change := range changes {
+		for _, name := range []string{change.From.Name, change.To.Name} {
+			if name != "" && !IsPresent(changedFiles, name) {
+				changedFiles = append(changedFiles, name)
+			}
+		}
+	}
+	sort.Strings(changedFiles)
+	return changedFiles, nil
+}
+
+// GetGitFileHistory returns the commits that changed the file, latest first. A max count of 0 returns all of them.
+func GetGitFileHistory(filePath string, maxCount int) ([]GitCommit, error) {
+	repo, err := openGitRepo(filePath)
+	if err != nil {
+		return nil, err
+	}
+	workTree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the work tree of the git repo containing the path %s . Error: %w", filePath, err)
+	}
+	absFilePath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make the path %s absolute. Error: %w", filePath, err)
+	}
+	relFilePath, err := filepath.Rel(workTree.Filesystem.Root(), absFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make the path %s relative to the git repo root. Error: %w", filePath, err)
+	}
+	relFilePath = filepath.ToSlash(relFilePath)
+	commits, err := repo.Log(&git.LogOptions{FileName: &relFilePath})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the history of the file %s . Error: %w", filePath, err)
+	}
+	defer commits.Close()
+	history := []GitCommit{}
+	for commit, err := commits.Next(); err == nil; commit, err = commits.Next() {
+		history = append(history, GitCommit{
+			Hash:    commit.Hash.String(),
+			Author:  commit.Author.Name,
+			Email:   commit.Author.Email,
+			Time:    commit.Author.When,
+			Message: commit.Message,
+		})
+		if maxCount > 0 && len(history) >= maxCount {
+			break
+		}
+	}
+	return history, nil
+}
+
+func openGitRepo(path string) (*git.Repository, error) {
+	if finfo, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to stat the path %s . Error: %w", path, err)
+	} else if !finfo.IsDir() {
+		path = filepath.Dir(path)
+	}
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the path %s as a git repo. Error: %w", path, err)
+	}
+	return repo, nil
+}
+
+func getGitTree(repo *git.Repository, ref string) (*object.Tree, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the git ref %s . Error: %w", ref, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the commit %s . Error: %w", hash, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the tree of the commit %s . Error: %w", hash, err)
+	}
+	return tree, nil
+}
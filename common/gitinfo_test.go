@@ -0,0 +1,173 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGitInfo(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("failed to create the git repo. Error: %q", err)
+	}
+	if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"https://github.com/konveyor/move2kube.git"}}); err != nil {
+		t.Fatalf("failed to create the remote. Error: %q", err)
+	}
+	workTree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get the work tree. Error: %q", err)
+	}
+	commit := func(message string, files map[string]string) string {
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), DefaultDirectoryPermission); err != nil {
+				t.Fatalf("failed to create the directory for %s . Error: %q", path, err)
+			}
+			if err := os.WriteFile(path, []byte(content), DefaultFilePermission); err != nil {
+				t.Fatalf("failed to write the file %s . Error: %q", path, err)
+			}
+			if _, err := workTree.Add(name); err != nil {
+				t.Fatalf("failed to add the file %s . Error: %q", name, err)
+			}
+		}
+		hash, err := workTree.Commit(message, &git.CommitOptions{Author: &object.Signature{Name: "dev", Email: "dev@example.com", When: time.Now()}})
+		if err != nil {
+			t.Fatalf("failed to commit. Error: %q", err)
+		}
+		return hash.String()
+	}
+	first := commit("initial\n", map[string]string{"app/main.go": "package main\n", "README.md": "# app\n"})
+	second := commit("update main\n", map[string]string{"app/main.go": "package main\n\nfunc main() {}\n", "Dockerfile": "FROM scratch\n"})
+
+	branch, err := GetGitBranch(dir)
+	if err != nil {
+		t.Fatalf("failed to get the branch. Error: %q", err)
+	}
+	if branch != "master" {
+		t.Fatalf("expected the branch master . Actual: %s", branch)
+	}
+	remotes, err := GetGitRemotes(filepath.Join(dir, "app"))
+	if err != nil {
+		t.Fatalf("failed to get the remotes. Error: %q", err)
+	}
+	if diff := cmp.Diff(map[string][]string{"origin": {"https://github.com/konveyor/move2kube.git"}}, remotes); diff != "" {
+		t.Fatalf("unexpected remotes. Difference:\n%s", diff)
+	}
+	changedFiles, err := GetGitChangedFiles(dir, first)
+	if err != nil {
+		t.Fatalf("failed to get the changed files. Error: %q", err)
+	}
+	if diff := cmp.Diff([]string{"Dockerfile", "app/main.go"}, changedFiles); diff != "" {
+		t.Fatalf("unexpected changed files. Difference:\n%s", diff)
+	}
+	history, err := GetGitFileHistory(filepath.Join(dir, "app", "main.go"), 0)
+	if err != nil {
+		t.Fatalf("failed to get the file history. Error: %q", err)
+	}
+	hashes := []string{}
+	for _, c := range history {
+		hashes = append(hashes, c.Hash)
+	}
+	if diff := cmp.Diff([]string{second, first}, hashes); diff != "" {
+		t.Fatalf("unexpected file history. Difference:\n%s", diff)
+	}
+	if history, err = GetGitFileHistory(filepath.Join(dir, "README.md"), 1); err != nil || len(history) != 1 || history[0].Hash != first {
+		t.Fatalf("expected only the initial commit in the history of the README. Actual: %+v Error: %v", history, err)
+	}
+}
+
+func TestGatherGitRevisionInfo(t *testing.T) {
+	if _, _, err := GatherGitRevisionInfo(t.TempDir()); err == nil {
+		t.Fatalf("expected an error for a directory that is not in a git repo")
+	}
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("failed to create the git repo. Error: %q", err)
+	}
+	workTree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get the work tree. Error: %q", err)
+	}
+	signature := &object.Signature{Name: "dev", Email: "dev@example.com", When: time.Now()}
+	commit := func(content string) plumbing.Hash {
+		if err := os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte(content), DefaultFilePermission); err != nil {
+			t.Fatalf("failed to write the Dockerfile. Error: %q", err)
+		}
+		if _, err := workTree.Add("Dockerfile"); err != nil {
+			t.Fatalf("failed to add the Dockerfile. Error: %q", err)
+		}
+		hash, err := workTree.Commit("update\n", &git.CommitOptions{Author: signature})
+		if err != nil {
+			t.Fatalf("failed to commit. Error: %q", err)
+		}
+		return hash
+	}
+	testCases := []struct {
+		name    string
+		setup   func() plumbing.Hash
+		wantTag string
+	}{
+		{name: "no tags", setup: func() plumbing.Hash { return commit("FROM scratch\n") }},
+		{
+			name: "lightweight tag on HEAD",
+			setup: func() plumbing.Hash {
+				hash := commit("FROM alpine\n")
+				if _, err := repo.CreateTag("v1.0.0", hash, nil); err != nil {
+					t.Fatalf("failed to create the tag. Error: %q", err)
+				}
+				return hash
+			},
+			wantTag: "v1.0.0",
+		},
+		{
+			name: "annotated tag on HEAD",
+			setup: func() plumbing.Hash {
+				hash := commit("FROM ubuntu\n")
+				if _, err := repo.CreateTag("v2.0.0", hash, &git.CreateTagOptions{Tagger: signature, Message: "release"}); err != nil {
+					t.Fatalf("failed to create the tag. Error: %q", err)
+				}
+				return hash
+			},
+			wantTag: "v2.0.0",
+		},
+		{name: "tags on the older commits are ignored", setup: func() plumbing.Hash { return commit("FROM debian\n") }},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			hash := testCase.setup()
+			commitHash, tag, err := GatherGitRevisionInfo(filepath.Join(dir, "Dockerfile"))
+			if err != nil {
+				t.Fatalf("failed to get the git revision info. Error: %q", err)
+			}
+			if commitHash != hash.String() || tag != testCase.wantTag {
+				t.Fatalf("the git revision info is incorrect. Expected: %s %q Actual: %s %q", hash, testCase.wantTag, commitHash, tag)
+			}
+		})
+	}
+}
@@ -19,4 +19,10 @@ package common
 var (
 	// ProjectName stores the project name during an execution
 	ProjectName = DefaultProjectName
+	// Environments stores the target environments (like dev, staging and prod) used for parameterization during an execution
+	Environments = []string{}
+	// DefaultEnvironments are the environments used for parameterization when none are specified
+	DefaultEnvironments = []string{"dev", "staging", "prod"}
+	// DebugTransformers are the names of the starlark transformers that pause at their breakpoints for interactive debugging
+	DebugTransformers = []string{}
 )
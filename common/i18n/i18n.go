@@ -0,0 +1,165 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultLocale is the locale of the messages in the code, which needs no catalog
+	DefaultLocale = "en"
+	// catalogExt is the extension of the message catalog files
+	catalogExt = ".yaml"
+	// CatalogsDirName is the name of the directory containing the message catalogs of a transformer, next to its yaml
+	CatalogsDirName = "locales"
+)
+
+// Catalog maps the english messages, which are also the format strings used in the code, to the messages in a locale
+type Catalog map[string]string
+
+var (
+	catalog = Catalog{}
+	locale  = DefaultLocale
+	// requestedLocale is the locale given to SetLocale, which is used to find the catalogs added later
+	requestedLocale = DefaultLocale
+	catalogMutex    sync.RWMutex
+)
+
+// GetLocaleFromEnv returns the locale from the LC_ALL, LC_MESSAGES and LANG environment variables, in that order
+func GetLocaleFromEnv() string {
+	for _, envName := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(envName); value != "" && value != "C" && value != "POSIX" {
+			return value
+		}
+	}
+	return DefaultLocale
+}
+
+// SetLocale loads the message catalog of the locale from the directory.
+// The catalog of the language is used when there is no catalog for the region, like es for es_MX.UTF-8 .
+// The messages are not translated if there is no catalog for the language either.
+func SetLocale(catalogsDir, newLocale string) error {
+	candidates := getLocaleCandidates(newLocale)
+	newCatalog, selectedLocale, err := readCatalog(catalogsDir, candidates)
+	if err != nil {
+		return err
+	}
+	if selectedLocale == DefaultLocale && len(candidates) > 0 && candidates[len(candidates)-1] != DefaultLocale {
+		logrus.Debugf("no message catalog was found for the locale %s . Using the english messages.", newLocale)
+	}
+	catalogMutex.Lock()
+	defer catalogMutex.Unlock()
+	catalog = newCatalog
+	locale = selectedLocale
+	requestedLocale = newLocale
+	return nil
+}
+
+// AddCatalogs adds the messages in the catalog of the current locale from the directory, like the locales directory of a transformer.
+// The messages that are already translated keep their translations. It does nothing if the directory has no catalog for the locale.
+func AddCatalogs(catalogsDir string) error {
+	catalogMutex.RLock()
+	candidates := getLocaleCandidates(requestedLocale)
+	catalogMutex.RUnlock()
+	newCatalog, selectedLocale, err := readCatalog(catalogsDir, candidates)
+	if err != nil || selectedLocale == DefaultLocale {
+		return err
+	}
+	catalogMutex.Lock()
+	defer catalogMutex.Unlock()
+	for message, translated := range newCatalog {
+		if _, ok := catalog[message]; !ok {
+			catalog[message] = translated
+		}
+	}
+	if locale == DefaultLocale {
+		locale = selectedLocale
+	}
+	return nil
+}
+
+// GetLocale returns the locale whose catalog is used to translate the messages
+func GetLocale() string {
+	catalogMutex.RLock()
+	defer catalogMutex.RUnlock()
+	return locale
+}
+
+// T translates the message using the catalog of the current locale and formats it with the arguments.
+// The message is used as it is when the catalog does not have a translation for it.
+func T(message string, args ...interface{}) string {
+	catalogMutex.RLock()
+	translated, ok := catalog[message]
+	catalogMutex.RUnlock()
+	if !ok || translated == "" {
+		translated = message
+	}
+	if len(args) == 0 {
+		return translated
+	}
+	return fmt.Sprintf(translated, args...)
+}
+
+// readCatalog reads the catalog of the first candidate that has one in the directory.
+// It returns an empty catalog and the default locale if none of them have a catalog.
+func readCatalog(catalogsDir string, candidates []string) (Catalog, string, error) {
+	newCatalog := Catalog{}
+	for _, candidate := range candidates {
+		if candidate == DefaultLocale {
+			break
+		}
+		catalogPath := filepath.Join(catalogsDir, candidate+catalogExt)
+		data, err := os.ReadFile(catalogPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return newCatalog, DefaultLocale, fmt.Errorf("failed to read the message catalog at path %s . Error: %w", catalogPath, err)
+		}
+		if err := yaml.Unmarshal(data, &newCatalog); err != nil {
+			return newCatalog, DefaultLocale, fmt.Errorf("failed to parse the message catalog at path %s . Error: %w", catalogPath, err)
+		}
+		return newCatalog, candidate, nil
+	}
+	return newCatalog, DefaultLocale, nil
+}
+
+// getLocaleCandidates returns the catalog names to look for, from the most specific to the language.
+// For example es_MX.UTF-8 gives es_mx and es .
+func getLocaleCandidates(locale string) []string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if idx := strings.IndexAny(locale, ".@"); idx != -1 {
+		locale = locale[:idx]
+	}
+	locale = strings.ReplaceAll(locale, "-", "_")
+	if locale == "" {
+		return nil
+	}
+	candidates := []string{locale}
+	if idx := strings.Index(locale, "_"); idx != -1 {
+		candidates = append(candidates, locale[:idx])
+	}
+	return candidates
+}
@@ -0,0 +1,110 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetLocaleCandidates(t *testing.T) {
+	testcases := map[string][]string{
+		"es_MX.UTF-8":   {"es_mx", "es"},
+		"de-DE":         {"de_de", "de"},
+		"fr":            {"fr"},
+		"sr_RS@latin":   {"sr_rs", "sr"},
+		"":              nil,
+		" en_US.UTF-8 ": {"en_us", "en"},
+	}
+	for locale, want := range testcases {
+		if got := getLocaleCandidates(locale); !cmp.Equal(got, want) {
+			t.Errorf("failed to get the candidates for the locale %q. Difference:\n%s", locale, cmp.Diff(want, got))
+		}
+	}
+}
+
+func TestSetLocale(t *testing.T) {
+	catalogsDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(catalogsDir, "es.yaml"), []byte(`"Plan can be found at [%s].": "El plan se encuentra en [%s]."`), 0644); err != nil {
+		t.Fatalf("failed to write the catalog. Error: %q", err)
+	}
+	defer SetLocale(catalogsDir, DefaultLocale)
+
+	t.Run("the catalog of the language is used for a region", func(t *testing.T) {
+		if err := SetLocale(catalogsDir, "es_MX.UTF-8"); err != nil {
+			t.Fatalf("failed to set the locale. Error: %q", err)
+		}
+		if GetLocale() != "es" {
+			t.Fatalf("expected the locale es. Actual: %s", GetLocale())
+		}
+		if got, want := T("Plan can be found at [%s].", "m2k.plan"), "El plan se encuentra en [m2k.plan]."; got != want {
+			t.Fatalf("failed to translate the message. Expected: %q Actual: %q", want, got)
+		}
+		if got, want := T("Not in the catalog %d", 1), "Not in the catalog 1"; got != want {
+			t.Fatalf("failed to fall back to the message. Expected: %q Actual: %q", want, got)
+		}
+	})
+	t.Run("the messages are not translated without a catalog", func(t *testing.T) {
+		if err := SetLocale(catalogsDir, "fr_FR"); err != nil {
+			t.Fatalf("failed to set the locale. Error: %q", err)
+		}
+		if GetLocale() != DefaultLocale {
+			t.Fatalf("expected the locale %s. Actual: %s", DefaultLocale, GetLocale())
+		}
+		if got, want := T("Plan can be found at [%s].", "m2k.plan"), "Plan can be found at [m2k.plan]."; got != want {
+			t.Fatalf("failed to fall back to the message. Expected: %q Actual: %q", want, got)
+		}
+	})
+	t.Run("an invalid catalog is an error", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(catalogsDir, "de.yaml"), []byte("- not a map"), 0644); err != nil {
+			t.Fatalf("failed to write the catalog. Error: %q", err)
+		}
+		if err := SetLocale(catalogsDir, "de"); err == nil {
+			t.Fatalf("expected an error for the invalid catalog")
+		}
+	})
+}
+
+func TestAddCatalogs(t *testing.T) {
+	catalogsDir := t.TempDir()
+	transformerCatalogsDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(catalogsDir, "es.yaml"), []byte(`"Hints:": "Sugerencias:"`), 0644); err != nil {
+		t.Fatalf("failed to write the catalog. Error: %q", err)
+	}
+	if err := os.WriteFile(filepath.Join(transformerCatalogsDir, "es_mx.yaml"), []byte(`{"Hints:": "Pistas:", "Enter the port": "Introduzca el puerto"}`), 0644); err != nil {
+		t.Fatalf("failed to write the catalog. Error: %q", err)
+	}
+	defer SetLocale(catalogsDir, DefaultLocale)
+	if err := SetLocale(catalogsDir, "es_MX"); err != nil {
+		t.Fatalf("failed to set the locale. Error: %q", err)
+	}
+	if err := AddCatalogs(transformerCatalogsDir); err != nil {
+		t.Fatalf("failed to add the catalogs. Error: %q", err)
+	}
+	if err := AddCatalogs(t.TempDir()); err != nil {
+		t.Fatalf("expected a directory without catalogs to be ignored. Error: %q", err)
+	}
+	if got, want := T("Enter the port"), "Introduzca el puerto"; got != want {
+		t.Fatalf("failed to translate the message of the added catalog. Expected: %q Actual: %q", want, got)
+	}
+	if got, want := T("Hints:"), "Sugerencias:"; got != want {
+		t.Fatalf("expected the added catalog not to override the translations. Expected: %q Actual: %q", want, got)
+	}
+}
@@ -0,0 +1,446 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package imagecache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/konveyor/move2kube/common"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// DirEnvKey is the environment variable that contains the directory of the OCI layout used as the image cache.
+	// The cache is disabled when it is not set.
+	DirEnvKey = "MOVE2KUBE_IMAGE_CACHE_DIR"
+	// OfflineEnvKey is the environment variable that stops the cache from pulling images that are not already cached
+	OfflineEnvKey = "MOVE2KUBE_IMAGE_CACHE_OFFLINE"
+	// MaxSizeEnvKey is the environment variable that contains the maximum size of the cache. Example: 20Gi
+	MaxSizeEnvKey = "MOVE2KUBE_IMAGE_CACHE_MAX_SIZE"
+	// MaxAgeEnvKey is the environment variable that contains how long an image can stay unused in the cache. Example: 720h
+	MaxAgeEnvKey = "MOVE2KUBE_IMAGE_CACHE_MAX_AGE"
+
+	// refNameAnnotation is the standard OCI annotation that contains the image reference
+	refNameAnnotation = "org.opencontainers.image.ref.name"
+	// lastUsedAnnotation contains the last time the image was fetched from the cache
+	lastUsedAnnotation = "move2kube.konveyor.io/last-used"
+	// containerdNameAnnotation is the annotation that containerd and some other tools store the full image reference in
+	containerdNameAnnotation = "io.containerd.image.name"
+	blobsDir                 = "blobs"
+	indexFile                = "index.json"
+	// lockFile is locked by the processes sharing the cache while they use the index and the blobs
+	lockFile = "move2kube.lock"
+)
+
+var (
+	defaultMaxSize = resource.MustParse("20Gi")
+	defaultMaxAge  = 30 * 24 * time.Hour
+	cache          *Cache
+	cacheOnce      sync.Once
+)
+
+// Cache is a local OCI image layout directory that stores the images pulled by move2kube
+type Cache struct {
+	path    layout.Path
+	offline bool
+	maxSize int64
+	maxAge  time.Duration
+	mutex   sync.Mutex
+}
+
+// Get returns the cache configured using the environment variables and nil if the cache is disabled.
+// Garbage collection is done the first time the cache is opened.
+func Get() *Cache {
+	cacheOnce.Do(func() {
+		dir := os.Getenv(DirEnvKey)
+		if dir == "" {
+			return
+		}
+		c, err := New(dir, cast.ToBool(os.Getenv(OfflineEnvKey)), getMaxSize(), getMaxAge())
+		if err != nil {
+			logrus.Errorf("Failed to open the image cache at %s . Error: %q", dir, err)
+			return
+		}
+		if err := c.GC(); err != nil {
+			logrus.Warnf("Failed to garbage collect the image cache at %s . Error: %q", dir, err)
+		}
+		cache = c
+	})
+	return cache
+}
+
+// New opens the OCI layout at the given directory, creating it if necessary.
+// A non positive maxSize or maxAge disables the corresponding garbage collection policy.
+func New(dir string, offline bool, maxSize int64, maxAge time.Duration) (*Cache, error) {
+	if err := os.MkdirAll(dir, common.DefaultDirectoryPermission); err != nil {
+		return nil, fmt.Errorf("failed to create the directory %s . Error: %w", dir, err)
+	}
+	path, err := layout.FromPath(dir)
+	if err != nil {
+		if path, err = layout.Write(dir, empty.Index); err != nil {
+			return nil, fmt.Errorf("failed to create an OCI layout at %s . Error: %w", dir, err)
+		}
+	}
+	return &Cache{path: path, offline: offline, maxSize: maxSize, maxAge: maxAge}, nil
+}
+
+// Image returns the image from the cache, pulling it into the cache if it is not present.
+// The cache stays locked while pulling, so the other processes sharing the cache wait for the pull to finish.
+func (c *Cache) Image(image string) (v1.Image, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the image reference %s . Error: %w", image, err)
+	}
+	refName := ref.Name()
+	unlock, err := c.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	indexManifest, err := c.readIndex()
+	if err != nil {
+		return nil, err
+	}
+	if i := findImage(indexManifest, ref); i != -1 {
+		desc := indexManifest.Manifests[i]
+		img, err := c.path.Image(desc.Digest)
+		if err == nil {
+			if err := c.touch(desc.Digest); err != nil {
+				logrus.Debugf("failed to update the last used time of the cached image %s . Error: %q", refName, err)
+			}
+			logrus.Debugf("Using the image %s from the image cache", refName)
+			return img, nil
+		}
+		logrus.Debugf("failed to read the cached image %s . Error: %q", refName, err)
+	}
+	if c.offline {
+		return nil, fmt.Errorf("the image %s is not in the image cache and pulling is disabled by %s", refName, OfflineEnvKey)
+	}
+	logrus.Infof("Pulling the image %s into the image cache. This could take a few mins.", refName)
+	img, err := crane.Pull(refName, crane.WithAuthFromKeychain(authn.DefaultKeychain), crane.WithPlatform(&v1.Platform{OS: "linux", Architecture: runtime.GOARCH}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull the image %s . Error: %w", refName, err)
+	}
+	if err := c.removeRef(ref); err != nil {
+		return nil, err
+	}
+	annotations := map[string]string{refNameAnnotation: refName, lastUsedAnnotation: time.Now().UTC().Format(time.RFC3339)}
+	if err := c.path.AppendImage(img, layout.WithAnnotations(annotations)); err != nil {
+		return nil, fmt.Errorf("failed to write the image %s to the image cache. Error: %w", refName, err)
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the digest of the image %s . Error: %w", refName, err)
+	}
+	return c.path.Image(digest)
+}
+
+// ConfigFile returns the config of the image from the cache, pulling it into the cache if it is not present
+func (c *Cache) ConfigFile(image string) (*v1.ConfigFile, error) {
+	img, err := c.Image(image)
+	if err != nil {
+		return nil, err
+	}
+	configFile, err := img.ConfigFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the config of the image %s . Error: %w", image, err)
+	}
+	return configFile, nil
+}
+
+// WriteDockerTarball writes the image from the cache as a tarball that can be loaded using docker load
+func (c *Cache) WriteDockerTarball(image string, w io.Writer) error {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return fmt.Errorf("failed to parse the image reference %s . Error: %w", image, err)
+	}
+	img, err := c.Image(image)
+	if err != nil {
+		return err
+	}
+	if err := tarball.Write(ref, img, w); err != nil {
+		return fmt.Errorf("failed to write the image %s as a tarball. Error: %w", image, err)
+	}
+	return nil
+}
+
+// GC removes the images that have not been used within the max age and
+// then the least recently used images till the cache fits within the max size
+// The images seeded into the layout by other tools have no last used time, so it is set to the time of the first GC.
+func (c *Cache) GC() error {
+	unlock, err := c.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	indexManifest, err := c.readIndex()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	kept := []v1.Descriptor{}
+	for _, desc := range indexManifest.Manifests {
+		if _, err := time.Parse(time.RFC3339, desc.Annotations[lastUsedAnnotation]); err != nil {
+			if desc.Annotations == nil {
+				desc.Annotations = map[string]string{}
+			}
+			desc.Annotations[lastUsedAnnotation] = now.UTC().Format(time.RFC3339)
+		}
+		if c.maxAge > 0 && now.Sub(getLastUsed(desc)) > c.maxAge {
+			logrus.Debugf("Removing the image %s from the image cache since it has not been used for %s", desc.Annotations[refNameAnnotation], c.maxAge)
+			continue
+		}
+		kept = append(kept, desc)
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return getLastUsed(kept[i]).Before(getLastUsed(kept[j])) })
+	for {
+		indexManifest.Manifests = kept
+		if err := c.writeIndex(indexManifest); err != nil {
+			return err
+		}
+		size, err := c.removeUnreferencedBlobs(kept)
+		if err != nil {
+			return err
+		}
+		if c.maxSize <= 0 || size <= c.maxSize || len(kept) == 0 {
+			return nil
+		}
+		logrus.Debugf("Removing the image %s from the image cache since the cache size %d exceeds %d bytes", kept[0].Annotations[refNameAnnotation], size, c.maxSize)
+		kept = kept[1:]
+	}
+}
+
+// lock takes the lock of this process and the file lock shared with the other processes using the cache,
+// and returns the function that releases them
+func (c *Cache) lock() (func(), error) {
+	c.mutex.Lock()
+	lockPath := filepath.Join(string(c.path), lockFile)
+	f, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, common.DefaultFilePermission)
+	if err != nil {
+		c.mutex.Unlock()
+		return nil, fmt.Errorf("failed to open the lock file %s of the image cache. Error: %w", lockPath, err)
+	}
+	if err := lockFileExclusive(f); err != nil {
+		f.Close()
+		c.mutex.Unlock()
+		return nil, fmt.Errorf("failed to lock the image cache using the file %s . Error: %w", lockPath, err)
+	}
+	return func() {
+		if err := unlockFile(f); err != nil {
+			logrus.Debugf("failed to unlock the image cache lock file %s . Error: %q", lockPath, err)
+		}
+		f.Close()
+		c.mutex.Unlock()
+	}, nil
+}
+
+func (c *Cache) touch(digest v1.Hash) error {
+	indexManifest, err := c.readIndex()
+	if err != nil {
+		return err
+	}
+	for i, desc := range indexManifest.Manifests {
+		if desc.Digest != digest {
+			continue
+		}
+		if desc.Annotations == nil {
+			indexManifest.Manifests[i].Annotations = map[string]string{}
+		}
+		indexManifest.Manifests[i].Annotations[lastUsedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	}
+	return c.writeIndex(indexManifest)
+}
+
+func (c *Cache) removeRef(ref name.Reference) error {
+	indexManifest, err := c.readIndex()
+	if err != nil {
+		return err
+	}
+	kept := []v1.Descriptor{}
+	for _, desc := range indexManifest.Manifests {
+		if !isSameImage(desc, ref) {
+			kept = append(kept, desc)
+		}
+	}
+	indexManifest.Manifests = kept
+	return c.writeIndex(indexManifest)
+}
+
+// findImage returns the position of the image in the index, or -1 if it is not cached.
+// The images pulled by move2kube are annotated with the full reference, like index.docker.io/library/nginx:1.21 .
+// The layouts seeded using other tools may have a short reference like nginx:1.21 , which is normalized before comparing,
+// or only the tag like 1.21 (skopeo copy oci:<dir>:<tag>). A tag only image is used if no image has a matching reference,
+// so seed at most one image per tag if the references are not stored.
+func findImage(indexManifest *v1.IndexManifest, ref name.Reference) int {
+	tagOnly := -1
+	for i, desc := range indexManifest.Manifests {
+		if isSameImage(desc, ref) {
+			return i
+		}
+		if refName := desc.Annotations[refNameAnnotation]; tagOnly == -1 && refName == ref.Identifier() && !strings.ContainsAny(refName, "/:@") {
+			tagOnly = i
+		}
+	}
+	return tagOnly
+}
+
+// isSameImage returns true if the reference annotations of the image, after normalization, are the same as the reference
+func isSameImage(desc v1.Descriptor, ref name.Reference) bool {
+	for _, annotation := range []string{refNameAnnotation, containerdNameAnnotation} {
+		refName := desc.Annotations[annotation]
+		if refName == "" {
+			continue
+		}
+		if refName == ref.Name() {
+			return true
+		}
+		if !strings.ContainsAny(refName, "/:@") {
+			// only a tag
+			continue
+		}
+		if otherRef, err := name.ParseReference(refName); err == nil && otherRef.Name() == ref.Name() {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Cache) readIndex() (*v1.IndexManifest, error) {
+	index, err := c.path.ImageIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the index of the image cache. Error: %w", err)
+	}
+	indexManifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the index manifest of the image cache. Error: %w", err)
+	}
+	return indexManifest.DeepCopy(), nil
+}
+
+func (c *Cache) writeIndex(indexManifest *v1.IndexManifest) error {
+	rawIndex, err := json.MarshalIndent(indexManifest, "", "   ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the index of the image cache. Error: %w", err)
+	}
+	if err := c.path.WriteFile(indexFile, rawIndex, common.DefaultFilePermission); err != nil {
+		return fmt.Errorf("failed to write the index of the image cache. Error: %w", err)
+	}
+	return nil
+}
+
+// removeUnreferencedBlobs deletes the blobs not used by the given images and returns the size of the remaining blobs
+func (c *Cache) removeUnreferencedBlobs(descs []v1.Descriptor) (int64, error) {
+	referenced := map[string]bool{}
+	for _, desc := range descs {
+		referenced[desc.Digest.String()] = true
+		img, err := c.path.Image(desc.Digest)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read the cached image %s . Error: %w", desc.Digest, err)
+		}
+		manifest, err := img.Manifest()
+		if err != nil {
+			return 0, fmt.Errorf("failed to read the manifest of the cached image %s . Error: %w", desc.Digest, err)
+		}
+		referenced[manifest.Config.Digest.String()] = true
+		for _, layer := range manifest.Layers {
+			referenced[layer.Digest.String()] = true
+		}
+	}
+	size := int64(0)
+	blobsPath := filepath.Join(string(c.path), blobsDir)
+	algorithms, err := os.ReadDir(blobsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read the directory %s . Error: %w", blobsPath, err)
+	}
+	for _, algorithm := range algorithms {
+		algorithmPath := filepath.Join(blobsPath, algorithm.Name())
+		blobs, err := os.ReadDir(algorithmPath)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read the directory %s . Error: %w", algorithmPath, err)
+		}
+		for _, blob := range blobs {
+			blobPath := filepath.Join(algorithmPath, blob.Name())
+			if !referenced[algorithm.Name()+":"+blob.Name()] {
+				if err := os.Remove(blobPath); err != nil {
+					return 0, fmt.Errorf("failed to remove the blob %s . Error: %w", blobPath, err)
+				}
+				continue
+			}
+			blobInfo, err := blob.Info()
+			if err != nil {
+				return 0, fmt.Errorf("failed to stat the blob %s . Error: %w", blobPath, err)
+			}
+			size += blobInfo.Size()
+		}
+	}
+	return size, nil
+}
+
+func getLastUsed(desc v1.Descriptor) time.Time {
+	lastUsed, err := time.Parse(time.RFC3339, desc.Annotations[lastUsedAnnotation])
+	if err != nil {
+		return time.Time{}
+	}
+	return lastUsed
+}
+
+func getMaxSize() int64 {
+	maxSizeStr := os.Getenv(MaxSizeEnvKey)
+	if maxSizeStr == "" {
+		return defaultMaxSize.Value()
+	}
+	maxSize, err := resource.ParseQuantity(maxSizeStr)
+	if err != nil {
+		logrus.Warnf("Ignoring the invalid image cache size %s in %s . Error: %q", maxSizeStr, MaxSizeEnvKey, err)
+		return defaultMaxSize.Value()
+	}
+	return maxSize.Value()
+}
+
+func getMaxAge() time.Duration {
+	maxAgeStr := os.Getenv(MaxAgeEnvKey)
+	if maxAgeStr == "" {
+		return defaultMaxAge
+	}
+	maxAge, err := time.ParseDuration(maxAgeStr)
+	if err != nil {
+		logrus.Warnf("Ignoring the invalid image cache max age %s in %s . Error: %q", maxAgeStr, MaxAgeEnvKey, err)
+		return defaultMaxAge
+	}
+	return maxAge
+}
@@ -0,0 +1,226 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package imagecache
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+)
+
+func pushTestImage(t *testing.T, reference, contents string) {
+	t.Helper()
+	img, err := crane.Image(map[string][]byte{"data.txt": []byte(contents)})
+	if err != nil {
+		t.Fatalf("failed to create the test image. Error: %q", err)
+	}
+	if err := crane.Push(img, reference, crane.Insecure); err != nil {
+		t.Fatalf("failed to push the test image. Error: %q", err)
+	}
+}
+
+func getCachedRefs(t *testing.T, c *Cache) []string {
+	t.Helper()
+	indexManifest, err := c.readIndex()
+	if err != nil {
+		t.Fatalf("failed to read the index. Error: %q", err)
+	}
+	refs := []string{}
+	for _, desc := range indexManifest.Manifests {
+		refs = append(refs, desc.Annotations[refNameAnnotation])
+	}
+	return refs
+}
+
+func TestCache(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	image1 := host + "/app/one:1.0.0"
+	image2 := host + "/app/two:1.0.0"
+	pushTestImage(t, image1, "one")
+	pushTestImage(t, image2, strings.Repeat("two", 1000))
+	dir := t.TempDir()
+
+	t.Run("pull through the cache and reuse it when offline", func(t *testing.T) {
+		c, err := New(dir, false, 0, 0)
+		if err != nil {
+			t.Fatalf("failed to create the cache. Error: %q", err)
+		}
+		for _, image := range []string{image1, image2, image1} {
+			if _, err := c.Image(image); err != nil {
+				t.Fatalf("failed to get the image %s . Error: %q", image, err)
+			}
+		}
+		if refs := getCachedRefs(t, c); len(refs) != 2 {
+			t.Fatalf("expected 2 cached images. Actual: %v", refs)
+		}
+		offlineCache, err := New(dir, true, 0, 0)
+		if err != nil {
+			t.Fatalf("failed to open the cache. Error: %q", err)
+		}
+		img, err := offlineCache.Image(image2)
+		if err != nil {
+			t.Fatalf("failed to get the cached image %s . Error: %q", image2, err)
+		}
+		if _, err := img.Manifest(); err != nil {
+			t.Fatalf("failed to read the manifest of the cached image. Error: %q", err)
+		}
+		if _, err := offlineCache.Image(host + "/app/three:1.0.0"); err == nil {
+			t.Fatalf("expected an error for an image that is not cached when offline")
+		}
+	})
+
+	t.Run("garbage collect the least recently used images", func(t *testing.T) {
+		c, err := New(dir, true, 0, 0)
+		if err != nil {
+			t.Fatalf("failed to open the cache. Error: %q", err)
+		}
+		time.Sleep(1100 * time.Millisecond)
+		if _, err := c.Image(image1); err != nil {
+			t.Fatalf("failed to get the image %s . Error: %q", image1, err)
+		}
+		indexManifest, err := c.readIndex()
+		if err != nil {
+			t.Fatalf("failed to read the index. Error: %q", err)
+		}
+		size, err := c.removeUnreferencedBlobs(indexManifest.Manifests)
+		if err != nil {
+			t.Fatalf("failed to get the size of the cache. Error: %q", err)
+		}
+		c.maxSize = size - 1
+		if err := c.GC(); err != nil {
+			t.Fatalf("failed to garbage collect the cache. Error: %q", err)
+		}
+		if refs := getCachedRefs(t, c); len(refs) != 1 || refs[0] != image1 {
+			t.Fatalf("expected only the recently used image %s to be cached. Actual: %v", image1, refs)
+		}
+	})
+
+	t.Run("garbage collect the images older than the max age", func(t *testing.T) {
+		c, err := New(t.TempDir(), false, 0, time.Hour)
+		if err != nil {
+			t.Fatalf("failed to create the cache. Error: %q", err)
+		}
+		if _, err := c.Image(image1); err != nil {
+			t.Fatalf("failed to get the image %s . Error: %q", image1, err)
+		}
+		if _, err := c.Image(image2); err != nil {
+			t.Fatalf("failed to get the image %s . Error: %q", image2, err)
+		}
+		indexManifest, err := c.readIndex()
+		if err != nil {
+			t.Fatalf("failed to read the index. Error: %q", err)
+		}
+		for i, desc := range indexManifest.Manifests {
+			if desc.Annotations[refNameAnnotation] == image2 {
+				indexManifest.Manifests[i].Annotations[lastUsedAnnotation] = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+			}
+		}
+		if err := c.writeIndex(indexManifest); err != nil {
+			t.Fatalf("failed to write the index. Error: %q", err)
+		}
+		if err := c.GC(); err != nil {
+			t.Fatalf("failed to garbage collect the cache. Error: %q", err)
+		}
+		if refs := getCachedRefs(t, c); len(refs) != 1 || refs[0] != image1 {
+			t.Fatalf("expected only %s to be cached. Actual: %v", image1, refs)
+		}
+	})
+	t.Run("keep and find the images seeded by other tools", func(t *testing.T) {
+		seededDir := t.TempDir()
+		path, err := layout.Write(seededDir, empty.Index)
+		if err != nil {
+			t.Fatalf("failed to create the OCI layout. Error: %q", err)
+		}
+		for _, refName := range []string{"1.0.0", "docker.io/library/seeded:2.0.0"} {
+			img, err := crane.Image(map[string][]byte{"data.txt": []byte(refName)})
+			if err != nil {
+				t.Fatalf("failed to create the test image. Error: %q", err)
+			}
+			if err := path.AppendImage(img, layout.WithAnnotations(map[string]string{refNameAnnotation: refName})); err != nil {
+				t.Fatalf("failed to seed the test image. Error: %q", err)
+			}
+		}
+		c, err := New(seededDir, true, 0, time.Hour)
+		if err != nil {
+			t.Fatalf("failed to open the cache. Error: %q", err)
+		}
+		if err := c.GC(); err != nil {
+			t.Fatalf("failed to garbage collect the cache. Error: %q", err)
+		}
+		indexManifest, err := c.readIndex()
+		if err != nil {
+			t.Fatalf("failed to read the index. Error: %q", err)
+		}
+		if len(indexManifest.Manifests) != 2 {
+			t.Fatalf("expected the 2 seeded images to be kept. Actual: %v", getCachedRefs(t, c))
+		}
+		for _, desc := range indexManifest.Manifests {
+			if _, err := time.Parse(time.RFC3339, desc.Annotations[lastUsedAnnotation]); err != nil {
+				t.Fatalf("expected the seeded image %s to have a last used time. Error: %q", desc.Annotations[refNameAnnotation], err)
+			}
+		}
+		for _, image := range []string{host + "/app/seeded:1.0.0", "seeded:2.0.0"} {
+			if _, err := c.Image(image); err != nil {
+				t.Fatalf("failed to get the seeded image %s . Error: %q", image, err)
+			}
+		}
+		if _, err := c.Image("seeded:3.0.0"); err == nil {
+			t.Fatalf("expected an error for an image that is not cached when offline")
+		}
+	})
+
+	t.Run("lock the cache while it is used by another process", func(t *testing.T) {
+		c1, err := New(dir, true, 0, 0)
+		if err != nil {
+			t.Fatalf("failed to open the cache. Error: %q", err)
+		}
+		c2, err := New(dir, true, 0, 0)
+		if err != nil {
+			t.Fatalf("failed to open the cache. Error: %q", err)
+		}
+		unlock, err := c1.lock()
+		if err != nil {
+			t.Fatalf("failed to lock the cache. Error: %q", err)
+		}
+		locked := make(chan struct{})
+		go func() {
+			defer close(locked)
+			if unlock, err := c2.lock(); err == nil {
+				unlock()
+			}
+		}()
+		select {
+		case <-locked:
+			t.Fatalf("expected the cache to stay locked until it is unlocked")
+		case <-time.After(200 * time.Millisecond):
+		}
+		unlock()
+		select {
+		case <-locked:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected the cache to be locked after it is unlocked")
+		}
+	})
+}
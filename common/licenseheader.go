@@ -0,0 +1,88 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	licenseHeaderKeywordRegex = regexp.MustCompile(`(?i)copyright|license|spdx-license-identifier`)
+	// Shebangs, Dockerfile parser directives and encoding declarations have to stay on the first lines of the file
+	leadingDirectiveRegex = regexp.MustCompile(`^(#!|#\s*(syntax|escape|check)\s*=|#.*-\*-.*coding)`)
+)
+
+// GetLicenseHeader returns the leading comment block of the file contents if it is a copyright or license header
+func GetLicenseHeader(content string) string {
+	lines := strings.SplitAfter(content, "\n")
+	i := 0
+	for i < len(lines) && leadingDirectiveRegex.MatchString(lines[i]) {
+		i++
+	}
+	start := i
+	blockEnd := ""
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if blockEnd != "" {
+			if strings.Contains(line, blockEnd) {
+				blockEnd = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if strings.HasPrefix(line, "/*") && !strings.Contains(line[2:], "*/") {
+			blockEnd = "*/"
+			continue
+		}
+		if strings.HasPrefix(line, "<!--") && !strings.Contains(line[4:], "-->") {
+			blockEnd = "-->"
+			continue
+		}
+		if (strings.HasPrefix(line, "/*") && strings.HasSuffix(line, "*/")) || (strings.HasPrefix(line, "<!--") && strings.HasSuffix(line, "-->")) {
+			continue
+		}
+		break
+	}
+	header := strings.Join(lines[start:i], "")
+	if !licenseHeaderKeywordRegex.MatchString(header) {
+		return ""
+	}
+	if !strings.HasSuffix(header, "\n") {
+		header += "\n"
+	}
+	return header
+}
+
+// AddLicenseHeader adds the header to the file contents after any shebang or parser directives
+func AddLicenseHeader(header, content string) string {
+	if header == "" || GetLicenseHeader(content) != "" {
+		return content
+	}
+	lines := strings.SplitAfter(content, "\n")
+	i := 0
+	for i < len(lines) && leadingDirectiveRegex.MatchString(lines[i]) {
+		i++
+	}
+	rest := strings.Join(lines[i:], "")
+	if rest != "" && !strings.HasPrefix(rest, "\n") {
+		header += "\n"
+	}
+	return strings.Join(lines[:i], "") + header + rest
+}
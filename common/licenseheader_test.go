@@ -0,0 +1,41 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import "testing"
+
+func TestLicenseHeader(t *testing.T) {
+	source := "# syntax=docker/dockerfile:1\n# Copyright 2020 Example Corp\n# SPDX-License-Identifier: MIT\n\nFROM golang:1.18\n"
+	header := GetLicenseHeader(source)
+	if want := "# Copyright 2020 Example Corp\n# SPDX-License-Identifier: MIT\n"; header != want {
+		t.Fatalf("expected the header %q . Actual: %q", want, header)
+	}
+	if header := GetLicenseHeader("# Build the app\nFROM golang:1.18\n"); header != "" {
+		t.Fatalf("expected no header for a comment without a copyright. Actual: %q", header)
+	}
+	if header := GetLicenseHeader("/*\n * Copyright IBM Corporation 2022\n */\npackage main\n"); header != "/*\n * Copyright IBM Corporation 2022\n */\n" {
+		t.Fatalf("expected the block comment header. Actual: %q", header)
+	}
+	generated := "# syntax=docker/dockerfile:1\nFROM registry.access.redhat.com/ubi8/go-toolset:1.18\n"
+	want := "# syntax=docker/dockerfile:1\n# Copyright 2020 Example Corp\n# SPDX-License-Identifier: MIT\n\nFROM registry.access.redhat.com/ubi8/go-toolset:1.18\n"
+	if actual := AddLicenseHeader(header, generated); actual != want {
+		t.Fatalf("expected the header to be added after the parser directive. Expected: %q Actual: %q", want, actual)
+	}
+	if actual := AddLicenseHeader(header, source); actual != source {
+		t.Fatalf("expected the contents with a header to be unchanged. Actual: %q", actual)
+	}
+}
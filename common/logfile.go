@@ -0,0 +1,238 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// LogFileOptions is how the logs are written to a log file
+type LogFileOptions struct {
+	// Level is the most verbose level written to the file
+	Level logrus.Level
+	// MaxSize is the size in bytes after which the file is rotated. 0 means the file is never rotated.
+	MaxSize int64
+	// MaxBackups is the number of rotated files that are kept
+	MaxBackups int
+}
+
+// GetLogFileOptions parses the log level and the maximum size (like 100Mi) of the log file
+func GetLogFileOptions(level, maxSize string, maxBackups int) (LogFileOptions, error) {
+	opts := LogFileOptions{Level: logrus.GetLevel(), MaxBackups: maxBackups}
+	if level != "" {
+		var err error
+		if opts.Level, err = logrus.ParseLevel(level); err != nil {
+			return opts, fmt.Errorf("failed to parse the log level %s . Error: %w", level, err)
+		}
+	}
+	if maxSize != "" {
+		quantity, err := resource.ParseQuantity(maxSize)
+		if err != nil {
+			return opts, fmt.Errorf("failed to parse the maximum size %s . Error: %w", maxSize, err)
+		}
+		opts.MaxSize = quantity.Value()
+	}
+	if opts.MaxSize < 0 || opts.MaxBackups < 0 {
+		return opts, fmt.Errorf("the maximum size %s and the number of backups %d of the log file cannot be negative", maxSize, maxBackups)
+	}
+	return opts, nil
+}
+
+// TeeLogsToFile writes the logs to the file in addition to the console and returns the file, which the caller should close.
+// When the level of the file is more verbose than the level of the console, the console keeps printing only at its own level.
+func TeeLogsToFile(path string, opts LogFileOptions) (*RotatingFileWriter, error) {
+	w, err := NewRotatingFileWriter(path, opts.MaxSize, opts.MaxBackups)
+	if err != nil {
+		return nil, err
+	}
+	consoleFormatter := logrus.StandardLogger().Formatter
+	var fileFormatter logrus.Formatter = &logrus.TextFormatter{DisableColors: true, FullTimestamp: true}
+	if _, ok := getBaseFormatter(consoleFormatter).(*logrus.JSONFormatter); ok {
+		fileFormatter = &logrus.JSONFormatter{}
+	}
+	if opts.Level > logrus.GetLevel() {
+		logrus.SetFormatter(&LevelFilterFormatter{Formatter: consoleFormatter, Level: logrus.GetLevel()})
+		logrus.SetLevel(opts.Level)
+	}
+	logrus.AddHook(NewLogFileHook(w, fileFormatter, opts.Level))
+	return w, nil
+}
+
+// getBaseFormatter returns the formatter wrapped by the level filters
+func getBaseFormatter(formatter logrus.Formatter) logrus.Formatter {
+	for {
+		filter, ok := formatter.(*LevelFilterFormatter)
+		if !ok {
+			return formatter
+		}
+		formatter = filter.Formatter
+	}
+}
+
+// LevelFilterFormatter formats only the log entries at or above a level, and drops the more verbose ones
+type LevelFilterFormatter struct {
+	Formatter logrus.Formatter
+	Level     logrus.Level
+}
+
+// Format formats the log entry using the wrapped formatter if it is at or above the level
+func (f *LevelFilterFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if entry.Level > f.Level {
+		return nil, nil
+	}
+	return f.Formatter.Format(entry)
+}
+
+// LogFileHook writes the log entries at or above a level to a writer
+type LogFileHook struct {
+	writer    io.Writer
+	formatter logrus.Formatter
+	level     logrus.Level
+}
+
+// NewLogFileHook creates a hook that writes the log entries at or above the level to the writer using the formatter
+func NewLogFileHook(writer io.Writer, formatter logrus.Formatter, level logrus.Level) *LogFileHook {
+	return &LogFileHook{writer: writer, formatter: formatter, level: level}
+}
+
+// Fire writes the log entry
+func (hook *LogFileHook) Fire(entry *logrus.Entry) error {
+	data, err := hook.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	_, err = hook.writer.Write(data)
+	return err
+}
+
+// Levels returns the levels that are written
+func (hook *LogFileHook) Levels() []logrus.Level {
+	levels := []logrus.Level{}
+	for _, level := range logrus.AllLevels {
+		if level <= hook.level {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// RotatingFileWriter appends to a file and rotates it once it grows past a size.
+// The rotated files are named <path>.1 (the newest) to <path>.<max backups> (the oldest).
+type RotatingFileWriter struct {
+	mutex      sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// NewRotatingFileWriter opens the file for appending. A maximum size of 0 means the file is never rotated.
+func NewRotatingFileWriter(path string, maxSize int64, maxBackups int) (*RotatingFileWriter, error) {
+	w := &RotatingFileWriter{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *RotatingFileWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, DefaultFilePermission)
+	if err != nil {
+		return fmt.Errorf("failed to open the log file at path %s . Error: %w", w.path, err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to stat the log file at path %s . Error: %w", w.path, err)
+	}
+	w.file = f
+	w.size = fi.Size()
+	return nil
+}
+
+// Write appends the data to the file, rotating it first if the data would make it grow past the maximum size
+func (w *RotatingFileWriter) Write(data []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.file == nil {
+		return 0, fmt.Errorf("the log file at path %s is closed", w.path)
+	}
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(data)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(data)
+	w.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the rotated files by one, dropping the oldest, and starts a new file. The caller must hold the lock.
+func (w *RotatingFileWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close the log file at path %s . Error: %w", w.path, err)
+	}
+	w.file = nil
+	if w.maxBackups == 0 {
+		if err := os.Remove(w.path); err != nil {
+			return fmt.Errorf("failed to remove the log file at path %s . Error: %w", w.path, err)
+		}
+		return w.open()
+	}
+	for i := w.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(fmt.Sprintf("%s.%d", w.path, i), fmt.Sprintf("%s.%d", w.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate the log file at path %s . Error: %w", w.path, err)
+		}
+	}
+	if err := os.Rename(w.path, w.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate the log file at path %s . Error: %w", w.path, err)
+	}
+	return w.open()
+}
+
+// MoveTo closes the file and continues writing to the file at the new path, for example before archiving the directory of the file
+func (w *RotatingFileWriter) MoveTo(path string) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return fmt.Errorf("failed to close the log file at path %s . Error: %w", w.path, err)
+		}
+		w.file = nil
+	}
+	w.path = path
+	return w.open()
+}
+
+// Close closes the file
+func (w *RotatingFileWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
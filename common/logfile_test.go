@@ -0,0 +1,99 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRotatingFileWriter(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "m2k.log")
+	w, err := common.NewRotatingFileWriter(logPath, 10, 2)
+	if err != nil {
+		t.Fatalf("failed to open the log file. Error: %q", err)
+	}
+	defer w.Close()
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("failed to write to the log file. Error: %q", err)
+		}
+	}
+	want := map[string]string{logPath: "fourth\n", logPath + ".1": "third\n", logPath + ".2": "second\n"}
+	for path, content := range want {
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != content {
+			t.Errorf("the log file %s is incorrect. Expected: %q Actual: %q Error: %v", path, content, data, err)
+		}
+	}
+	if _, err := os.Stat(logPath + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 rotated log files to be kept")
+	}
+}
+
+func TestRotatingFileWriterMoveTo(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "output", "m2k.log")
+	movedLogPath := filepath.Join(dir, "output.m2k.log")
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		t.Fatalf("failed to create the output directory. Error: %q", err)
+	}
+	w, err := common.NewRotatingFileWriter(logPath, 0, 0)
+	if err != nil {
+		t.Fatalf("failed to open the log file. Error: %q", err)
+	}
+	defer w.Close()
+	if _, err := w.Write([]byte("before\n")); err != nil {
+		t.Fatalf("failed to write to the log file. Error: %q", err)
+	}
+	if err := w.MoveTo(movedLogPath); err != nil {
+		t.Fatalf("failed to move the log file. Error: %q", err)
+	}
+	if err := os.RemoveAll(filepath.Dir(logPath)); err != nil {
+		t.Fatalf("failed to remove the output directory. Error: %q", err)
+	}
+	if _, err := w.Write([]byte("after\n")); err != nil {
+		t.Fatalf("failed to write to the moved log file. Error: %q", err)
+	}
+	if data, err := os.ReadFile(movedLogPath); err != nil || string(data) != "after\n" {
+		t.Errorf("the moved log file is incorrect. Expected: %q Actual: %q Error: %v", "after\n", data, err)
+	}
+}
+
+func TestLevelFilterFormatter(t *testing.T) {
+	console := &bytes.Buffer{}
+	file := &bytes.Buffer{}
+	logger := logrus.New()
+	logger.SetOutput(console)
+	logger.SetLevel(logrus.DebugLevel)
+	logger.SetFormatter(&common.LevelFilterFormatter{Formatter: &common.PlainFormatter{}, Level: logrus.InfoLevel})
+	logger.AddHook(common.NewLogFileHook(file, &common.PlainFormatter{}, logrus.DebugLevel))
+	logger.Debug("verbose")
+	logger.Info("normal")
+	if console.String() != "INFO: normal\n" {
+		t.Errorf("the console should only have the info logs. Actual: %q", console.String())
+	}
+	if !strings.Contains(file.String(), "DEBUG: verbose") || !strings.Contains(file.String(), "INFO: normal") {
+		t.Errorf("the log file should have the debug and info logs. Actual: %q", file.String())
+	}
+}
@@ -18,6 +18,9 @@ package common
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 )
@@ -45,3 +48,131 @@ func (hook *CleanupHook) Levels() []logrus.Level {
 		logrus.FatalLevel,
 	}
 }
+
+// MessageCollectorHook collects the warnings and errors that are logged, to summarize them at the end
+type MessageCollectorHook struct {
+	mutex    sync.Mutex
+	messages []string
+}
+
+// NewMessageCollectorHook creates a hook that collects the warnings and errors
+func NewMessageCollectorHook() *MessageCollectorHook {
+	return &MessageCollectorHook{messages: []string{}}
+}
+
+// Fire collects the message
+func (hook *MessageCollectorHook) Fire(entry *logrus.Entry) error {
+	hook.mutex.Lock()
+	defer hook.mutex.Unlock()
+	hook.messages = append(hook.messages, fmt.Sprintf("%s: %s", entry.Level, entry.Message))
+	return nil
+}
+
+// Levels returns the levels on which the messages are collected
+func (hook *MessageCollectorHook) Levels() []logrus.Level {
+	return []logrus.Level{
+		logrus.ErrorLevel,
+		logrus.WarnLevel,
+	}
+}
+
+// GetMessages returns the messages collected so far
+func (hook *MessageCollectorHook) GetMessages() []string {
+	hook.mutex.Lock()
+	defer hook.mutex.Unlock()
+	return append([]string{}, hook.messages...)
+}
+
+// Reset clears the messages collected so far
+func (hook *MessageCollectorHook) Reset() {
+	hook.mutex.Lock()
+	defer hook.mutex.Unlock()
+	hook.messages = []string{}
+}
+
+// PlainFormatter formats the log entries as plain lines of text without colors, timestamps or quoting,
+// which is easier to follow with a screen reader
+type PlainFormatter struct {
+}
+
+// Format formats the log entry as the level followed by the message
+func (*PlainFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	return []byte(fmt.Sprintf("%s: %s\n", strings.ToUpper(entry.Level.String()), entry.Message)), nil
+}
+
+// LogContext is what is being processed when a message is logged. It is added as fields to the structured logs.
+type LogContext struct {
+	Transformer string
+	Services    []string
+	Artifacts   []string
+}
+
+var (
+	// logContexts are the log contexts of the goroutines, keyed by the goroutine id, since the transformers running in parallel process different things
+	logContexts     = map[uint64]LogContext{}
+	logPhase        ProgressPhaseT
+	logContextMutex sync.Mutex
+)
+
+// SetLogContext sets what is being processed by the calling goroutine. An empty LogContext clears it.
+// The goroutines started by RunInParallel get the log context of the caller, the other goroutines start without one.
+func SetLogContext(c LogContext) {
+	id := GoroutineID()
+	logContextMutex.Lock()
+	defer logContextMutex.Unlock()
+	if c.Transformer == "" && len(c.Services) == 0 && len(c.Artifacts) == 0 {
+		delete(logContexts, id)
+		return
+	}
+	logContexts[id] = c
+}
+
+// getLogContext returns the log context of the calling goroutine
+func getLogContext() LogContext {
+	id := GoroutineID()
+	logContextMutex.Lock()
+	defer logContextMutex.Unlock()
+	return logContexts[id]
+}
+
+func setLogPhase(phase ProgressPhaseT) {
+	logContextMutex.Lock()
+	defer logContextMutex.Unlock()
+	logPhase = phase
+}
+
+// LogContextHook adds the phase along with the transformer, services and artifacts being processed as fields to the log entries
+type LogContextHook struct {
+}
+
+// NewLogContextHook creates a hook that adds the log context to the log entries
+func NewLogContextHook() *LogContextHook {
+	return &LogContextHook{}
+}
+
+// Fire adds the fields that are set and are not already present in the log entry.
+// The hooks are fired in the goroutine that logs, so the fields are those of the log context of that goroutine.
+func (*LogContextHook) Fire(entry *logrus.Entry) error {
+	id := GoroutineID()
+	logContextMutex.Lock()
+	c, phase := logContexts[id], logPhase
+	logContextMutex.Unlock()
+	fields := map[string]string{
+		"phase":       string(phase),
+		"transformer": c.Transformer,
+		"service":     strings.Join(c.Services, ","),
+		"artifact":    strings.Join(c.Artifacts, ","),
+	}
+	for key, value := range fields {
+		if _, ok := entry.Data[key]; ok || value == "" {
+			continue
+		}
+		entry.Data[key] = value
+	}
+	return nil
+}
+
+// Levels returns the levels on which the log context is added
+func (*LogContextHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
@@ -0,0 +1,99 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/sirupsen/logrus"
+)
+
+func TestLogContextHook(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := logrus.New()
+	logger.SetOutput(buf)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.AddHook(common.NewLogContextHook())
+	common.SetProgressPhase(common.ProgressPhaseTransforming)
+	common.SetLogContext(common.LogContext{Transformer: "Kubernetes", Services: []string{"web", "api"}, Artifacts: []string{"web"}})
+	defer common.SetLogContext(common.LogContext{})
+	logger.WithField("service", "overridden").Info("transforming")
+	entry := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to parse the log entry as json. Error: %q", err)
+	}
+	want := map[string]string{"msg": "transforming", "phase": "transforming", "transformer": "Kubernetes", "service": "overridden", "artifact": "web"}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("the field %s is incorrect. Expected: %s Actual: %v", key, value, entry[key])
+		}
+	}
+	buf.Reset()
+	common.SetLogContext(common.LogContext{})
+	logger.Info("done")
+	entry = map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to parse the log entry as json. Error: %q", err)
+	}
+	if _, ok := entry["transformer"]; ok {
+		t.Errorf("expected no transformer field after the log context is cleared. Actual: %+v", entry)
+	}
+}
+
+func TestLogContextHookInParallel(t *testing.T) {
+	if err := common.SetParallelism(4); err != nil {
+		t.Fatalf("failed to set the number of workers. Error: %q", err)
+	}
+	defer common.SetParallelism(1)
+	buf := &bytes.Buffer{}
+	logger := logrus.New()
+	logger.SetOutput(buf)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.AddHook(common.NewLogContextHook())
+	common.SetLogContext(common.LogContext{Services: []string{"web"}})
+	defer common.SetLogContext(common.LogContext{})
+	transformers := []string{"Kubernetes", "Knative", "Tekton", "Buildconfig", "ComposeAnalyser", "DockerfileDetector"}
+	common.RunInParallel(len(transformers), func(i int) {
+		logger.Info("started")
+		common.SetLogContext(common.LogContext{Transformer: transformers[i], Services: []string{"web"}})
+		for j := 0; j < 10; j++ {
+			time.Sleep(time.Millisecond)
+			logger.Info(transformers[i])
+		}
+	})
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(transformers)*11 {
+		t.Fatalf("expected %d log entries. Actual: %d", len(transformers)*11, len(lines))
+	}
+	for _, line := range lines {
+		entry := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("failed to parse the log entry as json. Error: %q", err)
+		}
+		if entry["service"] != "web" {
+			t.Errorf("expected the log context of the caller to be inherited. Actual: %+v", entry)
+		}
+		if entry["msg"] != "started" && entry["transformer"] != entry["msg"] {
+			t.Errorf("the log entry has the log context of another goroutine. Actual: %+v", entry)
+		}
+	}
+}
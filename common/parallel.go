@@ -0,0 +1,92 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+var (
+	// parallelWorkers holds a token for each worker running besides the callers of RunInParallel
+	parallelWorkers      = make(chan struct{})
+	parallelism          = 1
+	parallelWorkersMutex sync.Mutex
+)
+
+// SetParallelism sets the number of workers shared by the directory detection, the container builds and the transformers.
+// With a single worker everything runs one after the other.
+func SetParallelism(workers int) error {
+	if workers < 1 {
+		return fmt.Errorf("the number of workers %d must be at least 1", workers)
+	}
+	parallelWorkersMutex.Lock()
+	defer parallelWorkersMutex.Unlock()
+	parallelism = workers
+	parallelWorkers = make(chan struct{}, workers-1)
+	return nil
+}
+
+// GetParallelism returns the number of workers shared by the directory detection, the container builds and the transformers
+func GetParallelism() int {
+	parallelWorkersMutex.Lock()
+	defer parallelWorkersMutex.Unlock()
+	return parallelism
+}
+
+// RunInParallel calls fn for each index from 0 to n-1 and waits for all the calls to finish.
+// The calls run in new goroutines while there are free workers, otherwise in the calling goroutine, which also counts
+// as a worker. So the number of concurrent calls stays bounded even when RunInParallel is called from inside fn.
+// The new goroutines start with the log context of the calling goroutine.
+func RunInParallel(n int, fn func(i int)) {
+	parallelWorkersMutex.Lock()
+	workers := parallelWorkers
+	parallelWorkersMutex.Unlock()
+	logContext := getLogContext()
+	wg := sync.WaitGroup{}
+	for i := 0; i < n; i++ {
+		select {
+		case workers <- struct{}{}:
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer func() { <-workers }()
+				SetLogContext(logContext)
+				defer SetLogContext(LogContext{})
+				fn(i)
+			}(i)
+		default:
+			fn(i)
+		}
+	}
+	wg.Wait()
+}
+
+// GoroutineID returns the id of the calling goroutine, which is parsed from the header of its stack trace, like "goroutine 18 [running]:".
+// It is used to keep the state of the goroutines running in parallel separate, like what they are logging about.
+func GoroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = bytes.TrimPrefix(buf[:runtime.Stack(buf, false)], []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i >= 0 {
+		buf = buf[:i]
+	}
+	id, _ := strconv.ParseUint(string(buf), 10, 64)
+	return id
+}
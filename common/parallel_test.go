@@ -0,0 +1,69 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunInParallel(t *testing.T) {
+	defer SetParallelism(1)
+	if err := SetParallelism(0); err == nil {
+		t.Fatalf("expected an error for zero workers")
+	}
+	for _, workers := range []int{1, 3} {
+		if err := SetParallelism(workers); err != nil {
+			t.Fatalf("failed to set the number of workers to %d . Error: %q", workers, err)
+		}
+		mutex := sync.Mutex{}
+		running, maxRunning := 0, 0
+		called := map[int]int{}
+		task := func(i int) {
+			mutex.Lock()
+			called[i]++
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mutex.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+		}
+		// the nested calls must neither deadlock nor go over the number of workers
+		RunInParallel(4, func(i int) {
+			RunInParallel(3, func(j int) { task(i*3 + j) })
+		})
+		if len(called) != 12 {
+			t.Fatalf("expected all the 12 tasks to be called with %d workers. Actual: %+v", workers, called)
+		}
+		for i, count := range called {
+			if count != 1 {
+				t.Fatalf("expected the task %d to be called once with %d workers. Actual: %d", i, workers, count)
+			}
+		}
+		if maxRunning > workers {
+			t.Fatalf("expected at most %d tasks to run at the same time. Actual: %d", workers, maxRunning)
+		}
+		if workers > 1 && maxRunning < 2 {
+			t.Fatalf("expected the tasks to run in parallel with %d workers. Actual: %d", workers, maxRunning)
+		}
+	}
+}
@@ -25,6 +25,8 @@ import (
 const (
 	// DefaultPlanFile is the default name for the plan file
 	DefaultPlanFile = types.AppNameShort + ".plan"
+	// DefaultLogFile is the name of the log file written inside the output directory
+	DefaultLogFile = types.AppNameShort + ".log"
 	// DefaultConfigFilePath is the default config file path
 	DefaultConfigFilePath = types.AppNameShort + "-default-config.yaml"
 	// DefaultCustomizationDir is the default path for the customization directory
@@ -33,6 +35,10 @@ const (
 	TempDirPrefix = types.AppNameShort + "-"
 	// AssetsDir defines the dir of the assets temp directory
 	AssetsDir = types.AppNameShort + "assets"
+	// CustomAssetsDir defines the dir inside the assets directory where the customizations are copied to
+	CustomAssetsDir = "custom"
+	// RemoteDir defines the dir of the temp directory where remote sources and customizations are fetched into
+	RemoteDir = types.AppNameShort + "remote"
 
 	// ScriptsDir defines the directory where the output scripts are placed
 	ScriptsDir = "scripts"
@@ -42,6 +48,8 @@ const (
 	DeployDir = "deploy"
 	// CICDDir defines the directory where the deployment artifacts are placed
 	CICDDir = "cicd"
+	// SBOMDir defines the directory where the SBOMs of the images are placed
+	SBOMDir = "sbom"
 	// HelmDir defines the directory where the helm charts are placed
 	HelmDir = "helm-charts"
 	// OCTemplatesDir defines the directory where the openshift templates are placed
@@ -54,3 +62,8 @@ var (
 	// AssetsPath defines where all assets get stored during execution
 	AssetsPath = filepath.Join(TempPath, AssetsDir)
 )
+
+// GetCustomizationsAssetsPath returns the directory where the customizations are copied to during execution
+func GetCustomizationsAssetsPath() string {
+	return filepath.Join(AssetsPath, CustomAssetsDir)
+}
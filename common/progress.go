@@ -0,0 +1,133 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ProgressPhaseT is the phase a plan or transform is in
+type ProgressPhaseT string
+
+const (
+	// ProgressPhaseInitializing is the phase where the transformers are being initialized
+	ProgressPhaseInitializing ProgressPhaseT = "initializing"
+	// ProgressPhasePlanning is the phase where the services are being detected
+	ProgressPhasePlanning ProgressPhaseT = "planning"
+	// ProgressPhaseTransforming is the phase where the transformers are being run on the artifacts
+	ProgressPhaseTransforming ProgressPhaseT = "transforming"
+	// ProgressPhasePostprocessing is the phase where the output is being postprocessed
+	ProgressPhasePostprocessing ProgressPhaseT = "postprocessing"
+	// ProgressPhaseDone means the plan or transform has finished
+	ProgressPhaseDone ProgressPhaseT = "done"
+)
+
+// TransformerStatusT is the status of a transformer during a plan or transform
+type TransformerStatusT string
+
+const (
+	// TransformerRunning means the transformer is running
+	TransformerRunning TransformerStatusT = "running"
+	// TransformerDone means the transformer finished successfully the last time it ran
+	TransformerDone TransformerStatusT = "done"
+	// TransformerFailed means the transformer failed the last time it ran
+	TransformerFailed TransformerStatusT = "failed"
+)
+
+// ProgressT is the progress of a plan or transform
+type ProgressT struct {
+	Phase        ProgressPhaseT                `json:"phase"`
+	Transformers map[string]TransformerStatusT `json:"transformers,omitempty"`
+}
+
+var (
+	progress      = ProgressT{Transformers: map[string]TransformerStatusT{}}
+	progressFile  = ""
+	progressMutex sync.Mutex
+)
+
+// SetProgressFile sets the file where the progress is written to every time it changes
+func SetProgressFile(path string) {
+	progressMutex.Lock()
+	defer progressMutex.Unlock()
+	progressFile = path
+	writeProgress()
+}
+
+// SetProgressPhase updates the phase of the progress
+func SetProgressPhase(phase ProgressPhaseT) {
+	progressMutex.Lock()
+	defer progressMutex.Unlock()
+	progress.Phase = phase
+	setLogPhase(phase)
+	writeProgress()
+}
+
+// SetTransformerProgress updates the status of a transformer in the progress
+func SetTransformerProgress(transformerName string, status TransformerStatusT) {
+	progressMutex.Lock()
+	defer progressMutex.Unlock()
+	progress.Transformers[transformerName] = status
+	writeProgress()
+}
+
+// GetProgress returns a copy of the current progress
+func GetProgress() ProgressT {
+	progressMutex.Lock()
+	defer progressMutex.Unlock()
+	p := ProgressT{Phase: progress.Phase, Transformers: map[string]TransformerStatusT{}}
+	for name, status := range progress.Transformers {
+		p.Transformers[name] = status
+	}
+	return p
+}
+
+// ReadProgressFile reads the progress written by another process
+func ReadProgressFile(path string) (ProgressT, error) {
+	p := ProgressT{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return p, err
+	}
+	err = json.Unmarshal(data, &p)
+	return p, err
+}
+
+// writeProgress writes the progress to the progress file. The caller must hold the lock.
+func writeProgress() {
+	if progressFile == "" {
+		return
+	}
+	data, err := json.Marshal(progress)
+	if err != nil {
+		logrus.Debugf("failed to marshal the progress to json. Error: %q", err)
+		return
+	}
+	// write to a temporary file and rename so that readers never see a partially written file
+	tempFile := progressFile + ".tmp"
+	if err := os.WriteFile(tempFile, data, DefaultFilePermission); err != nil {
+		logrus.Debugf("failed to write the progress to the file %s . Error: %q", tempFile, err)
+		return
+	}
+	if err := os.Rename(tempFile, progressFile); err != nil {
+		logrus.Debugf("failed to write the progress to the file %s . Error: %q", progressFile, err)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// SkippedPath is a file or directory in the source that was skipped since it is over the source limits
+type SkippedPath struct {
+	Path   string
+	Reason string
+}
+
+var (
+	maxSourceFileSize       int64
+	maxSourceDirectoryFiles int
+	skippedPaths            []SkippedPath
+	skippedPathsSet         = map[string]bool{}
+	sourceLimitsMutex       sync.Mutex
+)
+
+// SetSourceLimits sets the size (like 100Mi) above which files are skipped and the number of entries above which
+// directories are skipped while detecting services and copying the source. An empty size or a zero count disables that limit.
+func SetSourceLimits(maxFileSize string, maxDirectoryFiles int) error {
+	maxFileSizeBytes := int64(0)
+	if maxFileSize != "" {
+		quantity, err := resource.ParseQuantity(maxFileSize)
+		if err != nil {
+			return fmt.Errorf("failed to parse the maximum file size %s . Error: %w", maxFileSize, err)
+		}
+		maxFileSizeBytes = quantity.Value()
+	}
+	if maxFileSizeBytes < 0 {
+		return fmt.Errorf("the maximum file size %s cannot be negative", maxFileSize)
+	}
+	if maxDirectoryFiles < 0 {
+		return fmt.Errorf("the maximum number of files in a directory %d cannot be negative", maxDirectoryFiles)
+	}
+	sourceLimitsMutex.Lock()
+	defer sourceLimitsMutex.Unlock()
+	maxSourceFileSize = maxFileSizeBytes
+	maxSourceDirectoryFiles = maxDirectoryFiles
+	return nil
+}
+
+// GetSourceLimits returns the size in bytes above which files are skipped and the number of entries above which directories are skipped
+func GetSourceLimits() (maxFileSize int64, maxDirectoryFiles int) {
+	sourceLimitsMutex.Lock()
+	defer sourceLimitsMutex.Unlock()
+	return maxSourceFileSize, maxSourceDirectoryFiles
+}
+
+// IsOverSourceLimits returns true if the file or directory is over the source limits and records it as skipped
+func IsOverSourceLimits(path string, info fs.FileInfo) bool {
+	maxFileSize, maxDirectoryFiles := GetSourceLimits()
+	if info.IsDir() {
+		if maxDirectoryFiles == 0 {
+			return false
+		}
+		numFiles, err := countDirectoryEntries(path, maxDirectoryFiles+1)
+		if err != nil {
+			logrus.Debugf("failed to count the files in the directory %s . Error: %q", path, err)
+			return false
+		}
+		if numFiles <= maxDirectoryFiles {
+			return false
+		}
+		reportSkippedPath(path, fmt.Sprintf("the directory has more than %d files", maxDirectoryFiles))
+		return true
+	}
+	if maxFileSize == 0 || !info.Mode().IsRegular() || info.Size() <= maxFileSize {
+		return false
+	}
+	reportSkippedPath(path, fmt.Sprintf("the file size %d bytes is more than the maximum of %d bytes", info.Size(), maxFileSize))
+	return true
+}
+
+// GetSkippedPaths returns the files and directories that were skipped so far since they are over the source limits
+func GetSkippedPaths() []SkippedPath {
+	sourceLimitsMutex.Lock()
+	defer sourceLimitsMutex.Unlock()
+	return append([]SkippedPath{}, skippedPaths...)
+}
+
+func reportSkippedPath(path, reason string) {
+	sourceLimitsMutex.Lock()
+	defer sourceLimitsMutex.Unlock()
+	if skippedPathsSet[path] {
+		return
+	}
+	skippedPathsSet[path] = true
+	skippedPaths = append(skippedPaths, SkippedPath{Path: path, Reason: reason})
+	logrus.Infof("Skipped %s since %s", path, reason)
+}
+
+// countDirectoryEntries counts the entries in the directory, stopping at the limit
+func countDirectoryEntries(path string, limit int) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	names, err := f.Readdirnames(limit)
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	return len(names), nil
+}
+
+// skipPathsOverSourceLimits wraps the walk function to skip the files and directories below the root that are over the source limits
+func skipPathsOverSourceLimits(root string, fn fs.WalkDirFunc) fs.WalkDirFunc {
+	if maxFileSize, maxDirectoryFiles := GetSourceLimits(); maxFileSize == 0 && maxDirectoryFiles == 0 {
+		return fn
+	}
+	return func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d == nil || path == root {
+			return fn(path, d, err)
+		}
+		if info, infoErr := d.Info(); infoErr == nil && IsOverSourceLimits(path, info) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return fn(path, d, err)
+	}
+}
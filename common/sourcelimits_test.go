@@ -0,0 +1,105 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetFilesByExtSourceLimits(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite := func(path string, size int) {
+		if err := os.MkdirAll(filepath.Dir(path), DefaultDirectoryPermission); err != nil {
+			t.Fatalf("failed to create the directory for %s . Error: %q", path, err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), DefaultFilePermission); err != nil {
+			t.Fatalf("failed to write the file %s . Error: %q", path, err)
+		}
+	}
+	mustWrite(filepath.Join(dir, "small.yaml"), 10)
+	mustWrite(filepath.Join(dir, "large.yaml"), 2048)
+	mustWrite(filepath.Join(dir, "app", "a.yaml"), 10)
+	for i := 0; i < 5; i++ {
+		mustWrite(filepath.Join(dir, "dataset", fmt.Sprintf("%d.yaml", i)), 10)
+	}
+
+	testcases := []struct {
+		name              string
+		maxFileSize       string
+		maxDirectoryFiles int
+		want              []string
+		skipped           []string
+	}{
+		{name: "no limits", want: []string{"app/a.yaml", "dataset/0.yaml", "dataset/1.yaml", "dataset/2.yaml", "dataset/3.yaml", "dataset/4.yaml", "large.yaml", "small.yaml"}},
+		{name: "file size", maxFileSize: "1Ki", want: []string{"app/a.yaml", "dataset/0.yaml", "dataset/1.yaml", "dataset/2.yaml", "dataset/3.yaml", "dataset/4.yaml", "small.yaml"}, skipped: []string{"large.yaml"}},
+		{name: "directory files", maxDirectoryFiles: 4, want: []string{"app/a.yaml", "large.yaml", "small.yaml"}, skipped: []string{"dataset"}},
+	}
+	defer SetSourceLimits("", 0)
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := SetSourceLimits(tc.maxFileSize, tc.maxDirectoryFiles); err != nil {
+				t.Fatalf("failed to set the source limits. Error: %q", err)
+			}
+			sourceLimitsMutex.Lock()
+			skippedPaths = nil
+			skippedPathsSet = map[string]bool{}
+			sourceLimitsMutex.Unlock()
+			paths, err := GetFilesByExt(dir, []string{".yaml"})
+			if err != nil {
+				t.Fatalf("failed to get the files. Error: %q", err)
+			}
+			got := []string{}
+			for _, path := range paths {
+				relPath, err := filepath.Rel(dir, path)
+				if err != nil {
+					t.Fatalf("failed to make the path %s relative. Error: %q", path, err)
+				}
+				got = append(got, filepath.ToSlash(relPath))
+			}
+			sort.Strings(got)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected files. Difference:\n%s", diff)
+			}
+			gotSkipped := []string{}
+			for _, skipped := range GetSkippedPaths() {
+				gotSkipped = append(gotSkipped, filepath.Base(skipped.Path))
+			}
+			if len(tc.skipped) == 0 {
+				tc.skipped = []string{}
+			}
+			if diff := cmp.Diff(tc.skipped, gotSkipped); diff != "" {
+				t.Fatalf("unexpected skipped paths. Difference:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetSourceLimitsInvalid(t *testing.T) {
+	defer SetSourceLimits("", 0)
+	if err := SetSourceLimits("lots", 0); err == nil {
+		t.Fatalf("expected an error for an invalid maximum file size")
+	}
+	if err := SetSourceLimits("", -1); err == nil {
+		t.Fatalf("expected an error for a negative maximum number of files")
+	}
+}
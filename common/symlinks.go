@@ -0,0 +1,199 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SymlinkPolicyT decides how symbolic links are handled while walking the source and copying files
+type SymlinkPolicyT string
+
+const (
+	// PreserveSymlinks copies symbolic links as links and does not descend into linked directories
+	PreserveSymlinks SymlinkPolicyT = "preserve"
+	// FollowSymlinks treats symbolic links as the files and directories they point to, skipping the links that form a cycle
+	FollowSymlinks SymlinkPolicyT = "follow"
+	// SkipSymlinks ignores symbolic links and reports them as warnings
+	SkipSymlinks SymlinkPolicyT = "skip"
+)
+
+// SymlinkPolicies are the supported symbolic link policies
+var SymlinkPolicies = []SymlinkPolicyT{FollowSymlinks, PreserveSymlinks, SkipSymlinks}
+
+// SkippedSymlink is a symbolic link that was not walked or copied
+type SkippedSymlink struct {
+	Path   string
+	Target string
+	Reason string
+}
+
+var (
+	symlinkPolicy        = FollowSymlinks
+	skippedSymlinks      []SkippedSymlink
+	skippedSymlinksPaths = map[string]bool{}
+	symlinksMutex        sync.Mutex
+)
+
+// SetSymlinkPolicy sets how symbolic links are handled while walking the source and copying files
+func SetSymlinkPolicy(policy SymlinkPolicyT) error {
+	policy = SymlinkPolicyT(strings.ToLower(string(policy)))
+	if policy == "" {
+		policy = FollowSymlinks
+	}
+	if !IsPresent(SymlinkPolicies, policy) {
+		return fmt.Errorf("the symlink policy %s is not supported. Supported policies are %+v", policy, SymlinkPolicies)
+	}
+	symlinksMutex.Lock()
+	defer symlinksMutex.Unlock()
+	symlinkPolicy = policy
+	return nil
+}
+
+// GetSymlinkPolicy returns how symbolic links are handled while walking the source and copying files
+func GetSymlinkPolicy() SymlinkPolicyT {
+	symlinksMutex.Lock()
+	defer symlinksMutex.Unlock()
+	return symlinkPolicy
+}
+
+// ReportSkippedSymlink records a symbolic link that was not walked or copied and logs it as a warning once
+func ReportSkippedSymlink(path, reason string) {
+	target, _ := os.Readlink(path)
+	symlinksMutex.Lock()
+	defer symlinksMutex.Unlock()
+	if skippedSymlinksPaths[path] {
+		return
+	}
+	skippedSymlinksPaths[path] = true
+	skippedSymlinks = append(skippedSymlinks, SkippedSymlink{Path: path, Target: target, Reason: reason})
+	logrus.Warnf("Skipped the symbolic link %s -> %s since %s", path, target, reason)
+}
+
+// GetSkippedSymlinks returns the symbolic links that were not walked or copied so far
+func GetSkippedSymlinks() []SkippedSymlink {
+	symlinksMutex.Lock()
+	defer symlinksMutex.Unlock()
+	return append([]SkippedSymlink{}, skippedSymlinks...)
+}
+
+// IsSymlinkCycle returns true if the directory the link points to contains any of the directories being walked.
+// Following such a link would walk the same directories again forever.
+func IsSymlinkCycle(linkPath string, walkedDirs []string) bool {
+	target, err := filepath.EvalSymlinks(linkPath)
+	if err != nil {
+		return false
+	}
+	if target, err = filepath.Abs(target); err != nil {
+		return false
+	}
+	for _, walkedDir := range walkedDirs {
+		absWalkedDir, err := filepath.Abs(walkedDir)
+		if err != nil {
+			continue
+		}
+		if target == absWalkedDir || IsParent(absWalkedDir, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// WalkDir walks the directory tree like filepath.WalkDir while handling the symbolic links using the symlink policy.
+// With the follow policy the paths passed to the function are inside the linked directories as seen from the root.
+// The files and directories over the source limits are skipped.
+func WalkDir(root string, fn fs.WalkDirFunc) error {
+	fn = skipPathsOverSourceLimits(root, fn)
+	switch GetSymlinkPolicy() {
+	case SkipSymlinks:
+		return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && path != root && d.Type()&fs.ModeSymlink != 0 {
+				ReportSkippedSymlink(path, "the symlink policy is "+string(SkipSymlinks))
+				return nil
+			}
+			return fn(path, d, err)
+		})
+	case FollowSymlinks:
+		realRoot, err := filepath.EvalSymlinks(root)
+		if err != nil {
+			return fn(root, nil, err)
+		}
+		rootInfo, err := os.Stat(realRoot)
+		if err != nil {
+			return fn(root, nil, err)
+		}
+		if err := fn(root, fs.FileInfoToDirEntry(rootInfo), nil); err != nil || !rootInfo.IsDir() {
+			if err == filepath.SkipDir {
+				return nil
+			}
+			return err
+		}
+		return walkDirFollowingSymlinks(realRoot, root, []string{realRoot}, fn)
+	}
+	return filepath.WalkDir(root, fn)
+}
+
+// walkDirFollowingSymlinks walks the real directory and reports the paths relative to the logical directory
+func walkDirFollowingSymlinks(realDir, logicalDir string, walkedDirs []string, fn fs.WalkDirFunc) error {
+	return filepath.WalkDir(realDir, func(path string, d fs.DirEntry, err error) error {
+		if path == realDir {
+			// the directory itself has already been passed to the function
+			if err != nil {
+				return fn(logicalDir, d, err)
+			}
+			return nil
+		}
+		relPath, relErr := filepath.Rel(realDir, path)
+		if relErr != nil {
+			return relErr
+		}
+		logicalPath := filepath.Join(logicalDir, relPath)
+		if err != nil || d.Type()&fs.ModeSymlink == 0 {
+			return fn(logicalPath, d, err)
+		}
+		targetInfo, statErr := os.Stat(path)
+		if statErr != nil {
+			ReportSkippedSymlink(logicalPath, "the target does not exist")
+			return nil
+		}
+		if !targetInfo.IsDir() {
+			return fn(logicalPath, fs.FileInfoToDirEntry(targetInfo), nil)
+		}
+		if IsSymlinkCycle(path, walkedDirs) {
+			ReportSkippedSymlink(logicalPath, "it forms a cycle")
+			return nil
+		}
+		if err := fn(logicalPath, fs.FileInfoToDirEntry(targetInfo), nil); err != nil {
+			if err == filepath.SkipDir {
+				return nil
+			}
+			return err
+		}
+		realTarget, evalErr := filepath.EvalSymlinks(path)
+		if evalErr != nil {
+			return fn(logicalPath, nil, evalErr)
+		}
+		return walkDirFollowingSymlinks(realTarget, logicalPath, append(append([]string{}, walkedDirs...), realTarget), fn)
+	})
+}
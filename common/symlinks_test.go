@@ -0,0 +1,122 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetFilesByExtSymlinkPolicies(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite := func(path string) {
+		if err := os.MkdirAll(filepath.Dir(path), DefaultDirectoryPermission); err != nil {
+			t.Fatalf("failed to create the directory for %s . Error: %q", path, err)
+		}
+		if err := os.WriteFile(path, []byte("a: b\n"), DefaultFilePermission); err != nil {
+			t.Fatalf("failed to write the file %s . Error: %q", path, err)
+		}
+	}
+	mustLink := func(target, link string) {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("symbolic links are not supported. Error: %q", err)
+		}
+	}
+	mustWrite(filepath.Join(dir, "src", "a.yaml"))
+	mustWrite(filepath.Join(dir, "shared", "b.yaml"))
+	mustLink(filepath.Join(dir, "shared"), filepath.Join(dir, "src", "shared"))
+	mustLink("a.yaml", filepath.Join(dir, "src", "c.yaml"))
+	mustLink("..", filepath.Join(dir, "src", "loop"))
+	mustLink("missing.yaml", filepath.Join(dir, "src", "broken.yaml"))
+	root := filepath.Join(dir, "src")
+
+	testcases := []struct {
+		policy  SymlinkPolicyT
+		want    []string
+		skipped []string
+	}{
+		{policy: PreserveSymlinks, want: []string{"a.yaml", "broken.yaml", "c.yaml"}},
+		{policy: FollowSymlinks, want: []string{"a.yaml", "c.yaml", "shared/b.yaml"}, skipped: []string{"broken.yaml", "loop"}},
+		{policy: SkipSymlinks, want: []string{"a.yaml"}, skipped: []string{"broken.yaml", "c.yaml", "loop", "shared"}},
+	}
+	defer SetSymlinkPolicy(FollowSymlinks)
+	for _, tc := range testcases {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			if err := SetSymlinkPolicy(tc.policy); err != nil {
+				t.Fatalf("failed to set the symlink policy. Error: %q", err)
+			}
+			symlinksMutex.Lock()
+			skippedSymlinks = nil
+			skippedSymlinksPaths = map[string]bool{}
+			symlinksMutex.Unlock()
+			paths, err := GetFilesByExt(root, []string{".yaml"})
+			if err != nil {
+				t.Fatalf("failed to get the files. Error: %q", err)
+			}
+			got := []string{}
+			for _, path := range paths {
+				relPath, err := filepath.Rel(root, path)
+				if err != nil {
+					t.Fatalf("failed to make the path %s relative. Error: %q", path, err)
+				}
+				got = append(got, filepath.ToSlash(relPath))
+			}
+			sort.Strings(got)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected files. Difference:\n%s", diff)
+			}
+			gotSkipped := []string{}
+			for _, skipped := range GetSkippedSymlinks() {
+				gotSkipped = append(gotSkipped, filepath.Base(skipped.Path))
+			}
+			sort.Strings(gotSkipped)
+			if len(tc.skipped) == 0 {
+				tc.skipped = []string{}
+			}
+			if diff := cmp.Diff(tc.skipped, gotSkipped); diff != "" {
+				t.Fatalf("unexpected skipped symlinks. Difference:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetSymlinkPolicyInvalid(t *testing.T) {
+	defer SetSymlinkPolicy(FollowSymlinks)
+	if err := SetSymlinkPolicy("sometimes"); err == nil {
+		t.Fatalf("expected an error for an invalid symlink policy")
+	}
+	if got := GetSymlinkPolicy(); got != FollowSymlinks {
+		t.Fatalf("expected the policy to stay %s . Actual: %s", FollowSymlinks, got)
+	}
+}
+
+func TestSetSymlinkPolicyDefault(t *testing.T) {
+	defer SetSymlinkPolicy(FollowSymlinks)
+	if err := SetSymlinkPolicy(PreserveSymlinks); err != nil {
+		t.Fatalf("failed to set the symlink policy. Error: %q", err)
+	}
+	if err := SetSymlinkPolicy(""); err != nil {
+		t.Fatalf("failed to reset the symlink policy. Error: %q", err)
+	}
+	if got := GetSymlinkPolicy(); got != FollowSymlinks {
+		t.Fatalf("expected the default policy to be %s . Actual: %s", FollowSymlinks, got)
+	}
+}
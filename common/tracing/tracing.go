@@ -0,0 +1,104 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/konveyor/move2kube/types"
+	"github.com/konveyor/move2kube/types/info"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName      = "github.com/konveyor/move2kube"
+	shutdownTimeout = 10 * time.Second
+)
+
+// otlpEndpointEnvVars are the standard environment variables used to configure the OTLP exporter
+var otlpEndpointEnvVars = []string{"OTEL_EXPORTER_OTLP_ENDPOINT", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"}
+
+// Setup exports the spans to an OTLP collector over gRPC.
+// The endpoint is a host:port. If it is empty, the standard OTEL_EXPORTER_OTLP_* environment variables are used.
+// If neither are set, the spans are not recorded.
+// The returned function flushes the remaining spans and must be called before exiting.
+func Setup(ctx context.Context, endpoint string, insecure bool) (func(), error) {
+	if endpoint == "" && !isEndpointSetInEnv() {
+		return func() {}, nil
+	}
+	opts := []otlptracegrpc.Option{}
+	if endpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(endpoint))
+	}
+	if insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return func() {}, fmt.Errorf("failed to create the OTLP trace exporter. Error: %w", err)
+	}
+	res := resource.NewSchemaless(
+		attribute.String("service.name", types.AppName),
+		attribute.String("service.version", info.GetVersion()),
+	)
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	logrus.Debugf("exporting the traces using OTLP")
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			logrus.Errorf("failed to flush the traces. Error: %q", err)
+		}
+	}, nil
+}
+
+func isEndpointSetInEnv() bool {
+	for _, envVar := range otlpEndpointEnvVars {
+		if os.Getenv(envVar) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Start starts a span that is a child of the span in the context, if any
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End marks the span as failed if there is an error and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,66 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetupWithoutEndpoint(t *testing.T) {
+	for _, envVar := range otlpEndpointEnvVars {
+		t.Setenv(envVar, "")
+	}
+	shutdown, err := Setup(context.Background(), "", false)
+	if err != nil {
+		t.Fatalf("expected no error when the endpoint is not configured. Error: %q", err)
+	}
+	shutdown()
+}
+
+func TestSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	oldProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(oldProvider)
+
+	ctx, parent := Start(context.Background(), "Transform")
+	_, child := Start(ctx, "RunTransformer")
+	End(child, errors.New("failed"))
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans. Actual: %d", len(spans))
+	}
+	if spans[0].Name() != "RunTransformer" || spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Fatalf("expected RunTransformer to be a child of Transform. Actual: %+v", spans)
+	}
+	if spans[0].Status().Code != codes.Error || len(spans[0].Events()) != 1 {
+		t.Fatalf("expected the error to be recorded on the span. Actual status: %+v", spans[0].Status())
+	}
+	if spans[1].Status().Code != codes.Unset {
+		t.Fatalf("expected the parent span to not be failed. Actual status: %+v", spans[1].Status())
+	}
+}
@@ -18,6 +18,7 @@ package common
 
 import (
 	"archive/tar"
+	"archive/zip"
 	"bytes"
 	"compress/gzip"
 	"crypto/sha256"
@@ -28,6 +29,7 @@ import (
 	"fmt"
 	"hash/crc64"
 	"io"
+	"io/fs"
 	"math"
 	"math/rand"
 	"net/url"
@@ -38,6 +40,7 @@ import (
 	"strconv"
 	"strings"
 	"text/template"
+	"time"
 	"unicode"
 
 	"github.com/Masterminds/sprig"
@@ -119,7 +122,7 @@ func GetFilesByExt(inputPath string, exts []string) ([]string, error) {
 	} else if !info.IsDir() {
 		logrus.Warnf("The path %q is not a directory.", inputPath)
 	}
-	err := filepath.WalkDir(inputPath, func(path string, info os.DirEntry, err error) error {
+	err := WalkDir(inputPath, func(path string, info os.DirEntry, err error) error {
 		if err != nil && path == inputPath { // if walk for root search path return gets error
 			// then stop walking and return this error
 			return err
@@ -206,7 +209,7 @@ func GetFilesByName(inputPath string, names []string, nameRegexes []string) ([]s
 		}
 		compiledNameRegexes = append(compiledNameRegexes, compiledNameRegex)
 	}
-	err := filepath.WalkDir(inputPath, func(path string, info os.DirEntry, err error) error {
+	err := WalkDir(inputPath, func(path string, info os.DirEntry, err error) error {
 		if err != nil && path == inputPath { // if walk for root search path return gets error
 			// then stop walking and return this error
 			return err
@@ -1198,6 +1201,7 @@ func GatherGitInfo(path string) (repoName, repoDir, repoHostName, repoURL, repoB
 	remotes, err := repo.Remotes()
 	if err != nil || len(remotes) == 0 {
 		logrus.Debugf("No remotes found at path %q Error: %q", path, err)
+		return "", "", "", "", "", fmt.Errorf("no remotes found in the git repo at path %s", path)
 	}
 	var preferredRemote *git.Remote
 	if preferredRemote = getGitRemoteByName(remotes, "upstream"); preferredRemote == nil {
@@ -1236,6 +1240,46 @@ func GatherGitInfo(path string) (repoName, repoDir, repoHostName, repoURL, repoB
 	return
 }
 
+// GatherGitRevisionInfo returns the commit hash of HEAD and the tag pointing to HEAD if one exists.
+func GatherGitRevisionInfo(path string) (commitHash, tag string, err error) {
+	if finfo, err := os.Stat(path); err != nil {
+		return "", "", fmt.Errorf("failed to stat the path %s . Error: %w", path, err)
+	} else if !finfo.IsDir() {
+		path = filepath.Dir(path)
+	}
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open the path %s as a git repo. Error: %w", path, err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get the HEAD of the git repo at path %s . Error: %w", path, err)
+	}
+	commitHash = head.Hash().String()
+	tags, err := repo.Tags()
+	if err != nil {
+		logrus.Debugf("failed to get the tags of the git repo at path %s . Error: %q", path, err)
+		return commitHash, "", nil
+	}
+	defer tags.Close()
+	for ref, err := tags.Next(); err == nil; ref, err = tags.Next() {
+		tagCommitHash := ref.Hash()
+		if tagObj, err := repo.TagObject(ref.Hash()); err == nil {
+			// annotated tag
+			tagCommit, err := tagObj.Commit()
+			if err != nil {
+				continue
+			}
+			tagCommitHash = tagCommit.Hash
+		}
+		if tagCommitHash == head.Hash() {
+			tag = ref.Name().Short()
+			break
+		}
+	}
+	return commitHash, tag, nil
+}
+
 func getGitRemoteByName(remotes []*git.Remote, remoteName string) *git.Remote {
 	for _, r := range remotes {
 		if r.Config().Name == remoteName {
@@ -1279,6 +1323,8 @@ func GetMapInterfaceFromObj(obj interface{}) (mapobj interface{}, err error) {
 
 // Interrupt creates SIGINT signal
 func Interrupt() error {
+	// the default handler for the signal exits without running the deferred functions
+	RemoveTempWorkspace()
 	p, err := os.FindProcess(os.Getpid())
 	if err != nil {
 		logrus.Fatal(err)
@@ -1421,7 +1467,12 @@ func writeToTar(w *io.PipeWriter, srcPath, basePath string, compressionType Comp
 		return err
 	}
 	mode := f.Mode()
-	return filepath.Walk(srcPath, func(file string, fi os.FileInfo, err error) error {
+	return WalkDir(srcPath, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			logrus.Debugf("Error walking folder to copy to container : %s", err)
+			return err
+		}
+		fi, err := d.Info()
 		if err != nil {
 			logrus.Debugf("Error walking folder to copy to container : %s", err)
 			return err
@@ -1429,22 +1480,9 @@ func writeToTar(w *io.PipeWriter, srcPath, basePath string, compressionType Comp
 		if fi.Mode()&os.ModeSocket != 0 {
 			return nil
 		}
-		var header *tar.Header
-		if fi.Mode()&os.ModeSymlink != 0 {
-			target, err := os.Readlink(file)
-			if err != nil {
-				return err
-			}
-			// Ensure that symlinks have Linux link names
-			header, err = tar.FileInfoHeader(fi, filepath.ToSlash(target))
-			if err != nil {
-				return err
-			}
-		} else {
-			header, err = tar.FileInfoHeader(fi, fi.Name())
-			if err != nil {
-				return err
-			}
+		header, err := getTarHeader(file, fi)
+		if err != nil {
+			return err
 		}
 		if mode.IsDir() {
 			relPath, err := filepath.Rel(srcPath, file)
@@ -1478,3 +1516,327 @@ func writeToTar(w *io.PipeWriter, srcPath, basePath string, compressionType Comp
 	})
 
 }
+
+// getTarHeader returns the tar header for the file, with the link name set for symlinks
+func getTarHeader(file string, fi fs.FileInfo) (*tar.Header, error) {
+	if fi.Mode()&os.ModeSymlink == 0 {
+		return tar.FileInfoHeader(fi, fi.Name())
+	}
+	target, err := os.Readlink(file)
+	if err != nil {
+		return nil, err
+	}
+	// Ensure that symlinks have Linux link names
+	return tar.FileInfoHeader(fi, filepath.ToSlash(target))
+}
+
+// CreateArchive writes the contents of the directory to an archive whose format is chosen using the extension of the archive path.
+// The entries are written in lexical order with a fixed timestamp and no owners, so the same contents always produce the same archive.
+func CreateArchive(srcPath, archivePath string) (err error) {
+	ext := getArchiveExt(archivePath)
+	if ext == "" {
+		return fmt.Errorf("the archive path %s does not have a supported extension", archivePath)
+	}
+	f, err := os.OpenFile(archivePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, DefaultFilePermission)
+	if err != nil {
+		return fmt.Errorf("failed to create the archive %s . Error: %w", archivePath, err)
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("failed to close the archive %s . Error: %w", archivePath, cerr)
+		}
+	}()
+	switch ext {
+	case ".zip":
+		err = writeDirToZip(f, srcPath)
+	case ".tar":
+		err = writeDirToTar(f, srcPath)
+	default:
+		gzipWriter := gzip.NewWriter(f)
+		if err = writeDirToTar(gzipWriter, srcPath); err == nil {
+			err = gzipWriter.Close()
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write the directory %s to the archive %s . Error: %w", srcPath, archivePath, err)
+	}
+	return nil
+}
+
+// archiveModTime is the timestamp of all the entries in the archives created by CreateArchive.
+// Zip archives can't store timestamps before 1980.
+var archiveModTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// walkArchiveEntries calls fn with the slash separated relative path of every entry in the directory except the directory itself
+func walkArchiveEntries(srcPath string, fn func(file, name string, fi fs.FileInfo) error) error {
+	return filepath.WalkDir(srcPath, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(srcPath, file)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() && !fi.Mode().IsRegular() && fi.Mode()&os.ModeSymlink == 0 {
+			logrus.Debugf("skipping the file %s with the unsupported mode %s", file, fi.Mode())
+			return nil
+		}
+		return fn(file, filepath.ToSlash(relPath), fi)
+	})
+}
+
+func writeDirToTar(w io.Writer, srcPath string) error {
+	tw := tar.NewWriter(w)
+	if err := walkArchiveEntries(srcPath, func(file, name string, fi fs.FileInfo) error {
+		header, err := getTarHeader(file, fi)
+		if err != nil {
+			return err
+		}
+		header.Name = name
+		if fi.IsDir() {
+			header.Name += "/"
+		}
+		header.ModTime = archiveModTime
+		header.AccessTime = time.Time{}
+		header.ChangeTime = time.Time{}
+		header.Uid, header.Gid, header.Uname, header.Gname = 0, 0, "", ""
+		header.Format = tar.FormatPAX
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		return copyFileTo(tw, file)
+	}); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+func writeDirToZip(w io.Writer, srcPath string) error {
+	zw := zip.NewWriter(w)
+	if err := walkArchiveEntries(srcPath, func(file, name string, fi fs.FileInfo) error {
+		header, err := zip.FileInfoHeader(fi)
+		if err != nil {
+			return err
+		}
+		header.Name = name
+		header.Modified = archiveModTime
+		if fi.IsDir() {
+			header.Name += "/"
+		} else {
+			header.Method = zip.Deflate
+		}
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			// zip archives store the target of a symlink as its contents
+			target, err := os.Readlink(file)
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(fw, filepath.ToSlash(target))
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		return copyFileTo(fw, file)
+	}); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func copyFileTo(w io.Writer, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// IsArchiveFile returns true if the path has the extension of a supported archive format
+func IsArchiveFile(path string) bool {
+	return getArchiveExt(path) != ""
+}
+
+func getArchiveExt(path string) string {
+	lowerPath := strings.ToLower(path)
+	for _, ext := range []string{".tar.gz", ".tgz", ".tar", ".zip"} {
+		if strings.HasSuffix(lowerPath, ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+// ExtractArchive extracts a zip, tar or gzipped tar archive into the destination directory
+func ExtractArchive(archivePath, destPath string) error {
+	if err := os.MkdirAll(destPath, DefaultDirectoryPermission); err != nil {
+		return fmt.Errorf("failed to create the directory %s . Error: %w", destPath, err)
+	}
+	switch getArchiveExt(archivePath) {
+	case ".zip":
+		return extractZip(archivePath, destPath)
+	case ".tar":
+		f, err := os.Open(archivePath)
+		if err != nil {
+			return fmt.Errorf("failed to open the archive %s . Error: %w", archivePath, err)
+		}
+		defer f.Close()
+		return extractTar(f, destPath)
+	case ".tar.gz", ".tgz":
+		f, err := os.Open(archivePath)
+		if err != nil {
+			return fmt.Errorf("failed to open the archive %s . Error: %w", archivePath, err)
+		}
+		defer f.Close()
+		gzipReader, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("failed to read the gzip archive %s . Error: %w", archivePath, err)
+		}
+		defer gzipReader.Close()
+		return extractTar(gzipReader, destPath)
+	}
+	return fmt.Errorf("the file %s is not a supported archive. Supported formats are zip, tar and tar.gz", archivePath)
+}
+
+// getArchiveEntryPath returns the destination path of an archive entry, making sure it does not escape the destination directory
+func getArchiveEntryPath(destPath, entryName string) (string, error) {
+	entryPath := filepath.Join(destPath, filepath.FromSlash(entryName))
+	if entryPath != filepath.Clean(destPath) && !strings.HasPrefix(entryPath, filepath.Clean(destPath)+string(os.PathSeparator)) {
+		return "", fmt.Errorf("the archive entry %s points outside the destination directory %s", entryName, destPath)
+	}
+	return entryPath, nil
+}
+
+// ExtractTarStream extracts an uncompressed tar stream into the destination directory
+func ExtractTarStream(r io.Reader, destPath string) error {
+	if err := os.MkdirAll(destPath, DefaultDirectoryPermission); err != nil {
+		return fmt.Errorf("failed to create the directory %s . Error: %w", destPath, err)
+	}
+	return extractTar(r, destPath)
+}
+
+func extractTar(r io.Reader, destPath string) error {
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read the tar archive. Error: %w", err)
+		}
+		entryPath, err := getArchiveEntryPath(destPath, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(entryPath, DefaultDirectoryPermission); err != nil {
+				return fmt.Errorf("failed to create the directory %s . Error: %w", entryPath, err)
+			}
+		case tar.TypeReg:
+			if err := writeArchiveEntry(tarReader, entryPath, os.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(entryPath), DefaultDirectoryPermission); err != nil {
+				return fmt.Errorf("failed to create the directory %s . Error: %w", filepath.Dir(entryPath), err)
+			}
+			if err := checkArchiveLinkTarget(destPath, entryPath, header.Linkname); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, entryPath); err != nil {
+				return fmt.Errorf("failed to create the symlink %s . Error: %w", entryPath, err)
+			}
+		default:
+			logrus.Debugf("skipping the tar entry %s with the unsupported type %c", header.Name, header.Typeflag)
+		}
+	}
+}
+
+// checkArchiveLinkTarget makes sure that a symlink in an archive points inside the destination directory,
+// so that the entries after it can't be written outside the destination through the symlink
+func checkArchiveLinkTarget(destPath, entryPath, linkname string) error {
+	if filepath.IsAbs(linkname) {
+		return fmt.Errorf("the archive symlink %s has the absolute target %s", entryPath, linkname)
+	}
+	realDestPath, err := filepath.EvalSymlinks(destPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the symlinks in the path %s . Error: %w", destPath, err)
+	}
+	// the parent directory could itself be a symlink extracted from the archive
+	realLinkDir, err := filepath.EvalSymlinks(filepath.Dir(entryPath))
+	if err != nil {
+		return fmt.Errorf("failed to resolve the symlinks in the path %s . Error: %w", filepath.Dir(entryPath), err)
+	}
+	target := filepath.Join(realLinkDir, filepath.FromSlash(linkname))
+	if target != realDestPath && !strings.HasPrefix(target, realDestPath+string(os.PathSeparator)) {
+		return fmt.Errorf("the archive symlink %s points outside the destination directory %s", entryPath, destPath)
+	}
+	return nil
+}
+
+func extractZip(archivePath, destPath string) error {
+	zipReader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open the zip archive %s . Error: %w", archivePath, err)
+	}
+	defer zipReader.Close()
+	for _, f := range zipReader.File {
+		entryPath, err := getArchiveEntryPath(destPath, f.Name)
+		if err != nil {
+			return err
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(entryPath, DefaultDirectoryPermission); err != nil {
+				return fmt.Errorf("failed to create the directory %s . Error: %w", entryPath, err)
+			}
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open the zip entry %s . Error: %w", f.Name, err)
+		}
+		perm := f.Mode().Perm()
+		if perm == 0 {
+			perm = DefaultFilePermission
+		}
+		err = writeArchiveEntry(rc, entryPath, perm)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeArchiveEntry(r io.Reader, entryPath string, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(entryPath), DefaultDirectoryPermission); err != nil {
+		return fmt.Errorf("failed to create the directory %s . Error: %w", filepath.Dir(entryPath), err)
+	}
+	f, err := os.OpenFile(entryPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return fmt.Errorf("failed to create the file %s . Error: %w", entryPath, err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, r); err != nil {
+		return fmt.Errorf("failed to write the file %s . Error: %w", entryPath, err)
+	}
+	return nil
+}
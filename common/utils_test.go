@@ -17,10 +17,13 @@
 package common_test
 
 import (
+	"archive/tar"
 	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/konveyor/move2kube/common"
@@ -193,3 +196,108 @@ func TestConvertUtf8AndUtf16ToUtf8(t *testing.T) {
 		}
 	})
 }
+
+func TestExtractTarStreamSymlinks(t *testing.T) {
+	writeTar := func(t *testing.T, headers []*tar.Header) *bytes.Buffer {
+		t.Helper()
+		archive := &bytes.Buffer{}
+		tarWriter := tar.NewWriter(archive)
+		for _, header := range headers {
+			if err := tarWriter.WriteHeader(header); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := tarWriter.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return archive
+	}
+	symlink := func(name, linkname string) *tar.Header {
+		return &tar.Header{Typeflag: tar.TypeSymlink, Name: name, Linkname: linkname}
+	}
+
+	t.Run("symlinks inside the destination are extracted", func(t *testing.T) {
+		destPath := t.TempDir()
+		archive := writeTar(t, []*tar.Header{
+			{Typeflag: tar.TypeDir, Name: "app/", Mode: 0755},
+			symlink("app/current", "."),
+			symlink("link", "app/current"),
+		})
+		if err := common.ExtractTarStream(archive, destPath); err != nil {
+			t.Fatalf("failed to extract the archive. Error: %q", err)
+		}
+		if linkname, err := os.Readlink(filepath.Join(destPath, "link")); err != nil || linkname != "app/current" {
+			t.Fatalf("the symlink was not extracted. Target: %s Error: %v", linkname, err)
+		}
+	})
+	testcases := map[string][]*tar.Header{
+		"an absolute target":                        {symlink("link", "/etc")},
+		"a relative target outside":                 {symlink("app/link", "../../outside")},
+		"a target escaping through another symlink": {symlink("dot", "."), symlink("dot/escape", "..")},
+	}
+	for name, headers := range testcases {
+		headers := headers
+		t.Run("symlinks with "+name+" are rejected", func(t *testing.T) {
+			destPath := filepath.Join(t.TempDir(), "dest")
+			if err := common.ExtractTarStream(writeTar(t, headers), destPath); err == nil {
+				t.Fatalf("expected the malicious archive to be rejected")
+			}
+		})
+	}
+}
+
+func TestCreateArchive(t *testing.T) {
+	writeFiles := func(t *testing.T, srcPath string, modTime time.Time) {
+		t.Helper()
+		files := map[string]string{"deploy/app.yaml": "kind: Deployment", "Dockerfile": "FROM scratch", "deploy/svc.yaml": "kind: Service"}
+		for name, content := range files {
+			filePath := filepath.Join(srcPath, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(filePath), common.DefaultDirectoryPermission); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filePath, []byte(content), common.DefaultFilePermission); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(filePath, modTime, modTime); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for _, ext := range []string{".tar.gz", ".zip"} {
+		ext := ext
+		t.Run("the "+ext+" archive is deterministic and can be extracted", func(t *testing.T) {
+			tempDir := t.TempDir()
+			archives := []string{}
+			for i, modTime := range []time.Time{time.Unix(1000, 0), time.Unix(2000, 0)} {
+				srcPath := filepath.Join(tempDir, fmt.Sprintf("src%d", i))
+				writeFiles(t, srcPath, modTime)
+				archivePath := filepath.Join(tempDir, fmt.Sprintf("output%d%s", i, ext))
+				if err := common.CreateArchive(srcPath, archivePath); err != nil {
+					t.Fatalf("failed to create the archive. Error: %q", err)
+				}
+				archives = append(archives, archivePath)
+			}
+			first, err := os.ReadFile(archives[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, err := os.ReadFile(archives[1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(first, second) {
+				t.Fatalf("expected the archives of the same contents to be identical")
+			}
+			destPath := filepath.Join(tempDir, "dest")
+			if err := common.ExtractArchive(archives[0], destPath); err != nil {
+				t.Fatalf("failed to extract the archive. Error: %q", err)
+			}
+			if content, err := os.ReadFile(filepath.Join(destPath, "deploy", "svc.yaml")); err != nil || string(content) != "kind: Service" {
+				t.Fatalf("the archive was not extracted correctly. Actual: %q Error: %v", content, err)
+			}
+		})
+	}
+	if err := common.CreateArchive(t.TempDir(), filepath.Join(t.TempDir(), "output.rar")); err == nil {
+		t.Fatalf("expected an error for an unsupported archive extension")
+	}
+}
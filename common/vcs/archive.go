@@ -0,0 +1,260 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package vcs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// s3RemotePathPrefix is the prefix of archives stored in S3 compatible object storage
+	// Format: s3://<bucket>/<key>[#sha256=<hex digest>]
+	s3RemotePathPrefix = "s3://"
+	// sha256FragmentKey is the url fragment key used to specify the expected checksum of the archive
+	// Example: https://example.com/snapshots/app.tar.gz#sha256=9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+	sha256FragmentKey = "sha256"
+	// archiveDownloadTimeout is how long downloading an archive can take, including reading its contents
+	archiveDownloadTimeout = 30 * time.Minute
+)
+
+// LocalArchive is a zip or tar.gz archive on the local filesystem
+// Format: <path to archive>[#sha256=<hex digest>]
+type LocalArchive struct {
+	// Path is the path of the archive without the fragment
+	Path string
+	// SHA256 is the optional expected hex encoded sha256 checksum of the archive
+	SHA256 string
+}
+
+// ArchiveRemote is a zip or tar.gz archive available over http(s) or in S3 compatible object storage
+type ArchiveRemote struct {
+	// URL is the http(s) or s3 url of the archive without the fragment
+	URL string
+	// SHA256 is the optional expected hex encoded sha256 checksum of the archive
+	SHA256 string
+}
+
+func isArchiveRemotePath(remotePath string) bool {
+	if !strings.HasPrefix(remotePath, s3RemotePathPrefix) && !strings.HasPrefix(remotePath, "http://") && !strings.HasPrefix(remotePath, "https://") {
+		return false
+	}
+	u, err := url.Parse(remotePath)
+	if err != nil {
+		return false
+	}
+	return common.IsArchiveFile(u.Path)
+}
+
+// isLocalArchivePath returns true if the path, without the checksum fragment, is an archive file on the local filesystem
+func isLocalArchivePath(archivePath string) bool {
+	if isArchiveRemotePath(archivePath) || isGitRemotePath(archivePath) || isOCIRemotePath(archivePath) {
+		return false
+	}
+	archivePath, _, _ = strings.Cut(archivePath, "#")
+	if !common.IsArchiveFile(archivePath) {
+		return false
+	}
+	fi, err := os.Stat(archivePath)
+	return err == nil && fi.Mode().IsRegular()
+}
+
+// getLocalArchiveFromPath parses a local archive path into a LocalArchive
+func getLocalArchiveFromPath(archivePath string) (*LocalArchive, error) {
+	archive := &LocalArchive{Path: archivePath}
+	if path, fragment, ok := strings.Cut(archivePath, "#"); ok {
+		archive.Path = path
+		var err error
+		if archive.SHA256, err = getSHA256FromFragment(fragment); err != nil {
+			return nil, fmt.Errorf("the checksum in the archive path %s is invalid. Error: %w", archivePath, err)
+		}
+	}
+	return archive, nil
+}
+
+// PinLocalArchivePath returns the absolute path of the local archive along with its sha256 checksum in the fragment,
+// so that the same archive is used when the path is fetched again. Other paths are returned unchanged.
+func PinLocalArchivePath(archivePath string) (string, error) {
+	if !isLocalArchivePath(archivePath) {
+		return archivePath, nil
+	}
+	archive, err := getLocalArchiveFromPath(archivePath)
+	if err != nil {
+		return archivePath, err
+	}
+	absPath, err := filepath.Abs(archive.Path)
+	if err != nil {
+		return archivePath, fmt.Errorf("failed to make the path %s absolute. Error: %w", archive.Path, err)
+	}
+	checksum, err := getSHA256(absPath)
+	if err != nil {
+		return archivePath, err
+	}
+	if archive.SHA256 != "" && archive.SHA256 != checksum {
+		return archivePath, fmt.Errorf("the sha256 checksum of the file %s does not match. Expected: %s Actual: %s", absPath, archive.SHA256, checksum)
+	}
+	return absPath + "#" + sha256FragmentKey + "=" + checksum, nil
+}
+
+// Load verifies the checksum of the archive and extracts it into the output directory
+func (a *LocalArchive) Load(outputPath string) (string, error) {
+	if a.SHA256 != "" {
+		if err := verifySHA256(a.Path, a.SHA256); err != nil {
+			return "", err
+		}
+		logrus.Debugf("the sha256 checksum of the archive %s matches", a.Path)
+	}
+	return extractSourceArchive(a.Path, outputPath)
+}
+
+// getArchiveRemoteFromRemotePath parses an archive remote path into an ArchiveRemote
+func getArchiveRemoteFromRemotePath(remotePath string) (*ArchiveRemote, error) {
+	u, err := url.Parse(remotePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the remote path %s as a url. Error: %w", remotePath, err)
+	}
+	archive := &ArchiveRemote{}
+	if u.Fragment != "" {
+		if archive.SHA256, err = getSHA256FromFragment(u.Fragment); err != nil {
+			return nil, fmt.Errorf("the checksum in the remote path %s is invalid. Error: %w", remotePath, err)
+		}
+		u.Fragment = ""
+	}
+	archive.URL = u.String()
+	return archive, nil
+}
+
+// getSHA256FromFragment returns the lower case sha256 checksum in a fragment like sha256=<hex digest>
+func getSHA256FromFragment(fragment string) (string, error) {
+	values, err := url.ParseQuery(fragment)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the fragment %s . Error: %w", fragment, err)
+	}
+	checksum := strings.ToLower(values.Get(sha256FragmentKey))
+	if _, err := hex.DecodeString(checksum); err != nil || (checksum != "" && len(checksum) != sha256.Size*2) {
+		return "", fmt.Errorf("the sha256 checksum %s is not a valid hex encoded sha256 digest", checksum)
+	}
+	return checksum, nil
+}
+
+// Load downloads the archive, verifies the checksum and extracts it into the output directory
+func (a *ArchiveRemote) Load(outputPath string) (string, error) {
+	u, err := url.Parse(a.URL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the url %s . Error: %w", a.URL, err)
+	}
+	archivePath := filepath.Join(outputPath, path.Base(u.Path))
+	if err := a.download(u, archivePath); err != nil {
+		return "", err
+	}
+	if a.SHA256 != "" {
+		if err := verifySHA256(archivePath, a.SHA256); err != nil {
+			return "", err
+		}
+		logrus.Debugf("the sha256 checksum of the archive %s matches", a.URL)
+	}
+	extractedPath, err := extractSourceArchive(archivePath, outputPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract the archive downloaded from %s . Error: %w", a.URL, err)
+	}
+	if err := os.Remove(archivePath); err != nil {
+		logrus.Debugf("failed to remove the downloaded archive at path %s . Error: %q", archivePath, err)
+	}
+	return extractedPath, nil
+}
+
+// extractSourceArchive extracts the archive into the source directory inside the output directory and returns the path to use
+func extractSourceArchive(archivePath, outputPath string) (string, error) {
+	extractedPath := filepath.Join(outputPath, "source")
+	if err := common.ExtractArchive(archivePath, extractedPath); err != nil {
+		return "", fmt.Errorf("failed to extract the archive %s . Error: %w", archivePath, err)
+	}
+	// Archives of source snapshots usually contain a single top level directory
+	entries, err := os.ReadDir(extractedPath)
+	if err == nil && len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(extractedPath, entries[0].Name()), nil
+	}
+	return extractedPath, nil
+}
+
+func (a *ArchiveRemote) download(u *url.URL, archivePath string) error {
+	var req *http.Request
+	var err error
+	if u.Scheme == "s3" {
+		req, err = newS3GetObjectRequest(u.Host, strings.TrimPrefix(u.Path, "/"))
+	} else {
+		req, err = http.NewRequest(http.MethodGet, u.String(), nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create the request to download %s . Error: %w", a.URL, err)
+	}
+	logrus.Infof("Downloading the archive %s", a.URL)
+	resp, err := (&http.Client{Timeout: archiveDownloadTimeout}).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download the archive %s . Error: %w", a.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to download the archive %s . Status: %s", a.URL, resp.Status)
+	}
+	f, err := os.OpenFile(archivePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, common.DefaultFilePermission)
+	if err != nil {
+		return fmt.Errorf("failed to create the file %s . Error: %w", archivePath, err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		return fmt.Errorf("failed to write the archive downloaded from %s to the file %s . Error: %w", a.URL, archivePath, err)
+	}
+	return nil
+}
+
+func verifySHA256(filePath, expected string) error {
+	actual, err := getSHA256(filePath)
+	if err != nil {
+		return err
+	}
+	if actual != expected {
+		return fmt.Errorf("the sha256 checksum of the file %s does not match. Expected: %s Actual: %s", filePath, expected, actual)
+	}
+	return nil
+}
+
+// getSHA256 returns the hex encoded sha256 checksum of the file
+func getSHA256(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open the file %s . Error: %w", filePath, err)
+	}
+	defer f.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", fmt.Errorf("failed to calculate the sha256 checksum of the file %s . Error: %w", filePath, err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
@@ -0,0 +1,143 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package vcs
+
+import (
+	"archive/zip"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsArchiveRemotePath(t *testing.T) {
+	testcases := map[string]bool{
+		"s3://snapshots/app/source.tar.gz":                           true,
+		"https://example.com/snapshots/source.zip#sha256=abcd":       true,
+		"https://example.com/snapshots/source.tgz?X-Amz-Expires=300": true,
+		"https://github.com/konveyor/move2kube-demos.git":            false,
+		"/tmp/source.tar.gz":                                         false,
+	}
+	for remotePath, want := range testcases {
+		if got := isArchiveRemotePath(remotePath); got != want {
+			t.Errorf("isArchiveRemotePath(%q) = %t , expected %t", remotePath, got, want)
+		}
+	}
+}
+
+func TestGetArchiveRemoteFromRemotePath(t *testing.T) {
+	checksum := strings.Repeat("ab", 32)
+	archive, err := getArchiveRemoteFromRemotePath("https://example.com/source.tar.gz?token=123#sha256=" + strings.ToUpper(checksum))
+	if err != nil {
+		t.Fatalf("failed to parse the archive remote path. Error: %q", err)
+	}
+	if archive.URL != "https://example.com/source.tar.gz?token=123" {
+		t.Errorf("the url was not parsed correctly. Actual: %s", archive.URL)
+	}
+	if archive.SHA256 != checksum {
+		t.Errorf("the checksum was not parsed correctly. Actual: %s", archive.SHA256)
+	}
+	if _, err := getArchiveRemoteFromRemotePath("https://example.com/source.zip#sha256=1234"); err == nil {
+		t.Errorf("expected an error for an invalid checksum")
+	}
+}
+
+func TestSignS3Request(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://snapshots.s3.us-east-1.amazonaws.com/app/source.tar.gz", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signS3Request(req, "us-east-1", "AKIDEXAMPLE", "secret", "token", time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC))
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20220102/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token, Signature=") {
+		t.Errorf("the authorization header is incorrect. Actual: %s", auth)
+	}
+	if req.Header.Get("x-amz-date") != "20220102T030405Z" || req.Header.Get("x-amz-security-token") != "token" {
+		t.Errorf("the signing headers are incorrect. Actual: %+v", req.Header)
+	}
+}
+
+func createTestZip(t *testing.T, zipPath string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	for name, content := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIsLocalArchivePath(t *testing.T) {
+	tempDir := t.TempDir()
+	zipPath := filepath.Join(tempDir, "app.zip")
+	createTestZip(t, zipPath, map[string]string{"app/main.go": "package main"})
+	testcases := map[string]bool{
+		zipPath:                                 true,
+		zipPath + "#sha256=abcd":                true,
+		filepath.Join(tempDir, "missing.zip"):   false,
+		tempDir:                                 false,
+		"https://example.com/snapshots/app.zip": false,
+	}
+	for archivePath, want := range testcases {
+		if got := isLocalArchivePath(archivePath); got != want {
+			t.Errorf("isLocalArchivePath(%q) = %t , expected %t", archivePath, got, want)
+		}
+	}
+}
+
+func TestLoadLocalArchive(t *testing.T) {
+	tempDir := t.TempDir()
+	zipPath := filepath.Join(tempDir, "app.zip")
+	createTestZip(t, zipPath, map[string]string{"app/main.go": "package main"})
+	pinnedPath, err := PinLocalArchivePath(zipPath)
+	if err != nil {
+		t.Fatalf("failed to pin the archive path. Error: %q", err)
+	}
+	if !strings.HasPrefix(pinnedPath, zipPath+"#sha256=") {
+		t.Fatalf("the pinned path is incorrect. Actual: %s", pinnedPath)
+	}
+	if samePath, err := PinLocalArchivePath(pinnedPath); err != nil || samePath != pinnedPath {
+		t.Errorf("pinning an already pinned path should not change it. Actual: %s Error: %v", samePath, err)
+	}
+	localPath, err := FetchRemotePath(pinnedPath, filepath.Join(tempDir, "remote"))
+	if err != nil {
+		t.Fatalf("failed to fetch the local archive. Error: %q", err)
+	}
+	if filepath.Base(localPath) != "app" {
+		t.Errorf("expected the single top level directory to be returned. Actual: %s", localPath)
+	}
+	if content, err := os.ReadFile(filepath.Join(localPath, "main.go")); err != nil || string(content) != "package main" {
+		t.Errorf("the archive was not extracted correctly. Actual: %q Error: %v", content, err)
+	}
+	if _, err := FetchRemotePath(zipPath+"#sha256="+strings.Repeat("ab", 32), filepath.Join(tempDir, "remote")); err == nil {
+		t.Errorf("expected an error for a checksum mismatch")
+	}
+}
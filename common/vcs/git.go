@@ -0,0 +1,366 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package vcs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/konveyor/move2kube/common"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+)
+
+const (
+	// gitRemotePathPrefix is the prefix used to mark a path as a git remote path
+	// Format: git+<repo url>[//<path within repo>][?ref=<branch|tag|commit>&depth=<n>&submodules=<bool>&sparsecheckout=<bool>]
+	// sparsecheckout only limits the files written to the disk to the path within the repo, the whole repo is still fetched.
+	// Examples:
+	//   git+https://github.com/konveyor/move2kube-demos.git//samples/docker-compose?ref=main&depth=1
+	//   git+ssh://git@github.com/konveyor/move2kube-demos.git?ref=v0.3.0&submodules=true
+	gitRemotePathPrefix = "git+"
+	// gitURLSuffix marks the http(s), ssh and scp like urls that are git remote paths without the prefix
+	// Format: <repo url ending in .git>[#<branch|tag|commit>[:<path within repo>]]
+	// Examples:
+	//   https://github.com/konveyor/move2kube-demos.git#main:samples/docker-compose
+	//   git@github.com:konveyor/move2kube-demos.git#v0.3.0
+	gitURLSuffix = ".git"
+	// gitUsernameEnvKey is the environment variable containing the username for http(s) git remotes
+	gitUsernameEnvKey = "MOVE2KUBE_GIT_USERNAME"
+	// gitPasswordEnvKey is the environment variable containing the password or token for http(s) git remotes
+	gitPasswordEnvKey = "MOVE2KUBE_GIT_PASSWORD"
+	// gitSSHKeyPathEnvKey is the environment variable containing the path to the private key for ssh git remotes
+	gitSSHKeyPathEnvKey = "MOVE2KUBE_GIT_SSH_KEY_PATH"
+	// gitSSHKeyPassphraseEnvKey is the environment variable containing the passphrase of the private key
+	gitSSHKeyPassphraseEnvKey = "MOVE2KUBE_GIT_SSH_KEY_PASSPHRASE"
+	// sshAuthSockEnvKey is the environment variable containing the ssh agent socket
+	sshAuthSockEnvKey = "SSH_AUTH_SOCK"
+	// defaultGitUser is the user used for ssh remotes when the url does not specify one
+	defaultGitUser = "git"
+)
+
+var (
+	commitHashRegex = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+	// gitURLRegex matches the urls of the git repos that can be cloned without the git+ prefix
+	gitURLRegex = regexp.MustCompile(`^(?:(?:https?|ssh|git)://|[\w.-]+@[\w.-]+:)`)
+)
+
+// GitVCSRepo stores information about a git remote path
+type GitVCSRepo struct {
+	// URL is the url of the repo
+	URL string
+	// Ref is the branch, tag or commit hash to checkout. Empty means the default branch.
+	Ref string
+	// PathWithinRepo is the sub directory inside the repo that should be used
+	PathWithinRepo string
+	// Depth limits the clone to the given number of commits. 0 means the full history.
+	Depth int
+	// RecurseSubmodules initializes all the submodules after cloning
+	RecurseSubmodules bool
+	// SparseCheckout only writes the files under PathWithinRepo to the disk instead of checking out the whole repo.
+	// The clone still fetches the objects of the whole repo (limited by Depth), since partial clones are not supported by go-git.
+	SparseCheckout bool
+}
+
+func isGitRemotePath(path string) bool {
+	return strings.HasPrefix(path, gitRemotePathPrefix) || isGitURL(path)
+}
+
+// isGitURL returns true if the path is the url of a git repo ending in .git, optionally followed by #<ref>[:<path within repo>]
+func isGitURL(path string) bool {
+	repoURL := path
+	if idx := strings.Index(repoURL, "#"); idx >= 0 {
+		repoURL = repoURL[:idx]
+	}
+	return gitURLRegex.MatchString(repoURL) && strings.HasSuffix(strings.TrimSuffix(repoURL, "/"), gitURLSuffix)
+}
+
+// getGitRepoStructFromURL parses a git url of the form <repo url>[#<ref>[:<path within repo>]] into a shallow clone of the ref
+func getGitRepoStructFromURL(remotePath string) (*GitVCSRepo, error) {
+	repo := &GitVCSRepo{URL: remotePath, Depth: 1}
+	if idx := strings.Index(remotePath, "#"); idx >= 0 {
+		repo.URL = remotePath[:idx]
+		repo.Ref = remotePath[idx+1:]
+		if idx := strings.Index(repo.Ref, ":"); idx >= 0 {
+			repo.PathWithinRepo = strings.Trim(repo.Ref[idx+1:], "/")
+			repo.Ref = repo.Ref[:idx]
+		}
+	}
+	if repo.URL == "" {
+		return nil, fmt.Errorf("the git remote path %s does not contain a repo url", remotePath)
+	}
+	if commitHashRegex.MatchString(repo.Ref) {
+		// Arbitrary commits cannot be fetched with a shallow clone
+		repo.Depth = 0
+	}
+	return repo, nil
+}
+
+// getGitRepoStructFromRemotePath parses a git remote path into a GitVCSRepo
+func getGitRepoStructFromRemotePath(remotePath string) (*GitVCSRepo, error) {
+	if !strings.HasPrefix(remotePath, gitRemotePathPrefix) {
+		if isGitURL(remotePath) {
+			return getGitRepoStructFromURL(remotePath)
+		}
+		return nil, fmt.Errorf("the path %s is not a git remote path. Expected the prefix %s or a repo url ending in %s", remotePath, gitRemotePathPrefix, gitURLSuffix)
+	}
+	rest := strings.TrimPrefix(remotePath, gitRemotePathPrefix)
+	repo := &GitVCSRepo{Depth: 1}
+	if idx := strings.Index(rest, "?"); idx >= 0 {
+		query, err := url.ParseQuery(rest[idx+1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the query parameters of the git remote path %s . Error: %w", remotePath, err)
+		}
+		rest = rest[:idx]
+		repo.Ref = query.Get("ref")
+		if depth := query.Get("depth"); depth != "" {
+			if repo.Depth, err = cast.ToIntE(depth); err != nil || repo.Depth < 0 {
+				return nil, fmt.Errorf("the depth %s in the git remote path %s is not a valid non-negative integer", depth, remotePath)
+			}
+		}
+		if submodules := query.Get("submodules"); submodules != "" {
+			if repo.RecurseSubmodules, err = cast.ToBoolE(submodules); err != nil {
+				return nil, fmt.Errorf("the submodules value %s in the git remote path %s is not a valid boolean", submodules, remotePath)
+			}
+		}
+		if sparseCheckout := query.Get("sparsecheckout"); sparseCheckout != "" {
+			if repo.SparseCheckout, err = cast.ToBoolE(sparseCheckout); err != nil {
+				return nil, fmt.Errorf("the sparsecheckout value %s in the git remote path %s is not a valid boolean", sparseCheckout, remotePath)
+			}
+		}
+	}
+	schemeEnd := 0
+	if idx := strings.Index(rest, "://"); idx >= 0 {
+		schemeEnd = idx + len("://")
+	}
+	if idx := strings.Index(rest[schemeEnd:], "//"); idx >= 0 {
+		repo.PathWithinRepo = strings.Trim(rest[schemeEnd+idx+len("//"):], "/")
+		rest = rest[:schemeEnd+idx]
+	}
+	if rest == "" {
+		return nil, fmt.Errorf("the git remote path %s does not contain a repo url", remotePath)
+	}
+	repo.URL = rest
+	if repo.PathWithinRepo == "" || repo.RecurseSubmodules {
+		repo.SparseCheckout = false
+	}
+	if commitHashRegex.MatchString(repo.Ref) {
+		// Arbitrary commits cannot be fetched with a shallow clone
+		repo.Depth = 0
+	}
+	return repo, nil
+}
+
+// Load clones the git repo into the output directory and returns the path to the requested sub directory
+func (g *GitVCSRepo) Load(outputPath string) (string, error) {
+	auth, err := getGitAuth(g.URL)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the credentials for the git repo %s . Error: %w", g.URL, err)
+	}
+	cloneOpts := &git.CloneOptions{
+		URL:          g.URL,
+		Auth:         auth,
+		Depth:        g.Depth,
+		SingleBranch: g.Ref != "" && !commitHashRegex.MatchString(g.Ref),
+		NoCheckout:   g.SparseCheckout,
+	}
+	if g.RecurseSubmodules {
+		cloneOpts.RecurseSubmodules = git.DefaultSubmoduleRecursionDepth
+	}
+	repoPath := outputPath
+	if g.SparseCheckout {
+		repoPath = filepath.Join(outputPath, "repo")
+	}
+	repo, err := g.clone(repoPath, cloneOpts)
+	if err != nil {
+		return "", err
+	}
+	if commitHashRegex.MatchString(g.Ref) {
+		if err := g.checkoutCommit(repo); err != nil {
+			return "", err
+		}
+	}
+	if !g.SparseCheckout {
+		localPath := filepath.Join(repoPath, filepath.FromSlash(g.PathWithinRepo))
+		if _, err := os.Stat(localPath); err != nil {
+			return "", fmt.Errorf("the path %s does not exist in the git repo %s . Error: %w", g.PathWithinRepo, g.URL, err)
+		}
+		return localPath, nil
+	}
+	sparsePath := filepath.Join(outputPath, "source")
+	if err := g.sparseCheckout(repo, sparsePath); err != nil {
+		return "", err
+	}
+	if err := os.RemoveAll(repoPath); err != nil {
+		logrus.Debugf("failed to remove the cloned repo at path %s . Error: %q", repoPath, err)
+	}
+	return sparsePath, nil
+}
+
+// clone clones the repo, trying the ref first as a branch and then as a tag
+func (g *GitVCSRepo) clone(repoPath string, cloneOpts *git.CloneOptions) (*git.Repository, error) {
+	logrus.Infof("Cloning the git repo %s (ref: %q, depth: %d, sparse checkout: %t, submodules: %t)", g.URL, g.Ref, g.Depth, g.SparseCheckout, g.RecurseSubmodules)
+	if !cloneOpts.SingleBranch {
+		repo, err := git.PlainClone(repoPath, g.SparseCheckout, cloneOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to clone the git repo %s . Error: %w", g.URL, err)
+		}
+		return repo, nil
+	}
+	cloneOpts.ReferenceName = plumbing.NewBranchReferenceName(g.Ref)
+	repo, err := git.PlainClone(repoPath, g.SparseCheckout, cloneOpts)
+	if err == nil {
+		return repo, nil
+	}
+	if !errors.Is(err, git.NoMatchingRefSpecError{}) && !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, fmt.Errorf("failed to clone the branch %s of the git repo %s . Error: %w", g.Ref, g.URL, err)
+	}
+	logrus.Debugf("the ref %s is not a branch of the git repo %s . Trying it as a tag.", g.Ref, g.URL)
+	if err := os.RemoveAll(repoPath); err != nil {
+		logrus.Debugf("failed to remove the partially cloned repo at path %s . Error: %q", repoPath, err)
+	}
+	cloneOpts.ReferenceName = plumbing.NewTagReferenceName(g.Ref)
+	repo, err = git.PlainClone(repoPath, g.SparseCheckout, cloneOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone the ref %s of the git repo %s as a branch or a tag. Error: %w", g.Ref, g.URL, err)
+	}
+	return repo, nil
+}
+
+func (g *GitVCSRepo) checkoutCommit(repo *git.Repository) error {
+	hash, err := repo.ResolveRevision(plumbing.Revision(g.Ref))
+	if err != nil {
+		return fmt.Errorf("failed to find the commit %s in the git repo %s . Error: %w", g.Ref, g.URL, err)
+	}
+	if g.SparseCheckout {
+		return repo.Storer.SetReference(plumbing.NewHashReference(plumbing.HEAD, *hash))
+	}
+	workTree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get the worktree of the git repo %s . Error: %w", g.URL, err)
+	}
+	if err := workTree.Checkout(&git.CheckoutOptions{Hash: *hash}); err != nil {
+		return fmt.Errorf("failed to checkout the commit %s of the git repo %s . Error: %w", g.Ref, g.URL, err)
+	}
+	if g.RecurseSubmodules {
+		submodules, err := workTree.Submodules()
+		if err != nil {
+			return fmt.Errorf("failed to get the submodules of the git repo %s . Error: %w", g.URL, err)
+		}
+		if err := submodules.Update(&git.SubmoduleUpdateOptions{Init: true, RecurseSubmodules: git.DefaultSubmoduleRecursionDepth}); err != nil {
+			return fmt.Errorf("failed to update the submodules of the git repo %s . Error: %w", g.URL, err)
+		}
+	}
+	return nil
+}
+
+// sparseCheckout writes only the files under PathWithinRepo at HEAD into the output directory
+func (g *GitVCSRepo) sparseCheckout(repo *git.Repository, outputPath string) error {
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to get the HEAD of the git repo %s . Error: %w", g.URL, err)
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return fmt.Errorf("failed to get the commit %s of the git repo %s . Error: %w", head.Hash(), g.URL, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return fmt.Errorf("failed to get the tree of the commit %s of the git repo %s . Error: %w", head.Hash(), g.URL, err)
+	}
+	subTree, err := tree.Tree(g.PathWithinRepo)
+	if err != nil {
+		return fmt.Errorf("the path %s does not exist in the git repo %s . Error: %w", g.PathWithinRepo, g.URL, err)
+	}
+	if err := os.MkdirAll(outputPath, common.DefaultDirectoryPermission); err != nil {
+		return fmt.Errorf("failed to create the directory %s . Error: %w", outputPath, err)
+	}
+	return subTree.Files().ForEach(func(f *object.File) error {
+		return writeGitFile(f, filepath.Join(outputPath, filepath.FromSlash(f.Name)))
+	})
+}
+
+func writeGitFile(f *object.File, destPath string) error {
+	if err := os.MkdirAll(filepath.Dir(destPath), common.DefaultDirectoryPermission); err != nil {
+		return fmt.Errorf("failed to create the directory %s . Error: %w", filepath.Dir(destPath), err)
+	}
+	if f.Mode == filemode.Symlink {
+		target, err := f.Contents()
+		if err != nil {
+			return fmt.Errorf("failed to read the symlink %s . Error: %w", f.Name, err)
+		}
+		return os.Symlink(target, destPath)
+	}
+	perm, err := f.Mode.ToOSFileMode()
+	if err != nil {
+		perm = common.DefaultFilePermission
+	}
+	reader, err := f.Reader()
+	if err != nil {
+		return fmt.Errorf("failed to read the file %s . Error: %w", f.Name, err)
+	}
+	defer reader.Close()
+	destFile, err := os.OpenFile(destPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm.Perm())
+	if err != nil {
+		return fmt.Errorf("failed to create the file %s . Error: %w", destPath, err)
+	}
+	defer destFile.Close()
+	if _, err := io.Copy(destFile, reader); err != nil {
+		return fmt.Errorf("failed to write the file %s . Error: %w", destPath, err)
+	}
+	return nil
+}
+
+// getGitAuth returns the credentials to use for the repo url, gathered from the environment and the ssh agent
+func getGitAuth(repoURL string) (transport.AuthMethod, error) {
+	if strings.HasPrefix(repoURL, "http://") || strings.HasPrefix(repoURL, "https://") {
+		password := os.Getenv(gitPasswordEnvKey)
+		if password == "" {
+			return nil, nil
+		}
+		username := os.Getenv(gitUsernameEnvKey)
+		if username == "" {
+			// when using tokens any non-empty username works
+			username = defaultGitUser
+		}
+		return &http.BasicAuth{Username: username, Password: password}, nil
+	}
+	user := defaultGitUser
+	if endpoint, err := transport.NewEndpoint(repoURL); err == nil && endpoint.User != "" {
+		user = endpoint.User
+	}
+	if keyPath := os.Getenv(gitSSHKeyPathEnvKey); keyPath != "" {
+		return ssh.NewPublicKeysFromFile(user, keyPath, os.Getenv(gitSSHKeyPassphraseEnvKey))
+	}
+	if os.Getenv(sshAuthSockEnvKey) != "" {
+		return ssh.NewSSHAgentAuth(user)
+	}
+	return nil, nil
+}
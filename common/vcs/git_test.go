@@ -0,0 +1,117 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package vcs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetGitRepoStructFromRemotePath(t *testing.T) {
+	testcases := []struct {
+		name       string
+		remotePath string
+		want       *GitVCSRepo
+		wantErr    bool
+	}{
+		{
+			name:       "https url without options",
+			remotePath: "git+https://github.com/konveyor/move2kube-demos.git",
+			want:       &GitVCSRepo{URL: "https://github.com/konveyor/move2kube-demos.git", Depth: 1},
+		},
+		{
+			name:       "https url with sub path and ref",
+			remotePath: "git+https://github.com/konveyor/move2kube-demos.git//samples/docker-compose?ref=main",
+			want:       &GitVCSRepo{URL: "https://github.com/konveyor/move2kube-demos.git", Ref: "main", PathWithinRepo: "samples/docker-compose", Depth: 1},
+		},
+		{
+			name:       "https url with sub path and sparse checkout",
+			remotePath: "git+https://github.com/konveyor/move2kube-demos.git//samples/docker-compose?ref=main&sparsecheckout=true",
+			want:       &GitVCSRepo{URL: "https://github.com/konveyor/move2kube-demos.git", Ref: "main", PathWithinRepo: "samples/docker-compose", Depth: 1, SparseCheckout: true},
+		},
+		{
+			name:       "ssh url with submodules disables the sparse checkout",
+			remotePath: "git+ssh://git@github.com/konveyor/move2kube-demos.git//samples?submodules=true&depth=5&sparsecheckout=true",
+			want:       &GitVCSRepo{URL: "ssh://git@github.com/konveyor/move2kube-demos.git", PathWithinRepo: "samples", Depth: 5, RecurseSubmodules: true},
+		},
+		{
+			name:       "scp like url with commit hash does a full clone",
+			remotePath: "git+git@github.com:konveyor/move2kube-demos.git//samples?ref=1a2b3c4d&sparsecheckout=false",
+			want:       &GitVCSRepo{URL: "git@github.com:konveyor/move2kube-demos.git", Ref: "1a2b3c4d", PathWithinRepo: "samples"},
+		},
+		{
+			name:       "invalid depth",
+			remotePath: "git+https://github.com/konveyor/move2kube-demos.git?depth=abc",
+			wantErr:    true,
+		},
+		{
+			name:       "https url ending in .git without the prefix",
+			remotePath: "https://github.com/konveyor/move2kube-demos.git",
+			want:       &GitVCSRepo{URL: "https://github.com/konveyor/move2kube-demos.git", Depth: 1},
+		},
+		{
+			name:       "https url with branch and sub path in the fragment",
+			remotePath: "https://github.com/konveyor/move2kube-demos.git#main:samples/docker-compose/",
+			want:       &GitVCSRepo{URL: "https://github.com/konveyor/move2kube-demos.git", Ref: "main", PathWithinRepo: "samples/docker-compose", Depth: 1},
+		},
+		{
+			name:       "scp like url with commit hash in the fragment",
+			remotePath: "git@github.com:konveyor/move2kube-demos.git#1a2b3c4d",
+			want:       &GitVCSRepo{URL: "git@github.com:konveyor/move2kube-demos.git", Ref: "1a2b3c4d"},
+		},
+		{
+			name:       "missing prefix",
+			remotePath: "https://github.com/konveyor/move2kube-demos",
+			wantErr:    true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := getGitRepoStructFromRemotePath(tc.remotePath)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error for the remote path %s . Actual: %+v", tc.remotePath, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to parse the remote path %s . Error: %q", tc.remotePath, err)
+			}
+			if !cmp.Equal(got, tc.want) {
+				t.Fatalf("the parsed git repo is incorrect. Differences:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestIsGitRemotePath(t *testing.T) {
+	testcases := map[string]bool{
+		"git+https://github.com/konveyor/move2kube-demos.git":        true,
+		"https://github.com/konveyor/move2kube-demos.git#main":       true,
+		"ssh://git@github.com/konveyor/move2kube-demos.git":          true,
+		"git@github.com:konveyor/move2kube-demos.git#main:samples":   true,
+		"https://github.com/konveyor/move2kube-demos":                false,
+		"https://github.com/konveyor/move2kube-demos/archive.tar.gz": false,
+		"path/to/local/repo.git":                                     false,
+	}
+	for path, want := range testcases {
+		if got := isGitRemotePath(path); got != want {
+			t.Errorf("expected isGitRemotePath(%s) to be %t . Actual: %t", path, want, got)
+		}
+	}
+}
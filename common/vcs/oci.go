@@ -0,0 +1,72 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package vcs
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/konveyor/move2kube/common"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ociRemotePathPrefix is the prefix of directories stored as the filesystem of an OCI image
+	// Format: oci://<registry>/<repository>[:<tag>|@<digest>]
+	ociRemotePathPrefix = "oci://"
+)
+
+// OCIRemote is a directory stored as the filesystem of an image in an OCI registry.
+// The image is usually built FROM scratch with the directory copied to the root.
+type OCIRemote struct {
+	// Reference is the image reference without the oci:// prefix
+	Reference string
+}
+
+func isOCIRemotePath(remotePath string) bool {
+	return strings.HasPrefix(remotePath, ociRemotePathPrefix)
+}
+
+// getOCIRemoteFromRemotePath parses an OCI remote path into an OCIRemote
+func getOCIRemoteFromRemotePath(remotePath string) (*OCIRemote, error) {
+	reference := strings.TrimPrefix(remotePath, ociRemotePathPrefix)
+	if _, err := name.ParseReference(reference); err != nil {
+		return nil, fmt.Errorf("the remote path %s is not a valid OCI image reference. Error: %w", remotePath, err)
+	}
+	return &OCIRemote{Reference: reference}, nil
+}
+
+// Load pulls the image and extracts its filesystem into the output directory
+func (o *OCIRemote) Load(outputPath string) (string, error) {
+	logrus.Infof("Pulling the OCI image %s", o.Reference)
+	img, err := crane.Pull(o.Reference, crane.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return "", fmt.Errorf("failed to pull the OCI image %s . Error: %w", o.Reference, err)
+	}
+	fs := mutate.Extract(img)
+	defer fs.Close()
+	extractedPath := filepath.Join(outputPath, "oci")
+	if err := common.ExtractTarStream(fs, extractedPath); err != nil {
+		return "", fmt.Errorf("failed to extract the filesystem of the OCI image %s . Error: %w", o.Reference, err)
+	}
+	return extractedPath, nil
+}
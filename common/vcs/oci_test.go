@@ -0,0 +1,61 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package vcs
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+)
+
+func TestOCIRemoteLoad(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	reference := strings.TrimPrefix(server.URL, "http://") + "/transformers/custom:1.0.0"
+	img, err := crane.Image(map[string][]byte{
+		"custom/transformer.yaml": []byte("kind: Transformer\n"),
+	})
+	if err != nil {
+		t.Fatalf("failed to create the test image. Error: %q", err)
+	}
+	if err := crane.Push(img, reference, crane.Insecure); err != nil {
+		t.Fatalf("failed to push the test image. Error: %q", err)
+	}
+	if !IsRemotePath(ociRemotePathPrefix + reference) {
+		t.Fatalf("expected %s to be a remote path", ociRemotePathPrefix+reference)
+	}
+	remote, err := getOCIRemoteFromRemotePath(ociRemotePathPrefix + reference)
+	if err != nil {
+		t.Fatalf("failed to parse the OCI remote path. Error: %q", err)
+	}
+	localPath, err := remote.Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to load the OCI image. Error: %q", err)
+	}
+	data, err := os.ReadFile(filepath.Join(localPath, "custom", "transformer.yaml"))
+	if err != nil {
+		t.Fatalf("failed to read the extracted file. Error: %q", err)
+	}
+	if string(data) != "kind: Transformer\n" {
+		t.Fatalf("unexpected contents of the extracted file. Actual: %q", string(data))
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package vcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+const (
+	// GitHubProvider opens pull requests using the GitHub REST API
+	GitHubProvider = "github"
+	// GitLabProvider opens merge requests using the GitLab REST API
+	GitLabProvider = "gitlab"
+	// pullRequestTimeout is how long the API call that opens the pull request can take
+	pullRequestTimeout = 30 * time.Second
+)
+
+// PullRequestOptions is how a pull request is opened for a branch
+type PullRequestOptions struct {
+	// RepoURL is the http(s) or ssh url of the repo
+	RepoURL string
+	// Provider is either GitHubProvider or GitLabProvider
+	Provider string
+	// APIURL is the base url of the REST API. Empty means it is derived from the host of the repo.
+	APIURL string
+	// Token is used to authenticate with the API. Empty means the git password from the environment is used.
+	Token string
+	// Branch is the branch with the changes
+	Branch string
+	// BaseBranch is the branch the changes are merged into
+	BaseBranch string
+	Title      string
+	Body       string
+}
+
+// GetGitProvider returns the provider of the repo guessed from its host, and empty string if it is not known
+func GetGitProvider(repoURL string) string {
+	host, _, err := getRepoHostAndPath(repoURL)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case strings.Contains(host, GitHubProvider):
+		return GitHubProvider
+	case strings.Contains(host, GitLabProvider):
+		return GitLabProvider
+	}
+	return ""
+}
+
+// OpenPullRequest opens a pull request (a merge request on GitLab) and returns its url
+func OpenPullRequest(opts PullRequestOptions) (string, error) {
+	host, repoPath, err := getRepoHostAndPath(opts.RepoURL)
+	if err != nil {
+		return "", err
+	}
+	token := opts.Token
+	if token == "" {
+		token = os.Getenv(gitPasswordEnvKey)
+	}
+	if token == "" {
+		return "", fmt.Errorf("a token is required to open a pull request on the git repo %s", opts.RepoURL)
+	}
+	var reqURL string
+	var reqBody interface{}
+	headers := map[string]string{}
+	switch opts.Provider {
+	case GitHubProvider:
+		apiURL := opts.APIURL
+		if apiURL == "" {
+			apiURL = "https://api.github.com"
+			if host != "github.com" {
+				// GitHub Enterprise Server
+				apiURL = "https://" + host + "/api/v3"
+			}
+		}
+		reqURL = strings.TrimSuffix(apiURL, "/") + "/repos/" + repoPath + "/pulls"
+		reqBody = map[string]string{"title": opts.Title, "body": opts.Body, "head": opts.Branch, "base": opts.BaseBranch}
+		headers["Accept"] = "application/vnd.github+json"
+		headers["Authorization"] = "Bearer " + token
+	case GitLabProvider:
+		apiURL := opts.APIURL
+		if apiURL == "" {
+			apiURL = "https://" + host + "/api/v4"
+		}
+		reqURL = strings.TrimSuffix(apiURL, "/") + "/projects/" + url.PathEscape(repoPath) + "/merge_requests"
+		reqBody = map[string]string{"title": opts.Title, "description": opts.Body, "source_branch": opts.Branch, "target_branch": opts.BaseBranch}
+		headers["PRIVATE-TOKEN"] = token
+	default:
+		return "", fmt.Errorf("the git provider %s is not supported. Supported providers are %s and %s", opts.Provider, GitHubProvider, GitLabProvider)
+	}
+	reqBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the pull request to json. Error: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, reqURL, bytes.NewReader(reqBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to create the request to %s . Error: %w", reqURL, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := (&http.Client{Timeout: pullRequestTimeout}).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to open the pull request using %s . Error: %w", reqURL, err)
+	}
+	defer resp.Body.Close()
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the response from %s . Error: %w", reqURL, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("failed to open the pull request using %s . Status: %s Response: %s", reqURL, resp.Status, string(respBytes))
+	}
+	pr := struct {
+		HTMLURL string `json:"html_url"`
+		WebURL  string `json:"web_url"`
+	}{}
+	if err := json.Unmarshal(respBytes, &pr); err != nil {
+		return "", fmt.Errorf("failed to parse the response from %s . Error: %w", reqURL, err)
+	}
+	if pr.HTMLURL != "" {
+		return pr.HTMLURL, nil
+	}
+	return pr.WebURL, nil
+}
+
+// getRepoHostAndPath returns the host of the repo and its path without the leading slash and the .git suffix
+func getRepoHostAndPath(repoURL string) (string, string, error) {
+	endpoint, err := transport.NewEndpoint(repoURL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse the git repo url %s . Error: %w", repoURL, err)
+	}
+	repoPath := strings.TrimSuffix(strings.Trim(endpoint.Path, "/"), gitURLSuffix)
+	if endpoint.Host == "" || repoPath == "" {
+		return "", "", fmt.Errorf("the git repo url %s does not have a host and a path", repoURL)
+	}
+	return endpoint.Host, repoPath, nil
+}
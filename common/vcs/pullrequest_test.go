@@ -0,0 +1,110 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package vcs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetGitProvider(t *testing.T) {
+	testcases := map[string]string{
+		"https://github.com/konveyor/move2kube-demos.git":    GitHubProvider,
+		"git@github.com:konveyor/move2kube-demos.git":        GitHubProvider,
+		"https://gitlab.example.com/group/subgroup/repo.git": GitLabProvider,
+		"https://git.example.com/org/repo.git":               "",
+	}
+	for repoURL, want := range testcases {
+		if got := GetGitProvider(repoURL); got != want {
+			t.Errorf("GetGitProvider(%q) = %q , expected %q", repoURL, got, want)
+		}
+	}
+}
+
+func TestOpenPullRequest(t *testing.T) {
+	testcases := []struct {
+		name       string
+		provider   string
+		repoURL    string
+		wantPath   string
+		wantHeader string
+		wantBody   map[string]string
+		response   string
+	}{
+		{
+			name:       "github pull request",
+			provider:   GitHubProvider,
+			repoURL:    "git@github.com:org/deploy.git",
+			wantPath:   "/repos/org/deploy/pulls",
+			wantHeader: "Authorization",
+			wantBody:   map[string]string{"title": "Update", "body": "Generated", "head": "m2k", "base": "main"},
+			response:   `{"html_url": "https://github.com/org/deploy/pull/1"}`,
+		},
+		{
+			name:       "gitlab merge request",
+			provider:   GitLabProvider,
+			repoURL:    "https://gitlab.com/group/sub/deploy.git",
+			wantPath:   "/projects/group%2Fsub%2Fdeploy/merge_requests",
+			wantHeader: "PRIVATE-TOKEN",
+			wantBody:   map[string]string{"title": "Update", "description": "Generated", "source_branch": "m2k", "target_branch": "main"},
+			response:   `{"web_url": "https://gitlab.com/group/sub/deploy/-/merge_requests/1"}`,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.EscapedPath() != tc.wantPath {
+					t.Errorf("the request path is incorrect. Expected: %s Actual: %s", tc.wantPath, r.URL.EscapedPath())
+				}
+				if r.Header.Get(tc.wantHeader) == "" {
+					t.Errorf("the request is missing the %s header", tc.wantHeader)
+				}
+				body := map[string]string{}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("failed to decode the request body. Error: %q", err)
+				}
+				for k, v := range tc.wantBody {
+					if body[k] != v {
+						t.Errorf("the request body key %s is incorrect. Expected: %s Actual: %s", k, v, body[k])
+					}
+				}
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(tc.response))
+			}))
+			defer server.Close()
+			prURL, err := OpenPullRequest(PullRequestOptions{
+				RepoURL:    tc.repoURL,
+				Provider:   tc.provider,
+				APIURL:     server.URL,
+				Token:      "token",
+				Branch:     "m2k",
+				BaseBranch: "main",
+				Title:      "Update",
+				Body:       "Generated",
+			})
+			if err != nil {
+				t.Fatalf("failed to open the pull request. Error: %q", err)
+			}
+			if prURL == "" {
+				t.Fatalf("expected the url of the pull request")
+			}
+		})
+	}
+}
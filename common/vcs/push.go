@@ -0,0 +1,151 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package vcs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/filesystem"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultCommitAuthorName is the name of the author of the commits that push the output
+	defaultCommitAuthorName = "Move2Kube"
+	// defaultCommitAuthorEmail is the email of the author of the commits that push the output
+	defaultCommitAuthorEmail = "move2kube@konveyor.io"
+)
+
+// ErrNothingToPush is returned when the directory being pushed has the same contents as the base branch
+var ErrNothingToPush = errors.New("there are no changes to push")
+
+// GitPushOptions is how a directory is committed to a new branch of a git repo
+type GitPushOptions struct {
+	// RepoURL is the http(s) or ssh url of the repo
+	RepoURL string
+	// BaseBranch is the branch the new branch is created from. Empty means the default branch.
+	BaseBranch string
+	// Branch is the name of the new branch
+	Branch string
+	// PathWithinRepo is the directory inside the repo whose contents are replaced by the directory being pushed
+	PathWithinRepo string
+	// CommitMessage is the message of the commit
+	CommitMessage string
+	// Username is used along with the token for http(s) repos
+	Username string
+	// Token is the password or token for http(s) repos. Empty means the credentials are taken from the environment.
+	Token string
+}
+
+// PushDirectory commits the contents of the directory to a new branch of the git repo, pushes it and returns the base branch
+func PushDirectory(srcPath string, opts GitPushOptions) (string, error) {
+	if opts.Branch == "" {
+		return "", fmt.Errorf("the name of the branch to push to is empty")
+	}
+	if err := os.MkdirAll(common.TempPath, common.DefaultDirectoryPermission); err != nil {
+		return "", fmt.Errorf("failed to create the directory %s . Error: %w", common.TempPath, err)
+	}
+	tempPath, err := os.MkdirTemp(common.TempPath, "push-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create a temporary directory inside %s . Error: %w", common.TempPath, err)
+	}
+	defer os.RemoveAll(tempPath)
+	auth, err := getGitPushAuth(opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the credentials for the git repo %s . Error: %w", opts.RepoURL, err)
+	}
+	cloneOpts := &git.CloneOptions{URL: opts.RepoURL, Auth: auth, Depth: 1, SingleBranch: true}
+	if opts.BaseBranch != "" {
+		cloneOpts.ReferenceName = plumbing.NewBranchReferenceName(opts.BaseBranch)
+	}
+	// the git objects are kept in memory so that the worktree can be replaced with the directory being pushed
+	worktreePath := filepath.Join(tempPath, "worktree")
+	logrus.Infof("Cloning the git repo %s (branch: %q) to push to", opts.RepoURL, opts.BaseBranch)
+	repo, err := git.Clone(memory.NewStorage(), osfs.New(worktreePath), cloneOpts)
+	if err != nil {
+		return "", fmt.Errorf("failed to clone the git repo %s . Error: %w", opts.RepoURL, err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the HEAD of the git repo %s . Error: %w", opts.RepoURL, err)
+	}
+	baseBranch := head.Name().Short()
+	workTree, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the worktree of the git repo %s . Error: %w", opts.RepoURL, err)
+	}
+	if err := workTree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(opts.Branch), Create: true}); err != nil {
+		return "", fmt.Errorf("failed to create the branch %s . Error: %w", opts.Branch, err)
+	}
+	destPath := filepath.Join(worktreePath, filepath.FromSlash(strings.Trim(opts.PathWithinRepo, "/")))
+	if err := os.MkdirAll(destPath, common.DefaultDirectoryPermission); err != nil {
+		return "", fmt.Errorf("failed to create the directory %s . Error: %w", destPath, err)
+	}
+	if err := filesystem.Replicate(srcPath, destPath); err != nil {
+		return "", fmt.Errorf("failed to copy the directory %s into the git repo. Error: %w", srcPath, err)
+	}
+	if err := workTree.AddWithOptions(&git.AddOptions{All: true}); err != nil {
+		return "", fmt.Errorf("failed to stage the changes. Error: %w", err)
+	}
+	status, err := workTree.Status()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the status of the worktree. Error: %w", err)
+	}
+	if status.IsClean() {
+		return baseBranch, ErrNothingToPush
+	}
+	commitOpts := &git.CommitOptions{
+		All:    true,
+		Author: &object.Signature{Name: defaultCommitAuthorName, Email: defaultCommitAuthorEmail, When: time.Now()},
+	}
+	if _, err := workTree.Commit(opts.CommitMessage, commitOpts); err != nil {
+		return "", fmt.Errorf("failed to commit the changes. Error: %w", err)
+	}
+	refSpec := config.RefSpec(fmt.Sprintf("refs/heads/%s:refs/heads/%s", opts.Branch, opts.Branch))
+	logrus.Infof("Pushing the branch %s to the git repo %s", opts.Branch, opts.RepoURL)
+	if err := repo.Push(&git.PushOptions{RemoteName: git.DefaultRemoteName, RefSpecs: []config.RefSpec{refSpec}, Auth: auth}); err != nil {
+		return "", fmt.Errorf("failed to push the branch %s to the git repo %s . Error: %w", opts.Branch, opts.RepoURL, err)
+	}
+	return baseBranch, nil
+}
+
+// getGitPushAuth returns the given credentials for http(s) repos, falling back to the ones from the environment
+func getGitPushAuth(opts GitPushOptions) (transport.AuthMethod, error) {
+	if opts.Token == "" || !(strings.HasPrefix(opts.RepoURL, "http://") || strings.HasPrefix(opts.RepoURL, "https://")) {
+		return getGitAuth(opts.RepoURL)
+	}
+	username := opts.Username
+	if username == "" {
+		// when using tokens any non-empty username works
+		username = defaultGitUser
+	}
+	return &http.BasicAuth{Username: username, Password: opts.Token}, nil
+}
@@ -0,0 +1,88 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package vcs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/konveyor/move2kube/common"
+)
+
+func TestPushDirectory(t *testing.T) {
+	t.Setenv(sshAuthSockEnvKey, "")
+	t.Setenv(gitSSHKeyPathEnvKey, "")
+	common.TempPath = t.TempDir()
+	remotePath := filepath.Join(t.TempDir(), "remote")
+	remote, err := git.PlainInit(remotePath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(remotePath, "README.md"), []byte("deploy"), common.DefaultFilePermission); err != nil {
+		t.Fatal(err)
+	}
+	remoteWorkTree, err := remote.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := remoteWorkTree.Add("README.md"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := remoteWorkTree.Commit("init", &git.CommitOptions{Author: &object.Signature{Name: "test", When: time.Now()}}); err != nil {
+		t.Fatal(err)
+	}
+	srcPath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(srcPath, "app.yaml"), []byte("kind: Deployment"), common.DefaultFilePermission); err != nil {
+		t.Fatal(err)
+	}
+	opts := GitPushOptions{RepoURL: remotePath, Branch: "move2kube", PathWithinRepo: "deploy", CommitMessage: "Add the manifests"}
+	baseBranch, err := PushDirectory(srcPath, opts)
+	if err != nil {
+		t.Fatalf("failed to push the directory. Error: %q", err)
+	}
+	if baseBranch != "master" {
+		t.Errorf("the base branch is incorrect. Actual: %s", baseBranch)
+	}
+	ref, err := remote.Reference(plumbing.NewBranchReferenceName("move2kube"), true)
+	if err != nil {
+		t.Fatalf("the branch was not pushed. Error: %q", err)
+	}
+	commit, err := remote.CommitObject(ref.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := commit.File("README.md"); err != nil {
+		t.Errorf("the files outside the path within the repo should be kept. Error: %q", err)
+	}
+	file, err := commit.File("deploy/app.yaml")
+	if err != nil {
+		t.Fatalf("the directory was not committed. Error: %q", err)
+	}
+	if content, _ := file.Contents(); content != "kind: Deployment" {
+		t.Errorf("the committed file is incorrect. Actual: %q", content)
+	}
+	opts.BaseBranch, opts.Branch = "move2kube", "move2kube-again"
+	if _, err := PushDirectory(srcPath, opts); !errors.Is(err, ErrNothingToPush) {
+		t.Errorf("expected nothing to push when the contents are the same. Error: %v", err)
+	}
+}
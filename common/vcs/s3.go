@@ -0,0 +1,117 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package vcs
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	awsAccessKeyIDEnvKey     = "AWS_ACCESS_KEY_ID"
+	awsSecretAccessKeyEnvKey = "AWS_SECRET_ACCESS_KEY"
+	awsSessionTokenEnvKey    = "AWS_SESSION_TOKEN"
+	awsRegionEnvKey          = "AWS_REGION"
+	awsDefaultRegionEnvKey   = "AWS_DEFAULT_REGION"
+	// awsEndpointURLEnvKey can be used to point to S3 compatible object storage like MinIO or IBM COS
+	awsEndpointURLEnvKey = "AWS_ENDPOINT_URL"
+	defaultAWSRegion     = "us-east-1"
+	awsUnsignedPayload   = "UNSIGNED-PAYLOAD"
+	awsAlgorithm         = "AWS4-HMAC-SHA256"
+	awsDateTimeFormat    = "20060102T150405Z"
+	awsDateFormat        = "20060102"
+)
+
+// newS3GetObjectRequest creates a request to get an object from S3.
+// If credentials are available in the environment, the request is signed using AWS signature version 4.
+func newS3GetObjectRequest(bucket, key string) (*http.Request, error) {
+	region := os.Getenv(awsRegionEnvKey)
+	if region == "" {
+		region = os.Getenv(awsDefaultRegionEnvKey)
+	}
+	if region == "" {
+		region = defaultAWSRegion
+	}
+	var objectURL *url.URL
+	if endpoint := os.Getenv(awsEndpointURLEnvKey); endpoint != "" {
+		endpointURL, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("the endpoint %s in the environment variable %s is not a valid url. Error: %w", endpoint, awsEndpointURLEnvKey, err)
+		}
+		// custom endpoints use path style addressing
+		endpointURL.Path = strings.TrimSuffix(endpointURL.Path, "/") + "/" + bucket + "/" + key
+		objectURL = endpointURL
+	} else {
+		objectURL = &url.URL{Scheme: "https", Host: bucket + ".s3." + region + ".amazonaws.com", Path: "/" + key}
+	}
+	req, err := http.NewRequest(http.MethodGet, objectURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	accessKeyID := os.Getenv(awsAccessKeyIDEnvKey)
+	secretAccessKey := os.Getenv(awsSecretAccessKeyEnvKey)
+	if accessKeyID == "" || secretAccessKey == "" {
+		return req, nil
+	}
+	signS3Request(req, region, accessKeyID, secretAccessKey, os.Getenv(awsSessionTokenEnvKey), time.Now().UTC())
+	return req, nil
+}
+
+// signS3Request adds the AWS signature version 4 headers to a request without a body
+func signS3Request(req *http.Request, region, accessKeyID, secretAccessKey, sessionToken string, now time.Time) {
+	amzDate := now.Format(awsDateTimeFormat)
+	date := now.Format(awsDateFormat)
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", awsUnsignedPayload)
+	signedHeaders := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	canonicalHeaders := "host:" + req.URL.Host + "\n" + "x-amz-content-sha256:" + awsUnsignedPayload + "\n" + "x-amz-date:" + amzDate + "\n"
+	if sessionToken != "" {
+		req.Header.Set("x-amz-security-token", sessionToken)
+		signedHeaders = append(signedHeaders, "x-amz-security-token")
+		canonicalHeaders += "x-amz-security-token:" + sessionToken + "\n"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders,
+		strings.Join(signedHeaders, ";"),
+		awsUnsignedPayload,
+	}, "\n")
+	scope := date + "/" + region + "/s3/aws4_request"
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := awsAlgorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalRequestHash[:])
+	signingKey := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", awsAlgorithm, accessKeyID, scope, strings.Join(signedHeaders, ";"), signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,82 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package vcs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/sirupsen/logrus"
+)
+
+// VCS defines a version control system from which sources and customizations can be fetched
+type VCS interface {
+	// Load fetches the remote contents into the given directory and returns the path to use
+	Load(outputPath string) (string, error)
+}
+
+// getVCS returns the VCS that can handle the remote path
+func getVCS(remotePath string) (VCS, error) {
+	if isGitRemotePath(remotePath) {
+		return getGitRepoStructFromRemotePath(remotePath)
+	}
+	if isArchiveRemotePath(remotePath) {
+		return getArchiveRemoteFromRemotePath(remotePath)
+	}
+	if isOCIRemotePath(remotePath) {
+		return getOCIRemoteFromRemotePath(remotePath)
+	}
+	if isLocalArchivePath(remotePath) {
+		return getLocalArchiveFromPath(remotePath)
+	}
+	return nil, fmt.Errorf("the path %s is not a supported remote path", remotePath)
+}
+
+// IsRemotePath returns true if the given path refers to a remote location or a local archive that needs to be fetched
+func IsRemotePath(path string) bool {
+	return isGitRemotePath(path) || isArchiveRemotePath(path) || isOCIRemotePath(path) || isLocalArchivePath(path)
+}
+
+// FetchRemotePath fetches the remote path into a new directory inside the temp directory and returns the local path
+func FetchRemotePath(remotePath, tempPath string) (string, error) {
+	v, err := getVCS(remotePath)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(tempPath, common.DefaultDirectoryPermission); err != nil {
+		return "", fmt.Errorf("failed to create the directory %s . Error: %w", tempPath, err)
+	}
+	outputPath, err := os.MkdirTemp(tempPath, "remote-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create a temporary directory inside %s . Error: %w", tempPath, err)
+	}
+	localPath, err := v.Load(outputPath)
+	if err != nil {
+		if rmErr := os.RemoveAll(outputPath); rmErr != nil {
+			logrus.Debugf("failed to remove the directory %s . Error: %q", outputPath, rmErr)
+		}
+		return "", err
+	}
+	localPath, err = filepath.Abs(localPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to make the path %s absolute. Error: %w", localPath, err)
+	}
+	logrus.Infof("Fetched the remote path %s into %s", remotePath, localPath)
+	return localPath, nil
+}
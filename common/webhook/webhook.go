@@ -0,0 +1,163 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/sirupsen/logrus"
+)
+
+// EventTypeT is the type of a lifecycle event
+type EventTypeT string
+
+const (
+	// PlanCompleted is sent when the plan has been written
+	PlanCompleted EventTypeT = "plan.completed"
+	// PlanFailed is sent when the planning fails
+	PlanFailed EventTypeT = "plan.failed"
+	// TransformCompleted is sent when the output has been written
+	TransformCompleted EventTypeT = "transform.completed"
+	// TransformFailed is sent when the transformation fails
+	TransformFailed EventTypeT = "transform.failed"
+	// QuestionStalled is sent when a question stays unanswered for too long
+	QuestionStalled EventTypeT = "question.stalled"
+)
+
+const (
+	sendTimeout = 10 * time.Second
+	maxAttempts = 3
+)
+
+// EventT is the json body posted to the webhooks
+type EventT struct {
+	Type      EventTypeT             `json:"type"`
+	Timestamp time.Time              `json:"timestamp"`
+	Project   string                 `json:"project"`
+	Message   string                 `json:"message"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+var (
+	urls       = []string{}
+	urlsMutex  sync.Mutex
+	httpClient = &http.Client{Timeout: sendTimeout}
+	// retryDelay is the delay before the first retry. It doubles for every retry.
+	retryDelay = time.Second
+)
+
+// SetURLs sets the urls that receive the events
+func SetURLs(newURLs []string) {
+	urlsMutex.Lock()
+	defer urlsMutex.Unlock()
+	urls = []string{}
+	for _, url := range newURLs {
+		if url != "" {
+			urls = common.AppendIfNotPresent(urls, url)
+		}
+	}
+}
+
+func getURLs() []string {
+	urlsMutex.Lock()
+	defer urlsMutex.Unlock()
+	return append([]string{}, urls...)
+}
+
+// Send posts the event to all the webhooks. Failures are logged and otherwise ignored.
+func Send(eventType EventTypeT, message string, data map[string]interface{}) {
+	webhookURLs := getURLs()
+	if len(webhookURLs) == 0 {
+		return
+	}
+	event := EventT{Type: eventType, Timestamp: time.Now().UTC(), Project: common.ProjectName, Message: message, Data: data}
+	body, err := json.Marshal(event)
+	if err != nil {
+		logrus.Errorf("failed to marshal the %s event to json. Error: %q", eventType, err)
+		return
+	}
+	wg := sync.WaitGroup{}
+	for _, url := range webhookURLs {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			if err := post(url, body); err != nil {
+				logrus.Warnf("failed to send the %s event to the webhook %s . Error: %q", eventType, url, err)
+				return
+			}
+			logrus.Debugf("sent the %s event to the webhook %s", eventType, url)
+		}(url)
+	}
+	wg.Wait()
+}
+
+// post sends the body to the url, retrying on network errors and server errors
+func post(url string, body []byte) error {
+	var err error
+	delay := retryDelay
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		var resp *http.Response
+		resp, err = httpClient.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode < http.StatusBadRequest {
+			return nil
+		}
+		err = fmt.Errorf("the webhook responded with the status %d", resp.StatusCode)
+		if resp.StatusCode < http.StatusInternalServerError {
+			// the request will not succeed on a retry
+			return err
+		}
+	}
+	return err
+}
+
+// FailureHook sends a failure event when a fatal error is logged
+type FailureHook struct {
+	eventType EventTypeT
+}
+
+// NewFailureHook creates a hook that sends the given event on fatal and panic errors
+func NewFailureHook(eventType EventTypeT) *FailureHook {
+	return &FailureHook{eventType: eventType}
+}
+
+// Fire sends the failure event
+func (hook *FailureHook) Fire(entry *logrus.Entry) error {
+	Send(hook.eventType, entry.Message, nil)
+	return nil
+}
+
+// Levels returns the levels on which the failure hook gets called
+func (hook *FailureHook) Levels() []logrus.Level {
+	return []logrus.Level{
+		logrus.PanicLevel,
+		logrus.FatalLevel,
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSend(t *testing.T) {
+	retryDelay = time.Millisecond
+	defer func() { retryDelay = time.Second }()
+	defer SetURLs(nil)
+
+	t.Run("event is posted to the webhook", func(t *testing.T) {
+		events := []EventT{}
+		mutex := sync.Mutex{}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			event := EventT{}
+			if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+				t.Errorf("failed to decode the event. Error: %q", err)
+			}
+			mutex.Lock()
+			events = append(events, event)
+			mutex.Unlock()
+		}))
+		defer ts.Close()
+		SetURLs([]string{ts.URL, ts.URL})
+		Send(PlanCompleted, "done", map[string]interface{}{"services": 2})
+		if len(events) != 1 {
+			t.Fatalf("expected exactly one event. Actual: %+v", events)
+		}
+		if events[0].Type != PlanCompleted || events[0].Message != "done" || events[0].Data["services"] != float64(2) {
+			t.Fatalf("the event is incorrect. Actual: %+v", events[0])
+		}
+	})
+
+	t.Run("server errors are retried and client errors are not", func(t *testing.T) {
+		for _, tc := range []struct {
+			status   int
+			attempts int
+		}{
+			{status: http.StatusInternalServerError, attempts: maxAttempts},
+			{status: http.StatusNotFound, attempts: 1},
+		} {
+			attempts := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.WriteHeader(tc.status)
+			}))
+			SetURLs([]string{ts.URL})
+			Send(TransformFailed, "failed", nil)
+			ts.Close()
+			if attempts != tc.attempts {
+				t.Fatalf("expected %d attempts for the status %d. Actual: %d", tc.attempts, tc.status, attempts)
+			}
+		}
+	})
+}
@@ -0,0 +1,149 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/konveyor/move2kube/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// WorkspaceManifestFile is the file in the temp workspace that records the run that created it
+	WorkspaceManifestFile = types.AppNameShort + "run.yaml"
+	// DefaultStaleWorkspaceAge is the age after which a temp workspace whose run cannot be checked is considered stale
+	DefaultStaleWorkspaceAge = 24 * time.Hour
+)
+
+var (
+	// KeepTempWorkspace stops the temp workspace from being removed when the run ends
+	KeepTempWorkspace   bool
+	removeWorkspaceOnce sync.Once
+)
+
+// WorkspaceManifest records the run that owns a temp workspace
+type WorkspaceManifest struct {
+	PID       int       `yaml:"pid"`
+	Hostname  string    `yaml:"hostname"`
+	Command   []string  `yaml:"command"`
+	StartTime time.Time `yaml:"startTime"`
+}
+
+// StaleWorkspace is a temp workspace left behind by a run that is no longer running
+type StaleWorkspace struct {
+	Path   string
+	Reason string
+}
+
+// WriteWorkspaceManifest records the current run in the temp workspace
+func WriteWorkspaceManifest(tempPath string) error {
+	hostname, err := os.Hostname()
+	if err != nil {
+		logrus.Debugf("failed to get the hostname. Error: %q", err)
+	}
+	manifest := WorkspaceManifest{
+		PID:       os.Getpid(),
+		Hostname:  hostname,
+		Command:   os.Args,
+		StartTime: time.Now().UTC(),
+	}
+	return WriteYaml(filepath.Join(tempPath, WorkspaceManifestFile), manifest)
+}
+
+// RemoveTempWorkspace removes the temp workspace of the current run unless it should be kept.
+// It is safe to call more than once, only the first call has any effect.
+func RemoveTempWorkspace() {
+	removeWorkspaceOnce.Do(func() {
+		if KeepTempWorkspace {
+			logrus.Infof("Keeping the temporary directory %s", TempPath)
+			return
+		}
+		if err := os.RemoveAll(TempPath); err != nil {
+			logrus.Debugf("failed to remove the temporary directory %s . Error: %q", TempPath, err)
+		}
+	})
+}
+
+// GetStaleWorkspaces returns the temp workspaces in the directory that were left behind by runs that are no longer running.
+// Workspaces without a manifest or created on another host are stale once they are older than maxAge.
+func GetStaleWorkspaces(dir string, maxAge time.Duration) ([]StaleWorkspace, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the directory %s . Error: %w", dir, err)
+	}
+	hostname, _ := os.Hostname()
+	currentTempPath, _ := filepath.Abs(TempPath)
+	staleWorkspaces := []StaleWorkspace{}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), types.AppName) {
+			continue
+		}
+		workspacePath := filepath.Join(dir, entry.Name())
+		if workspacePath == currentTempPath {
+			continue
+		}
+		manifest := WorkspaceManifest{}
+		if err := ReadYaml(filepath.Join(workspacePath, WorkspaceManifestFile), &manifest); err != nil {
+			// workspaces created before the manifest was introduced are only recognized by the assets directory
+			if _, err := os.Stat(filepath.Join(workspacePath, AssetsDir)); err != nil {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil || time.Since(info.ModTime()) < maxAge {
+				continue
+			}
+			staleWorkspaces = append(staleWorkspaces, StaleWorkspace{Path: workspacePath, Reason: fmt.Sprintf("it has no run manifest and is older than %s", maxAge)})
+			continue
+		}
+		if manifest.Hostname != hostname {
+			if time.Since(manifest.StartTime) < maxAge {
+				continue
+			}
+			staleWorkspaces = append(staleWorkspaces, StaleWorkspace{Path: workspacePath, Reason: fmt.Sprintf("it was created on the host %s more than %s ago", manifest.Hostname, maxAge)})
+			continue
+		}
+		if isProcessRunning(manifest.PID) {
+			continue
+		}
+		staleWorkspaces = append(staleWorkspaces, StaleWorkspace{Path: workspacePath, Reason: fmt.Sprintf("the run with PID %d that created it is no longer running", manifest.PID)})
+	}
+	return staleWorkspaces, nil
+}
+
+// CleanupStaleWorkspaces removes the stale temp workspaces in the directory and returns the ones that were removed
+func CleanupStaleWorkspaces(dir string, maxAge time.Duration) ([]StaleWorkspace, error) {
+	staleWorkspaces, err := GetStaleWorkspaces(dir, maxAge)
+	if err != nil {
+		return nil, err
+	}
+	removed := []StaleWorkspace{}
+	for _, staleWorkspace := range staleWorkspaces {
+		if err := os.RemoveAll(staleWorkspace.Path); err != nil {
+			logrus.Errorf("Failed to remove the stale temporary directory %s . Error: %q", staleWorkspace.Path, err)
+			continue
+		}
+		logrus.Debugf("Removed the stale temporary directory %s since %s", staleWorkspace.Path, staleWorkspace.Reason)
+		removed = append(removed, staleWorkspace)
+	}
+	return removed, nil
+}
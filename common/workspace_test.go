@@ -0,0 +1,93 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/types"
+)
+
+func TestGetStaleWorkspaces(t *testing.T) {
+	dir := t.TempDir()
+	hostname, _ := os.Hostname()
+	exited := exec.Command("go", "version")
+	if err := exited.Run(); err != nil {
+		t.Fatalf("failed to run a process. Error: %q", err)
+	}
+	createWorkspace := func(name string, manifest *WorkspaceManifest, modTime time.Time) string {
+		workspacePath := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Join(workspacePath, AssetsDir), DefaultDirectoryPermission); err != nil {
+			t.Fatalf("failed to create the workspace %s . Error: %q", workspacePath, err)
+		}
+		if manifest != nil {
+			if err := WriteYaml(filepath.Join(workspacePath, WorkspaceManifestFile), manifest); err != nil {
+				t.Fatalf("failed to write the workspace manifest. Error: %q", err)
+			}
+		}
+		if err := os.Chtimes(workspacePath, modTime, modTime); err != nil {
+			t.Fatalf("failed to change the modification time of %s . Error: %q", workspacePath, err)
+		}
+		return workspacePath
+	}
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+	createWorkspace(types.AppName+"running", &WorkspaceManifest{PID: os.Getpid(), Hostname: hostname, StartTime: old}, old)
+	exitedPath := createWorkspace(types.AppName+"exited", &WorkspaceManifest{PID: exited.Process.Pid, Hostname: hostname, StartTime: now}, now)
+	createWorkspace(types.AppName+"otherhostnew", &WorkspaceManifest{PID: os.Getpid(), Hostname: hostname + "-other", StartTime: now}, now)
+	otherHostOldPath := createWorkspace(types.AppName+"otherhostold", &WorkspaceManifest{PID: os.Getpid(), Hostname: hostname + "-other", StartTime: old}, old)
+	createWorkspace(types.AppName+"nomanifestnew", nil, now)
+	noManifestOldPath := createWorkspace(types.AppName+"nomanifestold", nil, old)
+	createWorkspace("unrelated", nil, old)
+	if err := os.MkdirAll(filepath.Join(dir, types.AppName+"notaworkspace"), DefaultDirectoryPermission); err != nil {
+		t.Fatalf("failed to create the directory. Error: %q", err)
+	}
+
+	staleWorkspaces, err := GetStaleWorkspaces(dir, DefaultStaleWorkspaceAge)
+	if err != nil {
+		t.Fatalf("failed to get the stale workspaces. Error: %q", err)
+	}
+	stalePaths := []string{}
+	for _, staleWorkspace := range staleWorkspaces {
+		stalePaths = append(stalePaths, staleWorkspace.Path)
+	}
+	sort.Strings(stalePaths)
+	want := []string{exitedPath, noManifestOldPath, otherHostOldPath}
+	sort.Strings(want)
+	if !cmp.Equal(stalePaths, want) {
+		t.Fatalf("failed to get the stale workspaces. Difference:\n%s", cmp.Diff(want, stalePaths))
+	}
+
+	removed, err := CleanupStaleWorkspaces(dir, DefaultStaleWorkspaceAge)
+	if err != nil {
+		t.Fatalf("failed to cleanup the stale workspaces. Error: %q", err)
+	}
+	if len(removed) != len(want) {
+		t.Fatalf("expected %d workspaces to be removed. Actual: %+v", len(want), removed)
+	}
+	for _, path := range want {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("expected the stale workspace %s to be removed. Error: %v", path, err)
+		}
+	}
+}
@@ -29,10 +29,12 @@ import (
 	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/docker/pkg/stdcopy"
 
 	// "github.com/docker/docker/pkg/stdcopy"
 	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/imagecache"
 	environmenttypes "github.com/konveyor/move2kube/types/environment"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cast"
@@ -87,6 +89,14 @@ func (e *dockerEngine) pullImage(image string) error {
 	if _, ok := e.availableImages[image]; ok {
 		return nil
 	}
+	if imageCache := imagecache.Get(); imageCache != nil {
+		err := e.loadImageFromCache(imageCache, image)
+		if err == nil {
+			e.availableImages[image] = true
+			return nil
+		}
+		logrus.Warnf("Failed to load the image %s from the image cache. Pulling it using docker. Error: %q", image, err)
+	}
 	logrus.Infof("Pulling container image %s. This could take a few mins.", image)
 	out, err := e.cli.ImagePull(e.ctx, image, types.ImagePullOptions{})
 	if err != nil {
@@ -100,6 +110,24 @@ func (e *dockerEngine) pullImage(image string) error {
 	return nil
 }
 
+// loadImageFromCache loads the image from the image cache into docker, pulling it into the cache if necessary
+func (e *dockerEngine) loadImageFromCache(imageCache *imagecache.Cache, image string) error {
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(imageCache.WriteDockerTarball(image, writer))
+	}()
+	resp, err := e.cli.ImageLoad(e.ctx, reader, true)
+	if err != nil {
+		reader.CloseWithError(err)
+		return fmt.Errorf("failed to load the image '%s' using the docker client. Error: %w", image, err)
+	}
+	defer resp.Body.Close()
+	if err := jsonmessage.DisplayJSONMessagesStream(resp.Body, io.Discard, 0, false, nil); err != nil {
+		return fmt.Errorf("failed to load the image '%s' using the docker client. Error: %w", image, err)
+	}
+	return nil
+}
+
 // RunCmdInContainer executes a container
 func (e *dockerEngine) RunCmdInContainer(containerID string, cmd environmenttypes.Command, workingdir string, env []string) (stdout, stderr string, exitCode int, err error) {
 	execConfig := types.ExecConfig{
@@ -27,6 +27,7 @@ import (
 	"runtime"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/konveyor/move2kube/common"
 	"github.com/konveyor/move2kube/common/deepcopy"
@@ -79,7 +80,7 @@ type EnvironmentInstance interface {
 	Stat(name string) (fs.FileInfo, error)
 	Download(envpath string) (outpath string, err error)
 	Upload(outpath string) (envpath string, err error)
-	Exec(cmd environmenttypes.Command) (stdout string, stderr string, exitcode int, err error)
+	Exec(cmd environmenttypes.Command, timeout time.Duration) (stdout string, stderr string, exitcode int, err error)
 	Destroy() error
 
 	GetSource() string
@@ -164,10 +165,16 @@ func (e *Environment) Reset() error {
 
 // Exec executes an executable within the environment
 func (e *Environment) Exec(cmd environmenttypes.Command) (stdout string, stderr string, exitcode int, err error) {
+	return e.ExecWithTimeout(cmd, 0)
+}
+
+// ExecWithTimeout executes an executable within the environment and stops it if it runs longer than the timeout.
+// A timeout of 0 waits for the executable to finish.
+func (e *Environment) ExecWithTimeout(cmd environmenttypes.Command, timeout time.Duration) (stdout string, stderr string, exitcode int, err error) {
 	if !e.active {
 		return "", "", 0, ErrEnvironmentNotActive
 	}
-	return e.Env.Exec(cmd)
+	return e.Env.Exec(cmd, timeout)
 }
 
 // Destroy destroys all artifacts specific to the environment
@@ -413,6 +420,9 @@ func (e *Environment) ProcessPathMappings(pathMappings []transformertypes.PathMa
 				(pm.SrcPath == "" || !filepath.IsAbs(pm.SrcPath)) {
 				dupPathMappings[pmi].SrcPath = filepath.Join(e.GetEnvironmentContext(), e.RelTemplatesDir, pm.SrcPath)
 			}
+			if strings.EqualFold(string(pm.Type), string(transformertypes.TemplatePathMappingType)) && pm.TemplateOptions == nil {
+				dupPathMappings[pmi].TemplateOptions = e.getTemplateOptions()
+			}
 			tempMappings = append(tempMappings, dupPathMappings[pmi])
 		}
 	}
@@ -420,6 +430,19 @@ func (e *Environment) ProcessPathMappings(pathMappings []transformertypes.PathMa
 	return dupPathMappings
 }
 
+// getTemplateOptions returns the template options of the transformer with the verbatim paths made absolute
+func (e *Environment) getTemplateOptions() *transformertypes.TemplateOptions {
+	templateOptions := e.TemplateOptions
+	templateOptions.VerbatimPaths = []string{}
+	for _, verbatimPath := range e.TemplateOptions.VerbatimPaths {
+		if !filepath.IsAbs(verbatimPath) {
+			verbatimPath = filepath.Join(e.GetEnvironmentContext(), e.RelTemplatesDir, verbatimPath)
+		}
+		templateOptions.VerbatimPaths = append(templateOptions.VerbatimPaths, verbatimPath)
+	}
+	return &templateOptions
+}
+
 // SourceRel makes the path relative. Exposed to be used within path-mapping destination-path template.
 func (e *Environment) SourceRel(destPath string) (string, error) {
 	if !common.IsParent(destPath, e.GetEnvironmentSource()) {
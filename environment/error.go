@@ -21,4 +21,6 @@ import "errors"
 var (
 	// ErrEnvironmentNotActive represents the error when an environment is not active and a function is called on it.
 	ErrEnvironmentNotActive = errors.New("environment Not active. Process is terminating")
+	// ErrExecTimedOut represents the error when a command executed in an environment runs longer than its timeout.
+	ErrExecTimedOut = errors.New("the command did not finish before the timeout")
 )
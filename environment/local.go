@@ -18,6 +18,7 @@ package environment
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -25,6 +26,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"time"
 
 	"github.com/konveyor/move2kube/common"
 	"github.com/konveyor/move2kube/filesystem"
@@ -86,15 +88,21 @@ func (e *Local) Stat(name string) (fs.FileInfo, error) {
 	return os.Stat(name)
 }
 
-// Exec executes an executable within the environment
-func (e *Local) Exec(cmd environmenttypes.Command) (stdout string, stderr string, exitcode int, err error) {
+// Exec executes an executable within the environment, killing it if it runs longer than the timeout
+func (e *Local) Exec(cmd environmenttypes.Command, timeout time.Duration) (stdout string, stderr string, exitcode int, err error) {
 	if common.DisableLocalExecution {
 		return "", "", 0, fmt.Errorf("local execution prevented by %s flag", common.DisableLocalExecutionFlag)
 	}
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 	var outb, errb bytes.Buffer
 	var execcmd *exec.Cmd
 	if len(cmd) > 0 {
-		execcmd = exec.Command(cmd[0], cmd[1:]...)
+		execcmd = exec.CommandContext(ctx, cmd[0], cmd[1:]...)
 	} else {
 		return "", "", 0, fmt.Errorf("no command found to execute")
 	}
@@ -104,6 +112,9 @@ func (e *Local) Exec(cmd environmenttypes.Command) (stdout string, stderr string
 	execcmd.Env = e.getEnv()
 	if err := execcmd.Run(); err != nil {
 		var ee *exec.ExitError
+		if ctx.Err() == context.DeadlineExceeded {
+			return outb.String(), errb.String(), -1, fmt.Errorf("the command %v was killed after %s . Error: %w", cmd, timeout, ErrExecTimedOut)
+		}
 		var pe *os.PathError
 		if errors.As(err, &ee) {
 			exitcode = ee.ExitCode()
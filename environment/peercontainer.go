@@ -23,6 +23,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/dchest/uniuri"
 	"github.com/konveyor/move2kube/environment/container"
@@ -136,8 +137,10 @@ func (e *PeerContainer) Stat(name string) (fs.FileInfo, error) {
 	return cengine.Stat(e.ContainerInfo.ID, name)
 }
 
-// Exec executes a command in the container
-func (e *PeerContainer) Exec(cmd environmenttypes.Command) (stdout string, stderr string, exitcode int, err error) {
+// Exec executes a command in the container.
+// If the command runs longer than the timeout, the error is returned without waiting for it,
+// and the command keeps running until the container is destroyed.
+func (e *PeerContainer) Exec(cmd environmenttypes.Command, timeout time.Duration) (stdout string, stderr string, exitcode int, err error) {
 	cengine, err := container.GetContainerEngine(false)
 	if err != nil {
 		return "", "", 0, fmt.Errorf("failed to get the container engine. Error: %w", err)
@@ -148,7 +151,26 @@ func (e *PeerContainer) Exec(cmd environmenttypes.Command) (stdout string, stder
 		port := cast.ToString(e.GRPCQAReceiver.(*net.TCPAddr).Port)
 		envs = append(envs, GRPCEnvName+"="+hostname+":"+port)
 	}
-	return cengine.RunCmdInContainer(e.ContainerInfo.ID, cmd, e.ContainerInfo.WorkingDir, envs)
+	if timeout <= 0 {
+		return cengine.RunCmdInContainer(e.ContainerInfo.ID, cmd, e.ContainerInfo.WorkingDir, envs)
+	}
+	type execResult struct {
+		stdout   string
+		stderr   string
+		exitcode int
+		err      error
+	}
+	done := make(chan execResult, 1)
+	go func() {
+		stdout, stderr, exitcode, err := cengine.RunCmdInContainer(e.ContainerInfo.ID, cmd, e.ContainerInfo.WorkingDir, envs)
+		done <- execResult{stdout: stdout, stderr: stderr, exitcode: exitcode, err: err}
+	}()
+	select {
+	case result := <-done:
+		return result.stdout, result.stderr, result.exitcode, result.err
+	case <-time.After(timeout):
+		return "", "", -1, fmt.Errorf("the command %v in the container with ID '%s' did not finish in %s . Error: %w", cmd, e.ContainerInfo.ID, timeout, ErrExecTimedOut)
+	}
 }
 
 // Destroy destroys the container instance
@@ -18,6 +18,7 @@ package environment
 
 import (
 	environmenttypes "github.com/konveyor/move2kube/types/environment"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
 )
 
 // EnvInfo stores the envionment generic info
@@ -32,6 +33,7 @@ type EnvInfo struct {
 	Context               string
 	CurrEnvOutputBasePath string
 	RelTemplatesDir       string
+	TemplateOptions       transformertypes.TemplateOptions
 	TempPath              string
 	EnvPlatformConfig     environmenttypes.EnvPlatformConfig
 	SpawnContainers       bool
@@ -21,11 +21,14 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/konveyor/move2kube/common"
 	"github.com/sirupsen/logrus"
 )
 
 type processor struct {
 	options options
+	// walkedDirs are the real paths of the source directories being processed, used to detect symbolic link cycles
+	walkedDirs []string
 }
 
 type options struct {
@@ -43,19 +46,40 @@ func newProcessor(options options) *processor {
 }
 
 func (p *processor) process(source, destination string) error {
-	si, err := os.Stat(source)
+	si, err := os.Lstat(source)
 	if err != nil {
 		return fmt.Errorf("failed to stat the source path '%s' . Error: %w", source, err)
 	}
+	if si.Mode()&os.ModeSymlink != 0 {
+		// the source path given by the caller is always followed, the policy applies to the links inside it
+		policy := common.FollowSymlinks
+		if len(p.walkedDirs) > 0 {
+			policy = common.GetSymlinkPolicy()
+		}
+		switch policy {
+		case common.SkipSymlinks:
+			common.ReportSkippedSymlink(source, "the symlink policy is "+string(common.SkipSymlinks))
+			return nil
+		case common.PreserveSymlinks:
+			return p.processSymLink(source, destination)
+		}
+		if si, err = os.Stat(source); err != nil {
+			common.ReportSkippedSymlink(source, "the target does not exist")
+			return nil
+		}
+		if si.IsDir() && common.IsSymlinkCycle(source, p.walkedDirs) {
+			common.ReportSkippedSymlink(source, "it forms a cycle")
+			return nil
+		}
+	}
+	if len(p.walkedDirs) > 0 && common.IsOverSourceLimits(source, si) {
+		return nil
+	}
 	switch si.Mode() & os.ModeType {
 	case os.ModeDir:
 		if err := p.processDirectory(source, destination); err != nil {
 			return err
 		}
-	case os.ModeSymlink:
-		if err := p.processSymLink(source, destination); err != nil {
-			return err
-		}
 	default:
 		di, err := os.Stat(destination)
 		if err == nil {
@@ -84,6 +108,10 @@ func (p *processor) processFile(source, destination string) error {
 }
 
 func (p *processor) processDirectory(source, destination string) error {
+	if realSource, err := filepath.EvalSymlinks(source); err == nil {
+		p.walkedDirs = append(p.walkedDirs, realSource)
+		defer func() { p.walkedDirs = p.walkedDirs[:len(p.walkedDirs)-1] }()
+	}
 	destEntryNames := map[string]bool{}
 	entries, err := os.ReadDir(source)
 	if err != nil {
@@ -134,5 +162,17 @@ func (p *processor) processSymLink(source, destination string) error {
 	if err != nil {
 		return err
 	}
+	if di, err := os.Lstat(destination); err == nil {
+		if di.Mode()&os.ModeSymlink != 0 {
+			if existingLink, err := os.Readlink(destination); err == nil && existingLink == link {
+				return nil
+			}
+		} else if di.IsDir() {
+			return fmt.Errorf("failed to copy the symbolic link '%s' since the destination '%s' is a directory", source, destination)
+		}
+		if err := os.Remove(destination); err != nil {
+			return fmt.Errorf("failed to remove the existing destination '%s' . Error: %w", destination, err)
+		}
+	}
 	return os.Symlink(link, destination)
 }
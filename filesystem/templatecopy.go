@@ -40,6 +40,7 @@ const (
 type AddOnConfig struct {
 	OpeningDelimiter string
 	ClosingDelimiter string
+	VerbatimPaths    []string // Glob patterns of the files and directories to be copied without templating
 	Config           interface{}
 }
 
@@ -101,9 +102,16 @@ func templateCopyProcessFileCallBack(sourceFilePath, destinationFilePath string,
 		}
 	}
 	defer destinationWriter.Close()
-	err = writeTemplateToFile(string(src), addOnConfig.Config,
-		destinationFilePath, si.Mode(),
-		addOnConfig.OpeningDelimiter, addOnConfig.ClosingDelimiter)
+	if isVerbatimPath(sourceFilePath, addOnConfig.VerbatimPaths) {
+		logrus.Debugf("copying the file %s without templating", sourceFilePath)
+		if _, err = destinationWriter.Write(src); err == nil {
+			err = os.Chmod(destinationFilePath, si.Mode())
+		}
+	} else {
+		err = writeTemplateToFile(string(src), addOnConfig.Config,
+			destinationFilePath, si.Mode(),
+			addOnConfig.OpeningDelimiter, addOnConfig.ClosingDelimiter)
+	}
 	if err != nil {
 		logrus.Errorf("Unable to copy templated file %s to %s : %s", sourceFilePath, destinationFilePath, err)
 		return err
@@ -140,6 +148,24 @@ func templateCopyDeletionCallBack(source, destination string, addOnConfigAsIface
 	return nil
 }
 
+// isVerbatimPath checks if the file or any of its parent directories matches one of the verbatim path patterns
+func isVerbatimPath(filePath string, verbatimPaths []string) bool {
+	for _, verbatimPath := range verbatimPaths {
+		for currPath := filepath.Clean(filePath); ; currPath = filepath.Dir(currPath) {
+			if matched, err := filepath.Match(verbatimPath, currPath); err != nil {
+				logrus.Errorf("invalid verbatim path pattern %s . Error: %q", verbatimPath, err)
+				break
+			} else if matched {
+				return true
+			}
+			if parentPath := filepath.Dir(currPath); parentPath == currPath {
+				break
+			}
+		}
+	}
+	return false
+}
+
 // execTemplate executes the template and returns the filled template
 func execTemplate(t *template.Template) func(string, interface{}) (string, error) {
 	return func(name string, v interface{}) (string, error) {
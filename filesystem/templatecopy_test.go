@@ -0,0 +1,87 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsVerbatimPath(t *testing.T) {
+	templatesDir := filepath.Join("transformer", "templates")
+	testCases := []struct {
+		name          string
+		filePath      string
+		verbatimPaths []string
+		want          bool
+	}{
+		{name: "no verbatim paths", filePath: filepath.Join(templatesDir, "Dockerfile")},
+		{name: "the file matches", filePath: filepath.Join(templatesDir, "Dockerfile"), verbatimPaths: []string{filepath.Join(templatesDir, "Dockerfile")}, want: true},
+		{name: "the file matches a glob", filePath: filepath.Join(templatesDir, "chart.tpl"), verbatimPaths: []string{filepath.Join(templatesDir, "*.tpl")}, want: true},
+		{name: "a parent directory matches", filePath: filepath.Join(templatesDir, "helm", "templates", "deployment.yaml"), verbatimPaths: []string{filepath.Join(templatesDir, "helm")}, want: true},
+		{name: "a sibling does not match", filePath: filepath.Join(templatesDir, "Dockerfile"), verbatimPaths: []string{filepath.Join(templatesDir, "helm")}},
+		{name: "invalid pattern", filePath: filepath.Join(templatesDir, "Dockerfile"), verbatimPaths: []string{"["}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := isVerbatimPath(testCase.filePath, testCase.verbatimPaths); got != testCase.want {
+				t.Fatalf("expected the path %s to be verbatim: %t Actual: %t", testCase.filePath, testCase.want, got)
+			}
+		})
+	}
+}
+
+func TestTemplateCopyWithOptions(t *testing.T) {
+	source := t.TempDir()
+	destination := t.TempDir()
+	files := map[string]string{
+		"config.yaml":                  "name: <% .Name %>\nimage: {{ .Image }}\n",
+		filepath.Join("helm", "a.tpl"): "name: {{ .Release.Name }}\n",
+	}
+	for path, content := range files {
+		path = filepath.Join(source, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create the directory %s . Error: %q", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write the file %s . Error: %q", path, err)
+		}
+	}
+	addOnConfig := AddOnConfig{
+		OpeningDelimiter: "<%",
+		ClosingDelimiter: "%>",
+		VerbatimPaths:    []string{filepath.Join(source, "helm")},
+		Config:           map[string]interface{}{"Name": "web"},
+	}
+	if err := TemplateCopy(source, destination, addOnConfig); err != nil {
+		t.Fatalf("failed to copy the templates. Error: %q", err)
+	}
+	want := map[string]string{
+		"config.yaml":                  "name: web\nimage: {{ .Image }}\n",
+		filepath.Join("helm", "a.tpl"): files[filepath.Join("helm", "a.tpl")],
+	}
+	for path, content := range want {
+		data, err := os.ReadFile(filepath.Join(destination, path))
+		if err != nil {
+			t.Fatalf("failed to read the file %s . Error: %q", path, err)
+		}
+		if string(data) != content {
+			t.Errorf("the file %s is incorrect. Expected: %q Actual: %q", path, content, data)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	graphtypes "github.com/konveyor/move2kube/types/graph"
+)
+
+const (
+	// JSONFormat exports the flow of the artifacts as json
+	JSONFormat = "json"
+	// DOTFormat exports the graph in the Graphviz DOT language
+	DOTFormat = "dot"
+	// MermaidFormat exports the graph as a Mermaid flowchart
+	MermaidFormat = "mermaid"
+)
+
+var (
+	// ExportFormats are the formats the graph can be exported in
+	ExportFormats  = []string{JSONFormat, DOTFormat, MermaidFormat}
+	dotEscaper     = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	mermaidEscaper = strings.NewReplacer(`"`, "#quot;", "\n", "<br/>")
+)
+
+// GetFlow returns the flow of the artifacts between the transformers, ordered by the ids of the vertices and the edges.
+func GetFlow(graph graphtypes.Graph) graphtypes.Flow {
+	flow := graphtypes.Flow{Nodes: []graphtypes.FlowNode{}, Edges: []graphtypes.FlowEdge{}}
+	for _, vertex := range graph.Vertices {
+		node := graphtypes.FlowNode{Id: vertex.Id, Label: vertex.Name, Iteration: vertex.Iteration}
+		if vertex.Transformer != nil {
+			node.Name, node.Class = vertex.Transformer.Name, vertex.Transformer.Class
+			node.Label = vertex.Transformer.Name
+			if vertex.Transformer.Class != "" {
+				node.Label += " (" + vertex.Transformer.Class + ")"
+			}
+		}
+		flow.Nodes = append(flow.Nodes, node)
+	}
+	for _, edge := range graph.Edges {
+		flowEdge := graphtypes.FlowEdge{From: edge.From, To: edge.To, Label: edge.Name}
+		if edge.Artifact != nil {
+			flowEdge.ArtifactName, flowEdge.ArtifactType = edge.Artifact.Name, edge.Artifact.Type
+			flowEdge.Label = edge.Artifact.Type
+			if edge.Artifact.Name != "" && edge.Artifact.Name != edge.Artifact.Type {
+				flowEdge.Label += " (" + edge.Artifact.Name + ")"
+			}
+		}
+		flow.Edges = append(flow.Edges, flowEdge)
+	}
+	sort.Slice(flow.Nodes, func(i, j int) bool { return flow.Nodes[i].Id < flow.Nodes[j].Id })
+	sort.SliceStable(flow.Edges, func(i, j int) bool {
+		if flow.Edges[i].From != flow.Edges[j].From {
+			return flow.Edges[i].From < flow.Edges[j].From
+		}
+		return flow.Edges[i].To < flow.Edges[j].To
+	})
+	return flow
+}
+
+// Export returns the graph in the format, one of json, dot and mermaid.
+func Export(graph graphtypes.Graph, format string) ([]byte, error) {
+	flow := GetFlow(graph)
+	switch format {
+	case JSONFormat:
+		data, err := json.MarshalIndent(flow, "", "    ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal the flow of the artifacts to json. Error: %w", err)
+		}
+		return append(data, '\n'), nil
+	case DOTFormat:
+		return []byte(toDOT(flow)), nil
+	case MermaidFormat:
+		return []byte(toMermaid(flow)), nil
+	}
+	return nil, fmt.Errorf("the format %s is not supported. Supported formats are: %s", format, strings.Join(ExportFormats, ", "))
+}
+
+// toDOT returns the flow in the Graphviz DOT language
+func toDOT(flow graphtypes.Flow) string {
+	b := strings.Builder{}
+	b.WriteString("digraph move2kube {\n    rankdir=LR;\n    node [shape=box];\n")
+	for _, node := range flow.Nodes {
+		fmt.Fprintf(&b, "    v%d [label=\"%s\"];\n", node.Id, dotEscaper.Replace(node.Label))
+	}
+	for _, edge := range flow.Edges {
+		fmt.Fprintf(&b, "    v%d -> v%d [label=\"%s\"];\n", edge.From, edge.To, dotEscaper.Replace(edge.Label))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// toMermaid returns the flow as a Mermaid flowchart
+func toMermaid(flow graphtypes.Flow) string {
+	b := strings.Builder{}
+	b.WriteString("flowchart LR\n")
+	for _, node := range flow.Nodes {
+		fmt.Fprintf(&b, "    v%d[\"%s\"]\n", node.Id, mermaidEscaper.Replace(node.Label))
+	}
+	for _, edge := range flow.Edges {
+		if edge.Label == "" {
+			fmt.Fprintf(&b, "    v%d --> v%d\n", edge.From, edge.To)
+			continue
+		}
+		fmt.Fprintf(&b, "    v%d -->|\"%s\"| v%d\n", edge.From, mermaidEscaper.Replace(edge.Label), edge.To)
+	}
+	return b.String()
+}
@@ -0,0 +1,93 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	graphtypes "github.com/konveyor/move2kube/types/graph"
+)
+
+func getTestGraph() graphtypes.Graph {
+	graph := graphtypes.NewGraph()
+	startID := graph.AddVertex("start", 0, nil)
+	detectorID := graph.AddVertex("iteration: 1\nclass: DockerfileDetector\nname: DockerfileDetector", 1, nil)
+	graph.SetVertexTransformer(detectorID, graphtypes.TransformerInfo{Name: "DockerfileDetector", Class: "DockerfileDetector"})
+	kubernetesID := graph.AddVertex("iteration: 2\nclass: Kubernetes\nname: Kubernetes", 2, nil)
+	graph.SetVertexTransformer(kubernetesID, graphtypes.TransformerInfo{Name: "Kubernetes", Class: "Kubernetes"})
+	graph.AddEdge(startID, detectorID, "0 -> 1 (invoked by default)", nil)
+	edgeID := graph.AddEdge(detectorID, kubernetesID, "1 -> 2", nil)
+	graph.SetEdgeArtifact(edgeID, graphtypes.ArtifactInfo{Name: `my "api"`, Type: "IR"})
+	return *graph
+}
+
+func TestExport(t *testing.T) {
+	testcases := []struct {
+		format string
+		want   string
+	}{
+		{format: DOTFormat, want: `digraph move2kube {
+    rankdir=LR;
+    node [shape=box];
+    v0 [label="start"];
+    v1 [label="DockerfileDetector (DockerfileDetector)"];
+    v2 [label="Kubernetes (Kubernetes)"];
+    v0 -> v1 [label="0 -> 1 (invoked by default)"];
+    v1 -> v2 [label="IR (my \"api\")"];
+}
+`},
+		{format: MermaidFormat, want: `flowchart LR
+    v0["start"]
+    v1["DockerfileDetector (DockerfileDetector)"]
+    v2["Kubernetes (Kubernetes)"]
+    v0 -->|"0 -> 1 (invoked by default)"| v1
+    v1 -->|"IR (my #quot;api#quot;)"| v2
+`},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.format, func(t *testing.T) {
+			data, err := Export(getTestGraph(), tc.format)
+			if err != nil {
+				t.Fatalf("failed to export the graph. Error: %q", err)
+			}
+			if string(data) != tc.want {
+				t.Fatalf("the exported graph is incorrect. Differences:\n%s", cmp.Diff(tc.want, string(data)))
+			}
+		})
+	}
+	if _, err := Export(getTestGraph(), "svg"); err == nil {
+		t.Fatalf("expected an error for an unsupported format")
+	}
+}
+
+func TestGetFlow(t *testing.T) {
+	want := graphtypes.Flow{
+		Nodes: []graphtypes.FlowNode{
+			{Id: 0, Label: "start"},
+			{Id: 1, Label: "DockerfileDetector (DockerfileDetector)", Iteration: 1, Name: "DockerfileDetector", Class: "DockerfileDetector"},
+			{Id: 2, Label: "Kubernetes (Kubernetes)", Iteration: 2, Name: "Kubernetes", Class: "Kubernetes"},
+		},
+		Edges: []graphtypes.FlowEdge{
+			{From: 0, To: 1, Label: "0 -> 1 (invoked by default)"},
+			{From: 1, To: 2, Label: `IR (my "api")`, ArtifactName: `my "api"`, ArtifactType: "IR"},
+		},
+	}
+	if flow := GetFlow(getTestGraph()); !cmp.Equal(flow, want) {
+		t.Fatalf("the flow is incorrect. Differences:\n%s", cmp.Diff(want, flow))
+	}
+}
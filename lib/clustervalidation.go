@@ -0,0 +1,136 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/move2kube/types"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// ClusterValidationResult is the result of the server-side dry run of a single manifest file
+type ClusterValidationResult struct {
+	Path  string
+	Error string
+}
+
+// ValidateAgainstCluster does a server-side dry run of the Kubernetes manifests in the output directory
+// against the cluster in the given kubeconfig context and returns the result for each manifest file.
+// Nothing is created in the cluster.
+func ValidateAgainstCluster(outputPath string, kubeContext string) ([]ClusterValidationResult, error) {
+	clusterCmd := ""
+	for _, cmd := range []string{"kubectl", "oc"} {
+		if _, err := exec.LookPath(cmd); err == nil {
+			clusterCmd = cmd
+			break
+		}
+	}
+	if clusterCmd == "" {
+		return nil, fmt.Errorf("no kubectl or oc in path. Add kubectl to path to validate the output against the cluster")
+	}
+	files, err := getDryRunManifestFiles(outputPath)
+	if err != nil {
+		return nil, err
+	}
+	results := []ClusterValidationResult{}
+	for _, file := range files {
+		relPath, err := filepath.Rel(outputPath, file)
+		if err != nil {
+			relPath = file
+		}
+		logrus.Debugf("Doing a server-side dry run of the manifest file %s", relPath)
+		cmd := exec.Command(clusterCmd, "--context", kubeContext, "apply", "--dry-run=server", "-o", "name", "-f", file)
+		output, err := cmd.CombinedOutput()
+		result := ClusterValidationResult{Path: relPath}
+		if err != nil {
+			result.Error = strings.TrimSpace(string(output))
+			if result.Error == "" {
+				result.Error = err.Error()
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// getDryRunManifestFiles returns the yaml files in the output directory that contain only Kubernetes resources.
+// Templates (for example Helm charts) and move2kube's own files can't be applied, so they are skipped.
+func getDryRunManifestFiles(outputPath string) ([]string, error) {
+	files := []string{}
+	err := filepath.WalkDir(outputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logrus.Debugf("Failed to read the file at path %s . Error: %q", path, err)
+			return nil
+		}
+		if isKubernetesManifest(data) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk the output directory %s . Error: %w", outputPath, err)
+	}
+	return files, nil
+}
+
+// isKubernetesManifest returns true if every document in the yaml is a Kubernetes resource
+func isKubernetesManifest(data []byte) bool {
+	if bytes.Contains(data, []byte("{{")) {
+		return false
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	found := false
+	for {
+		header := struct {
+			APIVersion string `yaml:"apiVersion"`
+			Kind       string `yaml:"kind"`
+		}{}
+		if err := decoder.Decode(&header); err != nil {
+			if err == io.EOF {
+				return found
+			}
+			return false
+		}
+		if header.APIVersion == "" && header.Kind == "" {
+			// empty document
+			continue
+		}
+		if header.APIVersion == "" || header.Kind == "" || strings.HasPrefix(header.APIVersion, types.GroupName+"/") {
+			return false
+		}
+		found = true
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIsKubernetesManifest(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+		want bool
+	}{
+		{name: "single resource", data: "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n", want: true},
+		{name: "multiple resources with an empty document", data: "apiVersion: v1\nkind: Service\n---\n---\napiVersion: apps/v1\nkind: Deployment\n", want: true},
+		{name: "empty file", data: ""},
+		{name: "helm template", data: "apiVersion: v1\nkind: Service\nmetadata:\n  name: {{ .Release.Name }}\n"},
+		{name: "move2kube file", data: "apiVersion: move2kube.konveyor.io/v1alpha1\nkind: Plan\n"},
+		{name: "missing the kind", data: "apiVersion: v1\nmetadata:\n  name: web\n"},
+		{name: "missing the apiVersion after a resource", data: "apiVersion: v1\nkind: Service\n---\nkind: Deployment\n"},
+		{name: "invalid yaml", data: "apiVersion: v1\nkind: [Service\n"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := isKubernetesManifest([]byte(testCase.data)); got != testCase.want {
+				t.Fatalf("expected the result for %q to be %t. Actual: %t", testCase.data, testCase.want, got)
+			}
+		})
+	}
+}
+
+func TestGetDryRunManifestFiles(t *testing.T) {
+	outputPath := t.TempDir()
+	writeValidationTestFile(t, filepath.Join(outputPath, "deploy", "yamls", "web-service.yaml"), "apiVersion: v1\nkind: Service\n")
+	writeValidationTestFile(t, filepath.Join(outputPath, "deploy", "yamls", "web-deployment.yml"), "apiVersion: apps/v1\nkind: Deployment\n")
+	writeValidationTestFile(t, filepath.Join(outputPath, "deploy", "helm-chart", "templates", "web-service.yaml"), "apiVersion: v1\nkind: Service\nmetadata:\n  name: {{ .Release.Name }}\n")
+	writeValidationTestFile(t, filepath.Join(outputPath, "m2k.plan"), "apiVersion: move2kube.konveyor.io/v1alpha1\nkind: Plan\n")
+	writeValidationTestFile(t, filepath.Join(outputPath, "m2kqacache.yaml"), "apiVersion: move2kube.konveyor.io/v1alpha1\nkind: QACache\n")
+	writeValidationTestFile(t, filepath.Join(outputPath, "Readme.md"), "apiVersion: v1\nkind: Service\n")
+	got, err := getDryRunManifestFiles(outputPath)
+	if err != nil {
+		t.Fatalf("failed to get the manifest files. Error: %q", err)
+	}
+	sort.Strings(got)
+	want := []string{
+		filepath.Join(outputPath, "deploy", "yamls", "web-deployment.yml"),
+		filepath.Join(outputPath, "deploy", "yamls", "web-service.yaml"),
+	}
+	if !cmp.Equal(got, want) {
+		t.Fatalf("the manifest files are incorrect. Differences:\n%s", cmp.Diff(want, got))
+	}
+	if _, err := getDryRunManifestFiles(filepath.Join(outputPath, "missing")); err == nil {
+		t.Fatalf("expected an error for a missing output directory")
+	}
+}
+
+func TestValidateAgainstCluster(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake kubectl in this test is a sh script")
+	}
+	binPath := t.TempDir()
+	writeValidationTestFile(t, filepath.Join(binPath, "kubectl"), `#!/bin/sh
+for last; do true; done
+while read -r line; do
+  case "$line" in
+    *invalid*) echo "error: the server rejected ${last##*/}"; exit 1 ;;
+  esac
+done < "$last"
+echo "service/web created (server dry run)"
+`)
+	if err := os.Chmod(filepath.Join(binPath, "kubectl"), 0755); err != nil {
+		t.Fatalf("failed to make the fake kubectl executable. Error: %q", err)
+	}
+	t.Setenv("PATH", binPath)
+	outputPath := t.TempDir()
+	writeValidationTestFile(t, filepath.Join(outputPath, "deploy", "yamls", "api-service.yaml"), "apiVersion: v1\nkind: Service\nmetadata:\n  name: invalid\n")
+	writeValidationTestFile(t, filepath.Join(outputPath, "deploy", "yamls", "web-service.yaml"), "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n")
+	got, err := ValidateAgainstCluster(outputPath, "mycontext")
+	if err != nil {
+		t.Fatalf("failed to validate the output against the cluster. Error: %q", err)
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i].Path < got[j].Path })
+	want := []ClusterValidationResult{
+		{Path: filepath.Join("deploy", "yamls", "api-service.yaml"), Error: "error: the server rejected api-service.yaml"},
+		{Path: filepath.Join("deploy", "yamls", "web-service.yaml")},
+	}
+	if !cmp.Equal(got, want) {
+		t.Fatalf("the validation results are incorrect. Differences:\n%s", cmp.Diff(want, got))
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if _, err := ValidateAgainstCluster(outputPath, "mycontext"); err == nil {
+		t.Fatalf("expected an error when there is no kubectl or oc in the path")
+	}
+}
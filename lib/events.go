@@ -0,0 +1,59 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"github.com/konveyor/move2kube/common/eventbus"
+)
+
+// Event is an event published while planning and transforming.
+// Use a type switch on the concrete event types below to handle them.
+type Event = eventbus.Event
+
+// EventTypeT is the type of an event
+type EventTypeT = eventbus.EventTypeT
+
+// QuestionRaisedEvent is published when a question needs to be answered
+type QuestionRaisedEvent = eventbus.QuestionRaisedEvent
+
+// AnswerReceivedEvent is published when a question has been answered. The answers to passwords are masked.
+type AnswerReceivedEvent = eventbus.AnswerReceivedEvent
+
+// TransformerStartedEvent is published when a transformer starts transforming artifacts
+type TransformerStartedEvent = eventbus.TransformerStartedEvent
+
+// TransformerFinishedEvent is published when a transformer finishes transforming artifacts
+type TransformerFinishedEvent = eventbus.TransformerFinishedEvent
+
+// PathMappingAppliedEvent is published when a path mapping has been applied to a directory
+type PathMappingAppliedEvent = eventbus.PathMappingAppliedEvent
+
+// WarningEmittedEvent is published when a warning or an error is logged
+type WarningEmittedEvent = eventbus.WarningEmittedEvent
+
+// SubscribeToEvents registers a handler that is called for every event and returns a function to unsubscribe.
+// The handlers are called synchronously, so they should return quickly. Embedding applications like
+// web UIs and IDE plugins can use the events to show the progress without parsing the logs.
+func SubscribeToEvents(handler func(Event)) (unsubscribe func()) {
+	return eventbus.Subscribe(handler)
+}
+
+// SubscribeToEventsOfType registers a handler that is called for every event of the type E and returns a function to unsubscribe.
+// Example: lib.SubscribeToEventsOfType(func(e lib.TransformerFinishedEvent) { ... })
+func SubscribeToEventsOfType[E Event](handler func(E)) (unsubscribe func()) {
+	return eventbus.SubscribeTo(handler)
+}
@@ -0,0 +1,81 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/vcs"
+	"github.com/konveyor/move2kube/qaengine"
+	"github.com/sirupsen/logrus"
+)
+
+// PushOutput commits the output to a new branch of a git repo and optionally opens a pull request for it.
+// The repo, the branch names and the credentials are asked using the QA engine.
+// It returns the url of the pull request, and empty string if none was opened.
+func PushOutput(outputPath, projectName string) (string, error) {
+	qaengine.StartGroup("Push to git")
+	defer qaengine.EndGroup()
+	notEmpty := func(answer interface{}) error {
+		if s, ok := answer.(string); !ok || strings.TrimSpace(s) == "" {
+			return fmt.Errorf("the answer can't be empty")
+		}
+		return nil
+	}
+	opts := vcs.GitPushOptions{CommitMessage: fmt.Sprintf("Add the deployment artifacts of %s generated by Move2Kube", projectName)}
+	opts.RepoURL = strings.TrimSpace(qaengine.FetchStringAnswer(common.ConfigGitPushRepoURLKey, "Enter the url of the git repo the output should be pushed to:", []string{"Example: https://github.com/org/deploy.git or git@github.com:org/deploy.git"}, "", notEmpty))
+	opts.BaseBranch = strings.TrimSpace(qaengine.FetchStringAnswer(common.ConfigGitPushBaseBranchKey, "Enter the branch the new branch should be created from:", []string{"Leave empty to use the default branch of the repo."}, "", nil))
+	defaultBranch := "move2kube/" + common.NormalizeForMetadataName(projectName) + "-" + time.Now().Format("20060102150405")
+	opts.Branch = strings.TrimSpace(qaengine.FetchStringAnswer(common.ConfigGitPushBranchKey, "Enter the name of the new branch the output should be committed to:", nil, defaultBranch, notEmpty))
+	opts.PathWithinRepo = strings.TrimSpace(qaengine.FetchStringAnswer(common.ConfigGitPushPathKey, "Enter the directory inside the git repo the output should be written to:", []string{"The contents of the directory are replaced by the output. Use . for the root of the repo."}, projectName, nil))
+	if opts.PathWithinRepo == "." {
+		opts.PathWithinRepo = ""
+	}
+	if strings.HasPrefix(opts.RepoURL, "http://") || strings.HasPrefix(opts.RepoURL, "https://") {
+		opts.Username = qaengine.FetchStringAnswer(common.ConfigGitPushUsernameKey, "Enter the username for the git repo:", []string{"Leave empty when using a token."}, "", nil)
+	}
+	opts.Token = qaengine.FetchPasswordAnswer(common.ConfigGitPushTokenKey, "Enter the password or token for the git repo:", []string{"Leave empty to use the credentials from the environment or the ssh agent. A token is required to open a pull request."}, nil)
+	baseBranch, err := vcs.PushDirectory(outputPath, opts)
+	if err != nil {
+		if errors.Is(err, vcs.ErrNothingToPush) {
+			logrus.Infof("The git repo %s already has the output in the branch %s . Nothing to push.", opts.RepoURL, baseBranch)
+			return "", nil
+		}
+		return "", err
+	}
+	logrus.Infof("Pushed the output to the branch %s of the git repo %s", opts.Branch, opts.RepoURL)
+	if !qaengine.FetchBoolAnswer(common.ConfigGitPushPullRequestKey, "Do you want to open a pull request for the new branch?", []string{"Pull requests are opened using the GitHub or GitLab APIs."}, true, nil) {
+		return "", nil
+	}
+	provider := vcs.GetGitProvider(opts.RepoURL)
+	if provider == "" {
+		provider = qaengine.FetchSelectAnswer(common.ConfigGitPushProviderKey, "Select the provider hosting the git repo:", []string{"The API of the provider is used to open the pull request."}, vcs.GitHubProvider, []string{vcs.GitHubProvider, vcs.GitLabProvider}, nil)
+	}
+	return vcs.OpenPullRequest(vcs.PullRequestOptions{
+		RepoURL:    opts.RepoURL,
+		Provider:   provider,
+		Token:      opts.Token,
+		Branch:     opts.Branch,
+		BaseBranch: baseBranch,
+		Title:      fmt.Sprintf("Deployment artifacts of %s", projectName),
+		Body:       "This pull request was opened by Move2Kube with the output of the transformation.",
+	})
+}
@@ -0,0 +1,101 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/transformer"
+	graphtypes "github.com/konveyor/move2kube/types/graph"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+// ExportGraph writes the plan and the graph of the last transformation (if any) to a file that can be consumed by external tools.
+// Files with the .pb or .binpb extension are written in the protobuf wire format, anything else is written as json.
+func ExportGraph(plan plantypes.Plan, exportPath string) error {
+	export := graphtypes.NewExport(GetExportPlan(plan), transformer.GetGraph())
+	var data []byte
+	switch filepath.Ext(exportPath) {
+	case ".pb", ".binpb":
+		data = export.MarshalProto()
+	default:
+		var err error
+		data, err = json.MarshalIndent(export, "", "    ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal the graph export to json. Error: %w", err)
+		}
+	}
+	if err := os.WriteFile(exportPath, data, common.DefaultFilePermission); err != nil {
+		return fmt.Errorf("failed to write the graph export to the file at path %s . Error: %w", exportPath, err)
+	}
+	return nil
+}
+
+// GetExportPlan converts the plan into the format used by the graph export
+func GetExportPlan(plan plantypes.Plan) graphtypes.ExportPlan {
+	exportPlan := graphtypes.ExportPlan{Name: plan.Name, SourceDir: plan.Spec.SourceDir}
+	serviceNames := []string{}
+	for serviceName := range plan.Spec.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		service := graphtypes.ExportService{Name: serviceName}
+		for _, option := range plan.Spec.Services[serviceName] {
+			paths := map[string][]string{}
+			for pathType, ps := range option.Paths {
+				paths[string(pathType)] = ps
+			}
+			service.Options = append(service.Options, graphtypes.ExportServiceOption{
+				TransformerName: option.TransformerName,
+				ArtifactType:    string(option.Type),
+				Paths:           graphtypes.NewExportPaths(paths),
+			})
+		}
+		exportPlan.Services = append(exportPlan.Services, service)
+	}
+	return exportPlan
+}
+
+// GetPlanGraph returns a graph of the services in the plan and the transformers that will transform them.
+// The edges are the artifacts of the services, going from the services to the transformers.
+func GetPlanGraph(plan plantypes.Plan) graphtypes.Graph {
+	graph := graphtypes.NewGraph()
+	startVertexID := graph.AddVertex("plan: "+plan.Name, 0, nil)
+	transformerVertexIDs := map[string]int{}
+	exportPlan := GetExportPlan(plan)
+	for _, service := range exportPlan.Services {
+		serviceVertexID := graph.AddVertex("service: "+service.Name, 0, nil)
+		graph.AddEdge(startVertexID, serviceVertexID, "", nil)
+		for _, option := range service.Options {
+			transformerVertexID, ok := transformerVertexIDs[option.TransformerName]
+			if !ok {
+				transformerVertexID = graph.AddVertex(option.TransformerName, 1, nil)
+				graph.SetVertexTransformer(transformerVertexID, graphtypes.TransformerInfo{Name: option.TransformerName})
+				transformerVertexIDs[option.TransformerName] = transformerVertexID
+			}
+			edgeID := graph.AddEdge(serviceVertexID, transformerVertexID, fmt.Sprintf("%d -> %d", serviceVertexID, transformerVertexID), nil)
+			graph.SetEdgeArtifact(edgeID, graphtypes.ArtifactInfo{Name: service.Name, Type: option.ArtifactType})
+		}
+	}
+	return *graph
+}
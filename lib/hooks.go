@@ -0,0 +1,146 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	hookStageEnvKey          = "MOVE2KUBE_HOOK_STAGE"
+	hookProjectNameEnvKey    = "MOVE2KUBE_PROJECT_NAME"
+	hookSourceDirEnvKey      = "MOVE2KUBE_SOURCE_DIR"
+	hookOutputDirEnvKey      = "MOVE2KUBE_OUTPUT_DIR"
+	hookCustomizationsEnvKey = "MOVE2KUBE_CUSTOMIZATIONS_DIR"
+	hookServiceNameEnvKey    = "MOVE2KUBE_SERVICE_NAME"
+	hookServiceSourceEnvKey  = "MOVE2KUBE_SERVICE_SOURCE_DIR"
+	hookServiceOutputEnvKey  = "MOVE2KUBE_SERVICE_OUTPUT_DIR"
+)
+
+// loadHooks reads the hooks declared in the hooks file of the customizations directory, if there is one
+func loadHooks(customizationsPath string) (plantypes.Hooks, error) {
+	hooks := plantypes.Hooks{}
+	if customizationsPath == "" {
+		return hooks, nil
+	}
+	hooksPath := filepath.Join(customizationsPath, common.HooksFile)
+	if _, err := os.Stat(hooksPath); err != nil {
+		if os.IsNotExist(err) {
+			return hooks, nil
+		}
+		return hooks, fmt.Errorf("failed to stat the hooks file at path %s . Error: %w", hooksPath, err)
+	}
+	if err := common.ReadYaml(hooksPath, &hooks); err != nil {
+		return hooks, fmt.Errorf("failed to read the hooks file at path %s . Error: %w", hooksPath, err)
+	}
+	return hooks, nil
+}
+
+// runHooks runs the hooks of the stage one after another in the working directory.
+// The environment variables are added to the environment of the CLI.
+// A failing hook stops the remaining hooks unless it is allowed to fail.
+// The hooks run on the host, so they are refused when local execution is disabled.
+func runHooks(ctx context.Context, stage plantypes.HookStageT, hooks []plantypes.Hook, workDir string, env map[string]string) error {
+	if len(hooks) != 0 && common.DisableLocalExecution {
+		return fmt.Errorf("refusing to run the %d %s hooks since local execution is prevented by the %s flag", len(hooks), stage, common.DisableLocalExecutionFlag)
+	}
+	for i, hook := range hooks {
+		name := hook.Name
+		if name == "" {
+			name = fmt.Sprintf("%s[%d]", stage, i)
+		}
+		if err := runHook(ctx, stage, name, hook, workDir, env); err != nil {
+			if !hook.IgnoreFailure {
+				return err
+			}
+			logrus.Warnf("ignoring the failure of the %s hook '%s' . Error: %q", stage, name, err)
+		}
+	}
+	return nil
+}
+
+func runHook(ctx context.Context, stage plantypes.HookStageT, name string, hook plantypes.Hook, workDir string, env map[string]string) error {
+	commandLine, err := hook.GetCommandLine(runtime.GOOS)
+	if err != nil {
+		return fmt.Errorf("failed to get the command of the %s hook '%s' . Error: %w", stage, name, err)
+	}
+	logrus.Infof("Running the %s hook '%s'", stage, name)
+	cmd := exec.CommandContext(ctx, commandLine[0], commandLine[1:]...)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), hookStageEnvKey+"="+string(stage))
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	output, err := cmd.CombinedOutput()
+	if len(output) != 0 {
+		logrus.Infof("Output of the %s hook '%s':\n%s", stage, name, strings.TrimRight(string(output), "\n"))
+	}
+	if err != nil {
+		return fmt.Errorf("the %s hook '%s' failed. Error: %w", stage, name, err)
+	}
+	return nil
+}
+
+// runPreDetectHooks runs the hooks declared to run before the services are detected
+func runPreDetectHooks(ctx context.Context, plan plantypes.Plan, outputPath string) error {
+	workDir := plan.Spec.SourceDir
+	if workDir == "" {
+		workDir = "."
+	}
+	return runHooks(ctx, plantypes.HookStagePreDetect, plan.Spec.Hooks.PreDetect, workDir, getHookEnv(plan, outputPath))
+}
+
+// runPostTransformHooks runs the hooks declared to run after the transformation, first the ones
+// for each transformed service and then the ones for the whole output
+func runPostTransformHooks(ctx context.Context, plan plantypes.Plan, transformationOptions []plantypes.PlanArtifact, outputPath string) error {
+	if len(plan.Spec.Hooks.PerService) != 0 {
+		for _, option := range transformationOptions {
+			relSourceDir := getServiceRelSourceDir(option, plan.Spec.SourceDir)
+			serviceOutputDir := getServiceOutputDir(option.ServiceName, relSourceDir, outputPath)
+			if _, err := os.Stat(serviceOutputDir); err != nil {
+				serviceOutputDir = outputPath
+			}
+			env := getHookEnv(plan, outputPath)
+			env[hookServiceNameEnvKey] = option.ServiceName
+			env[hookServiceSourceEnvKey] = filepath.Join(plan.Spec.SourceDir, filepath.FromSlash(relSourceDir))
+			env[hookServiceOutputEnvKey] = serviceOutputDir
+			if err := runHooks(ctx, plantypes.HookStagePerService, plan.Spec.Hooks.PerService, serviceOutputDir, env); err != nil {
+				return fmt.Errorf("failed to run the hooks for the service %s . Error: %w", option.ServiceName, err)
+			}
+		}
+	}
+	return runHooks(ctx, plantypes.HookStagePostTransform, plan.Spec.Hooks.PostTransform, outputPath, getHookEnv(plan, outputPath))
+}
+
+func getHookEnv(plan plantypes.Plan, outputPath string) map[string]string {
+	return map[string]string{
+		hookProjectNameEnvKey:    plan.Name,
+		hookSourceDirEnvKey:      plan.Spec.SourceDir,
+		hookOutputDirEnvKey:      outputPath,
+		hookCustomizationsEnvKey: plan.Spec.CustomizationsDir,
+	}
+}
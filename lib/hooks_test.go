@@ -0,0 +1,63 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+func TestRunHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hooks in this test are sh scripts")
+	}
+	defer func() { common.DisableLocalExecution = false }()
+	testcases := []struct {
+		name                  string
+		disableLocalExecution bool
+		hooks                 []plantypes.Hook
+		wantErr               bool
+		wantRan               bool
+	}{
+		{name: "no hooks", disableLocalExecution: true},
+		{name: "runs the hooks", hooks: []plantypes.Hook{{Script: "touch ran"}}, wantRan: true},
+		{name: "failing hook", hooks: []plantypes.Hook{{Script: "exit 1"}, {Script: "touch ran"}}, wantErr: true},
+		{name: "ignored failure", hooks: []plantypes.Hook{{Script: "exit 1", IgnoreFailure: true}, {Script: "touch ran"}}, wantRan: true},
+		{name: "local execution disabled", disableLocalExecution: true, hooks: []plantypes.Hook{{Script: "touch ran"}}, wantErr: true},
+		{name: "local execution disabled with ignored failures", disableLocalExecution: true, hooks: []plantypes.Hook{{Script: "touch ran", IgnoreFailure: true}}, wantErr: true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			workDir := t.TempDir()
+			common.DisableLocalExecution = tc.disableLocalExecution
+			err := runHooks(context.Background(), plantypes.HookStagePostTransform, tc.hooks, workDir, map[string]string{})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected an error to be %t. Actual error: %v", tc.wantErr, err)
+			}
+			_, statErr := os.Stat(filepath.Join(workDir, "ran"))
+			if ran := statErr == nil; ran != tc.wantRan {
+				t.Fatalf("expected the hooks to run to be %t. Actual: %t", tc.wantRan, ran)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// manifestsDirName is the name of the directory the kubernetes manifests are written to, inside the deploy directory
+	manifestsDirName = "yamls"
+	// kustomizationFileName is the name of the kustomization files, which are not kubernetes resources
+	kustomizationFileName = "kustomization.yaml"
+)
+
+// WriteManifests writes the final Kubernetes manifests in the output directory to the writer as a single multi-document yaml.
+// Each document is preceded by a comment with the file it came from. Returns the number of files written.
+func WriteManifests(outputPath string, w io.Writer) (int, error) {
+	files := []string{}
+	if err := filepath.WalkDir(outputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() == kustomizationFileName || !isFinalManifestsDir(filepath.Dir(path)) {
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	}); err != nil {
+		return 0, fmt.Errorf("failed to walk the output directory %s . Error: %w", outputPath, err)
+	}
+	sort.Strings(files)
+	written := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return written, fmt.Errorf("failed to read the manifest file %s . Error: %w", file, err)
+		}
+		if !isKubernetesManifest(data) {
+			logrus.Debugf("skipping the file %s since it is not a kubernetes manifest", file)
+			continue
+		}
+		relPath, err := filepath.Rel(outputPath, file)
+		if err != nil {
+			relPath = file
+		}
+		docs, err := common.SplitYAML(data)
+		if err != nil {
+			return written, fmt.Errorf("failed to split the manifest file %s into yaml documents. Error: %w", file, err)
+		}
+		for _, doc := range docs {
+			doc = bytes.TrimSpace(doc)
+			if len(doc) == 0 || string(doc) == "null" {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "---\n# Source: %s\n%s\n", filepath.ToSlash(relPath), doc); err != nil {
+				return written, fmt.Errorf("failed to write the manifest file %s . Error: %w", relPath, err)
+			}
+		}
+		written++
+	}
+	return written, nil
+}
+
+// isFinalManifestsDir returns true for the directories the kubernetes transformer writes the manifests to.
+// These are deploy/yamls and, in the per service layout, the same directory inside each service.
+func isFinalManifestsDir(dir string) bool {
+	return filepath.Base(dir) == manifestsDirName && filepath.Base(filepath.Dir(dir)) == common.DeployDir
+}
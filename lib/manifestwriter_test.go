@@ -0,0 +1,88 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+)
+
+func TestWriteManifests(t *testing.T) {
+	outputPath := t.TempDir()
+	writeValidationTestFile(t, filepath.Join(outputPath, common.DeployDir, "yamls", "web-service.yaml"), "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n---\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\n")
+	writeValidationTestFile(t, filepath.Join(outputPath, common.DeployDir, "yamls", "kustomization.yaml"), "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\n")
+	writeValidationTestFile(t, filepath.Join(outputPath, common.DeployDir, "yamls", "notes.txt"), "apiVersion: v1\nkind: Service\n")
+	writeValidationTestFile(t, filepath.Join(outputPath, common.DeployDir, "yamls", "m2k.plan.yaml"), "apiVersion: move2kube.konveyor.io/v1alpha1\nkind: Plan\n")
+	writeValidationTestFile(t, filepath.Join(outputPath, common.DeployDir, "yamls-parameterized", "web-service.yaml"), "apiVersion: v1\nkind: Service\n")
+	writeValidationTestFile(t, filepath.Join(outputPath, common.DeployDir, "helm-chart", "myproject", "templates", "web-service.yaml"), "apiVersion: v1\nkind: Service\nmetadata:\n  name: {{ .Release.Name }}\n")
+	writeValidationTestFile(t, filepath.Join(outputPath, "services", "api", common.DeployDir, "yamls", "api-deployment.yml"), "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\n")
+	writeValidationTestFile(t, filepath.Join(outputPath, common.DefaultSourceDir, "yamls", "config.yaml"), "apiVersion: v1\nkind: Service\n")
+	buffer := bytes.Buffer{}
+	written, err := WriteManifests(outputPath, &buffer)
+	if err != nil {
+		t.Fatalf("failed to write the manifests. Error: %q", err)
+	}
+	if written != 2 {
+		t.Fatalf("expected 2 manifest files to be written. Actual: %d", written)
+	}
+	want := `---
+# Source: deploy/yamls/web-service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+    name: web
+---
+# Source: deploy/yamls/web-service.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+    name: web
+---
+# Source: services/api/deploy/yamls/api-deployment.yml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+    name: api
+`
+	if got := buffer.String(); got != want {
+		t.Fatalf("the manifests are incorrect. Differences:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestIsFinalManifestsDir(t *testing.T) {
+	testCases := []struct {
+		dir  string
+		want bool
+	}{
+		{dir: filepath.Join("out", "deploy", "yamls"), want: true},
+		{dir: filepath.Join("out", "services", "web", "deploy", "yamls"), want: true},
+		{dir: filepath.Join("out", "deploy", "yamls-parameterized")},
+		{dir: filepath.Join("out", "deploy", "knative")},
+		{dir: filepath.Join("out", "source", "yamls")},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.dir, func(t *testing.T) {
+			if got := isFinalManifestsDir(testCase.dir); got != testCase.want {
+				t.Fatalf("expected the result for the directory %s to be %t. Actual: %t", testCase.dir, testCase.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+)
+
+// migrationReport summarizes the transformation for the people reviewing the migration
+type migrationReport struct {
+	ProjectName string
+	Services    []migrationReportService
+	Files       []string
+	Answers     []migrationReportAnswer
+	Warnings    []string
+	FollowUps   []common.FidelityItemT
+	Skipped     []common.SkippedPath
+}
+
+type migrationReportService struct {
+	Name        string
+	Transformer string
+}
+
+type migrationReportAnswer struct {
+	ID       string
+	Question string
+	Answer   string
+}
+
+const migrationReportMarkdownTemplate = `# Migration report for {{ .ProjectName }}
+
+## Services
+
+| Service | Transformer |
+| --- | --- |
+{{- range .Services }}
+| {{ cell .Name }} | {{ cell .Transformer }} |
+{{- end }}
+
+## Manual follow-up items
+{{ if .FollowUps }}
+| Source | Service | Field | Status | Message |
+| --- | --- | --- | --- | --- |
+{{- range .FollowUps }}
+| {{ cell .Source }} | {{ cell .Service }} | {{ cell .Field }} | {{ cell .Status }} | {{ cell .Message }} |
+{{- end }}
+{{ else }}
+None
+{{ end }}
+## Warnings
+{{ if .Warnings }}
+{{- range .Warnings }}
+- {{ . }}
+{{- end }}
+{{ else }}
+None
+{{ end }}
+## Skipped source files and directories
+{{ if .Skipped }}
+| Path | Reason |
+| --- | --- |
+{{- range .Skipped }}
+| {{ cell .Path }} | {{ cell .Reason }} |
+{{- end }}
+{{ else }}
+None
+{{ end }}
+## Answers
+
+| Question | Answer |
+| --- | --- |
+{{- range .Answers }}
+| {{ cell .Question }} ({{ cell .ID }}) | {{ cell .Answer }} |
+{{- end }}
+
+## Generated files
+{{ range .Files }}
+- {{ . }}
+{{- end }}
+`
+
+const migrationReportHTMLTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Migration report for {{ .ProjectName }}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+</style>
+</head>
+<body>
+<h1>Migration report for {{ .ProjectName }}</h1>
+<h2>Services</h2>
+<table>
+<tr><th>Service</th><th>Transformer</th></tr>
+{{- range .Services }}
+<tr><td>{{ .Name }}</td><td>{{ .Transformer }}</td></tr>
+{{- end }}
+</table>
+<h2>Manual follow-up items</h2>
+{{- if .FollowUps }}
+<table>
+<tr><th>Source</th><th>Service</th><th>Field</th><th>Status</th><th>Message</th></tr>
+{{- range .FollowUps }}
+<tr><td>{{ .Source }}</td><td>{{ .Service }}</td><td>{{ .Field }}</td><td>{{ .Status }}</td><td>{{ .Message }}</td></tr>
+{{- end }}
+</table>
+{{- else }}
+<p>None</p>
+{{- end }}
+<h2>Warnings</h2>
+{{- if .Warnings }}
+<ul>
+{{- range .Warnings }}
+<li>{{ . }}</li>
+{{- end }}
+</ul>
+{{- else }}
+<p>None</p>
+{{- end }}
+<h2>Skipped source files and directories</h2>
+{{- if .Skipped }}
+<table>
+<tr><th>Path</th><th>Reason</th></tr>
+{{- range .Skipped }}
+<tr><td>{{ .Path }}</td><td>{{ .Reason }}</td></tr>
+{{- end }}
+</table>
+{{- else }}
+<p>None</p>
+{{- end }}
+<h2>Answers</h2>
+<table>
+<tr><th>Question</th><th>Id</th><th>Answer</th></tr>
+{{- range .Answers }}
+<tr><td>{{ .Question }}</td><td>{{ .ID }}</td><td>{{ .Answer }}</td></tr>
+{{- end }}
+</table>
+<h2>Generated files</h2>
+<ul>
+{{- range .Files }}
+<li>{{ . }}</li>
+{{- end }}
+</ul>
+</body>
+</html>
+`
+
+// writeMigrationReport writes the markdown and html reports summarizing the services, the transformers used for them,
+// the generated files, the answers to the questions, the warnings and the items that need manual follow-up
+func writeMigrationReport(projectName, sourceDir string, transformationOptions []plantypes.PlanArtifact, outputPath string, warnings []string) {
+	report := migrationReport{ProjectName: projectName, Warnings: warnings}
+	for _, option := range transformationOptions {
+		report.Services = append(report.Services, migrationReportService{Name: option.ServiceName, Transformer: option.TransformerName})
+	}
+	sort.Slice(report.Services, func(i, j int) bool { return report.Services[i].Name < report.Services[j].Name })
+	for _, item := range common.GetFidelityReport(sourceDir).Items {
+		if item.Status != common.FidelityApproximated {
+			report.FollowUps = append(report.FollowUps, item)
+		}
+	}
+	for _, skipped := range common.GetSkippedPaths() {
+		if sourceDir != "" && common.IsParent(skipped.Path, sourceDir) {
+			if relPath, err := filepath.Rel(sourceDir, skipped.Path); err == nil {
+				skipped.Path = relPath
+			}
+		}
+		report.Skipped = append(report.Skipped, skipped)
+	}
+	for _, prob := range qaengine.GetAnsweredProblems() {
+		report.Answers = append(report.Answers, migrationReportAnswer{ID: prob.ID, Question: prob.Desc, Answer: formatReportAnswer(prob.Answer)})
+	}
+	reportFiles := []string{common.MigrationReportMarkdownFile, common.MigrationReportHTMLFile, common.QAReportMarkdownFile, common.QAReportHTMLFile}
+	if err := filepath.WalkDir(outputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(outputPath, path)
+		if err != nil || common.IsPresent(reportFiles, relPath) {
+			return nil
+		}
+		report.Files = append(report.Files, filepath.ToSlash(relPath))
+		return nil
+	}); err != nil {
+		logrus.Errorf("failed to list the files in the output directory %s . Error: %q", outputPath, err)
+	}
+	markdown := bytes.Buffer{}
+	if err := texttemplate.Must(texttemplate.New("report").Funcs(texttemplate.FuncMap{"cell": formatMarkdownCell}).Parse(migrationReportMarkdownTemplate)).Execute(&markdown, report); err != nil {
+		logrus.Errorf("failed to generate the markdown migration report. Error: %q", err)
+		return
+	}
+	html := bytes.Buffer{}
+	if err := htmltemplate.Must(htmltemplate.New("report").Parse(migrationReportHTMLTemplate)).Execute(&html, report); err != nil {
+		logrus.Errorf("failed to generate the html migration report. Error: %q", err)
+		return
+	}
+	markdownPath := filepath.Join(outputPath, common.MigrationReportMarkdownFile)
+	if err := os.WriteFile(markdownPath, markdown.Bytes(), common.DefaultFilePermission); err != nil {
+		logrus.Errorf("failed to write the migration report to %s . Error: %q", markdownPath, err)
+		return
+	}
+	htmlPath := filepath.Join(outputPath, common.MigrationReportHTMLFile)
+	if err := os.WriteFile(htmlPath, html.Bytes(), common.DefaultFilePermission); err != nil {
+		logrus.Errorf("failed to write the migration report to %s . Error: %q", htmlPath, err)
+		return
+	}
+	logrus.Infof("The migration report can be found at [%s] and [%s].", markdownPath, htmlPath)
+}
+
+// formatMarkdownCell escapes the value to fit in a markdown table cell
+func formatMarkdownCell(value interface{}) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(fmt.Sprint(value))
+}
+
+// formatReportAnswer formats the answer to fit in a single table cell
+func formatReportAnswer(answer interface{}) string {
+	if answers, ok := answer.([]string); ok {
+		return strings.Join(answers, ", ")
+	}
+	if answers, ok := answer.([]interface{}); ok {
+		return strings.Join(cast.ToStringSlice(answers), ", ")
+	}
+	return strings.ReplaceAll(cast.ToString(answer), "\n", " ")
+}
@@ -19,25 +19,47 @@ package lib
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 
 	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/tracing"
+	"github.com/konveyor/move2kube/common/vcs"
 	"github.com/konveyor/move2kube/transformer"
 	plantypes "github.com/konveyor/move2kube/types/plan"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // CreatePlan creates the plan from all planners
-func CreatePlan(ctx context.Context, inputPath, outputPath string, customizationsPath, transformerSelector, prjName string) (plantypes.Plan, error) {
+func CreatePlan(ctx context.Context, inputPath, outputPath string, customizationsPath, transformerSelector, prjName string) (p plantypes.Plan, err error) {
+	ctx, span := tracing.Start(ctx, "CreatePlan", attribute.String("project", prjName), attribute.String("sourcePath", inputPath), attribute.Int("parallel", common.GetParallelism()))
+	defer func() { tracing.End(span, err) }()
 	logrus.Debugf("Temp Dir : %s", common.TempPath)
-	p := plantypes.NewPlan()
+	logrus.Debugf("Using %d parallel workers", common.GetParallelism())
+	p = plantypes.NewPlan()
 	p.Name = prjName
 	common.ProjectName = prjName
+	if vcs.IsRemotePath(inputPath) {
+		if p.Spec.SourceURL, err = vcs.PinLocalArchivePath(inputPath); err != nil {
+			return p, fmt.Errorf("failed to calculate the checksum of the archive %s . Error: %w", inputPath, err)
+		}
+		if inputPath, err = vcs.FetchRemotePath(p.Spec.SourceURL, filepath.Join(common.TempPath, common.RemoteDir)); err != nil {
+			return p, fmt.Errorf("failed to fetch the source %s . Error: %w", p.Spec.SourceURL, err)
+		}
+	}
 	p.Spec.SourceDir = inputPath
 	p.Spec.CustomizationsDir = customizationsPath
 	if customizationsPath != "" {
 		CheckAndCopyCustomizations(customizationsPath)
 	}
+	if p.Spec.Hooks, err = loadHooks(customizationsPath); err != nil {
+		return p, fmt.Errorf("failed to load the hooks. Error: %w", err)
+	}
+	if err := runPreDetectHooks(ctx, p, outputPath); err != nil {
+		return p, fmt.Errorf("failed to run the hooks before detection. Error: %w", err)
+	}
+	common.SetProgressPhase(common.ProgressPhaseInitializing)
 	transformerSelectorObj, err := metav1.ParseToLabelSelector(transformerSelector)
 	if err != nil {
 		return p, fmt.Errorf("failed to parse the transformer selector string. Error: %q", err)
@@ -48,7 +70,7 @@ func CreatePlan(ctx context.Context, inputPath, outputPath string, customization
 	if err != nil {
 		return p, fmt.Errorf("failed to convert label selector to selector. Error: %q", err)
 	}
-	deselectedTransformers, err := transformer.Init(common.AssetsPath, inputPath, lblSelector, outputPath, p.Name)
+	deselectedTransformers, err := transformer.Init(ctx, common.AssetsPath, inputPath, lblSelector, outputPath, p.Name)
 	if err != nil {
 		return p, fmt.Errorf("failed to initialize the transformers. Error: %q", err)
 	}
@@ -66,13 +88,25 @@ func CreatePlan(ctx context.Context, inputPath, outputPath string, customization
 	logrus.Infoln("Configuration loading done")
 
 	logrus.Infoln("Start planning")
+	common.SetProgressPhase(common.ProgressPhasePlanning)
 	if inputPath != "" {
-		p.Spec.Services, err = transformer.GetServices(p.Name, inputPath)
+		p.Spec.Services, err = transformer.GetServices(ctx, p.Name, inputPath)
 		if err != nil {
 			logrus.Errorf("Unable to create plan : %s", err)
 		}
+		p.Spec.Readiness = getReadiness(inputPath, p.Spec.Services)
 	}
 	logrus.Infoln("Planning done")
 	logrus.Infof("No of services identified : %d", len(p.Spec.Services))
+	span.SetAttributes(attribute.Int("services", len(p.Spec.Services)))
+	common.SetProgressPhase(common.ProgressPhaseDone)
 	return p, nil
 }
+
+// WriteDetectionExplanation writes why the transformers did or did not detect services in each directory to the path
+func WriteDetectionExplanation(outputPath string) error {
+	if err := common.WriteYaml(outputPath, transformer.GetDetectionExplanation()); err != nil {
+		return fmt.Errorf("failed to write the detection explanation to the file at path %s . Error: %w", outputPath, err)
+	}
+	return nil
+}
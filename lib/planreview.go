@@ -0,0 +1,199 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+)
+
+const (
+	savePlanOption          = "Save the plan"
+	renameServiceOption     = "Rename the service"
+	selectTransformerOption = "Select the transformers"
+	editPathsOption         = "Edit the source paths"
+	backOption              = "Back"
+)
+
+// ReviewPlan lets the user interactively rename the services, deselect their transformers and adjust their source paths before the plan is written
+func ReviewPlan(plan plantypes.Plan) (plantypes.Plan, error) {
+	for {
+		serviceNames := []string{}
+		for serviceName := range plan.Spec.Services {
+			serviceNames = append(serviceNames, serviceName)
+		}
+		sort.Strings(serviceNames)
+		options := []string{}
+		for _, serviceName := range serviceNames {
+			options = append(options, fmt.Sprintf("%s %v", serviceName, getServiceTransformerNames(plan, serviceName)))
+		}
+		options = append(options, savePlanOption)
+		selected := 0
+		if err := survey.AskOne(&survey.Select{Message: "Select a service to review or save the plan:", Options: options, PageSize: 20}, &selected); err != nil {
+			return plan, fmt.Errorf("failed to select a service to review. Error: %w", err)
+		}
+		if selected == len(serviceNames) {
+			return plan, nil
+		}
+		if err := reviewService(&plan, serviceNames[selected]); err != nil {
+			return plan, err
+		}
+	}
+}
+
+func reviewService(plan *plantypes.Plan, serviceName string) error {
+	for {
+		action := ""
+		if err := survey.AskOne(&survey.Select{
+			Message: fmt.Sprintf("Service %s is transformed by %v:", serviceName, getServiceTransformerNames(*plan, serviceName)),
+			Options: []string{renameServiceOption, selectTransformerOption, editPathsOption, backOption},
+		}, &action); err != nil {
+			return fmt.Errorf("failed to select an action for the service %s . Error: %w", serviceName, err)
+		}
+		switch action {
+		case renameServiceOption:
+			newName := ""
+			if err := survey.AskOne(&survey.Input{Message: "Enter the new name of the service:", Default: serviceName}, &newName, survey.WithValidator(func(ans interface{}) error {
+				name := strings.TrimSpace(cast.ToString(ans))
+				if _, ok := plan.Spec.Services[name]; ok && name != serviceName {
+					return fmt.Errorf("a service named %s is already present in the plan", name)
+				}
+				return survey.Required(name)
+			})); err != nil {
+				return fmt.Errorf("failed to get the new name of the service %s . Error: %w", serviceName, err)
+			}
+			newName = strings.TrimSpace(newName)
+			if err := plan.RenameService(serviceName, newName); err != nil {
+				logrus.Errorf("failed to rename the service %s to %s . Error: %q", serviceName, newName, err)
+				continue
+			}
+			serviceName = newName
+		case selectTransformerOption:
+			transformerNames := getServiceTransformerNames(*plan, serviceName)
+			selected := []string{}
+			if err := survey.AskOne(&survey.MultiSelect{
+				Message: "Select the transformers that should transform the service. The service is removed from the plan if none are selected:",
+				Options: transformerNames,
+				Default: transformerNames,
+			}, &selected); err != nil {
+				return fmt.Errorf("failed to select the transformers of the service %s . Error: %w", serviceName, err)
+			}
+			plan.SelectServiceTransformers(serviceName, selected)
+			if _, ok := plan.Spec.Services[serviceName]; !ok {
+				logrus.Infof("Removed the service %s from the plan", serviceName)
+				return nil
+			}
+		case editPathsOption:
+			if err := editArtifactPaths(plan, serviceName); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// editArtifactPaths lets the user change the paths of an artifact of the service. The paths are shown relative to the source directory.
+func editArtifactPaths(plan *plantypes.Plan, serviceName string) error {
+	artifacts := plan.Spec.Services[serviceName]
+	artifactIdx := 0
+	if len(artifacts) > 1 {
+		options := []string{}
+		for _, artifact := range artifacts {
+			options = append(options, artifact.TransformerName)
+		}
+		if err := survey.AskOne(&survey.Select{Message: "Select the transformer whose paths should be edited:", Options: options}, &artifactIdx); err != nil {
+			return fmt.Errorf("failed to select the transformer of the service %s . Error: %w", serviceName, err)
+		}
+	}
+	artifact := &artifacts[artifactIdx]
+	if len(artifact.Paths) == 0 {
+		logrus.Infof("The transformer %s of the service %s has no paths", artifact.TransformerName, serviceName)
+		return nil
+	}
+	pathTypes := []string{}
+	for pathType := range artifact.Paths {
+		pathTypes = append(pathTypes, string(pathType))
+	}
+	sort.Strings(pathTypes)
+	pathType := pathTypes[0]
+	if len(pathTypes) > 1 {
+		if err := survey.AskOne(&survey.Select{Message: "Select the paths to edit:", Options: pathTypes}, &pathType); err != nil {
+			return fmt.Errorf("failed to select the paths of the service %s . Error: %w", serviceName, err)
+		}
+	}
+	relPaths := []string{}
+	for _, path := range artifact.Paths[transformertypes.PathType(pathType)] {
+		relPaths = append(relPaths, toSourceRelPath(plan.Spec.SourceDir, path))
+	}
+	answer := ""
+	if err := survey.AskOne(&survey.Input{
+		Message: fmt.Sprintf("Enter the comma separated %s paths relative to the source directory:", pathType),
+		Default: strings.Join(relPaths, ","),
+	}, &answer, survey.WithValidator(func(ans interface{}) error {
+		for _, path := range splitPaths(plan.Spec.SourceDir, cast.ToString(ans)) {
+			if _, err := os.Stat(path); err != nil {
+				return fmt.Errorf("the path %s is not accessible. Error: %w", path, err)
+			}
+		}
+		return nil
+	})); err != nil {
+		return fmt.Errorf("failed to get the %s paths of the service %s . Error: %w", pathType, serviceName, err)
+	}
+	artifact.Paths[transformertypes.PathType(pathType)] = splitPaths(plan.Spec.SourceDir, answer)
+	return nil
+}
+
+func getServiceTransformerNames(plan plantypes.Plan, serviceName string) []string {
+	transformerNames := []string{}
+	for _, artifact := range plan.Spec.Services[serviceName] {
+		transformerNames = append(transformerNames, artifact.TransformerName)
+	}
+	return transformerNames
+}
+
+func toSourceRelPath(sourceDir, path string) string {
+	if relPath, err := filepath.Rel(sourceDir, path); err == nil && !strings.HasPrefix(relPath, "..") {
+		return relPath
+	}
+	return path
+}
+
+// splitPaths splits the comma separated paths and makes them absolute using the source directory
+func splitPaths(sourceDir, paths string) []string {
+	absPaths := []string{}
+	for _, path := range strings.Split(paths, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(sourceDir, path)
+		}
+		absPaths = append(absPaths, path)
+	}
+	return absPaths
+}
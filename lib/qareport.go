@@ -0,0 +1,183 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/eventbus"
+	"github.com/konveyor/move2kube/qaengine"
+	"github.com/konveyor/move2kube/types"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// QAReportMarkdownFormat writes the QA report as markdown
+	QAReportMarkdownFormat = "md"
+	// QAReportHTMLFormat writes the QA report as html
+	QAReportHTMLFormat = "html"
+)
+
+// QAReport records the transformers that ask the questions so that every decision made during
+// the transformation can be reviewed without reading the QA cache
+type QAReport struct {
+	mutex sync.Mutex
+	// transformer is the transformer that is running, it is empty outside the transformers
+	transformer string
+	// askedBy maps the question ids to the transformers that asked them first
+	askedBy     map[string]string
+	unsubscribe func()
+}
+
+type qaReportDecision struct {
+	ID          string
+	Question    string
+	Answer      string
+	Default     string
+	Transformer string
+	Services    string
+}
+
+type qaReportData struct {
+	ProjectName string
+	Decisions   []qaReportDecision
+}
+
+const qaReportMarkdownTemplate = `# Decisions made while transforming {{ .ProjectName }}
+
+| Question | Answer | Default | Asked by | Services |
+| --- | --- | --- | --- | --- |
+{{- range .Decisions }}
+| {{ cell .Question }} ({{ cell .ID }}) | {{ cell .Answer }} | {{ cell .Default }} | {{ cell .Transformer }} | {{ cell .Services }} |
+{{- end }}
+`
+
+const qaReportHTMLTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Decisions made while transforming {{ .ProjectName }}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+</style>
+</head>
+<body>
+<h1>Decisions made while transforming {{ .ProjectName }}</h1>
+<table>
+<tr><th>Question</th><th>Id</th><th>Answer</th><th>Default</th><th>Asked by</th><th>Services</th></tr>
+{{- range .Decisions }}
+<tr><td>{{ .Question }}</td><td>{{ .ID }}</td><td>{{ .Answer }}</td><td>{{ .Default }}</td><td>{{ .Transformer }}</td><td>{{ .Services }}</td></tr>
+{{- end }}
+</table>
+</body>
+</html>
+`
+
+// StartQAReport starts recording the transformers that ask the questions
+func StartQAReport() *QAReport {
+	report := &QAReport{askedBy: map[string]string{}}
+	report.unsubscribe = eventbus.Subscribe(report.handleEvent)
+	return report
+}
+
+func (r *QAReport) handleEvent(event eventbus.Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	switch e := event.(type) {
+	case eventbus.TransformerStartedEvent:
+		r.transformer = e.Name
+	case eventbus.TransformerFinishedEvent:
+		r.transformer = ""
+	case eventbus.QuestionRaisedEvent:
+		if _, ok := r.askedBy[e.Problem.ID]; !ok && r.transformer != "" {
+			r.askedBy[e.Problem.ID] = r.transformer
+		}
+	}
+}
+
+// Write stops the recording and writes the report of the answered questions to the output directory.
+// The questions that are about a service are matched to the service using the service name in the question id.
+// The format is either QAReportMarkdownFormat or QAReportHTMLFormat. Returns the path of the report.
+func (r *QAReport) Write(projectName string, serviceNames []string, outputPath, format string) (string, error) {
+	r.unsubscribe()
+	report := qaReportData{ProjectName: projectName, Decisions: r.getDecisions(qaengine.GetAnsweredProblems(), serviceNames)}
+	content := bytes.Buffer{}
+	reportPath := ""
+	switch format {
+	case QAReportMarkdownFormat:
+		if err := texttemplate.Must(texttemplate.New("qareport").Funcs(texttemplate.FuncMap{"cell": formatMarkdownCell}).Parse(qaReportMarkdownTemplate)).Execute(&content, report); err != nil {
+			return "", fmt.Errorf("failed to generate the markdown QA report. Error: %w", err)
+		}
+		reportPath = filepath.Join(outputPath, common.QAReportMarkdownFile)
+	case QAReportHTMLFormat:
+		if err := htmltemplate.Must(htmltemplate.New("qareport").Parse(qaReportHTMLTemplate)).Execute(&content, report); err != nil {
+			return "", fmt.Errorf("failed to generate the html QA report. Error: %w", err)
+		}
+		reportPath = filepath.Join(outputPath, common.QAReportHTMLFile)
+	default:
+		return "", fmt.Errorf("the QA report format %s is not supported. Supported formats are %s and %s", format, QAReportMarkdownFormat, QAReportHTMLFormat)
+	}
+	if err := os.WriteFile(reportPath, content.Bytes(), common.DefaultFilePermission); err != nil {
+		return "", fmt.Errorf("failed to write the QA report to %s . Error: %w", reportPath, err)
+	}
+	logrus.Infof("The QA report can be found at [%s].", reportPath)
+	return reportPath, nil
+}
+
+// getDecisions returns the answered questions along with the transformers that asked them and the services they affect
+func (r *QAReport) getDecisions(answeredProblems []qatypes.Problem, serviceNames []string) []qaReportDecision {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	sortedServiceNames := append([]string{}, serviceNames...)
+	sort.Strings(sortedServiceNames)
+	decisions := []qaReportDecision{}
+	for _, prob := range answeredProblems {
+		decision := qaReportDecision{ID: prob.ID, Question: prob.Desc, Answer: formatReportAnswer(prob.Answer), Transformer: r.askedBy[prob.ID]}
+		if prob.Default != nil && prob.Type != qatypes.PasswordSolutionFormType {
+			decision.Default = formatReportAnswer(prob.Default)
+		}
+		if decision.Transformer == "" {
+			decision.Transformer = types.AppName
+		}
+		services := []string{}
+		for _, serviceName := range sortedServiceNames {
+			if isServiceProblem(prob.ID, serviceName) {
+				services = append(services, serviceName)
+			}
+		}
+		if len(services) == 0 {
+			decision.Services = "all"
+		} else {
+			decision.Services = strings.Join(services, ", ")
+		}
+		decisions = append(decisions, decision)
+	}
+	return decisions
+}
@@ -0,0 +1,51 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common/eventbus"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+)
+
+func TestQAReportDecisions(t *testing.T) {
+	report := StartQAReport()
+	defer report.unsubscribe()
+	servicesProb := qatypes.Problem{ID: "move2kube.services.[].enable", Desc: "Select all services that are needed:", Type: qatypes.MultiSelectSolutionFormType, Default: []string{"api", "web"}, Answer: []string{"api"}}
+	portProb := qatypes.Problem{ID: `move2kube.services."api".port`, Desc: "Select the port to be exposed for the api service:", Type: qatypes.SelectSolutionFormType, Default: "8080", Answer: "9090"}
+	passwordProb := qatypes.Problem{ID: "move2kube.registry.password", Desc: "Enter the password:", Type: qatypes.PasswordSolutionFormType, Default: "secret", Answer: "********"}
+	eventbus.Publish(eventbus.QuestionRaisedEvent{Problem: servicesProb})
+	eventbus.Publish(eventbus.TransformerStartedEvent{Name: "Dockerfile"})
+	eventbus.Publish(eventbus.QuestionRaisedEvent{Problem: portProb})
+	eventbus.Publish(eventbus.TransformerFinishedEvent{Name: "Dockerfile"})
+	eventbus.Publish(eventbus.TransformerStartedEvent{Name: "Kubernetes"})
+	eventbus.Publish(eventbus.QuestionRaisedEvent{Problem: portProb})
+	eventbus.Publish(eventbus.QuestionRaisedEvent{Problem: passwordProb})
+	eventbus.Publish(eventbus.TransformerFinishedEvent{Name: "Kubernetes"})
+
+	want := []qaReportDecision{
+		{ID: servicesProb.ID, Question: servicesProb.Desc, Answer: "api", Default: "api, web", Transformer: "move2kube", Services: "all"},
+		{ID: portProb.ID, Question: portProb.Desc, Answer: "9090", Default: "8080", Transformer: "Dockerfile", Services: "api"},
+		{ID: passwordProb.ID, Question: passwordProb.Desc, Answer: "********", Transformer: "Kubernetes", Services: "all"},
+	}
+	decisions := report.getDecisions([]qatypes.Problem{servicesProb, portProb, passwordProb}, []string{"web", "api"})
+	if !cmp.Equal(decisions, want) {
+		t.Fatalf("the decisions are incorrect. Differences:\n%s", cmp.Diff(want, decisions))
+	}
+}
@@ -0,0 +1,41 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"github.com/konveyor/move2kube/qaengine"
+)
+
+// AnswerResolver is a custom source of answers like a database or a ticketing system
+type AnswerResolver = qaengine.AnswerResolver
+
+// ResolverPrecedence is when a resolver is consulted relative to the built-in sources of answers
+type ResolverPrecedence = qaengine.ResolverPrecedence
+
+const (
+	// BeforeBuiltinSources consults the resolver before the environment, the vault, the session, the caches and the config files
+	BeforeBuiltinSources = qaengine.BeforeBuiltinSources
+	// AfterBuiltinSources consults the resolver after the built-in sources and before asking the user or using the default
+	AfterBuiltinSources = qaengine.AfterBuiltinSources
+)
+
+// RegisterAnswerResolver registers a custom source of answers for the questions asked while planning and transforming.
+// Resolvers with the same precedence are consulted in the order they were registered.
+// Example: lib.RegisterAnswerResolver(myTicketResolver, lib.AfterBuiltinSources)
+func RegisterAnswerResolver(resolver AnswerResolver, precedence ResolverPrecedence) error {
+	return qaengine.RegisterAnswerResolver(resolver, precedence)
+}
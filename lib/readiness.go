@@ -0,0 +1,234 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	windowsFileExts      = []string{".bat", ".cmd", ".ps1", ".exe", ".dll", ".msi"}
+	embeddedDatabaseExts = []string{".db", ".sqlite", ".sqlite3", ".mdb"}
+	ignoredReadinessDirs = []string{"node_modules", "vendor"}
+
+	dockerfileVolumeRegex       = regexp.MustCompile(`(?im)^\s*VOLUME\s`)
+	dockerfileWindowsBaseRegex  = regexp.MustCompile(`(?im)^\s*FROM\s+\S*(windows|servercore|nanoserver)`)
+	dotNetFrameworkVersionRegex = regexp.MustCompile(`<TargetFrameworkVersion>\s*v[1-4]`)
+	composeFileRegex            = regexp.MustCompile(`^(docker-)?compose.*\.ya?ml$`)
+)
+
+// readinessReport is the migration readiness of the transformed services, ordered from the easiest to the hardest to migrate
+type readinessReport struct {
+	Services []serviceReadinessReport `yaml:"services"`
+}
+
+type serviceReadinessReport struct {
+	Name                       string `yaml:"name"`
+	plantypes.ServiceReadiness `yaml:",inline"`
+}
+
+// getReadiness scans the source files of each service for the factors that make it harder to migrate
+func getReadiness(sourceDir string, services map[string][]plantypes.PlanArtifact) map[string]plantypes.ServiceReadiness {
+	readiness := map[string]plantypes.ServiceReadiness{}
+	for serviceName, planArtifacts := range services {
+		readiness[serviceName] = plantypes.NewServiceReadiness(getReadinessFindings(sourceDir, serviceName, planArtifacts))
+	}
+	return readiness
+}
+
+func getReadinessFindings(sourceDir, serviceName string, planArtifacts []plantypes.PlanArtifact) []plantypes.ReadinessFinding {
+	findings := []plantypes.ReadinessFinding{}
+	scannedPaths := map[string]bool{}
+	for _, planArtifact := range planArtifacts {
+		for _, paths := range planArtifact.Paths {
+			for _, path := range paths {
+				if !filepath.IsAbs(path) {
+					path = filepath.Join(sourceDir, path)
+				}
+				if scannedPaths[path] {
+					continue
+				}
+				scannedPaths[path] = true
+				if err := filepath.WalkDir(path, func(filePath string, d fs.DirEntry, err error) error {
+					if err != nil {
+						return nil
+					}
+					if d.IsDir() {
+						if filePath != path && (strings.HasPrefix(d.Name(), ".") || common.IsPresent(ignoredReadinessDirs, d.Name())) {
+							return filepath.SkipDir
+						}
+						return nil
+					}
+					if scannedPaths[filePath] && filePath != path {
+						return nil
+					}
+					scannedPaths[filePath] = true
+					findings = append(findings, getFileReadinessFindings(sourceDir, serviceName, filePath)...)
+					return nil
+				}); err != nil {
+					logrus.Debugf("failed to walk the path %s to check the readiness of the service %s . Error: %q", path, serviceName, err)
+				}
+			}
+		}
+	}
+	return findings
+}
+
+func getFileReadinessFindings(sourceDir, serviceName, filePath string) []plantypes.ReadinessFinding {
+	relPath := filePath
+	if sourceDir != "" && common.IsParent(filePath, sourceDir) {
+		if p, err := filepath.Rel(sourceDir, filePath); err == nil {
+			relPath = p
+		}
+	}
+	name := strings.ToLower(filepath.Base(filePath))
+	ext := filepath.Ext(name)
+	newFinding := func(factor plantypes.ReadinessFactorT, message string) []plantypes.ReadinessFinding {
+		return []plantypes.ReadinessFinding{{Factor: factor, Message: message, Path: relPath}}
+	}
+	switch {
+	case common.IsPresent(windowsFileExts, ext):
+		return newFinding(plantypes.ReadinessFactorOSSpecific, fmt.Sprintf("the Windows specific file %s can't be used in a Linux container", filepath.Base(filePath)))
+	case common.IsPresent(embeddedDatabaseExts, ext):
+		return newFinding(plantypes.ReadinessFactorStatefulStorage, fmt.Sprintf("the embedded database file %s needs a persistent volume", filepath.Base(filePath)))
+	case name == "dockerfile" || strings.HasPrefix(name, "dockerfile.") || ext == ".dockerfile":
+		return getDockerfileReadinessFindings(relPath, filePath)
+	case ext == ".csproj" || ext == ".vbproj":
+		content, err := os.ReadFile(filePath)
+		if err == nil && dotNetFrameworkVersionRegex.Match(content) {
+			return newFinding(plantypes.ReadinessFactorOSSpecific, "the project targets the .NET Framework which only runs on Windows")
+		}
+	case composeFileRegex.MatchString(name):
+		return getComposeReadinessFindings(relPath, filePath, serviceName)
+	}
+	return nil
+}
+
+func getDockerfileReadinessFindings(relPath, filePath string) []plantypes.ReadinessFinding {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		logrus.Debugf("failed to read the Dockerfile %s . Error: %q", filePath, err)
+		return nil
+	}
+	findings := []plantypes.ReadinessFinding{}
+	if dockerfileVolumeRegex.Match(content) {
+		findings = append(findings, plantypes.ReadinessFinding{Factor: plantypes.ReadinessFactorStatefulStorage, Message: "the Dockerfile declares a volume", Path: relPath})
+	}
+	if dockerfileWindowsBaseRegex.Match(content) {
+		findings = append(findings, plantypes.ReadinessFinding{Factor: plantypes.ReadinessFactorOSSpecific, Message: "the Dockerfile uses a Windows base image", Path: relPath})
+	}
+	return findings
+}
+
+func getComposeReadinessFindings(relPath, filePath, serviceName string) []plantypes.ReadinessFinding {
+	compose := struct {
+		Services map[string]map[string]interface{} `yaml:"services"`
+	}{}
+	if err := common.ReadYaml(filePath, &compose); err != nil {
+		logrus.Debugf("failed to read the compose file %s . Error: %q", filePath, err)
+		return nil
+	}
+	service, ok := compose.Services[serviceName]
+	if !ok {
+		for name, s := range compose.Services {
+			if common.MakeStringK8sServiceNameCompliant(name) == serviceName {
+				service, ok = s, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return nil
+	}
+	findings := []plantypes.ReadinessFinding{}
+	newFinding := func(factor plantypes.ReadinessFactorT, message string) {
+		findings = append(findings, plantypes.ReadinessFinding{Factor: factor, Message: message, Path: relPath})
+	}
+	if privileged, ok := service["privileged"].(bool); ok && privileged {
+		newFinding(plantypes.ReadinessFactorPrivileged, "the service runs in privileged mode")
+	}
+	if capAdd, ok := service["cap_add"].([]interface{}); ok && len(capAdd) > 0 {
+		newFinding(plantypes.ReadinessFactorPrivileged, fmt.Sprintf("the service adds the capabilities %v", capAdd))
+	}
+	if devices, ok := service["devices"].([]interface{}); ok && len(devices) > 0 {
+		newFinding(plantypes.ReadinessFactorPrivileged, "the service uses host devices")
+	}
+	for _, key := range []string{"network_mode", "pid", "ipc"} {
+		if mode, ok := service[key].(string); ok && mode == "host" {
+			newFinding(plantypes.ReadinessFactorPrivileged, fmt.Sprintf("the service uses the host namespace for %s", key))
+		}
+	}
+	if volumes, ok := service["volumes"].([]interface{}); ok && len(volumes) > 0 {
+		newFinding(plantypes.ReadinessFactorStatefulStorage, fmt.Sprintf("the service mounts %d volume(s)", len(volumes)))
+	}
+	return findings
+}
+
+// writeReadinessReport combines the readiness found while planning with the features that didn't survive the transformation
+func writeReadinessReport(sourceDir string, services map[string][]plantypes.PlanArtifact, planReadiness map[string]plantypes.ServiceReadiness, outputPath string) {
+	unsupportedFeatures := map[string][]plantypes.ReadinessFinding{}
+	for _, item := range common.GetFidelityReport(sourceDir).Items {
+		if item.Service == "" || item.Status == common.FidelityApproximated {
+			continue
+		}
+		message := fmt.Sprintf("the field %s was %s", item.Field, item.Status)
+		if item.Status == common.FidelityManualAttention {
+			message = fmt.Sprintf("the field %s needs manual attention", item.Field)
+		}
+		unsupportedFeatures[item.Service] = append(unsupportedFeatures[item.Service], plantypes.ReadinessFinding{Factor: plantypes.ReadinessFactorUnsupportedFeature, Message: message, Path: item.Source})
+	}
+	report := readinessReport{Services: []serviceReadinessReport{}}
+	for serviceName, planArtifacts := range services {
+		findings := []plantypes.ReadinessFinding{}
+		if readiness, ok := planReadiness[serviceName]; ok {
+			findings = append(findings, readiness.Findings...)
+		} else {
+			findings = getReadinessFindings(sourceDir, serviceName, planArtifacts)
+		}
+		findings = append(findings, unsupportedFeatures[serviceName]...)
+		report.Services = append(report.Services, serviceReadinessReport{Name: serviceName, ServiceReadiness: plantypes.NewServiceReadiness(findings)})
+	}
+	if len(report.Services) == 0 {
+		return
+	}
+	sort.SliceStable(report.Services, func(i, j int) bool {
+		if report.Services[i].Score != report.Services[j].Score {
+			return report.Services[i].Score > report.Services[j].Score
+		}
+		return report.Services[i].Name < report.Services[j].Name
+	})
+	reportPath := filepath.Join(outputPath, common.ReadinessReportFile)
+	if err := common.WriteYaml(reportPath, report); err != nil {
+		logrus.Errorf("failed to write the readiness report to %s . Error: %q", reportPath, err)
+		return
+	}
+	summary := []string{}
+	for _, service := range report.Services {
+		summary = append(summary, fmt.Sprintf("%s: %d (%s complexity)", service.Name, service.Score, service.Complexity))
+	}
+	logrus.Infof("Migration readiness of the services:\n  %s\nSee %s for details.", strings.Join(summary, "\n  "), reportPath)
+}
@@ -0,0 +1,358 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	"github.com/konveyor/move2kube/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	"github.com/konveyor/move2kube/types/transformer/artifacts"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// readmeServiceLabel is the label that contains the name of the service a generated resource belongs to
+	readmeServiceLabel = types.GroupName + "/service"
+	// servicesOutputDir is the directory containing the per service directories in the perservice output layout
+	servicesOutputDir = "services"
+)
+
+// serviceReadme describes how a service was detected, built and deployed for the teams receiving the output
+type serviceReadme struct {
+	ProjectName    string
+	Name           string
+	Transformer    string
+	SourceDir      string
+	Dockerfiles    []string
+	Images         []string
+	BuildScripts   []string
+	Manifests      []string
+	Kustomizations []string
+	HelmCharts     []string
+	Answers        []migrationReportAnswer
+}
+
+const serviceReadmeTemplate = `# {{ .Name }}
+
+This service is part of the {{ .ProjectName }} project. The commands below are meant to be run from the root of the output directory.
+
+## Detection
+
+The service was detected in the directory ` + "`{{ .SourceDir }}`" + ` of the source by the ` + "`{{ .Transformer }}`" + ` transformer.
+
+## Building the image
+{{ if .Dockerfiles }}
+{{- range .Dockerfiles }}
+` + "```" + `
+docker build -f {{ . }} -t {{ if $.Images }}{{ index $.Images 0 }}{{ else }}{{ $.Name }}{{ end }} {{ dir . }}
+` + "```" + `
+{{- end }}
+{{ else }}
+No Dockerfile was generated for this service.
+{{ end }}
+{{- if .Images }}
+The manifests use the images:
+{{ range .Images }}
+- {{ . }}
+{{- end }}
+{{ end }}
+{{- if .BuildScripts }}
+The images of all the services can also be built using:
+{{ range .BuildScripts }}
+- {{ . }}
+{{- end }}
+{{ end }}
+## Deploying
+{{ if .Manifests }}
+Using kubectl:
+
+` + "```" + `
+{{- range .Manifests }}
+kubectl apply -f {{ . }}
+{{- end }}
+` + "```" + `
+{{ else }}
+No manifests were generated for this service.
+{{ end }}
+{{- if .Kustomizations }}
+Using kustomize:
+
+` + "```" + `
+{{- range .Kustomizations }}
+kubectl apply -k {{ . }}
+{{- end }}
+` + "```" + `
+{{ end }}
+{{- if .HelmCharts }}
+Using helm, which deploys all the services of the project:
+
+` + "```" + `
+{{- range .HelmCharts }}
+helm upgrade --install {{ $.ProjectName }} {{ . }}
+{{- end }}
+` + "```" + `
+{{ end }}
+## Answers that influenced this service
+{{ if .Answers }}
+| Question | Answer |
+| --- | --- |
+{{- range .Answers }}
+| {{ cell .Question }} ({{ cell .ID }}) | {{ cell .Answer }} |
+{{- end }}
+{{ else }}
+None
+{{ end -}}
+`
+
+// writeServiceReadmes writes a readme for each service describing how it was detected, how to build its image,
+// how to deploy it and the answers that influenced it
+func writeServiceReadmes(projectName, sourceDir string, transformationOptions []plantypes.PlanArtifact, outputPath string) {
+	manifestServices, kustomizationDirs, helmCharts := getOutputManifests(outputPath)
+	buildScripts := []string{}
+	for _, buildScript := range []string{"buildimages.sh", "buildimages.bat"} {
+		buildScriptPath := filepath.Join(common.ScriptsDir, buildScript)
+		if _, err := os.Stat(filepath.Join(outputPath, buildScriptPath)); err == nil {
+			buildScripts = append(buildScripts, filepath.ToSlash(buildScriptPath))
+		}
+	}
+	answeredProblems := qaengine.GetAnsweredProblems()
+	tpl := template.Must(template.New("readme").Funcs(template.FuncMap{"cell": formatMarkdownCell, "dir": func(p string) string { return filepath.ToSlash(filepath.Dir(p)) }}).Parse(serviceReadmeTemplate))
+	for _, option := range transformationOptions {
+		readme := serviceReadme{
+			ProjectName:  projectName,
+			Name:         option.ServiceName,
+			Transformer:  option.TransformerName,
+			SourceDir:    getServiceRelSourceDir(option, sourceDir),
+			BuildScripts: buildScripts,
+			HelmCharts:   helmCharts,
+		}
+		serviceOutputDir := getServiceOutputDir(option.ServiceName, readme.SourceDir, outputPath)
+		readme.Dockerfiles = getServiceDockerfiles(serviceOutputDir, outputPath)
+		manifestDirs := []string{}
+		for _, manifest := range manifestServices[option.ServiceName] {
+			readme.Manifests = append(readme.Manifests, manifest.path)
+			readme.Images = append(readme.Images, manifest.images...)
+			if dir := filepath.ToSlash(filepath.Dir(manifest.path)); !common.IsPresent(manifestDirs, dir) {
+				manifestDirs = append(manifestDirs, dir)
+			}
+		}
+		readme.Images = common.MergeSlices(nil, readme.Images)
+		for _, manifestDir := range manifestDirs {
+			if common.IsPresent(kustomizationDirs, manifestDir) {
+				readme.Kustomizations = append(readme.Kustomizations, manifestDir)
+			}
+		}
+		for _, prob := range answeredProblems {
+			if isServiceProblem(prob.ID, option.ServiceName) {
+				readme.Answers = append(readme.Answers, migrationReportAnswer{ID: prob.ID, Question: prob.Desc, Answer: formatReportAnswer(prob.Answer)})
+			}
+		}
+		content := bytes.Buffer{}
+		if err := tpl.Execute(&content, readme); err != nil {
+			logrus.Errorf("failed to generate the readme for the service %s . Error: %q", option.ServiceName, err)
+			continue
+		}
+		if err := os.MkdirAll(serviceOutputDir, common.DefaultDirectoryPermission); err != nil {
+			logrus.Errorf("failed to create the directory %s for the readme of the service %s . Error: %q", serviceOutputDir, option.ServiceName, err)
+			continue
+		}
+		readmePath := filepath.Join(serviceOutputDir, common.ServiceReadmeFile)
+		if _, err := os.Stat(readmePath); err == nil {
+			readmePath = filepath.Join(serviceOutputDir, common.ServiceReadmeFallbackFile)
+		}
+		if err := os.WriteFile(readmePath, content.Bytes(), common.DefaultFilePermission); err != nil {
+			logrus.Errorf("failed to write the readme of the service %s to %s . Error: %q", option.ServiceName, readmePath, err)
+			continue
+		}
+		logrus.Debugf("wrote the readme of the service %s to %s", option.ServiceName, readmePath)
+	}
+}
+
+// getServiceRelSourceDir returns the directory of the service relative to the source directory, using slashes
+func getServiceRelSourceDir(option plantypes.PlanArtifact, sourceDir string) string {
+	serviceDirs := option.Paths[artifacts.ServiceDirPathType]
+	if len(serviceDirs) == 0 {
+		return "."
+	}
+	if relServiceDir, err := filepath.Rel(sourceDir, serviceDirs[0]); err == nil && filepath.IsAbs(serviceDirs[0]) {
+		return filepath.ToSlash(relServiceDir)
+	}
+	return filepath.ToSlash(serviceDirs[0])
+}
+
+// getServiceOutputDir returns the directory in the output that contains the source of the service.
+// The directory depends on the layout of the output.
+func getServiceOutputDir(serviceName, relSourceDir, outputPath string) string {
+	candidates := []string{
+		filepath.Join(outputPath, servicesOutputDir, common.MakeFileNameCompliant(serviceName)),
+		filepath.Join(outputPath, common.DefaultSourceDir, relSourceDir),
+	}
+	if relSourceDir != "." {
+		candidates = append(candidates, filepath.Join(outputPath, relSourceDir))
+	}
+	for _, candidate := range candidates {
+		if fi, err := os.Stat(candidate); err == nil && fi.IsDir() {
+			return candidate
+		}
+	}
+	return candidates[0]
+}
+
+// getServiceDockerfiles returns the Dockerfiles in the output directory of the service, relative to the output directory
+func getServiceDockerfiles(serviceOutputDir, outputPath string) []string {
+	dockerfiles := []string{}
+	if err := filepath.WalkDir(serviceOutputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != serviceOutputDir && (d.Name() == common.DeployDir || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != common.DefaultDockerfileName && !strings.HasPrefix(d.Name(), common.DefaultDockerfileName+".") {
+			return nil
+		}
+		if relPath, err := filepath.Rel(outputPath, path); err == nil {
+			dockerfiles = append(dockerfiles, filepath.ToSlash(relPath))
+		}
+		return nil
+	}); err != nil {
+		logrus.Debugf("failed to walk the directory %s to find the Dockerfiles. Error: %q", serviceOutputDir, err)
+	}
+	return dockerfiles
+}
+
+// outputManifest is a manifest file whose resources all belong to a single service
+type outputManifest struct {
+	path   string
+	images []string
+}
+
+// getOutputManifests returns the manifest files of each service, the directories with a kustomization
+// and the helm charts in the output, all relative to the output directory
+func getOutputManifests(outputPath string) (map[string][]outputManifest, []string, []string) {
+	manifestServices := map[string][]outputManifest{}
+	kustomizationDirs := []string{}
+	helmCharts := []string{}
+	if err := filepath.WalkDir(outputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != outputPath && (d.Name() == common.DefaultSourceDir || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		relPath, err := filepath.Rel(outputPath, path)
+		if err != nil {
+			return nil
+		}
+		switch d.Name() {
+		case "Chart.yaml":
+			helmCharts = append(helmCharts, filepath.ToSlash(filepath.Dir(relPath)))
+			return nil
+		case "kustomization.yaml":
+			kustomizationDirs = append(kustomizationDirs, filepath.ToSlash(filepath.Dir(relPath)))
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		if serviceName, images := getManifestServiceAndImages(path); serviceName != "" {
+			manifestServices[serviceName] = append(manifestServices[serviceName], outputManifest{path: filepath.ToSlash(relPath), images: images})
+		}
+		return nil
+	}); err != nil {
+		logrus.Errorf("failed to walk the output directory %s to find the manifests. Error: %q", outputPath, err)
+	}
+	sort.Strings(helmCharts)
+	return manifestServices, kustomizationDirs, helmCharts
+}
+
+// getManifestServiceAndImages returns the service all the resources in the manifest file belong to and the images they use.
+// Returns an empty service name if the resources belong to different services or to none.
+func getManifestServiceAndImages(manifestPath string) (string, []string) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil || strings.Contains(string(data), "{{") {
+		return "", nil
+	}
+	docs, err := common.SplitYAML(data)
+	if err != nil {
+		return "", nil
+	}
+	serviceName := ""
+	images := []string{}
+	for _, doc := range docs {
+		var resource map[string]interface{}
+		if err := yaml.Unmarshal(doc, &resource); err != nil {
+			return "", nil
+		}
+		metadata, _ := resource["metadata"].(map[string]interface{})
+		labels, _ := metadata["labels"].(map[string]interface{})
+		currServiceName, _ := labels[readmeServiceLabel].(string)
+		if currServiceName == "" || (serviceName != "" && serviceName != currServiceName) {
+			return "", nil
+		}
+		serviceName = currServiceName
+		images = append(images, getContainerImages(resource)...)
+	}
+	return serviceName, images
+}
+
+// getContainerImages returns the images of the containers anywhere inside the resource
+func getContainerImages(value interface{}) []string {
+	images := []string{}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if key == "containers" || key == "initContainers" {
+				containers, _ := child.([]interface{})
+				for _, containerI := range containers {
+					container, _ := containerI.(map[string]interface{})
+					if image, ok := container["image"].(string); ok && image != "" {
+						images = append(images, image)
+					}
+				}
+				continue
+			}
+			images = append(images, getContainerImages(child)...)
+		}
+	case []interface{}:
+		for _, child := range v {
+			images = append(images, getContainerImages(child)...)
+		}
+	}
+	return images
+}
+
+// isServiceProblem returns true if the question id has a segment with the service name
+func isServiceProblem(id, serviceName string) bool {
+	return strings.Contains(id, `"`+serviceName+`"`) || strings.Contains(id+common.Delim, common.Delim+serviceName+common.Delim)
+}
@@ -0,0 +1,189 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/konveyor/move2kube/types/transformer/artifacts"
+)
+
+func TestIsServiceProblem(t *testing.T) {
+	testCases := []struct {
+		name        string
+		id          string
+		serviceName string
+		want        bool
+	}{
+		{name: "quoted service name", id: `move2kube.services."web".ports`, serviceName: "web", want: true},
+		{name: "service name segment", id: "move2kube.services.web.ports", serviceName: "web", want: true},
+		{name: "last segment", id: "move2kube.services.web", serviceName: "web", want: true},
+		{name: "prefix of another service", id: "move2kube.services.webapp.ports", serviceName: "web"},
+		{name: "quoted prefix of another service", id: `move2kube.services."webapp".ports`, serviceName: "web"},
+		{name: "global question", id: "move2kube.target.clustertype", serviceName: "web"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := isServiceProblem(testCase.id, testCase.serviceName); got != testCase.want {
+				t.Fatalf("expected the result for the question %s and the service %s to be %t. Actual: %t", testCase.id, testCase.serviceName, testCase.want, got)
+			}
+		})
+	}
+}
+
+func TestGetManifestServiceAndImages(t *testing.T) {
+	testCases := []struct {
+		name        string
+		data        string
+		wantService string
+		wantImages  []string
+	}{
+		{
+			name: "deployment with init containers",
+			data: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    move2kube.konveyor.io/service: web
+spec:
+  template:
+    spec:
+      initContainers:
+        - image: busybox
+      containers:
+        - image: quay.io/myproject/web:latest
+`,
+			wantService: "web",
+			wantImages:  []string{"busybox", "quay.io/myproject/web:latest"},
+		},
+		{
+			name:        "resources of a single service",
+			data:        "kind: Service\nmetadata:\n  labels:\n    move2kube.konveyor.io/service: web\n---\nkind: Route\nmetadata:\n  labels:\n    move2kube.konveyor.io/service: web\n",
+			wantService: "web",
+			wantImages:  []string{},
+		},
+		{
+			name: "resources of different services",
+			data: "kind: Service\nmetadata:\n  labels:\n    move2kube.konveyor.io/service: web\n---\nkind: Service\nmetadata:\n  labels:\n    move2kube.konveyor.io/service: api\n",
+		},
+		{
+			name: "helm template",
+			data: "kind: Service\nmetadata:\n  name: {{ .Release.Name }}\n  labels:\n    move2kube.konveyor.io/service: web\n",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			manifestPath := filepath.Join(t.TempDir(), "manifest.yaml")
+			writeValidationTestFile(t, manifestPath, testCase.data)
+			serviceName, images := getManifestServiceAndImages(manifestPath)
+			sort.Strings(images)
+			if serviceName != testCase.wantService {
+				t.Fatalf("expected the service to be %q. Actual: %q", testCase.wantService, serviceName)
+			}
+			if !cmp.Equal(images, testCase.wantImages) {
+				t.Fatalf("the images are incorrect. Differences:\n%s", cmp.Diff(testCase.wantImages, images))
+			}
+		})
+	}
+}
+
+func TestWriteServiceReadmes(t *testing.T) {
+	sourceDir := t.TempDir()
+	outputPath := t.TempDir()
+	writeValidationTestFile(t, filepath.Join(outputPath, common.DefaultSourceDir, "web", "Dockerfile"), "FROM nginx\n")
+	writeValidationTestFile(t, filepath.Join(outputPath, common.DefaultSourceDir, "api", "README.md"), "# api\n")
+	writeValidationTestFile(t, filepath.Join(outputPath, common.DeployDir, "yamls", "web-deployment.yaml"), `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    move2kube.konveyor.io/service: web
+spec:
+  template:
+    spec:
+      containers:
+        - image: quay.io/myproject/web:latest
+`)
+	writeValidationTestFile(t, filepath.Join(outputPath, common.DeployDir, "yamls", "kustomization.yaml"), "resources:\n  - web-deployment.yaml\n")
+	writeValidationTestFile(t, filepath.Join(outputPath, common.DeployDir, "helm-chart", "myproject", "Chart.yaml"), "name: myproject\n")
+	writeValidationTestFile(t, filepath.Join(outputPath, common.ScriptsDir, "buildimages.sh"), "#!/bin/sh\n")
+	newPlanArtifact := func(serviceName string, serviceDir string) plantypes.PlanArtifact {
+		return plantypes.PlanArtifact{
+			ServiceName:     serviceName,
+			TransformerName: "Nginx",
+			Artifact:        transformertypes.Artifact{Paths: map[transformertypes.PathType][]string{artifacts.ServiceDirPathType: {serviceDir}}},
+		}
+	}
+	writeServiceReadmes("myproject", sourceDir, []plantypes.PlanArtifact{
+		newPlanArtifact("web", filepath.Join(sourceDir, "web")),
+		newPlanArtifact("api", filepath.Join(sourceDir, "api")),
+	}, outputPath)
+
+	testCases := []struct {
+		name       string
+		readmePath string
+		want       []string
+	}{
+		{
+			name:       "service with a Dockerfile and manifests",
+			readmePath: filepath.Join(outputPath, common.DefaultSourceDir, "web", common.ServiceReadmeFile),
+			want: []string{
+				"# web\n",
+				"The service was detected in the directory `web` of the source by the `Nginx` transformer.",
+				"docker build -f source/web/Dockerfile -t quay.io/myproject/web:latest source/web\n",
+				"- quay.io/myproject/web:latest\n",
+				"- scripts/buildimages.sh\n",
+				"kubectl apply -f deploy/yamls/web-deployment.yaml\n",
+				"kubectl apply -k deploy/yamls\n",
+				"helm upgrade --install myproject deploy/helm-chart/myproject\n",
+			},
+		},
+		{
+			name:       "service with an existing readme",
+			readmePath: filepath.Join(outputPath, common.DefaultSourceDir, "api", common.ServiceReadmeFallbackFile),
+			want: []string{
+				"# api\n",
+				"No Dockerfile was generated for this service.",
+				"No manifests were generated for this service.",
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			data, err := os.ReadFile(testCase.readmePath)
+			if err != nil {
+				t.Fatalf("failed to read the readme %s . Error: %q", testCase.readmePath, err)
+			}
+			for _, want := range testCase.want {
+				if !strings.Contains(string(data), want) {
+					t.Fatalf("expected the readme to contain %q . Actual:\n%s", want, string(data))
+				}
+			}
+		})
+	}
+	data, err := os.ReadFile(filepath.Join(outputPath, common.DefaultSourceDir, "api", common.ServiceReadmeFile))
+	if err != nil || string(data) != "# api\n" {
+		t.Fatalf("expected the existing readme of the service to be left untouched. Actual: %q Error: %v", string(data), err)
+	}
+}
@@ -20,20 +20,43 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
+	"sync"
 
 	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/tracing"
 	"github.com/konveyor/move2kube/qaengine"
 	"github.com/konveyor/move2kube/transformer"
 	plantypes "github.com/konveyor/move2kube/types/plan"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+var (
+	// transformMessages collects the warnings and errors of the transformation for the migration report
+	transformMessages = common.NewMessageCollectorHook()
+	addMessagesHook   sync.Once
+)
+
+// getMessageCollector registers the message collector hook the first time and clears the messages of the previous transformation
+func getMessageCollector() *common.MessageCollectorHook {
+	addMessagesHook.Do(func() { logrus.AddHook(transformMessages) })
+	transformMessages.Reset()
+	return transformMessages
+}
+
 // Transform transforms the artifacts and writes output
-func Transform(ctx context.Context, plan plantypes.Plan, preExistingPlan bool, outputPath string, transformerSelector string) error {
+func Transform(ctx context.Context, plan plantypes.Plan, preExistingPlan bool, outputPath string, transformerSelector string) (err error) {
+	ctx, span := tracing.Start(ctx, "Transform", attribute.String("project", plan.Name), attribute.String("outputPath", outputPath), attribute.Int("parallel", common.GetParallelism()))
+	defer func() { tracing.End(span, err) }()
 	logrus.Infof("Starting transformation")
+	logrus.Debugf("Using %d parallel workers", common.GetParallelism())
+	messages := getMessageCollector()
+	common.SetProgressPhase(common.ProgressPhaseInitializing)
 
 	common.ProjectName = plan.Name
+	common.Environments = selectEnvironments(plan.Spec.Environments)
 	logrus.Debugf("common.TempPath: '%s'", common.TempPath)
 
 	transformerSelectorObj, err := common.ConvertStringSelectorsToSelectors(transformerSelector)
@@ -47,10 +70,14 @@ func Transform(ctx context.Context, plan plantypes.Plan, preExistingPlan bool, o
 	requirements, _ := selectorsInPlan.Requirements()
 	transformerSelectorObj = transformerSelectorObj.Add(requirements...)
 
-	if _, err := transformer.InitTransformers(plan.Spec.Transformers, transformerSelectorObj, plan.Spec.SourceDir, outputPath, plan.Name, true, preExistingPlan); err != nil {
+	if _, err := transformer.InitTransformers(ctx, plan.Spec.Transformers, transformerSelectorObj, plan.Spec.SourceDir, outputPath, plan.Name, true, preExistingPlan); err != nil {
 		return fmt.Errorf("failed to initialize the transformers. Error: %w", err)
 	}
+	return transformServices(ctx, plan, outputPath, messages)
+}
 
+// transformServices transforms the selected services using the initialized transformers and writes the reports
+func transformServices(ctx context.Context, plan plantypes.Plan, outputPath string, messages *common.MessageCollectorHook) error {
 	// select only the services the user is interested in
 	serviceNames := []string{}
 	for serviceName := range plan.Spec.Services {
@@ -84,16 +111,52 @@ func Transform(ctx context.Context, plan plantypes.Plan, preExistingPlan bool, o
 	}
 
 	// transform the selected services using the selected transformation options
-	if err := transformer.Transform(selectedTransformationOptions, plan.Spec.SourceDir, outputPath); err != nil {
+	common.SetProgressPhase(common.ProgressPhaseTransforming)
+	if err := transformer.Transform(ctx, selectedTransformationOptions, plan.Spec.SourceDir, outputPath); err != nil {
 		return fmt.Errorf("failed to transform using the plan. Error: %w", err)
 	}
+	selectedServices := map[string][]plantypes.PlanArtifact{}
+	for _, selectedTransformationOption := range selectedTransformationOptions {
+		selectedServices[selectedTransformationOption.ServiceName] = plan.Spec.Services[selectedTransformationOption.ServiceName]
+	}
+	writeReadinessReport(plan.Spec.SourceDir, selectedServices, plan.Spec.Readiness, outputPath)
+	writeServiceReadmes(plan.Name, plan.Spec.SourceDir, selectedTransformationOptions, outputPath)
+	writeMigrationReport(plan.Name, plan.Spec.SourceDir, selectedTransformationOptions, outputPath, messages.GetMessages())
+	if err := runPostTransformHooks(ctx, plan, selectedTransformationOptions, outputPath); err != nil {
+		return fmt.Errorf("failed to run the hooks after the transformation. Error: %w", err)
+	}
 
+	common.SetProgressPhase(common.ProgressPhaseDone)
 	logrus.Infof("Transformation done")
 	return nil
 }
 
+// selectEnvironments returns the target environments used for parameterization.
+// The environments in the plan, which include the ones given using the flag, are used as is and the question is asked only when there are none.
+func selectEnvironments(planEnvironments []string) []string {
+	answer := strings.Join(planEnvironments, ",")
+	if len(planEnvironments) == 0 {
+		answer = qaengine.FetchStringAnswer(common.ConfigTargetEnvironmentsKey, "Enter the comma separated list of target environments to generate parameterized output (Helm values, Kustomize overlays and OpenShift template parameters) for:", []string{"Example: dev,staging,prod"}, strings.Join(common.DefaultEnvironments, ","), nil)
+	}
+	environments := []string{}
+	for _, environment := range strings.Split(answer, ",") {
+		environment = common.NormalizeForMetadataName(strings.TrimSpace(environment))
+		if environment == "" {
+			continue
+		}
+		environments = common.AppendIfNotPresent(environments, environment)
+	}
+	logrus.Debugf("Using the target environments %+v", environments)
+	return environments
+}
+
 // Destroy destroys the tranformers
 func Destroy() {
 	logrus.Debugf("Cleaning up!")
 	transformer.Destroy()
 }
+
+// GetProgress returns the progress of the plan or transform that is currently running
+func GetProgress() common.ProgressT {
+	return common.GetProgress()
+}
@@ -0,0 +1,71 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	"github.com/sirupsen/logrus"
+)
+
+func TestGetMessageCollector(t *testing.T) {
+	messages := getMessageCollector()
+	logrus.Warnf("a warning from the first transformation")
+	if len(messages.GetMessages()) != 1 {
+		t.Fatalf("expected the warning to be collected. Actual: %+v", messages.GetMessages())
+	}
+	messages = getMessageCollector()
+	if len(messages.GetMessages()) != 0 {
+		t.Fatalf("expected the messages of the previous transformation to be cleared. Actual: %+v", messages.GetMessages())
+	}
+	logrus.Warnf("a warning from the second transformation")
+	if got := messages.GetMessages(); len(got) != 1 {
+		t.Fatalf("expected the hook to be registered only once. Actual messages: %+v", got)
+	}
+	registered := 0
+	for _, hook := range logrus.StandardLogger().Hooks[logrus.WarnLevel] {
+		if hook == messages {
+			registered++
+		}
+	}
+	if registered != 1 {
+		t.Fatalf("expected the hook to be registered once. Actual: %d", registered)
+	}
+}
+
+func TestSelectEnvironments(t *testing.T) {
+	qaengine.AddEngine(qaengine.NewDefaultEngine())
+	testCases := []struct {
+		name             string
+		planEnvironments []string
+		want             []string
+	}{
+		{name: "the default environments are used when the plan has none", want: common.DefaultEnvironments},
+		{name: "the plan environments are used without asking", planEnvironments: []string{"qa", "prod"}, want: []string{"qa", "prod"}},
+		{name: "the plan environments are normalized", planEnvironments: []string{" QA ", "prod", "qa"}, want: []string{"qa", "prod"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := selectEnvironments(testCase.planEnvironments); !cmp.Equal(got, testCase.want) {
+				t.Fatalf("the environments are incorrect. Expected: %+v Actual: %+v", testCase.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/vcs"
+	"github.com/konveyor/move2kube/filesystem"
+	"github.com/konveyor/move2kube/types"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// transformerIndexFileName is the name of the index file inside directories, archives and OCI images
+	transformerIndexFileName = "index.yaml"
+	// transformerIndexTimeout is how long downloading a transformer index over http(s) can take
+	transformerIndexTimeout = time.Minute
+)
+
+// TransformerIndexEntry is a transformer along with the index it was found in
+type TransformerIndexEntry struct {
+	transformertypes.TransformerIndexEntry
+	// Index is the location of the index the transformer was found in
+	Index string
+	// base is the directory or url the relative sources in the index are resolved against
+	base string
+}
+
+// ListTransformersInIndexes returns the transformers in all the indexes.
+// An index can be a local file or directory, a http(s) url, an archive url, a git remote path or an oci://<image> reference.
+func ListTransformersInIndexes(indexPaths []string) ([]TransformerIndexEntry, error) {
+	entries := []TransformerIndexEntry{}
+	for _, indexPath := range indexPaths {
+		index, base, err := fetchTransformerIndex(indexPath)
+		if err != nil {
+			return entries, err
+		}
+		for _, entry := range index.Spec.Transformers {
+			entries = append(entries, TransformerIndexEntry{TransformerIndexEntry: entry, Index: indexPath, base: base})
+		}
+	}
+	return entries, nil
+}
+
+// InstallTransformer fetches the given version of the transformer from the first index that contains it
+// and copies it into the customizations directory. An empty version installs the latest version.
+// Returns the directory the transformer was installed into.
+func InstallTransformer(indexPaths []string, transformerName, version, customizationsPath string, overwrite bool) (string, error) {
+	entries, err := ListTransformersInIndexes(indexPaths)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.Name != transformerName {
+			continue
+		}
+		selectedVersion, err := selectTransformerVersion(entry.TransformerIndexEntry, version)
+		if err != nil {
+			return "", fmt.Errorf("failed to select the version of the transformer %s in the index %s . Error: %w", transformerName, entry.Index, err)
+		}
+		destPath := filepath.Join(customizationsPath, common.NormalizeForFilename(transformerName))
+		if _, err := os.Stat(destPath); err == nil {
+			if !overwrite {
+				return "", fmt.Errorf("the directory %s already exists. Use the overwrite flag to replace it", destPath)
+			}
+			if err := os.RemoveAll(destPath); err != nil {
+				return "", fmt.Errorf("failed to remove the directory %s . Error: %w", destPath, err)
+			}
+		}
+		srcPath, err := fetchTransformerSource(entry.base, selectedVersion.Source)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch the version %s of the transformer %s . Error: %w", selectedVersion.Version, transformerName, err)
+		}
+		if !containsTransformer(srcPath) {
+			return "", fmt.Errorf("did not find any transformer yamls in the version %s of the transformer %s fetched from %s", selectedVersion.Version, transformerName, selectedVersion.Source)
+		}
+		if err := os.MkdirAll(customizationsPath, common.DefaultDirectoryPermission); err != nil {
+			return "", fmt.Errorf("failed to create the customizations directory %s . Error: %w", customizationsPath, err)
+		}
+		if err := filesystem.Replicate(srcPath, destPath); err != nil {
+			return "", fmt.Errorf("failed to copy the transformer from %s to %s . Error: %w", srcPath, destPath, err)
+		}
+		logrus.Infof("Installed the version %s of the transformer %s into %s", selectedVersion.Version, transformerName, destPath)
+		return destPath, nil
+	}
+	return "", fmt.Errorf("the transformer %s was not found in the indexes %+v", transformerName, indexPaths)
+}
+
+// GetLatestTransformerVersion returns the latest version of the transformer in the index entry
+func GetLatestTransformerVersion(entry transformertypes.TransformerIndexEntry) (transformertypes.TransformerIndexVersion, error) {
+	return selectTransformerVersion(entry, "")
+}
+
+// selectTransformerVersion returns the given version or the latest version if the given version is empty.
+// The versions are compared as semantic versions. Versions that are not valid semantic versions are only selected if they are the only version.
+func selectTransformerVersion(entry transformertypes.TransformerIndexEntry, version string) (transformertypes.TransformerIndexVersion, error) {
+	if len(entry.Versions) == 0 {
+		return transformertypes.TransformerIndexVersion{}, fmt.Errorf("the transformer %s does not have any versions", entry.Name)
+	}
+	if version != "" {
+		for _, v := range entry.Versions {
+			if v.Version == version || strings.TrimPrefix(v.Version, "v") == strings.TrimPrefix(version, "v") {
+				return v, nil
+			}
+		}
+		return transformertypes.TransformerIndexVersion{}, fmt.Errorf("the version %s of the transformer %s was not found", version, entry.Name)
+	}
+	if len(entry.Versions) == 1 {
+		return entry.Versions[0], nil
+	}
+	var latest transformertypes.TransformerIndexVersion
+	var latestSemver *semver.Version
+	for _, v := range entry.Versions {
+		sv, err := semver.NewVersion(v.Version)
+		if err != nil {
+			logrus.Debugf("the version %s of the transformer %s is not a valid semantic version. Error: %q", v.Version, entry.Name, err)
+			continue
+		}
+		if latestSemver == nil || sv.GreaterThan(latestSemver) {
+			latest, latestSemver = v, sv
+		}
+	}
+	if latestSemver == nil {
+		return latest, fmt.Errorf("none of the versions of the transformer %s are valid semantic versions, so specify the version to use", entry.Name)
+	}
+	return latest, nil
+}
+
+// fetchTransformerIndex fetches and parses the index. It also returns the base the relative sources in the index are resolved against.
+func fetchTransformerIndex(indexPath string) (index transformertypes.TransformerIndex, base string, err error) {
+	localPath := indexPath
+	if vcs.IsRemotePath(indexPath) {
+		localPath, err = vcs.FetchRemotePath(indexPath, filepath.Join(common.TempPath, common.RemoteDir))
+		if err != nil {
+			return index, base, fmt.Errorf("failed to fetch the transformer index %s . Error: %w", indexPath, err)
+		}
+	} else if strings.HasPrefix(indexPath, "http://") || strings.HasPrefix(indexPath, "https://") {
+		localPath, err = downloadTransformerIndex(indexPath)
+		if err != nil {
+			return index, base, err
+		}
+		u, err := url.Parse(indexPath)
+		if err != nil {
+			return index, base, fmt.Errorf("failed to parse the url %s . Error: %w", indexPath, err)
+		}
+		u.Path = path.Dir(u.Path) + "/"
+		base = u.String()
+	}
+	if fi, err := os.Stat(localPath); err == nil && fi.IsDir() {
+		localPath = filepath.Join(localPath, transformerIndexFileName)
+	}
+	if base == "" {
+		base = filepath.Dir(localPath)
+	}
+	if err := common.ReadMove2KubeYamlStrict(localPath, &index, transformertypes.TransformerIndexKind); err != nil {
+		return index, base, fmt.Errorf("failed to read the transformer index at %s . Error: %w", indexPath, err)
+	}
+	return index, base, nil
+}
+
+func downloadTransformerIndex(indexURL string) (string, error) {
+	logrus.Debugf("Downloading the transformer index %s", indexURL)
+	resp, err := (&http.Client{Timeout: transformerIndexTimeout}).Get(indexURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to download the transformer index %s . Error: %w", indexURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("failed to download the transformer index %s . Status: %s", indexURL, resp.Status)
+	}
+	tempPath := filepath.Join(common.TempPath, common.RemoteDir)
+	if err := os.MkdirAll(tempPath, common.DefaultDirectoryPermission); err != nil {
+		return "", fmt.Errorf("failed to create the directory %s . Error: %w", tempPath, err)
+	}
+	f, err := os.CreateTemp(tempPath, "index-*.yaml")
+	if err != nil {
+		return "", fmt.Errorf("failed to create a temporary file inside %s . Error: %w", tempPath, err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		return "", fmt.Errorf("failed to write the transformer index downloaded from %s to %s . Error: %w", indexURL, f.Name(), err)
+	}
+	return f.Name(), nil
+}
+
+// fetchTransformerSource returns the local directory containing the transformer, fetching it if it is remote
+func fetchTransformerSource(base, source string) (string, error) {
+	if !vcs.IsRemotePath(source) {
+		if strings.HasPrefix(base, "http://") || strings.HasPrefix(base, "https://") {
+			baseURL, err := url.Parse(base)
+			if err != nil {
+				return "", fmt.Errorf("failed to parse the url %s . Error: %w", base, err)
+			}
+			sourceURL, err := baseURL.Parse(source)
+			if err != nil {
+				return "", fmt.Errorf("failed to resolve the source %s against the index url %s . Error: %w", source, base, err)
+			}
+			source = sourceURL.String()
+		} else if !filepath.IsAbs(source) {
+			source = filepath.Join(base, source)
+		}
+	}
+	if vcs.IsRemotePath(source) {
+		return vcs.FetchRemotePath(source, filepath.Join(common.TempPath, common.RemoteDir))
+	}
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return "", fmt.Errorf("the source %s must be a git remote path, an archive or an OCI image", source)
+	}
+	if common.IsArchiveFile(source) {
+		extractedPath, err := os.MkdirTemp(common.TempPath, "transformer-*")
+		if err != nil {
+			return "", fmt.Errorf("failed to create a temporary directory. Error: %w", err)
+		}
+		if err := common.ExtractArchive(source, extractedPath); err != nil {
+			return "", fmt.Errorf("failed to extract the archive %s . Error: %w", source, err)
+		}
+		return extractedPath, nil
+	}
+	return source, nil
+}
+
+// containsTransformer returns true if there is at least one transformer yaml in the directory
+func containsTransformer(dir string) bool {
+	yamlPaths, err := common.GetFilesByExt(dir, []string{".yml", ".yaml"})
+	if err != nil {
+		logrus.Debugf("failed to get the yaml files in the directory %s . Error: %q", dir, err)
+		return false
+	}
+	for _, yamlPath := range yamlPaths {
+		typeMeta := types.TypeMeta{}
+		if err := common.ReadMove2KubeYaml(yamlPath, &typeMeta); err != nil {
+			continue
+		}
+		if typeMeta.Kind == transformertypes.TransformerKind {
+			return true
+		}
+	}
+	return false
+}
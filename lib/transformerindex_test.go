@@ -0,0 +1,63 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"testing"
+
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+)
+
+func TestSelectTransformerVersion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		versions []string
+		version  string
+		want     string
+		wantErr  bool
+	}{
+		{name: "no versions", wantErr: true},
+		{name: "the latest semantic version", versions: []string{"v0.1.0", "v0.10.0", "v0.9.1"}, want: "v0.10.0"},
+		{name: "the versions that are not semantic versions are ignored", versions: []string{"main", "v0.2.0", "v0.1.0"}, want: "v0.2.0"},
+		{name: "the only version is used even if it is not a semantic version", versions: []string{"main"}, want: "main"},
+		{name: "none of the versions are semantic versions", versions: []string{"main", "develop"}, wantErr: true},
+		{name: "the given version", versions: []string{"v0.1.0", "v0.2.0"}, version: "v0.1.0", want: "v0.1.0"},
+		{name: "the given version without the v prefix", versions: []string{"v0.1.0", "v0.2.0"}, version: "0.1.0", want: "v0.1.0"},
+		{name: "the given version is not in the index", versions: []string{"v0.1.0"}, version: "v0.3.0", wantErr: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			entry := transformertypes.TransformerIndexEntry{Name: "Custom"}
+			for _, version := range testCase.versions {
+				entry.Versions = append(entry.Versions, transformertypes.TransformerIndexVersion{Version: version})
+			}
+			got, err := selectTransformerVersion(entry, testCase.version)
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error. Actual: %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to select the version. Error: %q", err)
+			}
+			if got.Version != testCase.want {
+				t.Fatalf("the selected version is incorrect. Expected: %s Actual: %s", testCase.want, got.Version)
+			}
+		})
+	}
+}
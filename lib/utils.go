@@ -72,7 +72,7 @@ func CopyCustomizationsAssetsData(customizationsPath string) (err error) {
 		logrus.Errorf("Unable to make the assets path %q absolute. Error: %q", assetsPath, err)
 		return err
 	}
-	customizationsAssetsPath := filepath.Join(assetsPath, "custom")
+	customizationsAssetsPath := filepath.Join(assetsPath, common.CustomAssetsDir)
 
 	// Create the subdirectory and copy the assets into it.
 	if err = os.MkdirAll(customizationsAssetsPath, common.DefaultDirectoryPermission); err != nil {
@@ -0,0 +1,367 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	"github.com/konveyor/move2kube/transformer/kubernetes/parameterizer"
+	"github.com/konveyor/move2kube/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"go.starlark.net/syntax"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// starlarkTransformerClass is the class of the transformers written in Starlark
+	starlarkTransformerClass = "Starlark"
+	// starlarkFileExt is the extension of the Starlark files
+	starlarkFileExt = ".star"
+)
+
+// yamlErrorLineRegex finds the line in the errors returned by the yaml parser, like "yaml: line 3: did not find expected key"
+var yamlErrorLineRegex = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// ValidationIssue is a problem found while validating a plan or the customizations without running a transform
+type ValidationIssue struct {
+	Path    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// String returns the issue prefixed with its position, like path:line:column: message
+func (i ValidationIssue) String() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s", i.Path, i.Message)
+	}
+	if i.Column == 0 {
+		return fmt.Sprintf("%s:%d: %s", i.Path, i.Line, i.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", i.Path, i.Line, i.Column, i.Message)
+}
+
+// ValidatePlanFile validates the plan file and returns all the issues found, ordered by their position
+func ValidatePlanFile(planPath string) []ValidationIssue {
+	root, issues := readYamlForValidation(planPath)
+	if root == nil {
+		return issues
+	}
+	issues = append(issues, validateTypeMeta(planPath, root, string(plantypes.PlanKind))...)
+	plan := plantypes.Plan{}
+	if err := root.Decode(&plan); err != nil {
+		return sortValidationIssues(append(issues, getYamlErrorIssues(planPath, err)...))
+	}
+	if plan.Spec.SourceDir != "" && plan.Spec.SourceURL == "" {
+		if _, err := os.Stat(plan.Spec.SourceDir); err != nil {
+			issues = append(issues, newYamlValidationIssue(planPath, root, fmt.Sprintf("the source directory %s does not exist", plan.Spec.SourceDir), "spec", "sourceDir"))
+		}
+	}
+	if plan.Spec.CustomizationsDir != "" && plan.Spec.CustomizationsURL == "" {
+		if _, err := os.Stat(plan.Spec.CustomizationsDir); err != nil {
+			issues = append(issues, newYamlValidationIssue(planPath, root, fmt.Sprintf("the customizations directory %s does not exist", plan.Spec.CustomizationsDir), "spec", "customizationsDir"))
+		}
+	}
+	for serviceName, artifacts := range plan.Spec.Services {
+		if len(artifacts) == 0 {
+			issues = append(issues, newYamlValidationIssue(planPath, root, fmt.Sprintf("the service %s does not have any artifacts", serviceName), "spec", "services", serviceName))
+		}
+		for i, artifact := range artifacts {
+			keys := []string{"spec", "services", serviceName, strconv.Itoa(i)}
+			if artifact.TransformerName == "" {
+				issues = append(issues, newYamlValidationIssue(planPath, root, fmt.Sprintf("the artifact %d of the service %s does not have a transformerName", i, serviceName), keys...))
+				continue
+			}
+			if len(plan.Spec.Transformers) == 0 {
+				continue
+			}
+			if _, ok := plan.Spec.Transformers[artifact.TransformerName]; !ok {
+				message := fmt.Sprintf("the service %s uses the transformer %s which is not one of the transformers in spec.transformers", serviceName, artifact.TransformerName)
+				if _, ok := plan.Spec.DisabledTransformers[artifact.TransformerName]; ok {
+					message = fmt.Sprintf("the service %s uses the transformer %s which is disabled", serviceName, artifact.TransformerName)
+				}
+				issues = append(issues, newYamlValidationIssue(planPath, root, message, append(keys, "transformerName")...))
+			}
+		}
+	}
+	return sortValidationIssues(issues)
+}
+
+// ValidateCustomizations validates the transformer yamls, the parameterizer yamls and the Starlark files
+// in the customizations directory and returns all the issues found, ordered by the file and the position
+func ValidateCustomizations(customizationsDir string) []ValidationIssue {
+	if _, err := os.Stat(customizationsDir); err != nil {
+		return []ValidationIssue{{Path: customizationsDir, Message: fmt.Sprintf("failed to access the customizations directory. Error: %s", err)}}
+	}
+	issues := []ValidationIssue{}
+	// starFiles are the Starlark files referenced by the yamls along with the issue to report if they are missing
+	starFiles := map[string]ValidationIssue{}
+	yamlPaths, err := common.GetFilesByExt(customizationsDir, []string{".yaml", ".yml"})
+	if err != nil {
+		issues = append(issues, ValidationIssue{Path: customizationsDir, Message: fmt.Sprintf("failed to find the yaml files. Error: %s", err)})
+	}
+	for _, yamlPath := range yamlPaths {
+		data, err := os.ReadFile(yamlPath)
+		if err != nil {
+			issues = append(issues, ValidationIssue{Path: yamlPath, Message: fmt.Sprintf("failed to read the file. Error: %s", err)})
+			continue
+		}
+		header := types.TypeMeta{}
+		if err := yaml.Unmarshal(data, &header); err != nil || !isMove2KubeAPIVersion(header.APIVersion) {
+			// only the yamls with the move2kube api version are customizations, others like k8s yamls are ignored
+			continue
+		}
+		switch header.Kind {
+		case transformertypes.TransformerKind:
+			issues = append(issues, validateTransformerYaml(yamlPath, data, starFiles)...)
+		case parameterizer.ParameterizerKind:
+			issues = append(issues, validateParameterizerYaml(yamlPath, data, starFiles)...)
+		}
+	}
+	starPaths, err := common.GetFilesByExt(customizationsDir, []string{starlarkFileExt})
+	if err != nil {
+		issues = append(issues, ValidationIssue{Path: customizationsDir, Message: fmt.Sprintf("failed to find the Starlark files. Error: %s", err)})
+	}
+	for _, starPath := range starPaths {
+		if _, ok := starFiles[starPath]; !ok {
+			starFiles[starPath] = ValidationIssue{}
+		}
+	}
+	for starPath, missingIssue := range starFiles {
+		if _, err := os.Stat(starPath); err != nil {
+			issues = append(issues, missingIssue)
+			continue
+		}
+		issues = append(issues, validateStarlarkFile(starPath)...)
+	}
+	return sortValidationIssues(issues)
+}
+
+// validateTransformerYaml validates the transformer yaml against the schema of the transformers and the schema of its config
+func validateTransformerYaml(path string, data []byte, starFiles map[string]ValidationIssue) []ValidationIssue {
+	issues := []ValidationIssue{}
+	violations, err := transformertypes.ValidateTransformerYaml(data)
+	if err != nil {
+		return getYamlErrorIssues(path, err)
+	}
+	issues = append(issues, getSchemaViolationIssues(path, violations)...)
+	root := yaml.Node{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return append(issues, getYamlErrorIssues(path, err)...)
+	}
+	tc := transformertypes.NewTransformer()
+	if err := root.Decode(&tc); err != nil {
+		return append(issues, getYamlErrorIssues(path, err)...)
+	}
+	if tc.Spec.ConfigSchema != "" {
+		configSchemaPath := getPathRelativeToFile(path, tc.Spec.ConfigSchema)
+		var configSchema interface{}
+		if configSchemaData, err := os.ReadFile(configSchemaPath); err != nil {
+			issues = append(issues, newYamlValidationIssue(path, &root, fmt.Sprintf("failed to read the config schema at path %s . Error: %s", configSchemaPath, err), "spec", "configSchema"))
+		} else if err := yaml.Unmarshal(configSchemaData, &configSchema); err != nil {
+			issues = append(issues, getYamlErrorIssues(configSchemaPath, err)...)
+		} else if violations, err := transformertypes.ValidateTransformerConfig(data, configSchema); err != nil {
+			issues = append(issues, newYamlValidationIssue(path, &root, fmt.Sprintf("failed to validate the config against the config schema. Error: %s", err), "spec", "config"))
+		} else {
+			issues = append(issues, getSchemaViolationIssues(path, violations)...)
+		}
+	}
+	if tc.Spec.Class == starlarkTransformerClass {
+		starConfig := struct {
+			StarFile string `yaml:"starFile"`
+		}{}
+		if err := common.GetObjFromInterface(tc.Spec.Config, &starConfig); err != nil || starConfig.StarFile == "" {
+			issues = append(issues, newYamlValidationIssue(path, &root, "the Starlark transformer does not have a starFile in its config", "spec", "config"))
+		} else {
+			starFile := getPathRelativeToFile(path, starConfig.StarFile)
+			starFiles[starFile] = newYamlValidationIssue(path, &root, fmt.Sprintf("the Starlark file %s does not exist", starFile), "spec", "config", "starFile")
+		}
+	}
+	return issues
+}
+
+// validateParameterizerYaml validates the parameterizers in the yaml and collects their Starlark file
+func validateParameterizerYaml(path string, data []byte, starFiles map[string]ValidationIssue) []ValidationIssue {
+	root := yaml.Node{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return getYamlErrorIssues(path, err)
+	}
+	issues := []ValidationIssue{}
+	paramFile := parameterizer.ParameterizerFileT{}
+	if err := root.Decode(&paramFile); err != nil {
+		return getYamlErrorIssues(path, err)
+	}
+	if paramFile.ObjectMeta.Name == "" {
+		issues = append(issues, newYamlValidationIssue(path, &root, "the parameterizer does not have a name", "metadata", "name"))
+	}
+	for i, param := range paramFile.Spec.Parameterizers {
+		keys := []string{"spec", "parameterizers", strconv.Itoa(i)}
+		if param.Target == "" {
+			issues = append(issues, newYamlValidationIssue(path, &root, fmt.Sprintf("the parameterizer %d does not have a target", i), keys...))
+		}
+		if param.Regex != "" {
+			if _, err := regexp.Compile(param.Regex); err != nil {
+				issues = append(issues, newYamlValidationIssue(path, &root, fmt.Sprintf("the regex %s is invalid. Error: %s", param.Regex, err), append(keys, "regex")...))
+			}
+		}
+		if param.Question != nil {
+			// the type and the id are filled in while parameterizing, if they are not given
+			question := *param.Question
+			if question.Type == "" {
+				question.Type = qatypes.InputSolutionFormType
+			}
+			if question.ID == "" {
+				question.ID = common.JoinQASubKeys(parameterizer.ParamQuesIDPrefix, param.Target)
+			}
+			if err := qaengine.ValidateProblem(question); err != nil {
+				issues = append(issues, newYamlValidationIssue(path, &root, fmt.Sprintf("the question is invalid. Error: %s", err), append(keys, "question")...))
+			}
+		}
+		for j, parameter := range param.Parameters {
+			if parameter.Name == "" {
+				issues = append(issues, newYamlValidationIssue(path, &root, fmt.Sprintf("the parameter %d does not have a name", j), append(keys, "parameters", strconv.Itoa(j))...))
+			}
+		}
+	}
+	if paramFile.Spec.StarFile != "" {
+		starFile := getPathRelativeToFile(path, paramFile.Spec.StarFile)
+		starFiles[starFile] = newYamlValidationIssue(path, &root, fmt.Sprintf("the Starlark file %s does not exist", starFile), "spec", "starFile")
+	}
+	return issues
+}
+
+// validateStarlarkFile checks the syntax of the Starlark file
+func validateStarlarkFile(path string) []ValidationIssue {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []ValidationIssue{{Path: path, Message: fmt.Sprintf("failed to read the file. Error: %s", err)}}
+	}
+	if _, err := syntax.Parse(path, data, 0); err != nil {
+		if syntaxErr, ok := err.(syntax.Error); ok {
+			return []ValidationIssue{{Path: path, Line: int(syntaxErr.Pos.Line), Column: int(syntaxErr.Pos.Col), Message: syntaxErr.Msg}}
+		}
+		return []ValidationIssue{{Path: path, Message: err.Error()}}
+	}
+	return nil
+}
+
+// readYamlForValidation reads the yaml file, returning the issues instead of the root node if it can't be parsed
+func readYamlForValidation(path string) (*yaml.Node, []ValidationIssue) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, []ValidationIssue{{Path: path, Message: fmt.Sprintf("failed to read the file. Error: %s", err)}}
+	}
+	root := yaml.Node{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, getYamlErrorIssues(path, err)
+	}
+	if len(root.Content) == 0 {
+		return nil, []ValidationIssue{{Path: path, Message: "the file is empty"}}
+	}
+	return &root, nil
+}
+
+// validateTypeMeta checks that the yaml has the move2kube api version and the kind
+func validateTypeMeta(path string, root *yaml.Node, kind string) []ValidationIssue {
+	issues := []ValidationIssue{}
+	header := types.TypeMeta{}
+	if err := root.Decode(&header); err != nil {
+		return getYamlErrorIssues(path, err)
+	}
+	if !isMove2KubeAPIVersion(header.APIVersion) {
+		issues = append(issues, newYamlValidationIssue(path, root, fmt.Sprintf("the apiVersion %q is invalid. Expected: %s", header.APIVersion, types.SchemeGroupVersion.String()), "apiVersion"))
+	}
+	if header.Kind != kind {
+		issues = append(issues, newYamlValidationIssue(path, root, fmt.Sprintf("the kind %q is invalid. Expected: %s", header.Kind, kind), "kind"))
+	}
+	return issues
+}
+
+// isMove2KubeAPIVersion returns true if the api version is in the move2kube group
+func isMove2KubeAPIVersion(apiVersion string) bool {
+	groupVersion, err := schema.ParseGroupVersion(apiVersion)
+	return err == nil && apiVersion != "" && groupVersion.Group == types.SchemeGroupVersion.Group
+}
+
+// newYamlValidationIssue returns an issue at the position of the deepest node found along the keys
+func newYamlValidationIssue(path string, root *yaml.Node, message string, keys ...string) ValidationIssue {
+	line, column := transformertypes.GetYamlNodePosition(root, keys)
+	return ValidationIssue{Path: path, Line: line, Column: column, Message: message}
+}
+
+// getSchemaViolationIssues converts the schema violations to issues
+func getSchemaViolationIssues(path string, violations []transformertypes.SchemaViolation) []ValidationIssue {
+	issues := []ValidationIssue{}
+	for _, violation := range violations {
+		field := violation.Field
+		if field == "" {
+			field = "(root)"
+		}
+		issues = append(issues, ValidationIssue{Path: path, Line: violation.Line, Column: violation.Column, Message: field + ": " + violation.Message})
+	}
+	return issues
+}
+
+// getYamlErrorIssues converts the errors returned by the yaml parser to issues, one for each type error
+func getYamlErrorIssues(path string, err error) []ValidationIssue {
+	messages := []string{err.Error()}
+	if typeErr, ok := err.(*yaml.TypeError); ok {
+		messages = typeErr.Errors
+	}
+	issues := []ValidationIssue{}
+	for _, message := range messages {
+		issue := ValidationIssue{Path: path, Message: message}
+		if matches := yamlErrorLineRegex.FindStringSubmatch(message); matches != nil {
+			issue.Line, _ = strconv.Atoi(matches[1])
+			issue.Message = matches[2]
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// getPathRelativeToFile returns the path relative to the directory of the file if it is not absolute
+func getPathRelativeToFile(filePath, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(filePath), path)
+}
+
+// sortValidationIssues orders the issues by the file and the position
+func sortValidationIssues(issues []ValidationIssue) []ValidationIssue {
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Path != issues[j].Path {
+			return issues[i].Path < issues[j].Path
+		}
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Column < issues[j].Column
+	})
+	return issues
+}
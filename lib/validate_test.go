@@ -0,0 +1,106 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+)
+
+func writeValidationTestFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), common.DefaultDirectoryPermission); err != nil {
+		t.Fatalf("failed to create the directory for %s . Error: %q", path, err)
+	}
+	if err := os.WriteFile(path, []byte(data), common.DefaultFilePermission); err != nil {
+		t.Fatalf("failed to write the file %s . Error: %q", path, err)
+	}
+}
+
+func TestValidatePlanFile(t *testing.T) {
+	planPath := filepath.Join(t.TempDir(), "m2k.plan")
+	writeValidationTestFile(t, planPath, `apiVersion: move2kube.konveyor.io/v1alpha1
+kind: Planner
+metadata:
+  name: myproject
+spec:
+  services:
+    api:
+      - transformerName: Golang-Dockerfile
+      - paths:
+          ServiceDirPath:
+            - api
+  transformers:
+    Golang-Dockerfile: m2kassets/built-in/transformers/dockerfilegenerator/golang/transformer.yaml
+`)
+	want := []ValidationIssue{
+		{Path: planPath, Line: 2, Column: 1, Message: `the kind "Planner" is invalid. Expected: Plan`},
+		{Path: planPath, Line: 9, Column: 9, Message: "the artifact 1 of the service api does not have a transformerName"},
+	}
+	if issues := ValidatePlanFile(planPath); !cmp.Equal(issues, want) {
+		t.Fatalf("the issues are incorrect. Differences:\n%s", cmp.Diff(want, issues))
+	}
+
+	writeValidationTestFile(t, planPath, "apiVersion: move2kube.konveyor.io/v1alpha1\nkind: Plan\nspec:\n  services: [\n")
+	if issues := ValidatePlanFile(planPath); len(issues) != 1 || issues[0].Line == 0 {
+		t.Fatalf("expected a single issue with the line of the syntax error. Actual: %+v", issues)
+	}
+}
+
+func TestValidateCustomizations(t *testing.T) {
+	customizationsDir := t.TempDir()
+	transformerPath := filepath.Join(customizationsDir, "mytransformer", "transformer.yaml")
+	writeValidationTestFile(t, transformerPath, `apiVersion: move2kube.konveyor.io/v1alpha1
+kind: Transformer
+metadata:
+  name: MyTransformer
+spec:
+  class: Starlark
+  config:
+    starFile: missing.star
+`)
+	starPath := filepath.Join(customizationsDir, "myparameterizer", "params.star")
+	writeValidationTestFile(t, starPath, "def parameterize(resource, envs):\n    return [\n")
+	parameterizerPath := filepath.Join(customizationsDir, "myparameterizer", "parameterizers.yaml")
+	writeValidationTestFile(t, parameterizerPath, `apiVersion: move2kube.konveyor.io/v1alpha1
+kind: Parameterizer
+metadata:
+  name: myparameterizers
+spec:
+  starFile: params.star
+  parameterizers:
+    - target: "spec.replicas"
+      regex: "(["
+    - template: "${common.replicas}"
+`)
+	writeValidationTestFile(t, filepath.Join(customizationsDir, "deployment.yaml"), "apiVersion: apps/v1\nkind: Deployment\n")
+
+	issues := ValidateCustomizations(customizationsDir)
+	want := []ValidationIssue{
+		{Path: parameterizerPath, Line: 9, Column: 7, Message: "the regex ([ is invalid. Error: error parsing regexp: missing closing ]: `[`"},
+		{Path: parameterizerPath, Line: 10, Column: 7, Message: "the parameterizer 1 does not have a target"},
+		{Path: starPath, Line: 3, Column: 1, Message: "got outdent, want primary expression"},
+		{Path: transformerPath, Line: 8, Column: 5, Message: "the Starlark file " + filepath.Join(filepath.Dir(transformerPath), "missing.star") + " does not exist"},
+	}
+	if !cmp.Equal(issues, want) {
+		t.Fatalf("the issues are incorrect. Differences:\n%s", cmp.Diff(want, issues))
+	}
+}
@@ -0,0 +1,170 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/transformer"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	"github.com/sirupsen/logrus"
+)
+
+// watchDebounce is how long to wait after the last change before reloading, since editors write files in several steps
+const watchDebounce = 500 * time.Millisecond
+
+// WatchCustomizations watches the customizations directory of the plan until the context is done.
+// When files change, the customizations are copied to the assets again and the transformers in the changed directories
+// are reloaded. If any of them was used by the last transformation, the services are transformed again with the
+// reloaded transformers, without initializing the other transformers again.
+func WatchCustomizations(ctx context.Context, plan plantypes.Plan, preExistingPlan bool, outputPath string) error {
+	if plan.Spec.CustomizationsDir == "" {
+		return fmt.Errorf("the plan does not have a customizations directory to watch")
+	}
+	customizationsPath, err := filepath.Abs(plan.Spec.CustomizationsDir)
+	if err != nil {
+		return fmt.Errorf("failed to make the customizations directory path %s absolute. Error: %w", plan.Spec.CustomizationsDir, err)
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create the file watcher. Error: %w", err)
+	}
+	defer watcher.Close()
+	if err := addWatchedDirs(watcher, customizationsPath); err != nil {
+		return fmt.Errorf("failed to watch the customizations directory %s . Error: %w", customizationsPath, err)
+	}
+	logrus.Infof("Watching the customizations at %s for changes. Press Ctrl+C to stop.", customizationsPath)
+	changedPaths := []string{}
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&fsnotify.Create != 0 {
+				if fi, err := os.Stat(event.Name); err == nil && fi.IsDir() {
+					if err := addWatchedDirs(watcher, event.Name); err != nil {
+						logrus.Warnf("failed to watch the new directory %s . Error: %q", event.Name, err)
+					}
+				}
+			}
+			changedPaths = common.AppendIfNotPresent(changedPaths, event.Name)
+			debounce = time.After(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			logrus.Warnf("failed to watch the customizations. Error: %q", err)
+		case <-debounce:
+			transformChangedCustomizations(ctx, plan, preExistingPlan, customizationsPath, changedPaths, outputPath)
+			changedPaths = []string{}
+			debounce = nil
+		}
+	}
+}
+
+// addWatchedDirs watches the directory and all the directories inside it
+func addWatchedDirs(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		return watcher.Add(path)
+	})
+}
+
+// transformChangedCustomizations reloads the transformers of the changed customizations and transforms again if they were used
+func transformChangedCustomizations(ctx context.Context, plan plantypes.Plan, preExistingPlan bool, customizationsPath string, changedPaths []string, outputPath string) {
+	logrus.Debugf("the customizations changed: %+v", changedPaths)
+	messages := getMessageCollector()
+	if err := CopyCustomizationsAssetsData(customizationsPath); err != nil {
+		logrus.Errorf("failed to copy the changed customizations. Error: %q", err)
+		return
+	}
+	assetsPath, err := filepath.Abs(common.AssetsPath)
+	if err != nil {
+		logrus.Errorf("failed to make the assets path %s absolute. Error: %q", common.AssetsPath, err)
+		return
+	}
+	customizationsAssetsPath := filepath.Join(assetsPath, common.CustomAssetsDir)
+	changedAssetPaths := []string{}
+	for _, changedPath := range changedPaths {
+		relPath, err := filepath.Rel(customizationsPath, changedPath)
+		if err != nil {
+			logrus.Debugf("failed to make the changed path %s relative to the customizations directory %s . Error: %q", changedPath, customizationsPath, err)
+			continue
+		}
+		changedAssetPaths = append(changedAssetPaths, filepath.Join(customizationsAssetsPath, relPath))
+	}
+	reloaded := transformer.ReloadTransformers(ctx, changedAssetPaths, plan.Spec.SourceDir, outputPath, plan.Name, preExistingPlan)
+	if len(reloaded) == 0 {
+		logrus.Infof("None of the initialized transformers changed. New transformers are only used after planning again.")
+		return
+	}
+	affected := getAffectedTransformerNames(reloaded, getUsedTransformerNames(plan))
+	if len(affected) == 0 {
+		logrus.Infof("The reloaded transformers %+v were not used in the last transformation.", reloaded)
+		return
+	}
+	logrus.Infof("Transforming again since the transformers %+v changed", affected)
+	if err := transformServices(ctx, plan, outputPath, messages); err != nil {
+		logrus.Errorf("failed to transform again. Error: %q", err)
+		return
+	}
+	logrus.Infof("Transformed target artifacts can be found at [%s].", outputPath)
+}
+
+// getAffectedTransformerNames returns the reloaded transformers that were used in the last transformation
+func getAffectedTransformerNames(reloaded, used []string) []string {
+	affected := []string{}
+	for _, name := range reloaded {
+		if common.IsPresent(used, name) {
+			affected = append(affected, name)
+		}
+	}
+	return affected
+}
+
+// getUsedTransformerNames returns the names of the transformers chosen in the plan and those that ran in the last transformation
+func getUsedTransformerNames(plan plantypes.Plan) []string {
+	names := []string{}
+	for _, options := range plan.Spec.Services {
+		for _, option := range options {
+			names = common.AppendIfNotPresent(names, option.TransformerName)
+		}
+	}
+	if graph := transformer.GetGraph(); graph != nil {
+		for _, vertex := range graph.Vertices {
+			if vertex.Transformer != nil {
+				names = common.AppendIfNotPresent(names, vertex.Transformer.Name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,50 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+func TestGetUsedTransformerNames(t *testing.T) {
+	plan := plantypes.NewPlan()
+	plan.Spec.Services = map[string][]plantypes.PlanArtifact{
+		"web": {{TransformerName: "Golang"}, {TransformerName: "Dockerfile"}},
+		"api": {{TransformerName: "Dockerfile"}},
+	}
+	want := []string{"Dockerfile", "Golang"}
+	names := getUsedTransformerNames(plan)
+	if !cmp.Equal(names, want, cmpopts.SortSlices(func(x, y string) bool { return x < y })) {
+		t.Fatalf("the used transformers are incorrect. Differences:\n%s", cmp.Diff(want, names))
+	}
+}
+
+func TestGetAffectedTransformerNames(t *testing.T) {
+	reloaded := []string{"Golang", "Custom", "Kubernetes"}
+	used := []string{"Kubernetes", "Golang", "Dockerfile"}
+	want := []string{"Golang", "Kubernetes"}
+	if affected := getAffectedTransformerNames(reloaded, used); !cmp.Equal(affected, want) {
+		t.Fatalf("the affected transformers are incorrect. Differences:\n%s", cmp.Diff(want, affected))
+	}
+	if affected := getAffectedTransformerNames(reloaded, nil); len(affected) != 0 {
+		t.Fatalf("expected no affected transformers when none were used. Actual: %+v", affected)
+	}
+}
@@ -17,8 +17,6 @@
 package main
 
 import (
-	"os"
-
 	"github.com/konveyor/move2kube/assets"
 	"github.com/konveyor/move2kube/cmd"
 	"github.com/konveyor/move2kube/common"
@@ -39,7 +37,12 @@ func main() {
 	}
 	common.TempPath = tempPath
 	common.AssetsPath = assetsPath
-	defer os.RemoveAll(tempPath)
+	if err := common.WriteWorkspaceManifest(tempPath); err != nil {
+		logrus.Debugf("failed to write the run manifest to the temporary directory %s . Error: %q", tempPath, err)
+	}
+	// remove the temp directory even when exiting using logrus.Fatal
+	logrus.RegisterExitHandler(common.RemoveTempWorkspace)
+	defer common.RemoveTempWorkspace()
 	if err := rootCmd.Execute(); err != nil {
 		logrus.Fatalf("Error: %q", err)
 	}
@@ -0,0 +1,116 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+)
+
+const (
+	// ImageRegistryCategory contains the questions about the registry the images are pushed to
+	ImageRegistryCategory = "imageregistry"
+	// IngressCategory contains the questions about the ingress of the target cluster
+	IngressCategory = "ingress"
+	// NetworkingCategory contains the questions about the ports, the ingress and the service mesh
+	NetworkingCategory = "networking"
+	// StorageCategory contains the questions about the persistent volumes
+	StorageCategory = "storage"
+	// CICDCategory contains the questions about the CI/CD pipelines and the signing of the images
+	CICDCategory = "cicd"
+)
+
+// categoryRule tags the questions whose ids start with the prefix or contain the sub key segment with the category
+type categoryRule struct {
+	category string
+	prefix   string
+	segment  string
+}
+
+var (
+	categoryRules = []categoryRule{
+		{category: ImageRegistryCategory, prefix: common.ConfigImageRegistryKey},
+		{category: ImageRegistryCategory, prefix: common.ConfigImageTagSourceKey},
+		{category: IngressCategory, segment: common.IngressKey},
+		{category: NetworkingCategory, segment: common.IngressKey},
+		{category: NetworkingCategory, segment: common.ConfigPortsForServiceKeySegment},
+		{category: NetworkingCategory, segment: common.ConfigPortForServiceKeySegment},
+		{category: NetworkingCategory, segment: "servicetype"},
+		{category: NetworkingCategory, segment: "urlpath"},
+		{category: NetworkingCategory, segment: "newurlpath"},
+		{category: NetworkingCategory, prefix: common.ConfigServicesExposeKey},
+		{category: NetworkingCategory, prefix: common.ConfigTargetServiceMeshKey},
+		{category: StorageCategory, prefix: common.ConfigStoragesKey},
+		{category: CICDCategory, prefix: common.ConfigRepoKey},
+		{category: CICDCategory, prefix: common.ConfigSigningKey},
+	}
+	// enabledCategories are the only categories whose questions are asked, all of them are asked if it is empty
+	enabledCategories []string
+	// disabledCategories are the categories whose questions are answered using the defaults
+	disabledCategories []string
+)
+
+// SetCategories sets the categories of questions that are asked. If some categories are enabled only their questions
+// are asked. The questions in the disabled categories and the questions that are not asked are answered using the
+// config files and the defaults instead of the interactive engines.
+func SetCategories(enabled, disabled []string) {
+	enabledCategories = enabled
+	disabledCategories = disabled
+}
+
+// GetCategories returns the categories of the problem. The built-in questions are categorized using their ids
+// and the questions of the custom transformers can set their category.
+func GetCategories(prob qatypes.Problem) []string {
+	categories := []string{}
+	if prob.Category != "" {
+		categories = append(categories, prob.Category)
+	}
+	for _, rule := range categoryRules {
+		if common.IsPresent(categories, rule.category) {
+			continue
+		}
+		if (rule.prefix != "" && (prob.ID == rule.prefix || strings.HasPrefix(prob.ID, rule.prefix+common.Delim))) ||
+			(rule.segment != "" && strings.Contains(prob.ID+common.Delim, common.Delim+rule.segment+common.Delim)) {
+			categories = append(categories, rule.category)
+		}
+	}
+	return categories
+}
+
+// isCategoryEnabled returns true if the problem should be asked using the interactive engines
+func isCategoryEnabled(prob qatypes.Problem) bool {
+	if len(enabledCategories) == 0 && len(disabledCategories) == 0 {
+		return true
+	}
+	categories := GetCategories(prob)
+	for _, category := range categories {
+		if common.IsPresent(disabledCategories, category) {
+			return false
+		}
+	}
+	if len(enabledCategories) == 0 {
+		return true
+	}
+	for _, category := range categories {
+		if common.IsPresent(enabledCategories, category) {
+			return true
+		}
+	}
+	return false
+}
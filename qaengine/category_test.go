@@ -0,0 +1,80 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+)
+
+func TestGetCategories(t *testing.T) {
+	testcases := []struct {
+		id       string
+		category string
+		want     []string
+	}{
+		{id: "move2kube.target.imageregistry.url", want: []string{ImageRegistryCategory}},
+		{id: `move2kube.target."default".ingress.host`, want: []string{IngressCategory, NetworkingCategory}},
+		{id: `move2kube.services."api".ports`, want: []string{NetworkingCategory}},
+		{id: `move2kube.storages."data".size`, want: []string{StorageCategory}},
+		{id: "move2kube.repo.keys.pub.load", want: []string{CICDCategory}},
+		{id: "move2kube.target.imagetagsource", want: []string{ImageRegistryCategory}},
+		{id: "move2kube.minreplicas", want: []string{}},
+		{id: "move2kube.custom.queue", category: StorageCategory, want: []string{StorageCategory}},
+	}
+	for _, tc := range testcases {
+		categories := GetCategories(qatypes.Problem{ID: tc.id, Category: tc.category})
+		if !cmp.Equal(categories, tc.want) {
+			t.Errorf("wrong categories for the question %s . Differences:\n%s", tc.id, cmp.Diff(tc.want, categories))
+		}
+	}
+}
+
+func TestFetchAnswerCategories(t *testing.T) {
+	b := &blockingEngine{release: make(chan string, 1)}
+	engines = []Engine{b}
+	SetCategories([]string{NetworkingCategory}, []string{IngressCategory})
+	defer func() {
+		answeredProblems, engines = nil, nil
+		SetCategories(nil, nil)
+	}()
+
+	registryProb, err := qatypes.NewInputProblem("move2kube.target.imageregistry.url", "Enter the registry url", nil, "quay.io", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved, err := FetchAnswer(registryProb); err != nil || resolved.Answer != "quay.io" {
+		t.Fatalf("expected the default answer for the question that is not enabled. Actual: %+v Error: %v", resolved.Answer, err)
+	}
+	ingressProb, err := qatypes.NewInputProblem(`move2kube.target."default".ingress.host`, "Enter the ingress host", nil, "example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved, err := FetchAnswer(ingressProb); err != nil || resolved.Answer != "example.com" {
+		t.Fatalf("expected the default answer for the question in a disabled category. Actual: %+v Error: %v", resolved.Answer, err)
+	}
+	portsProb, err := qatypes.NewInputProblem(`move2kube.services."api".port`, "Enter the port", nil, "8080", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.release <- "9090"
+	if resolved, err := FetchAnswer(portsProb); err != nil || resolved.Answer != "9090" {
+		t.Fatalf("expected the question in an enabled category to be asked. Actual: %+v Error: %v", resolved.Answer, err)
+	}
+}
@@ -22,12 +22,15 @@ import (
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/i18n"
 	qatypes "github.com/konveyor/move2kube/types/qaengine"
 	"github.com/sirupsen/logrus"
 )
 
 // CliEngine handles the CLI based qa
 type CliEngine struct {
+	// lastGroup is the group of the last question, used to print the section headers only when the group changes
+	lastGroup []string
 }
 
 // NewCliEngine creates a new instance of cli engine
@@ -51,6 +54,13 @@ func (c *CliEngine) FetchAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
 		logrus.Errorf("the QA problem object is invalid. Error: %q", err)
 		return prob, err
 	}
+	for _, header := range getGroupHeaders(c.lastGroup, prob.Group) {
+		fmt.Fprintln(plainOutput, header)
+	}
+	c.lastGroup = prob.Group
+	if plainMode && prob.Type != qatypes.PasswordSolutionFormType {
+		return fetchPlainAnswer(prob)
+	}
 	switch prob.Type {
 	case qatypes.SelectSolutionFormType:
 		return c.fetchSelectAnswer(prob)
@@ -64,6 +74,10 @@ func (c *CliEngine) FetchAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
 		return c.fetchMultilineInputAnswer(prob)
 	case qatypes.PasswordSolutionFormType:
 		return c.fetchPasswordAnswer(prob)
+	case qatypes.FilePathSolutionFormType:
+		return c.fetchFilePathAnswer(prob)
+	case qatypes.TableSolutionFormType:
+		return c.fetchTableAnswer(prob)
 	}
 	logrus.Fatalf("unknown QA problem type: %+v", prob)
 	return prob, nil
@@ -83,7 +97,7 @@ func (*CliEngine) fetchSelectAnswer(prob qatypes.Problem) (qatypes.Problem, erro
 	}
 	question := &survey.Question{
 		Prompt:   prompt,
-		Validate: prob.Validator,
+		Validate: prob.Validate,
 	}
 	if err := survey.Ask([]*survey.Question{question}, &ans); err != nil {
 		logrus.Fatalf("Error while asking a question : %s", err)
@@ -102,7 +116,7 @@ func (*CliEngine) fetchMultiSelectAnswer(prob qatypes.Problem) (qatypes.Problem,
 	}
 	question := &survey.Question{
 		Prompt:   prompt,
-		Validate: prob.Validator,
+		Validate: prob.Validate,
 	}
 	tickIcon := func(icons *survey.IconSet) { icons.MarkedOption.Text = "[\u2713]" }
 	if err := survey.Ask([]*survey.Question{question}, &ans, survey.WithIcons(tickIcon)); err != nil {
@@ -152,7 +166,7 @@ func (*CliEngine) fetchConfirmAnswer(prob qatypes.Problem) (qatypes.Problem, err
 	}
 	question := &survey.Question{
 		Prompt:   prompt,
-		Validate: prob.Validator,
+		Validate: prob.Validate,
 	}
 	if err := survey.Ask([]*survey.Question{question}, &ans); err != nil {
 		logrus.Fatalf("Error while asking a question : %s", err)
@@ -172,7 +186,7 @@ func (*CliEngine) fetchInputAnswer(prob qatypes.Problem) (qatypes.Problem, error
 	}
 	question := &survey.Question{
 		Prompt:   prompt,
-		Validate: prob.Validator,
+		Validate: prob.Validate,
 	}
 	if err := survey.Ask([]*survey.Question{question}, &ans); err != nil {
 		logrus.Fatalf("Error while asking a question : %s", err)
@@ -192,7 +206,7 @@ func (*CliEngine) fetchMultilineInputAnswer(prob qatypes.Problem) (qatypes.Probl
 	}
 	question := &survey.Question{
 		Prompt:   prompt,
-		Validate: prob.Validator,
+		Validate: prob.Validate,
 	}
 	if err := survey.Ask([]*survey.Question{question}, &ans); err != nil {
 		logrus.Fatalf("Error while asking a question : %s", err)
@@ -208,7 +222,34 @@ func (*CliEngine) fetchPasswordAnswer(prob qatypes.Problem) (qatypes.Problem, er
 	}
 	question := &survey.Question{
 		Prompt:   prompt,
-		Validate: prob.Validator,
+		Validate: prob.Validate,
+	}
+	if err := survey.Ask([]*survey.Question{question}, &ans); err != nil {
+		logrus.Fatalf("Error while asking a question : %s", err)
+	}
+	prob.Answer = ans
+	return prob, nil
+}
+
+func (*CliEngine) fetchFilePathAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
+	var ans, def string
+	if prob.Default != nil {
+		def = prob.Default.(string)
+	}
+	options := qatypes.FilePathOptions{}
+	if prob.FilePathOptions != nil {
+		options = *prob.FilePathOptions
+	}
+	prompt := &survey.Input{
+		Message: getQAMessage(prob),
+		Default: def,
+		Suggest: func(toComplete string) []string {
+			return getFilePathSuggestions(toComplete, options)
+		},
+	}
+	question := &survey.Question{
+		Prompt:   prompt,
+		Validate: prob.Validate,
 	}
 	if err := survey.Ask([]*survey.Question{question}, &ans); err != nil {
 		logrus.Fatalf("Error while asking a question : %s", err)
@@ -217,12 +258,60 @@ func (*CliEngine) fetchPasswordAnswer(prob qatypes.Problem) (qatypes.Problem, er
 	return prob, nil
 }
 
+// fetchTableAnswer asks for all the rows in one prompt, one line of comma separated values per row
+func (*CliEngine) fetchTableAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
+	var ans string
+	options := *prob.TableOptions
+	def := ""
+	if prob.Default != nil {
+		defaultRows, err := options.NormalizeRows(prob.Default)
+		if err != nil {
+			return prob, err
+		}
+		def = strings.Join(options.FormatRows(defaultRows), "\n")
+	}
+	prompt := &survey.Multiline{
+		Message: getQAMessage(prob) + getTableMessage(options),
+		Default: def,
+	}
+	question := &survey.Question{
+		Prompt: prompt,
+		Validate: func(ans interface{}) error {
+			ansStr, _ := ans.(string)
+			rows, err := options.ParseRows(strings.Split(ansStr, "\n"))
+			if err != nil {
+				return err
+			}
+			return prob.Validate(rows)
+		},
+	}
+	if err := survey.Ask([]*survey.Question{question}, &ans); err != nil {
+		logrus.Fatalf("Error while asking a question : %s", err)
+	}
+	rows, err := options.ParseRows(strings.Split(ans, "\n"))
+	if err != nil {
+		return prob, err
+	}
+	prob.Answer = rows
+	return prob, nil
+}
+
+// getTableMessage describes the columns whose values are expected on each line of a table answer
+func getTableMessage(options qatypes.TableOptions) string {
+	return i18n.T("Enter one row per line with the comma separated values of: %s", strings.Join(options.GetColumnNames(), ", ")) + "\n"
+}
+
 func getQAMessage(prob qatypes.Problem) string {
+	desc := i18n.T(prob.Desc)
 	if prob.Desc == "" {
-		prob.Desc = "Default description for question with id: " + prob.ID
+		desc = i18n.T("Default description for question with id: %s", prob.ID)
 	}
 	if len(prob.Hints) == 0 {
-		return fmt.Sprintf("%s\nID: %s\n", prob.Desc, prob.ID)
+		return fmt.Sprintf("%s\n%s %s\n", desc, i18n.T("ID:"), prob.ID)
+	}
+	hints := []string{}
+	for _, hint := range prob.Hints {
+		hints = append(hints, i18n.T(hint))
 	}
-	return fmt.Sprintf("%s\nID: %s\nHints:\n- %s\n\n", prob.Desc, prob.ID, strings.Join(prob.Hints, "\n- "))
+	return fmt.Sprintf("%s\n%s %s\n%s\n- %s\n\n", desc, i18n.T("ID:"), prob.ID, i18n.T("Hints:"), strings.Join(hints, "\n- "))
 }
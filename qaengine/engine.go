@@ -19,8 +19,14 @@ package qaengine
 import (
 	"fmt"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/eventbus"
+	"github.com/konveyor/move2kube/common/i18n"
 	qatypes "github.com/konveyor/move2kube/types/qaengine"
 	"github.com/sirupsen/logrus"
 )
@@ -32,19 +38,47 @@ type Engine interface {
 	FetchAnswer(prob qatypes.Problem) (ans qatypes.Problem, err error)
 }
 
+// fetchResult is the answer fetched by an engine in the background
+type fetchResult struct {
+	prob qatypes.Problem
+	err  error
+}
+
+// SecretEngine is implemented by the engines whose answers must not be written to the config and the cache
+type SecretEngine interface {
+	IsSecretEngine() bool
+}
+
 var (
 	engines       []Engine
 	writeStores   []qatypes.Store
 	defaultEngine = NewDefaultEngine()
+	stallTimeout  time.Duration
+	stallHandler  func(qatypes.Problem)
+	// qaTimeout is how long an interactive engine can take to answer before the default answer is used, zero waits forever
+	qaTimeout time.Duration
+	// pendingFetches are the answers an interactive engine is still fetching for the questions that timed out
+	pendingFetches      = map[Engine]chan fetchResult{}
+	pendingFetchesMutex sync.Mutex
+	// answeredProblems are the problems answered so far, in the order they were asked
+	answeredProblems      []qatypes.Problem
+	answeredProblemsMutex sync.Mutex
+	// fetchAnswerMutex makes the transformers running in parallel ask their questions one at a time
+	fetchAnswerMutex sync.Mutex
+	// session records the questions and the answers, it is nil if the session is not being recorded
+	session *qatypes.Session
 )
 
-// StartEngine starts the QA Engines
-func StartEngine(qaskip bool, qaport int, qadisablecli bool) {
+// StartEngine starts the QA Engines. If the cli is disabled the questions are served
+// using the gRPC QA service if qagrpc is true and using the HTTP REST service otherwise.
+func StartEngine(qaskip bool, qaport int, qadisablecli bool, qagrpc bool) {
 	var e Engine
 	if qaskip {
 		e = NewDefaultEngine()
 	} else if !qadisablecli {
 		e = NewCliEngine()
+	} else if qagrpc {
+		e = NewGRPCEngine(qaport)
 	} else {
 		e = NewHTTPRESTEngine(qaport)
 	}
@@ -91,8 +125,25 @@ func SetupWriteCacheFile(writeCachePath string, persistPasswords bool) {
 	AddCaches(writeCachePath)
 }
 
-// SetupConfigFile adds config responders - should be called only once
-func SetupConfigFile(writeConfigFile string, configStrings, configFiles, presets []string, persistPasswords bool) {
+// SetupSessionFile records every question along with its default and answer in the session file
+func SetupSessionFile(sessionPath string) {
+	session = qatypes.NewSession(sessionPath)
+	if err := session.Write(); err != nil {
+		logrus.Errorf("Failed to write the session file at path %s . Error: %q", sessionPath, err)
+	}
+}
+
+// SetupReplaySessionFile adds the responder that answers the questions using the answers recorded in the session file
+func SetupReplaySessionFile(sessionPath string) {
+	e := &StoreEngine{store: qatypes.NewSession(sessionPath)}
+	if err := AddEngineHighestPriority(e); err != nil {
+		logrus.Errorf("Ignoring engine %T due to error : %s", e, err)
+	}
+}
+
+// SetupConfigFile adds config responders - should be called only once.
+// The answers in the given profile override the shared answers in each config file.
+func SetupConfigFile(writeConfigFile string, configStrings, configFiles, presets []string, profile string, persistPasswords bool) {
 	presetPaths := []string{}
 	for _, preset := range presets {
 		presetPath := filepath.Join(common.AssetsPath, "built-in", "presets", preset+".yaml")
@@ -100,6 +151,7 @@ func SetupConfigFile(writeConfigFile string, configStrings, configFiles, presets
 	}
 	configFiles = append(presetPaths, configFiles...)
 	writeConfig := qatypes.NewConfig(writeConfigFile, configStrings, configFiles, persistPasswords)
+	writeConfig.Profile = profile
 	if writeConfigFile != "" {
 		writeStores = append(writeStores, writeConfig)
 	}
@@ -109,6 +161,24 @@ func SetupConfigFile(writeConfigFile string, configStrings, configFiles, presets
 	}
 }
 
+// SetupVaultEngine adds the responder that answers the password questions using the secrets in HashiCorp Vault.
+// The paths of the secrets are rendered from the template and the address and the token are read from VAULT_ADDR and VAULT_TOKEN.
+func SetupVaultEngine(pathTemplate string) {
+	e := NewVaultEngine(pathTemplate)
+	if err := AddEngineHighestPriority(e); err != nil {
+		logrus.Errorf("Ignoring engine %T due to error : %s", e, err)
+	}
+}
+
+// SetupEnvStore adds the responder that answers using the environment variables like M2K_QA_TARGET_IMAGEREGISTRY_URL.
+// It has the highest priority so that the environment can override the answers in the config and the cache.
+func SetupEnvStore() {
+	e := &StoreEngine{store: qatypes.NewEnvStore()}
+	if err := AddEngineHighestPriority(e); err != nil {
+		logrus.Errorf("Ignoring engine %T due to error : %s", e, err)
+	}
+}
+
 // FetchAnswer fetches the answer for the question
 func FetchAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
 	logrus.Debugf("Fetching answer for the problem: %#v", prob)
@@ -116,12 +186,32 @@ func FetchAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
 		logrus.Debugf("Problem already solved.")
 		return prob, nil
 	}
+	fetchAnswerMutex.Lock()
+	defer fetchAnswerMutex.Unlock()
+	if prob.Group == nil {
+		prob.Group = getCurrentGroup()
+	}
+	if prob.When != "" {
+		ask, err := qatypes.EvaluateWhen(prob.When, getAnswerForWhen)
+		if err != nil {
+			logrus.Warnf("Asking the question %s since its condition could not be evaluated. Error: %q", prob.ID, err)
+		} else if !ask {
+			logrus.Debugf("Skipping the question %s since its condition '%s' is false. Using the default answer.", prob.ID, prob.When)
+			return defaultEngine.FetchAnswer(prob)
+		}
+	}
+	eventbus.Publish(eventbus.QuestionRaisedEvent{Problem: prob})
 	var err error
-	for _, e := range engines {
+	secret := false
+	for _, e := range getEngines() {
 		if prob.Desc == "" && e.IsInteractiveEngine() {
 			return defaultEngine.FetchAnswer(prob)
 		}
-		prob, err = e.FetchAnswer(prob)
+		if e.IsInteractiveEngine() && !isCategoryEnabled(prob) {
+			logrus.Debugf("Using the default answer for the question %s since its categories %+v are not enabled", prob.ID, GetCategories(prob))
+			e = defaultEngine
+		}
+		prob, err = fetchAnswerFromEngine(e, prob)
 		if err != nil {
 			if _, ok := err.(*qatypes.ValidationError); ok {
 				logrus.Errorf("Error while fetching answer using engine %T Error: %q", e, err)
@@ -131,6 +221,9 @@ func FetchAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
 			continue
 		}
 		if prob.Answer != nil {
+			if secretEngine, ok := e.(SecretEngine); ok {
+				secret = secretEngine.IsSecretEngine()
+			}
 			prob = changeSelectToInputForOther(prob)
 			break
 		}
@@ -145,7 +238,7 @@ func FetchAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
 			return prob, fmt.Errorf("failed to fetch the answer for problem\n%+v\nError: %q", prob, err)
 		}
 		for err != nil || prob.Answer == nil {
-			prob, err = lastEngine.FetchAnswer(prob)
+			prob, err = fetchAnswerFromEngine(lastEngine, prob)
 			if err != nil {
 				logrus.Errorf("Unable to get answer to %s Error: %q", prob.Desc, err)
 				continue
@@ -155,12 +248,172 @@ func FetchAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
 			}
 		}
 	}
-	for _, writeStore := range writeStores {
-		writeStore.AddSolution(prob)
+	if !secret {
+		for _, writeStore := range writeStores {
+			writeStore.AddSolution(prob)
+		}
+	}
+	if err == nil && prob.Answer != nil {
+		answeredProblemsMutex.Lock()
+		answeredProblems = append(answeredProblems, prob)
+		answeredProblemsMutex.Unlock()
+		answeredProb := prob
+		if answeredProb.Type == qatypes.PasswordSolutionFormType {
+			answeredProb.Answer = "********"
+		}
+		eventbus.Publish(eventbus.AnswerReceivedEvent{Problem: answeredProb})
+		if session != nil {
+			session.AddSolution(answeredProb)
+		}
 	}
 	return prob, err
 }
 
+// getAnswerForWhen returns the answer to the latest problem with the key. The base key is optional.
+func getAnswerForWhen(key string) (interface{}, bool) {
+	if !strings.HasPrefix(key, common.BaseKey+common.Delim) {
+		key = common.JoinQASubKeys(common.BaseKey, key)
+	}
+	answeredProblemsMutex.Lock()
+	defer answeredProblemsMutex.Unlock()
+	for i := len(answeredProblems) - 1; i >= 0; i-- {
+		if answeredProblems[i].ID == key {
+			return answeredProblems[i].Answer, true
+		}
+	}
+	return nil, false
+}
+
+// localizeProblem translates the description, the hints and the group titles of the problem for the engines that serve
+// the problems to other programs. The id and the options are not translated since they are used in the answers.
+func localizeProblem(prob qatypes.Problem) qatypes.Problem {
+	prob.Desc = i18n.T(prob.Desc)
+	if prob.Hints != nil {
+		hints := []string{}
+		for _, hint := range prob.Hints {
+			hints = append(hints, i18n.T(hint))
+		}
+		prob.Hints = hints
+	}
+	if prob.Group != nil {
+		group := []string{}
+		for _, title := range prob.Group {
+			group = append(group, i18n.T(title))
+		}
+		prob.Group = group
+	}
+	return prob
+}
+
+// GetAnsweredProblems returns the problems answered so far. The answers to password problems are masked.
+func GetAnsweredProblems() []qatypes.Problem {
+	answeredProblemsMutex.Lock()
+	defer answeredProblemsMutex.Unlock()
+	problems := []qatypes.Problem{}
+	for _, prob := range answeredProblems {
+		if prob.Type == qatypes.PasswordSolutionFormType {
+			prob.Answer = "********"
+		}
+		problems = append(problems, prob)
+	}
+	return problems
+}
+
+// SetStallHandler sets a function that is called when an interactive engine does not answer a question within the timeout
+func SetStallHandler(timeout time.Duration, handler func(qatypes.Problem)) {
+	stallTimeout = timeout
+	stallHandler = handler
+}
+
+// SetQATimeout sets how long an interactive engine can take to answer a question before the default answer is used.
+// The timeout of a problem overrides it. A zero timeout waits forever.
+func SetQATimeout(timeout time.Duration) {
+	qaTimeout = timeout
+}
+
+// fetchAnswerFromEngine fetches the answer using the engine, calling the stall handler if an interactive engine takes too long
+// and using the default answer if it does not answer within the timeout
+func fetchAnswerFromEngine(e Engine, prob qatypes.Problem) (qatypes.Problem, error) {
+	if !e.IsInteractiveEngine() {
+		return e.FetchAnswer(prob)
+	}
+	if stallHandler != nil && stallTimeout > 0 {
+		handler, stalledProb := stallHandler, prob
+		timer := time.AfterFunc(stallTimeout, func() { handler(stalledProb) })
+		defer timer.Stop()
+	}
+	timeout := qaTimeout
+	if prob.Timeout != "" {
+		t, err := time.ParseDuration(prob.Timeout)
+		if err != nil {
+			logrus.Errorf("Ignoring the invalid timeout %s of the question %s . Error: %q", prob.Timeout, prob.ID, err)
+		} else {
+			timeout = t
+		}
+	}
+	if timeout <= 0 {
+		return e.FetchAnswer(prob)
+	}
+	return fetchAnswerWithTimeout(e, prob, timeout)
+}
+
+// fetchAnswerWithTimeout fetches the answer using the engine and uses the default answer if the engine does not answer within the timeout.
+// The engine can't be interrupted, so it keeps waiting for the answer to the timed out question. Until that answer arrives
+// the later questions are answered with their defaults, or wait for it if they have no valid default.
+func fetchAnswerWithTimeout(e Engine, prob qatypes.Problem, timeout time.Duration) (qatypes.Problem, error) {
+	pendingFetchesMutex.Lock()
+	pending, ok := pendingFetches[e]
+	pendingFetchesMutex.Unlock()
+	if ok {
+		select {
+		case <-pending:
+		default:
+			if defProb, err := defaultEngine.FetchAnswer(prob); err == nil {
+				logrus.Warnf("Using the default answer %+v for the question %s since an earlier question is still waiting for an answer", defProb.Answer, prob.ID)
+				recordTimeout(defProb, timeout)
+				return defProb, nil
+			}
+			<-pending
+		}
+		pendingFetchesMutex.Lock()
+		delete(pendingFetches, e)
+		pendingFetchesMutex.Unlock()
+	}
+	results := make(chan fetchResult, 1)
+	go func() {
+		p, err := e.FetchAnswer(prob)
+		results <- fetchResult{prob: p, err: err}
+	}()
+	select {
+	case result := <-results:
+		return result.prob, result.err
+	case <-time.After(timeout):
+	}
+	defProb, err := defaultEngine.FetchAnswer(prob)
+	if err != nil {
+		logrus.Warnf("The question %s was not answered within %s and it has no valid default answer. Waiting for the answer. Error: %q", prob.ID, timeout, err)
+		result := <-results
+		return result.prob, result.err
+	}
+	logrus.Warnf("The question %s was not answered within %s . Using the default answer %+v", prob.ID, timeout, defProb.Answer)
+	pendingFetchesMutex.Lock()
+	pendingFetches[e] = results
+	pendingFetchesMutex.Unlock()
+	recordTimeout(defProb, timeout)
+	return defProb, nil
+}
+
+// recordTimeout records in the stores that support it that the problem was answered with the default after the timeout
+func recordTimeout(prob qatypes.Problem, timeout time.Duration) {
+	for _, writeStore := range writeStores {
+		if timeoutStore, ok := writeStore.(qatypes.TimeoutStore); ok {
+			if err := timeoutStore.AddTimeout(prob, timeout); err != nil {
+				logrus.Errorf("Failed to record the timeout of the question %s . Error: %q", prob.ID, err)
+			}
+		}
+	}
+}
+
 // WriteStoresToDisk forces all the stores to write their contents out to disk
 func WriteStoresToDisk() error {
 	var err error
@@ -184,6 +437,9 @@ func changeSelectToInputForOther(prob qatypes.Problem) qatypes.Problem {
 		if err != nil {
 			logrus.Fatalf("failed to change the QA select type problem to input type problem: %+v\nError: %q", prob, err)
 		}
+		newProb.Group = prob.Group
+		newProb.ValidationRegex = prob.ValidationRegex
+		newProb.ValidationMessage = prob.ValidationMessage
 		return newProb
 	}
 	return prob
@@ -293,6 +549,41 @@ func FetchMultilineInputAnswer(probid, desc string, context []string, def string
 	return answer
 }
 
+// FetchFilePathAnswer asks a file path type question and gets a string as the answer
+func FetchFilePathAnswer(probid, desc string, context []string, def string, options qatypes.FilePathOptions, validator func(interface{}) error) string {
+	problem, err := qatypes.NewFilePathProblem(probid, desc, context, def, options, validator)
+	if err != nil {
+		logrus.Fatalf("Unable to create problem. Error: %q", err)
+	}
+	problem, err = FetchAnswer(problem)
+	if err != nil {
+		logrus.Fatalf("Unable to fetch answer. Error: %q", err)
+	}
+	answer, ok := problem.Answer.(string)
+	if !ok {
+		logrus.Fatalf("Answer is not of the correct type. Expected string. Actual value is %+v of type %T", problem.Answer, problem.Answer)
+	}
+	return answer
+}
+
+// FetchTableAnswer asks a table type question and gets a list of rows as the answer.
+// Each row maps the column names to the values of the column types.
+func FetchTableAnswer(probid, desc string, context []string, def []map[string]interface{}, options qatypes.TableOptions, validator func(interface{}) error) []map[string]interface{} {
+	problem, err := qatypes.NewTableProblem(probid, desc, context, def, options, validator)
+	if err != nil {
+		logrus.Fatalf("Unable to create problem. Error: %q", err)
+	}
+	problem, err = FetchAnswer(problem)
+	if err != nil {
+		logrus.Fatalf("Unable to fetch answer. Error: %q", err)
+	}
+	answer, ok := problem.Answer.([]map[string]interface{})
+	if !ok {
+		logrus.Fatalf("Answer is not of the correct type. Expected a list of rows. Actual value is %+v of type %T", problem.Answer, problem.Answer)
+	}
+	return answer
+}
+
 // ValidateProblem validates the problem object.
 func ValidateProblem(prob qatypes.Problem) error {
 	if prob.ID == "" {
@@ -306,6 +597,16 @@ func ValidateProblem(prob qatypes.Problem) error {
 			return fmt.Errorf("expected the hints to be an array of strings for the QA problem: %+v\nError: %q", prob, err)
 		}
 	}
+	if prob.Timeout != "" {
+		if _, err := time.ParseDuration(prob.Timeout); err != nil {
+			return fmt.Errorf("the timeout for the QA problem is invalid: %+v\nError: %q", prob, err)
+		}
+	}
+	if prob.ValidationRegex != "" {
+		if _, err := regexp.Compile(prob.ValidationRegex); err != nil {
+			return fmt.Errorf("the validation regex for the QA problem is invalid: %+v\nError: %q", prob, err)
+		}
+	}
 	switch prob.Type {
 	case qatypes.MultiSelectSolutionFormType:
 		if len(prob.Options) == 0 {
@@ -354,19 +655,31 @@ func ValidateProblem(prob qatypes.Problem) error {
 				return fmt.Errorf("expected the default to be a bool for the QA confirm problem: %+v", prob)
 			}
 		}
-	case qatypes.InputSolutionFormType, qatypes.MultilineInputSolutionFormType, qatypes.PasswordSolutionFormType:
+	case qatypes.InputSolutionFormType, qatypes.MultilineInputSolutionFormType, qatypes.PasswordSolutionFormType, qatypes.FilePathSolutionFormType:
 		if len(prob.Options) > 0 {
-			logrus.Warnf("options are not supported for the QA input/multiline/password question types: %+v", prob)
+			logrus.Warnf("options are not supported for the QA input/multiline/password/filepath question types: %+v", prob)
 		}
 		if prob.Default != nil {
 			if prob.Type == qatypes.PasswordSolutionFormType {
 				logrus.Warnf("default is not supported for the QA password question type: %+v", prob)
 			} else {
 				if _, ok := prob.Default.(string); !ok {
-					return fmt.Errorf("expected the default to be a string for the QA input/multiline problem: %+v", prob)
+					return fmt.Errorf("expected the default to be a string for the QA input/multiline/filepath problem: %+v", prob)
 				}
 			}
 		}
+	case qatypes.TableSolutionFormType:
+		if prob.TableOptions == nil {
+			return fmt.Errorf("the QA table problem has no columns specified: %+v", prob)
+		}
+		if err := prob.TableOptions.Validate(); err != nil {
+			return fmt.Errorf("the columns of the QA table problem are invalid: %+v\nError: %q", prob, err)
+		}
+		if prob.Default != nil {
+			if _, err := prob.TableOptions.NormalizeRows(prob.Default); err != nil {
+				return fmt.Errorf("the default of the QA table problem is invalid: %+v\nError: %q", prob, err)
+			}
+		}
 	default:
 		return fmt.Errorf("unknown QA problem type: %+v", prob)
 	}
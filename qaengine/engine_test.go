@@ -17,9 +17,13 @@
 package qaengine
 
 import (
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
 	"github.com/sirupsen/logrus"
 )
 
@@ -46,3 +50,128 @@ func TestEngine(t *testing.T) {
 	})
 
 }
+
+func TestGetAnsweredProblems(t *testing.T) {
+	answeredProblems = []qatypes.Problem{
+		{ID: "move2kube.target.imageregistry.url", Type: qatypes.InputSolutionFormType, Answer: "quay.io"},
+		{ID: "move2kube.target.imageregistry.quay_io.password", Type: qatypes.PasswordSolutionFormType, Answer: "secret"},
+	}
+	defer func() { answeredProblems = nil }()
+	want := []qatypes.Problem{
+		{ID: "move2kube.target.imageregistry.url", Type: qatypes.InputSolutionFormType, Answer: "quay.io"},
+		{ID: "move2kube.target.imageregistry.quay_io.password", Type: qatypes.PasswordSolutionFormType, Answer: "********"},
+	}
+	actual := GetAnsweredProblems()
+	if !cmp.Equal(actual, want, cmpopts.IgnoreFields(qatypes.Problem{}, "Validator")) {
+		t.Fatalf("the answered problems differ from the expected. Difference:\n%s", cmp.Diff(want, actual, cmpopts.IgnoreFields(qatypes.Problem{}, "Validator")))
+	}
+	if answeredProblems[1].Answer != "secret" {
+		t.Fatalf("the recorded answer of the password problem was modified")
+	}
+}
+
+func TestFetchAnswerWhen(t *testing.T) {
+	answeredProblems = []qatypes.Problem{
+		{ID: "move2kube.target.ingress.enabled", Type: qatypes.ConfirmSolutionFormType, Answer: false},
+	}
+	engines = []Engine{NewDefaultEngine()}
+	defer func() { answeredProblems, engines = nil, nil }()
+	prob, err := qatypes.NewInputProblem("move2kube.target.ingress.tls", "Enter the TLS secret name", nil, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prob.When = "target.ingress.enabled"
+	skipped, err := FetchAnswer(prob)
+	if err != nil || skipped.Answer != "" {
+		t.Fatalf("expected the question to be skipped with the default answer. Actual: %+v Error: %v", skipped.Answer, err)
+	}
+	if len(answeredProblems) != 1 {
+		t.Fatalf("expected the skipped question not to be recorded as answered. Actual: %+v", answeredProblems)
+	}
+	answeredProblems[0].Answer = true
+	if _, err := FetchAnswer(prob); err != nil || len(answeredProblems) != 2 {
+		t.Fatalf("expected the question to be asked once the condition is true. Answered: %+v Error: %v", answeredProblems, err)
+	}
+}
+
+func TestFetchAnswerValidationRegex(t *testing.T) {
+	config := qatypes.NewConfig("", []string{`move2kube.services.api.name="My_API"`}, nil, false)
+	if err := config.Load(); err != nil {
+		t.Fatalf("failed to load the config. Error: %q", err)
+	}
+	engines = []Engine{&StoreEngine{store: config}, NewDefaultEngine()}
+	defer func() { answeredProblems, engines = nil, nil }()
+	prob, err := qatypes.NewInputProblem("move2kube.services.api.name", "Enter the name of the service", nil, "api", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prob.ValidationRegex = "^[a-z0-9-]+$"
+	resolved, err := FetchAnswer(prob)
+	if err != nil || resolved.Answer != "api" {
+		t.Fatalf("expected the invalid config answer to be rejected in favour of the default. Actual: %+v Error: %v", resolved.Answer, err)
+	}
+}
+
+// blockingEngine is an interactive engine that answers only when it is released
+type blockingEngine struct {
+	release chan string
+}
+
+func (*blockingEngine) StartEngine() error { return nil }
+
+func (*blockingEngine) IsInteractiveEngine() bool { return true }
+
+func (b *blockingEngine) FetchAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
+	prob.Answer = <-b.release
+	return prob, nil
+}
+
+func TestFetchAnswerTimeout(t *testing.T) {
+	cache := qatypes.NewCache(filepath.Join(t.TempDir(), "m2kqacache.yaml"), false)
+	b := &blockingEngine{release: make(chan string, 1)}
+	engines, writeStores = []Engine{b}, []qatypes.Store{cache}
+	defer func() {
+		answeredProblems, engines, writeStores, pendingFetches = nil, nil, nil, map[Engine]chan fetchResult{}
+	}()
+
+	prob, err := qatypes.NewInputProblem("move2kube.services.api.name", "Enter the name of the service", nil, "api", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prob.Timeout = "10ms"
+	resolved, err := FetchAnswer(prob)
+	if err != nil || resolved.Answer != "api" {
+		t.Fatalf("expected the default answer after the timeout. Actual: %+v Error: %v", resolved.Answer, err)
+	}
+	if len(cache.Spec.Timeouts) != 1 || cache.Spec.Timeouts[0].ID != prob.ID || cache.Spec.Timeouts[0].Timeout != "10ms" {
+		t.Fatalf("expected the timeout to be recorded in the cache. Actual: %+v", cache.Spec.Timeouts)
+	}
+
+	t.Run("earlier question still pending", func(t *testing.T) {
+		prob, err := qatypes.NewInputProblem("move2kube.services.web.name", "Enter the name of the service", nil, "web", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prob.Timeout = time.Hour.String()
+		resolved, err := FetchAnswer(prob)
+		if err != nil || resolved.Answer != "web" {
+			t.Fatalf("expected the default answer while the earlier question is pending. Actual: %+v Error: %v", resolved.Answer, err)
+		}
+	})
+
+	t.Run("no default waits for the earlier question", func(t *testing.T) {
+		prob, err := qatypes.NewPasswordProblem("move2kube.services.db.password", "Enter the password of the database", nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prob.Timeout = time.Hour.String()
+		go func() {
+			b.release <- "late"
+			b.release <- "secret"
+		}()
+		resolved, err := FetchAnswer(prob)
+		if err != nil || resolved.Answer != "secret" {
+			t.Fatalf("expected the answer from the engine. Actual: %+v Error: %v", resolved.Answer, err)
+		}
+	})
+}
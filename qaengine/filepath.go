@@ -0,0 +1,105 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+	"github.com/sirupsen/logrus"
+)
+
+// listFilePathEntries lists the directories and the files accepted by the options in the directory, sorted by name
+func listFilePathEntries(dir string, options qatypes.FilePathOptions) ([]qatypes.FilePathEntry, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries := []qatypes.FilePathEntry{}
+	for _, dirEntry := range dirEntries {
+		isDir := dirEntry.IsDir()
+		if !isDir && dirEntry.Type()&os.ModeSymlink != 0 {
+			if fi, err := os.Stat(filepath.Join(dir, dirEntry.Name())); err == nil {
+				isDir = fi.IsDir()
+			}
+		}
+		if !isDir && (options.Directory || !options.HasExtension(dirEntry.Name())) {
+			continue
+		}
+		entries = append(entries, qatypes.FilePathEntry{Name: dirEntry.Name(), Path: filepath.Join(dir, dirEntry.Name()), IsDir: isDir})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// listFilePathDirectory lists the directory for browsing. The current directory is listed if the path is empty
+// and the directory containing the path is listed if the path is a file or doesn't exist.
+func listFilePathDirectory(path string, options qatypes.FilePathOptions) (qatypes.FilePathListing, error) {
+	if path == "" {
+		path = "."
+	}
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return qatypes.FilePathListing{}, fmt.Errorf("failed to make the path %s absolute. Error: %w", path, err)
+	}
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	entries, err := listFilePathEntries(dir, options)
+	if err != nil {
+		return qatypes.FilePathListing{}, fmt.Errorf("failed to list the directory %s . Error: %w", dir, err)
+	}
+	listing := qatypes.FilePathListing{Path: dir, Entries: entries}
+	if parent := filepath.Dir(dir); parent != dir {
+		listing.Parent = parent
+	}
+	return listing, nil
+}
+
+// getFilePathSuggestions returns the paths that complete the partially typed path.
+// The directories end with a path separator so that the completion can continue inside them.
+func getFilePathSuggestions(toComplete string, options qatypes.FilePathOptions) []string {
+	dir, prefix := filepath.Split(toComplete)
+	listDir := dir
+	if listDir == "" {
+		listDir = "."
+	}
+	entries, err := listFilePathEntries(listDir, options)
+	if err != nil {
+		logrus.Debugf("failed to list the directory %s for completing the path %s . Error: %q", listDir, toComplete, err)
+		return nil
+	}
+	suggestions := []string{}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name, prefix) {
+			continue
+		}
+		if strings.HasPrefix(entry.Name, ".") && !strings.HasPrefix(prefix, ".") {
+			continue
+		}
+		suggestion := dir + entry.Name
+		if entry.IsDir {
+			suggestion += string(os.PathSeparator)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions
+}
@@ -0,0 +1,151 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+)
+
+func newFilePathTestDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"cert.pem", "config.yaml", "key.pem", ".hidden.pem"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("test"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "certs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestGetFilePathSuggestions(t *testing.T) {
+	dir := newFilePathTestDir(t)
+	sep := string(os.PathSeparator)
+
+	testcases := []struct {
+		name       string
+		toComplete string
+		options    qatypes.FilePathOptions
+		want       []string
+	}{
+		{name: "files with the extensions and the directories", toComplete: dir + sep, options: qatypes.FilePathOptions{Extensions: []string{".pem"}}, want: []string{dir + sep + "cert.pem", dir + sep + "certs" + sep, dir + sep + "key.pem"}},
+		{name: "entries starting with the prefix", toComplete: dir + sep + "c", want: []string{dir + sep + "cert.pem", dir + sep + "certs" + sep, dir + sep + "config.yaml"}},
+		{name: "hidden entries if the prefix starts with a dot", toComplete: dir + sep + ".", want: []string{dir + sep + ".hidden.pem"}},
+		{name: "only directories", toComplete: dir + sep, options: qatypes.FilePathOptions{Directory: true}, want: []string{dir + sep + "certs" + sep}},
+		{name: "missing directory", toComplete: filepath.Join(dir, "missing", "c"), want: nil},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			suggestions := getFilePathSuggestions(testcase.toComplete, testcase.options)
+			if !cmp.Equal(suggestions, testcase.want) {
+				t.Fatalf("the suggestions are incorrect. Differences:\n%s", cmp.Diff(testcase.want, suggestions))
+			}
+		})
+	}
+}
+
+func TestFilePathOptions(t *testing.T) {
+	dir := newFilePathTestDir(t)
+
+	testcases := []struct {
+		name    string
+		path    string
+		options qatypes.FilePathOptions
+		valid   bool
+	}{
+		{name: "empty path", path: "", options: qatypes.FilePathOptions{MustExist: true}, valid: true},
+		{name: "existing file with the extension", path: filepath.Join(dir, "cert.pem"), options: qatypes.FilePathOptions{MustExist: true, Extensions: []string{"pem"}}, valid: true},
+		{name: "file without the extension", path: filepath.Join(dir, "config.yaml"), options: qatypes.FilePathOptions{Extensions: []string{".pem"}}, valid: false},
+		{name: "missing file", path: filepath.Join(dir, "missing.pem"), options: qatypes.FilePathOptions{}, valid: true},
+		{name: "missing file that must exist", path: filepath.Join(dir, "missing.pem"), options: qatypes.FilePathOptions{MustExist: true}, valid: false},
+		{name: "directory instead of a file", path: filepath.Join(dir, "certs"), options: qatypes.FilePathOptions{}, valid: false},
+		{name: "file instead of a directory", path: filepath.Join(dir, "cert.pem"), options: qatypes.FilePathOptions{Directory: true}, valid: false},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			err := testcase.options.ValidatePath(testcase.path)
+			if testcase.valid && err != nil {
+				t.Fatalf("expected the path to be valid. Error: %q", err)
+			}
+			if !testcase.valid && err == nil {
+				t.Fatalf("expected the path %s to be invalid", testcase.path)
+			}
+		})
+	}
+}
+
+func TestHTTPRESTEngineFilesHandler(t *testing.T) {
+	dir := newFilePathTestDir(t)
+	h := NewHTTPRESTEngine(0).(*HTTPRESTEngine)
+
+	t.Run("not a file path problem", func(t *testing.T) {
+		h.currentProblem = qatypes.Problem{ID: "input", Type: qatypes.InputSolutionFormType}
+		w := httptest.NewRecorder()
+		h.filesHandler(w, httptest.NewRequest(http.MethodGet, currentFilesURLPrefix, nil))
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("expected the status %d. Actual: %d", http.StatusNotFound, w.Code)
+		}
+	})
+
+	t.Run("directory of the default answer", func(t *testing.T) {
+		problem, err := qatypes.NewFilePathProblem("filepath", "desc", nil, filepath.Join(dir, "cert.pem"), qatypes.FilePathOptions{Extensions: []string{".pem"}}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.currentProblem = problem
+		w := httptest.NewRecorder()
+		h.filesHandler(w, httptest.NewRequest(http.MethodGet, currentFilesURLPrefix, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected the status %d. Actual: %d %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		listing := qatypes.FilePathListing{}
+		if err := json.Unmarshal(w.Body.Bytes(), &listing); err != nil {
+			t.Fatal(err)
+		}
+		want := qatypes.FilePathListing{
+			Path:   dir,
+			Parent: filepath.Dir(dir),
+			Entries: []qatypes.FilePathEntry{
+				{Name: ".hidden.pem", Path: filepath.Join(dir, ".hidden.pem")},
+				{Name: "cert.pem", Path: filepath.Join(dir, "cert.pem")},
+				{Name: "certs", Path: filepath.Join(dir, "certs"), IsDir: true},
+				{Name: "key.pem", Path: filepath.Join(dir, "key.pem")},
+			},
+		}
+		if !cmp.Equal(listing, want) {
+			t.Fatalf("the listing is incorrect. Differences:\n%s", cmp.Diff(want, listing))
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.filesHandler(w, httptest.NewRequest(http.MethodGet, currentFilesURLPrefix+"?path="+filepath.Join(dir, "missing", "dir"), nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected the status %d. Actual: %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/i18n"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// groups are the titles of the groups of the questions being asked, starting with the outermost group.
+	// They are kept per goroutine, keyed by the goroutine id, since the transformers running in parallel ask different questions.
+	groups      = map[uint64][]string{}
+	groupsMutex sync.Mutex
+)
+
+// StartGroup starts a group of related questions that are presented together under the title.
+// Groups can be nested and every call must be followed by a call to EndGroup once the questions are asked.
+func StartGroup(title string) {
+	id := common.GoroutineID()
+	groupsMutex.Lock()
+	defer groupsMutex.Unlock()
+	groups[id] = append(groups[id], title)
+}
+
+// EndGroup ends the innermost group of questions
+func EndGroup() {
+	id := common.GoroutineID()
+	groupsMutex.Lock()
+	defer groupsMutex.Unlock()
+	if len(groups[id]) == 0 {
+		logrus.Warnf("there is no group of questions to end")
+		return
+	}
+	if len(groups[id]) == 1 {
+		delete(groups, id)
+		return
+	}
+	groups[id] = groups[id][:len(groups[id])-1]
+}
+
+// getCurrentGroup returns the titles of the groups that the questions being asked belong to
+func getCurrentGroup() []string {
+	id := common.GoroutineID()
+	groupsMutex.Lock()
+	defer groupsMutex.Unlock()
+	if len(groups[id]) == 0 {
+		return nil
+	}
+	return append([]string{}, groups[id]...)
+}
+
+// getGroupHeaders returns the section headers to print when moving from the previous group to the next group.
+// The headers of the groups shared by both are not repeated and the nested groups are indented.
+func getGroupHeaders(prevGroup, nextGroup []string) []string {
+	shared := 0
+	for shared < len(prevGroup) && shared < len(nextGroup) && prevGroup[shared] == nextGroup[shared] {
+		shared++
+	}
+	headers := []string{}
+	for i := shared; i < len(nextGroup); i++ {
+		headers = append(headers, strings.Repeat("  ", i)+"== "+i18n.T(nextGroup[i])+" ==")
+	}
+	return headers
+}
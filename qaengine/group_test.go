@@ -0,0 +1,86 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+)
+
+func TestGetGroupHeaders(t *testing.T) {
+	testcases := []struct {
+		name      string
+		prevGroup []string
+		nextGroup []string
+		want      []string
+	}{
+		{name: "no groups", want: []string{}},
+		{name: "new group", nextGroup: []string{"Image registry"}, want: []string{"== Image registry =="}},
+		{name: "same group", prevGroup: []string{"Image registry"}, nextGroup: []string{"Image registry"}, want: []string{}},
+		{name: "nested group", prevGroup: []string{"Image registry"}, nextGroup: []string{"Image registry", "quay.io"}, want: []string{"  == quay.io =="}},
+		{name: "sibling group", prevGroup: []string{"Image registry", "quay.io"}, nextGroup: []string{"Image registry", "docker.io"}, want: []string{"  == docker.io =="}},
+		{name: "different group", prevGroup: []string{"Image registry", "quay.io"}, nextGroup: []string{"Image signing"}, want: []string{"== Image signing =="}},
+		{name: "leaving the group", prevGroup: []string{"Image registry"}, want: []string{}},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			headers := getGroupHeaders(testcase.prevGroup, testcase.nextGroup)
+			if !cmp.Equal(headers, testcase.want) {
+				t.Fatalf("the headers are incorrect. Differences:\n%s", cmp.Diff(testcase.want, headers))
+			}
+		})
+	}
+}
+
+func TestGroups(t *testing.T) {
+	engines = []Engine{}
+	AddEngine(NewDefaultEngine())
+	groups = map[uint64][]string{}
+
+	fetchGroup := func(key string) []string {
+		problem, err := qatypes.NewInputProblem(common.JoinQASubKeys(common.BaseKey, key), "Test description", nil, "default", nil)
+		if err != nil {
+			t.Fatalf("failed to create the problem. Error: %q", err)
+		}
+		problem, err = FetchAnswer(problem)
+		if err != nil {
+			t.Fatalf("failed to fetch the answer. Error: %q", err)
+		}
+		return problem.Group
+	}
+	StartGroup("Image registry")
+	if group := fetchGroup("url"); !cmp.Equal(group, []string{"Image registry"}) {
+		t.Fatalf("the group is incorrect. Actual: %+v", group)
+	}
+	otherGroup := make(chan []string)
+	go func() { otherGroup <- getCurrentGroup() }()
+	if group := <-otherGroup; group != nil {
+		t.Fatalf("expected the questions asked by another goroutine to not be in the group. Actual: %+v", group)
+	}
+	StartGroup("quay.io")
+	if group := fetchGroup("login"); !cmp.Equal(group, []string{"Image registry", "quay.io"}) {
+		t.Fatalf("the nested group is incorrect. Actual: %+v", group)
+	}
+	EndGroup()
+	EndGroup()
+	if group := fetchGroup("other"); group != nil {
+		t.Fatalf("expected the problem to not be in a group after the groups ended. Actual: %+v", group)
+	}
+}
@@ -0,0 +1,181 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+	"github.com/konveyor/move2kube/types/qaengine/qagrpc"
+	"github.com/phayes/freeport"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCEngine handles qa using the gRPC QA service defined in types/qaengine/qagrpc/qaservice.proto
+type GRPCEngine struct {
+	qagrpc.UnimplementedQAServiceServer
+	port  int
+	mutex sync.Mutex
+	// currentProblem is the problem waiting for an answer, it is nil if there is no such problem
+	currentProblem *qatypes.Problem
+	// problemCount is incremented every time a problem is asked so that the streams can tell the problems apart
+	problemCount int
+	// problemChanged is closed when a problem is asked to wake up the streams
+	problemChanged chan struct{}
+	answerChan     chan qatypes.Problem
+}
+
+// NewGRPCEngine creates a new instance of the gRPC engine
+func NewGRPCEngine(qaport int) Engine {
+	return &GRPCEngine{
+		port:           qaport,
+		problemChanged: make(chan struct{}),
+		answerChan:     make(chan qatypes.Problem, 1),
+	}
+}
+
+// StartEngine starts the gRPC QA service
+func (g *GRPCEngine) StartEngine() error {
+	if g.port == 0 {
+		var err error
+		g.port, err = freeport.GetFreePort()
+		if err != nil {
+			return fmt.Errorf("unable to find a free port : %s", err)
+		}
+	}
+	qaportstr := cast.ToString(g.port)
+	listener, err := net.Listen("tcp", ":"+qaportstr)
+	if err != nil {
+		return fmt.Errorf("unable to listen on port %d : %s", g.port, err)
+	}
+	s := grpc.NewServer()
+	qagrpc.RegisterQAServiceServer(s, g)
+	reflection.Register(s)
+	go func(listener net.Listener) {
+		if err := s.Serve(listener); err != nil {
+			logrus.Fatalf("Unable to start the gRPC qa server : %s", err)
+		}
+	}(listener)
+	logrus.Info("Started gRPC QA engine on: localhost:" + qaportstr)
+	return nil
+}
+
+// IsInteractiveEngine returns true if the engine interacts with the user
+func (*GRPCEngine) IsInteractiveEngine() bool {
+	return true
+}
+
+// FetchAnswer streams the problem to the clients and waits for one of them to answer it
+func (g *GRPCEngine) FetchAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
+	if err := ValidateProblem(prob); err != nil {
+		logrus.Errorf("the QA problem object is invalid. Error: %q", err)
+		return prob, err
+	}
+	if prob.Answer != nil {
+		return prob, nil
+	}
+	logrus.Debugf("Passing problem to gRPC QA Engine ID: %s, desc: %s", prob.ID, prob.Desc)
+	g.mutex.Lock()
+	g.currentProblem = &prob
+	g.problemCount++
+	close(g.problemChanged)
+	g.problemChanged = make(chan struct{})
+	g.mutex.Unlock()
+	prob = <-g.answerChan
+	if prob.Answer == nil {
+		return prob, fmt.Errorf("failed to resolve the QA problem: %+v", prob)
+	}
+	return prob, nil
+}
+
+// Questions streams the problem waiting for an answer and the problems asked after it
+func (g *GRPCEngine) Questions(_ *qagrpc.QuestionsRequest, stream qagrpc.QAService_QuestionsServer) error {
+	sentCount := 0
+	for {
+		g.mutex.Lock()
+		prob, count, changed := g.currentProblem, g.problemCount, g.problemChanged
+		g.mutex.Unlock()
+		if prob != nil && count != sentCount {
+			logrus.Debugf("gRPC QA Engine serves problem id: %s, desc: %s", prob.ID, prob.Desc)
+			if err := stream.Send(getGRPCQuestion(*prob)); err != nil {
+				return err
+			}
+			sentCount = count
+		}
+		select {
+		case <-changed:
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// Answer accepts the solution for the problem waiting for an answer
+func (g *GRPCEngine) Answer(_ context.Context, solution *qagrpc.Solution) (*qagrpc.AnswerResponse, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if g.currentProblem == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "there is no problem waiting for an answer")
+	}
+	prob := *g.currentProblem
+	if prob.ID != solution.GetId() {
+		return nil, status.Errorf(codes.InvalidArgument, "the solution's problem ID doesn't match the current problem. Expected: %s Actual %s", prob.ID, solution.GetId())
+	}
+	answers := solution.GetAnswer()
+	if answers == nil {
+		answers = []string{}
+	}
+	answer, err := qatypes.ArrayToInterface(answers, prob.Type)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to convert the solution to an answer. Error: %q", err)
+	}
+	if err := prob.SetAnswer(answer, true); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to set the solution as the answer. Error: %q", err)
+	}
+	g.currentProblem = nil
+	g.answerChan <- prob
+	return &qagrpc.AnswerResponse{}, nil
+}
+
+// getGRPCQuestion converts the problem to the message sent to the clients
+func getGRPCQuestion(prob qatypes.Problem) *qagrpc.Question {
+	prob = localizeProblem(prob)
+	question := &qagrpc.Question{
+		Id:          prob.ID,
+		Type:        string(prob.Type),
+		Description: prob.Desc,
+		Hints:       prob.Hints,
+		Options:     prob.Options,
+		Group:       prob.Group,
+	}
+	if prob.Default != nil {
+		defaults, err := qatypes.InterfaceToArray(prob.Default, prob.Type)
+		if err != nil {
+			logrus.Debugf("failed to convert the default of the problem %s . Error: %q", prob.ID, err)
+		}
+		question.Default = defaults
+	}
+	return question
+}
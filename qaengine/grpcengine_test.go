@@ -0,0 +1,88 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+	"github.com/konveyor/move2kube/types/qaengine/qagrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// waitForGRPCProblem waits until the engine has a problem waiting for an answer
+func waitForGRPCProblem(t *testing.T, e *GRPCEngine) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		e.mutex.Lock()
+		asked := e.currentProblem != nil
+		e.mutex.Unlock()
+		if asked {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("the problem was not asked")
+}
+
+func TestGRPCEngineAnswer(t *testing.T) {
+	e := NewGRPCEngine(0).(*GRPCEngine)
+	if _, err := e.Answer(context.Background(), &qagrpc.Solution{Id: "move2kube.foo"}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected the answer to be rejected when there is no problem. Actual: %v", err)
+	}
+	prob, err := qatypes.NewMultiSelectProblem("move2kube.services", "Select the services", nil, []string{"api", "web"}, []string{"api", "web"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type result struct {
+		prob qatypes.Problem
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		answered, err := e.FetchAnswer(prob)
+		results <- result{answered, err}
+	}()
+	waitForGRPCProblem(t, e)
+
+	question := getGRPCQuestion(*e.currentProblem)
+	if question.Id != prob.ID || question.Type != string(qatypes.MultiSelectSolutionFormType) || len(question.Default) != 2 {
+		t.Fatalf("the question is incorrect. Actual: %+v", question)
+	}
+	if _, err := e.Answer(context.Background(), &qagrpc.Solution{Id: "move2kube.other"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected the answer with the wrong ID to be rejected. Actual: %v", err)
+	}
+	if _, err := e.Answer(context.Background(), &qagrpc.Solution{Id: prob.ID, Answer: []string{"web", "db"}}); err != nil {
+		t.Fatalf("failed to answer the problem. Error: %q", err)
+	}
+	select {
+	case r := <-results:
+		if r.err != nil {
+			t.Fatalf("failed to fetch the answer. Error: %q", r.err)
+		}
+		// the options that don't exist are ignored
+		answer, ok := r.prob.Answer.([]string)
+		if !ok || len(answer) != 1 || answer[0] != "web" {
+			t.Fatalf("the answer is incorrect. Actual: %#v", r.prob.Answer)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the answer was not returned")
+	}
+}
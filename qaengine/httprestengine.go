@@ -45,6 +45,7 @@ const (
 	problemsURLPrefix        = "/problems"
 	currentProblemURLPrefix  = problemsURLPrefix + "/current"
 	currentSolutionURLPrefix = currentProblemURLPrefix + "/solution"
+	currentFilesURLPrefix    = currentProblemURLPrefix + "/files"
 )
 
 // NewHTTPRESTEngine creates a new instance of Http REST engine
@@ -70,6 +71,7 @@ func (h *HTTPRESTEngine) StartEngine() error {
 	r := mux.NewRouter()
 	r.HandleFunc(currentProblemURLPrefix, h.problemHandler).Methods("GET")
 	r.HandleFunc(currentSolutionURLPrefix, h.solutionHandler).Methods("POST")
+	r.HandleFunc(currentFilesURLPrefix, h.filesHandler).Methods("GET")
 
 	http.Handle("/", r)
 	qaportstr := cast.ToString(h.port)
@@ -152,7 +154,7 @@ func (h *HTTPRESTEngine) problemHandler(w http.ResponseWriter, r *http.Request)
 	}
 	logrus.Debugf("QA Engine serves problem id: %s, desc: %s", h.currentProblem.ID, h.currentProblem.Desc)
 	// Send the problem to the request.
-	_ = json.NewEncoder(w).Encode(h.currentProblem)
+	_ = json.NewEncoder(w).Encode(localizeProblem(h.currentProblem))
 }
 
 // solutionHandler accepts solution for a single open problem.
@@ -188,3 +190,31 @@ func (h *HTTPRESTEngine) solutionHandler(w http.ResponseWriter, r *http.Request)
 	}
 	h.answerChan <- h.currentProblem
 }
+
+// filesHandler lists a directory so that the answer to the current FilePath problem can be picked using a file browser
+func (h *HTTPRESTEngine) filesHandler(w http.ResponseWriter, r *http.Request) {
+	problem := h.currentProblem
+	if problem.ID == "" || problem.Answer != nil || problem.Type != qatypes.FilePathSolutionFormType {
+		errstr := fmt.Sprintf("the current problem is not a %s problem", qatypes.FilePathSolutionFormType)
+		http.Error(w, errstr, http.StatusNotFound)
+		logrus.Errorf(errstr)
+		return
+	}
+	options := qatypes.FilePathOptions{}
+	if problem.FilePathOptions != nil {
+		options = *problem.FilePathOptions
+	}
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		path, _ = problem.Default.(string)
+	}
+	listing, err := listFilePathDirectory(path, options)
+	if err != nil {
+		errstr := fmt.Sprintf("failed to list the files. Error: %q", err)
+		http.Error(w, errstr, http.StatusBadRequest)
+		logrus.Errorf(errstr)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(listing)
+}
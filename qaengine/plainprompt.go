@@ -0,0 +1,250 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/i18n"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+)
+
+var (
+	plainMode   bool
+	plainInput            = bufio.NewReader(os.Stdin)
+	plainOutput io.Writer = os.Stdout
+)
+
+// SetPlainMode makes the cli engine ask the questions as plain lines of text instead of interactive widgets.
+// This works better with screen readers and terminals that do not support cursor movement.
+func SetPlainMode(enabled bool) {
+	plainMode = enabled
+}
+
+// fetchPlainAnswer asks the question as plain text and reads the answer line by line, asking again until the answer is valid
+func fetchPlainAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
+	fmt.Fprint(plainOutput, getQAMessage(prob))
+	for {
+		ans, err := readPlainAnswer(prob)
+		if err != nil {
+			return prob, err
+		}
+		if err := prob.Validate(ans); err != nil {
+			fmt.Fprintln(plainOutput, i18n.T("The answer is invalid: %s", err))
+			continue
+		}
+		prob.Answer = ans
+		return prob, nil
+	}
+}
+
+// readPlainAnswer prints the options of the question and parses the answer as per the question type
+func readPlainAnswer(prob qatypes.Problem) (interface{}, error) {
+	switch prob.Type {
+	case qatypes.SelectSolutionFormType:
+		def := ""
+		if prob.Default != nil {
+			def, _ = prob.Default.(string)
+		} else if len(prob.Options) > 0 {
+			def = prob.Options[0]
+		}
+		printPlainOptions(prob.Options)
+		line, err := readPlainLine(i18n.T("Enter the number of the option [default: %s]: ", def))
+		if err != nil {
+			return nil, err
+		}
+		if line == "" {
+			return def, nil
+		}
+		selected, err := parsePlainOptions(line, prob.Options)
+		if err != nil || len(selected) != 1 {
+			fmt.Fprintln(plainOutput, i18n.T("Enter a single number between 1 and %d.", len(prob.Options)))
+			return readPlainAnswer(prob)
+		}
+		if selected[0] == qatypes.OtherAnswer {
+			return readPlainLine(i18n.T("Enter the custom option: "))
+		}
+		return selected[0], nil
+	case qatypes.MultiSelectSolutionFormType:
+		def, _ := prob.Default.([]string)
+		printPlainOptions(prob.Options)
+		line, err := readPlainLine(i18n.T("Enter the numbers of the options separated by commas, or none [default: %s]: ", strings.Join(def, ", ")))
+		if err != nil {
+			return nil, err
+		}
+		if line == "" {
+			return def, nil
+		}
+		if strings.EqualFold(line, "none") {
+			return []string{}, nil
+		}
+		selected, err := parsePlainOptions(line, prob.Options)
+		if err != nil {
+			fmt.Fprintln(plainOutput, i18n.T("Enter numbers between 1 and %d separated by commas.", len(prob.Options)))
+			return readPlainAnswer(prob)
+		}
+		ans := []string{}
+		for _, option := range selected {
+			if option != qatypes.OtherAnswer {
+				ans = common.AppendIfNotPresent(ans, option)
+				continue
+			}
+			others, err := readPlainLines(i18n.T("Enter the custom options, one per line, followed by an empty line:"))
+			if err != nil {
+				return nil, err
+			}
+			ans = common.AppendIfNotPresent(ans, others...)
+		}
+		return ans, nil
+	case qatypes.ConfirmSolutionFormType:
+		def, _ := prob.Default.(bool)
+		defText := "y/N"
+		if def {
+			defText = "Y/n"
+		}
+		line, err := readPlainLine(fmt.Sprintf("(%s): ", defText))
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(line) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(plainOutput, i18n.T("Enter y or n."))
+		return readPlainAnswer(prob)
+	case qatypes.TableSolutionFormType:
+		options := *prob.TableOptions
+		defaultRows := []map[string]interface{}{}
+		if prob.Default != nil {
+			rows, err := options.NormalizeRows(prob.Default)
+			if err != nil {
+				return nil, err
+			}
+			defaultRows = rows
+		}
+		for _, line := range options.FormatRows(defaultRows) {
+			fmt.Fprintln(plainOutput, line)
+		}
+		lines, err := readPlainLines(getTableMessage(options) + i18n.T("Enter the rows followed by an empty line. An empty first line keeps the rows above."))
+		if err != nil {
+			return nil, err
+		}
+		if len(lines) == 0 {
+			return defaultRows, nil
+		}
+		rows, err := options.ParseRows(lines)
+		if err != nil {
+			fmt.Fprintln(plainOutput, i18n.T("The answer is invalid: %s", err))
+			return readPlainAnswer(prob)
+		}
+		return rows, nil
+	case qatypes.MultilineInputSolutionFormType:
+		def, _ := prob.Default.(string)
+		lines, err := readPlainLines(i18n.T("Enter the lines followed by an empty line. An empty first line keeps the default."))
+		if err != nil {
+			return nil, err
+		}
+		if len(lines) == 0 {
+			return def, nil
+		}
+		return strings.Join(lines, "\n"), nil
+	default:
+		def, _ := prob.Default.(string)
+		prompt := i18n.T("Enter the answer: ")
+		if def != "" {
+			prompt = i18n.T("Enter the answer [default: %s]: ", def)
+		}
+		line, err := readPlainLine(prompt)
+		if err != nil {
+			return nil, err
+		}
+		if line == "" {
+			return def, nil
+		}
+		return line, nil
+	}
+}
+
+// printPlainOptions prints the options as a numbered list
+func printPlainOptions(options []string) {
+	for i, option := range options {
+		fmt.Fprintf(plainOutput, "%d. %s\n", i+1, option)
+	}
+}
+
+// parsePlainOptions returns the options for the comma separated numbers or option names
+func parsePlainOptions(line string, options []string) ([]string, error) {
+	selected := []string{}
+	for _, part := range strings.Split(line, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if idx, err := strconv.Atoi(part); err == nil {
+			if idx < 1 || idx > len(options) {
+				return nil, fmt.Errorf("the option number %d is out of range", idx)
+			}
+			selected = append(selected, options[idx-1])
+			continue
+		}
+		if !common.IsPresent(options, part) {
+			return nil, fmt.Errorf("%s is not one of the options", part)
+		}
+		selected = append(selected, part)
+	}
+	return selected, nil
+}
+
+// readPlainLine prints the prompt and reads a line
+func readPlainLine(prompt string) (string, error) {
+	fmt.Fprint(plainOutput, prompt)
+	line, err := plainInput.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("failed to read the answer. Error: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// readPlainLines prints the prompt and reads the lines until an empty line
+func readPlainLines(prompt string) ([]string, error) {
+	fmt.Fprintln(plainOutput, prompt)
+	lines := []string{}
+	for {
+		line, err := plainInput.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read the answer. Error: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if strings.TrimSpace(line) == "" {
+			return lines, nil
+		}
+		lines = append(lines, line)
+		if err == io.EOF {
+			return lines, nil
+		}
+	}
+}
@@ -0,0 +1,118 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+)
+
+func TestFetchPlainAnswer(t *testing.T) {
+	defer func(input *bufio.Reader) { plainInput = input }(plainInput)
+	defer func(output io.Writer) { plainOutput = output }(plainOutput)
+	testcases := []struct {
+		name  string
+		prob  qatypes.Problem
+		input string
+		want  interface{}
+	}{
+		{
+			name:  "select by number",
+			prob:  qatypes.Problem{ID: "move2kube.target.outputlayout", Type: qatypes.SelectSolutionFormType, Options: []string{"monorepo", "perservice"}, Default: "monorepo"},
+			input: "2\n",
+			want:  "perservice",
+		},
+		{
+			name:  "select the default after an invalid number",
+			prob:  qatypes.Problem{ID: "move2kube.target.outputlayout", Type: qatypes.SelectSolutionFormType, Options: []string{"monorepo", "perservice"}, Default: "monorepo"},
+			input: "5\n\n",
+			want:  "monorepo",
+		},
+		{
+			name:  "multiselect by numbers and names",
+			prob:  qatypes.Problem{ID: "move2kube.services", Type: qatypes.MultiSelectSolutionFormType, Options: []string{"api", "web", "db"}, Default: []string{"api"}},
+			input: "3, web\n",
+			want:  []string{"db", "web"},
+		},
+		{
+			name:  "multiselect none",
+			prob:  qatypes.Problem{ID: "move2kube.services", Type: qatypes.MultiSelectSolutionFormType, Options: []string{"api", "web"}, Default: []string{"api"}},
+			input: "none\n",
+			want:  []string{},
+		},
+		{
+			name:  "confirm",
+			prob:  qatypes.Problem{ID: "move2kube.target.vpa", Type: qatypes.ConfirmSolutionFormType, Default: false},
+			input: "maybe\nyes\n",
+			want:  true,
+		},
+		{
+			name:  "input with the default",
+			prob:  qatypes.Problem{ID: "move2kube.target.imageregistry.url", Type: qatypes.InputSolutionFormType, Default: "quay.io"},
+			input: "\n",
+			want:  "quay.io",
+		},
+		{
+			name: "input that is validated",
+			prob: qatypes.Problem{ID: "move2kube.target.imageregistry.url", Type: qatypes.InputSolutionFormType, Validator: func(ans interface{}) error {
+				if strings.Contains(ans.(string), " ") {
+					return fmt.Errorf("spaces are not allowed")
+				}
+				return nil
+			}},
+			input: "bad value\ndocker.io\n",
+			want:  "docker.io",
+		},
+		{
+			name:  "multiline input",
+			prob:  qatypes.Problem{ID: "move2kube.webhooks.urls", Type: qatypes.MultilineInputSolutionFormType},
+			input: "http://a\nhttp://b\n\n",
+			want:  "http://a\nhttp://b",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			plainInput = bufio.NewReader(strings.NewReader(tc.input))
+			output := &bytes.Buffer{}
+			plainOutput = output
+			prob, err := fetchPlainAnswer(tc.prob)
+			if err != nil {
+				t.Fatalf("failed to fetch the answer. Error: %q", err)
+			}
+			if !cmp.Equal(prob.Answer, tc.want) {
+				t.Fatalf("wrong answer. Difference:\n%s", cmp.Diff(tc.want, prob.Answer))
+			}
+			if !strings.Contains(output.String(), tc.prob.ID) {
+				t.Fatalf("expected the question id in the output. Actual: %s", output.String())
+			}
+		})
+	}
+	t.Run("the end of the input is an error", func(t *testing.T) {
+		plainInput = bufio.NewReader(strings.NewReader(""))
+		plainOutput = &bytes.Buffer{}
+		if _, err := fetchPlainAnswer(qatypes.Problem{ID: "move2kube.target.vpa", Type: qatypes.ConfirmSolutionFormType}); err == nil {
+			t.Fatalf("expected an error at the end of the input")
+		}
+	})
+}
@@ -0,0 +1,119 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"fmt"
+	"sync"
+
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+)
+
+// AnswerResolver is implemented by the custom sources of answers like databases and ticketing systems.
+// If the resolver also implements SecretEngine its answers are not written to the config and the cache.
+type AnswerResolver interface {
+	// Name returns the name of the resolver used in the logs
+	Name() string
+	// Resolve returns the answer to the problem. It returns false if the resolver does not have an answer.
+	Resolve(prob qatypes.Problem) (answer interface{}, ok bool, err error)
+}
+
+// ResolverPrecedence is when a resolver is consulted relative to the built-in sources of answers
+type ResolverPrecedence int
+
+const (
+	// BeforeBuiltinSources consults the resolver before the environment, the vault, the session, the caches and the config files
+	BeforeBuiltinSources ResolverPrecedence = iota
+	// AfterBuiltinSources consults the resolver after the built-in sources and before asking the user or using the default
+	AfterBuiltinSources
+)
+
+var (
+	// resolvers are the registered resolvers for each precedence, in the order they were registered
+	resolvers      = map[ResolverPrecedence][]Engine{}
+	resolversMutex sync.Mutex
+)
+
+// resolverEngine adapts an AnswerResolver to the Engine interface
+type resolverEngine struct {
+	resolver AnswerResolver
+}
+
+// StartEngine starts the resolver engine
+func (*resolverEngine) StartEngine() error {
+	return nil
+}
+
+// IsInteractiveEngine returns true if the engine interacts with the user
+func (*resolverEngine) IsInteractiveEngine() bool {
+	return false
+}
+
+// IsSecretEngine returns true if the answers of the resolver must not be persisted
+func (re *resolverEngine) IsSecretEngine() bool {
+	secretEngine, ok := re.resolver.(SecretEngine)
+	return ok && secretEngine.IsSecretEngine()
+}
+
+// FetchAnswer fetches the answer from the resolver
+func (re *resolverEngine) FetchAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
+	answer, ok, err := re.resolver.Resolve(prob)
+	if err != nil {
+		return prob, fmt.Errorf("the resolver %s failed to resolve the problem %s . Error: %w", re.resolver.Name(), prob.ID, err)
+	}
+	if !ok {
+		return prob, fmt.Errorf("the resolver %s does not have an answer for the problem %s", re.resolver.Name(), prob.ID)
+	}
+	err = prob.SetAnswer(answer, true)
+	return prob, err
+}
+
+// RegisterAnswerResolver registers a custom source of answers. Resolvers with the same precedence
+// are consulted in the order they were registered. The first resolver to return an answer wins.
+func RegisterAnswerResolver(resolver AnswerResolver, precedence ResolverPrecedence) error {
+	if resolver == nil {
+		return fmt.Errorf("the resolver is nil")
+	}
+	if precedence != BeforeBuiltinSources && precedence != AfterBuiltinSources {
+		return fmt.Errorf("the precedence %d of the resolver %s is invalid", precedence, resolver.Name())
+	}
+	resolversMutex.Lock()
+	defer resolversMutex.Unlock()
+	resolvers[precedence] = append(resolvers[precedence], &resolverEngine{resolver: resolver})
+	return nil
+}
+
+// getEngines returns the engines in the order they are consulted. The resolvers registered after the built-in
+// sources are placed before the first interactive or default engine since those always answer.
+func getEngines() []Engine {
+	resolversMutex.Lock()
+	defer resolversMutex.Unlock()
+	if len(resolvers) == 0 {
+		return engines
+	}
+	fallbackIdx := len(engines)
+	for i, e := range engines {
+		if _, ok := e.(*DefaultEngine); ok || e.IsInteractiveEngine() {
+			fallbackIdx = i
+			break
+		}
+	}
+	orderedEngines := append([]Engine{}, resolvers[BeforeBuiltinSources]...)
+	orderedEngines = append(orderedEngines, engines[:fallbackIdx]...)
+	orderedEngines = append(orderedEngines, resolvers[AfterBuiltinSources]...)
+	return append(orderedEngines, engines[fallbackIdx:]...)
+}
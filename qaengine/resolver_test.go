@@ -0,0 +1,91 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+)
+
+// mapResolver answers the questions using the answers in the map
+type mapResolver struct {
+	name    string
+	answers map[string]interface{}
+}
+
+func (r *mapResolver) Name() string { return r.name }
+
+func (r *mapResolver) Resolve(prob qatypes.Problem) (interface{}, bool, error) {
+	answer, ok := r.answers[prob.ID]
+	return answer, ok, nil
+}
+
+func TestRegisterAnswerResolver(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "m2kconfig.yaml")
+	if err := os.WriteFile(configPath, []byte("move2kube:\n  services:\n    api:\n      name: config-api\n"), common.DefaultFilePermission); err != nil {
+		t.Fatalf("failed to write the config file. Error: %q", err)
+	}
+	config := qatypes.NewConfig("", nil, []string{configPath}, false)
+	if err := config.Load(); err != nil {
+		t.Fatalf("failed to load the config. Error: %q", err)
+	}
+	engines = []Engine{&StoreEngine{store: config}, NewDefaultEngine()}
+	defer func() { answeredProblems, engines, resolvers = nil, nil, map[ResolverPrecedence][]Engine{} }()
+
+	after := &mapResolver{name: "after", answers: map[string]interface{}{"move2kube.services.api.name": "after-api", "move2kube.services.web.name": "after-web"}}
+	if err := RegisterAnswerResolver(after, AfterBuiltinSources); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterAnswerResolver(after, ResolverPrecedence(5)); err == nil {
+		t.Fatalf("expected an error for an invalid precedence")
+	}
+	testcases := []struct {
+		id   string
+		want string
+	}{
+		{id: "move2kube.services.api.name", want: "config-api"},
+		{id: "move2kube.services.web.name", want: "after-web"},
+		{id: "move2kube.services.db.name", want: "default"},
+	}
+	for _, tc := range testcases {
+		prob, err := qatypes.NewInputProblem(tc.id, "Enter the name of the service", nil, "default", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resolved, err := FetchAnswer(prob)
+		if err != nil || resolved.Answer != tc.want {
+			t.Fatalf("expected the answer %s for %s . Actual: %+v Error: %v", tc.want, tc.id, resolved.Answer, err)
+		}
+	}
+
+	before := &mapResolver{name: "before", answers: map[string]interface{}{"move2kube.services.api.name": "before-api"}}
+	if err := RegisterAnswerResolver(before, BeforeBuiltinSources); err != nil {
+		t.Fatal(err)
+	}
+	prob, err := qatypes.NewInputProblem("move2kube.services.api.name", "Enter the name of the service", nil, "default", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := FetchAnswer(prob)
+	if err != nil || resolved.Answer != "before-api" {
+		t.Fatalf("expected the resolver registered before the built-in sources to win. Actual: %+v Error: %v", resolved.Answer, err)
+	}
+}
@@ -0,0 +1,181 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	vaultAddrEnvVar      = "VAULT_ADDR"
+	vaultTokenEnvVar     = "VAULT_TOKEN"
+	vaultNamespaceEnvVar = "VAULT_NAMESPACE"
+	// vaultPasswordField is the field of the secret that contains the answer when the secret has more than one field
+	vaultPasswordField = "password"
+	vaultTimeout       = 30 * time.Second
+)
+
+// VaultEngine answers the password questions using the secrets in HashiCorp Vault
+type VaultEngine struct {
+	pathTemplateStr string
+	pathTemplate    *template.Template
+	address         string
+	token           string
+	namespace       string
+	client          *http.Client
+}
+
+// vaultPathData is the data used to render the path template of the secrets
+type vaultPathData struct {
+	// ID is the QA key of the question like move2kube.target.imageregistry."quay.io".password
+	ID string
+	// Path is the QA key without the move2kube prefix and with the sub keys separated by slashes like target/imageregistry/quay.io/password
+	Path string
+}
+
+// NewVaultEngine creates a new instance of the Vault engine. The path template is a Go template like secret/data/move2kube/{{ .Path }}
+func NewVaultEngine(pathTemplate string) Engine {
+	return &VaultEngine{pathTemplateStr: pathTemplate, client: &http.Client{Timeout: vaultTimeout}}
+}
+
+// StartEngine reads the address and the token of Vault from the environment and parses the path template
+func (v *VaultEngine) StartEngine() error {
+	v.address = strings.TrimSuffix(os.Getenv(vaultAddrEnvVar), "/")
+	if v.address == "" {
+		return fmt.Errorf("the address of Vault is not set. Set the %s environment variable", vaultAddrEnvVar)
+	}
+	v.token = os.Getenv(vaultTokenEnvVar)
+	if v.token == "" {
+		return fmt.Errorf("the token for Vault is not set. Set the %s environment variable", vaultTokenEnvVar)
+	}
+	v.namespace = os.Getenv(vaultNamespaceEnvVar)
+	pathTemplate, err := template.New("vault").Option("missingkey=error").Parse(v.pathTemplateStr)
+	if err != nil {
+		return fmt.Errorf("failed to parse the Vault path template '%s' . Error: %w", v.pathTemplateStr, err)
+	}
+	v.pathTemplate = pathTemplate
+	return nil
+}
+
+// IsInteractiveEngine returns true if the engine interacts with the user
+func (*VaultEngine) IsInteractiveEngine() bool {
+	return false
+}
+
+// IsSecretEngine returns true since the answers from Vault must not be written to the config and the cache
+func (*VaultEngine) IsSecretEngine() bool {
+	return true
+}
+
+// FetchAnswer fetches the answer to a password question from the secret in Vault
+func (v *VaultEngine) FetchAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
+	if prob.Type != qatypes.PasswordSolutionFormType {
+		return prob, fmt.Errorf("only the %s questions are answered using Vault. Actual type: %s", qatypes.PasswordSolutionFormType, prob.Type)
+	}
+	secretPath, err := v.getSecretPath(prob.ID)
+	if err != nil {
+		return prob, err
+	}
+	secret, err := v.readSecret(secretPath)
+	if err != nil {
+		return prob, err
+	}
+	password, err := getVaultPassword(secret)
+	if err != nil {
+		return prob, fmt.Errorf("failed to get the answer from the Vault secret at %s . Error: %w", secretPath, err)
+	}
+	logrus.Debugf("answering the problem %s using the Vault secret at %s", prob.ID, secretPath)
+	err = prob.SetAnswer(password, true)
+	return prob, err
+}
+
+func (v *VaultEngine) getSecretPath(key string) (string, error) {
+	subKeys := []string{}
+	for _, subKey := range common.SplitOnDotExpectInsideQuotes(key) {
+		subKeys = append(subKeys, common.StripQuotes(subKey))
+	}
+	if len(subKeys) > 1 && subKeys[0] == common.BaseKey {
+		subKeys = subKeys[1:]
+	}
+	secretPath := bytes.Buffer{}
+	if err := v.pathTemplate.Execute(&secretPath, vaultPathData{ID: key, Path: strings.Join(subKeys, "/")}); err != nil {
+		return "", fmt.Errorf("failed to render the Vault path template for the key %s . Error: %w", key, err)
+	}
+	return strings.Trim(secretPath.String(), "/"), nil
+}
+
+// readSecret reads the secret from the KV secrets engine. Both the version 1 and the version 2 of the engine are supported.
+func (v *VaultEngine) readSecret(secretPath string) (map[string]interface{}, error) {
+	secretURL := v.address + "/v1/" + secretPath
+	req, err := http.NewRequest(http.MethodGet, secretURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the request for the Vault secret at %s . Error: %w", secretURL, err)
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the Vault secret at %s . Error: %w", secretURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("failed to read the Vault secret at %s . Status: %s", secretURL, resp.Status)
+	}
+	body := struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to parse the Vault secret at %s . Error: %w", secretURL, err)
+	}
+	if data, ok := body.Data["data"].(map[string]interface{}); ok {
+		if _, ok := body.Data["metadata"]; ok {
+			return data, nil
+		}
+	}
+	return body.Data, nil
+}
+
+// getVaultPassword returns the password field of the secret, or the only field if the secret has just one
+func getVaultPassword(secret map[string]interface{}) (string, error) {
+	value, ok := secret[vaultPasswordField]
+	if !ok {
+		if len(secret) != 1 {
+			return "", fmt.Errorf("the secret should have a %s field or exactly one field. Actual number of fields: %d", vaultPasswordField, len(secret))
+		}
+		for _, v := range secret {
+			value = v
+		}
+	}
+	password, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("expected the password to be a string. Actual value is of type %T", value)
+	}
+	return password, nil
+}
@@ -0,0 +1,111 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+)
+
+func newTestVaultEngine(t *testing.T, secrets map[string]interface{}) Engine {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		secret, ok := secrets[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(secret)
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv(vaultAddrEnvVar, server.URL+"/")
+	t.Setenv(vaultTokenEnvVar, "test-token")
+	e := NewVaultEngine("secret/data/move2kube/{{ .Path }}")
+	if err := e.StartEngine(); err != nil {
+		t.Fatalf("failed to start the Vault engine. Error: %q", err)
+	}
+	return e
+}
+
+func TestVaultEngine(t *testing.T) {
+	e := newTestVaultEngine(t, map[string]interface{}{
+		"/v1/secret/data/move2kube/target/imageregistry/quay.io/password": map[string]interface{}{
+			"data": map[string]interface{}{"data": map[string]interface{}{"password": "kv2-password", "username": "user"}, "metadata": map[string]interface{}{"version": 1}},
+		},
+		"/v1/secret/data/move2kube/target/imageregistry/docker.io/password": map[string]interface{}{
+			"data": map[string]interface{}{"token": "kv1-password"},
+		},
+	})
+	newPasswordProblem := func(registry string) qatypes.Problem {
+		problem, err := qatypes.NewPasswordProblem(`move2kube.target.imageregistry."`+registry+`".password`, "desc", nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return problem
+	}
+
+	t.Run("password field of a KV version 2 secret", func(t *testing.T) {
+		problem, err := e.FetchAnswer(newPasswordProblem("quay.io"))
+		if err != nil {
+			t.Fatalf("failed to fetch the answer. Error: %q", err)
+		}
+		if problem.Answer != "kv2-password" {
+			t.Fatalf("the answer is incorrect. Actual: %+v", problem.Answer)
+		}
+	})
+
+	t.Run("only field of a KV version 1 secret", func(t *testing.T) {
+		problem, err := e.FetchAnswer(newPasswordProblem("docker.io"))
+		if err != nil {
+			t.Fatalf("failed to fetch the answer. Error: %q", err)
+		}
+		if problem.Answer != "kv1-password" {
+			t.Fatalf("the answer is incorrect. Actual: %+v", problem.Answer)
+		}
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		if _, err := e.FetchAnswer(newPasswordProblem("ghcr.io")); err == nil {
+			t.Fatalf("expected an error for a missing secret")
+		}
+	})
+
+	t.Run("not a password problem", func(t *testing.T) {
+		problem, err := qatypes.NewInputProblem(`move2kube.target.imageregistry."quay.io".username`, "desc", nil, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := e.FetchAnswer(problem); err == nil {
+			t.Fatalf("expected an error for a question that is not a password question")
+		}
+	})
+}
+
+func TestVaultEngineNotConfigured(t *testing.T) {
+	t.Setenv(vaultAddrEnvVar, "")
+	if err := NewVaultEngine("secret/{{ .Path }}").StartEngine(); err == nil {
+		t.Fatalf("expected an error when the address of Vault is not set")
+	}
+}
@@ -0,0 +1,43 @@
+//go:build ignore
+// +build ignore
+
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// This program generates the JSON schema for the transformer yamls. It can be invoked by running
+// go generate
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		logrus.Fatalf("expected the path of the schema file as the only argument. Actual: %+v", os.Args[1:])
+	}
+	schemaBytes, err := json.MarshalIndent(transformertypes.GenerateTransformerSchema(), "", "  ")
+	if err != nil {
+		logrus.Fatalf("failed to marshal the transformer schema to json. Error: %q", err)
+	}
+	if err := os.WriteFile(os.Args[1], append(schemaBytes, '\n'), 0644); err != nil {
+		logrus.Fatalf("failed to write the transformer schema to the file %s . Error: %q", os.Args[1], err)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package server
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// writeZipArchive writes the contents of the directory to w as a zip archive
+func writeZipArchive(w io.Writer, srcPath string) error {
+	zipWriter := zip.NewWriter(w)
+	err := filepath.WalkDir(srcPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == srcPath {
+			return nil
+		}
+		relPath, err := filepath.Rel(srcPath, path)
+		if err != nil {
+			return fmt.Errorf("failed to make the path %s relative to %s . Error: %w", path, srcPath, err)
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat the path %s . Error: %w", path, err)
+		}
+		if !fi.IsDir() && !fi.Mode().IsRegular() {
+			return nil
+		}
+		header, err := zip.FileInfoHeader(fi)
+		if err != nil {
+			return fmt.Errorf("failed to create the zip header for the path %s . Error: %w", path, err)
+		}
+		header.Name = filepath.ToSlash(relPath)
+		if fi.IsDir() {
+			header.Name += "/"
+		} else {
+			header.Method = zip.Deflate
+		}
+		entryWriter, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			return fmt.Errorf("failed to add the path %s to the zip archive. Error: %w", path, err)
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open the file %s . Error: %w", path, err)
+		}
+		defer f.Close()
+		if _, err := io.Copy(entryWriter, f); err != nil {
+			return fmt.Errorf("failed to add the file %s to the zip archive. Error: %w", path, err)
+		}
+		return nil
+	})
+	if err != nil {
+		zipWriter.Close()
+		return err
+	}
+	return zipWriter.Close()
+}
@@ -0,0 +1,286 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package client is a Go client for the REST API served by `move2kube serve`.
+// It follows the operations in server/openapi.yaml and uses the request and response types of the server package.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/move2kube/server"
+	"github.com/konveyor/move2kube/types/info"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+)
+
+// APIError is returned when the server responds with an error status
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("the server responded with the status %d : %s", e.StatusCode, e.Message)
+}
+
+// Client calls the REST API
+type Client struct {
+	// BaseURL is the url of the server including the /api/v1 prefix
+	BaseURL    string
+	HTTPClient *http.Client
+	// AuthToken is sent as the bearer token if the server requires one
+	AuthToken string
+}
+
+// NewClient returns a client for the server at the given url like http://localhost:8080/api/v1
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), HTTPClient: http.DefaultClient}
+}
+
+// do sends the request and decodes the json response into out, if out is not nil
+func (c *Client) do(ctx context.Context, method, path, contentType string, body io.Reader, out interface{}) error {
+	resp, err := c.send(ctx, method, path, contentType, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode the response of %s %s . Error: %w", method, path, err)
+	}
+	return nil
+}
+
+// send sends the request and returns the response if it was successful. The caller must close the body.
+func (c *Client) send(ctx context.Context, method, path, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the request %s %s . Error: %w", method, path, err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AuthToken)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send the request %s %s . Error: %w", method, path, err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		data, _ := io.ReadAll(resp.Body)
+		errResp := map[string]string{}
+		if err := json.Unmarshal(data, &errResp); err == nil && errResp["error"] != "" {
+			apiErr.Message = errResp["error"]
+		} else {
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		return nil, apiErr
+	}
+	return resp, nil
+}
+
+func (c *Client) doJSON(ctx context.Context, method, path string, in, out interface{}) error {
+	if in == nil {
+		return c.do(ctx, method, path, "", nil, out)
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the request body of %s %s . Error: %w", method, path, err)
+	}
+	return c.do(ctx, method, path, "application/json", bytes.NewReader(data), out)
+}
+
+func workspacePath(id string) string {
+	return "/workspaces/" + url.PathEscape(id)
+}
+
+func jobPath(id string) string {
+	return "/jobs/" + url.PathEscape(id)
+}
+
+// GetOpenAPISpec returns the OpenAPI document of the server
+func (c *Client) GetOpenAPISpec(ctx context.Context) ([]byte, error) {
+	return c.getBytes(ctx, "/openapi.yaml")
+}
+
+// GetVersion returns the version of the server
+func (c *Client) GetVersion(ctx context.Context) (info.VersionInfo, error) {
+	v := info.VersionInfo{}
+	err := c.doJSON(ctx, http.MethodGet, "/version", nil, &v)
+	return v, err
+}
+
+// ListWorkspaces returns all the workspaces
+func (c *Client) ListWorkspaces(ctx context.Context) ([]server.WorkspaceT, error) {
+	workspaces := []server.WorkspaceT{}
+	err := c.doJSON(ctx, http.MethodGet, "/workspaces", nil, &workspaces)
+	return workspaces, err
+}
+
+// CreateWorkspace creates a new workspace for the project
+func (c *Client) CreateWorkspace(ctx context.Context, name string) (server.WorkspaceT, error) {
+	w := server.WorkspaceT{}
+	err := c.doJSON(ctx, http.MethodPost, "/workspaces", server.CreateWorkspaceRequestT{Name: name}, &w)
+	return w, err
+}
+
+// GetWorkspace returns the workspace
+func (c *Client) GetWorkspace(ctx context.Context, id string) (server.WorkspaceT, error) {
+	w := server.WorkspaceT{}
+	err := c.doJSON(ctx, http.MethodGet, workspacePath(id), nil, &w)
+	return w, err
+}
+
+// DeleteWorkspace deletes the workspace, cancelling its job if it is still running
+func (c *Client) DeleteWorkspace(ctx context.Context, id string) error {
+	return c.doJSON(ctx, http.MethodDelete, workspacePath(id), nil, nil)
+}
+
+// UploadSource replaces the source in the workspace with the contents of the archive.
+// The file name must have the extension of a supported archive format.
+func (c *Client) UploadSource(ctx context.Context, id, fileName string, archive io.Reader) (server.WorkspaceT, error) {
+	w := server.WorkspaceT{}
+	body := &bytes.Buffer{}
+	formWriter := multipart.NewWriter(body)
+	fileWriter, err := formWriter.CreateFormFile("file", filepath.Base(fileName))
+	if err != nil {
+		return w, fmt.Errorf("failed to create the multipart form. Error: %w", err)
+	}
+	if _, err := io.Copy(fileWriter, archive); err != nil {
+		return w, fmt.Errorf("failed to read the archive %s . Error: %w", fileName, err)
+	}
+	if err := formWriter.Close(); err != nil {
+		return w, fmt.Errorf("failed to create the multipart form. Error: %w", err)
+	}
+	err = c.do(ctx, http.MethodPut, workspacePath(id)+"/source", formWriter.FormDataContentType(), body, &w)
+	return w, err
+}
+
+// StartPlan starts a job to create the plan for the source in the workspace
+func (c *Client) StartPlan(ctx context.Context, id string) (server.JobT, error) {
+	j := server.JobT{}
+	err := c.doJSON(ctx, http.MethodPost, workspacePath(id)+"/plan", nil, &j)
+	return j, err
+}
+
+// GetPlan returns the plan yaml in the workspace
+func (c *Client) GetPlan(ctx context.Context, id string) ([]byte, error) {
+	return c.getBytes(ctx, workspacePath(id)+"/plan")
+}
+
+// UpdatePlan replaces the plan in the workspace
+func (c *Client) UpdatePlan(ctx context.Context, id string, plan []byte) (server.WorkspaceT, error) {
+	w := server.WorkspaceT{}
+	err := c.do(ctx, http.MethodPut, workspacePath(id)+"/plan", "application/x-yaml", bytes.NewReader(plan), &w)
+	return w, err
+}
+
+// StartTransform starts a job to transform the source in the workspace using the plan
+func (c *Client) StartTransform(ctx context.Context, id string, options server.TransformOptionsT) (server.JobT, error) {
+	j := server.JobT{}
+	err := c.doJSON(ctx, http.MethodPost, workspacePath(id)+"/transform", options, &j)
+	return j, err
+}
+
+// GetCurrentProblem returns the question the transformation is waiting on. It blocks until the next question is asked.
+func (c *Client) GetCurrentProblem(ctx context.Context, id string) (qatypes.Problem, error) {
+	p := qatypes.Problem{}
+	err := c.doJSON(ctx, http.MethodGet, workspacePath(id)+"/problems/current", nil, &p)
+	return p, err
+}
+
+// PostSolution answers the current question
+func (c *Client) PostSolution(ctx context.Context, id string, problem qatypes.Problem) error {
+	return c.doJSON(ctx, http.MethodPost, workspacePath(id)+"/problems/current/solution", problem, nil)
+}
+
+// GetCurrentProblemFiles lists the directory at the path for picking the answer to the current FilePath question.
+// The directory of the default answer is listed if the path is empty.
+func (c *Client) GetCurrentProblemFiles(ctx context.Context, id, path string) (qatypes.FilePathListing, error) {
+	l := qatypes.FilePathListing{}
+	err := c.doJSON(ctx, http.MethodGet, workspacePath(id)+"/problems/current/files?path="+url.QueryEscape(path), nil, &l)
+	return l, err
+}
+
+// DownloadOutput writes the output of the transformation as a zip archive to w
+func (c *Client) DownloadOutput(ctx context.Context, id string, w io.Writer) error {
+	resp, err := c.send(ctx, http.MethodGet, workspacePath(id)+"/output", "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to download the output of the workspace %s . Error: %w", id, err)
+	}
+	return nil
+}
+
+// GetLogs returns the logs of the last job in the workspace
+func (c *Client) GetLogs(ctx context.Context, id string) ([]byte, error) {
+	return c.getBytes(ctx, workspacePath(id)+"/logs")
+}
+
+// ListJobs returns the jobs. If the workspace id is not empty, only the jobs of that workspace are returned.
+func (c *Client) ListJobs(ctx context.Context, workspaceID string) ([]server.JobT, error) {
+	path := "/jobs"
+	if workspaceID != "" {
+		path += "?workspace=" + url.QueryEscape(workspaceID)
+	}
+	jobs := []server.JobT{}
+	err := c.doJSON(ctx, http.MethodGet, path, nil, &jobs)
+	return jobs, err
+}
+
+// GetJob returns the job along with its progress
+func (c *Client) GetJob(ctx context.Context, id string) (server.JobT, error) {
+	j := server.JobT{}
+	err := c.doJSON(ctx, http.MethodGet, jobPath(id), nil, &j)
+	return j, err
+}
+
+// CancelJob cancels a queued or running job
+func (c *Client) CancelJob(ctx context.Context, id string) (server.JobT, error) {
+	j := server.JobT{}
+	err := c.doJSON(ctx, http.MethodPost, jobPath(id)+"/cancel", nil, &j)
+	return j, err
+}
+
+func (c *Client) getBytes(ctx context.Context, path string) ([]byte, error) {
+	resp, err := c.send(ctx, http.MethodGet, path, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response of GET %s . Error: %w", path, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,93 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package client_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/konveyor/move2kube/server"
+	"github.com/konveyor/move2kube/server/client"
+)
+
+func TestClient(t *testing.T) {
+	handler, err := server.NewHandler(server.ConfigT{WorkspacesPath: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create the server. Error: %q", err)
+	}
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+	c := client.NewClient(ts.URL + "/api/v1")
+	ctx := context.Background()
+
+	spec, err := c.GetOpenAPISpec(ctx)
+	if err != nil || !bytes.HasPrefix(spec, []byte("openapi: ")) {
+		t.Fatalf("failed to get the OpenAPI document. Error: %v", err)
+	}
+	w, err := c.CreateWorkspace(ctx, "myproject")
+	if err != nil {
+		t.Fatalf("failed to create the workspace. Error: %q", err)
+	}
+	if w.Name != "myproject" || w.Status != server.WorkspaceCreated {
+		t.Fatalf("the created workspace is incorrect. Actual: %+v", w)
+	}
+
+	archive := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(archive)
+	entryWriter, err := zipWriter.Create("app/main.py")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := entryWriter.Write([]byte("print('hello')\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if w, err = c.UploadSource(ctx, w.ID, "source.zip", archive); err != nil {
+		t.Fatalf("failed to upload the source. Error: %q", err)
+	}
+	if !w.HasSource {
+		t.Fatalf("expected the workspace to have a source. Actual: %+v", w)
+	}
+
+	_, err = c.GetPlan(ctx, w.ID)
+	apiErr := &client.APIError{}
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected a not found error for the missing plan. Actual: %v", err)
+	}
+
+	workspaces, err := c.ListWorkspaces(ctx)
+	if err != nil || len(workspaces) != 1 || workspaces[0].ID != w.ID {
+		t.Fatalf("failed to list the workspaces. Actual: %+v Error: %v", workspaces, err)
+	}
+	jobs, err := c.ListJobs(ctx, w.ID)
+	if err != nil || len(jobs) != 0 {
+		t.Fatalf("expected no jobs. Actual: %+v Error: %v", jobs, err)
+	}
+	if err := c.DeleteWorkspace(ctx, w.ID); err != nil {
+		t.Fatalf("failed to delete the workspace. Error: %q", err)
+	}
+	if _, err := c.GetWorkspace(ctx, w.ID); err == nil {
+		t.Fatalf("expected the workspace to be deleted")
+	}
+}
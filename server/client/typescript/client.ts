@@ -0,0 +1,265 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// TypeScript client for the REST API served by `move2kube serve`.
+// It follows the operations and schemas in server/openapi.yaml and only depends on the fetch API.
+
+export interface VersionInfo {
+  version?: string;
+  gitCommit?: string;
+  gitTreeState?: string;
+  goVersion?: string;
+  platform?: string;
+}
+
+export type WorkspaceStatus = 'created' | 'planning' | 'planned' | 'transforming' | 'transformed' | 'failed';
+
+export interface Workspace {
+  id: string;
+  name: string;
+  timestamp: string;
+  status: WorkspaceStatus;
+  error?: string;
+  hasSource: boolean;
+  jobID?: string;
+}
+
+export interface TransformOptions {
+  qaSkip?: boolean;
+  setConfigs?: string[];
+  presets?: string[];
+}
+
+export type ProgressPhase = 'initializing' | 'planning' | 'transforming' | 'postprocessing' | 'done';
+
+export type TransformerStatus = 'running' | 'done' | 'failed';
+
+export interface Progress {
+  phase: ProgressPhase;
+  transformers?: { [name: string]: TransformerStatus };
+}
+
+export type JobType = 'plan' | 'transform';
+
+export type JobStatus = 'queued' | 'running' | 'succeeded' | 'failed' | 'cancelled';
+
+export interface Job {
+  id: string;
+  workspaceID: string;
+  type: JobType;
+  status: JobStatus;
+  progress: Progress;
+  error?: string;
+  createdAt: string;
+  startedAt?: string;
+  finishedAt?: string;
+}
+
+export type ProblemType = 'Select' | 'MultiSelect' | 'Input' | 'MultiLineInput' | 'Password' | 'Confirm' | 'FilePath' | 'Table';
+
+export interface FilePathOptions {
+  mustExist?: boolean;
+  directory?: boolean;
+  extensions?: string[];
+}
+
+export interface TableColumn {
+  name: string;
+  type?: 'string' | 'integer' | 'boolean';
+  options?: string[];
+  required?: boolean;
+}
+
+export interface TableOptions {
+  columns: TableColumn[];
+  minRows?: number;
+  maxRows?: number;
+}
+
+// TableRow maps the column names of a Table question to the values
+export type TableRow = Record<string, string | number | boolean>;
+
+export interface FilePathEntry {
+  name: string;
+  path: string;
+  isDir?: boolean;
+}
+
+export interface FilePathListing {
+  path: string;
+  parent?: string;
+  entries: FilePathEntry[];
+}
+
+export interface Problem {
+  id: string;
+  type?: ProblemType;
+  description?: string;
+  hints?: string[];
+  options?: string[];
+  default?: string | string[] | boolean | TableRow[];
+  answer?: string | string[] | boolean | TableRow[];
+  filePathOptions?: FilePathOptions;
+  tableOptions?: TableOptions;
+  // validationRegex is a regular expression that string answers must match
+  validationRegex?: string;
+  validationMessage?: string;
+  // timeout is a duration like 30s after which the default answer is used if the question is not answered
+  timeout?: string;
+  category?: string;
+  // group are the titles of the nested sections of related questions, starting with the outermost section
+  group?: string[];
+}
+
+export class APIError extends Error {
+  constructor(public readonly statusCode: number, message: string) {
+    super(`the server responded with the status ${statusCode} : ${message}`);
+  }
+}
+
+export class Client {
+  private readonly baseURL: string;
+
+  // baseURL is the url of the server including the /api/v1 prefix, like http://localhost:8080/api/v1
+  // authToken is sent as the bearer token if the server was started with --auth-token
+  constructor(baseURL: string, private readonly fetchFn: typeof fetch = fetch, private readonly authToken?: string) {
+    this.baseURL = baseURL.replace(/\/$/, '');
+  }
+
+  private async send(method: string, path: string, body?: BodyInit, contentType?: string): Promise<Response> {
+    const headers: { [key: string]: string } = {};
+    if (contentType) {
+      headers['Content-Type'] = contentType;
+    }
+    if (this.authToken) {
+      headers['Authorization'] = `Bearer ${this.authToken}`;
+    }
+    const resp = await this.fetchFn(this.baseURL + path, { method, body, headers });
+    if (resp.status >= 400) {
+      const text = await resp.text();
+      let message = text.trim();
+      try {
+        const errResp = JSON.parse(text);
+        if (errResp && errResp.error) {
+          message = errResp.error;
+        }
+      } catch (e) {
+        // not a json error response
+      }
+      throw new APIError(resp.status, message);
+    }
+    return resp;
+  }
+
+  private async json<T>(method: string, path: string, body?: unknown): Promise<T> {
+    const resp = body === undefined
+      ? await this.send(method, path)
+      : await this.send(method, path, JSON.stringify(body), 'application/json');
+    return resp.json() as Promise<T>;
+  }
+
+  private static workspacePath(id: string): string {
+    return `/workspaces/${encodeURIComponent(id)}`;
+  }
+
+  private static jobPath(id: string): string {
+    return `/jobs/${encodeURIComponent(id)}`;
+  }
+
+  async getOpenAPISpec(): Promise<string> {
+    return (await this.send('GET', '/openapi.yaml')).text();
+  }
+
+  getVersion(): Promise<VersionInfo> {
+    return this.json('GET', '/version');
+  }
+
+  listWorkspaces(): Promise<Workspace[]> {
+    return this.json('GET', '/workspaces');
+  }
+
+  createWorkspace(name: string): Promise<Workspace> {
+    return this.json('POST', '/workspaces', { name });
+  }
+
+  getWorkspace(id: string): Promise<Workspace> {
+    return this.json('GET', Client.workspacePath(id));
+  }
+
+  async deleteWorkspace(id: string): Promise<void> {
+    await this.send('DELETE', Client.workspacePath(id));
+  }
+
+  // uploadSource replaces the source with the contents of a zip, tar or tar.gz archive.
+  // The file name must have the extension of a supported archive format.
+  async uploadSource(id: string, fileName: string, archive: Blob): Promise<Workspace> {
+    const form = new FormData();
+    form.append('file', archive, fileName);
+    return (await this.send('PUT', `${Client.workspacePath(id)}/source`, form)).json();
+  }
+
+  startPlan(id: string): Promise<Job> {
+    return this.json('POST', `${Client.workspacePath(id)}/plan`);
+  }
+
+  async getPlan(id: string): Promise<string> {
+    return (await this.send('GET', `${Client.workspacePath(id)}/plan`)).text();
+  }
+
+  async updatePlan(id: string, plan: string): Promise<Workspace> {
+    return (await this.send('PUT', `${Client.workspacePath(id)}/plan`, plan, 'application/x-yaml')).json();
+  }
+
+  startTransform(id: string, options: TransformOptions = {}): Promise<Job> {
+    return this.json('POST', `${Client.workspacePath(id)}/transform`, options);
+  }
+
+  // getCurrentProblem blocks until the transformation asks the next question
+  getCurrentProblem(id: string): Promise<Problem> {
+    return this.json('GET', `${Client.workspacePath(id)}/problems/current`);
+  }
+
+  async postSolution(id: string, problem: Problem): Promise<void> {
+    await this.send('POST', `${Client.workspacePath(id)}/problems/current/solution`, JSON.stringify(problem), 'application/json');
+  }
+
+  // getCurrentProblemFiles lists a directory for the file browser of a FilePath question.
+  // The directory of the default answer is listed if the path is empty.
+  getCurrentProblemFiles(id: string, path = ''): Promise<FilePathListing> {
+    return this.json('GET', `${Client.workspacePath(id)}/problems/current/files?path=${encodeURIComponent(path)}`);
+  }
+
+  async downloadOutput(id: string): Promise<Blob> {
+    return (await this.send('GET', `${Client.workspacePath(id)}/output`)).blob();
+  }
+
+  async getLogs(id: string): Promise<string> {
+    return (await this.send('GET', `${Client.workspacePath(id)}/logs`)).text();
+  }
+
+  listJobs(workspaceID?: string): Promise<Job[]> {
+    const query = workspaceID ? `?workspace=${encodeURIComponent(workspaceID)}` : '';
+    return this.json('GET', `/jobs${query}`);
+  }
+
+  getJob(id: string): Promise<Job> {
+    return this.json('GET', Client.jobPath(id));
+  }
+
+  cancelJob(id: string): Promise<Job> {
+    return this.json('POST', `${Client.jobPath(id)}/cancel`);
+  }
+}
@@ -0,0 +1,526 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/types/info"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateWorkspaceRequestT is the body of the request to create a workspace
+type CreateWorkspaceRequestT struct {
+	Name string `json:"name"`
+}
+
+func sendJSON(w http.ResponseWriter, status int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		logrus.Errorf("failed to write the json response. Error: %q", err)
+	}
+}
+
+func sendError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if status >= http.StatusInternalServerError {
+		logrus.Error(msg)
+	} else {
+		logrus.Debug(msg)
+	}
+	sendJSON(w, status, map[string]string{"error": msg})
+}
+
+// getWorkspace returns the workspace in the request path. The caller must hold the lock.
+func (s *server) getWorkspace(w http.ResponseWriter, r *http.Request) (*WorkspaceT, bool) {
+	id := mux.Vars(r)["id"]
+	workspace, ok := s.workspaces[id]
+	if !ok {
+		sendError(w, http.StatusNotFound, "the workspace %s does not exist", id)
+	}
+	return workspace, ok
+}
+
+func (s *server) handleGetOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/x-yaml")
+	if _, err := w.Write(openAPISpec); err != nil {
+		logrus.Errorf("failed to write the OpenAPI document to the response. Error: %q", err)
+	}
+}
+
+func (s *server) handleGetVersion(w http.ResponseWriter, r *http.Request) {
+	sendJSON(w, http.StatusOK, info.GetVersionInfo())
+}
+
+func (s *server) handleListWorkspaces(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	workspaces := []WorkspaceT{}
+	for _, workspace := range s.workspaces {
+		workspaces = append(workspaces, *workspace)
+	}
+	sort.Slice(workspaces, func(i, j int) bool { return workspaces[i].Timestamp.Before(workspaces[j].Timestamp) })
+	sendJSON(w, http.StatusOK, workspaces)
+}
+
+func (s *server) handleCreateWorkspace(w http.ResponseWriter, r *http.Request) {
+	req := CreateWorkspaceRequestT{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		sendError(w, http.StatusBadRequest, "failed to decode the request body as json. Error: %q", err)
+		return
+	}
+	if req.Name == "" {
+		req.Name = common.DefaultProjectName
+	}
+	if name := common.NormalizeForMetadataName(req.Name); name != req.Name {
+		sendError(w, http.StatusBadRequest, "the workspace name '%s' is invalid. Use a valid kubernetes resource name like '%s'", req.Name, name)
+		return
+	}
+	id, err := newID()
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "%s", err)
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	workspace := &WorkspaceT{ID: id, Name: req.Name, Timestamp: time.Now().UTC(), Status: WorkspaceCreated}
+	if err := os.MkdirAll(s.getWorkspacePath(id), common.DefaultDirectoryPermission); err != nil {
+		sendError(w, http.StatusInternalServerError, "failed to create the workspace directory. Error: %q", err)
+		return
+	}
+	if err := s.saveWorkspace(workspace); err != nil {
+		sendError(w, http.StatusInternalServerError, "%s", err)
+		return
+	}
+	s.workspaces[id] = workspace
+	sendJSON(w, http.StatusCreated, workspace)
+}
+
+func (s *server) handleGetWorkspace(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	workspace, ok := s.getWorkspace(w, r)
+	if !ok {
+		return
+	}
+	sendJSON(w, http.StatusOK, workspace)
+}
+
+func (s *server) handleDeleteWorkspace(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	workspace, ok := s.getWorkspace(w, r)
+	if !ok {
+		return
+	}
+	if j, ok := s.jobs[workspace.JobID]; ok && !j.isFinished() {
+		if err := s.cancelJob(j); err != nil {
+			sendError(w, http.StatusInternalServerError, "failed to cancel the job running in the workspace. Error: %q", err)
+			return
+		}
+	}
+	delete(s.workspaces, workspace.ID)
+	if err := os.RemoveAll(s.getWorkspacePath(workspace.ID)); err != nil {
+		sendError(w, http.StatusInternalServerError, "failed to remove the workspace directory. Error: %q", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleUploadSource replaces the source in the workspace with the contents of an uploaded zip, tar or tar.gz archive.
+// The archive is sent as the "file" field of a multipart form.
+func (s *server) handleUploadSource(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "failed to get the archive from the 'file' field of the multipart form. Error: %q", err)
+		return
+	}
+	defer file.Close()
+	if !common.IsArchiveFile(header.Filename) {
+		sendError(w, http.StatusBadRequest, "the file %s is not a supported archive. Supported formats are zip, tar and tar.gz", header.Filename)
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	workspace, ok := s.getWorkspace(w, r)
+	if !ok {
+		return
+	}
+	if workspace.isRunning() {
+		sendError(w, http.StatusConflict, "the workspace %s is %s", workspace.ID, workspace.Status)
+		return
+	}
+	// keep the file name since the archive format is detected using the extension
+	archivePath := filepath.Join(s.getWorkspacePath(workspace.ID), "upload-"+filepath.Base(header.Filename))
+	defer os.Remove(archivePath)
+	archiveFile, err := os.Create(archivePath)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "failed to create the file %s . Error: %q", archivePath, err)
+		return
+	}
+	if _, err := io.Copy(archiveFile, file); err != nil {
+		archiveFile.Close()
+		sendError(w, http.StatusBadRequest, "failed to read the uploaded archive. Error: %q", err)
+		return
+	}
+	archiveFile.Close()
+	sourcePath := s.getSourcePath(workspace.ID)
+	if err := os.RemoveAll(sourcePath); err != nil {
+		sendError(w, http.StatusInternalServerError, "failed to remove the old source directory. Error: %q", err)
+		return
+	}
+	if err := common.ExtractArchive(archivePath, sourcePath); err != nil {
+		sendError(w, http.StatusBadRequest, "failed to extract the uploaded archive. Error: %q", err)
+		return
+	}
+	workspace.HasSource = true
+	if err := s.saveWorkspace(workspace); err != nil {
+		sendError(w, http.StatusInternalServerError, "%s", err)
+		return
+	}
+	sendJSON(w, http.StatusOK, workspace)
+}
+
+func (s *server) handleStartPlan(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	workspace, ok := s.getWorkspace(w, r)
+	if !ok {
+		return
+	}
+	if workspace.isRunning() {
+		sendError(w, http.StatusConflict, "the workspace %s is %s", workspace.ID, workspace.Status)
+		return
+	}
+	if !workspace.HasSource {
+		sendError(w, http.StatusBadRequest, "upload the source to the workspace %s before planning", workspace.ID)
+		return
+	}
+	j, err := s.startPlan(workspace)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "failed to start planning. Error: %q", err)
+		return
+	}
+	sendJSON(w, http.StatusAccepted, s.getJobView(j))
+}
+
+func (s *server) handleGetPlan(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	workspace, ok := s.getWorkspace(w, r)
+	if !ok {
+		return
+	}
+	data, err := os.ReadFile(s.getPlanPath(workspace.ID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			sendError(w, http.StatusNotFound, "the workspace %s does not have a plan", workspace.ID)
+			return
+		}
+		sendError(w, http.StatusInternalServerError, "failed to read the plan. Error: %q", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-yaml")
+	if _, err := w.Write(data); err != nil {
+		logrus.Errorf("failed to write the plan to the response. Error: %q", err)
+	}
+}
+
+// handleUpdatePlan replaces the plan in the workspace with the yaml in the request body
+func (s *server) handleUpdatePlan(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxUploadSize))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "failed to read the request body. Error: %q", err)
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	workspace, ok := s.getWorkspace(w, r)
+	if !ok {
+		return
+	}
+	if workspace.isRunning() {
+		sendError(w, http.StatusConflict, "the workspace %s is %s", workspace.ID, workspace.Status)
+		return
+	}
+	planPath := s.getPlanPath(workspace.ID)
+	newPlanPath := planPath + ".new"
+	if err := os.WriteFile(newPlanPath, data, common.DefaultFilePermission); err != nil {
+		sendError(w, http.StatusInternalServerError, "failed to write the plan. Error: %q", err)
+		return
+	}
+	plan := plantypes.Plan{}
+	if err := common.ReadMove2KubeYaml(newPlanPath, &plan); err != nil || plan.Kind != string(plantypes.PlanKind) {
+		os.Remove(newPlanPath)
+		sendError(w, http.StatusBadRequest, "the request body is not a valid plan. Error: %v", err)
+		return
+	}
+	if err := s.checkPlanPaths(workspace.ID, plan); err != nil {
+		os.Remove(newPlanPath)
+		sendError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	if err := s.checkPlanCapabilities(plan); err != nil {
+		os.Remove(newPlanPath)
+		sendError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	if err := os.Rename(newPlanPath, planPath); err != nil {
+		sendError(w, http.StatusInternalServerError, "failed to replace the plan. Error: %q", err)
+		return
+	}
+	workspace.Status = WorkspacePlanned
+	workspace.Error = ""
+	if err := s.saveWorkspace(workspace); err != nil {
+		sendError(w, http.StatusInternalServerError, "%s", err)
+		return
+	}
+	sendJSON(w, http.StatusOK, workspace)
+}
+
+// checkPlanPaths makes sure that the source and customizations directories of the plan are inside the workspace,
+// since the transformation reads the source and runs the transformers in the customizations
+func (s *server) checkPlanPaths(workspaceID string, plan plantypes.Plan) error {
+	workspacePath := s.getWorkspacePath(workspaceID)
+	if realWorkspacePath, err := filepath.EvalSymlinks(workspacePath); err == nil {
+		workspacePath = realWorkspacePath
+	}
+	dirs := map[string]string{"sourceDir": plan.Spec.SourceDir, "customizationsDir": plan.Spec.CustomizationsDir}
+	for field, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		// the jobs run in the workspace directory, so the relative paths are relative to it
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(workspacePath, dir)
+		}
+		if realDir, err := filepath.EvalSymlinks(dir); err == nil {
+			dir = realDir
+		}
+		if !common.IsParent(dir, workspacePath) {
+			return fmt.Errorf("the %s %s of the plan is outside the workspace %s", field, dirs[field], workspaceID)
+		}
+	}
+	return nil
+}
+
+// checkPlanCapabilities rejects the plans that run commands on the server or make it fetch remote urls,
+// unless the server allows them
+func (s *server) checkPlanCapabilities(plan plantypes.Plan) error {
+	hooks := plan.Spec.Hooks
+	if !s.config.AllowLocalExecution && len(hooks.PreDetect)+len(hooks.PerService)+len(hooks.PostTransform) != 0 {
+		return fmt.Errorf("the plan has hooks, which are not allowed since local execution is disabled on the server")
+	}
+	if !s.config.AllowRemoteURLs {
+		urls := map[string]string{"sourceURL": plan.Spec.SourceURL, "customizationsURL": plan.Spec.CustomizationsURL}
+		for _, field := range []string{"sourceURL", "customizationsURL"} {
+			if urls[field] != "" {
+				return fmt.Errorf("the %s %s of the plan is not allowed since remote urls are disabled on the server", field, urls[field])
+			}
+		}
+	}
+	return nil
+}
+
+// handleStartTransform starts the transformation. The optional body contains the TransformOptionsT.
+func (s *server) handleStartTransform(w http.ResponseWriter, r *http.Request) {
+	options := TransformOptionsT{}
+	if err := json.NewDecoder(r.Body).Decode(&options); err != nil && err != io.EOF {
+		sendError(w, http.StatusBadRequest, "failed to decode the request body as json. Error: %q", err)
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	workspace, ok := s.getWorkspace(w, r)
+	if !ok {
+		return
+	}
+	if workspace.isRunning() {
+		sendError(w, http.StatusConflict, "the workspace %s is %s", workspace.ID, workspace.Status)
+		return
+	}
+	if _, err := os.Stat(s.getPlanPath(workspace.ID)); err != nil {
+		sendError(w, http.StatusBadRequest, "create a plan in the workspace %s before transforming", workspace.ID)
+		return
+	}
+	j, err := s.startTransform(workspace, options)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "failed to start the transformation. Error: %q", err)
+		return
+	}
+	sendJSON(w, http.StatusAccepted, s.getJobView(j))
+}
+
+// getQAURL returns the url of the QA engine of the transformation running in the workspace
+func (s *server) getQAURL(w http.ResponseWriter, r *http.Request, path string) (string, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	workspace, ok := s.getWorkspace(w, r)
+	if !ok {
+		return "", false
+	}
+	j, ok := s.jobs[workspace.JobID]
+	if !ok || j.Status != JobRunning || j.qaPort == 0 {
+		sendError(w, http.StatusNotFound, "there is no transformation waiting for answers in the workspace %s", workspace.ID)
+		return "", false
+	}
+	return fmt.Sprintf("http://localhost:%d%s", j.qaPort, path), true
+}
+
+// proxyQA forwards the request to the QA engine of the transformation.
+// The lock is not held while waiting since the QA engine blocks until the next question is asked.
+func (s *server) proxyQA(w http.ResponseWriter, r *http.Request, method, path string) {
+	url, ok := s.getQAURL(w, r, path)
+	if !ok {
+		return
+	}
+	req, err := http.NewRequestWithContext(r.Context(), method, url, r.Body)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "failed to create the request to the QA engine. Error: %q", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		sendError(w, http.StatusServiceUnavailable, "the QA engine of the transformation is not available yet. Error: %q", err)
+		return
+	}
+	defer resp.Body.Close()
+	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+	w.WriteHeader(resp.StatusCode)
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		logrus.Errorf("failed to copy the response from the QA engine. Error: %q", err)
+	}
+}
+
+func (s *server) handleGetCurrentProblem(w http.ResponseWriter, r *http.Request) {
+	s.proxyQA(w, r, http.MethodGet, "/problems/current")
+}
+
+// handleGetCurrentProblemFiles lists a directory for picking the answer to the current FilePath question
+func (s *server) handleGetCurrentProblemFiles(w http.ResponseWriter, r *http.Request) {
+	s.proxyQA(w, r, http.MethodGet, "/problems/current/files?"+r.URL.RawQuery)
+}
+
+func (s *server) handlePostSolution(w http.ResponseWriter, r *http.Request) {
+	s.proxyQA(w, r, http.MethodPost, "/problems/current/solution")
+}
+
+// handleDownloadOutput sends the output of the transformation as a zip archive
+func (s *server) handleDownloadOutput(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	workspace, ok := s.getWorkspace(w, r)
+	if !ok {
+		s.mutex.Unlock()
+		return
+	}
+	if workspace.Status != WorkspaceTransformed {
+		s.mutex.Unlock()
+		sendError(w, http.StatusNotFound, "the workspace %s does not have any output. The workspace is %s", workspace.ID, workspace.Status)
+		return
+	}
+	id, name := workspace.ID, workspace.Name
+	s.mutex.Unlock()
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".zip"))
+	if err := writeZipArchive(w, s.getOutputPath(id)); err != nil {
+		logrus.Errorf("failed to send the output of the workspace %s . Error: %q", id, err)
+	}
+}
+
+func (s *server) handleGetLogs(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	workspace, ok := s.getWorkspace(w, r)
+	s.mutex.Unlock()
+	if !ok {
+		return
+	}
+	f, err := os.Open(s.getLogPath(workspace.ID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			sendError(w, http.StatusNotFound, "nothing has been run in the workspace %s", workspace.ID)
+			return
+		}
+		sendError(w, http.StatusInternalServerError, "failed to open the log file. Error: %q", err)
+		return
+	}
+	defer f.Close()
+	w.Header().Set("Content-Type", "text/plain")
+	if _, err := io.Copy(w, f); err != nil {
+		logrus.Errorf("failed to send the logs of the workspace %s . Error: %q", workspace.ID, err)
+	}
+}
+
+// handleListJobs returns the jobs, optionally filtered by the workspace query parameter
+func (s *server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	workspaceID := r.URL.Query().Get("workspace")
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	jobs := []JobT{}
+	for _, j := range s.jobs {
+		if workspaceID != "" && j.WorkspaceID != workspaceID {
+			continue
+		}
+		jobs = append(jobs, s.getJobView(j))
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.Before(jobs[j].CreatedAt) })
+	sendJSON(w, http.StatusOK, jobs)
+}
+
+func (s *server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	id := mux.Vars(r)["id"]
+	j, ok := s.jobs[id]
+	if !ok {
+		sendError(w, http.StatusNotFound, "the job %s does not exist", id)
+		return
+	}
+	sendJSON(w, http.StatusOK, s.getJobView(j))
+}
+
+func (s *server) handleCancelJob(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	id := mux.Vars(r)["id"]
+	j, ok := s.jobs[id]
+	if !ok {
+		sendError(w, http.StatusNotFound, "the job %s does not exist", id)
+		return
+	}
+	if j.isFinished() {
+		sendError(w, http.StatusConflict, "the job %s has already finished", id)
+		return
+	}
+	if err := s.cancelJob(j); err != nil {
+		sendError(w, http.StatusInternalServerError, "failed to cancel the job %s . Error: %q", id, err)
+		return
+	}
+	sendJSON(w, http.StatusOK, s.getJobView(j))
+}
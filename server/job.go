@@ -0,0 +1,291 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package server
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/types"
+	"github.com/phayes/freeport"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+)
+
+// JobTypeT is the type of a job
+type JobTypeT string
+
+const (
+	// PlanJob creates the plan for the source in the workspace
+	PlanJob JobTypeT = "plan"
+	// TransformJob transforms the source in the workspace using the plan
+	TransformJob JobTypeT = "transform"
+)
+
+// JobStatusT is the status of a job
+type JobStatusT string
+
+const (
+	// JobQueued means the job is waiting for a free slot
+	JobQueued JobStatusT = "queued"
+	// JobRunning means the job is running
+	JobRunning JobStatusT = "running"
+	// JobSucceeded means the job finished successfully
+	JobSucceeded JobStatusT = "succeeded"
+	// JobFailed means the job failed
+	JobFailed JobStatusT = "failed"
+	// JobCancelled means the job was cancelled before it finished
+	JobCancelled JobStatusT = "cancelled"
+)
+
+// JobT is a plan or transform running in the background.
+// The engine keeps a lot of global state, so every job runs in its own process.
+type JobT struct {
+	ID          string           `json:"id"`
+	WorkspaceID string           `json:"workspaceID"`
+	Type        JobTypeT         `json:"type"`
+	Status      JobStatusT       `json:"status"`
+	Progress    common.ProgressT `json:"progress"`
+	Error       string           `json:"error,omitempty"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	StartedAt   *time.Time       `json:"startedAt,omitempty"`
+	FinishedAt  *time.Time       `json:"finishedAt,omitempty"`
+	args        []string
+	qaPort      int
+	cmd         *exec.Cmd
+}
+
+// TransformOptionsT are the options that can be given while starting a transformation
+type TransformOptionsT struct {
+	QASkip     bool     `json:"qaSkip,omitempty"`
+	SetConfigs []string `json:"setConfigs,omitempty"`
+	Presets    []string `json:"presets,omitempty"`
+}
+
+func (j *JobT) isFinished() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed || j.Status == JobCancelled
+}
+
+func (s *server) getProgressPath(j *JobT) string {
+	return filepath.Join(s.getWorkspacePath(j.WorkspaceID), "progress-"+j.ID+".json")
+}
+
+// getJobView returns a copy of the job with the latest progress. The caller must hold the lock.
+func (s *server) getJobView(j *JobT) JobT {
+	if j.Status == JobRunning {
+		if progress, err := common.ReadProgressFile(s.getProgressPath(j)); err == nil {
+			j.Progress = progress
+		}
+	}
+	return *j
+}
+
+// enqueueJob adds a job for the workspace to the queue. The caller must hold the lock.
+func (s *server) enqueueJob(w *WorkspaceT, jobType JobTypeT, args []string, qaPort int) (*JobT, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	j := &JobT{ID: id, WorkspaceID: w.ID, Type: jobType, Status: JobQueued, CreatedAt: time.Now().UTC(), args: args, qaPort: qaPort}
+	if jobType == PlanJob {
+		w.Status = WorkspacePlanning
+	} else {
+		w.Status = WorkspaceTransforming
+	}
+	w.Error = ""
+	w.JobID = j.ID
+	if err := s.saveWorkspace(w); err != nil {
+		logrus.Errorf("failed to save the workspace %s . Error: %q", w.ID, err)
+	}
+	s.jobs[j.ID] = j
+	s.queue = append(s.queue, j)
+	s.scheduleJobs()
+	return j, nil
+}
+
+// scheduleJobs starts the queued jobs while there are free slots. The caller must hold the lock.
+func (s *server) scheduleJobs() {
+	for len(s.queue) > 0 && s.numRunningJobs < s.config.MaxParallelJobs {
+		j := s.queue[0]
+		s.queue = s.queue[1:]
+		if err := s.startJob(j); err != nil {
+			logrus.Errorf("failed to start the job %s . Error: %q", j.ID, err)
+			s.finishJob(j, JobFailed, err.Error())
+		}
+	}
+}
+
+// getJobArgs returns the arguments of the child process running the job
+func (s *server) getJobArgs(j *JobT) []string {
+	args := append([]string{}, j.args...)
+	args = append(args, "--progress-file", s.getProgressPath(j), "--log-level", logrus.GetLevel().String())
+	if !s.config.AllowLocalExecution {
+		args = append(args, "--"+common.DisableLocalExecutionFlag)
+	}
+	return args
+}
+
+// startJob runs the job in a child process. The caller must hold the lock.
+func (s *server) startJob(j *JobT) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to get the path of the %s executable. Error: %w", types.AppName, err)
+	}
+	logFile, err := os.OpenFile(s.getLogPath(j.WorkspaceID), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, common.DefaultFilePermission)
+	if err != nil {
+		return fmt.Errorf("failed to create the log file for the workspace %s . Error: %w", j.WorkspaceID, err)
+	}
+	j.cmd = exec.Command(exe, s.getJobArgs(j)...)
+	j.cmd.Dir = s.getWorkspacePath(j.WorkspaceID)
+	j.cmd.Stdout = logFile
+	j.cmd.Stderr = logFile
+	if err := j.cmd.Start(); err != nil {
+		logFile.Close()
+		return fmt.Errorf("failed to start the %s command in the workspace %s . Error: %w", j.Type, j.WorkspaceID, err)
+	}
+	now := time.Now().UTC()
+	j.StartedAt = &now
+	j.Status = JobRunning
+	s.numRunningJobs++
+	logrus.Infof("started the %s job %s in the workspace %s", j.Type, j.ID, j.WorkspaceID)
+	go func() {
+		err := j.cmd.Wait()
+		logFile.Close()
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.numRunningJobs--
+		if j.Status == JobCancelled {
+			s.scheduleJobs()
+			return
+		}
+		if err != nil {
+			s.finishJob(j, JobFailed, fmt.Sprintf("the %s command failed. Check the logs for details. Error: %s", j.Type, err))
+		} else {
+			s.finishJob(j, JobSucceeded, "")
+		}
+		s.scheduleJobs()
+	}()
+	return nil
+}
+
+// finishJob records the final status of the job and updates its workspace. The caller must hold the lock.
+func (s *server) finishJob(j *JobT, status JobStatusT, errMsg string) {
+	if progress, err := common.ReadProgressFile(s.getProgressPath(j)); err == nil {
+		j.Progress = progress
+	}
+	os.Remove(s.getProgressPath(j))
+	now := time.Now().UTC()
+	j.FinishedAt = &now
+	j.Status = status
+	j.Error = errMsg
+	logrus.Infof("the %s job %s in the workspace %s is %s", j.Type, j.ID, j.WorkspaceID, status)
+	w, ok := s.workspaces[j.WorkspaceID]
+	if !ok || w.JobID != j.ID {
+		// the workspace was deleted while the job was running
+		return
+	}
+	switch {
+	case status == JobSucceeded && j.Type == PlanJob:
+		w.Status = WorkspacePlanned
+	case status == JobSucceeded && j.Type == TransformJob:
+		w.Status = WorkspaceTransformed
+	default:
+		w.Status = WorkspaceFailed
+		w.Error = fmt.Sprintf("the %s job %s is %s", j.Type, j.ID, status)
+		if errMsg != "" {
+			w.Error += " : " + errMsg
+		}
+	}
+	if err := s.saveWorkspace(w); err != nil {
+		logrus.Errorf("failed to save the workspace %s . Error: %q", w.ID, err)
+	}
+}
+
+// cancelJob stops a queued or running job. The caller must hold the lock.
+func (s *server) cancelJob(j *JobT) error {
+	switch j.Status {
+	case JobQueued:
+		for i, queued := range s.queue {
+			if queued == j {
+				s.queue = append(s.queue[:i], s.queue[i+1:]...)
+				break
+			}
+		}
+	case JobRunning:
+		if err := j.cmd.Process.Kill(); err != nil {
+			return fmt.Errorf("failed to kill the process of the job %s . Error: %w", j.ID, err)
+		}
+	default:
+		return fmt.Errorf("the job %s has already finished", j.ID)
+	}
+	s.finishJob(j, JobCancelled, "")
+	return nil
+}
+
+// removeOldJobs forgets the finished jobs that are older than the retention period
+func (s *server) removeOldJobs() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	cutoff := time.Now().UTC().Add(-s.config.JobRetention)
+	for id, j := range s.jobs {
+		if j.isFinished() && j.FinishedAt.Before(cutoff) {
+			logrus.Debugf("removing the %s job %s that finished at %s", j.Type, j.ID, j.FinishedAt)
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// startPlan queues a job to run the plan command on the uploaded source. The caller must hold the lock.
+func (s *server) startPlan(w *WorkspaceT) (*JobT, error) {
+	args := []string{"plan", "--source", s.getSourcePath(w.ID), "--plan", s.getPlanPath(w.ID), "--name", w.Name}
+	return s.enqueueJob(w, PlanJob, args, 0)
+}
+
+// startTransform queues a job to run the transform command using the plan in the workspace.
+// Unless the questions are skipped, they are answered through the QA endpoints of the workspace.
+// The caller must hold the lock.
+func (s *server) startTransform(w *WorkspaceT, options TransformOptionsT) (*JobT, error) {
+	outputPath := s.getOutputPath(w.ID)
+	if err := os.RemoveAll(outputPath); err != nil {
+		return nil, fmt.Errorf("failed to remove the old output directory %s . Error: %w", outputPath, err)
+	}
+	if err := os.MkdirAll(outputPath, common.DefaultDirectoryPermission); err != nil {
+		return nil, fmt.Errorf("failed to create the output directory %s . Error: %w", outputPath, err)
+	}
+	args := []string{"transform", "--plan", s.getPlanPath(w.ID), "--output", outputPath, "--overwrite"}
+	for _, setConfig := range options.SetConfigs {
+		args = append(args, "--set-config", setConfig)
+	}
+	for _, preset := range options.Presets {
+		args = append(args, "--preset", preset)
+	}
+	qaPort := 0
+	if options.QASkip {
+		args = append(args, "--qa-skip")
+	} else {
+		var err error
+		if qaPort, err = freeport.GetFreePort(); err != nil {
+			return nil, fmt.Errorf("failed to find a free port for the QA engine. Error: %w", err)
+		}
+		args = append(args, "--qa-disable-cli", "--qa-port", cast.ToString(qaPort))
+	}
+	return s.enqueueJob(w, TransformJob, args, qaPort)
+}
@@ -0,0 +1,73 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package server
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+	"gopkg.in/yaml.v3"
+)
+
+// TestOpenAPISpecMatchesRoutes makes sure the OpenAPI document does not drift from the routes the server handles
+func TestOpenAPISpecMatchesRoutes(t *testing.T) {
+	spec := struct {
+		Paths map[string]map[string]interface{} `yaml:"paths"`
+	}{}
+	if err := yaml.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatalf("failed to parse the OpenAPI document. Error: %q", err)
+	}
+	specOperations := []string{}
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if method == "parameters" {
+				continue
+			}
+			specOperations = append(specOperations, strings.ToUpper(method)+" "+path)
+		}
+	}
+	s, err := newServer(ConfigT{WorkspacesPath: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create the server. Error: %q", err)
+	}
+	routes := []string{}
+	err = s.getRouter().Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		for _, method := range methods {
+			routes = append(routes, method+" "+strings.TrimPrefix(path, apiURLPrefix))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk the routes. Error: %q", err)
+	}
+	sort.Strings(specOperations)
+	sort.Strings(routes)
+	if !cmp.Equal(specOperations, routes) {
+		t.Fatalf("the OpenAPI document does not match the routes. Differences:\n%s", cmp.Diff(specOperations, routes))
+	}
+}
@@ -0,0 +1,173 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package server
+
+import (
+	"crypto/subtle"
+	_ "embed"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/konveyor/move2kube/common"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+)
+
+const (
+	apiURLPrefix = "/api/v1"
+	// maxUploadSize is the maximum size of an uploaded source archive
+	maxUploadSize = 1 << 30
+	// jobRetentionCheckInterval is how often the finished jobs are checked against the retention period
+	jobRetentionCheckInterval = time.Minute
+)
+
+// openAPISpec is the OpenAPI document describing the API
+//
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+// ConfigT is the configuration of the server
+type ConfigT struct {
+	// Host is the address the server listens on. The default localhost only accepts local connections.
+	Host string
+	Port int32
+	// AuthToken is the bearer token required in the Authorization header of every request, if not empty
+	AuthToken string
+	// WorkspacesPath is the directory where the uploaded sources, plans and outputs are stored
+	WorkspacesPath string
+	// MaxParallelJobs is the number of plan and transform jobs that can run at the same time. The rest are queued.
+	MaxParallelJobs int
+	// JobRetention is how long the finished jobs can be queried for
+	JobRetention time.Duration
+	// AllowLocalExecution lets the jobs execute programs on the server, like the hooks of the uploaded plans.
+	// By default the jobs run with local execution disabled and plans with hooks are rejected.
+	AllowLocalExecution bool
+	// AllowRemoteURLs lets the uploaded plans fetch their source and customizations from remote urls
+	AllowRemoteURLs bool
+}
+
+// server serves the plan, transform and QA functionality over a REST API
+type server struct {
+	config         ConfigT
+	workspacesPath string
+	mutex          sync.Mutex
+	workspaces     map[string]*WorkspaceT
+	jobs           map[string]*JobT
+	queue          []*JobT
+	numRunningJobs int
+}
+
+func newServer(config ConfigT) (*server, error) {
+	if config.MaxParallelJobs < 1 {
+		config.MaxParallelJobs = 1
+	}
+	if err := os.MkdirAll(config.WorkspacesPath, common.DefaultDirectoryPermission); err != nil {
+		return nil, fmt.Errorf("failed to create the workspaces directory %s . Error: %w", config.WorkspacesPath, err)
+	}
+	s := &server{config: config, workspacesPath: config.WorkspacesPath, workspaces: map[string]*WorkspaceT{}, jobs: map[string]*JobT{}}
+	if err := s.loadWorkspaces(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *server) getRouter() *mux.Router {
+	router := mux.NewRouter()
+	api := router.PathPrefix(apiURLPrefix).Subrouter()
+	api.HandleFunc("/openapi.yaml", s.handleGetOpenAPISpec).Methods("GET")
+	api.HandleFunc("/version", s.handleGetVersion).Methods("GET")
+	api.HandleFunc("/workspaces", s.handleListWorkspaces).Methods("GET")
+	api.HandleFunc("/workspaces", s.handleCreateWorkspace).Methods("POST")
+	api.HandleFunc("/workspaces/{id}", s.handleGetWorkspace).Methods("GET")
+	api.HandleFunc("/workspaces/{id}", s.handleDeleteWorkspace).Methods("DELETE")
+	api.HandleFunc("/workspaces/{id}/source", s.handleUploadSource).Methods("PUT")
+	api.HandleFunc("/workspaces/{id}/plan", s.handleStartPlan).Methods("POST")
+	api.HandleFunc("/workspaces/{id}/plan", s.handleGetPlan).Methods("GET")
+	api.HandleFunc("/workspaces/{id}/plan", s.handleUpdatePlan).Methods("PUT")
+	api.HandleFunc("/workspaces/{id}/transform", s.handleStartTransform).Methods("POST")
+	api.HandleFunc("/workspaces/{id}/problems/current", s.handleGetCurrentProblem).Methods("GET")
+	api.HandleFunc("/workspaces/{id}/problems/current/solution", s.handlePostSolution).Methods("POST")
+	api.HandleFunc("/workspaces/{id}/problems/current/files", s.handleGetCurrentProblemFiles).Methods("GET")
+	api.HandleFunc("/workspaces/{id}/output", s.handleDownloadOutput).Methods("GET")
+	api.HandleFunc("/workspaces/{id}/logs", s.handleGetLogs).Methods("GET")
+	api.HandleFunc("/jobs", s.handleListJobs).Methods("GET")
+	api.HandleFunc("/jobs/{id}", s.handleGetJob).Methods("GET")
+	api.HandleFunc("/jobs/{id}/cancel", s.handleCancelJob).Methods("POST")
+	if s.config.AuthToken != "" {
+		router.Use(s.authenticate)
+	}
+	return router
+}
+
+// authenticate rejects the requests that do not have the auth token as the bearer token
+func (s *server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		token := strings.TrimPrefix(authorization, "Bearer ")
+		if token == authorization || subtle.ConstantTimeCompare([]byte(token), []byte(s.config.AuthToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			sendError(w, http.StatusUnauthorized, "the request does not have a valid bearer token in the Authorization header")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// NewHandler returns a handler serving the REST API, so that it can be embedded in other servers
+func NewHandler(config ConfigT) (http.Handler, error) {
+	s, err := newServer(config)
+	if err != nil {
+		return nil, err
+	}
+	if s.config.JobRetention > 0 {
+		go func() {
+			for range time.Tick(jobRetentionCheckInterval) {
+				s.removeOldJobs()
+			}
+		}()
+	}
+	return s.getRouter(), nil
+}
+
+// StartServer starts the REST API server
+func StartServer(config ConfigT) error {
+	handler, err := NewHandler(config)
+	if err != nil {
+		return err
+	}
+	if config.Host == "" {
+		config.Host = "localhost"
+	}
+	if config.AuthToken == "" && config.Host != "localhost" && config.Host != "127.0.0.1" && config.Host != "::1" {
+		logrus.Warnf("The server is listening on %s without an auth token. Anyone who can reach it can run transformations on this machine.", config.Host)
+	}
+	addr := net.JoinHostPort(config.Host, cast.ToString(config.Port))
+	httpServer := &http.Server{
+		Handler: handler,
+		Addr:    addr,
+		// no write timeout since the QA endpoints wait for the next question and the output archives can be large
+		ReadHeaderTimeout: 15 * time.Second,
+	}
+	logrus.Infof("Listening on http://%s%s", addr, apiURLPrefix)
+	return httpServer.ListenAndServe()
+}
@@ -0,0 +1,281 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+func TestWorkspaceLifecycle(t *testing.T) {
+	workspacesPath := t.TempDir()
+	s, err := newServer(ConfigT{WorkspacesPath: workspacesPath})
+	if err != nil {
+		t.Fatalf("failed to create the server. Error: %q", err)
+	}
+	router := s.getRouter()
+	do := func(method, path, contentType string, body *bytes.Buffer) *httptest.ResponseRecorder {
+		if body == nil {
+			body = &bytes.Buffer{}
+		}
+		req := httptest.NewRequest(method, apiURLPrefix+path, body)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/workspaces", "application/json", bytes.NewBufferString(`{"name": "Not Valid"}`))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected an invalid name to be rejected. Actual status: %d", rec.Code)
+	}
+	rec = do(http.MethodPost, "/workspaces", "application/json", bytes.NewBufferString(`{"name": "myproject"}`))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("failed to create the workspace. Status: %d Body: %s", rec.Code, rec.Body.String())
+	}
+	workspace := WorkspaceT{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &workspace); err != nil {
+		t.Fatalf("failed to decode the workspace. Error: %q", err)
+	}
+	if workspace.Name != "myproject" || workspace.Status != WorkspaceCreated || workspace.HasSource {
+		t.Fatalf("the created workspace is incorrect. Actual: %+v", workspace)
+	}
+
+	if rec := do(http.MethodPost, "/workspaces/"+workspace.ID+"/plan", "", nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected planning without a source to fail. Actual status: %d", rec.Code)
+	}
+
+	archive := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(archive)
+	entryWriter, err := zipWriter.Create("app/main.py")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := entryWriter.Write([]byte("print('hello')\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	form := &bytes.Buffer{}
+	formWriter := multipart.NewWriter(form)
+	fileWriter, err := formWriter.CreateFormFile("file", "source.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fileWriter.Write(archive.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := formWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if rec := do(http.MethodPut, "/workspaces/"+workspace.ID+"/source", formWriter.FormDataContentType(), form); rec.Code != http.StatusOK {
+		t.Fatalf("failed to upload the source. Status: %d Body: %s", rec.Code, rec.Body.String())
+	}
+	if _, err := os.Stat(filepath.Join(workspacesPath, workspace.ID, workspaceSourceDir, "app", "main.py")); err != nil {
+		t.Fatalf("the uploaded source was not extracted. Error: %q", err)
+	}
+
+	if rec := do(http.MethodGet, "/workspaces/"+workspace.ID+"/plan", "", nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected no plan. Actual status: %d", rec.Code)
+	}
+	if rec := do(http.MethodPut, "/workspaces/"+workspace.ID+"/plan", "application/x-yaml", bytes.NewBufferString("foo: bar\n")); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected an invalid plan to be rejected. Actual status: %d", rec.Code)
+	}
+	outsidePlan := "apiVersion: move2kube.konveyor.io/v1alpha1\nkind: Plan\nspec:\n  sourceDir: ../../\n"
+	if rec := do(http.MethodPut, "/workspaces/"+workspace.ID+"/plan", "application/x-yaml", bytes.NewBufferString(outsidePlan)); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a plan with a source directory outside the workspace to be rejected. Actual status: %d", rec.Code)
+	}
+	insidePlan := "apiVersion: move2kube.konveyor.io/v1alpha1\nkind: Plan\nspec:\n  sourceDir: " + workspaceSourceDir + "\n"
+	if rec := do(http.MethodPut, "/workspaces/"+workspace.ID+"/plan", "application/x-yaml", bytes.NewBufferString(insidePlan)); rec.Code != http.StatusOK {
+		t.Fatalf("failed to update the plan. Status: %d Body: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodGet, "/workspaces/"+workspace.ID+"/output", "", nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected no output. Actual status: %d", rec.Code)
+	}
+
+	// the workspaces should survive a restart of the server
+	s2, err := newServer(ConfigT{WorkspacesPath: workspacesPath})
+	if err != nil {
+		t.Fatalf("failed to recreate the server. Error: %q", err)
+	}
+	if w, ok := s2.workspaces[workspace.ID]; !ok || !w.HasSource {
+		t.Fatalf("the workspace was not loaded after a restart. Actual: %+v", s2.workspaces)
+	}
+
+	if rec := do(http.MethodDelete, "/workspaces/"+workspace.ID, "", nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("failed to delete the workspace. Status: %d", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/workspaces/"+workspace.ID, "", nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected the workspace to be deleted. Actual status: %d", rec.Code)
+	}
+}
+
+func TestAuthToken(t *testing.T) {
+	s, err := newServer(ConfigT{WorkspacesPath: t.TempDir(), AuthToken: "secret"})
+	if err != nil {
+		t.Fatalf("failed to create the server. Error: %q", err)
+	}
+	router := s.getRouter()
+	testcases := []struct {
+		authorization string
+		status        int
+	}{
+		{authorization: "", status: http.StatusUnauthorized},
+		{authorization: "Bearer wrong", status: http.StatusUnauthorized},
+		{authorization: "secret", status: http.StatusUnauthorized},
+		{authorization: "Bearer secret", status: http.StatusOK},
+	}
+	for _, tc := range testcases {
+		req := httptest.NewRequest(http.MethodGet, apiURLPrefix+"/workspaces", nil)
+		if tc.authorization != "" {
+			req.Header.Set("Authorization", tc.authorization)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != tc.status {
+			t.Fatalf("unexpected status for the Authorization header '%s'. Expected: %d Actual: %d", tc.authorization, tc.status, rec.Code)
+		}
+	}
+}
+
+func TestWriteZipArchive(t *testing.T) {
+	srcPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(srcPath, "deploy", "yamls"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(srcPath, "deploy", "yamls", "svc.yaml"), []byte("kind: Service\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := writeZipArchive(buf, srcPath); err != nil {
+		t.Fatalf("failed to create the zip archive. Error: %q", err)
+	}
+	zipReader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to read the zip archive. Error: %q", err)
+	}
+	names := []string{}
+	for _, f := range zipReader.File {
+		names = append(names, f.Name)
+	}
+	want := "deploy/,deploy/yamls/,deploy/yamls/svc.yaml"
+	if strings.Join(names, ",") != want {
+		t.Fatalf("the zip archive entries are incorrect. Expected: %s Actual: %s", want, strings.Join(names, ","))
+	}
+}
+
+func TestJobQueue(t *testing.T) {
+	s, err := newServer(ConfigT{WorkspacesPath: t.TempDir(), MaxParallelJobs: 1, JobRetention: time.Hour})
+	if err != nil {
+		t.Fatalf("failed to create the server. Error: %q", err)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	// pretend all the slots are taken so that the jobs stay queued
+	s.numRunningJobs = 1
+	workspace := &WorkspaceT{ID: "ws1", Name: "myproject", Status: WorkspaceCreated, HasSource: true}
+	if err := os.MkdirAll(s.getWorkspacePath(workspace.ID), 0755); err != nil {
+		t.Fatal(err)
+	}
+	s.workspaces[workspace.ID] = workspace
+	j, err := s.startPlan(workspace)
+	if err != nil {
+		t.Fatalf("failed to queue the plan job. Error: %q", err)
+	}
+	if j.Status != JobQueued || len(s.queue) != 1 || workspace.Status != WorkspacePlanning || workspace.JobID != j.ID {
+		t.Fatalf("the job was not queued correctly. Job: %+v Workspace: %+v", j, workspace)
+	}
+	if err := s.cancelJob(j); err != nil {
+		t.Fatalf("failed to cancel the queued job. Error: %q", err)
+	}
+	if j.Status != JobCancelled || len(s.queue) != 0 || workspace.Status != WorkspaceFailed {
+		t.Fatalf("the job was not cancelled correctly. Job: %+v Workspace: %+v", j, workspace)
+	}
+	if err := s.cancelJob(j); err == nil {
+		t.Fatalf("expected an error while cancelling a finished job")
+	}
+
+	finishedAt := time.Now().UTC().Add(-2 * time.Hour)
+	j.FinishedAt = &finishedAt
+	s.mutex.Unlock()
+	s.removeOldJobs()
+	s.mutex.Lock()
+	if _, ok := s.jobs[j.ID]; ok {
+		t.Fatalf("expected the old job to be removed")
+	}
+}
+
+func TestCheckPlanCapabilities(t *testing.T) {
+	hooksPlan := plantypes.NewPlan()
+	hooksPlan.Spec.Hooks.PostTransform = []plantypes.Hook{{Script: "echo hi"}}
+	remotePlan := plantypes.NewPlan()
+	remotePlan.Spec.SourceURL = "https://github.com/konveyor/move2kube-demos.git"
+	customizationsPlan := plantypes.NewPlan()
+	customizationsPlan.Spec.CustomizationsURL = "https://example.com/customizations.zip"
+	testcases := []struct {
+		name    string
+		config  ConfigT
+		plan    plantypes.Plan
+		wantErr bool
+	}{
+		{name: "plain plan", plan: plantypes.NewPlan()},
+		{name: "hooks", plan: hooksPlan, wantErr: true},
+		{name: "hooks allowed", config: ConfigT{AllowLocalExecution: true}, plan: hooksPlan},
+		{name: "remote source", plan: remotePlan, wantErr: true},
+		{name: "remote customizations", plan: customizationsPlan, wantErr: true},
+		{name: "remote urls allowed", config: ConfigT{AllowRemoteURLs: true}, plan: remotePlan},
+		{name: "hooks are not allowed by remote urls", config: ConfigT{AllowRemoteURLs: true}, plan: hooksPlan, wantErr: true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &server{config: tc.config}
+			if err := s.checkPlanCapabilities(tc.plan); (err != nil) != tc.wantErr {
+				t.Fatalf("expected an error to be %t. Actual error: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGetJobArgs(t *testing.T) {
+	j := &JobT{ID: "job1", WorkspaceID: "ws1", args: []string{"transform"}}
+	disableFlag := "--" + common.DisableLocalExecutionFlag
+	s := &server{workspacesPath: t.TempDir()}
+	if args := s.getJobArgs(j); args[0] != "transform" || args[len(args)-1] != disableFlag {
+		t.Fatalf("expected the job to run with %s by default. Actual args: %+v", disableFlag, args)
+	}
+	s.config.AllowLocalExecution = true
+	for _, arg := range s.getJobArgs(j) {
+		if arg == disableFlag {
+			t.Fatalf("expected the job to run without %s when local execution is allowed", disableFlag)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+)
+
+// WorkspaceStatusT is the status of a workspace
+type WorkspaceStatusT string
+
+const (
+	// WorkspaceCreated means the workspace has been created but nothing has been run yet
+	WorkspaceCreated WorkspaceStatusT = "created"
+	// WorkspacePlanning means the planning is in progress
+	WorkspacePlanning WorkspaceStatusT = "planning"
+	// WorkspacePlanned means the plan is ready
+	WorkspacePlanned WorkspaceStatusT = "planned"
+	// WorkspaceTransforming means the transformation is in progress
+	WorkspaceTransforming WorkspaceStatusT = "transforming"
+	// WorkspaceTransformed means the output is ready for download
+	WorkspaceTransformed WorkspaceStatusT = "transformed"
+	// WorkspaceFailed means the last plan or transform failed
+	WorkspaceFailed WorkspaceStatusT = "failed"
+)
+
+const (
+	workspaceMetadataFile = "workspace.json"
+	workspaceSourceDir    = "source"
+	workspaceOutputDir    = "output"
+	workspaceLogFile      = "run.log"
+)
+
+// WorkspaceT holds the uploaded source, the plan and the output of a single project
+type WorkspaceT struct {
+	ID        string           `json:"id"`
+	Name      string           `json:"name"`
+	Timestamp time.Time        `json:"timestamp"`
+	Status    WorkspaceStatusT `json:"status"`
+	Error     string           `json:"error,omitempty"`
+	HasSource bool             `json:"hasSource"`
+	JobID     string           `json:"jobID,omitempty"`
+}
+
+// isRunning returns true if a plan or transform is in progress in the workspace
+func (w *WorkspaceT) isRunning() bool {
+	return w.Status == WorkspacePlanning || w.Status == WorkspaceTransforming
+}
+
+// newID returns a random id for a workspace or a job
+func newID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate a random id. Error: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (s *server) getWorkspacePath(id string) string {
+	return filepath.Join(s.workspacesPath, id)
+}
+
+func (s *server) getSourcePath(id string) string {
+	return filepath.Join(s.getWorkspacePath(id), workspaceSourceDir)
+}
+
+func (s *server) getPlanPath(id string) string {
+	return filepath.Join(s.getWorkspacePath(id), common.DefaultPlanFile)
+}
+
+func (s *server) getOutputPath(id string) string {
+	return filepath.Join(s.getWorkspacePath(id), workspaceOutputDir)
+}
+
+func (s *server) getLogPath(id string) string {
+	return filepath.Join(s.getWorkspacePath(id), workspaceLogFile)
+}
+
+// saveWorkspace writes the workspace metadata to disk. The caller must hold the lock.
+func (s *server) saveWorkspace(w *WorkspaceT) error {
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the workspace %s to json. Error: %w", w.ID, err)
+	}
+	metadataPath := filepath.Join(s.getWorkspacePath(w.ID), workspaceMetadataFile)
+	if err := os.WriteFile(metadataPath, data, common.DefaultFilePermission); err != nil {
+		return fmt.Errorf("failed to write the workspace metadata to the file %s . Error: %w", metadataPath, err)
+	}
+	return nil
+}
+
+// loadWorkspaces reads the workspaces left behind by a previous run of the server
+func (s *server) loadWorkspaces() error {
+	entries, err := os.ReadDir(s.workspacesPath)
+	if err != nil {
+		return fmt.Errorf("failed to read the workspaces directory %s . Error: %w", s.workspacesPath, err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		metadataPath := filepath.Join(s.workspacesPath, entry.Name(), workspaceMetadataFile)
+		data, err := os.ReadFile(metadataPath)
+		if err != nil {
+			continue
+		}
+		w := &WorkspaceT{}
+		if err := json.Unmarshal(data, w); err != nil || w.ID != entry.Name() {
+			continue
+		}
+		if w.isRunning() {
+			w.Error = "the server was restarted while the workspace was " + string(w.Status)
+			w.Status = WorkspaceFailed
+			if err := s.saveWorkspace(w); err != nil {
+				return err
+			}
+		}
+		s.workspaces[w.ID] = w
+	}
+	return nil
+}
@@ -185,6 +185,7 @@ func (t *CloudFoundry) Transform(newArtifacts []transformertypes.Artifact, alrea
 			}
 			logrus.Debugf("Using cf manifest file at path %s to transform service %s", path, cfConfig.ServiceName)
 			application := applications[0]
+			reportCfFidelity(path, serviceConfig.ServiceName, application, cfinstanceapp)
 			irService := irtypes.Service{Name: serviceConfig.ServiceName}
 			rList := core.ResourceList{"memory": resource.MustParse(fmt.Sprintf("%dM", cfinstanceapp.Application.Memory)),
 				"ephemeral-storage": resource.MustParse(fmt.Sprintf("%dM", cfinstanceapp.Application.DiskQuota))}
@@ -263,6 +264,41 @@ func (t *CloudFoundry) Transform(newArtifacts []transformertypes.Artifact, alrea
 	return nil, artifactsCreated, nil
 }
 
+// reportCfFidelity records the fields of a cf manifest application that are dropped, approximated or need manual attention
+func reportCfFidelity(path, serviceName string, application manifest.Application, cfinstanceapp collecttypes.CfApp) {
+	report := func(field string, present bool, status common.FidelityStatusT, message string) {
+		if present {
+			common.AddFidelityItem(path, serviceName, field, status, message)
+		}
+	}
+	report("memory", application.Memory.IsSet && cfinstanceapp.Application.Memory == 0, common.FidelityDropped,
+		"The memory is only taken from the running app collected using move2kube collect")
+	report("disk_quota", application.DiskQuota.IsSet && cfinstanceapp.Application.DiskQuota == 0, common.FidelityDropped,
+		"The disk quota is only taken from the running app collected using move2kube collect")
+	report("health-check-type", application.HealthCheckType != "", common.FidelityDropped,
+		"Health checks are not translated into liveness or readiness probes")
+	report("health-check-http-endpoint", application.HealthCheckHTTPEndpoint != "", common.FidelityDropped,
+		"Health checks are not translated into liveness or readiness probes")
+	report("timeout", application.HealthCheckTimeout != 0, common.FidelityDropped,
+		"The startup timeout is not translated, add a startup probe if the app takes long to start")
+	report("command", application.Command.IsSet, common.FidelityManualAttention,
+		"The start command is not set on the container, make sure the image starts the app the same way")
+	report("buildpacks", application.Buildpack.IsSet || len(application.Buildpacks) > 0, common.FidelityApproximated,
+		"The app is containerized using the selected containerization option instead of the buildpacks")
+	report("stack", application.StackName != "", common.FidelityDropped,
+		"The stack is replaced by the base image chosen during containerization")
+	report("routes", len(application.Routes) > 0 || application.Hostname != "" || application.Domain != "" || application.RoutePath != "", common.FidelityManualAttention,
+		"Routes are not translated as is, the ingress host and paths are chosen during the transformation")
+	report("random-route", application.RandomRoute, common.FidelityDropped,
+		"Random routes have no equivalent in Kubernetes")
+	report("no-route", application.NoRoute, common.FidelityApproximated,
+		"A Kubernetes service is still created for the app, choose not to expose it during the transformation")
+	report("docker.username", application.DockerUsername != "", common.FidelityManualAttention,
+		"The registry credentials must be created as an image pull secret")
+	report("services", len(application.Services) > 0, common.FidelityManualAttention,
+		"The bound services are only available through VCAP_SERVICES, they must be provisioned separately")
+}
+
 // prioritizeAndAddEnvironmentVariables adds relevant environment variables relevant to the application deployment
 func (t *CloudFoundry) prioritizeAndAddEnvironmentVariables(cfApp collecttypes.CfApp,
 	manifestEnvMap map[string]string, secretName string, serviceName string) ([]core.EnvVar, map[string][]byte) {
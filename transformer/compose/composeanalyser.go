@@ -83,8 +83,8 @@ func (t *ComposeAnalyser) DirectoryDetect(dir string) (services map[string][]tra
 		}
 	}
 	services = map[string][]transformertypes.Artifact{}
-	for _, path := range yamlpaths {
-		currServices := t.getServicesFromComposeFile(path, imageMetadataPaths)
+	for _, composeFileSet := range t.getComposeFileSets(yamlpaths) {
+		currServices := t.getServicesFromComposeFiles(composeFileSet, imageMetadataPaths)
 		services = plantypes.MergeServicesT(services, currServices)
 	}
 	logrus.Debugf("Docker compose services : %+v", services)
@@ -111,17 +111,18 @@ func (t *ComposeAnalyser) Transform(newArtifacts []transformertypes.Artifact, al
 			logrus.Debugf("unable to load config for Transformer into %T : %s", imageName, err)
 		}
 		ir := irtypes.NewIR()
-		for _, path := range newArtifact.Paths[composeFilePathType] {
-			logrus.Debugf("File %s being loaded from compose service : %s", path, config.ServiceName)
+		// the compose files of a service are merged like docker compose merges the override files
+		if composeFilePaths := newArtifact.Paths[composeFilePathType]; len(composeFilePaths) != 0 {
+			logrus.Debugf("Files %+v being loaded from compose service : %s", composeFilePaths, config.ServiceName)
 			// Try v3 first and if it fails try v1v2
-			if cir, errV3 := new(v3Loader).ConvertToIR(path, config.ServiceName); errV3 == nil {
+			if cir, errV3 := new(v3Loader).ConvertToIR(composeFilePaths, config.ServiceName); errV3 == nil {
 				ir.Merge(cir)
 				logrus.Debugf("compose v3 transformer returned %d services", len(ir.Services))
-			} else if cir, errV1V2 := new(v1v2Loader).ConvertToIR(path, config.ServiceName); errV1V2 == nil {
+			} else if cir, errV1V2 := new(v1v2Loader).ConvertToIR(composeFilePaths, config.ServiceName); errV1V2 == nil {
 				ir.Merge(cir)
 				logrus.Debugf("compose v1v2 transformer returned %d services", len(ir.Services))
 			} else {
-				logrus.Errorf("Unable to parse the docker compose file at path %s Error V3: %q Error V1V2: %q", path, errV3, errV1V2)
+				logrus.Errorf("Unable to parse the docker compose files at paths %+v Error V3: %q Error V1V2: %q", composeFilePaths, errV3, errV1V2)
 			}
 		}
 		for _, path := range newArtifact.Paths[imageInfoPathType] {
@@ -201,17 +202,17 @@ func (t *ComposeAnalyser) Transform(newArtifacts []transformertypes.Artifact, al
 	return pathMappings, createdArtifacts, nil
 }
 
-func (t *ComposeAnalyser) getService(composeFilePath string, serviceName string, serviceImage string, relContextPath string, relDockerfilePath string, imageMetadataPaths map[string]string) transformertypes.Artifact {
+func (t *ComposeAnalyser) getService(composeFilePaths []string, serviceName string, serviceImage string, relContextPath string, relDockerfilePath string, imageMetadataPaths map[string]string) transformertypes.Artifact {
 	ct := transformertypes.Artifact{
 		Configs: map[transformertypes.ConfigType]interface{}{ComposeServiceConfigType: ComposeConfig{ServiceName: serviceName}},
-		Paths:   map[transformertypes.PathType][]string{composeFilePathType: {composeFilePath}},
+		Paths:   map[transformertypes.PathType][]string{composeFilePathType: composeFilePaths},
 	}
 	if imagepath, ok := imageMetadataPaths[serviceImage]; ok {
 		ct.Paths[imageInfoPathType] = common.AppendIfNotPresent(ct.Paths[imageInfoPathType], imagepath)
 	}
 	logrus.Debugf("Found a docker compose service : %s", serviceName)
 	if relContextPath != "" {
-		composeFileDir := filepath.Dir(composeFilePath)
+		composeFileDir := filepath.Dir(composeFilePaths[0])
 		contextPath := filepath.Join(composeFileDir, relContextPath)
 		if filepath.IsAbs(relContextPath) {
 			contextPath = relContextPath // this happens with v1v2 parser
@@ -230,14 +231,15 @@ func (t *ComposeAnalyser) getService(composeFilePath string, serviceName string,
 	return ct
 }
 
-func (t *ComposeAnalyser) getServicesFromComposeFile(composeFilePath string, imageMetadataPaths map[string]string) map[string][]transformertypes.Artifact {
+// getServicesFromComposeFiles returns the services in the compose files, after merging the files after the first one into it
+func (t *ComposeAnalyser) getServicesFromComposeFiles(composeFilePaths []string, imageMetadataPaths map[string]string) map[string][]transformertypes.Artifact {
 	services := map[string][]transformertypes.Artifact{}
 	// Try v3 first and if it fails try v1v2
-	dcV3, errV3 := parseV3(composeFilePath)
+	dcV3, errV3 := parseV3(composeFilePaths...)
 	if errV3 == nil {
-		logrus.Debugf("Found a docker compose file at path %s", composeFilePath)
+		logrus.Debugf("Found docker compose files at paths %+v", composeFilePaths)
 		for _, service := range dcV3.Services {
-			services[service.Name] = []transformertypes.Artifact{t.getService(composeFilePath, service.Name, service.Image, service.Build.Context, service.Build.Dockerfile, imageMetadataPaths)}
+			services[service.Name] = []transformertypes.Artifact{t.getService(composeFilePaths, service.Name, service.Image, service.Build.Context, service.Build.Dockerfile, imageMetadataPaths)}
 		}
 		return services
 	}
@@ -246,14 +248,14 @@ func (t *ComposeAnalyser) getServicesFromComposeFile(composeFilePath string, ima
 		// With interpolation error v2 parser panics. This prevents the panic. TODO: Is this still relevant? https://github.com/compose-spec/compose-go
 		interpolate = false
 	}
-	dcV1V2, errV1V2 := parseV2(composeFilePath, interpolate)
+	dcV1V2, errV1V2 := parseV2(composeFilePaths, interpolate)
 	if errV1V2 != nil {
-		logrus.Debugf("Failed to parse file at path %s as a docker compose file. Error V3: %q Error V1V2: %q", composeFilePath, errV3, errV1V2)
+		logrus.Debugf("Failed to parse the files at paths %+v as docker compose files. Error V3: %q Error V1V2: %q", composeFilePaths, errV3, errV1V2)
 		return services
 	}
-	logrus.Debugf("Found a docker compose file at path %s", composeFilePath)
+	logrus.Debugf("Found docker compose files at paths %+v", composeFilePaths)
 	for serviceName, serviceConfig := range dcV1V2.ServiceConfigs.All() {
-		services[serviceName] = []transformertypes.Artifact{t.getService(composeFilePath, serviceName, serviceConfig.Image, serviceConfig.Build.Context, serviceConfig.Build.Dockerfile, imageMetadataPaths)}
+		services[serviceName] = []transformertypes.Artifact{t.getService(composeFilePaths, serviceName, serviceConfig.Image, serviceConfig.Build.Context, serviceConfig.Build.Dockerfile, imageMetadataPaths)}
 	}
 	return services
 }
@@ -0,0 +1,125 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package compose
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+)
+
+var (
+	// defaultComposeFileNames are the compose files docker compose loads when no file is given, in the order of preference
+	defaultComposeFileNames = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+	// extraComposeFileRegex matches the compose files usually given to docker compose using -f along with the default one
+	extraComposeFileRegex = regexp.MustCompile(`^(docker-)?compose[.-].+\.ya?ml$`)
+)
+
+// getComposeFileSets groups the yaml files into the sets of compose files that docker compose loads together.
+// In each directory the default compose file is followed by its override file and the additional files selected by the user.
+// The other yaml files are loaded on their own.
+func (t *ComposeAnalyser) getComposeFileSets(yamlPaths []string) [][]string {
+	dirPaths := map[string][]string{}
+	for _, path := range yamlPaths {
+		dirPaths[filepath.Dir(path)] = append(dirPaths[filepath.Dir(path)], path)
+	}
+	dirs := []string{}
+	for dir := range dirPaths {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	fileSets := [][]string{}
+	for _, dir := range dirs {
+		paths := dirPaths[dir]
+		sort.Strings(paths)
+		fileSet := []string{}
+		if defaultFile := getDefaultComposeFile(dir, paths); defaultFile != "" {
+			fileSet = append(fileSet, defaultFile)
+			if overrideFile := getComposeOverrideFile(defaultFile, paths); overrideFile != "" {
+				fileSet = append(fileSet, overrideFile)
+			}
+			fileSet = append(fileSet, t.getExtraComposeFiles(defaultFile, paths, fileSet)...)
+			fileSets = append(fileSets, fileSet)
+		}
+		for _, path := range paths {
+			if !common.IsPresent(fileSet, path) {
+				fileSets = append(fileSets, []string{path})
+			}
+		}
+	}
+	return fileSets
+}
+
+// getDefaultComposeFile returns the compose file docker compose loads by default in the directory
+func getDefaultComposeFile(dir string, paths []string) string {
+	for _, name := range defaultComposeFileNames {
+		if path := filepath.Join(dir, name); common.IsPresent(paths, path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// getComposeOverrideFile returns the override file docker compose merges into the default compose file
+func getComposeOverrideFile(defaultFile string, paths []string) string {
+	stem := strings.TrimSuffix(defaultFile, filepath.Ext(defaultFile))
+	for _, ext := range []string{filepath.Ext(defaultFile), ".yaml", ".yml"} {
+		if path := stem + ".override" + ext; common.IsPresent(paths, path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// getExtraComposeFiles asks the user which of the other compose files in the directory should be merged into the default compose file
+func (t *ComposeAnalyser) getExtraComposeFiles(defaultFile string, paths []string, fileSet []string) []string {
+	candidates := []string{}
+	for _, path := range paths {
+		if !common.IsPresent(fileSet, path) && extraComposeFileRegex.MatchString(filepath.Base(path)) {
+			candidates = append(candidates, filepath.Base(path))
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	relDefaultFile := defaultFile
+	if t.Env != nil {
+		if rel, err := filepath.Rel(t.Env.GetEnvironmentSource(), defaultFile); err == nil {
+			relDefaultFile = filepath.ToSlash(rel)
+		}
+	}
+	selected := qaengine.FetchMultiSelectAnswer(
+		fmt.Sprintf(common.ConfigComposeExtraFilesKey, `"`+relDefaultFile+`"`),
+		fmt.Sprintf("Select the compose files to merge into %s, like docker compose -f:", relDefaultFile),
+		[]string{"The selected files are merged in the listed order. The other files are loaded on their own."},
+		[]string{},
+		candidates,
+		nil,
+	)
+	extraFiles := []string{}
+	for _, candidate := range candidates {
+		if common.IsPresent(selected, candidate) {
+			extraFiles = append(extraFiles, filepath.Join(filepath.Dir(defaultFile), candidate))
+		}
+	}
+	return extraFiles
+}
@@ -0,0 +1,88 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package compose
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/qaengine"
+)
+
+func TestGetComposeOverrideFile(t *testing.T) {
+	testCases := []struct {
+		name        string
+		defaultFile string
+		paths       []string
+		want        string
+	}{
+		{
+			name:        "same extension",
+			defaultFile: filepath.Join("app", "docker-compose.yml"),
+			paths:       []string{filepath.Join("app", "docker-compose.yml"), filepath.Join("app", "docker-compose.override.yaml"), filepath.Join("app", "docker-compose.override.yml")},
+			want:        filepath.Join("app", "docker-compose.override.yml"),
+		},
+		{
+			name:        "different extension",
+			defaultFile: filepath.Join("app", "compose.yaml"),
+			paths:       []string{filepath.Join("app", "compose.yaml"), filepath.Join("app", "compose.override.yml")},
+			want:        filepath.Join("app", "compose.override.yml"),
+		},
+		{
+			name:        "override of another default file",
+			defaultFile: filepath.Join("app", "compose.yaml"),
+			paths:       []string{filepath.Join("app", "compose.yaml"), filepath.Join("app", "docker-compose.override.yaml")},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := getComposeOverrideFile(testCase.defaultFile, testCase.paths); got != testCase.want {
+				t.Fatalf("expected the override file to be %q. Actual: %q", testCase.want, got)
+			}
+		})
+	}
+}
+
+func TestGetComposeFileSets(t *testing.T) {
+	qaengine.AddEngine(qaengine.NewDefaultEngine())
+	web := func(name string) string { return filepath.Join("src", "web", name) }
+	api := func(name string) string { return filepath.Join("src", "api", name) }
+	db := func(name string) string { return filepath.Join("src", "db", name) }
+	yamlPaths := []string{
+		web("docker-compose.override.yml"),
+		web("docker-compose.yml"),
+		web("docker-compose.prod.yml"),
+		web("config.yaml"),
+		api("docker-compose.yml"),
+		api("compose.yaml"),
+		db("docker-compose.dev.yaml"),
+	}
+	want := [][]string{
+		{api("compose.yaml")},
+		{api("docker-compose.yml")},
+		{db("docker-compose.dev.yaml")},
+		{web("docker-compose.yml"), web("docker-compose.override.yml")},
+		{web("config.yaml")},
+		// the extra compose files are not merged unless they are selected
+		{web("docker-compose.prod.yml")},
+	}
+	analyser := &ComposeAnalyser{}
+	if got := analyser.getComposeFileSets(yamlPaths); !cmp.Equal(got, want) {
+		t.Fatalf("the compose file sets are incorrect. Differences:\n%s", cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,300 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/loader"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/deepcopy"
+	"github.com/spf13/cast"
+)
+
+const extendsKey = "extends"
+
+var (
+	// concatenatedServiceKeys are the options whose values are concatenated when a service is extended
+	concatenatedServiceKeys = []string{"ports", "expose", "external_links", "dns", "dns_search", "tmpfs"}
+	// keyedServiceKeys are the options whose entries are merged by key when a service is extended,
+	// along with the separator between the key and the value when the entries are given as a list
+	keyedServiceKeys = map[string]string{"environment": "=", "labels": "=", "sysctls": "=", "extra_hosts": ":"}
+	// mountServiceKeys are the options whose entries are merged by the path in the container when a service is extended
+	mountServiceKeys = []string{"volumes", "devices"}
+	// nonInheritedServiceKeys are never inherited from the extended service
+	nonInheritedServiceKeys = []string{"links", "volumes_from", "depends_on", "net", "network_mode", "container_name", extendsKey}
+)
+
+// resolveExtends replaces the extends option of the services in the parsed compose file with the configuration they inherit,
+// the way docker compose does, since the v3 loader rejects the extends option
+func resolveExtends(path string, parsedComposeFile map[string]interface{}) error {
+	services, ok := parsedComposeFile["services"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	serviceNames := []string{}
+	for serviceName := range services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		if _, err := resolveServiceExtends(path, services, serviceName, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveServiceExtends resolves the extends option of the service recursively and returns the resolved service
+func resolveServiceExtends(path string, services map[string]interface{}, serviceName string, chain []string) (map[string]interface{}, error) {
+	chainKey := path + ":" + serviceName
+	if common.IsPresent(chain, chainKey) {
+		return nil, fmt.Errorf("the service %s in the compose file %s extends itself through %s", serviceName, path, strings.Join(chain, " -> "))
+	}
+	service, ok := services[serviceName].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the service %s was not found in the compose file %s", serviceName, path)
+	}
+	extends, ok := service[extendsKey]
+	if !ok {
+		return service, nil
+	}
+	baseServiceName, baseFile := "", ""
+	switch extends := extends.(type) {
+	case string:
+		baseServiceName = extends
+	case map[string]interface{}:
+		baseServiceName = cast.ToString(extends["service"])
+		baseFile = cast.ToString(extends["file"])
+	default:
+		return nil, fmt.Errorf("the extends option of the service %s in the compose file %s is invalid. Expected a service name or a mapping. Actual: %T", serviceName, path, extends)
+	}
+	if baseServiceName == "" {
+		return nil, fmt.Errorf("the extends option of the service %s in the compose file %s does not have the service to extend", serviceName, path)
+	}
+	basePath, baseServices := path, services
+	if baseFile != "" {
+		basePath = baseFile
+		if !filepath.IsAbs(basePath) {
+			basePath = filepath.Join(filepath.Dir(path), baseFile)
+		}
+		data, err := os.ReadFile(basePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the compose file %s extended by the service %s . Error: %w", basePath, serviceName, err)
+		}
+		parsedBaseFile, err := loader.ParseYAML(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the compose file %s extended by the service %s . Error: %w", basePath, serviceName, err)
+		}
+		if baseServices, ok = parsedBaseFile["services"].(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("the compose file %s extended by the service %s does not have any services", basePath, serviceName)
+		}
+	}
+	baseService, err := resolveServiceExtends(basePath, baseServices, baseServiceName, append(chain, chainKey))
+	if err != nil {
+		return nil, err
+	}
+	inherited := deepcopy.DeepCopy(baseService).(map[string]interface{})
+	for _, key := range nonInheritedServiceKeys {
+		delete(inherited, key)
+	}
+	if filepath.Dir(basePath) != filepath.Dir(path) {
+		rebaseServicePaths(inherited, filepath.Dir(basePath), filepath.Dir(path))
+	}
+	local := map[string]interface{}{}
+	for k, v := range service {
+		if k != extendsKey {
+			local[k] = v
+		}
+	}
+	resolved := mergeComposeServices(inherited, local)
+	services[serviceName] = resolved
+	return resolved, nil
+}
+
+// mergeComposeServices merges the override service into the base service using the rules docker compose uses for extends
+func mergeComposeServices(base, override map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, overrideValue := range override {
+		baseValue, ok := merged[k]
+		if !ok || baseValue == nil {
+			merged[k] = overrideValue
+			continue
+		}
+		separator, isKeyed := keyedServiceKeys[k]
+		switch {
+		case common.IsPresent(concatenatedServiceKeys, k):
+			merged[k] = concatenateComposeValues(baseValue, overrideValue)
+		case isKeyed:
+			mergedEntries := toComposeMapping(baseValue, separator)
+			for entryKey, entryValue := range toComposeMapping(overrideValue, separator) {
+				mergedEntries[entryKey] = entryValue
+			}
+			merged[k] = mergedEntries
+		case common.IsPresent(mountServiceKeys, k):
+			merged[k] = mergeComposeMounts(baseValue, overrideValue)
+		default:
+			baseMap, baseIsMap := baseValue.(map[string]interface{})
+			overrideMap, overrideIsMap := overrideValue.(map[string]interface{})
+			if baseIsMap && overrideIsMap {
+				merged[k] = mergeComposeMappings(baseMap, overrideMap)
+			} else {
+				merged[k] = overrideValue
+			}
+		}
+	}
+	return merged
+}
+
+// mergeComposeMappings merges nested mappings like build and logging, the values in the override take precedence
+func mergeComposeMappings(base, override map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, overrideValue := range override {
+		baseMap, baseIsMap := merged[k].(map[string]interface{})
+		overrideMap, overrideIsMap := overrideValue.(map[string]interface{})
+		if baseIsMap && overrideIsMap {
+			merged[k] = mergeComposeMappings(baseMap, overrideMap)
+			continue
+		}
+		merged[k] = overrideValue
+	}
+	return merged
+}
+
+func concatenateComposeValues(base, override interface{}) []interface{} {
+	values := []interface{}{}
+	for _, value := range []interface{}{base, override} {
+		if list, ok := value.([]interface{}); ok {
+			values = append(values, list...)
+		} else {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// toComposeMapping converts an option given either as a list of strings with the key and the value separated by the separator
+// or as a mapping into a mapping
+func toComposeMapping(value interface{}, separator string) map[string]interface{} {
+	mapping := map[string]interface{}{}
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for k, v := range value {
+			mapping[k] = v
+		}
+	case []interface{}:
+		for _, entry := range value {
+			entryStr := cast.ToString(entry)
+			if k, v, ok := strings.Cut(entryStr, separator); ok {
+				mapping[k] = v
+			} else {
+				mapping[entryStr] = nil
+			}
+		}
+	}
+	return mapping
+}
+
+// mergeComposeMounts merges the volumes or devices, the mounts in the override replace the ones with the same path in the container
+func mergeComposeMounts(base, override interface{}) []interface{} {
+	baseMounts, _ := base.([]interface{})
+	overrideMounts, _ := override.([]interface{})
+	overriddenTargets := map[string]bool{}
+	for _, mount := range overrideMounts {
+		overriddenTargets[getComposeMountTarget(mount)] = true
+	}
+	merged := []interface{}{}
+	for _, mount := range baseMounts {
+		if !overriddenTargets[getComposeMountTarget(mount)] {
+			merged = append(merged, mount)
+		}
+	}
+	return append(merged, overrideMounts...)
+}
+
+// getComposeMountTarget returns the path in the container of a volume or device in the short or the long syntax
+func getComposeMountTarget(mount interface{}) string {
+	if mount, ok := mount.(map[string]interface{}); ok {
+		return cast.ToString(mount["target"])
+	}
+	parts := strings.Split(cast.ToString(mount), ":")
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return parts[1]
+}
+
+// rebaseServicePaths makes the relative paths in the inherited service relative to the directory of the extending compose file
+func rebaseServicePaths(service map[string]interface{}, fromDir, toDir string) {
+	rebase := func(path string) string {
+		if path == "" || filepath.IsAbs(path) || strings.HasPrefix(path, "~") || strings.Contains(path, "://") {
+			return path
+		}
+		rebased, err := filepath.Rel(toDir, filepath.Join(fromDir, path))
+		if err != nil {
+			return path
+		}
+		rebased = filepath.ToSlash(rebased)
+		if !strings.HasPrefix(rebased, ".") {
+			rebased = "./" + rebased
+		}
+		return rebased
+	}
+	switch build := service["build"].(type) {
+	case string:
+		service["build"] = rebase(build)
+	case map[string]interface{}:
+		if context, ok := build["context"].(string); ok {
+			build["context"] = rebase(context)
+		}
+	}
+	switch envFiles := service[envFile].(type) {
+	case string:
+		service[envFile] = rebase(envFiles)
+	case []interface{}:
+		for i, f := range envFiles {
+			if f, ok := f.(string); ok {
+				envFiles[i] = rebase(f)
+			}
+		}
+	}
+	if volumes, ok := service["volumes"].([]interface{}); ok {
+		for i, volume := range volumes {
+			switch volume := volume.(type) {
+			case string:
+				// only the bind mounts have paths, named volumes don't start with a dot or a slash
+				if parts := strings.SplitN(volume, ":", 2); len(parts) == 2 && strings.HasPrefix(parts[0], ".") {
+					volumes[i] = rebase(parts[0]) + ":" + parts[1]
+				}
+			case map[string]interface{}:
+				if source, ok := volume["source"].(string); ok && cast.ToString(volume["type"]) == "bind" {
+					volume["source"] = rebase(source)
+				}
+			}
+		}
+	}
+}
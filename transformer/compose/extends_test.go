@@ -0,0 +1,166 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+)
+
+func TestResolveExtends(t *testing.T) {
+	testCases := []struct {
+		name     string
+		services map[string]interface{}
+		want     map[string]interface{}
+		wantErr  bool
+	}{
+		{
+			name: "service in the same file",
+			services: map[string]interface{}{
+				"base": map[string]interface{}{
+					"image":          "nginx",
+					"container_name": "base",
+					"ports":          []interface{}{"80"},
+					"environment":    []interface{}{"A=1", "B=2"},
+					"build":          map[string]interface{}{"context": ".", "args": map[string]interface{}{"X": "1"}},
+				},
+				"web": map[string]interface{}{
+					"extends":     "base",
+					"ports":       []interface{}{"81"},
+					"environment": map[string]interface{}{"B": "3"},
+					"build":       map[string]interface{}{"args": map[string]interface{}{"Y": "2"}},
+				},
+			},
+			want: map[string]interface{}{
+				"image":       "nginx",
+				"ports":       []interface{}{"80", "81"},
+				"environment": map[string]interface{}{"A": "1", "B": "3"},
+				"build":       map[string]interface{}{"context": ".", "args": map[string]interface{}{"X": "1", "Y": "2"}},
+			},
+		},
+		{
+			name: "chain of extends",
+			services: map[string]interface{}{
+				"base":   map[string]interface{}{"image": "nginx", "labels": []interface{}{"tier=web"}},
+				"middle": map[string]interface{}{"extends": map[string]interface{}{"service": "base"}, "labels": map[string]interface{}{"team": "a"}},
+				"web":    map[string]interface{}{"extends": "middle", "image": "nginx:alpine"},
+			},
+			want: map[string]interface{}{
+				"image":  "nginx:alpine",
+				"labels": map[string]interface{}{"tier": "web", "team": "a"},
+			},
+		},
+		{
+			name: "cycle",
+			services: map[string]interface{}{
+				"base": map[string]interface{}{"extends": "web"},
+				"web":  map[string]interface{}{"extends": "base"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing service",
+			services: map[string]interface{}{
+				"web": map[string]interface{}{"extends": "base"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid extends",
+			services: map[string]interface{}{
+				"web": map[string]interface{}{"extends": []interface{}{"base"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			parsedComposeFile := map[string]interface{}{"services": testCase.services}
+			err := resolveExtends(filepath.Join(t.TempDir(), "docker-compose.yaml"), parsedComposeFile)
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error. Actual services: %+v", testCase.services)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to resolve the extends. Error: %q", err)
+			}
+			if got := testCase.services["web"]; !cmp.Equal(got, testCase.want) {
+				t.Fatalf("the resolved service is incorrect. Differences:\n%s", cmp.Diff(testCase.want, got))
+			}
+		})
+	}
+}
+
+func TestResolveExtendsFromAnotherFile(t *testing.T) {
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, "common", "base.yaml")
+	if err := os.MkdirAll(filepath.Dir(basePath), common.DefaultDirectoryPermission); err != nil {
+		t.Fatalf("failed to create the directory %s . Error: %q", filepath.Dir(basePath), err)
+	}
+	if err := os.WriteFile(basePath, []byte(`services:
+  app:
+    build: ./app
+    env_file: app.env
+    volumes:
+      - ./data:/data
+      - cache:/cache
+    depends_on:
+      - db
+`), common.DefaultFilePermission); err != nil {
+		t.Fatalf("failed to write the file %s . Error: %q", basePath, err)
+	}
+	services := map[string]interface{}{
+		"web": map[string]interface{}{
+			"extends": map[string]interface{}{"file": "common/base.yaml", "service": "app"},
+			"volumes": []interface{}{"./other:/data"},
+		},
+	}
+	if err := resolveExtends(filepath.Join(dir, "docker-compose.yaml"), map[string]interface{}{"services": services}); err != nil {
+		t.Fatalf("failed to resolve the extends. Error: %q", err)
+	}
+	want := map[string]interface{}{
+		"build":    "./common/app",
+		"env_file": "./common/app.env",
+		"volumes":  []interface{}{"cache:/cache", "./other:/data"},
+	}
+	if got := services["web"]; !cmp.Equal(got, want) {
+		t.Fatalf("the resolved service is incorrect. Differences:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestGetComposeMountTarget(t *testing.T) {
+	testCases := []struct {
+		mount interface{}
+		want  string
+	}{
+		{mount: "/data", want: "/data"},
+		{mount: "./data:/data", want: "/data"},
+		{mount: "cache:/cache:ro", want: "/cache"},
+		{mount: map[string]interface{}{"type": "bind", "source": "./data", "target": "/data"}, want: "/data"},
+	}
+	for _, testCase := range testCases {
+		if got := getComposeMountTarget(testCase.mount); got != testCase.want {
+			t.Fatalf("expected the target of the mount %+v to be %s . Actual: %s", testCase.mount, testCase.want, got)
+		}
+	}
+}
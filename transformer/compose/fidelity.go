@@ -0,0 +1,159 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package compose
+
+import (
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/docker/libcompose/config"
+	"github.com/konveyor/move2kube/common"
+	"github.com/spf13/cast"
+)
+
+const (
+	noEquivalentMessage        = "Kubernetes has no equivalent for this field"
+	privilegedMessage          = "Privileged containers are not allowed by the restricted pod security standard and on OpenShift without an SCC"
+	hostPathMessage            = "Host paths are mounted as hostPath volumes which only work if the path exists on every node"
+	linksMessage               = "Services can reach each other using the names of their Kubernetes services, aliases are not created"
+	dependsOnMessage           = "Kubernetes starts all the containers together, add an init container or readiness checks if the start order matters"
+	networkModeMessage         = "All the containers in a pod share the pod network, other network modes can't be translated"
+	restartPolicyMessage       = "Deployments always restart the containers"
+	restartUnlessStoppedMsg    = "unless-stopped is translated to the Always restart policy"
+	nonNumericUserMessage      = "Only numeric user ids can be translated into runAsUser"
+	pidMessage                 = "Only the host pid namespace can be translated into hostPID"
+	stopSignalMessage          = "Kubernetes always sends SIGTERM, change the image if the application expects a different signal"
+	readOnlyVolumeMessage      = "Read-only volume mounts are translated as read-write mounts"
+	deployPlacementMessage     = "Placement constraints are not translated into node selectors or affinities"
+	deployUpdateConfigMessage  = "Update and rollback configs are not translated into the deployment strategy"
+	deployEndpointModeMessage  = "The endpoint mode is not translated, all services get a cluster IP"
+	deployRestartPolicyMessage = "Only the condition of the restart policy is translated, the delay, attempts and window are dropped"
+)
+
+// fidelityCheck is a field of a compose service that is not translated as is
+type fidelityCheck struct {
+	field   string
+	present bool
+	status  common.FidelityStatusT
+	message string
+}
+
+// reportFidelity records the checks that apply to the service in the fidelity report
+func reportFidelity(composefilepath, serviceName string, checks []fidelityCheck) {
+	for _, check := range checks {
+		if check.present {
+			common.AddFidelityItem(composefilepath, serviceName, check.field, check.status, check.message)
+		}
+	}
+}
+
+// reportV3Fidelity records the fields of a v3 compose service that are dropped, approximated or need manual attention
+func reportV3Fidelity(composefilepath string, s types.ServiceConfig) {
+	restart := s.Restart
+	if s.Deploy.RestartPolicy != nil {
+		restart = s.Deploy.RestartPolicy.Condition
+	}
+	_, userErr := cast.ToInt64E(s.User)
+	hostPathVolume, readOnlyVolume := false, false
+	for _, vol := range s.Volumes {
+		hostPathVolume = hostPathVolume || isPath(vol.Source)
+		readOnlyVolume = readOnlyVolume || vol.ReadOnly
+	}
+	reportFidelity(composefilepath, s.Name, []fidelityCheck{
+		{"privileged", s.Privileged, common.FidelityManualAttention, privilegedMessage},
+		{"volumes", hostPathVolume, common.FidelityManualAttention, hostPathMessage},
+		{"volumes.read_only", readOnlyVolume, common.FidelityApproximated, readOnlyVolumeMessage},
+		{"restart", restart == "unless-stopped", common.FidelityApproximated, restartUnlessStoppedMsg},
+		{"restart", restart == "no" || restart == "on-failure", common.FidelityApproximated, restartPolicyMessage},
+		{"user", s.User != "" && userErr != nil, common.FidelityDropped, nonNumericUserMessage},
+		{"pid", s.Pid != "" && s.Pid != "host", common.FidelityDropped, pidMessage},
+		{"links", len(s.Links) > 0, common.FidelityApproximated, linksMessage},
+		{"external_links", len(s.ExternalLinks) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"depends_on", len(s.DependsOn) > 0, common.FidelityApproximated, dependsOnMessage},
+		{"network_mode", s.NetworkMode != "", common.FidelityDropped, networkModeMessage},
+		{"stop_signal", s.StopSignal != "", common.FidelityDropped, stopSignalMessage},
+		{"stop_grace_period", s.StopGracePeriod != nil, common.FidelityDropped, noEquivalentMessage},
+		{"cgroup_parent", s.CgroupParent != "", common.FidelityDropped, noEquivalentMessage},
+		{"credential_spec", s.CredentialSpec != (types.CredentialSpecConfig{}), common.FidelityDropped, noEquivalentMessage},
+		{"devices", len(s.Devices) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"dns", len(s.DNS) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"dns_search", len(s.DNSSearch) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"extra_hosts", len(s.ExtraHosts) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"init", s.Init != nil, common.FidelityDropped, noEquivalentMessage},
+		{"ipc", s.Ipc != "", common.FidelityDropped, noEquivalentMessage},
+		{"isolation", s.Isolation != "", common.FidelityDropped, noEquivalentMessage},
+		{"logging", s.Logging != nil, common.FidelityDropped, noEquivalentMessage},
+		{"mac_address", s.MacAddress != "", common.FidelityDropped, noEquivalentMessage},
+		{"read_only", s.ReadOnly, common.FidelityDropped, noEquivalentMessage},
+		{"security_opt", len(s.SecurityOpt) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"shm_size", s.ShmSize != "", common.FidelityDropped, noEquivalentMessage},
+		{"sysctls", len(s.Sysctls) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"ulimits", len(s.Ulimits) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"userns_mode", s.UserNSMode != "", common.FidelityDropped, noEquivalentMessage},
+		{"deploy.placement", len(s.Deploy.Placement.Constraints) > 0 || len(s.Deploy.Placement.Preferences) > 0 || s.Deploy.Placement.MaxReplicas != 0, common.FidelityDropped, deployPlacementMessage},
+		{"deploy.update_config", s.Deploy.UpdateConfig != nil || s.Deploy.RollbackConfig != nil, common.FidelityDropped, deployUpdateConfigMessage},
+		{"deploy.endpoint_mode", s.Deploy.EndpointMode != "", common.FidelityDropped, deployEndpointModeMessage},
+		{"deploy.restart_policy", s.Deploy.RestartPolicy != nil && (s.Deploy.RestartPolicy.Delay != nil || s.Deploy.RestartPolicy.MaxAttempts != nil || s.Deploy.RestartPolicy.Window != nil), common.FidelityDropped, deployRestartPolicyMessage},
+	})
+}
+
+// reportV1V2Fidelity records the fields of a v1 or v2 compose service that are dropped, approximated or need manual attention
+func reportV1V2Fidelity(composefilepath, serviceName string, s *config.ServiceConfig) {
+	_, userErr := cast.ToInt64E(s.User)
+	hostPathVolume := false
+	if s.Volumes != nil {
+		for _, vol := range s.Volumes.Volumes {
+			hostPathVolume = hostPathVolume || isPath(vol.Source)
+		}
+	}
+	reportFidelity(composefilepath, serviceName, []fidelityCheck{
+		{"privileged", s.Privileged, common.FidelityManualAttention, privilegedMessage},
+		{"volumes", hostPathVolume, common.FidelityManualAttention, hostPathMessage},
+		{"restart", s.Restart == "unless-stopped", common.FidelityApproximated, restartUnlessStoppedMsg},
+		{"restart", s.Restart == "no" || s.Restart == "on-failure", common.FidelityApproximated, restartPolicyMessage},
+		{"user", s.User != "" && userErr != nil, common.FidelityDropped, nonNumericUserMessage},
+		{"links", len(s.Links) > 0, common.FidelityApproximated, linksMessage},
+		{"external_links", len(s.ExternalLinks) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"depends_on", len(s.DependsOn) > 0, common.FidelityApproximated, dependsOnMessage},
+		{"network_mode", s.NetworkMode != "", common.FidelityDropped, networkModeMessage},
+		{"volumes_from", len(s.VolumesFrom) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"stop_signal", s.StopSignal != "", common.FidelityDropped, stopSignalMessage},
+		{"pid", s.Pid != "", common.FidelityDropped, noEquivalentMessage},
+		{"cgroup_parent", s.CgroupParent != "", common.FidelityDropped, noEquivalentMessage},
+		{"cpuset", s.CPUSet != "", common.FidelityDropped, noEquivalentMessage},
+		{"cpu_shares", s.CPUShares != 0, common.FidelityDropped, noEquivalentMessage},
+		{"cpu_quota", s.CPUQuota != 0, common.FidelityDropped, noEquivalentMessage},
+		{"devices", len(s.Devices) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"dns", len(s.DNS) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"dns_opt", len(s.DNSOpts) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"dns_search", len(s.DNSSearch) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"extra_hosts", len(s.ExtraHosts) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"ipc", s.Ipc != "", common.FidelityDropped, noEquivalentMessage},
+		{"isolation", s.Isolation != "", common.FidelityDropped, noEquivalentMessage},
+		{"logging", s.Logging.Driver != "" || len(s.Logging.Options) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"mac_address", s.MacAddress != "", common.FidelityDropped, noEquivalentMessage},
+		{"mem_reservation", s.MemReservation != 0, common.FidelityDropped, noEquivalentMessage},
+		{"memswap_limit", s.MemSwapLimit != 0, common.FidelityDropped, noEquivalentMessage},
+		{"mem_swappiness", s.MemSwappiness != 0, common.FidelityDropped, noEquivalentMessage},
+		{"oom_kill_disable", s.OomKillDisable, common.FidelityDropped, noEquivalentMessage},
+		{"oom_score_adj", s.OomScoreAdj != 0, common.FidelityDropped, noEquivalentMessage},
+		{"read_only", s.ReadOnly, common.FidelityDropped, noEquivalentMessage},
+		{"security_opt", len(s.SecurityOpt) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"shm_size", s.ShmSize != 0, common.FidelityDropped, noEquivalentMessage},
+		{"ulimits", len(s.Ulimits.Elements) > 0, common.FidelityDropped, noEquivalentMessage},
+		{"uts", s.Uts != "", common.FidelityDropped, noEquivalentMessage},
+		{"volume_driver", s.VolumeDriver != "", common.FidelityDropped, noEquivalentMessage},
+	})
+}
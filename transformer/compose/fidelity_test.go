@@ -0,0 +1,134 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package compose
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/docker/libcompose/config"
+	"github.com/docker/libcompose/yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+)
+
+// getFidelityItems returns the items in the fidelity report that were recorded for the compose file
+func getFidelityItems(composefilepath string) []common.FidelityItemT {
+	items := []common.FidelityItemT{}
+	for _, item := range common.GetFidelityReport("").Items {
+		if item.Source == composefilepath {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func TestReportV3Fidelity(t *testing.T) {
+	testCases := []struct {
+		name    string
+		service types.ServiceConfig
+		want    []common.FidelityItemT
+	}{
+		{
+			name:    "fully translated service",
+			service: types.ServiceConfig{Name: "web", Image: "nginx", Restart: "always", User: "1000"},
+			want:    []common.FidelityItemT{},
+		},
+		{
+			name: "dropped, approximated and manual attention fields",
+			service: types.ServiceConfig{
+				Name:       "web",
+				Privileged: true,
+				User:       "nginx",
+				Volumes:    []types.ServiceVolumeConfig{{Source: "./data", Target: "/data", ReadOnly: true}},
+				Deploy:     types.DeployConfig{RestartPolicy: &types.RestartPolicy{Condition: "unless-stopped"}},
+			},
+			want: []common.FidelityItemT{
+				{Service: "web", Field: "privileged", Status: common.FidelityManualAttention, Message: privilegedMessage},
+				{Service: "web", Field: "restart", Status: common.FidelityApproximated, Message: restartUnlessStoppedMsg},
+				{Service: "web", Field: "user", Status: common.FidelityDropped, Message: nonNumericUserMessage},
+				{Service: "web", Field: "volumes", Status: common.FidelityManualAttention, Message: hostPathMessage},
+				{Service: "web", Field: "volumes.read_only", Status: common.FidelityApproximated, Message: readOnlyVolumeMessage},
+			},
+		},
+		{
+			name:    "host pid is translated",
+			service: types.ServiceConfig{Name: "web", Pid: "host", Restart: "no"},
+			want: []common.FidelityItemT{
+				{Service: "web", Field: "restart", Status: common.FidelityApproximated, Message: restartPolicyMessage},
+			},
+		},
+	}
+	for i, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			composefilepath := filepath.Join(t.TempDir(), fmt.Sprintf("docker-compose-%d.yaml", i))
+			for j := range testCase.want {
+				testCase.want[j].Source = composefilepath
+			}
+			reportV3Fidelity(composefilepath, testCase.service)
+			if got := getFidelityItems(composefilepath); !cmp.Equal(got, testCase.want) {
+				t.Fatalf("the fidelity items are incorrect. Differences:\n%s", cmp.Diff(testCase.want, got))
+			}
+		})
+	}
+}
+
+func TestReportV1V2Fidelity(t *testing.T) {
+	testCases := []struct {
+		name    string
+		service config.ServiceConfig
+		want    []common.FidelityItemT
+	}{
+		{
+			name:    "fully translated service",
+			service: config.ServiceConfig{Image: "nginx", Restart: "always", Volumes: &yaml.Volumes{Volumes: []*yaml.Volume{{Source: "data", Destination: "/data"}}}},
+			want:    []common.FidelityItemT{},
+		},
+		{
+			name: "dropped, approximated and manual attention fields",
+			service: config.ServiceConfig{
+				Privileged: true,
+				Restart:    "on-failure",
+				DependsOn:  []string{"db"},
+				Volumes:    &yaml.Volumes{Volumes: []*yaml.Volume{{Source: "./data", Destination: "/data"}}},
+				DNS:        yaml.Stringorslice{"8.8.8.8"},
+			},
+			want: []common.FidelityItemT{
+				{Service: "web", Field: "depends_on", Status: common.FidelityApproximated, Message: dependsOnMessage},
+				{Service: "web", Field: "dns", Status: common.FidelityDropped, Message: noEquivalentMessage},
+				{Service: "web", Field: "privileged", Status: common.FidelityManualAttention, Message: privilegedMessage},
+				{Service: "web", Field: "restart", Status: common.FidelityApproximated, Message: restartPolicyMessage},
+				{Service: "web", Field: "volumes", Status: common.FidelityManualAttention, Message: hostPathMessage},
+			},
+		},
+	}
+	for i, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			composefilepath := filepath.Join(t.TempDir(), fmt.Sprintf("docker-compose-%d.yaml", i))
+			for j := range testCase.want {
+				testCase.want[j].Source = composefilepath
+			}
+			service := testCase.service
+			reportV1V2Fidelity(composefilepath, "web", &service)
+			if got := getFidelityItems(composefilepath); !cmp.Equal(got, testCase.want) {
+				t.Fatalf("the fidelity items are incorrect. Differences:\n%s", cmp.Diff(testCase.want, got))
+			}
+		})
+	}
+}
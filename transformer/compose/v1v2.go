@@ -87,10 +87,11 @@ func removeNonExistentEnvFilesV2(path string) preprocessFunc {
 	}
 }
 
-// parseV2 parses version 2 compose files
-func parseV2(path string, interpolate bool) (*project.Project, error) {
+// parseV2 parses version 1 and 2 compose files.
+// The files after the first one are merged into it, like docker compose does with override files.
+func parseV2(paths []string, interpolate bool) (*project.Project, error) {
 	context := project.Context{}
-	context.ComposeFiles = []string{path}
+	context.ComposeFiles = paths
 	context.ResourceLookup = new(lookup.FileResourceLookup)
 	//TODO: Check if any variable is mandatory
 	someEnvFilePath := ".env"
@@ -111,7 +112,7 @@ func parseV2(path string, interpolate bool) (*project.Project, error) {
 	parseOptions := config.ParseOptions{
 		Interpolate: interpolate,
 		Validate:    true,
-		Preprocess:  removeNonExistentEnvFilesV2(path),
+		Preprocess:  removeNonExistentEnvFilesV2(paths[0]),
 	}
 	proj := project.NewProject(&context, nil, &parseOptions)
 	originalLevel := logrus.GetLevel()
@@ -119,23 +120,24 @@ func parseV2(path string, interpolate bool) (*project.Project, error) {
 	err := proj.Parse()
 	logrus.SetLevel(originalLevel) // TODO: this is a hack to prevent libcompose from printing errors to the console.
 	if err != nil {
-		err := fmt.Errorf("failed to load docker compose file at path %s Error: %q", path, err)
+		err := fmt.Errorf("failed to load docker compose files at paths %+v Error: %q", paths, err)
 		logrus.Debug(err)
 		return nil, err
 	}
 	return proj, nil
 }
 
-// ConvertToIR loads a compose file to IR
-func (c *v1v2Loader) ConvertToIR(composefilepath string, serviceName string) (ir irtypes.IR, err error) {
-	proj, err := parseV2(composefilepath, true)
+// ConvertToIR loads compose files to IR. The files after the first one are merged into it.
+func (c *v1v2Loader) ConvertToIR(composeFilePaths []string, serviceName string) (ir irtypes.IR, err error) {
+	proj, err := parseV2(composeFilePaths, true)
 	if err != nil {
 		return irtypes.IR{}, err
 	}
-	return c.convertToIR(filepath.Dir(composefilepath), proj, serviceName)
+	return c.convertToIR(composeFilePaths[0], proj, serviceName)
 }
 
-func (c *v1v2Loader) convertToIR(filedir string, composeObject *project.Project, serviceName string) (ir irtypes.IR, err error) {
+func (c *v1v2Loader) convertToIR(composefilepath string, composeObject *project.Project, serviceName string) (ir irtypes.IR, err error) {
+	filedir := filepath.Dir(composefilepath)
 	ir = irtypes.IR{
 		Services: map[string]irtypes.Service{},
 	}
@@ -144,6 +146,7 @@ func (c *v1v2Loader) convertToIR(filedir string, composeObject *project.Project,
 		if name != serviceName {
 			continue
 		}
+		reportV1V2Fidelity(composefilepath, name, composeServiceConfig)
 		serviceConfig := irtypes.NewServiceWithName(common.NormalizeForMetadataName(name))
 		serviceConfig.Annotations = map[string]string(composeServiceConfig.Labels)
 		if composeServiceConfig.Hostname != "" {
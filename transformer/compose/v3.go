@@ -88,50 +88,70 @@ func removeNonExistentEnvFilesV3(path string, parsedComposeFile map[string]inter
 	return parsedComposeFile
 }
 
-// parseV3 parses version 3 compose files
-func parseV3(path string) (*types.Config, error) {
-	fileData, err := os.ReadFile(path)
-	if err != nil {
-		err := fmt.Errorf("unable to load Compose file at path %s Error: %q", path, err)
-		logrus.Debug(err)
-		return nil, err
-	}
-	// Parse the Compose File
-	parsedComposeFile, err := loader.ParseYAML(fileData)
-	if err != nil {
-		err := fmt.Errorf("unable to load Compose file at path %s Error: %q", path, err)
-		logrus.Debug(err)
-		return nil, err
+// parseV3 parses version 3 compose files.
+// The files after the first one are merged into it, like docker compose does with override files.
+func parseV3(paths ...string) (*types.Config, error) {
+	configFiles := []types.ConfigFile{}
+	version := ""
+	for i, path := range paths {
+		fileData, err := os.ReadFile(path)
+		if err != nil {
+			err := fmt.Errorf("unable to load Compose file at path %s Error: %q", path, err)
+			logrus.Debug(err)
+			return nil, err
+		}
+		// Parse the Compose File
+		parsedComposeFile, err := loader.ParseYAML(fileData)
+		if err != nil {
+			err := fmt.Errorf("unable to load Compose file at path %s Error: %q", path, err)
+			logrus.Debug(err)
+			return nil, err
+		}
+		if err := resolveExtends(path, parsedComposeFile); err != nil {
+			err := fmt.Errorf("unable to resolve the extended services in the Compose file at path %s Error: %q", path, err)
+			logrus.Debug(err)
+			return nil, err
+		}
+		parsedComposeFile = removeNonExistentEnvFilesV3(path, parsedComposeFile)
+		// docker compose does not require the files being merged to have the same version, but the loader does
+		if i == 0 {
+			version = cast.ToString(parsedComposeFile["version"])
+		} else if version == "" {
+			delete(parsedComposeFile, "version")
+		} else {
+			parsedComposeFile["version"] = version
+		}
+		configFiles = append(configFiles, types.ConfigFile{Filename: path, Config: parsedComposeFile})
 	}
-	parsedComposeFile = removeNonExistentEnvFilesV3(path, parsedComposeFile)
 	// Config details
 	configDetails := types.ConfigDetails{
-		WorkingDir:  filepath.Dir(path),
-		ConfigFiles: []types.ConfigFile{{Filename: path, Config: parsedComposeFile}},
+		WorkingDir:  filepath.Dir(paths[0]),
+		ConfigFiles: configFiles,
 		Environment: getEnvironmentVariables(),
 	}
 	config, err := loader.Load(configDetails)
 	if err != nil {
-		err := fmt.Errorf("unable to load Compose file at path %s Error: %q", path, err)
+		err := fmt.Errorf("unable to load Compose files at paths %+v Error: %q", paths, err)
 		logrus.Debug(err)
 		return nil, err
 	}
 	return config, nil
 }
 
-// ConvertToIR loads an v3 compose file into IR
-func (c *v3Loader) ConvertToIR(composefilepath string, serviceName string) (irtypes.IR, error) {
-	logrus.Debugf("About to load configuration from docker compose file at path %s", composefilepath)
-	config, err := parseV3(composefilepath)
+// ConvertToIR loads v3 compose files into IR. The files after the first one are merged into it.
+func (c *v3Loader) ConvertToIR(composeFilePaths []string, serviceName string) (irtypes.IR, error) {
+	logrus.Debugf("About to load configuration from docker compose files at paths %+v", composeFilePaths)
+	config, err := parseV3(composeFilePaths...)
 	if err != nil {
 		logrus.Debugf("Error while loading docker compose config : %s", err)
 		return irtypes.IR{}, err
 	}
 	logrus.Debugf("About to start loading docker compose to intermediate rep")
-	return c.convertToIR(filepath.Dir(composefilepath), *config, serviceName)
+	return c.convertToIR(composeFilePaths[0], *config, serviceName)
 }
 
-func (c *v3Loader) convertToIR(filedir string, composeObject types.Config, serviceName string) (irtypes.IR, error) {
+func (c *v3Loader) convertToIR(composefilepath string, composeObject types.Config, serviceName string) (irtypes.IR, error) {
+	filedir := filepath.Dir(composefilepath)
 	ir := irtypes.IR{Services: map[string]irtypes.Service{}}
 
 	//Secret volumes transformed to IR
@@ -144,6 +164,7 @@ func (c *v3Loader) convertToIR(filedir string, composeObject types.Config, servi
 		if composeServiceConfig.Name != serviceName {
 			continue
 		}
+		reportV3Fidelity(composefilepath, composeServiceConfig)
 		name := common.NormalizeForMetadataName(composeServiceConfig.Name)
 		serviceConfig := irtypes.NewServiceWithName(name)
 		serviceContainer := core.Container{}
@@ -365,6 +386,7 @@ func (c *v3Loader) convertToIR(filedir string, composeObject types.Config, servi
 			if o, ok := composeObject.Configs[c.Source]; ok {
 				if o.External.External {
 					logrus.Errorf("Config metadata %s has an external source", c.Source)
+					common.AddFidelityItem(composefilepath, composeServiceConfig.Name, "configs", common.FidelityManualAttention, fmt.Sprintf("The external config %s must be created as a ConfigMap", c.Source))
 				} else {
 					srcBaseName := filepath.Base(o.File)
 					vSrc.Items = []core.KeyToPath{{Key: srcBaseName, Path: filepath.Base(target)}}
@@ -0,0 +1,110 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package containerimage
+
+import (
+	"path/filepath"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/environment"
+	"github.com/konveyor/move2kube/types/qaengine/commonqa"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/konveyor/move2kube/types/transformer/artifacts"
+	"github.com/sirupsen/logrus"
+)
+
+// ImageSigning implements Transformer interface
+type ImageSigning struct {
+	Config             transformertypes.Transformer
+	Env                *environment.Environment
+	ImageSigningConfig *ImageSigningConfig
+}
+
+// ImageSigningConfig stores the transformer specific configuration
+type ImageSigningConfig struct {
+	PolicyName string `yaml:"policyName"`
+}
+
+// ImageSigningTemplateConfig represents the data used to fill the signing scripts and the verification policies
+type ImageSigningTemplateConfig struct {
+	commonqa.SigningConfig
+	RegistryURL       string
+	RegistryNamespace string
+	Images            []string
+	PolicyName        string
+	ManifestsDir      string
+}
+
+// Init Initializes the transformer
+func (t *ImageSigning) Init(tc transformertypes.Transformer, env *environment.Environment) (err error) {
+	t.Config = tc
+	t.Env = env
+	t.ImageSigningConfig = &ImageSigningConfig{}
+	if err := common.GetObjFromInterface(t.Config.Spec.Config, t.ImageSigningConfig); err != nil {
+		logrus.Errorf("unable to load config for Transformer %+v into %T : %s", t.Config.Spec.Config, t.ImageSigningConfig, err)
+		return err
+	}
+	if t.ImageSigningConfig.PolicyName == "" {
+		t.ImageSigningConfig.PolicyName = "verify-signatures"
+	}
+	return nil
+}
+
+// GetConfig returns the transformer config
+func (t *ImageSigning) GetConfig() (transformertypes.Transformer, *environment.Environment) {
+	return t.Config, t.Env
+}
+
+// DirectoryDetect runs detect in each sub directory
+func (t *ImageSigning) DirectoryDetect(dir string) (services map[string][]transformertypes.Artifact, err error) {
+	return nil, nil
+}
+
+// Transform generates the scripts to sign the new images and the manifests using cosign along with the verification policies
+func (t *ImageSigning) Transform(newArtifacts []transformertypes.Artifact, alreadySeenArtifacts []transformertypes.Artifact) ([]transformertypes.PathMapping, []transformertypes.Artifact, error) {
+	images := []string{}
+	for _, a := range newArtifacts {
+		if a.Type != artifacts.NewImagesArtifactType {
+			continue
+		}
+		newImages := artifacts.NewImages{}
+		if err := a.GetConfig(artifacts.NewImagesConfigType, &newImages); err != nil {
+			logrus.Errorf("Unable to read Image config : %s", err)
+		}
+		images = common.MergeSlices(images, newImages.ImageNames)
+	}
+	if len(images) == 0 {
+		return nil, nil, nil
+	}
+	signing := commonqa.Signing()
+	if !signing.Enabled {
+		return nil, nil, nil
+	}
+	templateConfig := ImageSigningTemplateConfig{
+		SigningConfig:     signing,
+		RegistryURL:       commonqa.ImageRegistry(),
+		RegistryNamespace: commonqa.ImageRegistryNamespace(),
+		Images:            images,
+		PolicyName:        common.MakeStringK8sServiceNameCompliant(t.Env.GetProjectName() + "-" + t.ImageSigningConfig.PolicyName),
+		ManifestsDir:      common.DeployDir,
+	}
+	return []transformertypes.PathMapping{{
+		Type:           transformertypes.TemplatePathMappingType,
+		SrcPath:        filepath.Join(t.Env.Context, t.Config.Spec.TemplatesDir),
+		TemplateConfig: templateConfig,
+	}}, nil, nil
+}
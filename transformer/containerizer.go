@@ -36,8 +36,10 @@ func getContainerizationOptions(directory string) []string {
 	filters = filters.Add(*req)
 	for _, t := range GetInitializedTransformersF(filters) {
 		config, env := t.GetConfig()
+		unlock := lockTransformer(config.Name)
 		env.Reset()
 		newoptions, err := t.DirectoryDetect(directory)
+		unlock()
 		if err != nil {
 			logrus.Warnf("[%s] Failed during containerization option fetch : %s", config.Name, err)
 			continue
@@ -57,6 +59,7 @@ func getContainerizationConfig(serviceName string, projectDirectory, buildArtifa
 		return transformertypes.Artifact{}
 	}
 	tc, env := t.GetConfig()
+	defer lockTransformer(tc.Name)()
 	env.Reset()
 	newoptions, err := t.DirectoryDetect(projectDirectory[0])
 	if err != nil {
@@ -19,9 +19,12 @@ package dockerfile
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/konveyor/move2kube/common"
 	"github.com/konveyor/move2kube/environment"
+	"github.com/konveyor/move2kube/qaengine"
 	"github.com/konveyor/move2kube/types/qaengine/commonqa"
 	transformertypes "github.com/konveyor/move2kube/types/transformer"
 	"github.com/konveyor/move2kube/types/transformer/artifacts"
@@ -93,7 +96,9 @@ func (t *DockerfileImageBuildScript) Transform(newArtifacts []transformertypes.A
 	dockerfilesImageBuildConfig := []DockerfileImageBuildConfig{}
 	createdArtifacts := []transformertypes.Artifact{}
 	processedImages := map[string]bool{}
-	for _, artifact := range append(alreadySeenArtifacts, newArtifacts...) {
+	allArtifacts := append(alreadySeenArtifacts, newArtifacts...)
+	selectedDockerfiles := t.selectDockerfiles(allArtifacts)
+	for _, artifact := range allArtifacts {
 		if artifact.Type != artifacts.DockerfileArtifactType {
 			continue
 		}
@@ -105,7 +110,7 @@ func (t *DockerfileImageBuildScript) Transform(newArtifacts []transformertypes.A
 		if imageName.ImageName == "" {
 			imageName.ImageName = common.MakeStringContainerImageNameCompliant(artifact.Name)
 		}
-		if processedImages[imageName.ImageName] {
+		if processedImages[imageName.ImageName] || selectedDockerfiles[imageName.ImageName] != getDockerfilesKey(artifact) {
 			continue
 		}
 		processedImages[imageName.ImageName] = true
@@ -124,6 +129,7 @@ func (t *DockerfileImageBuildScript) Transform(newArtifacts []transformertypes.A
 				}
 			}
 			dockerfileImageBuildConfig.DockerfileName = relDockerfilePath
+			dockerfileImageBuildConfig.ImageName = getTaggedImageName(imageName.ImageName, dockerContextPath)
 			if common.IsParent(dockerfilePath, t.Env.GetEnvironmentSource()) {
 				relDockerContextPath, err := filepath.Rel(t.Env.GetEnvironmentSource(), filepath.Dir(dockerfilePath))
 				if err != nil {
@@ -152,7 +158,7 @@ func (t *DockerfileImageBuildScript) Transform(newArtifacts []transformertypes.A
 				Type: artifacts.NewImagesArtifactType,
 				Configs: map[transformertypes.ConfigType]interface{}{
 					artifacts.NewImagesConfigType: artifacts.NewImages{
-						ImageNames: []string{imageName.ImageName},
+						ImageNames: []string{dockerfileImageBuildConfig.ImageName},
 					},
 				},
 			})
@@ -218,3 +224,67 @@ func (t *DockerfileImageBuildScript) Transform(newArtifacts []transformertypes.A
 	})
 	return pathMappings, createdArtifacts, nil
 }
+
+// getTaggedImageName returns the image name with the tag chosen for the new images, so that the scripts build and push
+// the same tags that the generated manifests and pipelines refer to. Images without a tag keep their name if the default tag is used.
+func getTaggedImageName(imageName, contextPath string) string {
+	name, tag := imageName, ""
+	if i := strings.LastIndex(imageName, ":"); i > strings.LastIndex(imageName, "/") {
+		name, tag = imageName[:i], imageName[i+1:]
+	}
+	defaultTag := tag
+	if defaultTag == "" {
+		// like GetImageNameAndTag, no tag means latest
+		defaultTag = "latest"
+	}
+	newTag := commonqa.ImageTag(contextPath, defaultTag)
+	if tag == "" && newTag == defaultTag {
+		return imageName
+	}
+	return name + ":" + newTag
+}
+
+// selectDockerfiles returns the Dockerfiles to use for each image.
+// If different Dockerfiles build an image with the same name, the user is asked to choose one of them.
+func (t *DockerfileImageBuildScript) selectDockerfiles(allArtifacts []transformertypes.Artifact) map[string]string {
+	imageDockerfiles := map[string][]string{}
+	for _, artifact := range allArtifacts {
+		if artifact.Type != artifacts.DockerfileArtifactType {
+			continue
+		}
+		imageName := artifacts.ImageName{}
+		if err := artifact.GetConfig(artifacts.ImageNameConfigType, &imageName); err != nil {
+			continue
+		}
+		if imageName.ImageName == "" {
+			imageName.ImageName = common.MakeStringContainerImageNameCompliant(artifact.Name)
+		}
+		dockerfilesKey := getDockerfilesKey(artifact)
+		if !common.IsPresent(imageDockerfiles[imageName.ImageName], dockerfilesKey) {
+			imageDockerfiles[imageName.ImageName] = append(imageDockerfiles[imageName.ImageName], dockerfilesKey)
+		}
+	}
+	selectedDockerfiles := map[string]string{}
+	for imageName, dockerfilesKeys := range imageDockerfiles {
+		selectedDockerfiles[imageName] = dockerfilesKeys[0]
+		if len(dockerfilesKeys) == 1 {
+			continue
+		}
+		logrus.Warnf("The image %s is built by %d different Dockerfiles: %+v", imageName, len(dockerfilesKeys), dockerfilesKeys)
+		sort.Strings(dockerfilesKeys)
+		selectedDockerfiles[imageName] = qaengine.FetchSelectAnswer(
+			fmt.Sprintf(common.ConfigImagesDockerfileKey, `"`+imageName+`"`),
+			fmt.Sprintf("Different Dockerfiles build the image %s . Select the Dockerfile to use :", imageName),
+			[]string{"Only one of them can be built with this image name. Give the services different image names to build all of them."},
+			dockerfilesKeys[0],
+			dockerfilesKeys,
+			nil,
+		)
+	}
+	return selectedDockerfiles
+}
+
+// getDockerfilesKey returns a string identifying the Dockerfiles of the artifact
+func getDockerfilesKey(artifact transformertypes.Artifact) string {
+	return strings.Join(artifact.Paths[artifacts.DockerfilePathType], ",")
+}
@@ -86,6 +86,9 @@ func (t *DockerfileDetector) DirectoryDetect(dir string) (services map[string][]
 	if err != nil {
 		logrus.Warnf("Error in walking through files due to : %s", err)
 	}
+	if len(services) == 0 {
+		common.AddDetectionReason("no Dockerfiles were found in the directory or its subdirectories")
+	}
 	return services, nil
 }
 
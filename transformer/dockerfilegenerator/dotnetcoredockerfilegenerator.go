@@ -162,6 +162,7 @@ func (t *DotNetCoreDockerfileGenerator) DirectoryDetect(dir string) (services ma
 		return nil, fmt.Errorf("failed to list the dot net visual studio solution files in the directory %s . Error: %q", dir, err)
 	}
 	if len(slnPaths) == 0 {
+		common.AddDetectionReason("no %s files were found in the directory", dotnet.VISUAL_STUDIO_SOLUTION_FILE_EXT)
 		return nil, nil
 	}
 	if len(slnPaths) > 1 {
@@ -83,6 +83,7 @@ func (t *GolangDockerfileGenerator) DirectoryDetect(dir string) (map[string][]tr
 	modFilePath := filepath.Join(dir, "go.mod")
 	data, err := os.ReadFile(modFilePath)
 	if err != nil {
+		common.AddDetectionReason("no go.mod file was found in the directory")
 		return nil, nil
 	}
 	modFile, err := modfile.Parse(modFilePath, data, nil)
@@ -92,6 +93,7 @@ func (t *GolangDockerfileGenerator) DirectoryDetect(dir string) (map[string][]tr
 	prefix, _, ok := module.SplitPathVersion(modFile.Module.Mod.Path)
 	if !ok {
 		logrus.Errorf("Invalid module path")
+		common.AddDetectionReason("the module path %s in the go.mod file is invalid", modFile.Module.Mod.Path)
 		return nil, nil
 	}
 	serviceName := filepath.Base(prefix)
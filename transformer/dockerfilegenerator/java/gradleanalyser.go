@@ -267,6 +267,7 @@ func (t *GradleAnalyser) DirectoryDetect(dir string) (map[string][]transformerty
 	// if both are missing then skip
 
 	if len(gradleSettingsFilePaths) == 0 && len(gradleBuildFilePaths) == 0 {
+		common.AddDetectionReason("neither a %s nor a %s file was found in the directory", gradleSettingsFileName, gradleBuildFileName)
 		return nil, nil
 	}
 
@@ -112,6 +112,7 @@ func (t *MavenAnalyser) DirectoryDetect(dir string) (map[string][]transformertyp
 	// if pom.xml is missing then skip
 
 	if len(mavenFilePaths) == 0 {
+		common.AddDetectionReason("no %s file was found in the directory", maven.PomXMLFileName)
 		return nil, nil
 	}
 
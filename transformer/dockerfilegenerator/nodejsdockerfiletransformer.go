@@ -158,6 +158,7 @@ func (t *NodejsDockerfileGenerator) DirectoryDetect(dir string) (map[string][]tr
 	packageJson := PackageJSON{}
 	if err := common.ReadJSON(packageJsonPath, &packageJson); err != nil {
 		logrus.Debugf("failed to read the package.json file at the path %s . Error: %q", packageJsonPath, err)
+		common.AddDetectionReason("no readable %s file was found in the directory", packageJSONFile)
 		return nil, nil
 	}
 	if packageJson.Name == "" {
@@ -148,6 +148,7 @@ func (t *PHPDockerfileGenerator) DirectoryDetect(dir string) (map[string][]trans
 		return nil, fmt.Errorf("failed to look for .php files in the directory %s . Error: %q", dir, err)
 	}
 	if len(phpFiles) == 0 {
+		common.AddDetectionReason("no %s files were found in the directory", phpExt)
 		return nil, nil
 	}
 	serviceName := filepath.Base(dir)
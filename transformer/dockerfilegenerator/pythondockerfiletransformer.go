@@ -86,6 +86,7 @@ func (t *PythonDockerfileGenerator) GetConfig() (transformertypes.Transformer, *
 // findMainScripts returns the path of .py files having the main function
 func findMainScripts(pythonFilesPath []string) ([]string, error) {
 	if len(pythonFilesPath) == 0 {
+		common.AddDetectionReason("no %s files were found in the directory", pythonExt)
 		return nil, nil
 	}
 	pythonMainFiles := []string{}
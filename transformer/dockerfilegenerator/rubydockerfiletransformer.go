@@ -64,6 +64,7 @@ func (t *RubyDockerfileGenerator) DirectoryDetect(dir string) (map[string][]tran
 		return nil, fmt.Errorf("failed to look for Gemfiles in the dir %s . Error: %q", dir, err)
 	}
 	if len(gemfilePaths) == 0 {
+		common.AddDetectionReason("no Gemfile was found in the directory")
 		return nil, nil
 	}
 	rubyFiles, err := common.GetFilesByExt(dir, []string{rubyFileExt})
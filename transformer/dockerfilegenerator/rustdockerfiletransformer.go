@@ -88,6 +88,7 @@ func (t *RustDockerfileGenerator) GetConfig() (transformertypes.Transformer, *en
 func (t *RustDockerfileGenerator) DirectoryDetect(dir string) (map[string][]transformertypes.Artifact, error) {
 	cargoPath := filepath.Join(dir, cargoTomlFile)
 	if _, err := os.Stat(cargoPath); err != nil {
+		common.AddDetectionReason("no %s file was found in the directory", cargoTomlFile)
 		return nil, nil
 	}
 	cargoTomlConfig := CargoTomlConfig{}
@@ -0,0 +1,82 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package transformer
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/konveyor/move2kube/common"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+)
+
+var (
+	detectionExplanation      = plantypes.DetectionExplanation{Directories: []plantypes.DirectoryExplanation{}}
+	detectionExplanationIndex = map[string]int{} // [directory]index in detectionExplanation
+)
+
+// GetDetectionExplanation returns the detection decisions recorded while planning when the explanation is enabled
+func GetDetectionExplanation() plantypes.DetectionExplanation {
+	return detectionExplanation
+}
+
+// getDirectoryExplanation returns the explanation of the directory, relative to the source directory
+func getDirectoryExplanation(sourceDir, dir string) *plantypes.DirectoryExplanation {
+	relDir, err := filepath.Rel(sourceDir, dir)
+	if err != nil {
+		relDir = dir
+	}
+	idx, ok := detectionExplanationIndex[relDir]
+	if !ok {
+		idx = len(detectionExplanation.Directories)
+		detectionExplanation.Directories = append(detectionExplanation.Directories, plantypes.DirectoryExplanation{Path: relDir})
+		detectionExplanationIndex[relDir] = idx
+	}
+	return &detectionExplanation.Directories[idx]
+}
+
+// explainDetection records the services detected by the transformer in the directory along with the reasons it gave
+func explainDetection(sourceDir, dir, transformerName string, services map[string][]transformertypes.Artifact, err error) {
+	if !common.IsExplainDetectionEnabled() {
+		return
+	}
+	explanation := plantypes.TransformerExplanation{Name: transformerName, Reasons: common.TakeDetectionReasons()}
+	if err != nil {
+		explanation.Error = err.Error()
+	}
+	for serviceName, artifacts := range services {
+		explanation.Matched = explanation.Matched || len(artifacts) > 0
+		if serviceName != "" {
+			explanation.Services = append(explanation.Services, serviceName)
+		}
+	}
+	sort.Strings(explanation.Services)
+	if !explanation.Matched && err == nil && len(explanation.Reasons) == 0 {
+		explanation.Reasons = []string{"the transformer did not find anything it can transform in the directory"}
+	}
+	dirExplanation := getDirectoryExplanation(sourceDir, dir)
+	dirExplanation.Transformers = append(dirExplanation.Transformers, explanation)
+}
+
+// explainSkippedDirectory records why the directory was not searched for services
+func explainSkippedDirectory(sourceDir, dir, reason string) {
+	if !common.IsExplainDetectionEnabled() {
+		return
+	}
+	getDirectoryExplanation(sourceDir, dir).Skipped = reason
+}
@@ -21,6 +21,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/dchest/uniuri"
 	"github.com/konveyor/move2kube/common"
@@ -37,6 +38,9 @@ type Executable struct {
 	Config     transformertypes.Transformer
 	Env        *environment.Environment
 	ExecConfig *ExecutableYamlConfig
+
+	// timeout is the duration after which the detect and transform commands are killed
+	timeout time.Duration
 }
 
 // ExecutableYamlConfig is the format of executable yaml config
@@ -63,11 +67,20 @@ const (
 func (t *Executable) Init(tc transformertypes.Transformer, env *environment.Environment) error {
 	t.Config = tc
 	t.ExecConfig = &ExecutableYamlConfig{}
+	if tc.Spec.FilePath != "" {
+		if err := transformertypes.ValidateTransformerConfigFile(tc.Spec.FilePath, transformertypes.GenerateConfigSchema(ExecutableYamlConfig{})); err != nil {
+			return err
+		}
+	}
 	if err := common.GetObjFromInterface(t.Config.Spec.Config, t.ExecConfig); err != nil {
 		return fmt.Errorf("unable to load config for Transformer %+v into %T . Error: %q", t.Config.Spec.Config, t.ExecConfig, err)
 	}
-	var qaRPCReceiverAddr net.Addr
 	var err error
+	t.timeout, err = t.Config.Spec.GetTimeout()
+	if err != nil {
+		return fmt.Errorf("invalid timeout for the transformer %s . Error: %w", t.Config.Name, err)
+	}
+	var qaRPCReceiverAddr net.Addr
 	if t.ExecConfig.EnableQA {
 		qaRPCReceiverAddr, err = questionreceivers.StartGRPCReceiver()
 		if err != nil {
@@ -107,17 +120,7 @@ func (t *Executable) DirectoryDetect(dir string) (services map[string][]transfor
 	if err != nil {
 		return services, fmt.Errorf("failed to execute the detect script. Error: %w", err)
 	}
-	for sn, ns := range services {
-		for nsi, nst := range ns {
-			if len(nst.Paths) == 0 {
-				nst.Paths = map[transformertypes.PathType][]string{
-					artifacts.ServiceDirPathType: {dir},
-				}
-				ns[nsi] = nst
-			}
-		}
-		services[sn] = ns
-	}
+	setDefaultServicePaths(services, dir)
 	return services, nil
 }
 
@@ -138,7 +141,7 @@ func (t *Executable) Transform(newArtifacts []transformertypes.Artifact, already
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to upload the transform input into the environment at the path '%s' . Error: %w", transformInputFile, err)
 	}
-	stdout, stderr, exitcode, err := t.Env.Exec(append(t.ExecConfig.TransformCMD, filepath.Join(containerInputDir, transformInputFile)))
+	stdout, stderr, exitcode, err := t.Env.ExecWithTimeout(append(t.ExecConfig.TransformCMD, filepath.Join(containerInputDir, transformInputFile)), t.timeout)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to run the transform.\nstdout: %s\nstderr: %s\nexit code: %d . Error: %w", stdout, stderr, exitcode, err)
 	}
@@ -162,7 +165,7 @@ func (t *Executable) Transform(newArtifacts []transformertypes.Artifact, already
 }
 
 func (t *Executable) executeDetect(cmd environmenttypes.Command, dir string) (services map[string][]transformertypes.Artifact, err error) {
-	stdout, stderr, exitcode, err := t.Env.Exec(append(cmd, dir))
+	stdout, stderr, exitcode, err := t.Env.ExecWithTimeout(append(cmd, dir), t.timeout)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute the command in the environment.\nstdout: %s\nstderr: %s\nexit code: %d\nError: %w", stdout, stderr, exitcode, err)
 	} else if exitcode != 0 {
@@ -192,13 +195,33 @@ func (t *Executable) executeDetect(cmd environmenttypes.Command, dir string) (se
 }
 
 func (t *Executable) uploadInput(data interface{}, inputFile string) (string, error) {
-	inputDirPath := filepath.Join(t.Env.TempPath, uniuri.NewLen(5))
+	return uploadInput(t.Env, data, inputFile)
+}
+
+// uploadInput writes the data as json to the input file in a new directory, uploads it into the environment
+// and returns the path of the directory within the environment
+// setDefaultServicePaths sets the directory as the service directory of the detected artifacts that don't have any paths
+func setDefaultServicePaths(services map[string][]transformertypes.Artifact, dir string) {
+	for _, ns := range services {
+		for nsi, nst := range ns {
+			if len(nst.Paths) == 0 {
+				nst.Paths = map[transformertypes.PathType][]string{
+					artifacts.ServiceDirPathType: {dir},
+				}
+				ns[nsi] = nst
+			}
+		}
+	}
+}
+
+func uploadInput(env *environment.Environment, data interface{}, inputFile string) (string, error) {
+	inputDirPath := filepath.Join(env.TempPath, uniuri.NewLen(5))
 	os.MkdirAll(inputDirPath, common.DefaultDirectoryPermission)
 	inputFilePath := filepath.Join(inputDirPath, inputFile)
 	if err := common.WriteJSON(inputFilePath, data); err != nil {
 		return "", fmt.Errorf("failed to create the input json. Error: %w", err)
 	}
-	containerInputDir, err := t.Env.Env.Upload(inputDirPath)
+	containerInputDir, err := env.Env.Upload(inputDirPath)
 	if err != nil {
 		return "", fmt.Errorf("failed to copy input dir to new container image. Error: %w", err)
 	}
@@ -0,0 +1,189 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/environment"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultHTTPMaxAttempts is the number of times a request is tried if the max attempts are not configured
+	defaultHTTPMaxAttempts = 3
+)
+
+var (
+	// httpRetryDelay is the delay before the first retry. It doubles for every retry.
+	httpRetryDelay = time.Second
+)
+
+// HTTP implements transformer interface and is used to call external transformers running as REST services.
+// The directory detect and transform inputs are POSTed as json, same as the input files of the Executable class,
+// and the responses are the json outputs.
+type HTTP struct {
+	Config     transformertypes.Transformer
+	Env        *environment.Environment
+	HTTPConfig *HTTPYamlConfig
+
+	// timeout is the duration after which the directory detect and transform requests are stopped, including the retries
+	timeout time.Duration
+}
+
+// HTTPYamlConfig is the format of the HTTP transformer yaml config
+type HTTPYamlConfig struct {
+	DirectoryDetectURL string `yaml:"directoryDetectURL,omitempty"` // Directory detect is skipped if empty
+	TransformURL       string `yaml:"transformURL"`
+	// BearerTokenEnv is the name of the environment variable containing the token sent in the Authorization header
+	BearerTokenEnv string `yaml:"bearerTokenEnv,omitempty"`
+	// MaxAttempts is the number of times a request is tried on network errors and server errors
+	MaxAttempts int `yaml:"maxAttempts,omitempty"`
+}
+
+// Init Initializes the transformer
+func (t *HTTP) Init(tc transformertypes.Transformer, env *environment.Environment) (err error) {
+	t.Config = tc
+	t.Env = env
+	t.HTTPConfig = &HTTPYamlConfig{}
+	if tc.Spec.FilePath != "" {
+		httpConfigSchema := transformertypes.GenerateConfigSchema(HTTPYamlConfig{})
+		httpConfigSchema["required"] = []string{"transformURL"}
+		if err := transformertypes.ValidateTransformerConfigFile(tc.Spec.FilePath, httpConfigSchema); err != nil {
+			return err
+		}
+	}
+	if err := common.GetObjFromInterface(t.Config.Spec.Config, t.HTTPConfig); err != nil {
+		return fmt.Errorf("unable to load config for Transformer %+v into %T . Error: %w", t.Config.Spec.Config, t.HTTPConfig, err)
+	}
+	if t.HTTPConfig.MaxAttempts <= 0 {
+		t.HTTPConfig.MaxAttempts = defaultHTTPMaxAttempts
+	}
+	if t.HTTPConfig.BearerTokenEnv != "" {
+		if _, ok := os.LookupEnv(t.HTTPConfig.BearerTokenEnv); !ok {
+			return fmt.Errorf("the environment variable %s containing the bearer token of the transformer %s is not set", t.HTTPConfig.BearerTokenEnv, t.Config.Name)
+		}
+	}
+	t.timeout, err = t.Config.Spec.GetTimeout()
+	if err != nil {
+		return fmt.Errorf("invalid timeout for the transformer %s . Error: %w", t.Config.Name, err)
+	}
+	return nil
+}
+
+// GetConfig returns the transformer config
+func (t *HTTP) GetConfig() (transformertypes.Transformer, *environment.Environment) {
+	return t.Config, t.Env
+}
+
+// DirectoryDetect runs detect in each sub directory
+func (t *HTTP) DirectoryDetect(dir string) (services map[string][]transformertypes.Artifact, err error) {
+	if t.HTTPConfig.DirectoryDetectURL == "" {
+		return nil, nil
+	}
+	services = map[string][]transformertypes.Artifact{}
+	if err := t.post(t.HTTPConfig.DirectoryDetectURL, map[string]string{"InputDirectory": dir}, &services); err != nil {
+		return nil, fmt.Errorf("failed to detect in the directory %s . Error: %w", dir, err)
+	}
+	setDefaultServicePaths(services, dir)
+	return services, nil
+}
+
+// Transform transforms the artifacts
+func (t *HTTP) Transform(newArtifacts []transformertypes.Artifact, alreadySeenArtifacts []transformertypes.Artifact) ([]transformertypes.PathMapping, []transformertypes.Artifact, error) {
+	input := transformertypes.TransformInput{
+		NewArtifacts:         newArtifacts,
+		AlreadySeenArtifacts: alreadySeenArtifacts,
+	}
+	output := transformertypes.TransformOutput{}
+	if err := t.post(t.HTTPConfig.TransformURL, input, &output); err != nil {
+		return nil, nil, fmt.Errorf("failed to transform the artifacts. Error: %w", err)
+	}
+	return output.PathMappings, output.CreatedArtifacts, nil
+}
+
+// post sends the input as json to the url and decodes the json response into the output,
+// retrying on network errors, server errors and when being rate limited
+func (t *HTTP) post(url string, input interface{}, output interface{}) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the request body to json. Error: %w", err)
+	}
+	ctx := context.Background()
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+	delay := httpRetryDelay
+	for attempt := 1; attempt <= t.HTTPConfig.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			logrus.Debugf("retrying the request to %s for the transformer %s in %s . Error: %q", url, t.Config.Name, delay, err)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return fmt.Errorf("the request to %s did not succeed in %s . Error: %w", url, t.timeout, err)
+			}
+			delay *= 2
+		}
+		var retry bool
+		retry, err = t.postOnce(ctx, url, body, output)
+		if err == nil || !retry {
+			return err
+		}
+	}
+	return err
+}
+
+// postOnce sends the request and returns true along with the error if the request can succeed on a retry
+func (t *HTTP) postOnce(ctx context.Context, url string, body []byte, output interface{}) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create the request to %s . Error: %w", url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if t.HTTPConfig.BearerTokenEnv != "" {
+		req.Header.Set("Authorization", "Bearer "+os.Getenv(t.HTTPConfig.BearerTokenEnv))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("failed to send the request to %s . Error: %w", url, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("failed to read the response from %s . Error: %w", url, err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		retry := resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+		return retry, fmt.Errorf("%s responded with the status %d and the body: %s", url, resp.StatusCode, string(respBody))
+	}
+	if err := json.Unmarshal(respBody, output); err != nil {
+		return false, fmt.Errorf("failed to unmarshal the response from %s as json. Error: %w", url, err)
+	}
+	return false, nil
+}
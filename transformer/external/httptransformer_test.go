@@ -0,0 +1,173 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/konveyor/move2kube/types/transformer/artifacts"
+)
+
+// testHTTPServer responds with the statuses in order, followed by the response for all the later requests
+type testHTTPServer struct {
+	mutex         sync.Mutex
+	statuses      []int
+	response      interface{}
+	requestTimes  []time.Time
+	authorization []string
+}
+
+func (s *testHTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requestTimes = append(s.requestTimes, time.Now())
+	s.authorization = append(s.authorization, r.Header.Get("Authorization"))
+	if len(s.statuses) > 0 {
+		status := s.statuses[0]
+		s.statuses = s.statuses[1:]
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.response)
+}
+
+func newTestHTTPTransformer(t *testing.T, config map[string]interface{}) (*HTTP, error) {
+	t.Helper()
+	tc := transformertypes.Transformer{}
+	tc.Name = "http"
+	tc.Spec.Config = config
+	transformer := &HTTP{}
+	return transformer, transformer.Init(tc, nil)
+}
+
+func setTestHTTPRetryDelay(t *testing.T, delay time.Duration) {
+	t.Helper()
+	oldDelay := httpRetryDelay
+	httpRetryDelay = delay
+	t.Cleanup(func() { httpRetryDelay = oldDelay })
+}
+
+func TestHTTPTransformerRetries(t *testing.T) {
+	const delay = 20 * time.Millisecond
+	setTestHTTPRetryDelay(t, delay)
+
+	t.Run("server errors are retried with backoff", func(t *testing.T) {
+		server := &testHTTPServer{
+			statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests},
+			response: map[string][]transformertypes.Artifact{"web": {{Name: "web", Type: "Service"}}},
+		}
+		ts := httptest.NewServer(server)
+		defer ts.Close()
+		transformer, err := newTestHTTPTransformer(t, map[string]interface{}{"directoryDetectURL": ts.URL, "transformURL": ts.URL})
+		if err != nil {
+			t.Fatalf("failed to initialize the transformer. Error: %q", err)
+		}
+		services, err := transformer.DirectoryDetect("/source")
+		if err != nil {
+			t.Fatalf("failed to detect. Error: %q", err)
+		}
+		want := map[string][]transformertypes.Artifact{
+			"web": {{Name: "web", Type: "Service", Paths: map[transformertypes.PathType][]string{artifacts.ServiceDirPathType: {"/source"}}}},
+		}
+		if !cmp.Equal(services, want) {
+			t.Fatalf("the detected services are incorrect. Differences:\n%s", cmp.Diff(want, services))
+		}
+		if len(server.requestTimes) != 3 {
+			t.Fatalf("expected 3 requests. Actual: %d", len(server.requestTimes))
+		}
+		if gap := server.requestTimes[1].Sub(server.requestTimes[0]); gap < delay {
+			t.Fatalf("expected the first retry after at least %s . Actual: %s", delay, gap)
+		}
+		if gap := server.requestTimes[2].Sub(server.requestTimes[1]); gap < 2*delay {
+			t.Fatalf("expected the delay to double for the second retry to at least %s . Actual: %s", 2*delay, gap)
+		}
+	})
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		server := &testHTTPServer{statuses: []int{http.StatusBadRequest}}
+		ts := httptest.NewServer(server)
+		defer ts.Close()
+		transformer, err := newTestHTTPTransformer(t, map[string]interface{}{"transformURL": ts.URL})
+		if err != nil {
+			t.Fatalf("failed to initialize the transformer. Error: %q", err)
+		}
+		if _, _, err := transformer.Transform(nil, nil); err == nil {
+			t.Fatalf("expected the transform to fail")
+		}
+		if len(server.requestTimes) != 1 {
+			t.Fatalf("expected 1 request. Actual: %d", len(server.requestTimes))
+		}
+	})
+
+	t.Run("the request fails after the max attempts", func(t *testing.T) {
+		server := &testHTTPServer{statuses: []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError}}
+		ts := httptest.NewServer(server)
+		defer ts.Close()
+		transformer, err := newTestHTTPTransformer(t, map[string]interface{}{"transformURL": ts.URL, "maxAttempts": 2})
+		if err != nil {
+			t.Fatalf("failed to initialize the transformer. Error: %q", err)
+		}
+		_, _, err = transformer.Transform(nil, nil)
+		if err == nil || !strings.Contains(err.Error(), "500") {
+			t.Fatalf("expected the transform to fail with the server error. Actual: %v", err)
+		}
+		if len(server.requestTimes) != 2 {
+			t.Fatalf("expected 2 requests. Actual: %d", len(server.requestTimes))
+		}
+	})
+}
+
+func TestHTTPTransformerBearerToken(t *testing.T) {
+	const tokenEnv = "M2K_TEST_HTTP_TRANSFORMER_TOKEN"
+
+	t.Run("the token is sent in the authorization header", func(t *testing.T) {
+		t.Setenv(tokenEnv, "secret")
+		server := &testHTTPServer{response: transformertypes.TransformOutput{CreatedArtifacts: []transformertypes.Artifact{{Name: "web", Type: "Dockerfile"}}}}
+		ts := httptest.NewServer(server)
+		defer ts.Close()
+		transformer, err := newTestHTTPTransformer(t, map[string]interface{}{"transformURL": ts.URL, "bearerTokenEnv": tokenEnv})
+		if err != nil {
+			t.Fatalf("failed to initialize the transformer. Error: %q", err)
+		}
+		_, createdArtifacts, err := transformer.Transform([]transformertypes.Artifact{{Name: "web", Type: "Service"}}, nil)
+		if err != nil {
+			t.Fatalf("failed to transform. Error: %q", err)
+		}
+		want := []transformertypes.Artifact{{Name: "web", Type: "Dockerfile"}}
+		if !cmp.Equal(createdArtifacts, want) {
+			t.Fatalf("the created artifacts are incorrect. Differences:\n%s", cmp.Diff(want, createdArtifacts))
+		}
+		if want := []string{"Bearer secret"}; !cmp.Equal(server.authorization, want) {
+			t.Fatalf("the authorization headers are incorrect. Differences:\n%s", cmp.Diff(want, server.authorization))
+		}
+	})
+
+	t.Run("a missing token environment variable fails the init", func(t *testing.T) {
+		if _, err := newTestHTTPTransformer(t, map[string]interface{}{"transformURL": "http://localhost", "bearerTokenEnv": tokenEnv + "_MISSING"}); err == nil {
+			t.Fatalf("expected the init to fail when the token environment variable is not set")
+		}
+	})
+}
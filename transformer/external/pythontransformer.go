@@ -0,0 +1,205 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/environment"
+	environmenttypes "github.com/konveyor/move2kube/types/environment"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultPythonInterpreter  = "python3"
+	defaultPythonRequirements = "requirements.txt"
+	// pythonVenvDir is the virtualenv created in a temporary directory within the environment
+	pythonVenvDir         = ".m2k-venv"
+	pythonDetectFnName    = "directory_detect"
+	pythonTransformFnName = "transform"
+)
+
+// Python implements transformer interface and is used to write external transformers in python.
+// A virtualenv with the requirements of the transformer is created in a temporary directory within the environment
+// during Init, so that the customizations directory is not modified.
+// The entrypoint is run with directory_detect or transform as its argument and the same json input as the
+// Executable class on its stdin, and it writes the json output to its stdout.
+type Python struct {
+	Config       transformertypes.Transformer
+	Env          *environment.Environment
+	PythonConfig *PythonYamlConfig
+
+	// timeout is the duration after which the entrypoint is killed
+	timeout time.Duration
+	// venvPath is the path of the virtualenv within the environment
+	venvPath string
+}
+
+// PythonYamlConfig is the format of the python transformer yaml config
+type PythonYamlConfig struct {
+	Entrypoint   string                     `yaml:"entrypoint"`             // Relative to yaml directory
+	Requirements string                     `yaml:"requirements,omitempty"` // Relative to yaml directory, default is requirements.txt if it exists
+	Python       string                     `yaml:"python,omitempty"`       // Interpreter used to create the virtualenv, default is python3
+	Platforms    []string                   `yaml:"platforms,omitempty"`
+	Container    environmenttypes.Container `yaml:"container,omitempty"`
+}
+
+// Init Initializes the transformer
+func (t *Python) Init(tc transformertypes.Transformer, env *environment.Environment) (err error) {
+	t.Config = tc
+	t.PythonConfig = &PythonYamlConfig{}
+	if tc.Spec.FilePath != "" {
+		pythonConfigSchema := transformertypes.GenerateConfigSchema(PythonYamlConfig{})
+		pythonConfigSchema["required"] = []string{"entrypoint"}
+		if err := transformertypes.ValidateTransformerConfigFile(tc.Spec.FilePath, pythonConfigSchema); err != nil {
+			return err
+		}
+	}
+	if err := common.GetObjFromInterface(t.Config.Spec.Config, t.PythonConfig); err != nil {
+		return fmt.Errorf("unable to load config for Transformer %+v into %T . Error: %w", t.Config.Spec.Config, t.PythonConfig, err)
+	}
+	if t.PythonConfig.Python == "" {
+		t.PythonConfig.Python = defaultPythonInterpreter
+	}
+	t.timeout, err = t.Config.Spec.GetTimeout()
+	if err != nil {
+		return fmt.Errorf("invalid timeout for the transformer %s . Error: %w", t.Config.Name, err)
+	}
+	platforms := t.PythonConfig.Platforms
+	if len(platforms) == 0 {
+		platforms = []string{"linux", "darwin"}
+	}
+	env.EnvInfo.EnvPlatformConfig = environmenttypes.EnvPlatformConfig{
+		Container: t.PythonConfig.Container,
+		Platforms: platforms,
+	}
+	t.Env, err = environment.NewEnvironment(env.EnvInfo, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create the environment for the python transformer. Error: %w", err)
+	}
+	if err := t.installRequirements(); err != nil {
+		return fmt.Errorf("failed to install the requirements of the python transformer %s . Error: %w", t.Config.Name, err)
+	}
+	return nil
+}
+
+// GetConfig returns the transformer config
+func (t *Python) GetConfig() (transformertypes.Transformer, *environment.Environment) {
+	return t.Config, t.Env
+}
+
+// DirectoryDetect runs detect in each sub directory
+func (t *Python) DirectoryDetect(dir string) (services map[string][]transformertypes.Artifact, err error) {
+	services = map[string][]transformertypes.Artifact{}
+	if err := t.run(pythonDetectFnName, map[string]string{"InputDirectory": dir}, detectInputFile, &services); err != nil {
+		return nil, fmt.Errorf("failed to detect in the directory %s . Error: %w", dir, err)
+	}
+	setDefaultServicePaths(services, dir)
+	return services, nil
+}
+
+// Transform transforms the artifacts
+func (t *Python) Transform(newArtifacts []transformertypes.Artifact, alreadySeenArtifacts []transformertypes.Artifact) ([]transformertypes.PathMapping, []transformertypes.Artifact, error) {
+	input := transformertypes.TransformInput{
+		NewArtifacts:         newArtifacts,
+		AlreadySeenArtifacts: alreadySeenArtifacts,
+	}
+	output := transformertypes.TransformOutput{}
+	if err := t.run(pythonTransformFnName, input, transformInputFile, &output); err != nil {
+		return nil, nil, fmt.Errorf("failed to transform the artifacts. Error: %w", err)
+	}
+	return output.PathMappings, output.CreatedArtifacts, nil
+}
+
+// installRequirements creates the virtualenv and installs the requirements into it
+func (t *Python) installRequirements() error {
+	venvParentPath, err := os.MkdirTemp(t.Env.TempPath, "venv-*")
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary directory for the virtualenv. Error: %w", err)
+	}
+	envVenvParentPath, err := t.Env.Env.Upload(venvParentPath)
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary directory for the virtualenv within the environment. Error: %w", err)
+	}
+	t.venvPath = filepath.Join(envVenvParentPath, pythonVenvDir)
+	if err := t.exec(environmenttypes.Command{t.PythonConfig.Python, "-m", "venv", t.venvPath}, 0); err != nil {
+		return fmt.Errorf("failed to create the virtualenv at path %s . Error: %w", t.venvPath, err)
+	}
+	contextPath := t.Env.GetEnvironmentContext()
+	requirements := t.PythonConfig.Requirements
+	if requirements == "" {
+		requirements = defaultPythonRequirements
+		if _, err := t.Env.Env.Stat(filepath.Join(contextPath, requirements)); err != nil {
+			logrus.Debugf("the python transformer %s does not have a %s file", t.Config.Name, requirements)
+			return nil
+		}
+	}
+	requirementsPath := filepath.Join(contextPath, requirements)
+	pipCmd := environmenttypes.Command{filepath.Join(t.venvPath, "bin", "pip"), "install", "--disable-pip-version-check", "-r", requirementsPath}
+	if err := t.exec(pipCmd, 0); err != nil {
+		return fmt.Errorf("failed to install the requirements at path %s . Error: %w", requirementsPath, err)
+	}
+	return nil
+}
+
+// run runs the entrypoint with the function name as its argument and the json input on its stdin,
+// and decodes the json written to its stdout into the output
+func (t *Python) run(fnName string, input interface{}, inputFile string, output interface{}) error {
+	inputDir, err := uploadInput(t.Env, input, inputFile)
+	if err != nil {
+		return fmt.Errorf("failed to upload the input of '%s' into the environment. Error: %w", fnName, err)
+	}
+	contextPath := t.Env.GetEnvironmentContext()
+	cmd := environmenttypes.Command{
+		"/bin/sh", "-c", `exec "$0" "$1" "$2" < "$3"`,
+		filepath.Join(t.venvPath, "bin", "python"),
+		filepath.Join(contextPath, t.PythonConfig.Entrypoint),
+		fnName,
+		filepath.Join(inputDir, inputFile),
+	}
+	stdout, stderr, exitcode, err := t.Env.ExecWithTimeout(cmd, t.timeout)
+	if err != nil {
+		return fmt.Errorf("failed to run the entrypoint.\nstdout: %s\nstderr: %s\nexit code: %d . Error: %w", stdout, stderr, exitcode, err)
+	}
+	if exitcode != 0 {
+		return fmt.Errorf("the entrypoint failed with non-zero exit code.\nstdout: %s\nstderr: %s\nexit code: %d", stdout, stderr, exitcode)
+	}
+	logrus.Debugf("the '%s' of the python transformer %s succeeded.\nstderr: %s", fnName, t.Config.Name, stderr)
+	if err := json.Unmarshal([]byte(stdout), output); err != nil {
+		return fmt.Errorf("failed to unmarshal the stdout of the entrypoint as json.\nstdout: %s\nError: %w", stdout, err)
+	}
+	return nil
+}
+
+// exec runs the command in the environment and returns an error if it fails
+func (t *Python) exec(cmd environmenttypes.Command, timeout time.Duration) error {
+	stdout, stderr, exitcode, err := t.Env.ExecWithTimeout(cmd, timeout)
+	if err != nil {
+		return fmt.Errorf("failed to run the command %v .\nstdout: %s\nstderr: %s\nexit code: %d . Error: %w", cmd, stdout, stderr, exitcode, err)
+	}
+	if exitcode != 0 {
+		return fmt.Errorf("the command %v failed with non-zero exit code.\nstdout: %s\nstderr: %s\nexit code: %d", cmd, stdout, stderr, exitcode)
+	}
+	logrus.Debugf("the command %v succeeded.\nstdout: %s\nstderr: %s", cmd, stdout, stderr)
+	return nil
+}
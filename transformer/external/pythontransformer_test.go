@@ -0,0 +1,96 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/environment"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/konveyor/move2kube/types/transformer/artifacts"
+)
+
+const testPythonEntrypoint = `import json
+import sys
+
+data = json.load(sys.stdin)
+if sys.argv[1] == "directory_detect":
+    print(json.dumps({"web": [{"name": "web", "type": "Service"}]}))
+else:
+    print(json.dumps({"artifacts": [{"name": a["name"], "type": "Dockerfile"} for a in data["newArtifacts"]]}))
+`
+
+func TestPythonTransformer(t *testing.T) {
+	if _, err := exec.LookPath(defaultPythonInterpreter); err != nil {
+		t.Skipf("%s is not installed", defaultPythonInterpreter)
+	}
+	oldTempPath := common.TempPath
+	defer func() { common.TempPath = oldTempPath }()
+	common.TempPath = t.TempDir()
+	contextPath := t.TempDir()
+	sourcePath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(contextPath, "main.py"), []byte(testPythonEntrypoint), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tc := transformertypes.Transformer{}
+	tc.Name = "python"
+	tc.Spec.Config = map[string]interface{}{"entrypoint": "main.py"}
+	env := &environment.Environment{EnvInfo: environment.EnvInfo{Name: "python", Context: contextPath, Source: sourcePath}}
+	transformer := &Python{}
+	if err := transformer.Init(tc, env); err != nil {
+		t.Fatalf("failed to initialize the transformer. Error: %q", err)
+	}
+	defer transformer.Env.Destroy()
+
+	t.Run("the virtualenv is not created in the transformer directory", func(t *testing.T) {
+		if _, err := os.Stat(filepath.Join(contextPath, pythonVenvDir)); !os.IsNotExist(err) {
+			t.Fatalf("expected no virtualenv in the transformer directory %s . Error: %v", contextPath, err)
+		}
+		if !common.IsParent(transformer.venvPath, common.TempPath) {
+			t.Fatalf("expected the virtualenv %s to be in the temp directory %s", transformer.venvPath, common.TempPath)
+		}
+	})
+
+	t.Run("detect output is decoded and the paths are filled in", func(t *testing.T) {
+		services, err := transformer.DirectoryDetect(sourcePath)
+		if err != nil {
+			t.Fatalf("failed to detect. Error: %q", err)
+		}
+		want := map[string][]transformertypes.Artifact{
+			"web": {{Name: "web", Type: "Service", Paths: map[transformertypes.PathType][]string{artifacts.ServiceDirPathType: {sourcePath}}}},
+		}
+		if !cmp.Equal(services, want) {
+			t.Fatalf("the detected services are incorrect. Differences:\n%s", cmp.Diff(want, services))
+		}
+	})
+
+	t.Run("transform output is decoded", func(t *testing.T) {
+		_, createdArtifacts, err := transformer.Transform([]transformertypes.Artifact{{Name: "web", Type: "Service"}}, nil)
+		if err != nil {
+			t.Fatalf("failed to transform. Error: %q", err)
+		}
+		want := []transformertypes.Artifact{{Name: "web", Type: "Dockerfile"}}
+		if !cmp.Equal(createdArtifacts, want) {
+			t.Fatalf("the created artifacts are incorrect. Differences:\n%s", cmp.Diff(want, createdArtifacts))
+		}
+	})
+}
@@ -0,0 +1,124 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+const debuggerContinueCommand = "continue"
+
+func (t *Starlark) getStarlarkBreakpoint() *starlark.Builtin {
+	return starlark.NewBuiltin(breakpointFnName, func(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		if err := starlark.UnpackArgs(breakpointFnName, args, kwargs); err != nil {
+			return starlark.None, fmt.Errorf("invalid args provided to '%s'. Error: %q", breakpointFnName, err)
+		}
+		if !t.isDebugged() {
+			return starlark.None, nil
+		}
+		// the frame at depth 0 is the breakpoint builtin itself
+		locals := starlark.StringDict{}
+		caller := thread.DebugFrame(1)
+		if fn, ok := caller.Callable().(*starlark.Function); ok {
+			for i := 0; i < fn.NumParams(); i++ {
+				name, _ := fn.Param(i)
+				if value := caller.Local(i); value != nil {
+					locals[name] = value
+				}
+			}
+		}
+		t.debug(thread, caller.Position().String(), locals)
+		return starlark.None, nil
+	})
+}
+
+// isDebugged returns true if the transformer was chosen for debugging
+func (t *Starlark) isDebugged() bool {
+	return common.IsPresent(common.DebugTransformers, t.Config.Name)
+}
+
+// debug runs the starlark statements read from stdin using the globals of the transformer
+// and the locals, until the input ends or the continue command is given
+func (t *Starlark) debug(thread *starlark.Thread, location string, locals starlark.StringDict) {
+	globals := starlark.StringDict{}
+	for _, dict := range []starlark.StringDict{t.predeclared, t.StarGlobals, locals} {
+		for name, value := range dict {
+			globals[name] = value
+		}
+	}
+	fmt.Printf("[%s] Paused at %s . Available names: %s\n", t.Config.Name, location, strings.Join(globals.Keys(), ", "))
+	fmt.Printf("Enter starlark statements to run them. Type %s or press Ctrl-D to resume.\n", debuggerContinueCommand)
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		eof := false
+		resume := false
+		prompt := ">>> "
+		readline := func() ([]byte, error) {
+			fmt.Print(prompt)
+			prompt = "... "
+			line, err := reader.ReadString('\n')
+			if err != nil && (err != io.EOF || line == "") {
+				eof = true
+				return nil, io.EOF
+			}
+			if strings.TrimSpace(line) == debuggerContinueCommand {
+				resume = true
+				return nil, io.EOF
+			}
+			return []byte(line), nil
+		}
+		f, err := syntax.ParseCompoundStmt("<stdin>", readline)
+		if eof || resume {
+			fmt.Println()
+			return
+		}
+		if err != nil {
+			printStarlarkError(err)
+			continue
+		}
+		if len(f.Stmts) == 1 {
+			if stmt, ok := f.Stmts[0].(*syntax.ExprStmt); ok {
+				value, err := starlark.EvalExpr(thread, stmt.X, globals)
+				if err != nil {
+					printStarlarkError(err)
+				} else if value != starlark.None {
+					fmt.Println(value)
+				}
+				continue
+			}
+		}
+		if err := starlark.ExecREPLChunk(f, thread, globals); err != nil {
+			printStarlarkError(err)
+		}
+	}
+}
+
+func printStarlarkError(err error) {
+	if evalErr, ok := err.(*starlark.EvalError); ok {
+		fmt.Fprintln(os.Stderr, evalErr.Backtrace())
+		return
+	}
+	fmt.Fprintln(os.Stderr, err)
+}
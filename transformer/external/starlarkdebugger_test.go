@@ -0,0 +1,155 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+)
+
+const testBreakpointSrc = `def get_name(name):
+    m2k.breakpoint()
+    return name
+result = get_name("web")
+`
+
+// debugTestStarlark runs the starlark code with the input as stdin and returns the result and what was written to stdout
+func debugTestStarlark(t *testing.T, transformer *Starlark, src, input string) (interface{}, string, error) {
+	t.Helper()
+	stdinPath := filepath.Join(t.TempDir(), "stdin")
+	if err := os.WriteFile(stdinPath, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stdin, err := os.Open(stdinPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+	stdoutPath := filepath.Join(t.TempDir(), "stdout")
+	stdout, err := os.Create(stdoutPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdout.Close()
+	oldStdin, oldStdout := os.Stdin, os.Stdout
+	os.Stdin, os.Stdout = stdin, stdout
+	result, err := execTestStarlark(transformer, src)
+	os.Stdin, os.Stdout = oldStdin, oldStdout
+	output, readErr := os.ReadFile(stdoutPath)
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+	return result, string(output), err
+}
+
+func TestStarlarkBreakpoint(t *testing.T) {
+	oldDebugTransformers := common.DebugTransformers
+	t.Cleanup(func() { common.DebugTransformers = oldDebugTransformers })
+	transformer := newTestStarlarkTransformer(t)
+	testCases := []struct {
+		name          string
+		debugged      bool
+		src           string
+		input         string
+		want          interface{}
+		wantOutput    []string
+		notWantOutput []string
+		wantErr       bool
+	}{
+		{
+			name:  "transformer not being debugged",
+			src:   testBreakpointSrc,
+			input: "name\n",
+			want:  "web",
+		},
+		{
+			name:       "evaluate a local",
+			debugged:   true,
+			src:        testBreakpointSrc,
+			input:      "name\ncontinue\n",
+			want:       "web",
+			wantOutput: []string{"[starlark] Paused at test.star:2:", `"web"`},
+		},
+		{
+			name:       "run statements until the input ends",
+			debugged:   true,
+			src:        testBreakpointSrc,
+			input:      "size = len(name)\nsize + 1\n",
+			want:       "web",
+			wantOutput: []string{"4"},
+		},
+		{
+			name:       "errors do not stop the debugger",
+			debugged:   true,
+			src:        testBreakpointSrc,
+			input:      "undefined_name\n1 +\nname.upper()\ncontinue\n",
+			want:       "web",
+			wantOutput: []string{`"WEB"`},
+		},
+		{
+			name:          "statements after continue are not run",
+			debugged:      true,
+			src:           testBreakpointSrc,
+			input:         "continue\nname.upper()\n",
+			want:          "web",
+			notWantOutput: []string{`"WEB"`},
+		},
+		{
+			name:    "invalid args",
+			src:     "m2k.breakpoint(1)",
+			wantErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			common.DebugTransformers = []string{}
+			if testCase.debugged {
+				common.DebugTransformers = []string{transformer.Config.Name}
+			}
+			got, output, err := debugTestStarlark(t, transformer, testCase.src, testCase.input)
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error. Actual result: %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to run the starlark code. Error: %q", err)
+			}
+			if got != testCase.want {
+				t.Fatalf("the result is incorrect. Expected: %+v Actual: %+v", testCase.want, got)
+			}
+			if !testCase.debugged && output != "" {
+				t.Fatalf("expected no output when the transformer is not being debugged. Actual: %q", output)
+			}
+			for _, want := range testCase.wantOutput {
+				if !strings.Contains(output, want) {
+					t.Fatalf("expected the output to contain %q. Actual: %q", want, output)
+				}
+			}
+			for _, notWant := range testCase.notWantOutput {
+				if strings.Contains(output, notWant) {
+					t.Fatalf("expected the output to not contain %q. Actual: %q", notWant, output)
+				}
+			}
+		})
+	}
+}
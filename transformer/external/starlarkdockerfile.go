@@ -0,0 +1,138 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"fmt"
+	"os"
+
+	dockerinstructions "github.com/moby/buildkit/frontend/dockerfile/instructions"
+	dockerparser "github.com/moby/buildkit/frontend/dockerfile/parser"
+	starutil "github.com/qri-io/starlib/util"
+	"go.starlark.net/starlark"
+)
+
+func (t *Starlark) getStarlarkFSParseDockerfile() *starlark.Builtin {
+	return starlark.NewBuiltin(fsParseDockerfileFnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var path string
+		if err := starlark.UnpackPositionalArgs(fsParseDockerfileFnName, args, kwargs, 1, &path); err != nil {
+			return starlark.None, fmt.Errorf("invalid args provided to '%s'. Expected the path of the Dockerfile. Error: %q", fsParseDockerfileFnName, err)
+		}
+		if !t.Env.IsPathValid(path) {
+			return starlark.None, fmt.Errorf("invalid path")
+		}
+		dockerfile, err := parseDockerfile(path)
+		if err != nil {
+			return starlark.None, err
+		}
+		value, err := starutil.Marshal(dockerfile)
+		if err != nil {
+			return starlark.None, fmt.Errorf("failed to marshal the parsed Dockerfile %s into a starlark value. Error: %q", path, err)
+		}
+		return value, nil
+	})
+}
+
+// parseDockerfile returns the build args and the stages of the Dockerfile with their
+// base images, exposed ports, environment variables, labels, entrypoints and commands
+func parseDockerfile(path string) (map[string]interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the Dockerfile %s . Error: %w", path, err)
+	}
+	defer f.Close()
+	res, err := dockerparser.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the Dockerfile %s . Error: %w", path, err)
+	}
+	stages, metaArgs, err := dockerinstructions.Parse(res.AST)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the instructions in the Dockerfile %s . Error: %w", path, err)
+	}
+	buildArgs := map[string]interface{}{}
+	for _, metaArg := range metaArgs {
+		addDockerfileArgs(buildArgs, metaArg)
+	}
+	starStages := []interface{}{}
+	for _, stage := range stages {
+		exposedPorts := []interface{}{}
+		env := map[string]interface{}{}
+		labels := map[string]interface{}{}
+		stageArgs := map[string]interface{}{}
+		starStage := map[string]interface{}{
+			"name":       stage.Name,
+			"image":      stage.BaseName,
+			"platform":   stage.Platform,
+			"env":        env,
+			"labels":     labels,
+			"args":       stageArgs,
+			"workdir":    "",
+			"user":       "",
+			"entrypoint": nil,
+			"cmd":        nil,
+		}
+		for _, command := range stage.Commands {
+			switch c := command.(type) {
+			case *dockerinstructions.ExposeCommand:
+				for _, port := range c.Ports {
+					exposedPorts = append(exposedPorts, port)
+				}
+			case *dockerinstructions.EnvCommand:
+				for _, kv := range c.Env {
+					env[kv.Key] = kv.Value
+				}
+			case *dockerinstructions.LabelCommand:
+				for _, kv := range c.Labels {
+					labels[kv.Key] = kv.Value
+				}
+			case *dockerinstructions.ArgCommand:
+				addDockerfileArgs(stageArgs, *c)
+			case *dockerinstructions.WorkdirCommand:
+				starStage["workdir"] = c.Path
+			case *dockerinstructions.UserCommand:
+				starStage["user"] = c.User
+			case *dockerinstructions.EntrypointCommand:
+				starStage["entrypoint"] = getDockerfileCmdLine(c.ShellDependantCmdLine)
+			case *dockerinstructions.CmdCommand:
+				starStage["cmd"] = getDockerfileCmdLine(c.ShellDependantCmdLine)
+			}
+		}
+		starStage["ports"] = exposedPorts
+		starStages = append(starStages, starStage)
+	}
+	return map[string]interface{}{"args": buildArgs, "stages": starStages}, nil
+}
+
+// addDockerfileArgs adds the build args with their default values, or None if they have no default
+func addDockerfileArgs(args map[string]interface{}, argCommand dockerinstructions.ArgCommand) {
+	for _, arg := range argCommand.Args {
+		if arg.Value == nil {
+			args[arg.Key] = nil
+			continue
+		}
+		args[arg.Key] = *arg.Value
+	}
+}
+
+// getDockerfileCmdLine returns the command and whether it is in the shell form, where it is run using the shell
+func getDockerfileCmdLine(cmdLine dockerinstructions.ShellDependantCmdLine) map[string]interface{} {
+	command := []interface{}{}
+	for _, arg := range cmdLine.CmdLine {
+		command = append(command, arg)
+	}
+	return map[string]interface{}{"command": command, "shell": cmdLine.PrependShell}
+}
@@ -0,0 +1,169 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseDockerfile(t *testing.T) {
+	testCases := []struct {
+		name       string
+		dockerfile string
+		want       map[string]interface{}
+		wantErr    bool
+	}{
+		{
+			name:       "single stage",
+			dockerfile: "FROM nginx:1.21\nEXPOSE 8080 9090/udp\nENV MODE=production\nLABEL app=web\nWORKDIR /app\nUSER nginx\nCMD nginx -g daemon-off\n",
+			want: map[string]interface{}{
+				"args": map[string]interface{}{},
+				"stages": []interface{}{
+					map[string]interface{}{
+						"name":       "",
+						"image":      "nginx:1.21",
+						"platform":   "",
+						"env":        map[string]interface{}{"MODE": "production"},
+						"labels":     map[string]interface{}{"app": "web"},
+						"args":       map[string]interface{}{},
+						"workdir":    "/app",
+						"user":       "nginx",
+						"entrypoint": nil,
+						"cmd":        map[string]interface{}{"command": []interface{}{"nginx -g daemon-off"}, "shell": true},
+						"ports":      []interface{}{"8080", "9090/udp"},
+					},
+				},
+			},
+		},
+		{
+			name:       "multi stage with build args",
+			dockerfile: "ARG VERSION=1.18\nARG REGISTRY\nFROM golang:${VERSION} AS builder\nARG TARGET=app\nRUN go build -o ${TARGET}\nFROM --platform=linux/amd64 alpine\nENTRYPOINT [\"/app\", \"serve\"]\nCMD [\"--port\", \"8080\"]\n",
+			want: map[string]interface{}{
+				"args": map[string]interface{}{"VERSION": "1.18", "REGISTRY": nil},
+				"stages": []interface{}{
+					map[string]interface{}{
+						"name":       "builder",
+						"image":      "golang:${VERSION}",
+						"platform":   "",
+						"env":        map[string]interface{}{},
+						"labels":     map[string]interface{}{},
+						"args":       map[string]interface{}{"TARGET": "app"},
+						"workdir":    "",
+						"user":       "",
+						"entrypoint": nil,
+						"cmd":        nil,
+						"ports":      []interface{}{},
+					},
+					map[string]interface{}{
+						"name":       "",
+						"image":      "alpine",
+						"platform":   "linux/amd64",
+						"env":        map[string]interface{}{},
+						"labels":     map[string]interface{}{},
+						"args":       map[string]interface{}{},
+						"workdir":    "",
+						"user":       "",
+						"entrypoint": map[string]interface{}{"command": []interface{}{"/app", "serve"}, "shell": false},
+						"cmd":        map[string]interface{}{"command": []interface{}{"--port", "8080"}, "shell": false},
+						"ports":      []interface{}{},
+					},
+				},
+			},
+		},
+		{
+			name:       "invalid instruction",
+			dockerfile: "FROM alpine\nEXPOSE\n",
+			wantErr:    true,
+		},
+		{
+			name:    "missing file",
+			wantErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "Dockerfile")
+			if testCase.dockerfile != "" {
+				if err := os.WriteFile(path, []byte(testCase.dockerfile), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := parseDockerfile(path)
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error. Actual result: %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to parse the Dockerfile. Error: %q", err)
+			}
+			if !cmp.Equal(got, testCase.want) {
+				t.Fatalf("the parsed Dockerfile is incorrect. Differences:\n%s", cmp.Diff(testCase.want, got))
+			}
+		})
+	}
+}
+
+func TestStarlarkFSParseDockerfile(t *testing.T) {
+	transformer := newTestStarlarkTransformer(t)
+	dockerfilePath := filepath.Join(transformer.Env.GetEnvironmentContext(), "Dockerfile")
+	if err := os.WriteFile(dockerfilePath, []byte("ARG PORT\nFROM node:16 AS web\nEXPOSE 3000\nCMD [\"npm\", \"start\"]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name    string
+		src     string
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "stage",
+			src:  "stage = fs.parse_dockerfile(fs.path_join(context_dir, \"Dockerfile\"))[\"stages\"][0]\nresult = [stage[\"name\"], stage[\"image\"], stage[\"ports\"], stage[\"cmd\"][\"command\"]]",
+			want: []interface{}{"web", "node:16", []interface{}{"3000"}, []interface{}{"npm", "start"}},
+		},
+		{
+			name: "build args without a default",
+			src:  "result = fs.parse_dockerfile(fs.path_join(context_dir, \"Dockerfile\"))[\"args\"]",
+			want: map[string]interface{}{"PORT": nil},
+		},
+		{name: "path outside the environment", src: "result = fs.parse_dockerfile(\"/Dockerfile\")", wantErr: true},
+		{name: "missing Dockerfile", src: "result = fs.parse_dockerfile(fs.path_join(context_dir, \"missing\"))", wantErr: true},
+		{name: "no args", src: "result = fs.parse_dockerfile()", wantErr: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := execTestStarlark(transformer, testCase.src)
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error. Actual result: %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to run the starlark code. Error: %q", err)
+			}
+			if !cmp.Equal(got, testCase.want) {
+				t.Fatalf("the result is incorrect. Differences:\n%s", cmp.Diff(testCase.want, got))
+			}
+		})
+	}
+}
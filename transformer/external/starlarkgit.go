@@ -0,0 +1,156 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	starutil "github.com/qri-io/starlib/util"
+	"github.com/sirupsen/logrus"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// addGitModules adds the read-only git functions. Since starlark cannot catch errors,
+// they return None if the path is not in a git repo or the information cannot be read.
+func (t *Starlark) addGitModules() {
+	t.StarGlobals["git"] = &starlarkstruct.Module{
+		Name: "git",
+		Members: starlark.StringDict{
+			gitRepoInfoFnName:     t.getStarlarkGitRepoInfo(),
+			gitBranchFnName:       t.getStarlarkGitBranch(),
+			gitRemotesFnName:      t.getStarlarkGitRemotes(),
+			gitChangedFilesFnName: t.getStarlarkGitChangedFiles(),
+			gitFileHistoryFnName:  t.getStarlarkGitFileHistory(),
+		},
+	}
+}
+
+func (t *Starlark) getStarlarkGitRepoInfo() *starlark.Builtin {
+	return starlark.NewBuiltin(gitRepoInfoFnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var path string
+		if err := starlark.UnpackArgs(gitRepoInfoFnName, args, kwargs, "path", &path); err != nil {
+			return starlark.None, fmt.Errorf("invalid args provided to '%s'. Error: %q", gitRepoInfoFnName, err)
+		}
+		if !t.Env.IsPathValid(path) {
+			return starlark.None, fmt.Errorf("invalid path")
+		}
+		repoName, repoDir, repoHostName, repoURL, repoBranch, err := common.GatherGitInfo(path)
+		if err != nil {
+			return t.getGitNone(gitRepoInfoFnName, path, err)
+		}
+		return starutil.Marshal(map[string]interface{}{"name": repoName, "dir": repoDir, "host": repoHostName, "url": repoURL, "branch": repoBranch})
+	})
+}
+
+func (t *Starlark) getStarlarkGitBranch() *starlark.Builtin {
+	return starlark.NewBuiltin(gitBranchFnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var path string
+		if err := starlark.UnpackArgs(gitBranchFnName, args, kwargs, "path", &path); err != nil {
+			return starlark.None, fmt.Errorf("invalid args provided to '%s'. Error: %q", gitBranchFnName, err)
+		}
+		if !t.Env.IsPathValid(path) {
+			return starlark.None, fmt.Errorf("invalid path")
+		}
+		branch, err := common.GetGitBranch(path)
+		if err != nil {
+			return t.getGitNone(gitBranchFnName, path, err)
+		}
+		return starlark.String(branch), nil
+	})
+}
+
+func (t *Starlark) getStarlarkGitRemotes() *starlark.Builtin {
+	return starlark.NewBuiltin(gitRemotesFnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var path string
+		if err := starlark.UnpackArgs(gitRemotesFnName, args, kwargs, "path", &path); err != nil {
+			return starlark.None, fmt.Errorf("invalid args provided to '%s'. Error: %q", gitRemotesFnName, err)
+		}
+		if !t.Env.IsPathValid(path) {
+			return starlark.None, fmt.Errorf("invalid path")
+		}
+		remotes, err := common.GetGitRemotes(path)
+		if err != nil {
+			return t.getGitNone(gitRemotesFnName, path, err)
+		}
+		remotesI := map[string]interface{}{}
+		for name, urls := range remotes {
+			remotesI[name] = toInterfaceSlice(urls)
+		}
+		return starutil.Marshal(remotesI)
+	})
+}
+
+func (t *Starlark) getStarlarkGitChangedFiles() *starlark.Builtin {
+	return starlark.NewBuiltin(gitChangedFilesFnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var path, ref string
+		if err := starlark.UnpackArgs(gitChangedFilesFnName, args, kwargs, "path", &path, "ref", &ref); err != nil {
+			return starlark.None, fmt.Errorf("invalid args provided to '%s'. Error: %q", gitChangedFilesFnName, err)
+		}
+		if !t.Env.IsPathValid(path) {
+			return starlark.None, fmt.Errorf("invalid path")
+		}
+		changedFiles, err := common.GetGitChangedFiles(path, ref)
+		if err != nil {
+			return t.getGitNone(gitChangedFilesFnName, path, err)
+		}
+		return starutil.Marshal(toInterfaceSlice(changedFiles))
+	})
+}
+
+func (t *Starlark) getStarlarkGitFileHistory() *starlark.Builtin {
+	return starlark.NewBuiltin(gitFileHistoryFnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var path string
+		maxCount := 0
+		if err := starlark.UnpackArgs(gitFileHistoryFnName, args, kwargs, "path", &path, "max_count?", &maxCount); err != nil {
+			return starlark.None, fmt.Errorf("invalid args provided to '%s'. Error: %q", gitFileHistoryFnName, err)
+		}
+		if !t.Env.IsPathValid(path) {
+			return starlark.None, fmt.Errorf("invalid path")
+		}
+		history, err := common.GetGitFileHistory(path, maxCount)
+		if err != nil {
+			return t.getGitNone(gitFileHistoryFnName, path, err)
+		}
+		commits := []interface{}{}
+		for _, commit := range history {
+			commits = append(commits, map[string]interface{}{
+				"hash":    commit.Hash,
+				"author":  commit.Author,
+				"email":   commit.Email,
+				"time":    commit.Time.Format(time.RFC3339),
+				"message": commit.Message,
+			})
+		}
+		return starutil.Marshal(commits)
+	})
+}
+
+func (t *Starlark) getGitNone(fnName, path string, err error) (starlark.Value, error) {
+	logrus.Debugf("[%s] failed to get the git information for the path %s using '%s'. Error: %q", t.Config.Name, path, fnName, err)
+	return starlark.None, nil
+}
+
+func toInterfaceSlice(values []string) []interface{} {
+	valuesI := []interface{}{}
+	for _, value := range values {
+		valuesI = append(valuesI, value)
+	}
+	return valuesI
+}
@@ -0,0 +1,67 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"fmt"
+
+	"github.com/konveyor/move2kube/transformer/kubernetes/k8sschema"
+	starutil "github.com/qri-io/starlib/util"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// addK8sModules adds the functions for working with Kubernetes manifests
+func (t *Starlark) addK8sModules() {
+	t.StarGlobals["k8s"] = &starlarkstruct.Module{
+		Name: "k8s",
+		Members: starlark.StringDict{
+			k8sValidateFnName: t.getStarlarkK8sValidate(),
+		},
+	}
+}
+
+// getStarlarkK8sValidate returns a builtin that validates the manifests against the OpenAPI schema of the Kubernetes version.
+// It returns a list of errors, each with the document index, apiVersion, kind, name, field and message. The list is empty if the manifests are valid.
+func (t *Starlark) getStarlarkK8sValidate() *starlark.Builtin {
+	return starlark.NewBuiltin(k8sValidateFnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var yamlStr, targetVersion string
+		if err := starlark.UnpackArgs(k8sValidateFnName, args, kwargs, "yaml_str", &yamlStr, "target_version", &targetVersion); err != nil {
+			return starlark.None, fmt.Errorf("invalid args provided to '%s'. Expected the manifests and the Kubernetes version. Error: %q", k8sValidateFnName, err)
+		}
+		validationErrors, err := k8sschema.ValidateResources(yamlStr, targetVersion)
+		if err != nil {
+			return starlark.None, fmt.Errorf("failed to validate the manifests against Kubernetes %s . Error: %q", targetVersion, err)
+		}
+		starErrors := []interface{}{}
+		for _, validationError := range validationErrors {
+			starErrors = append(starErrors, map[string]interface{}{
+				"document":    validationError.Document,
+				"api_version": validationError.APIVersion,
+				"kind":        validationError.Kind,
+				"name":        validationError.Name,
+				"field":       validationError.Field,
+				"message":     validationError.Message,
+			})
+		}
+		value, err := starutil.Marshal(starErrors)
+		if err != nil {
+			return starlark.None, fmt.Errorf("failed to marshal the validation errors into a starlark value. Error: %q", err)
+		}
+		return value, nil
+	})
+}
@@ -0,0 +1,86 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/qri-io/starlib"
+	"github.com/sirupsen/logrus"
+	"go.starlark.net/starlark"
+)
+
+// starlarkModule is the result of loading a starlark file
+type starlarkModule struct {
+	globals starlark.StringDict
+	err     error
+}
+
+// getStarlarkLoader returns the function used by load() statements. The modules are resolved relative to the
+// transformer context directory, falling back to the starlib modules. Each module is executed only once.
+func (t *Starlark) getStarlarkLoader(predeclared starlark.StringDict) func(*starlark.Thread, string) (starlark.StringDict, error) {
+	modules := map[string]*starlarkModule{}
+	var load func(*starlark.Thread, string) (starlark.StringDict, error)
+	load = func(_ *starlark.Thread, module string) (starlark.StringDict, error) {
+		modulePath, err := t.getStarlarkModulePath(module)
+		if err != nil {
+			return nil, err
+		}
+		if modulePath == "" {
+			return starlib.Loader(t.StarThread, module)
+		}
+		loaded, ok := modules[modulePath]
+		if ok {
+			if loaded == nil {
+				return nil, fmt.Errorf("the starlark file %s is part of a load cycle", module)
+			}
+			return loaded.globals, loaded.err
+		}
+		// mark the module as being loaded to detect cycles
+		modules[modulePath] = nil
+		logrus.Debugf("[%s] Loading the starlark file %s", t.Config.Name, modulePath)
+		thread := &starlark.Thread{Name: t.Config.Name + ":" + module, Load: load}
+		globals, err := starlark.ExecFile(thread, modulePath, nil, predeclared)
+		if err != nil {
+			err = fmt.Errorf("failed to load the starlark file %s . Error: %w", module, err)
+		}
+		modules[modulePath] = &starlarkModule{globals: globals, err: err}
+		return globals, err
+	}
+	return load
+}
+
+// getStarlarkModulePath returns the path of the module in the transformer context directory
+// or an empty string if there is no such file
+func (t *Starlark) getStarlarkModulePath(module string) (string, error) {
+	contextDir := t.Env.GetEnvironmentContext()
+	modulePath := filepath.Join(contextDir, filepath.FromSlash(module))
+	relPath, err := filepath.Rel(contextDir, modulePath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(os.PathSeparator)) {
+		return "", fmt.Errorf("the starlark file %s is outside the transformer directory", module)
+	}
+	if _, err := os.Stat(modulePath); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to stat the starlark file %s . Error: %w", modulePath, err)
+	}
+	return modulePath, nil
+}
@@ -17,6 +17,8 @@
 package external
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -24,13 +26,17 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/antchfx/xmlquery"
 	"github.com/antchfx/xpath"
 	"github.com/konveyor/move2kube/common"
 	"github.com/konveyor/move2kube/environment"
 	"github.com/konveyor/move2kube/qaengine"
+	"github.com/konveyor/move2kube/transformer/kubernetes"
 	"github.com/konveyor/move2kube/types"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	environmenttypes "github.com/konveyor/move2kube/types/environment"
 	qatypes "github.com/konveyor/move2kube/types/qaengine"
 	transformertypes "github.com/konveyor/move2kube/types/transformer"
 	"github.com/qri-io/starlib"
@@ -39,6 +45,7 @@ import (
 	"github.com/spf13/cast"
 	"go.starlark.net/starlark"
 	"go.starlark.net/starlarkstruct"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -55,7 +62,10 @@ const (
 	outputDirVarName         = "output_dir"
 
 	// Function names
-	qaFnName = "query"
+	qaFnName             = "query"
+	clusterFnName        = "cluster"
+	renderTemplateFnName = "render_template"
+	breakpointFnName     = "breakpoint"
 	// fs package
 	fsExistsFnName               = "exists"
 	fsReadFnName                 = "read"
@@ -68,6 +78,11 @@ const (
 	fsWriteFnName                = "write"
 	fsPathBaseFnName             = "path_base"
 	fsPathRelFnName              = "path_rel"
+	fsReadYamlFnName             = "read_yaml"
+	fsReadJsonFnName             = "read_json"
+	fsWriteYamlFnName            = "write_yaml"
+	fsWriteJsonFnName            = "write_json"
+	fsParseDockerfileFnName      = "parse_dockerfile"
 
 	// encryption functions
 	encAesCbcPbkdfFnName = "enc_aes_cbc_pbkdf"
@@ -75,6 +90,20 @@ const (
 	// archival functions
 	archTarGZipStrFnName = "arch_tar_gzip_str"
 	archTarStrFnName     = "arch_tar_str"
+	// exec functions
+	execRunFnName = "run"
+	// config file parsing functions
+	confParsePropertiesFnName = "parse_properties"
+	confParseTomlFnName       = "parse_toml"
+	confParseIniFnName        = "parse_ini"
+	// git functions
+	gitRepoInfoFnName     = "repo_info"
+	gitBranchFnName       = "branch"
+	gitRemotesFnName      = "remotes"
+	gitChangedFilesFnName = "changed_files"
+	gitFileHistoryFnName  = "file_history"
+	// k8s package
+	k8sValidateFnName = "validate"
 )
 
 // Starlark implements transformer interface and is used to write simple external transformers
@@ -87,6 +116,12 @@ type Starlark struct {
 
 	detectFn    *starlark.Function
 	transformFn *starlark.Function
+	// newArtifacts are the artifacts being transformed, used to find the target cluster they were transformed for
+	newArtifacts []transformertypes.Artifact
+	// predeclared are the modules and variables available to the starlark files
+	predeclared starlark.StringDict
+	// timeout is the duration after which the directory_detect and transform functions are cancelled
+	timeout time.Duration
 }
 
 // StarYamlConfig defines yaml config for Starlark transformers
@@ -99,11 +134,24 @@ func (t *Starlark) Init(tc transformertypes.Transformer, env *environment.Enviro
 	t.Config = tc
 	t.Env = env
 	t.StarConfig = &StarYamlConfig{}
+	if tc.Spec.FilePath != "" {
+		starConfigSchema := transformertypes.GenerateConfigSchema(StarYamlConfig{})
+		// the starlark files can read their own keys from the config
+		delete(starConfigSchema, "additionalProperties")
+		starConfigSchema["required"] = []string{"starFile"}
+		if err := transformertypes.ValidateTransformerConfigFile(tc.Spec.FilePath, starConfigSchema); err != nil {
+			return err
+		}
+	}
 	err = common.GetObjFromInterface(t.Config.Spec.Config, t.StarConfig)
 	if err != nil {
 		logrus.Errorf("unable to load config for Transformer %+v into %T : %s", t.Config.Spec.Config, t.StarConfig, err)
 		return err
 	}
+	t.timeout, err = t.Config.Spec.GetTimeout()
+	if err != nil {
+		return fmt.Errorf("invalid timeout for the transformer %s . Error: %w", tc.Name, err)
+	}
 	t.StarThread = &starlark.Thread{Name: tc.Name}
 	t.setDefaultGlobals()
 	tcmapobj, err := common.GetMapInterfaceFromObj(tc)
@@ -151,6 +199,8 @@ func (t *Starlark) Init(tc transformertypes.Transformer, env *environment.Enviro
 		logrus.Errorf("Unable to load source : %s", err)
 		return err
 	}
+	t.predeclared = t.StarGlobals
+	t.StarThread.Load = t.getStarlarkLoader(t.predeclared)
 	t.StarGlobals, err = starlark.ExecFile(t.StarThread, filepath.Join(t.Env.GetEnvironmentContext(), t.StarConfig.StarFile), nil, t.StarGlobals)
 	if err != nil {
 		if t.StarConfig.StarFile == "" {
@@ -199,7 +249,12 @@ func (t *Starlark) Transform(newArtifacts []transformertypes.Artifact, alreadySe
 		logrus.Errorf("Unable to convert %s to starlark value : %s", alreadySeenArtifacts, err)
 		return nil, nil, err
 	}
-	val, err := starlark.Call(t.StarThread, t.transformFn, starlark.Tuple{starNewArtifacts, starOldArtifacts}, nil)
+	t.newArtifacts = newArtifacts
+	defer func() { t.newArtifacts = nil }()
+	if t.isDebugged() {
+		t.debug(t.StarThread, "the start of the "+transformFnName+" function", starlark.StringDict{"new_artifacts": starNewArtifacts, "old_artifacts": starOldArtifacts})
+	}
+	val, err := t.call(t.transformFn, starlark.Tuple{starNewArtifacts, starOldArtifacts})
 	if err != nil {
 		logrus.Errorf("failed to call the starlark function: %s Error: %q", t.transformFn.String(), err)
 		return nil, nil, err
@@ -227,7 +282,7 @@ func (t *Starlark) executeDetect(fn *starlark.Function, dir string) (services ma
 		logrus.Errorf("Unable to convert %s to starlark value : %s", dir, err)
 		return nil, err
 	}
-	val, err := starlark.Call(t.StarThread, fn, starlark.Tuple{starDir}, nil)
+	val, err := t.call(fn, starlark.Tuple{starDir})
 	if err != nil {
 		logrus.Errorf("Unable to execute starlark function : %s", err)
 		return nil, err
@@ -246,6 +301,20 @@ func (t *Starlark) executeDetect(fn *starlark.Function, dir string) (services ma
 	return services, nil
 }
 
+// call calls the starlark function and cancels it if it runs longer than the timeout of the transformer
+func (t *Starlark) call(fn *starlark.Function, args starlark.Tuple) (starlark.Value, error) {
+	if t.timeout == 0 {
+		return starlark.Call(t.StarThread, fn, args, nil)
+	}
+	// a cancelled thread can not be used again, so each call gets its own thread
+	thread := &starlark.Thread{Name: t.StarThread.Name, Print: t.StarThread.Print, Load: t.StarThread.Load}
+	timer := time.AfterFunc(t.timeout, func() {
+		thread.Cancel(fmt.Sprintf("the transformer %s did not finish in %s", t.Config.Name, t.timeout))
+	})
+	defer timer.Stop()
+	return starlark.Call(thread, fn, args, nil)
+}
+
 func (t *Starlark) getStarlarkQuery() *starlark.Builtin {
 	return starlark.NewBuiltin(qaFnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 		argDictValue := &starlark.Dict{}
@@ -329,6 +398,60 @@ func (t *Starlark) getStarlarkQuery() *starlark.Builtin {
 	})
 }
 
+func (t *Starlark) getStarlarkCluster() *starlark.Builtin {
+	return starlark.NewBuiltin(clusterFnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		clusterQaLabel := collecttypes.DefaultClusterSpecificQaLabel
+		if err := starlark.UnpackArgs(clusterFnName, args, kwargs, "qa_label?", &clusterQaLabel); err != nil {
+			return starlark.None, fmt.Errorf("invalid args provided to '%s'. Expected an optional cluster qa label. Error: %q", clusterFnName, err)
+		}
+		cluster, err := t.getTargetCluster(clusterQaLabel)
+		if err != nil {
+			return starlark.None, fmt.Errorf("failed to get the metadata of the target cluster. Error: %q", err)
+		}
+		cluster.Spec.DefaultStorageClass = cluster.Spec.GetDefaultStorageClass()
+		cluster.Spec.DefaultIngressClass = cluster.Spec.GetDefaultIngressClass()
+		clusterObj, err := common.GetMapInterfaceFromObj(cluster)
+		if err != nil {
+			return starlark.None, fmt.Errorf("failed to convert the cluster metadata %+v to map[string]interface{} . Error: %q", cluster, err)
+		}
+		return starutil.Marshal(clusterObj)
+	})
+}
+
+func (t *Starlark) getStarlarkRenderTemplate() *starlark.Builtin {
+	return starlark.NewBuiltin(renderTemplateFnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var tpl string
+		var data starlark.Value = starlark.None
+		if err := starlark.UnpackArgs(renderTemplateFnName, args, kwargs, "tpl", &tpl, "data?", &data); err != nil {
+			return starlark.None, fmt.Errorf("invalid args provided to '%s'. Expected a go template string and optionally the data to render it with. Error: %q", renderTemplateFnName, err)
+		}
+		dataI, err := starutil.Unmarshal(data)
+		if err != nil {
+			return starlark.None, fmt.Errorf("failed to unmarshal the data provided to '%s'. Error: %q", renderTemplateFnName, err)
+		}
+		rendered, err := common.GetStringFromTemplate(tpl, dataI)
+		if err != nil {
+			return starlark.None, fmt.Errorf("failed to render the template. Error: %q", err)
+		}
+		return starlark.String(rendered), nil
+	})
+}
+
+// getTargetCluster returns the cluster metadata the artifacts being transformed carry for the qa label,
+// or else the one chosen using the cluster selector, which was collected with move2kube collect or is built-in
+func (t *Starlark) getTargetCluster(clusterQaLabel string) (collecttypes.ClusterMetadata, error) {
+	for _, newArtifact := range t.newArtifacts {
+		cluster := collecttypes.ClusterMetadata{}
+		if err := newArtifact.GetConfig(kubernetes.ClusterMetadata, &cluster); err != nil {
+			continue
+		}
+		if cluster.Labels[collecttypes.ClusterQaLabelKey] == clusterQaLabel {
+			return cluster, nil
+		}
+	}
+	return kubernetes.SelectTargetCluster(clusterQaLabel)
+}
+
 func (t *Starlark) setDefaultGlobals() {
 	t.StarGlobals = starlark.StringDict{}
 	t.addStarlibModules()
@@ -336,6 +459,10 @@ func (t *Starlark) setDefaultGlobals() {
 	t.addAppModules()
 	t.addCryptoModules()
 	t.addArchiveModules()
+	t.addExecModules()
+	t.addConfModules()
+	t.addGitModules()
+	t.addK8sModules()
 }
 
 func (t *Starlark) addStarlibModules() {
@@ -369,6 +496,11 @@ func (t *Starlark) addFSModules() {
 			fsPathBaseFnName:             t.getStarlarkFSPathBase(),
 			fsPathRelFnName:              t.getStarlarkFSPathRel(),
 			fsFindXmlPathFnName:          t.getStarlarkFindXmlPath(),
+			fsReadYamlFnName:             t.getStarlarkFSReadEncoded(fsReadYamlFnName, decodeYaml),
+			fsReadJsonFnName:             t.getStarlarkFSReadEncoded(fsReadJsonFnName, decodeJson),
+			fsWriteYamlFnName:            t.getStarlarkFSWriteEncoded(fsWriteYamlFnName, common.ObjectToYamlBytes),
+			fsWriteJsonFnName:            t.getStarlarkFSWriteEncoded(fsWriteJsonFnName, encodeJson),
+			fsParseDockerfileFnName:      t.getStarlarkFSParseDockerfile(),
 		},
 	}
 }
@@ -393,11 +525,34 @@ func (t *Starlark) addArchiveModules() {
 	}
 }
 
+func (t *Starlark) addExecModules() {
+	t.StarGlobals["exec"] = &starlarkstruct.Module{
+		Name: "exec",
+		Members: starlark.StringDict{
+			execRunFnName: t.getStarlarkExecRun(),
+		},
+	}
+}
+
+func (t *Starlark) addConfModules() {
+	t.StarGlobals["conf"] = &starlarkstruct.Module{
+		Name: "conf",
+		Members: starlark.StringDict{
+			confParsePropertiesFnName: t.getStarlarkConfParse(confParsePropertiesFnName, common.ParseProperties),
+			confParseTomlFnName:       t.getStarlarkConfParse(confParseTomlFnName, common.ParseTOML),
+			confParseIniFnName:        t.getStarlarkConfParse(confParseIniFnName, common.ParseINI),
+		},
+	}
+}
+
 func (t *Starlark) addAppModules() {
 	t.StarGlobals[types.AppNameShort] = &starlarkstruct.Module{
 		Name: types.AppNameShort,
 		Members: starlark.StringDict{
-			qaFnName: t.getStarlarkQuery(),
+			qaFnName:             t.getStarlarkQuery(),
+			clusterFnName:        t.getStarlarkCluster(),
+			renderTemplateFnName: t.getStarlarkRenderTemplate(),
+			breakpointFnName:     t.getStarlarkBreakpoint(),
 		},
 	}
 }
@@ -626,6 +781,117 @@ func (t *Starlark) getStarlarkFSRead() *starlark.Builtin {
 	})
 }
 
+// getStarlarkFSReadEncoded returns a builtin that reads a file and decodes it into starlark values like dicts and lists
+func (t *Starlark) getStarlarkFSReadEncoded(fnName string, decode func([]byte) (interface{}, error)) *starlark.Builtin {
+	return starlark.NewBuiltin(fnName, func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var path string
+		if err := starlark.UnpackPositionalArgs(fnName, args, kwargs, 1, &path); err != nil {
+			return nil, err
+		}
+		if !t.Env.IsPathValid(path) {
+			return starlark.None, fmt.Errorf("invalid path")
+		}
+		fileBytes, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return starlark.None, nil
+			}
+			return nil, err
+		}
+		data, err := decode(fileBytes)
+		if err != nil {
+			return starlark.None, fmt.Errorf("failed to decode the file %s . Error: %q", path, err)
+		}
+		value, err := starutil.Marshal(data)
+		if err != nil {
+			return starlark.None, fmt.Errorf("failed to marshal the contents of the file %s into a starlark value. Error: %q", path, err)
+		}
+		return value, nil
+	})
+}
+
+// getStarlarkFSWriteEncoded returns a builtin that encodes starlark values like dicts and lists and writes them to a file
+func (t *Starlark) getStarlarkFSWriteEncoded(fnName string, encode func(interface{}) ([]byte, error)) *starlark.Builtin {
+	return starlark.NewBuiltin(fnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var filePath string
+		var data starlark.Value
+		var permissions = common.DefaultFilePermission
+		if err := starlark.UnpackArgs(fnName, args, kwargs, "filepath", &filePath, "data", &data, "perm?", &permissions); err != nil {
+			return starlark.None, fmt.Errorf("invalid args provided to '%s'. Error: %q", fnName, err)
+		}
+		if filePath == "" {
+			return starlark.None, fmt.Errorf("FilePath is missing in write parameters")
+		}
+		if !t.Env.IsPathValid(filePath) {
+			return starlark.None, fmt.Errorf("invalid path")
+		}
+		dataI, err := starutil.Unmarshal(data)
+		if err != nil {
+			return starlark.None, fmt.Errorf("failed to unmarshal the data provided to '%s'. Error: %q", fnName, err)
+		}
+		dataBytes, err := encode(dataI)
+		if err != nil {
+			return starlark.None, fmt.Errorf("failed to encode the data provided to '%s'. Error: %q", fnName, err)
+		}
+		if err := os.WriteFile(filePath, dataBytes, fs.FileMode(permissions)); err != nil {
+			return starlark.None, fmt.Errorf("could not write to file %s . Error: %q", filePath, err)
+		}
+		return starlark.MakeInt(len(dataBytes)), nil
+	})
+}
+
+// decodeYaml decodes the first yaml document
+func decodeYaml(data []byte) (interface{}, error) {
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func decodeJson(data []byte) (interface{}, error) {
+	utf8Data, err := common.ConvertUtf8AndUtf16ToUtf8(data)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(utf8Data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return normalizeJsonNumbers(value), nil
+}
+
+func encodeJson(data interface{}) ([]byte, error) {
+	dataBytes, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(dataBytes, '\n'), nil
+}
+
+// normalizeJsonNumbers converts the json numbers into ints where possible so that they stay ints in starlark
+func normalizeJsonNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, elem := range v {
+			v[key] = normalizeJsonNumbers(elem)
+		}
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = normalizeJsonNumbers(elem)
+		}
+	}
+	return value
+}
+
 func (t *Starlark) getStarlarkFSReadDir() *starlark.Builtin {
 	return starlark.NewBuiltin(fsReadDirFnName, func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 		var path string
@@ -767,3 +1033,53 @@ func (t *Starlark) getStarlarkArchTarStr() *starlark.Builtin {
 		return starlark.String(common.CreateTarArchiveNoCompressionStringWrapper(srcDir)), nil
 	})
 }
+
+func (t *Starlark) getStarlarkExecRun() *starlark.Builtin {
+	return starlark.NewBuiltin(execRunFnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var cmdValue *starlark.List
+		if err := starlark.UnpackArgs(execRunFnName, args, kwargs, "cmd", &cmdValue); err != nil {
+			return starlark.None, fmt.Errorf("invalid args provided to '%s'. Expected a list of strings with the command and its arguments. Error: %q", execRunFnName, err)
+		}
+		cmd := environmenttypes.Command{}
+		for i := 0; i < cmdValue.Len(); i++ {
+			arg, ok := starlark.AsString(cmdValue.Index(i))
+			if !ok {
+				return starlark.None, fmt.Errorf("the argument %s provided to '%s' is not a string", cmdValue.Index(i), execRunFnName)
+			}
+			cmd = append(cmd, arg)
+		}
+		if len(cmd) == 0 {
+			return starlark.None, fmt.Errorf("no command provided to '%s'", execRunFnName)
+		}
+		// the command runs in the environment of the transformer, inside its container if it has one
+		stdout, stderr, exitcode, err := t.Env.Exec(cmd)
+		if err != nil {
+			return starlark.None, fmt.Errorf("failed to execute the command %v in the environment. Error: %q", cmd, err)
+		}
+		logrus.Debugf("[%s] The command %v exited with the code %d\nstdout: %s\nstderr: %s", t.Config.Name, cmd, exitcode, stdout, stderr)
+		return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+			"stdout":    starlark.String(stdout),
+			"stderr":    starlark.String(stderr),
+			"exit_code": starlark.MakeInt(exitcode),
+		}), nil
+	})
+}
+
+// getStarlarkConfParse returns a builtin that parses the contents of a config file into a dict
+func (t *Starlark) getStarlarkConfParse(fnName string, parse func(string) (map[string]interface{}, error)) *starlark.Builtin {
+	return starlark.NewBuiltin(fnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var data string
+		if err := starlark.UnpackPositionalArgs(fnName, args, kwargs, 1, &data); err != nil {
+			return starlark.None, fmt.Errorf("invalid args provided to '%s'. Expected the contents of the file as a string. Error: %q", fnName, err)
+		}
+		values, err := parse(data)
+		if err != nil {
+			return starlark.None, err
+		}
+		value, err := starutil.Marshal(values)
+		if err != nil {
+			return starlark.None, fmt.Errorf("failed to marshal the parsed values into a starlark value. Error: %q", err)
+		}
+		return value, nil
+	})
+}
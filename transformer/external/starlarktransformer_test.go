@@ -0,0 +1,367 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/environment"
+	"github.com/konveyor/move2kube/transformer/kubernetes"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	environmenttypes "github.com/konveyor/move2kube/types/environment"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	starutil "github.com/qri-io/starlib/util"
+	"go.starlark.net/starlark"
+)
+
+const testStarlarkFile = `def transform(new_artifacts, old_artifacts):
+    return {}
+`
+
+func newTestStarlarkTransformer(t *testing.T) *Starlark {
+	t.Helper()
+	oldTempPath := common.TempPath
+	t.Cleanup(func() { common.TempPath = oldTempPath })
+	common.TempPath = t.TempDir()
+	contextPath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(contextPath, "main.star"), []byte(testStarlarkFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	env, err := environment.NewEnvironment(environment.EnvInfo{
+		Name:                  "starlark",
+		ProjectName:           "myproject",
+		Source:                t.TempDir(),
+		Context:               contextPath,
+		CurrEnvOutputBasePath: t.TempDir(),
+		EnvPlatformConfig:     environmenttypes.EnvPlatformConfig{Platforms: []string{runtime.GOOS}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to create the environment. Error: %q", err)
+	}
+	t.Cleanup(func() { env.Destroy() })
+	tc := transformertypes.Transformer{}
+	tc.Name = "starlark"
+	tc.Spec.Config = map[string]interface{}{"starFile": "main.star"}
+	transformer := &Starlark{}
+	if err := transformer.Init(tc, env); err != nil {
+		t.Fatalf("failed to initialize the transformer. Error: %q", err)
+	}
+	return transformer
+}
+
+// execTestStarlark runs the starlark code with the modules of the transformer and returns the value of the result variable
+func execTestStarlark(transformer *Starlark, src string) (interface{}, error) {
+	globals, err := starlark.ExecFile(transformer.StarThread, "test.star", src, transformer.predeclared)
+	if err != nil {
+		return nil, err
+	}
+	result, ok := globals["result"]
+	if !ok {
+		return nil, nil
+	}
+	return starutil.Unmarshal(result)
+}
+
+func TestStarlarkCluster(t *testing.T) {
+	transformer := newTestStarlarkTransformer(t)
+	cluster := collecttypes.NewClusterMetadata("mycluster")
+	cluster.Labels = map[string]string{collecttypes.ClusterQaLabelKey: "prod"}
+	cluster.Spec.StorageClasses = []string{"gp2"}
+	cluster.Spec.IngressClasses = []string{"nginx", "alb"}
+	transformer.newArtifacts = []transformertypes.Artifact{
+		{Name: "db"},
+		{Name: "web", Configs: map[transformertypes.ConfigType]interface{}{kubernetes.ClusterMetadata: cluster}},
+	}
+	defer func() { transformer.newArtifacts = nil }()
+	testCases := []struct {
+		name    string
+		src     string
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "cluster of the artifacts being transformed",
+			src:  `c = m2k.cluster("prod")` + "\n" + `result = [c["metadata"]["name"], c["spec"]["defaultStorageClass"], c["spec"].get("defaultIngressClass", "")]`,
+			want: []interface{}{"mycluster", "gp2", ""},
+		},
+		{
+			name: "qa label as a keyword argument",
+			src:  `result = m2k.cluster(qa_label = "prod")["metadata"]["name"]`,
+			want: "mycluster",
+		},
+		{
+			name:    "no cluster selector for the qa label",
+			src:     `result = m2k.cluster()`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid qa label",
+			src:     `result = m2k.cluster(1)`,
+			wantErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := execTestStarlark(transformer, testCase.src)
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error. Actual result: %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to run the starlark code. Error: %q", err)
+			}
+			if !cmp.Equal(got, testCase.want) {
+				t.Fatalf("the result is incorrect. Differences:\n%s", cmp.Diff(testCase.want, got))
+			}
+		})
+	}
+}
+
+func TestStarlarkFSEncoded(t *testing.T) {
+	transformer := newTestStarlarkTransformer(t)
+	outputPath := transformer.Env.GetEnvironmentOutput()
+	files := map[string]string{
+		"config.json":  `{"name": "web", "replicas": 2, "ratio": 0.5, "ports": [80, 443]}`,
+		"config.yaml":  "name: web\nreplicas: 2\nratio: 0.5\nports:\n  - 80\n  - 443\n",
+		"invalid.json": `{"name": `,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(outputPath, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := func(name string) string { return filepath.Join(outputPath, name) }
+	config := map[string]interface{}{"name": "web", "replicas": 2, "ratio": 0.5, "ports": []interface{}{80, 443}}
+	testCases := []struct {
+		name    string
+		src     string
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "read json", src: fmt.Sprintf("result = fs.read_json(%q)", path("config.json")), want: config},
+		{name: "read yaml", src: fmt.Sprintf("result = fs.read_yaml(%q)", path("config.yaml")), want: config},
+		{name: "read a missing file", src: fmt.Sprintf("result = fs.read_yaml(%q)", path("missing.yaml"))},
+		{name: "read invalid json", src: fmt.Sprintf("result = fs.read_json(%q)", path("invalid.json")), wantErr: true},
+		{name: "read outside the allowed directories", src: `result = fs.read_json("/config.json")`, wantErr: true},
+		{
+			name: "write yaml",
+			src:  fmt.Sprintf("fs.write_yaml(%q, {\"name\": \"web\", \"ports\": [80]})\nresult = fs.read(%q)", path("out.yaml"), path("out.yaml")),
+			want: "name: web\nports:\n  - 80\n",
+		},
+		{
+			name: "write json",
+			src:  fmt.Sprintf("fs.write_json(%q, {\"name\": \"web\", \"ports\": [80]})\nresult = fs.read(%q)", path("out.json"), path("out.json")),
+			want: "{\n  \"name\": \"web\",\n  \"ports\": [\n    80\n  ]\n}\n",
+		},
+		{
+			name: "write and read back",
+			src:  fmt.Sprintf("fs.write_json(%q, fs.read_yaml(%q))\nresult = fs.read_json(%q)", path("copy.json"), path("config.yaml"), path("copy.json")),
+			want: config,
+		},
+		{name: "write without a path", src: `fs.write_yaml("", {"name": "web"})`, wantErr: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := execTestStarlark(transformer, testCase.src)
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error. Actual result: %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to run the starlark code. Error: %q", err)
+			}
+			if !cmp.Equal(got, testCase.want) {
+				t.Fatalf("the result is incorrect. Differences:\n%s", cmp.Diff(testCase.want, got))
+			}
+		})
+	}
+}
+
+func TestStarlarkExecRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the commands in this test are sh commands")
+	}
+	defer func() { common.DisableLocalExecution = false }()
+	transformer := newTestStarlarkTransformer(t)
+	testCases := []struct {
+		name                  string
+		src                   string
+		disableLocalExecution bool
+		want                  interface{}
+		wantErr               bool
+	}{
+		{
+			name: "output and exit code",
+			src:  `r = exec.run(["sh", "-c", "echo out; echo err >&2; exit 3"])` + "\n" + `result = [r.stdout, r.stderr, r.exit_code]`,
+			want: []interface{}{"out\n", "err\n", 3},
+		},
+		{
+			name: "runs in the context directory",
+			src:  `result = exec.run(cmd = ["ls"]).stdout`,
+			want: "main.star\n",
+		},
+		{name: "empty command", src: `exec.run([])`, wantErr: true},
+		{name: "argument that is not a string", src: `exec.run(["sh", 1])`, wantErr: true},
+		{name: "command that is not a list", src: `exec.run("ls")`, wantErr: true},
+		{name: "missing executable", src: `exec.run(["move2kube-missing-executable"])`, wantErr: true},
+		{name: "local execution disabled", src: `exec.run(["ls"])`, disableLocalExecution: true, wantErr: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			common.DisableLocalExecution = testCase.disableLocalExecution
+			got, err := execTestStarlark(transformer, testCase.src)
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error. Actual result: %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to run the starlark code. Error: %q", err)
+			}
+			if !cmp.Equal(got, testCase.want) {
+				t.Fatalf("the result is incorrect. Differences:\n%s", cmp.Diff(testCase.want, got))
+			}
+		})
+	}
+}
+
+func TestStarlarkLoad(t *testing.T) {
+	transformer := newTestStarlarkTransformer(t)
+	contextPath := transformer.Env.GetEnvironmentContext()
+	files := map[string]string{
+		filepath.Join("lib", "utils.star"):  "load(\"lib/names.star\", \"suffix\")\ndef greet(name):\n    return \"hello \" + fs.path_base(fs.path_join(context_dir, name)) + suffix\n",
+		filepath.Join("lib", "names.star"):  "suffix = \"!\"\n",
+		filepath.Join("lib", "cycle1.star"): "load(\"lib/cycle2.star\", \"b\")\na = 1\n",
+		filepath.Join("lib", "cycle2.star"): "load(\"lib/cycle1.star\", \"a\")\nb = 2\n",
+		filepath.Join("lib", "broken.star"): "x = 1 +\n",
+	}
+	for path, data := range files {
+		path = filepath.Join(contextPath, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testCases := []struct {
+		name    string
+		src     string
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "file in the transformer directory using the predeclared modules",
+			src:  "load(\"lib/utils.star\", \"greet\")\nresult = greet(\"web\")",
+			want: "hello web!",
+		},
+		{
+			name: "starlib module",
+			src:  "load(\"encoding/json.star\", \"json\")\nresult = json.encode({\"name\": \"web\"})",
+			want: `{"name":"web"}`,
+		},
+		{name: "load cycle", src: "load(\"lib/cycle1.star\", \"a\")", wantErr: true},
+		{name: "file with a syntax error", src: "load(\"lib/broken.star\", \"x\")", wantErr: true},
+		{name: "file outside the transformer directory", src: "load(\"../outside.star\", \"x\")", wantErr: true},
+		{name: "missing file", src: "load(\"lib/missing.star\", \"x\")", wantErr: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := execTestStarlark(transformer, testCase.src)
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error. Actual result: %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to run the starlark code. Error: %q", err)
+			}
+			if !cmp.Equal(got, testCase.want) {
+				t.Fatalf("the result is incorrect. Differences:\n%s", cmp.Diff(testCase.want, got))
+			}
+		})
+	}
+}
+
+func TestStarlarkRenderTemplate(t *testing.T) {
+	transformer := newTestStarlarkTransformer(t)
+	testCases := []struct {
+		name    string
+		src     string
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "template with data",
+			src:  "result = m2k.render_template(\"Hello {{ .name }}\", {\"name\": \"web\"})",
+			want: "Hello web",
+		},
+		{
+			name: "template using the sprig functions",
+			src:  "result = m2k.render_template(\"{{ .name | upper }}\", {\"name\": \"web\"})",
+			want: "WEB",
+		},
+		{
+			name: "keyword arguments",
+			src:  "result = m2k.render_template(tpl = \"{{ .port }}\", data = {\"port\": 8080})",
+			want: "8080",
+		},
+		{
+			name: "list data",
+			src:  "result = m2k.render_template(\"{{ range . }}{{ . }},{{ end }}\", [1, 2])",
+			want: "1,2,",
+		},
+		{
+			name: "template without data",
+			src:  "result = m2k.render_template(\"static\")",
+			want: "static",
+		},
+		{name: "invalid template", src: "result = m2k.render_template(\"{{ .name\", {\"name\": \"web\"})", wantErr: true},
+		{name: "template that fails to execute", src: "result = m2k.render_template(\"{{ .name.first }}\", {\"name\": \"web\"})", wantErr: true},
+		{name: "data that cannot be unmarshalled", src: "result = m2k.render_template(\"static\", len)", wantErr: true},
+		{name: "no args", src: "result = m2k.render_template()", wantErr: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := execTestStarlark(transformer, testCase.src)
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error. Actual result: %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to run the starlark code. Error: %q", err)
+			}
+			if !cmp.Equal(got, testCase.want) {
+				t.Fatalf("the result is incorrect. Differences:\n%s", cmp.Diff(testCase.want, got))
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/deepcopy"
+	"github.com/konveyor/move2kube/common/pathconverters"
+	"github.com/konveyor/move2kube/environment"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/sirupsen/logrus"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+const (
+	wasmAllocateFnName  = "allocate"
+	wasmDetectFnName    = "directory_detect"
+	wasmTransformFnName = "transform"
+	// wasmInitializeFnName is called when instantiating reactor modules, which don't have a _start function
+	wasmInitializeFnName = "_initialize"
+	// wasmSourceDir and wasmContextDir are where the source and the transformer directories are mounted in the module
+	wasmSourceDir  = "/source"
+	wasmContextDir = "/context"
+)
+
+// WASM implements transformer interface and is used to write external transformers as WebAssembly (WASI) modules.
+// The module exports its memory and the functions:
+//   - allocate(size i32) i32 returns the offset of a buffer of that size, into which the JSON input is written
+//   - directory_detect(offset i32, size i32) i64 takes {"InputDirectory": dir} and returns a map of services to artifacts
+//   - transform(offset i32, size i32) i64 takes a TransformInput and returns a TransformOutput
+//
+// The results are JSON, located at offset << 32 | size in the memory. Each call gets a new instance of the module,
+// which can only read the source and the transformer directories, mounted at /source and /context. The paths in the
+// input and the output are converted between the paths outside and inside the module.
+type WASM struct {
+	Config     transformertypes.Transformer
+	Env        *environment.Environment
+	WASMConfig *WASMYamlConfig
+
+	runtime wazero.Runtime
+	module  wazero.CompiledModule
+	// timeout is the duration after which the directory_detect and transform functions are stopped
+	timeout time.Duration
+}
+
+// WASMYamlConfig is the format of the WASM transformer yaml config
+type WASMYamlConfig struct {
+	WASMFile string `yaml:"wasmFile"` // Relative to yaml directory
+}
+
+// Init Initializes the transformer
+func (t *WASM) Init(tc transformertypes.Transformer, env *environment.Environment) (err error) {
+	t.Config = tc
+	t.Env = env
+	t.WASMConfig = &WASMYamlConfig{}
+	if tc.Spec.FilePath != "" {
+		wasmConfigSchema := transformertypes.GenerateConfigSchema(WASMYamlConfig{})
+		wasmConfigSchema["required"] = []string{"wasmFile"}
+		if err := transformertypes.ValidateTransformerConfigFile(tc.Spec.FilePath, wasmConfigSchema); err != nil {
+			return err
+		}
+	}
+	if err := common.GetObjFromInterface(t.Config.Spec.Config, t.WASMConfig); err != nil {
+		return fmt.Errorf("unable to load config for Transformer %+v into %T . Error: %w", t.Config.Spec.Config, t.WASMConfig, err)
+	}
+	t.timeout, err = t.Config.Spec.GetTimeout()
+	if err != nil {
+		return fmt.Errorf("invalid timeout for the transformer %s . Error: %w", t.Config.Name, err)
+	}
+	wasmPath := filepath.Join(t.Env.GetEnvironmentContext(), t.WASMConfig.WASMFile)
+	wasmBytes, err := os.ReadFile(wasmPath)
+	if err != nil {
+		return fmt.Errorf("failed to read the WebAssembly module at path %s . Error: %w", wasmPath, err)
+	}
+	ctx := context.Background()
+	t.runtime = wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().WithCloseOnContextDone(true))
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, t.runtime); err != nil {
+		t.runtime.Close(ctx)
+		return fmt.Errorf("failed to instantiate WASI for the transformer %s . Error: %w", t.Config.Name, err)
+	}
+	t.module, err = t.runtime.CompileModule(ctx, wasmBytes)
+	if err != nil {
+		t.runtime.Close(ctx)
+		return fmt.Errorf("failed to compile the WebAssembly module at path %s . Error: %w", wasmPath, err)
+	}
+	exports := t.module.ExportedFunctions()
+	for _, fnName := range []string{wasmAllocateFnName, wasmTransformFnName} {
+		if _, ok := exports[fnName]; !ok {
+			t.runtime.Close(ctx)
+			return fmt.Errorf("the WebAssembly module at path %s does not export the function '%s'", wasmPath, fnName)
+		}
+	}
+	return nil
+}
+
+// GetConfig returns the transformer config
+func (t *WASM) GetConfig() (transformertypes.Transformer, *environment.Environment) {
+	return t.Config, t.Env
+}
+
+// DirectoryDetect runs detect in each sub directory
+func (t *WASM) DirectoryDetect(dir string) (services map[string][]transformertypes.Artifact, err error) {
+	if _, ok := t.module.ExportedFunctions()[wasmDetectFnName]; !ok {
+		return nil, nil
+	}
+	services = map[string][]transformertypes.Artifact{}
+	if err := t.call(wasmDetectFnName, map[string]string{"InputDirectory": t.toModulePath(dir)}, &services); err != nil {
+		return nil, fmt.Errorf("failed to detect in the directory %s . Error: %w", dir, err)
+	}
+	t.convertPaths(&services, t.fromModulePath)
+	setDefaultServicePaths(services, dir)
+	return services, nil
+}
+
+// Transform transforms the artifacts
+func (t *WASM) Transform(newArtifacts []transformertypes.Artifact, alreadySeenArtifacts []transformertypes.Artifact) ([]transformertypes.PathMapping, []transformertypes.Artifact, error) {
+	input := deepcopy.DeepCopy(transformertypes.TransformInput{
+		NewArtifacts:         newArtifacts,
+		AlreadySeenArtifacts: alreadySeenArtifacts,
+	}).(transformertypes.TransformInput)
+	t.convertPaths(&input, t.toModulePath)
+	output := transformertypes.TransformOutput{}
+	if err := t.call(wasmTransformFnName, input, &output); err != nil {
+		return nil, nil, fmt.Errorf("failed to transform the artifacts. Error: %w", err)
+	}
+	t.convertPaths(&output, t.fromModulePath)
+	return output.PathMappings, output.CreatedArtifacts, nil
+}
+
+// toModulePath converts a path in the source or the transformer directory into the path where the module sees it
+func (t *WASM) toModulePath(path string) string {
+	return changePathRoot(path, map[string]string{t.Env.GetEnvironmentSource(): wasmSourceDir, t.Env.GetEnvironmentContext(): wasmContextDir})
+}
+
+// fromModulePath converts a path inside the module into the path outside it
+func (t *WASM) fromModulePath(path string) string {
+	return changePathRoot(path, map[string]string{wasmSourceDir: t.Env.GetEnvironmentSource(), wasmContextDir: t.Env.GetEnvironmentContext()})
+}
+
+func (t *WASM) convertPaths(obj interface{}, convert func(string) string) {
+	if err := pathconverters.ProcessPaths(obj, func(path string) (string, error) { return convert(path), nil }); err != nil {
+		logrus.Errorf("failed to convert the paths for the transformer %s . Error: %q", t.Config.Name, err)
+	}
+}
+
+// changePathRoot moves an absolute path under one of the roots to the corresponding new root. Other paths are returned unchanged.
+func changePathRoot(path string, roots map[string]string) string {
+	if !filepath.IsAbs(path) {
+		return path
+	}
+	for oldRoot, newRoot := range roots {
+		if rel, err := filepath.Rel(oldRoot, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+			return filepath.Join(newRoot, rel)
+		}
+	}
+	return path
+}
+
+// call calls the function exported by a new instance of the module with the JSON input and decodes the JSON result into the output
+func (t *WASM) call(fnName string, input interface{}, output interface{}) error {
+	inputBytes, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the input of '%s' to json. Error: %w", fnName, err)
+	}
+	ctx := context.Background()
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+	var stdout, stderr bytes.Buffer
+	fsConfig := wazero.NewFSConfig().
+		WithReadOnlyDirMount(t.Env.GetEnvironmentSource(), wasmSourceDir).
+		WithReadOnlyDirMount(t.Env.GetEnvironmentContext(), wasmContextDir)
+	moduleConfig := wazero.NewModuleConfig().
+		WithName("").
+		WithStdout(&stdout).
+		WithStderr(&stderr).
+		WithFSConfig(fsConfig).
+		WithStartFunctions(wasmInitializeFnName)
+	mod, err := t.runtime.InstantiateModule(ctx, t.module, moduleConfig)
+	if err != nil {
+		return fmt.Errorf("failed to instantiate the WebAssembly module of the transformer %s . Error: %w", t.Config.Name, err)
+	}
+	defer mod.Close(ctx)
+	defer func() {
+		logrus.Debugf("the function '%s' of the transformer %s finished.\nstdout: %s\nstderr: %s", fnName, t.Config.Name, stdout.String(), stderr.String())
+	}()
+	results, err := mod.ExportedFunction(wasmAllocateFnName).Call(ctx, uint64(len(inputBytes)))
+	if err != nil {
+		return fmt.Errorf("failed to allocate %d bytes for the input of '%s' . Error: %w", len(inputBytes), fnName, err)
+	}
+	inputOffset := uint32(results[0])
+	if !mod.Memory().Write(inputOffset, inputBytes) {
+		return fmt.Errorf("the buffer at offset %d allocated for the input of '%s' is out of the range of the memory", inputOffset, fnName)
+	}
+	results, err = mod.ExportedFunction(fnName).Call(ctx, uint64(inputOffset), uint64(len(inputBytes)))
+	if err != nil {
+		return fmt.Errorf("failed to call the function '%s' .\nstdout: %s\nstderr: %s\nError: %w", fnName, stdout.String(), stderr.String(), err)
+	}
+	outputOffset, outputSize := uint32(results[0]>>32), uint32(results[0])
+	outputBytes, ok := mod.Memory().Read(outputOffset, outputSize)
+	if !ok {
+		return fmt.Errorf("the output of '%s' at offset %d with size %d is out of the range of the memory", fnName, outputOffset, outputSize)
+	}
+	if err := json.Unmarshal(outputBytes, output); err != nil {
+		return fmt.Errorf("failed to unmarshal the output of '%s' as json. Error: %w", fnName, err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/environment"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/konveyor/move2kube/types/transformer/artifacts"
+)
+
+const (
+	// testWASMDetectOutputOffset is where the data segment with the output of directory_detect is placed
+	testWASMDetectOutputOffset = 4096
+	testWASMDetectOutput       = `{"web": [{"name": "web", "type": "Service"}], "api": [{"name": "api", "type": "Service", "paths": {"ServiceDirPath": ["/source/api"]}}]}`
+)
+
+// uleb128 and sleb128 encode the integers in the WebAssembly binary format
+func uleb128(v uint64) []byte {
+	out := []byte{}
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func sleb128(v int64) []byte {
+	out := []byte{}
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func wasmVector(items ...[]byte) []byte {
+	out := uleb128(uint64(len(items)))
+	for _, item := range items {
+		out = append(out, item...)
+	}
+	return out
+}
+
+func wasmName(name string) []byte {
+	return append(uleb128(uint64(len(name))), name...)
+}
+
+func wasmSection(id byte, content []byte) []byte {
+	return append(append([]byte{id}, uleb128(uint64(len(content)))...), content...)
+}
+
+func wasmFunctionBody(instructions ...byte) []byte {
+	// no locals and the end opcode
+	body := append(append([]byte{0x00}, instructions...), 0x0b)
+	return append(uleb128(uint64(len(body))), body...)
+}
+
+// newTestWASMModule returns a module with the memory and the allocate function. If withFunctions is true it also has
+// a directory_detect that returns testWASMDetectOutput and a transform that returns its input unchanged.
+func newTestWASMModule(withFunctions bool) []byte {
+	const (
+		i32 = 0x7f
+		i64 = 0x7e
+	)
+	allocateType := []byte{0x60, 0x01, i32, 0x01, i32}
+	callType := []byte{0x60, 0x02, i32, i32, 0x01, i64}
+	// allocate always returns the offset 0, which is below the data segment
+	allocateBody := wasmFunctionBody(append([]byte{0x41}, sleb128(0)...)...)
+	functions := [][]byte{{0x00}}
+	exports := [][]byte{append(wasmName("memory"), 0x02, 0x00), append(wasmName(wasmAllocateFnName), 0x00, 0x00)}
+	bodies := [][]byte{allocateBody}
+	dataSegments := [][]byte{}
+	if withFunctions {
+		detectResult := int64(testWASMDetectOutputOffset)<<32 | int64(len(testWASMDetectOutput))
+		detectBody := wasmFunctionBody(append([]byte{0x42}, sleb128(detectResult)...)...)
+		// (offset << 32) | size using local.get, i64.extend_i32_u, i64.const, i64.shl and i64.or
+		echoBody := wasmFunctionBody(0x20, 0x00, 0xad, 0x42, 0x20, 0x86, 0x20, 0x01, 0xad, 0x84)
+		functions = append(functions, []byte{0x01}, []byte{0x01})
+		exports = append(exports, append(wasmName(wasmDetectFnName), 0x00, 0x01), append(wasmName(wasmTransformFnName), 0x00, 0x02))
+		bodies = append(bodies, detectBody, echoBody)
+		offsetExpr := append(append([]byte{0x41}, sleb128(testWASMDetectOutputOffset)...), 0x0b)
+		dataSegments = append(dataSegments, append(append([]byte{0x00}, offsetExpr...), wasmName(testWASMDetectOutput)...))
+	}
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	module = append(module, wasmSection(1, wasmVector(allocateType, callType))...)
+	module = append(module, wasmSection(3, wasmVector(functions...))...)
+	module = append(module, wasmSection(5, wasmVector([]byte{0x00, 0x01}))...)
+	module = append(module, wasmSection(7, wasmVector(exports...))...)
+	module = append(module, wasmSection(10, wasmVector(bodies...))...)
+	if len(dataSegments) > 0 {
+		module = append(module, wasmSection(11, wasmVector(dataSegments...))...)
+	}
+	return module
+}
+
+func newTestWASMTransformer(t *testing.T, module []byte) (*WASM, string, error) {
+	t.Helper()
+	contextPath := t.TempDir()
+	sourcePath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(contextPath, "module.wasm"), module, 0644); err != nil {
+		t.Fatal(err)
+	}
+	tc := transformertypes.Transformer{}
+	tc.Name = "wasm"
+	tc.Spec.Config = map[string]interface{}{"wasmFile": "module.wasm"}
+	env := &environment.Environment{Env: &environment.Local{WorkspaceSource: sourcePath, WorkspaceContext: contextPath}}
+	transformer := &WASM{}
+	return transformer, sourcePath, transformer.Init(tc, env)
+}
+
+func TestWASMTransformer(t *testing.T) {
+	transformer, sourcePath, err := newTestWASMTransformer(t, newTestWASMModule(true))
+	if err != nil {
+		t.Fatalf("failed to initialize the transformer. Error: %q", err)
+	}
+
+	t.Run("detect output is decoded and the paths are converted and filled in", func(t *testing.T) {
+		services, err := transformer.DirectoryDetect(sourcePath)
+		if err != nil {
+			t.Fatalf("failed to detect. Error: %q", err)
+		}
+		want := map[string][]transformertypes.Artifact{
+			"web": {{Name: "web", Type: "Service", Paths: map[transformertypes.PathType][]string{artifacts.ServiceDirPathType: {sourcePath}}}},
+			"api": {{Name: "api", Type: "Service", Paths: map[transformertypes.PathType][]string{artifacts.ServiceDirPathType: {filepath.Join(sourcePath, "api")}}}},
+		}
+		if !cmp.Equal(services, want) {
+			t.Fatalf("the detected services are incorrect. Differences:\n%s", cmp.Diff(want, services))
+		}
+	})
+
+	t.Run("transform input round trips through the module memory", func(t *testing.T) {
+		input := transformertypes.TransformInput{
+			NewArtifacts:         []transformertypes.Artifact{{Name: "web", Type: "Service", Configs: map[transformertypes.ConfigType]interface{}{"port": float64(8080)}}},
+			AlreadySeenArtifacts: []transformertypes.Artifact{{Name: "api", Type: "Dockerfile"}},
+		}
+		echoed := transformertypes.TransformInput{}
+		if err := transformer.call(wasmTransformFnName, input, &echoed); err != nil {
+			t.Fatalf("failed to call transform. Error: %q", err)
+		}
+		if !cmp.Equal(echoed, input) {
+			t.Fatalf("the input was changed by the round trip. Differences:\n%s", cmp.Diff(input, echoed))
+		}
+		// the echoed input has none of the fields of the output
+		pathMappings, createdArtifacts, err := transformer.Transform(input.NewArtifacts, input.AlreadySeenArtifacts)
+		if err != nil || len(pathMappings) != 0 || len(createdArtifacts) != 0 {
+			t.Fatalf("expected an empty output. Actual: %+v %+v Error: %v", pathMappings, createdArtifacts, err)
+		}
+	})
+}
+
+func TestWASMTransformerMissingExport(t *testing.T) {
+	_, _, err := newTestWASMTransformer(t, newTestWASMModule(false))
+	if err == nil {
+		t.Fatalf("expected a module without the transform function to be rejected")
+	}
+	if !strings.Contains(err.Error(), wasmTransformFnName) {
+		t.Fatalf("expected the error to name the missing function. Actual: %q", err)
+	}
+}
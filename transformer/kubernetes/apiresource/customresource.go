@@ -0,0 +1,56 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// customResourceObject is a custom resource whose types are not vendored, like the resources of the service meshes
+type customResourceObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              map[string]interface{} `json:"spec,omitempty"`
+}
+
+// DeepCopyObject implements the runtime.Object interface
+func (o *customResourceObject) DeepCopyObject() runtime.Object {
+	newObj := &customResourceObject{TypeMeta: o.TypeMeta}
+	o.ObjectMeta.DeepCopyInto(&newObj.ObjectMeta)
+	if o.Spec != nil {
+		newObj.Spec = runtime.DeepCopyJSON(o.Spec)
+	}
+	return newObj
+}
+
+func newCustomResourceObject(apiVersion, kind, name string, spec map[string]interface{}) *customResourceObject {
+	return &customResourceObject{
+		TypeMeta:   metav1.TypeMeta{APIVersion: apiVersion, Kind: kind},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: getServiceLabels(name)},
+		Spec:       spec,
+	}
+}
+
+// toJSONStrings converts the strings to the type used for lists in the specs of the custom resources
+func toJSONStrings(ss []string) []interface{} {
+	values := []interface{}{}
+	for _, s := range ss {
+		values = append(values, s)
+	}
+	return values
+}
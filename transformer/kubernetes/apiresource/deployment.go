@@ -50,6 +50,8 @@ const (
 
 // Deployment handles all objects like a Deployment
 type Deployment struct {
+	// ServiceMesh is the service mesh whose sidecar is injected into the pods
+	ServiceMesh ServiceMeshT
 }
 
 // getSupportedKinds returns kinds supported by the deployment
@@ -164,11 +166,16 @@ func (d *Deployment) convertToClusterSupportedKinds(obj runtime.Object, supporte
 
 // Create section
 
+// getPodAnnotations returns the annotations of the service along with the annotations to inject the service mesh sidecar
+func (d *Deployment) getPodAnnotations(service irtypes.Service) map[string]string {
+	return common.MergeStringMaps(getAnnotations(service), getServiceMeshAnnotations(d.ServiceMesh))
+}
+
 func (d *Deployment) createDeployment(service irtypes.Service, cluster collecttypes.ClusterMetadataSpec) *apps.Deployment {
 	meta := metav1.ObjectMeta{
 		Name:        service.Name,
 		Labels:      getPodLabels(service.Name, service.Networks),
-		Annotations: getAnnotations(service),
+		Annotations: d.getPodAnnotations(service),
 	}
 	podSpec := service.PodSpec
 	podSpec = irtypes.PodSpec(d.convertVolumesKindsByPolicy(core.PodSpec(podSpec), cluster))
@@ -181,7 +188,7 @@ func (d *Deployment) createDeploymentConfig(service irtypes.Service, cluster col
 	meta := metav1.ObjectMeta{
 		Name:        service.Name,
 		Labels:      getPodLabels(service.Name, service.Networks),
-		Annotations: getAnnotations(service),
+		Annotations: d.getPodAnnotations(service),
 	}
 	podSpec := service.PodSpec
 	podSpec = irtypes.PodSpec(d.convertVolumesKindsByPolicy(core.PodSpec(podSpec), cluster))
@@ -195,7 +202,7 @@ func (d *Deployment) createReplicationController(service irtypes.Service, cluste
 	meta := metav1.ObjectMeta{
 		Name:        service.Name,
 		Labels:      getPodLabels(service.Name, service.Networks),
-		Annotations: getAnnotations(service),
+		Annotations: d.getPodAnnotations(service),
 	}
 	podSpec := service.PodSpec
 	podSpec = irtypes.PodSpec(d.convertVolumesKindsByPolicy(core.PodSpec(podSpec), cluster))
@@ -211,7 +218,7 @@ func (d *Deployment) createPod(service irtypes.Service, cluster collecttypes.Clu
 	meta := metav1.ObjectMeta{
 		Name:        service.Name,
 		Labels:      getPodLabels(service.Name, service.Networks),
-		Annotations: getAnnotations(service),
+		Annotations: d.getPodAnnotations(service),
 	}
 	return d.toPod(meta, core.PodSpec(podSpec), podSpec.RestartPolicy, cluster)
 }
@@ -223,7 +230,7 @@ func (d *Deployment) createDaemonSet(service irtypes.Service, cluster collecttyp
 	meta := metav1.ObjectMeta{
 		Name:        service.Name,
 		Labels:      getPodLabels(service.Name, service.Networks),
-		Annotations: getAnnotations(service),
+		Annotations: d.getPodAnnotations(service),
 	}
 	pod := apps.DaemonSet{
 		TypeMeta: metav1.TypeMeta{
@@ -248,7 +255,7 @@ func (d *Deployment) createJob(service irtypes.Service, cluster collecttypes.Clu
 	meta := metav1.ObjectMeta{
 		Name:        service.Name,
 		Labels:      getPodLabels(service.Name, service.Networks),
-		Annotations: getAnnotations(service),
+		Annotations: d.getPodAnnotations(service),
 	}
 	pod := batch.Job{
 		TypeMeta: metav1.TypeMeta{
@@ -0,0 +1,179 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// GlobalMetadataKind is the kind for the customization yamls declaring labels and annotations for all the k8s resources
+	GlobalMetadataKind = "GlobalMetadata"
+	keyValueSeparator  = "="
+)
+
+// GlobalMetadataFileT is the file format for the global labels and annotations
+type GlobalMetadataFileT struct {
+	metav1.TypeMeta   `yaml:",inline" json:",inline"`
+	metav1.ObjectMeta `yaml:"metadata" json:"metadata"`
+	Spec              GlobalMetadataSpecT `yaml:"spec" json:"spec"`
+}
+
+// GlobalMetadataSpecT contains the labels and annotations to inject into every generated k8s resource
+type GlobalMetadataSpecT struct {
+	Labels      map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+}
+
+// GetGlobalMetadata collects the global labels and annotations from the customizations and lets the user modify them through QA.
+// The transformers resolve it once per Transform call and pass it to TransformIRAndPersist and TransformObjsAndPersist.
+func GetGlobalMetadata() GlobalMetadataSpecT {
+	globalMetadata := GlobalMetadataSpecT{Labels: map[string]string{}, Annotations: map[string]string{}}
+	yamlPaths, err := common.GetFilesByExt(common.GetCustomizationsAssetsPath(), []string{".yaml", ".yml"})
+	if err != nil {
+		logrus.Debugf("failed to look for global labels and annotations in the customizations. Error: %q", err)
+	}
+	for _, yamlPath := range yamlPaths {
+		metadataFile := GlobalMetadataFileT{}
+		if err := common.ReadMove2KubeYamlStrict(yamlPath, &metadataFile, GlobalMetadataKind); err != nil {
+			continue
+		}
+		logrus.Debugf("found global labels and annotations at path %s", yamlPath)
+		globalMetadata.Labels = common.MergeStringMaps(globalMetadata.Labels, metadataFile.Spec.Labels)
+		globalMetadata.Annotations = common.MergeStringMaps(globalMetadata.Annotations, metadataFile.Spec.Annotations)
+	}
+	labels := qaengine.FetchMultilineInputAnswer(
+		common.ConfigTargetLabelsKey,
+		"Enter the labels to add to all the generated k8s resources (one label per line):",
+		[]string{"Each label is of the form <key>=<value>", "Example: app.kubernetes.io/part-of=myapp"},
+		joinKeyValuePairs(globalMetadata.Labels),
+		validateLabels,
+	)
+	globalMetadata.Labels, _ = parseKeyValuePairs(labels)
+	annotations := qaengine.FetchMultilineInputAnswer(
+		common.ConfigTargetAnnotationsKey,
+		"Enter the annotations to add to all the generated k8s resources (one annotation per line):",
+		[]string{"Each annotation is of the form <key>=<value>", "Example: owner=team-a@example.com"},
+		joinKeyValuePairs(globalMetadata.Annotations),
+		validateAnnotations,
+	)
+	globalMetadata.Annotations, _ = parseKeyValuePairs(annotations)
+	return globalMetadata
+}
+
+// addGlobalMetadata adds the global labels and annotations to the top level metadata of the objects
+func addGlobalMetadata(objs []runtime.Object, globalMetadata GlobalMetadataSpecT) {
+	if len(globalMetadata.Labels) == 0 && len(globalMetadata.Annotations) == 0 {
+		return
+	}
+	for _, obj := range objs {
+		objMeta, err := meta.Accessor(obj)
+		if err != nil {
+			logrus.Debugf("failed to get the metadata of the object %+v . Error: %q", obj, err)
+			continue
+		}
+		if len(globalMetadata.Labels) != 0 {
+			labels := objMeta.GetLabels()
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			for k, v := range globalMetadata.Labels {
+				labels[k] = v
+			}
+			objMeta.SetLabels(labels)
+		}
+		if len(globalMetadata.Annotations) != 0 {
+			annotations := objMeta.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			for k, v := range globalMetadata.Annotations {
+				annotations[k] = v
+			}
+			objMeta.SetAnnotations(annotations)
+		}
+	}
+}
+
+func validateLabels(answer interface{}) error {
+	labels, err := parseKeyValuePairs(answer)
+	if err != nil {
+		return err
+	}
+	for k, v := range labels {
+		if errs := validation.IsQualifiedName(k); len(errs) != 0 {
+			return fmt.Errorf("invalid label key '%s' : %s", k, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) != 0 {
+			return fmt.Errorf("invalid value '%s' for the label '%s' : %s", v, k, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+func validateAnnotations(answer interface{}) error {
+	annotations, err := parseKeyValuePairs(answer)
+	if err != nil {
+		return err
+	}
+	for k := range annotations {
+		if errs := validation.IsQualifiedName(strings.ToLower(k)); len(errs) != 0 {
+			return fmt.Errorf("invalid annotation key '%s' : %s", k, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// parseKeyValuePairs parses lines of the form <key>=<value>
+func parseKeyValuePairs(answer interface{}) (map[string]string, error) {
+	answerStr, ok := answer.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a string. Actual value is %+v of type %T", answer, answer)
+	}
+	kvs := map[string]string{}
+	for _, line := range strings.Split(answerStr, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, keyValueSeparator, 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return kvs, fmt.Errorf("the line '%s' is not of the form <key>=<value>", line)
+		}
+		kvs[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return kvs, nil
+}
+
+func joinKeyValuePairs(kvs map[string]string) string {
+	lines := []string{}
+	for k, v := range kvs {
+		lines = append(lines, k+keyValueSeparator+v)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,124 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestParseKeyValuePairs(t *testing.T) {
+	testCases := []struct {
+		name    string
+		answer  interface{}
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "empty", answer: "", want: map[string]string{}},
+		{name: "one pair per line", answer: "app.kubernetes.io/part-of=myapp\nteam = a\n\n", want: map[string]string{"app.kubernetes.io/part-of": "myapp", "team": "a"}},
+		{name: "the value can contain the separator", answer: "selector=a=b", want: map[string]string{"selector": "a=b"}},
+		{name: "empty value", answer: "team=", want: map[string]string{"team": ""}},
+		{name: "missing separator", answer: "team", wantErr: true},
+		{name: "missing key", answer: "=a", wantErr: true},
+		{name: "not a string", answer: 1, wantErr: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := parseKeyValuePairs(testCase.answer)
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error. Actual: %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to parse the key value pairs. Error: %q", err)
+			}
+			if !cmp.Equal(got, testCase.want) {
+				t.Fatalf("the key value pairs are incorrect. Differences:\n%s", cmp.Diff(testCase.want, got))
+			}
+		})
+	}
+}
+
+func TestValidateGlobalMetadata(t *testing.T) {
+	testCases := []struct {
+		name     string
+		validate func(interface{}) error
+		answer   string
+		wantErr  bool
+	}{
+		{name: "valid labels", validate: validateLabels, answer: "app.kubernetes.io/part-of=myapp\nteam=a"},
+		{name: "invalid label key", validate: validateLabels, answer: "not a key=a", wantErr: true},
+		{name: "invalid label value", validate: validateLabels, answer: "team=not a value", wantErr: true},
+		{name: "annotation values can be anything", validate: validateAnnotations, answer: "owner=Team A <team-a@example.com>"},
+		{name: "invalid annotation key", validate: validateAnnotations, answer: "not a key=a", wantErr: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if err := testCase.validate(testCase.answer); (err != nil) != testCase.wantErr {
+				t.Fatalf("expected an error: %t Actual error: %v", testCase.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAddGlobalMetadata(t *testing.T) {
+	testCases := []struct {
+		name            string
+		objMeta         metav1.ObjectMeta
+		globalMetadata  GlobalMetadataSpecT
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+	}{
+		{
+			name:       "no global metadata",
+			objMeta:    metav1.ObjectMeta{Name: "web", Labels: map[string]string{"app": "web"}},
+			wantLabels: map[string]string{"app": "web"},
+		},
+		{
+			name:            "added to the object without labels and annotations",
+			objMeta:         metav1.ObjectMeta{Name: "web"},
+			globalMetadata:  GlobalMetadataSpecT{Labels: map[string]string{"team": "a"}, Annotations: map[string]string{"owner": "team-a@example.com"}},
+			wantLabels:      map[string]string{"team": "a"},
+			wantAnnotations: map[string]string{"owner": "team-a@example.com"},
+		},
+		{
+			name:            "merged with the existing labels and annotations",
+			objMeta:         metav1.ObjectMeta{Name: "web", Labels: map[string]string{"app": "web", "team": "b"}, Annotations: map[string]string{"note": "x"}},
+			globalMetadata:  GlobalMetadataSpecT{Labels: map[string]string{"team": "a"}, Annotations: map[string]string{"owner": "team-a@example.com"}},
+			wantLabels:      map[string]string{"app": "web", "team": "a"},
+			wantAnnotations: map[string]string{"note": "x", "owner": "team-a@example.com"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service := &corev1.Service{ObjectMeta: testCase.objMeta}
+			addGlobalMetadata([]runtime.Object{service}, testCase.globalMetadata)
+			if !cmp.Equal(service.Labels, testCase.wantLabels) {
+				t.Errorf("the labels are incorrect. Differences:\n%s", cmp.Diff(testCase.wantLabels, service.Labels))
+			}
+			if !cmp.Equal(service.Annotations, testCase.wantAnnotations) {
+				t.Errorf("the annotations are incorrect. Differences:\n%s", cmp.Diff(testCase.wantAnnotations, service.Annotations))
+			}
+		})
+	}
+}
@@ -21,10 +21,13 @@ import (
 	"path/filepath"
 
 	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/transformer/sbom"
 	collecttypes "github.com/konveyor/move2kube/types/collection"
 	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/konveyor/move2kube/types/qaengine/commonqa"
 	"github.com/sirupsen/logrus"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -35,6 +38,15 @@ const (
 	gitRepoURLPlaceholder     = "<TODO: insert git repo url>"
 	contextPathPlaceholder    = "<TODO: insert path to the directory containing Dockerfile>"
 	dockerfilePathPlaceholder = "<TODO: insert path to the Dockerfile>"
+	sbomGeneratorImage        = "docker.io/anchore/syft:v0.59.0"
+	cosignImage               = "gcr.io/projectsigstore/cosign:v1.13.1"
+	cosignKeyMountPath        = "/etc/cosign"
+	oidcTokenMountPath        = "/var/run/sigstore"
+	sigstoreOIDCAudience      = "sigstore"
+	// CosignKeySecretKey is the key in the signing secret that holds the cosign private key
+	CosignKeySecretKey = "cosign.key"
+	// CosignPasswordSecretKey is the key in the signing secret that holds the password of the cosign private key
+	CosignPasswordSecretKey = "cosign.password"
 )
 
 // Pipeline handles all objects like a Tekton pipeline.
@@ -74,6 +86,7 @@ func (*Pipeline) createNewResource(irpipeline irtypes.Pipeline, ir irtypes.Enhan
 	tasks := []v1beta1.PipelineTask{}
 	firstTask := true
 	prevTaskName := ""
+	signing := commonqa.Signing()
 	i := 0
 	for imageName, container := range ir.ContainerImages {
 		if container.Build.ContainerBuildType == "" {
@@ -134,6 +147,8 @@ func (*Pipeline) createNewResource(irpipeline irtypes.Pipeline, ir irtypes.Enhan
 				}
 			}
 
+			image, tag := common.GetImageNameAndTag(imageName)
+			tag = commonqa.ImageTag(container.Build.ContextPath, tag)
 			buildPushTaskName := "build-push-" + fmt.Sprint(i)
 			buildPushTask := v1beta1.PipelineTask{
 				RunAfter: []string{cloneTaskName},
@@ -143,14 +158,20 @@ func (*Pipeline) createNewResource(irpipeline irtypes.Pipeline, ir irtypes.Enhan
 					{Name: "source", Workspace: irpipeline.WorkspaceName},
 				},
 				Params: []v1beta1.Param{
-					{Name: "IMAGE", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: "$(params.image-registry-url)/" + imageName}},
+					{Name: "IMAGE", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: "$(params.image-registry-url)/" + image + ":" + tag}},
 					{Name: "DOCKERFILE", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: dockerfilePath}},
 					{Name: "CONTEXT", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: contextPath}},
 				},
 			}
-			tasks = append(tasks, cloneTask, buildPushTask)
+			sbomTask := createSBOMTask("sbom-"+fmt.Sprint(i), buildPushTaskName, irpipeline.WorkspaceName, imageName, "$(params.image-registry-url)/"+image+":"+tag)
+			tasks = append(tasks, cloneTask, buildPushTask, sbomTask)
 			firstTask = false
-			prevTaskName = buildPushTaskName
+			prevTaskName = sbomTask.Name
+			if signing.Enabled {
+				signTask := createSignTask("sign-"+fmt.Sprint(i), sbomTask.Name, "$(params.image-registry-url)/"+image+":"+tag, signing, irpipeline.SigningSecretName)
+				tasks = append(tasks, signTask)
+				prevTaskName = signTask.Name
+			}
 		} else if container.Build.ContainerBuildType == irtypes.S2IContainerBuildTypeValue {
 			// TODO: Implement support for S2I
 			logrus.Debugf("S2I not yet supported for Tekton")
@@ -165,6 +186,89 @@ func (*Pipeline) createNewResource(irpipeline irtypes.Pipeline, ir irtypes.Enhan
 	return pipeline
 }
 
+// createSBOMTask creates a task that generates the SBOM of the pushed image.
+// The SBOM is written to the same path in the workspace as the one move2kube generated from the source.
+func createSBOMTask(name, buildPushTaskName, workspaceName, imageName, pushedImage string) v1beta1.PipelineTask {
+	sbomPath := filepath.Join(common.SBOMDir, sbom.GetFileName(imageName, sbom.CycloneDXFormat))
+	return v1beta1.PipelineTask{
+		RunAfter: []string{buildPushTaskName},
+		Name:     name,
+		TaskSpec: &v1beta1.EmbeddedTask{TaskSpec: v1beta1.TaskSpec{
+			Params: []v1beta1.ParamSpec{
+				{Name: "IMAGE", Description: "The image to generate the SBOM for.", Type: v1beta1.ParamTypeString},
+				{Name: "SBOM_PATH", Description: "The path in the workspace where the CycloneDX SBOM is written.", Type: v1beta1.ParamTypeString},
+			},
+			Workspaces: []v1beta1.WorkspaceDeclaration{{Name: "source"}},
+			Steps: []v1beta1.Step{{
+				Container: corev1.Container{
+					Name:  "syft",
+					Image: sbomGeneratorImage,
+					Args:  []string{"$(params.IMAGE)", "--output", "cyclonedx-json=$(workspaces.source.path)/$(params.SBOM_PATH)"},
+				},
+			}},
+		}},
+		Workspaces: []v1beta1.WorkspacePipelineTaskBinding{
+			{Name: "source", Workspace: workspaceName},
+		},
+		Params: []v1beta1.Param{
+			{Name: "IMAGE", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: pushedImage}},
+			{Name: "SBOM_PATH", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: sbomPath}},
+		},
+	}
+}
+
+// createSignTask creates a task that signs the pushed image using cosign.
+// Keyless signing uses a service account token with the sigstore audience as the OIDC identity token.
+func createSignTask(name, runAfter, pushedImage string, signing commonqa.SigningConfig, secretName string) v1beta1.PipelineTask {
+	step := v1beta1.Step{Container: corev1.Container{Name: "cosign", Image: cosignImage}}
+	volumes := []corev1.Volume{}
+	if signing.Mode == commonqa.KeySigningMode {
+		keyRef := signing.Key
+		if signing.UsesKeyFile() {
+			keyRef = filepath.Join(cosignKeyMountPath, CosignKeySecretKey)
+			volumes = append(volumes, corev1.Volume{
+				Name:         "cosign-key",
+				VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: secretName}},
+			})
+			step.VolumeMounts = []corev1.VolumeMount{{Name: "cosign-key", MountPath: cosignKeyMountPath, ReadOnly: true}}
+			step.Env = []corev1.EnvVar{{
+				Name: "COSIGN_PASSWORD",
+				ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  CosignPasswordSecretKey,
+				}},
+			}}
+		}
+		step.Args = []string{"sign", "--key", keyRef, "$(params.IMAGE)"}
+	} else {
+		volumes = append(volumes, corev1.Volume{
+			Name: "oidc-token",
+			VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{
+					ServiceAccountToken: &corev1.ServiceAccountTokenProjection{Audience: sigstoreOIDCAudience, Path: "oidc-token"},
+				}},
+			}},
+		})
+		step.VolumeMounts = []corev1.VolumeMount{{Name: "oidc-token", MountPath: oidcTokenMountPath, ReadOnly: true}}
+		step.Env = []corev1.EnvVar{{Name: "COSIGN_EXPERIMENTAL", Value: "1"}}
+		step.Args = []string{"sign", "--yes", "--identity-token", filepath.Join(oidcTokenMountPath, "oidc-token"), "$(params.IMAGE)"}
+	}
+	return v1beta1.PipelineTask{
+		RunAfter: []string{runAfter},
+		Name:     name,
+		TaskSpec: &v1beta1.EmbeddedTask{TaskSpec: v1beta1.TaskSpec{
+			Params: []v1beta1.ParamSpec{
+				{Name: "IMAGE", Description: "The image to sign.", Type: v1beta1.ParamTypeString},
+			},
+			Steps:   []v1beta1.Step{step},
+			Volumes: volumes,
+		}},
+		Params: []v1beta1.Param{
+			{Name: "IMAGE", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: pushedImage}},
+		},
+	}
+}
+
 // convertToClusterSupportedKinds converts the object to supported types if possible.
 func (p *Pipeline) convertToClusterSupportedKinds(obj runtime.Object, supportedKinds []string, otherobjs []runtime.Object, _ irtypes.EnhancedIR, targetCluster collecttypes.ClusterMetadata) ([]runtime.Object, bool) {
 	if common.IsPresent(p.getSupportedKinds(), obj.GetObjectKind().GroupVersionKind().Kind) {
@@ -0,0 +1,74 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/types/qaengine/commonqa"
+)
+
+func TestCreateSignTask(t *testing.T) {
+	image := "quay.io/myns/myimage:latest"
+	t.Run("keyless signing", func(t *testing.T) {
+		signing := commonqa.SigningConfig{Enabled: true, Mode: commonqa.KeylessSigningMode, Issuer: "https://oauth2.sigstore.dev/auth"}
+		task := createSignTask("sign-0", "sbom-0", image, signing, "")
+		if len(task.RunAfter) != 1 || task.RunAfter[0] != "sbom-0" {
+			t.Fatalf("expected the sign task to run after the sbom task. Actual: %+v", task.RunAfter)
+		}
+		step := task.TaskSpec.Steps[0]
+		want := []string{"sign", "--yes", "--identity-token", "/var/run/sigstore/oidc-token", "$(params.IMAGE)"}
+		if len(step.Args) != len(want) {
+			t.Fatalf("unexpected args. Expected: %v Actual: %v", want, step.Args)
+		}
+		for i := range want {
+			if step.Args[i] != want[i] {
+				t.Fatalf("unexpected args. Expected: %v Actual: %v", want, step.Args)
+			}
+		}
+		volumes := task.TaskSpec.Volumes
+		if len(volumes) != 1 || volumes[0].Projected == nil || volumes[0].Projected.Sources[0].ServiceAccountToken.Audience != sigstoreOIDCAudience {
+			t.Fatalf("expected a projected service account token volume. Actual: %+v", volumes)
+		}
+	})
+	t.Run("key based signing with a key file", func(t *testing.T) {
+		signing := commonqa.SigningConfig{Enabled: true, Mode: commonqa.KeySigningMode, Key: "cosign.key"}
+		task := createSignTask("sign-0", "sbom-0", image, signing, "myproject-cosign")
+		step := task.TaskSpec.Steps[0]
+		if step.Args[2] != "/etc/cosign/cosign.key" {
+			t.Fatalf("expected the key to be read from the mounted secret. Actual: %v", step.Args)
+		}
+		volumes := task.TaskSpec.Volumes
+		if len(volumes) != 1 || volumes[0].Secret == nil || volumes[0].Secret.SecretName != "myproject-cosign" {
+			t.Fatalf("expected the signing secret to be mounted. Actual: %+v", volumes)
+		}
+		if len(step.Env) != 1 || step.Env[0].ValueFrom.SecretKeyRef.Key != CosignPasswordSecretKey {
+			t.Fatalf("expected the password to be read from the signing secret. Actual: %+v", step.Env)
+		}
+	})
+	t.Run("key based signing with a KMS key", func(t *testing.T) {
+		signing := commonqa.SigningConfig{Enabled: true, Mode: commonqa.KeySigningMode, Key: "awskms:///alias/mykey"}
+		task := createSignTask("sign-0", "sbom-0", image, signing, "")
+		step := task.TaskSpec.Steps[0]
+		if step.Args[2] != signing.Key {
+			t.Fatalf("expected the KMS key to be used as is. Actual: %v", step.Args)
+		}
+		if len(task.TaskSpec.Volumes) != 0 {
+			t.Fatalf("expected no volumes for a KMS key. Actual: %+v", task.TaskSpec.Volumes)
+		}
+	})
+}
@@ -0,0 +1,164 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ResourceTemplateKind is the kind for the customization yamls declaring additional per service k8s resources
+	ResourceTemplateKind = "ResourceTemplate"
+)
+
+// ResourceTemplateFileT is the file format for the per service resource templates
+type ResourceTemplateFileT struct {
+	metav1.TypeMeta   `yaml:",inline" json:",inline"`
+	metav1.ObjectMeta `yaml:"metadata" json:"metadata"`
+	Spec              ResourceTemplateSpecT `yaml:"spec" json:"spec"`
+}
+
+// ResourceTemplateSpecT contains a Go template that is filled once for every service
+type ResourceTemplateSpecT struct {
+	Template     string `yaml:"template,omitempty" json:"template,omitempty"`
+	TemplateFile string `yaml:"templateFile,omitempty" json:"templateFile,omitempty"` // Relative to the resource template yaml
+}
+
+// ResourceTemplateConfig is the data used to fill the resource templates
+type ResourceTemplateConfig struct {
+	ProjectName string
+	ServiceName string
+	Service     irtypes.Service
+	// SelectorLabels are the labels used by the generated Services to select the pods of the service
+	SelectorLabels map[string]string
+}
+
+type resourceTemplateT struct {
+	name     string
+	template string
+}
+
+// TransformIRUsingResourceTemplatesAndPersist fills the resource templates in the customizations for each service and writes them to the output path
+func TransformIRUsingResourceTemplatesAndPersist(ir irtypes.EnhancedIR, projectName, outputPath string) ([]string, error) {
+	resourceTemplates := getResourceTemplates()
+	if len(resourceTemplates) == 0 {
+		return nil, nil
+	}
+	if err := os.MkdirAll(outputPath, common.DefaultDirectoryPermission); err != nil {
+		return nil, fmt.Errorf("failed to create the directory at path %s . Error: %w", outputPath, err)
+	}
+	serviceNames := []string{}
+	for serviceName := range ir.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	filesWritten := []string{}
+	for _, serviceName := range serviceNames {
+		templateConfig := ResourceTemplateConfig{
+			ProjectName:    projectName,
+			ServiceName:    serviceName,
+			Service:        ir.Services[serviceName],
+			SelectorLabels: getServiceLabels(serviceName),
+		}
+		for _, resourceTemplate := range resourceTemplates {
+			filled, err := common.GetStringFromTemplate(resourceTemplate.template, templateConfig)
+			if err != nil {
+				logrus.Errorf("failed to fill the resource template %s for the service %s . Error: %q", resourceTemplate.name, serviceName, err)
+				continue
+			}
+			if strings.TrimSpace(filled) == "" {
+				logrus.Debugf("the resource template %s is empty for the service %s", resourceTemplate.name, serviceName)
+				continue
+			}
+			if err := validateYamlDocs(filled); err != nil {
+				logrus.Errorf("the resource template %s for the service %s did not produce a valid yaml. Error: %q", resourceTemplate.name, serviceName, err)
+				continue
+			}
+			yamlPath := filepath.Join(outputPath, fmt.Sprintf("%s-%s.yaml", serviceName, common.NormalizeForFilename(resourceTemplate.name)))
+			if err := os.WriteFile(yamlPath, []byte(filled), common.DefaultFilePermission); err != nil {
+				logrus.Errorf("failed to write the resource template output to the file at path %s . Error: %q", yamlPath, err)
+				continue
+			}
+			filesWritten = append(filesWritten, yamlPath)
+		}
+	}
+	return filesWritten, nil
+}
+
+// validateYamlDocs checks that all the documents in the string are valid yaml
+func validateYamlDocs(data string) error {
+	decoder := yaml.NewDecoder(strings.NewReader(data))
+	for {
+		var doc interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// getResourceTemplates collects the resource templates from the customizations
+func getResourceTemplates() []resourceTemplateT {
+	resourceTemplates := []resourceTemplateT{}
+	yamlPaths, err := common.GetFilesByExt(common.GetCustomizationsAssetsPath(), []string{".yaml", ".yml"})
+	if err != nil {
+		logrus.Debugf("failed to look for resource templates in the customizations. Error: %q", err)
+	}
+	for _, yamlPath := range yamlPaths {
+		resourceTemplateFile := ResourceTemplateFileT{}
+		if err := common.ReadMove2KubeYamlStrict(yamlPath, &resourceTemplateFile, ResourceTemplateKind); err != nil {
+			continue
+		}
+		logrus.Debugf("found a resource template at path %s", yamlPath)
+		tpl := resourceTemplateFile.Spec.Template
+		if resourceTemplateFile.Spec.TemplateFile != "" {
+			templatePath := resourceTemplateFile.Spec.TemplateFile
+			if !filepath.IsAbs(templatePath) {
+				templatePath = filepath.Join(filepath.Dir(yamlPath), templatePath)
+			}
+			tplBytes, err := os.ReadFile(templatePath)
+			if err != nil {
+				logrus.Errorf("failed to read the template file at path %s . Error: %q", templatePath, err)
+				continue
+			}
+			tpl = string(tplBytes)
+		}
+		if tpl == "" {
+			logrus.Warnf("ignoring the resource template at path %s since the template is empty", yamlPath)
+			continue
+		}
+		name := resourceTemplateFile.Name
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(yamlPath), filepath.Ext(yamlPath))
+		}
+		resourceTemplates = append(resourceTemplates, resourceTemplateT{name: name, template: tpl})
+	}
+	return resourceTemplates
+}
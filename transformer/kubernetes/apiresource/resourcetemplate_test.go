@@ -0,0 +1,129 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+)
+
+func TestValidateYamlDocs(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "empty", data: ""},
+		{name: "single document", data: "kind: ConfigMap\nmetadata:\n  name: web\n"},
+		{name: "multiple documents", data: "kind: ConfigMap\n---\nkind: Secret\n"},
+		{name: "invalid document after a valid one", data: "kind: ConfigMap\n---\nname: [web\n", wantErr: true},
+		{name: "tabs for indentation", data: "metadata:\n\tname: web\n", wantErr: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateYamlDocs(testCase.data)
+			if testCase.wantErr && err == nil {
+				t.Fatalf("expected an error for the yaml %q", testCase.data)
+			}
+			if !testCase.wantErr && err != nil {
+				t.Fatalf("failed to validate the yaml %q . Error: %q", testCase.data, err)
+			}
+		})
+	}
+}
+
+func TestTransformIRUsingResourceTemplatesAndPersist(t *testing.T) {
+	oldAssetsPath := common.AssetsPath
+	defer func() { common.AssetsPath = oldAssetsPath }()
+	common.AssetsPath = t.TempDir()
+	customizationsPath := common.GetCustomizationsAssetsPath()
+	files := map[string]string{
+		"configmap.yaml": `apiVersion: move2kube.konveyor.io/v1alpha1
+kind: ResourceTemplate
+metadata:
+  name: config
+spec:
+  template: |
+    apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: {{ .ServiceName }}-config
+    data:
+      project: {{ .ProjectName }}
+`,
+		"pdb.yaml": `apiVersion: move2kube.konveyor.io/v1alpha1
+kind: ResourceTemplate
+metadata:
+  name: pdb
+spec:
+  templateFile: templates/pdb.tpl
+`,
+		"templates/pdb.tpl": `{{ if eq .ServiceName "web" }}apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: {{ .ServiceName }}
+{{ end }}`,
+		"invalid.yaml": `apiVersion: move2kube.konveyor.io/v1alpha1
+kind: ResourceTemplate
+metadata:
+  name: invalid
+spec:
+  template: "name: [{{ .ServiceName }}"
+`,
+		"other.yaml": "apiVersion: move2kube.konveyor.io/v1alpha1\nkind: Patches\nmetadata:\n  name: other\n",
+	}
+	for path, content := range files {
+		path = filepath.Join(customizationsPath, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), common.DefaultDirectoryPermission); err != nil {
+			t.Fatalf("failed to create the directory %s . Error: %q", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), common.DefaultFilePermission); err != nil {
+			t.Fatalf("failed to write the file %s . Error: %q", path, err)
+		}
+	}
+	ir := irtypes.NewEnhancedIRFromIR(irtypes.NewIR())
+	ir.Services = map[string]irtypes.Service{"web": irtypes.NewServiceWithName("web"), "api": irtypes.NewServiceWithName("api")}
+	outputPath := filepath.Join(t.TempDir(), "yamls")
+	filesWritten, err := TransformIRUsingResourceTemplatesAndPersist(ir, "myproject", outputPath)
+	if err != nil {
+		t.Fatalf("failed to fill the resource templates. Error: %q", err)
+	}
+	got := []string{}
+	for _, fileWritten := range filesWritten {
+		got = append(got, filepath.Base(fileWritten))
+	}
+	sort.Strings(got)
+	configFilename := common.NormalizeForFilename("config")
+	want := []string{"api-" + configFilename + ".yaml", "web-" + configFilename + ".yaml", "web-" + common.NormalizeForFilename("pdb") + ".yaml"}
+	if !cmp.Equal(got, want) {
+		t.Fatalf("the files written are incorrect. Differences:\n%s", cmp.Diff(want, got))
+	}
+	data, err := os.ReadFile(filepath.Join(outputPath, "api-"+configFilename+".yaml"))
+	if err != nil {
+		t.Fatalf("failed to read the filled template. Error: %q", err)
+	}
+	wantConfig := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: api-config\ndata:\n  project: myproject\n"
+	if string(data) != wantConfig {
+		t.Fatalf("the filled template is incorrect. Differences:\n%s", cmp.Diff(wantConfig, string(data)))
+	}
+}
@@ -41,6 +41,8 @@ const (
 
 // Service handles all objects related to a service
 type Service struct {
+	// ServiceMesh is the service mesh the services are exposed through
+	ServiceMesh ServiceMeshT
 }
 
 // getSupportedKinds returns supported kinds
@@ -56,7 +58,10 @@ func (d *Service) createNewResources(ir irtypes.EnhancedIR, supportedKinds []str
 		exposeobjectcreated := false
 		if _, _, _, st := d.getExposeInfo(service); st != "" || service.OnlyIngress {
 			// Create services depending on whether the service needs to be externally exposed
-			if common.IsPresent(supportedKinds, routeKind) {
+			if d.ServiceMesh == IstioServiceMesh {
+				// The Istio Gateway and VirtualServices are created by the ServiceMesh api resource
+				exposeobjectcreated = true
+			} else if common.IsPresent(supportedKinds, routeKind) {
 				//Create Route
 				routeObjs := d.createRoutes(service, ir, targetCluster)
 				for _, routeObj := range routeObjs {
@@ -369,7 +374,20 @@ func (d *Service) createIngress(ir irtypes.EnhancedIR, targetCluster collecttype
 	// Set the default ingressClass value
 	quesKeyClass := common.JoinQASubKeys(qaId, common.ConfigIngressClassNameKeySuffix)
 	descClass := "Provide the Ingress class name for ingress"
-	ingressClassName := qaengine.FetchStringAnswer(quesKeyClass, descClass, []string{"Leave empty to use the cluster default"}, "", nil)
+	ingressClassName := ""
+	switch ingressClasses := targetCluster.Spec.IngressClasses; len(ingressClasses) {
+	case 0:
+		ingressClassName = qaengine.FetchStringAnswer(quesKeyClass, descClass, []string{"Leave empty to use the cluster default"}, "", nil)
+	case 1:
+		// The target cluster has only one ingress class, so there is nothing to ask.
+		ingressClassName = ingressClasses[0]
+	default:
+		defaultIngressClass := targetCluster.Spec.GetDefaultIngressClass()
+		if defaultIngressClass == "" {
+			defaultIngressClass = ingressClasses[0]
+		}
+		ingressClassName = qaengine.FetchSelectAnswer(quesKeyClass, descClass, []string{"These are the ingress classes available in the target cluster"}, defaultIngressClass, ingressClasses, nil)
+	}
 
 	// Configure the rule with the above fan-out paths
 	rules := []networking.IngressRule{}
@@ -422,6 +440,9 @@ func (d *Service) createIngress(ir irtypes.EnhancedIR, targetCluster collecttype
 	if ingressClassName != "" {
 		ingress.Spec.IngressClassName = &ingressClassName
 	}
+	if d.ServiceMesh == LinkerdServiceMesh {
+		ingress.ObjectMeta.Annotations = map[string]string{linkerdIngressAnnotation: "true"}
+	}
 
 	return &ingress
 }
@@ -0,0 +1,210 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"sort"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/konveyor/move2kube/types/qaengine/commonqa"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ServiceMeshT is the service mesh the output is prepared for
+type ServiceMeshT string
+
+const (
+	// NoServiceMesh generates plain Kubernetes resources
+	NoServiceMesh ServiceMeshT = "none"
+	// IstioServiceMesh injects the Istio sidecar and exposes the services using an Istio Gateway and VirtualServices
+	IstioServiceMesh ServiceMeshT = "istio"
+	// LinkerdServiceMesh injects the Linkerd proxy and keeps the Ingress
+	LinkerdServiceMesh ServiceMeshT = "linkerd"
+)
+
+const (
+	istioNetworkingAPIVersion = "networking.istio.io/v1beta1"
+	istioSecurityAPIVersion   = "security.istio.io/v1beta1"
+	istioGatewayKind          = "Gateway"
+	istioVirtualServiceKind   = "VirtualService"
+	istioDestinationRuleKind  = "DestinationRule"
+	istioPeerAuthKind         = "PeerAuthentication"
+	istioInjectAnnotation     = "sidecar.istio.io/inject"
+	linkerdInjectAnnotation   = "linkerd.io/inject"
+	// linkerdIngressAnnotation makes the nginx ingress controller route to the service instead of the pod IPs, as required by Linkerd
+	linkerdIngressAnnotation = "nginx.ingress.kubernetes.io/service-upstream"
+)
+
+// GetServiceMesh asks for the service mesh the output should be prepared for
+func GetServiceMesh() ServiceMeshT {
+	return ServiceMeshT(qaengine.FetchSelectAnswer(
+		common.ConfigTargetServiceMeshKey,
+		"Select the service mesh the output should be prepared for",
+		[]string{"Istio replaces the Ingress with a Gateway and VirtualServices", "Linkerd keeps the Ingress"},
+		string(NoServiceMesh),
+		[]string{string(NoServiceMesh), string(IstioServiceMesh), string(LinkerdServiceMesh)},
+		nil,
+	))
+}
+
+// getServiceMeshAnnotations returns the annotations to inject the sidecar of the service mesh into the pods
+func getServiceMeshAnnotations(serviceMesh ServiceMeshT) map[string]string {
+	switch serviceMesh {
+	case IstioServiceMesh:
+		return map[string]string{istioInjectAnnotation: "true"}
+	case LinkerdServiceMesh:
+		return map[string]string{linkerdInjectAnnotation: "enabled"}
+	}
+	return nil
+}
+
+// ServiceMesh handles the custom resources of the service mesh
+type ServiceMesh struct {
+	ServiceMesh ServiceMeshT
+}
+
+// getSupportedKinds returns the kinds supported by the service mesh
+func (d *ServiceMesh) getSupportedKinds() []string {
+	return []string{istioGatewayKind, istioVirtualServiceKind, istioDestinationRuleKind, istioPeerAuthKind}
+}
+
+// createNewResources creates the service mesh resources.
+// Like the other extensions, the supported kinds are ignored since it is up to the user to install the service mesh.
+func (d *ServiceMesh) createNewResources(ir irtypes.EnhancedIR, supportedKinds []string, targetCluster collecttypes.ClusterMetadata) []runtime.Object {
+	if d.ServiceMesh != IstioServiceMesh || len(ir.Services) == 0 {
+		return nil
+	}
+	qaLabel := collecttypes.DefaultClusterSpecificQaLabel
+	if _, ok := targetCluster.Labels[collecttypes.ClusterQaLabelKey]; ok {
+		qaLabel = targetCluster.Labels[collecttypes.ClusterQaLabelKey]
+	}
+	host := targetCluster.Spec.Host
+	if host == "" && d.hasExposedServices(ir) {
+		host = commonqa.IngressHost(new(Service).getHostName(ir.Name), qaLabel)
+	}
+	tlsSecretName := ""
+	if d.hasExposedServices(ir) {
+		qaID := common.JoinQASubKeys(common.ConfigTargetKey, `"`+qaLabel+`"`)
+		quesKeyTLS := common.JoinQASubKeys(qaID, common.ConfigIngressTLSKeySuffix)
+		tlsSecretName = qaengine.FetchStringAnswer(quesKeyTLS, "Provide the TLS secret for ingress", []string{"Leave empty to use http"}, "", nil)
+	}
+	return d.createIstioResources(ir, host, tlsSecretName)
+}
+
+// convertToClusterSupportedKinds returns the service mesh resources as is
+func (d *ServiceMesh) convertToClusterSupportedKinds(obj runtime.Object, supportedKinds []string, otherobjs []runtime.Object, _ irtypes.EnhancedIR, targetCluster collecttypes.ClusterMetadata) ([]runtime.Object, bool) {
+	if common.IsPresent(d.getSupportedKinds(), obj.GetObjectKind().GroupVersionKind().Kind) {
+		return []runtime.Object{obj}, true
+	}
+	return nil, false
+}
+
+func (d *ServiceMesh) hasExposedServices(ir irtypes.EnhancedIR) bool {
+	for _, service := range ir.Services {
+		_, _, relPaths, _ := new(Service).getExposeInfo(service)
+		for _, relPath := range relPaths {
+			if relPath != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// createIstioResources creates a strict mTLS PeerAuthentication, a DestinationRule for each service,
+// and a Gateway with a VirtualService for each exposed service in place of the Ingress
+func (d *ServiceMesh) createIstioResources(ir irtypes.EnhancedIR, host, tlsSecretName string) []runtime.Object {
+	objs := []runtime.Object{
+		newCustomResourceObject(istioSecurityAPIVersion, istioPeerAuthKind, "default", map[string]interface{}{
+			"mtls": map[string]interface{}{"mode": "STRICT"},
+		}),
+	}
+	gatewayHosts := []string{}
+	serviceNames := []string{}
+	for serviceName := range ir.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		backendServiceName := service.BackendServiceName
+		if backendServiceName == "" {
+			backendServiceName = service.Name
+		}
+		if !service.OnlyIngress {
+			objs = append(objs, newCustomResourceObject(istioNetworkingAPIVersion, istioDestinationRuleKind, service.Name, map[string]interface{}{
+				"host":          service.Name,
+				"trafficPolicy": map[string]interface{}{"tls": map[string]interface{}{"mode": "ISTIO_MUTUAL"}},
+			}))
+		}
+		servicePorts, hostPrefixes, relPaths, _ := new(Service).getExposeInfo(service)
+		routes := []interface{}{}
+		serviceHosts := []string{}
+		for i, servicePort := range servicePorts {
+			if relPaths[i] == "" {
+				continue
+			}
+			serviceHost := host
+			if hostPrefixes[i] != "" {
+				serviceHost = hostPrefixes[i] + "." + host
+			}
+			if !common.IsPresent(serviceHosts, serviceHost) {
+				serviceHosts = append(serviceHosts, serviceHost)
+			}
+			if !common.IsPresent(gatewayHosts, serviceHost) {
+				gatewayHosts = append(gatewayHosts, serviceHost)
+			}
+			routes = append(routes, map[string]interface{}{
+				"match": []interface{}{map[string]interface{}{"uri": map[string]interface{}{"prefix": relPaths[i]}}},
+				"route": []interface{}{map[string]interface{}{"destination": map[string]interface{}{
+					"host": backendServiceName,
+					"port": map[string]interface{}{"number": int64(servicePort.Port)},
+				}}},
+			})
+		}
+		if len(routes) == 0 {
+			continue
+		}
+		objs = append(objs, newCustomResourceObject(istioNetworkingAPIVersion, istioVirtualServiceKind, service.Name, map[string]interface{}{
+			"hosts":    toJSONStrings(serviceHosts),
+			"gateways": []interface{}{ir.Name},
+			"http":     routes,
+		}))
+	}
+	if len(gatewayHosts) == 0 {
+		return objs
+	}
+	servers := []interface{}{map[string]interface{}{
+		"port":  map[string]interface{}{"number": int64(80), "name": "http", "protocol": "HTTP"},
+		"hosts": toJSONStrings(gatewayHosts),
+	}}
+	if tlsSecretName != "" {
+		servers = append(servers, map[string]interface{}{
+			"port":  map[string]interface{}{"number": int64(443), "name": "https", "protocol": "HTTPS"},
+			"hosts": toJSONStrings(gatewayHosts),
+			"tls":   map[string]interface{}{"mode": "SIMPLE", "credentialName": tlsSecretName},
+		})
+	}
+	objs = append(objs, newCustomResourceObject(istioNetworkingAPIVersion, istioGatewayKind, ir.Name, map[string]interface{}{
+		"selector": map[string]interface{}{"istio": "ingressgateway"},
+		"servers":  servers,
+	}))
+	return objs
+}
@@ -0,0 +1,93 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/types/collection"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	core "k8s.io/kubernetes/pkg/apis/core"
+	"k8s.io/kubernetes/pkg/apis/networking"
+)
+
+func TestGetServiceMeshAnnotations(t *testing.T) {
+	if annotations := getServiceMeshAnnotations(NoServiceMesh); annotations != nil {
+		t.Fatalf("expected no annotations without a service mesh. Actual: %+v", annotations)
+	}
+	want := map[string]string{istioInjectAnnotation: "true"}
+	if annotations := getServiceMeshAnnotations(IstioServiceMesh); !cmp.Equal(annotations, want) {
+		t.Fatalf("wrong annotations for Istio. Differences:\n%s", cmp.Diff(want, annotations))
+	}
+	want = map[string]string{linkerdInjectAnnotation: "enabled"}
+	if annotations := getServiceMeshAnnotations(LinkerdServiceMesh); !cmp.Equal(annotations, want) {
+		t.Fatalf("wrong annotations for Linkerd. Differences:\n%s", cmp.Diff(want, annotations))
+	}
+}
+
+func TestCreateIstioResources(t *testing.T) {
+	t.Run("no service mesh", func(t *testing.T) {
+		serviceMesh := ServiceMesh{ServiceMesh: NoServiceMesh}
+		ir := irtypes.NewEnhancedIRFromIR(irtypes.NewIR())
+		ir.Services = map[string]irtypes.Service{"svc1": irtypes.NewServiceWithName("svc1")}
+		if objs := serviceMesh.createNewResources(ir, nil, collection.ClusterMetadata{}); objs != nil {
+			t.Fatalf("expected no objects without a service mesh. Actual: %+v", objs)
+		}
+	})
+	t.Run("exposed and internal services", func(t *testing.T) {
+		serviceMesh := ServiceMesh{ServiceMesh: IstioServiceMesh}
+		ir := irtypes.NewEnhancedIRFromIR(irtypes.NewIR())
+		ir.Name = "myproject"
+		web := irtypes.NewServiceWithName("web")
+		web.ServiceToPodPortForwardings = []irtypes.ServiceToPodPortForwarding{{
+			ServicePort:    networking.ServiceBackendPort{Number: 8080},
+			PodPort:        networking.ServiceBackendPort{Number: 8080},
+			ServiceRelPath: "/web",
+			ServiceType:    core.ServiceTypeClusterIP,
+		}}
+		ir.Services = map[string]irtypes.Service{"web": web, "db": irtypes.NewServiceWithName("db")}
+		objs := serviceMesh.createIstioResources(ir, "myproject.com", "")
+		kinds := []string{}
+		for _, obj := range objs {
+			meshObj := obj.(*customResourceObject)
+			kinds = append(kinds, meshObj.Kind+"/"+meshObj.Name)
+		}
+		want := []string{"PeerAuthentication/default", "DestinationRule/db", "DestinationRule/web", "VirtualService/web", "Gateway/myproject"}
+		if !cmp.Equal(kinds, want) {
+			t.Fatalf("wrong objects created. Differences:\n%s", cmp.Diff(want, kinds))
+		}
+		virtualService := objs[3].(*customResourceObject)
+		wantSpec := map[string]interface{}{
+			"hosts":    []interface{}{"myproject.com"},
+			"gateways": []interface{}{"myproject"},
+			"http": []interface{}{map[string]interface{}{
+				"match": []interface{}{map[string]interface{}{"uri": map[string]interface{}{"prefix": "/web"}}},
+				"route": []interface{}{map[string]interface{}{"destination": map[string]interface{}{
+					"host": "web",
+					"port": map[string]interface{}{"number": int64(8080)},
+				}}},
+			}},
+		}
+		if !cmp.Equal(virtualService.Spec, wantSpec) {
+			t.Fatalf("wrong VirtualService spec. Differences:\n%s", cmp.Diff(wantSpec, virtualService.Spec))
+		}
+		if copied := virtualService.DeepCopyObject().(*customResourceObject); !cmp.Equal(copied.Spec, virtualService.Spec) {
+			t.Fatalf("the deep copy differs from the original. Differences:\n%s", cmp.Diff(virtualService.Spec, copied.Spec))
+		}
+	})
+}
@@ -0,0 +1,98 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"sort"
+
+	"github.com/konveyor/move2kube/common"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/konveyor/move2kube/types/qaengine/commonqa"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	prometheusOperatorAPIVersion = "monitoring.coreos.com/v1"
+	serviceMonitorKind           = "ServiceMonitor"
+	podMonitorKind               = "PodMonitor"
+)
+
+// ServiceMonitor handles the Prometheus Operator resources that scrape the metrics endpoints of the services
+type ServiceMonitor struct {
+}
+
+// getSupportedKinds returns the kinds supported by the class
+func (d *ServiceMonitor) getSupportedKinds() []string {
+	return []string{serviceMonitorKind, podMonitorKind}
+}
+
+// createNewResources creates a ServiceMonitor for each service with a metrics endpoint, or a PodMonitor if the endpoint isn't exposed by the service.
+// The supported kinds are ignored since it is up to the user to install the Prometheus Operator.
+func (d *ServiceMonitor) createNewResources(ir irtypes.EnhancedIR, supportedKinds []string, targetCluster collecttypes.ClusterMetadata) []runtime.Object {
+	serviceNames := []string{}
+	for serviceName, service := range ir.Services {
+		if service.Annotations[common.PrometheusScrapeAnnotation] == common.AnnotationLabelValue {
+			serviceNames = append(serviceNames, serviceName)
+		}
+	}
+	if len(serviceNames) == 0 || commonqa.MonitoringStack() != commonqa.PrometheusOperatorMonitoringStack {
+		return nil
+	}
+	sort.Strings(serviceNames)
+	objs := []runtime.Object{}
+	for _, serviceName := range serviceNames {
+		if obj := d.createMonitor(ir.Services[serviceName]); obj != nil {
+			objs = append(objs, obj)
+		}
+	}
+	return objs
+}
+
+// convertToClusterSupportedKinds returns the monitors as is
+func (d *ServiceMonitor) convertToClusterSupportedKinds(obj runtime.Object, supportedKinds []string, otherobjs []runtime.Object, _ irtypes.EnhancedIR, targetCluster collecttypes.ClusterMetadata) ([]runtime.Object, bool) {
+	if common.IsPresent(d.getSupportedKinds(), obj.GetObjectKind().GroupVersionKind().Kind) {
+		return []runtime.Object{obj}, true
+	}
+	return nil, false
+}
+
+func (d *ServiceMonitor) createMonitor(service irtypes.Service) runtime.Object {
+	path := service.Annotations[common.PrometheusPathAnnotation]
+	port, err := cast.ToInt32E(service.Annotations[common.PrometheusPortAnnotation])
+	if err != nil || port == 0 {
+		logrus.Errorf("the metrics port %q of the service %s is invalid. Error: %q", service.Annotations[common.PrometheusPortAnnotation], service.Name, err)
+		return nil
+	}
+	labelSelector := map[string]interface{}{"matchLabels": map[string]interface{}{selector: service.Name}}
+	servicePorts, _, _, _ := new(Service).getExposeInfo(service)
+	for _, servicePort := range servicePorts {
+		if servicePort.TargetPort.IntVal != port && servicePort.Port != port {
+			continue
+		}
+		return newCustomResourceObject(prometheusOperatorAPIVersion, serviceMonitorKind, service.Name, map[string]interface{}{
+			"selector":  labelSelector,
+			"endpoints": []interface{}{map[string]interface{}{"port": servicePort.Name, "path": path}},
+		})
+	}
+	return newCustomResourceObject(prometheusOperatorAPIVersion, podMonitorKind, service.Name, map[string]interface{}{
+		"selector":            labelSelector,
+		"podMetricsEndpoints": []interface{}{map[string]interface{}{"targetPort": int64(port), "path": path}},
+	})
+}
@@ -0,0 +1,66 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	core "k8s.io/kubernetes/pkg/apis/core"
+	"k8s.io/kubernetes/pkg/apis/networking"
+)
+
+func TestCreateMonitor(t *testing.T) {
+	getService := func(exposed bool) irtypes.Service {
+		service := irtypes.NewServiceWithName("web")
+		service.Annotations = map[string]string{
+			common.PrometheusScrapeAnnotation: common.AnnotationLabelValue,
+			common.PrometheusPathAnnotation:   "/actuator/prometheus",
+			common.PrometheusPortAnnotation:   "8080",
+		}
+		if exposed {
+			service.ServiceToPodPortForwardings = []irtypes.ServiceToPodPortForwarding{{
+				ServicePort: networking.ServiceBackendPort{Number: 80},
+				PodPort:     networking.ServiceBackendPort{Number: 8080},
+				ServiceType: core.ServiceTypeClusterIP,
+			}}
+		}
+		return service
+	}
+	t.Run("metrics port exposed by the service", func(t *testing.T) {
+		obj := new(ServiceMonitor).createMonitor(getService(true)).(*customResourceObject)
+		if obj.Kind != serviceMonitorKind {
+			t.Fatalf("expected a %s . Actual: %s", serviceMonitorKind, obj.Kind)
+		}
+		want := []interface{}{map[string]interface{}{"port": "port-80", "path": "/actuator/prometheus"}}
+		if !cmp.Equal(obj.Spec["endpoints"], want) {
+			t.Fatalf("wrong endpoints. Differences:\n%s", cmp.Diff(want, obj.Spec["endpoints"]))
+		}
+	})
+	t.Run("metrics port not exposed by the service", func(t *testing.T) {
+		obj := new(ServiceMonitor).createMonitor(getService(false)).(*customResourceObject)
+		if obj.Kind != podMonitorKind {
+			t.Fatalf("expected a %s . Actual: %s", podMonitorKind, obj.Kind)
+		}
+		want := []interface{}{map[string]interface{}{"targetPort": int64(8080), "path": "/actuator/prometheus"}}
+		if !cmp.Equal(obj.Spec["podMetricsEndpoints"], want) {
+			t.Fatalf("wrong endpoints. Differences:\n%s", cmp.Diff(want, obj.Spec["podMetricsEndpoints"]))
+		}
+	})
+}
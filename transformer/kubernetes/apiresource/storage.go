@@ -17,7 +17,10 @@
 package apiresource
 
 import (
+	"fmt"
+
 	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
 	collecttypes "github.com/konveyor/move2kube/types/collection"
 	irtypes "github.com/konveyor/move2kube/types/ir"
 	"github.com/sirupsen/logrus"
@@ -46,7 +49,7 @@ func (s *Storage) createNewResources(ir irtypes.EnhancedIR, supportedKinds []str
 			objs = append(objs, s.createSecret(stObj))
 		}
 		if stObj.StorageType == irtypes.PVCKind {
-			objs = append(objs, s.createPVC(stObj))
+			objs = append(objs, s.createPVC(stObj, targetCluster))
 		}
 	}
 	return objs
@@ -101,7 +104,22 @@ func (s *Storage) createSecret(st irtypes.Storage) *core.Secret {
 	return secret
 }
 
-func (s *Storage) createPVC(st irtypes.Storage) *core.PersistentVolumeClaim {
+func (s *Storage) createPVC(st irtypes.Storage, targetCluster collecttypes.ClusterMetadata) *core.PersistentVolumeClaim {
+	if st.StorageClassName == nil && len(targetCluster.Spec.StorageClasses) > 1 {
+		def := targetCluster.Spec.GetDefaultStorageClass()
+		if def == "" {
+			def = targetCluster.Spec.StorageClasses[0]
+		}
+		storageClassName := qaengine.FetchSelectAnswer(
+			fmt.Sprintf(common.ConfigStoragesStorageClassKey, `"`+st.Name+`"`),
+			fmt.Sprintf("Select the storage class for the persistent volume claim %s:", st.Name),
+			[]string{"The storage classes are the ones available in the target cluster"},
+			def,
+			targetCluster.Spec.StorageClasses,
+			nil,
+		)
+		st.StorageClassName = &storageClassName
+	}
 	pvc := &core.PersistentVolumeClaim{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       string(irtypes.PVCKind),
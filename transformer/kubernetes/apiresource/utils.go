@@ -34,7 +34,7 @@ import (
 )
 
 // TransformIRAndPersist transforms IR to yamls and writes to filesystem
-func TransformIRAndPersist(ir irtypes.EnhancedIR, outputPath string, apis []IAPIResource, targetCluster collecttypes.ClusterMetadata) (files []string, err error) {
+func TransformIRAndPersist(ir irtypes.EnhancedIR, outputPath string, apis []IAPIResource, targetCluster collecttypes.ClusterMetadata, globalMetadata GlobalMetadataSpecT) (files []string, err error) {
 	targetObjs := []runtime.Object{}
 	for _, apiResource := range apis {
 		newObjs := (&APIResource{IAPIResource: apiResource}).convertIRToObjects(ir, targetCluster)
@@ -48,7 +48,7 @@ func TransformIRAndPersist(ir irtypes.EnhancedIR, outputPath string, apis []IAPI
 	if err != nil {
 		logrus.Errorf("Failed to fix, convert and transform the objects. Error: %q", err)
 	}
-	filesWritten, err := writeObjects(outputPath, convertedObjs)
+	filesWritten, err := writeObjects(outputPath, convertedObjs, globalMetadata)
 	if err != nil {
 		logrus.Errorf("Failed to write the transformed objects to the directory at path %s . Error: %q", outputPath, err)
 		return nil, err
@@ -57,7 +57,7 @@ func TransformIRAndPersist(ir irtypes.EnhancedIR, outputPath string, apis []IAPI
 }
 
 // TransformObjsAndPersist transforms versions of yamls in current directory and writes to filesystem
-func TransformObjsAndPersist(inputPath, outputPath string, apis []IAPIResource, targetCluster collecttypes.ClusterMetadata) (files []string, err error) {
+func TransformObjsAndPersist(inputPath, outputPath string, apis []IAPIResource, targetCluster collecttypes.ClusterMetadata, globalMetadata GlobalMetadataSpecT) (files []string, err error) {
 	targetObjs := []runtime.Object{}
 	if pendingObjs := k8sschema.GetKubernetesObjsInDir(inputPath); len(pendingObjs) != 0 {
 		for _, apiResource := range apis {
@@ -75,7 +75,7 @@ func TransformObjsAndPersist(inputPath, outputPath string, apis []IAPIResource,
 	if err != nil {
 		logrus.Errorf("Failed to fix, convert and transform the objects. Error: %q", err)
 	}
-	filesWritten, err := writeObjects(outputPath, convertedObjs)
+	filesWritten, err := writeObjects(outputPath, convertedObjs, globalMetadata)
 	if err != nil {
 		logrus.Errorf("Failed to write the transformed objects to the directory at path %s . Error: %q", outputPath, err)
 		return nil, err
@@ -83,11 +83,12 @@ func TransformObjsAndPersist(inputPath, outputPath string, apis []IAPIResource,
 	return filesWritten, nil
 }
 
-// writeObjects writes the runtime objects to yaml files
-func writeObjects(outputPath string, objs []runtime.Object) ([]string, error) {
+// writeObjects adds the global labels and annotations to the runtime objects and writes them to yaml files
+func writeObjects(outputPath string, objs []runtime.Object, globalMetadata GlobalMetadataSpecT) ([]string, error) {
 	if err := os.MkdirAll(outputPath, common.DefaultDirectoryPermission); err != nil {
 		return nil, err
 	}
+	addGlobalMetadata(objs, globalMetadata)
 	filesWritten := []string{}
 	for _, obj := range objs {
 		objYamlBytes, err := common.MarshalObjToYaml(obj)
@@ -0,0 +1,108 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"sort"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	vpaAPIVersion = "autoscaling.k8s.io/v1"
+	vpaKind       = "VerticalPodAutoscaler"
+	// vpaOffUpdateMode only computes the recommended resource requests without applying them to the pods
+	vpaOffUpdateMode = "Off"
+)
+
+// VerticalPodAutoscaler handles the VerticalPodAutoscalers that recommend the resource requests of the workloads
+type VerticalPodAutoscaler struct {
+}
+
+// getSupportedKinds returns the kinds supported by the class
+func (v *VerticalPodAutoscaler) getSupportedKinds() []string {
+	return []string{vpaKind}
+}
+
+// createNewResources creates a VerticalPodAutoscaler in recommendation mode for each long running workload.
+// The supported kinds are ignored since it is up to the user to install the VerticalPodAutoscaler.
+func (v *VerticalPodAutoscaler) createNewResources(ir irtypes.EnhancedIR, supportedKinds []string, targetCluster collecttypes.ClusterMetadata) []runtime.Object {
+	serviceNames := []string{}
+	for serviceName, service := range ir.Services {
+		if len(service.Containers) == 0 || service.OnlyIngress || service.RestartPolicy == core.RestartPolicyNever || service.RestartPolicy == core.RestartPolicyOnFailure {
+			continue
+		}
+		serviceNames = append(serviceNames, serviceName)
+	}
+	if len(serviceNames) == 0 {
+		return nil
+	}
+	if !qaengine.FetchBoolAnswer(common.ConfigTargetVPAKey, "Generate VerticalPodAutoscalers in recommendation mode for the workloads?", []string{"The recommendations can be used to right size the resource requests once the application is running. The VerticalPodAutoscaler has to be installed in the cluster."}, false, nil) {
+		return nil
+	}
+	sort.Strings(serviceNames)
+	objs := []runtime.Object{}
+	for _, serviceName := range serviceNames {
+		if obj := v.createVPA(ir.Services[serviceName], supportedKinds); obj != nil {
+			objs = append(objs, obj)
+		}
+	}
+	return objs
+}
+
+// convertToClusterSupportedKinds returns the VerticalPodAutoscalers as is
+func (v *VerticalPodAutoscaler) convertToClusterSupportedKinds(obj runtime.Object, supportedKinds []string, otherobjs []runtime.Object, _ irtypes.EnhancedIR, targetCluster collecttypes.ClusterMetadata) ([]runtime.Object, bool) {
+	if common.IsPresent(v.getSupportedKinds(), obj.GetObjectKind().GroupVersionKind().Kind) {
+		return []runtime.Object{obj}, true
+	}
+	return nil, false
+}
+
+func (v *VerticalPodAutoscaler) createVPA(service irtypes.Service, supportedKinds []string) runtime.Object {
+	apiVersion, kind := getWorkloadAPIVersionAndKind(service, supportedKinds)
+	if kind == podKind {
+		logrus.Warnf("skipping the VerticalPodAutoscaler of the service %s since it can't target the bare pods created for it", service.Name)
+		return nil
+	}
+	return newCustomResourceObject(vpaAPIVersion, vpaKind, service.Name, map[string]interface{}{
+		"targetRef":    map[string]interface{}{"apiVersion": apiVersion, "kind": kind, "name": service.Name},
+		"updatePolicy": map[string]interface{}{"updateMode": vpaOffUpdateMode},
+	})
+}
+
+// getWorkloadAPIVersionAndKind returns the kind of workload the Deployment resource creates for the service
+func getWorkloadAPIVersionAndKind(service irtypes.Service, supportedKinds []string) (string, string) {
+	switch {
+	case service.Daemon:
+		return "apps/v1", daemonSetKind
+	case common.IsPresent(supportedKinds, common.DeploymentKind):
+		return "apps/v1", common.DeploymentKind
+	case common.IsPresent(supportedKinds, deploymentConfigKind):
+		return "apps.openshift.io/v1", deploymentConfigKind
+	case common.IsPresent(supportedKinds, replicationControllerKind):
+		return "v1", replicationControllerKind
+	case common.IsPresent(supportedKinds, podKind):
+		return "v1", podKind
+	}
+	return "apps/v1", common.DeploymentKind
+}
@@ -0,0 +1,61 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+)
+
+func TestCreateVPA(t *testing.T) {
+	t.Run("deployment", func(t *testing.T) {
+		service := irtypes.NewServiceWithName("web")
+		obj := new(VerticalPodAutoscaler).createVPA(service, []string{common.DeploymentKind, podKind}).(*customResourceObject)
+		want := map[string]interface{}{
+			"targetRef":    map[string]interface{}{"apiVersion": "apps/v1", "kind": common.DeploymentKind, "name": "web"},
+			"updatePolicy": map[string]interface{}{"updateMode": vpaOffUpdateMode},
+		}
+		if !cmp.Equal(obj.Spec, want) {
+			t.Fatalf("wrong spec. Differences:\n%s", cmp.Diff(want, obj.Spec))
+		}
+	})
+	t.Run("deployment config", func(t *testing.T) {
+		service := irtypes.NewServiceWithName("web")
+		obj := new(VerticalPodAutoscaler).createVPA(service, []string{deploymentConfigKind}).(*customResourceObject)
+		want := map[string]interface{}{"apiVersion": "apps.openshift.io/v1", "kind": deploymentConfigKind, "name": "web"}
+		if !cmp.Equal(obj.Spec["targetRef"], want) {
+			t.Fatalf("wrong target. Differences:\n%s", cmp.Diff(want, obj.Spec["targetRef"]))
+		}
+	})
+	t.Run("daemon set", func(t *testing.T) {
+		service := irtypes.NewServiceWithName("agent")
+		service.Daemon = true
+		obj := new(VerticalPodAutoscaler).createVPA(service, []string{common.DeploymentKind}).(*customResourceObject)
+		want := map[string]interface{}{"apiVersion": "apps/v1", "kind": daemonSetKind, "name": "agent"}
+		if !cmp.Equal(obj.Spec["targetRef"], want) {
+			t.Fatalf("wrong target. Differences:\n%s", cmp.Diff(want, obj.Spec["targetRef"]))
+		}
+	})
+	t.Run("bare pods", func(t *testing.T) {
+		if obj := new(VerticalPodAutoscaler).createVPA(irtypes.NewServiceWithName("web"), []string{podKind}); obj != nil {
+			t.Fatalf("expected no VerticalPodAutoscaler for bare pods. Actual: %+v", obj)
+		}
+	})
+}
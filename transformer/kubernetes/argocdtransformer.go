@@ -81,6 +81,7 @@ func (t *ArgoCD) Transform(newArtifacts []transformertypes.Artifact, alreadySeen
 	pathMappings := []transformertypes.PathMapping{}
 	createdArtifacts := []transformertypes.Artifact{}
 
+	globalMetadata := apiresource.GetGlobalMetadata()
 	for _, newArtifact := range newArtifacts {
 		if newArtifact.Type != irtypes.IRArtifactType {
 			continue
@@ -109,7 +110,7 @@ func (t *ArgoCD) Transform(newArtifacts []transformertypes.Artifact, alreadySeen
 		tempDest := filepath.Join(t.Env.TempPath, deployCICDDir)
 		logrus.Debugf("Generating ArgoCD yamls for CI/CD")
 		enhancedIR := t.setupEnhancedIR(ir, t.Env.GetProjectName())
-		files, err := apiresource.TransformIRAndPersist(enhancedIR, tempDest, resources, clusterConfig)
+		files, err := apiresource.TransformIRAndPersist(enhancedIR, tempDest, resources, clusterConfig, globalMetadata)
 		if err != nil {
 			logrus.Errorf("failed to transform and persist IR. Error: %q", err)
 			continue
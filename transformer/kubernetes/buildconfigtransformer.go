@@ -90,6 +90,7 @@ func (t *BuildConfig) Transform(newArtifacts []transformertypes.Artifact, alread
 	logrus.Debugf("Translating IR using Buildconfig transformer")
 	pathMappings = []transformertypes.PathMapping{}
 	createdArtifacts = []transformertypes.Artifact{}
+	globalMetadata := apiresource.GetGlobalMetadata()
 	for _, newArtifact := range newArtifacts {
 		if newArtifact.Type != irtypes.IRArtifactType {
 			continue
@@ -120,7 +121,7 @@ func (t *BuildConfig) Transform(newArtifacts []transformertypes.Artifact, alread
 		tempDest := filepath.Join(t.Env.TempPath, deployCICDDir)
 		logrus.Infof("Generating Buildconfig pipeline for CI/CD")
 		enhancedIR := t.setupEnhancedIR(ir, t.Env.GetProjectName())
-		files, err := apiresource.TransformIRAndPersist(enhancedIR, tempDest, apis, clusterConfig)
+		files, err := apiresource.TransformIRAndPersist(enhancedIR, tempDest, apis, clusterConfig, globalMetadata)
 		if err != nil {
 			logrus.Errorf("Unable to transform and persist IR : %s", err)
 			return nil, nil, err
@@ -18,6 +18,7 @@ package kubernetes
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/konveyor/move2kube/common"
 	"github.com/konveyor/move2kube/environment"
@@ -41,6 +42,11 @@ const (
 	ClusterMetadata transformertypes.ConfigType = "ClusterMetadata"
 )
 
+var (
+	clusterSelectors      = map[string]*ClusterSelectorTransformer{} // [cluster qa label]
+	clusterSelectorsMutex sync.Mutex
+)
+
 // ClusterSelectorTransformer implements Transformer interface
 type ClusterSelectorTransformer struct {
 	Config   transformertypes.Transformer
@@ -84,6 +90,9 @@ func (t *ClusterSelectorTransformer) Init(tc transformertypes.Transformer, e *en
 	if t.CSConfig.ClusterQaLabel == "" {
 		t.CSConfig.ClusterQaLabel = defaultQALabel
 	}
+	clusterSelectorsMutex.Lock()
+	clusterSelectors[t.CSConfig.ClusterQaLabel] = t
+	clusterSelectorsMutex.Unlock()
 	return nil
 }
 
@@ -99,14 +108,39 @@ func (t *ClusterSelectorTransformer) DirectoryDetect(dir string) (services map[s
 
 // Transform transforms artifacts
 func (t *ClusterSelectorTransformer) Transform(newArtifacts []transformertypes.Artifact, alreadySeenArtifacts []transformertypes.Artifact) (pathMappings []transformertypes.PathMapping, createdArtifacts []transformertypes.Artifact, err error) {
+	cluster, err := t.selectCluster()
+	if err != nil {
+		logrus.Errorf("%s", err)
+		return nil, nil, err
+	}
+	for ai := range newArtifacts {
+		if newArtifacts[ai].Configs == nil {
+			newArtifacts[ai].Configs = make(map[transformertypes.ConfigType]interface{})
+		}
+		newArtifacts[ai].Configs[ClusterMetadata] = cluster
+	}
+	return nil, newArtifacts, nil
+}
+
+// SelectTargetCluster returns the metadata of the target cluster for the QA label,
+// asking for the cluster type using the cluster selector with that label if it has not been chosen yet
+func SelectTargetCluster(clusterQaLabel string) (collecttypes.ClusterMetadata, error) {
+	clusterSelectorsMutex.Lock()
+	t, ok := clusterSelectors[clusterQaLabel]
+	clusterSelectorsMutex.Unlock()
+	if !ok {
+		return collecttypes.ClusterMetadata{}, fmt.Errorf("no cluster selector has been initialized for the cluster qa label %s", clusterQaLabel)
+	}
+	return t.selectCluster()
+}
+
+func (t *ClusterSelectorTransformer) selectCluster() (collecttypes.ClusterMetadata, error) {
 	clusterTypeList := []string{}
 	for c := range t.Clusters {
 		clusterTypeList = append(clusterTypeList, c)
 	}
 	if len(clusterTypeList) == 0 {
-		err = fmt.Errorf("no cluster configuration available")
-		logrus.Errorf("%s", err)
-		return nil, nil, err
+		return collecttypes.ClusterMetadata{}, fmt.Errorf("no cluster configuration available")
 	}
 	def := defaultClusterType
 	if !common.IsPresent(clusterTypeList, def) {
@@ -118,19 +152,13 @@ func (t *ClusterSelectorTransformer) Transform(newArtifacts []transformertypes.A
 		[]string{"Choose the cluster type you would like to target"}, def, clusterTypeList,
 		nil,
 	)
-	for ai := range newArtifacts {
-		if newArtifacts[ai].Configs == nil {
-			newArtifacts[ai].Configs = make(map[transformertypes.ConfigType]interface{})
-		}
-		cluster := t.Clusters[clusterType]
-		if cluster.Labels == nil {
-			cluster.Labels = make(map[string]string)
-		}
-		cluster.Labels[collecttypes.ClusterQaLabelKey] = t.CSConfig.ClusterQaLabel
-		t.Clusters[clusterType] = cluster
-		newArtifacts[ai].Configs[ClusterMetadata] = t.Clusters[clusterType]
+	cluster := t.Clusters[clusterType]
+	if cluster.Labels == nil {
+		cluster.Labels = make(map[string]string)
 	}
-	return nil, newArtifacts, nil
+	cluster.Labels[collecttypes.ClusterQaLabelKey] = t.CSConfig.ClusterQaLabel
+	t.Clusters[clusterType] = cluster
+	return cluster, nil
 }
 
 // GetClusterMetadata returns the Cluster Metadata
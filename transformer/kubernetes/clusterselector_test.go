@@ -0,0 +1,96 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/qaengine"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+)
+
+func TestSelectTargetCluster(t *testing.T) {
+	qaengine.AddEngine(qaengine.NewDefaultEngine())
+	kubernetesCluster := collecttypes.NewClusterMetadata(defaultClusterType)
+	openshiftCluster := collecttypes.NewClusterMetadata("Openshift")
+	clusterSelectorsMutex.Lock()
+	clusterSelectors["selecttest"] = &ClusterSelectorTransformer{
+		Clusters: map[string]collecttypes.ClusterMetadata{defaultClusterType: kubernetesCluster, "Openshift": openshiftCluster},
+		CSConfig: &ClusterSelectorConfig{ClusterQaLabel: "selecttest"},
+	}
+	clusterSelectors["emptytest"] = &ClusterSelectorTransformer{
+		Clusters: map[string]collecttypes.ClusterMetadata{},
+		CSConfig: &ClusterSelectorConfig{ClusterQaLabel: "emptytest"},
+	}
+	clusterSelectorsMutex.Unlock()
+	defer func() {
+		clusterSelectorsMutex.Lock()
+		delete(clusterSelectors, "selecttest")
+		delete(clusterSelectors, "emptytest")
+		clusterSelectorsMutex.Unlock()
+	}()
+	testCases := []struct {
+		name           string
+		clusterQaLabel string
+		want           string
+		wantErr        bool
+	}{
+		{name: "the default cluster type is selected", clusterQaLabel: "selecttest", want: defaultClusterType},
+		{name: "no cluster selector for the qa label", clusterQaLabel: "missingtest", wantErr: true},
+		{name: "no cluster configurations", clusterQaLabel: "emptytest", wantErr: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cluster, err := SelectTargetCluster(testCase.clusterQaLabel)
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error. Actual cluster: %+v", cluster)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to select the target cluster. Error: %q", err)
+			}
+			if cluster.Name != testCase.want {
+				t.Fatalf("expected the cluster %s to be selected. Actual: %s", testCase.want, cluster.Name)
+			}
+			if got := cluster.Labels[collecttypes.ClusterQaLabelKey]; got != testCase.clusterQaLabel {
+				t.Fatalf("expected the cluster to have the qa label %s . Actual: %s", testCase.clusterQaLabel, got)
+			}
+		})
+	}
+}
+
+func TestClusterSelectorTransform(t *testing.T) {
+	qaengine.AddEngine(qaengine.NewDefaultEngine())
+	cluster := collecttypes.NewClusterMetadata(defaultClusterType)
+	transformer := &ClusterSelectorTransformer{
+		Clusters: map[string]collecttypes.ClusterMetadata{defaultClusterType: cluster},
+		CSConfig: &ClusterSelectorConfig{ClusterQaLabel: "transformtest"},
+	}
+	_, createdArtifacts, err := transformer.Transform([]transformertypes.Artifact{{Name: "web"}}, nil)
+	if err != nil {
+		t.Fatalf("failed to transform. Error: %q", err)
+	}
+	cluster.Labels = map[string]string{collecttypes.ClusterQaLabelKey: "transformtest"}
+	want := []transformertypes.Artifact{{Name: "web", Configs: map[transformertypes.ConfigType]interface{}{ClusterMetadata: cluster}}}
+	if !cmp.Equal(createdArtifacts, want) {
+		t.Fatalf("the created artifacts are incorrect. Differences:\n%s", cmp.Diff(want, createdArtifacts))
+	}
+}
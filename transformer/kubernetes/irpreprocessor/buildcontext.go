@@ -0,0 +1,100 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// buildContextMaxDepth is how deep inside the build context the dependency and configuration files are searched for
+	buildContextMaxDepth = 4
+)
+
+var (
+	ignoredBuildContextDirs = []string{"node_modules", "vendor", "target", "build", "test"}
+)
+
+// getBuildContextPaths returns the build contexts of the images used by the containers of the service
+func getBuildContextPaths(ir irtypes.IR, service irtypes.Service) []string {
+	contextPaths := []string{}
+	for _, container := range service.Containers {
+		if image, ok := ir.ContainerImages[container.Image]; ok && image.Build.ContextPath != "" && !common.IsPresent(contextPaths, image.Build.ContextPath) {
+			contextPaths = append(contextPaths, image.Build.ContextPath)
+		}
+	}
+	return contextPaths
+}
+
+// readBuildContextFiles returns the contents of the files in the build context whose names are accepted by the filter
+func readBuildContextFiles(contextPath string, filter func(name string) bool) map[string]string {
+	contents := map[string]string{} // [file path]file content
+	if err := filepath.WalkDir(contextPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			relPath, err := filepath.Rel(contextPath, path)
+			if err != nil {
+				return nil
+			}
+			if path != contextPath && (strings.HasPrefix(d.Name(), ".") || common.IsPresent(ignoredBuildContextDirs, d.Name()) || len(strings.Split(relPath, string(os.PathSeparator))) > buildContextMaxDepth) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !filter(d.Name()) {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			logrus.Debugf("failed to read the file %s . Error: %q", path, err)
+			return nil
+		}
+		contents[path] = string(content)
+		return nil
+	}); err != nil {
+		logrus.Debugf("failed to walk the build context %s . Error: %q", contextPath, err)
+	}
+	return contents
+}
+
+func matchesAnyFilePattern(name string, filePatterns []string) bool {
+	for _, filePattern := range filePatterns {
+		if matched, _ := filepath.Match(filePattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAllPatterns(content string, patterns []*regexp.Regexp) bool {
+	for _, pattern := range patterns {
+		if !pattern.MatchString(content) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,256 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/magiconair/properties"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	imageConfigFileType     = "image"
+	configMapConfigFileType = "configmap"
+	envConfigFileType       = "env"
+
+	propertiesConfigFormat = "properties"
+	yamlConfigFormat       = "yaml"
+	jsonConfigFormat       = "json"
+	dotEnvConfigFormat     = "dotenv"
+)
+
+// configFileRule describes a kind of configuration file and how it is provided to the application
+type configFileRule struct {
+	files  []string
+	format string
+	// defaultType is the default choice of how the file is provided to the containers
+	defaultType string
+	// defaultMountDir is the directory the application reads the file from when it is mounted
+	defaultMountDir string
+	// mountDirEnv is set to the directory the file is mounted at, to make the application read the file from there
+	mountDirEnv       string
+	mountDirEnvFormat string
+	// separator joins the nested keys of the file when flattening them
+	separator string
+	// toEnvName converts the flattened keys to the names of the env vars the application reads
+	toEnvName func(key string) string
+}
+
+var configFileRules = []configFileRule{
+	{files: []string{"application*.properties", "bootstrap*.properties"}, format: propertiesConfigFormat, defaultType: imageConfigFileType, defaultMountDir: "/config", mountDirEnv: "SPRING_CONFIG_ADDITIONAL_LOCATION", mountDirEnvFormat: "optional:file:%s/", separator: ".", toEnvName: toSpringEnvName},
+	{files: []string{"application*.yml", "application*.yaml", "bootstrap*.yml", "bootstrap*.yaml"}, format: yamlConfigFormat, defaultType: imageConfigFileType, defaultMountDir: "/config", mountDirEnv: "SPRING_CONFIG_ADDITIONAL_LOCATION", mountDirEnvFormat: "optional:file:%s/", separator: ".", toEnvName: toSpringEnvName},
+	{files: []string{"appsettings*.json"}, format: jsonConfigFormat, defaultType: imageConfigFileType, defaultMountDir: "/app", separator: "__", toEnvName: func(key string) string { return key }},
+	{files: []string{".env"}, format: dotEnvConfigFormat, defaultType: envConfigFileType, defaultMountDir: "/app", toEnvName: func(key string) string { return key }},
+}
+
+// configFilePreprocessor lets the user choose whether each configuration file detected in the build context
+// stays in the image, is mounted from a ConfigMap or is converted to env vars
+type configFilePreprocessor struct {
+}
+
+func (p configFilePreprocessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
+	serviceNames := []string{}
+	for serviceName := range ir.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		if len(service.Containers) == 0 {
+			continue
+		}
+		for _, contextPath := range getBuildContextPaths(ir, service) {
+			contents := readBuildContextFiles(contextPath, func(name string) bool { return getConfigFileRule(name) != nil })
+			paths := []string{}
+			for path := range contents {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			for _, configFilePath := range paths {
+				relPath, err := filepath.Rel(contextPath, configFilePath)
+				if err != nil {
+					logrus.Errorf("failed to make the path %s relative to the build context %s . Error: %q", configFilePath, contextPath, err)
+					continue
+				}
+				ir = p.configureConfigFile(ir, serviceName, filepath.ToSlash(relPath), contents[configFilePath])
+			}
+		}
+	}
+	return ir, nil
+}
+
+func (p configFilePreprocessor) configureConfigFile(ir irtypes.IR, serviceName, relPath, content string) irtypes.IR {
+	fileName := path.Base(relPath)
+	rule := getConfigFileRule(fileName)
+	configFileType := qaengine.FetchSelectAnswer(
+		fmt.Sprintf(common.ConfigServicesConfigFilesTypeKey, `"`+serviceName+`"`, `"`+relPath+`"`),
+		fmt.Sprintf("Select how the configuration file %s of the service %s should be provided to the containers:", relPath, serviceName),
+		[]string{"image keeps the file in the container image, configmap mounts the file from a ConfigMap, env converts each setting in the file to an env var"},
+		rule.defaultType,
+		[]string{imageConfigFileType, configMapConfigFileType, envConfigFileType},
+		nil,
+	)
+	configMapName := common.NormalizeForMetadataName(serviceName + "-" + strings.TrimPrefix(relPath, "."))
+	service := ir.Services[serviceName]
+	switch configFileType {
+	case configMapConfigFileType:
+		mountPath := qaengine.FetchStringAnswer(
+			fmt.Sprintf(common.ConfigServicesConfigFilesMountPathKey, `"`+serviceName+`"`, `"`+relPath+`"`),
+			fmt.Sprintf("Enter the path the configuration file %s of the service %s should be mounted at:", relPath, serviceName),
+			[]string{"The path should be one the application reads its configuration from"},
+			path.Join(rule.defaultMountDir, fileName),
+			nil,
+		)
+		ir.AddStorage(irtypes.Storage{Name: configMapName, StorageType: irtypes.ConfigMapKind, Content: map[string][]byte{fileName: []byte(content)}})
+		service.AddVolume(core.Volume{Name: configMapName, VolumeSource: core.VolumeSource{ConfigMap: &core.ConfigMapVolumeSource{LocalObjectReference: core.LocalObjectReference{Name: configMapName}}}})
+		for i := range service.Containers {
+			service.Containers[i].VolumeMounts = append(service.Containers[i].VolumeMounts, core.VolumeMount{Name: configMapName, MountPath: mountPath, SubPath: fileName, ReadOnly: true})
+			if rule.mountDirEnv != "" {
+				service.Containers[i].Env = addEnvIfNotPresent(service.Containers[i].Env, core.EnvVar{Name: rule.mountDirEnv, Value: fmt.Sprintf(rule.mountDirEnvFormat, path.Dir(mountPath))})
+			}
+		}
+	case envConfigFileType:
+		envs, err := getConfigFileEnvs(content, *rule)
+		if err != nil {
+			logrus.Errorf("failed to convert the configuration file %s of the service %s to env vars. Keeping the file in the image. Error: %q", relPath, serviceName, err)
+			return ir
+		}
+		data := map[string][]byte{}
+		for name, value := range envs {
+			data[name] = []byte(value)
+		}
+		ir.AddStorage(irtypes.Storage{Name: configMapName, StorageType: irtypes.ConfigMapKind, Content: data})
+		names := []string{}
+		for name := range envs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for i := range service.Containers {
+			for _, name := range names {
+				service.Containers[i].Env = addEnvIfNotPresent(service.Containers[i].Env, core.EnvVar{
+					Name:      name,
+					ValueFrom: &core.EnvVarSource{ConfigMapKeyRef: &core.ConfigMapKeySelector{LocalObjectReference: core.LocalObjectReference{Name: configMapName}, Key: name}},
+				})
+			}
+		}
+	default:
+		return ir
+	}
+	ir.Services[serviceName] = service
+	return ir
+}
+
+func getConfigFileRule(name string) *configFileRule {
+	for i, rule := range configFileRules {
+		if matchesAnyFilePattern(name, rule.files) {
+			return &configFileRules[i]
+		}
+	}
+	return nil
+}
+
+// getConfigFileEnvs returns the env vars equivalent to the settings in the configuration file
+func getConfigFileEnvs(content string, rule configFileRule) (map[string]string, error) {
+	settings := map[string]string{}
+	switch rule.format {
+	case propertiesConfigFormat:
+		props, err := properties.LoadString(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the properties. Error: %w", err)
+		}
+		settings = props.Map()
+	case dotEnvConfigFormat:
+		envs, err := godotenv.Unmarshal(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the env file. Error: %w", err)
+		}
+		settings = envs
+	case yamlConfigFormat, jsonConfigFormat:
+		var value interface{}
+		var err error
+		if rule.format == yamlConfigFormat {
+			err = yaml.Unmarshal([]byte(content), &value)
+		} else {
+			err = json.Unmarshal([]byte(content), &value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the %s configuration. Error: %w", rule.format, err)
+		}
+		flattenConfig("", value, rule.separator, settings)
+	default:
+		return nil, fmt.Errorf("unsupported configuration file format %s", rule.format)
+	}
+	envs := map[string]string{}
+	for key, value := range settings {
+		envs[rule.toEnvName(key)] = value
+	}
+	return envs, nil
+}
+
+// flattenConfig flattens the nested maps and lists of the configuration into keys joined with the separator
+func flattenConfig(prefix string, value interface{}, separator string, settings map[string]string) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + separator + key
+	}
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, v := range value {
+			flattenConfig(join(key), v, separator, settings)
+		}
+	case []interface{}:
+		for i, v := range value {
+			flattenConfig(join(cast.ToString(i)), v, separator, settings)
+		}
+	case nil:
+		if prefix != "" {
+			settings[prefix] = ""
+		}
+	default:
+		settings[prefix] = cast.ToString(value)
+	}
+}
+
+// toSpringEnvName converts a property name to the env var name using the relaxed binding rules of Spring Boot
+func toSpringEnvName(key string) string {
+	name := strings.NewReplacer(".", "_", "-", "", "[", "_", "]", "").Replace(key)
+	return strings.ToUpper(name)
+}
+
+func addEnvIfNotPresent(envs []core.EnvVar, env core.EnvVar) []core.EnvVar {
+	for _, e := range envs {
+		if e.Name == env.Name {
+			return envs
+		}
+	}
+	return append(envs, env)
+}
@@ -0,0 +1,52 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetConfigFileEnvs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fileName string
+		content  string
+		want     map[string]string
+	}{
+		{name: "spring properties", fileName: "application.properties", content: "server.port=8080\nspring.datasource.url=jdbc:h2:mem:db\nmy-app.items[0]=a\n", want: map[string]string{"SERVER_PORT": "8080", "SPRING_DATASOURCE_URL": "jdbc:h2:mem:db", "MYAPP_ITEMS_0": "a"}},
+		{name: "spring yaml", fileName: "application-prod.yml", content: "server:\n  port: 8080\nmanagement:\n  endpoints:\n    - health\n", want: map[string]string{"SERVER_PORT": "8080", "MANAGEMENT_ENDPOINTS_0": "health"}},
+		{name: "dotnet appsettings", fileName: "appsettings.json", content: `{"Logging": {"LogLevel": {"Default": "Information"}}, "AllowedHosts": "*"}`, want: map[string]string{"Logging__LogLevel__Default": "Information", "AllowedHosts": "*"}},
+		{name: "env file", fileName: ".env", content: "# comment\nPORT=3000\nDB_HOST=localhost\n", want: map[string]string{"PORT": "3000", "DB_HOST": "localhost"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			rule := getConfigFileRule(testCase.fileName)
+			if rule == nil {
+				t.Fatalf("expected the file %s to be detected as a configuration file", testCase.fileName)
+			}
+			actual, err := getConfigFileEnvs(testCase.content, *rule)
+			if err != nil {
+				t.Fatalf("failed to get the env vars. Error: %q", err)
+			}
+			if !cmp.Equal(actual, testCase.want) {
+				t.Fatalf("Failed to get the env vars properly. Differences:\n%s", cmp.Diff(testCase.want, actual))
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+)
+
+// conflictPreprocessor detects the services whose names collide after normalization and
+// the service ports exposed on the same ingress host and path, and asks the user to resolve them
+type conflictPreprocessor struct {
+}
+
+func (opt *conflictPreprocessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
+	ir = opt.resolveServiceNameConflicts(ir)
+	ir = opt.resolveIngressPathConflicts(ir)
+	return ir, nil
+}
+
+// resolveServiceNameConflicts renames the services that would end up with the same Kubernetes name
+func (opt *conflictPreprocessor) resolveServiceNameConflicts(ir irtypes.IR) irtypes.IR {
+	serviceNames := []string{}
+	for serviceName := range ir.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	usedNames := map[string]string{}
+	for _, serviceName := range serviceNames {
+		normalizedName := common.MakeStringK8sServiceNameCompliant(serviceName)
+		otherServiceName, ok := usedNames[normalizedName]
+		if !ok {
+			usedNames[normalizedName] = serviceName
+			continue
+		}
+		logrus.Warnf("The services %s and %s have the same name %s after normalization", otherServiceName, serviceName, normalizedName)
+		defaultName := normalizedName
+		for i := 2; usedNames[defaultName] != ""; i++ {
+			defaultName = fmt.Sprintf("%s-%d", normalizedName, i)
+		}
+		quesKey := fmt.Sprintf(common.ConfigServicesNewNameKey, `"`+serviceName+`"`)
+		desc := fmt.Sprintf("The services %s and %s have the same name %s in Kubernetes. Provide a new name for the service %s :", otherServiceName, serviceName, normalizedName, serviceName)
+		newName := qaengine.FetchStringAnswer(quesKey, desc, []string{"The name must be unique"}, defaultName, func(answer interface{}) error {
+			name := common.MakeStringK8sServiceNameCompliant(cast.ToString(answer))
+			if other, ok := usedNames[name]; ok {
+				return fmt.Errorf("the name %s is already used by the service %s", name, other)
+			}
+			return nil
+		})
+		newName = common.MakeStringK8sServiceNameCompliant(newName)
+		if _, ok := usedNames[newName]; ok {
+			logrus.Errorf("The new name %s for the service %s is already used. Keeping the old name.", newName, serviceName)
+			continue
+		}
+		usedNames[newName] = serviceName
+		service := ir.Services[serviceName]
+		delete(ir.Services, serviceName)
+		service.Name = newName
+		ir.Services[newName] = service
+	}
+	return ir
+}
+
+// resolveIngressPathConflicts changes the ingress paths that are used by more than one service port
+func (opt *conflictPreprocessor) resolveIngressPathConflicts(ir irtypes.IR) irtypes.IR {
+	serviceNames := []string{}
+	for serviceName := range ir.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	usedPaths := map[string]string{}
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		for i, pf := range service.ServiceToPodPortForwardings {
+			if pf.ServiceRelPath == "" {
+				continue
+			}
+			owner := fmt.Sprintf("%s's %d port", serviceName, pf.ServicePort.Number)
+			path := normalizeIngressPath(pf.ServiceRelPath)
+			other, ok := usedPaths[path]
+			if !ok {
+				usedPaths[path] = owner
+				continue
+			}
+			logrus.Warnf("The service %s and the service %s are exposed on the same ingress path %s", other, owner, pf.ServiceRelPath)
+			defaultPath := fmt.Sprintf("/%s-%d", serviceName, pf.ServicePort.Number)
+			quesKey := fmt.Sprintf(common.ConfigServicesNewURLPathKey, `"`+serviceName+`"`, `"`+cast.ToString(pf.ServicePort.Number)+`"`)
+			desc := fmt.Sprintf("The service %s and the service %s are exposed on the same ingress path %s . Provide a new ingress path for the service %s :", other, owner, pf.ServiceRelPath, owner)
+			newPath := strings.TrimSpace(qaengine.FetchStringAnswer(quesKey, desc, []string{"Leave out leading / to use first part as subdomain"}, defaultPath, func(answer interface{}) error {
+				if other, ok := usedPaths[normalizeIngressPath(strings.TrimSpace(cast.ToString(answer)))]; ok {
+					return fmt.Errorf("the ingress path is already used by the service %s", other)
+				}
+				return nil
+			}))
+			if _, ok := usedPaths[normalizeIngressPath(newPath)]; ok || newPath == "" {
+				logrus.Errorf("The new ingress path %s for the service %s is already used. Keeping the old path.", newPath, owner)
+				continue
+			}
+			usedPaths[normalizeIngressPath(newPath)] = owner
+			service.ServiceToPodPortForwardings[i].ServiceRelPath = newPath
+		}
+		ir.Services[serviceName] = service
+	}
+	return ir
+}
+
+// normalizeIngressPath returns the host prefix and path the ingress path is exposed on in a comparable form
+func normalizeIngressPath(relPath string) string {
+	if relPath != "/" {
+		relPath = strings.TrimSuffix(relPath, "/")
+	}
+	if strings.HasPrefix(relPath, "/") {
+		return relPath
+	}
+	parts := strings.SplitN(relPath, "/", 2)
+	if len(parts) == 1 {
+		return parts[0] + " /"
+	}
+	return parts[0] + " /" + parts[1]
+}
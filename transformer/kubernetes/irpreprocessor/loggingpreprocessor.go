@@ -0,0 +1,284 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	noLogging        = "none"
+	daemonSetLogging = "daemonset"
+	sidecarLogging   = "sidecar"
+
+	jsonLogFormat          = "json"
+	javaMultilineLogFormat = "java"
+
+	fluentBitContainerName     = "fluent-bit"
+	fluentBitImage             = "fluent/fluent-bit:2.1.10"
+	fluentBitConfigFile        = "fluent-bit.conf"
+	fluentBitConfigMountPath   = "/fluent-bit/etc/" + fluentBitConfigFile
+	fluentBitConfigVolumeName  = "fluent-bit-config"
+	fluentBitParserAnnotation  = "fluentbit.io/parser"
+	defaultLogForwardAddress   = "fluentd.logging.svc.cluster.local:24224"
+	logVolumeNamePrefix        = "app-logs"
+	fluentBitConfigMapSuffix   = "-fluent-bit"
+	defaultForwardPort         = "24224"
+	logFileNamePatternWildcard = "*"
+)
+
+// logFileRule finds the files or directories the application writes its logs to
+type logFileRule struct {
+	files []string
+	// pattern has the path of the log file or directory as its first group
+	pattern *regexp.Regexp
+	isDir   bool
+}
+
+var (
+	javaLoggingFiles = []string{"logback*.xml", "log4j2*.xml", "log4j*.properties", "application*.properties", "application*.yml", "application*.yaml"}
+	logFileRules     = []logFileRule{
+		{files: []string{"logback*.xml"}, pattern: regexp.MustCompile(`<file>\s*([^<\s]+)\s*</file>`)},
+		{files: []string{"logback*.xml"}, pattern: regexp.MustCompile(`<fileNamePattern>\s*([^<\s]+)\s*</fileNamePattern>`)},
+		{files: []string{"log4j2*.xml"}, pattern: regexp.MustCompile(`\bfileName\s*=\s*"([^"]+)"`)},
+		{files: []string{"log4j*.properties"}, pattern: regexp.MustCompile(`(?m)^\s*log4j\.appender\.[\w.]+\.File\s*=\s*(\S+)`)},
+		{files: []string{"application*.properties", "application*.yml", "application*.yaml"}, pattern: regexp.MustCompile(`(?m)^\s*logging\.file\.name\s*[=:]\s*(\S+)`)},
+		{files: []string{"application*.properties", "application*.yml", "application*.yaml"}, pattern: regexp.MustCompile(`(?m)^\s*logging\.file\.path\s*[=:]\s*(\S+)`), isDir: true},
+	}
+	// jsonLogFormatRules detect the libraries and layouts that write the logs as json
+	jsonLogFormatRules = []metricsEndpointRule{
+		{files: javaLoggingFiles, patterns: []*regexp.Regexp{regexp.MustCompile(`LogstashEncoder|JsonEncoder|JsonLayout|JsonTemplateLayout|EcsLayout`)}},
+		{files: []string{"package.json"}, patterns: []*regexp.Regexp{regexp.MustCompile(`"(pino|bunyan)"`)}},
+		{files: []string{"requirements*.txt", "pyproject.toml", "Pipfile"}, patterns: []*regexp.Regexp{regexp.MustCompile(`(?i)python[-_]json[-_]logger|structlog`)}},
+		{files: []string{"go.mod"}, patterns: []*regexp.Regexp{regexp.MustCompile(`go\.uber\.org/zap`)}},
+	}
+)
+
+// loggingConfig is how a service writes its logs
+type loggingConfig struct {
+	// logDirs are the directories of the log files written by the service
+	logDirs []string
+	// format is the parser used for the log lines
+	format string
+}
+
+// loggingPreprocessor configures the collection of the logs by fluent-bit.
+// The pods are annotated with the log parser for the fluent-bit DaemonSet, and the services that write their logs
+// to files get a volume for the log directory along with a fluent-bit sidecar that tails the files.
+type loggingPreprocessor struct {
+}
+
+func (p loggingPreprocessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
+	configs := map[string]loggingConfig{}
+	for serviceName, service := range ir.Services {
+		if len(service.Containers) == 0 {
+			continue
+		}
+		config := loggingConfig{}
+		for _, contextPath := range getBuildContextPaths(ir, service) {
+			contextConfig := detectLogging(contextPath, serviceName)
+			for _, logDir := range contextConfig.logDirs {
+				if !common.IsPresent(config.logDirs, logDir) {
+					config.logDirs = append(config.logDirs, logDir)
+				}
+			}
+			if config.format == "" || contextConfig.format == jsonLogFormat {
+				config.format = contextConfig.format
+			}
+		}
+		if len(config.logDirs) == 0 && config.format == "" {
+			continue
+		}
+		configs[serviceName] = config
+	}
+	if len(configs) == 0 {
+		return ir, nil
+	}
+	mode := qaengine.FetchSelectAnswer(
+		common.ConfigTargetLoggingKey,
+		"Select how the logs of the services should be collected:",
+		[]string{
+			"daemonset annotates the pods for a fluent-bit DaemonSet and streams the log files to stdout using sidecars",
+			"sidecar forwards the log files directly to a log aggregator using sidecars",
+		},
+		noLogging,
+		[]string{noLogging, daemonSetLogging, sidecarLogging},
+		nil,
+	)
+	if mode == noLogging {
+		return ir, nil
+	}
+	forwardAddress := ""
+	if mode == sidecarLogging {
+		forwardAddress = qaengine.FetchStringAnswer(common.ConfigTargetLoggingForwardAddressKey, "Enter the address of the fluentd or fluent-bit aggregator the logs should be forwarded to:", []string{"Ex : " + defaultLogForwardAddress}, defaultLogForwardAddress, nil)
+	}
+	serviceNames := []string{}
+	for serviceName := range configs {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		ir = addLogging(ir, serviceName, configs[serviceName], forwardAddress)
+	}
+	return ir, nil
+}
+
+// detectLogging finds the log files and the log format of the application in the build context
+func detectLogging(contextPath, serviceName string) loggingConfig {
+	config := loggingConfig{}
+	contents := readBuildContextFiles(contextPath, func(name string) bool {
+		return matchesAnyFilePattern(name, javaLoggingFiles) || matchesAnyFilePattern(name, javaBuildFiles) || isJSONLogFormatRuleFile(name)
+	})
+	paths := []string{}
+	for path := range contents {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		name := filepath.Base(path)
+		if matchesAnyFilePattern(name, javaLoggingFiles) || matchesAnyFilePattern(name, javaBuildFiles) {
+			config.format = javaMultilineLogFormat
+		}
+		for _, rule := range logFileRules {
+			if !matchesAnyFilePattern(name, rule.files) {
+				continue
+			}
+			for _, match := range rule.pattern.FindAllStringSubmatch(contents[path], -1) {
+				logDir := match[1]
+				if !rule.isDir {
+					logDir = filepath.Dir(logDir)
+				}
+				if strings.Contains(logDir, "${") || !filepath.IsAbs(logDir) {
+					logrus.Warnf("the log file %s of the service %s can't be resolved to an absolute path. The log volume has to be added manually.", match[1], serviceName)
+					common.AddFidelityItem(path, serviceName, "log file "+match[1], common.FidelityManualAttention, "mount a volume for the log directory to collect the log files")
+					continue
+				}
+				logDir = filepath.Clean(logDir)
+				if !common.IsPresent(config.logDirs, logDir) {
+					config.logDirs = append(config.logDirs, logDir)
+				}
+			}
+		}
+	}
+	for _, rule := range jsonLogFormatRules {
+		for _, path := range paths {
+			if matchesAnyFilePattern(filepath.Base(path), rule.files) && matchesAllPatterns(contents[path], rule.patterns) {
+				config.format = jsonLogFormat
+			}
+		}
+	}
+	return config
+}
+
+func isJSONLogFormatRuleFile(name string) bool {
+	for _, rule := range jsonLogFormatRules {
+		if matchesAnyFilePattern(name, rule.files) {
+			return true
+		}
+	}
+	return false
+}
+
+// addLogging annotates the pods with the log parser and adds the log volumes and the fluent-bit sidecar
+func addLogging(ir irtypes.IR, serviceName string, config loggingConfig, forwardAddress string) irtypes.IR {
+	service := ir.Services[serviceName]
+	if config.format == jsonLogFormat {
+		if service.Annotations == nil {
+			service.Annotations = map[string]string{}
+		}
+		service.Annotations[fluentBitParserAnnotation] = jsonLogFormat
+	}
+	if len(config.logDirs) == 0 {
+		ir.Services[serviceName] = service
+		return ir
+	}
+	sidecar := core.Container{
+		Name:  fluentBitContainerName,
+		Image: fluentBitImage,
+	}
+	for i, logDir := range config.logDirs {
+		volumeName := fmt.Sprintf("%s-%d", logVolumeNamePrefix, i)
+		service.Volumes = append(service.Volumes, core.Volume{Name: volumeName, VolumeSource: core.VolumeSource{EmptyDir: &core.EmptyDirVolumeSource{}}})
+		for j := range service.Containers {
+			service.Containers[j].VolumeMounts = append(service.Containers[j].VolumeMounts, core.VolumeMount{Name: volumeName, MountPath: logDir})
+		}
+		sidecar.VolumeMounts = append(sidecar.VolumeMounts, core.VolumeMount{Name: volumeName, MountPath: logDir, ReadOnly: true})
+	}
+	configMapName := serviceName + fluentBitConfigMapSuffix
+	ir.Storages = append(ir.Storages, irtypes.Storage{
+		Name:        configMapName,
+		StorageType: irtypes.ConfigMapKind,
+		Content:     map[string][]byte{fluentBitConfigFile: []byte(getFluentBitConfig(serviceName, config, forwardAddress))},
+	})
+	service.Volumes = append(service.Volumes, core.Volume{
+		Name:         fluentBitConfigVolumeName,
+		VolumeSource: core.VolumeSource{ConfigMap: &core.ConfigMapVolumeSource{LocalObjectReference: core.LocalObjectReference{Name: configMapName}}},
+	})
+	sidecar.VolumeMounts = append(sidecar.VolumeMounts, core.VolumeMount{Name: fluentBitConfigVolumeName, MountPath: fluentBitConfigMountPath, SubPath: fluentBitConfigFile})
+	service.Containers = append(service.Containers, sidecar)
+	ir.Services[serviceName] = service
+	return ir
+}
+
+// getFluentBitConfig returns the configuration of the sidecar that tails the log files.
+// Without a forward address the logs are written to stdout to be collected by the fluent-bit DaemonSet.
+func getFluentBitConfig(serviceName string, config loggingConfig, forwardAddress string) string {
+	paths := []string{}
+	for _, logDir := range config.logDirs {
+		paths = append(paths, filepath.Join(logDir, logFileNamePatternWildcard))
+	}
+	lines := []string{
+		"[SERVICE]",
+		"    Flush         5",
+		"    Log_Level     info",
+		"    Parsers_File  parsers.conf",
+		"",
+		"[INPUT]",
+		"    Name            tail",
+		"    Path            " + strings.Join(paths, ","),
+		"    Tag             " + serviceName,
+		"    Read_from_Head  true",
+	}
+	switch config.format {
+	case jsonLogFormat:
+		lines = append(lines, "    Parser          "+jsonLogFormat)
+	case javaMultilineLogFormat:
+		lines = append(lines, "    multiline.parser "+javaMultilineLogFormat)
+	}
+	lines = append(lines, "", "[OUTPUT]")
+	if forwardAddress == "" {
+		lines = append(lines, "    Name    stdout", "    Match   *", "    Format  json_lines")
+	} else {
+		host, port, err := net.SplitHostPort(forwardAddress)
+		if err != nil {
+			host, port = forwardAddress, defaultForwardPort
+		}
+		lines = append(lines, "    Name    forward", "    Match   *", "    Host    "+host, "    Port    "+port)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
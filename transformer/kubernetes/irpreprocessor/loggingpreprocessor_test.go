@@ -0,0 +1,58 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDetectLogging(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files map[string]string
+		want  loggingConfig
+	}{
+		{name: "no logging configuration", files: map[string]string{"main.go": "package main\n"}, want: loggingConfig{}},
+		{name: "logback file appender", files: map[string]string{"src/main/resources/logback.xml": "<appender class=\"ch.qos.logback.core.FileAppender\">\n<file>/var/log/app/app.log</file>\n</appender>\n"}, want: loggingConfig{logDirs: []string{"/var/log/app"}, format: javaMultilineLogFormat}},
+		{name: "log4j2 json layout", files: map[string]string{"log4j2.xml": "<File name=\"f\" fileName=\"/opt/logs/app.log\"><JsonTemplateLayout/></File>\n"}, want: loggingConfig{logDirs: []string{"/opt/logs"}, format: jsonLogFormat}},
+		{name: "spring log path", files: map[string]string{"application.properties": "logging.file.path=/logs\n"}, want: loggingConfig{logDirs: []string{"/logs"}, format: javaMultilineLogFormat}},
+		{name: "unresolved log file", files: map[string]string{"logback.xml": "<file>${LOG_DIR}/app.log</file>\n"}, want: loggingConfig{format: javaMultilineLogFormat}},
+		{name: "node pino", files: map[string]string{"package.json": `{"dependencies": {"pino": "^8.0.0"}}`}, want: loggingConfig{format: jsonLogFormat}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			contextPath := t.TempDir()
+			for path, content := range testCase.files {
+				fullPath := filepath.Join(contextPath, path)
+				if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+					t.Fatalf("failed to create the directory for %s . Error: %q", path, err)
+				}
+				if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+					t.Fatalf("failed to write the file %s . Error: %q", path, err)
+				}
+			}
+			actual := detectLogging(contextPath, "svc1")
+			if !cmp.Equal(actual, testCase.want, cmp.AllowUnexported(loggingConfig{})) {
+				t.Fatalf("the detected logging configuration differs from the expected. Differences:\n%s", cmp.Diff(testCase.want, actual, cmp.AllowUnexported(loggingConfig{})))
+			}
+		})
+	}
+}
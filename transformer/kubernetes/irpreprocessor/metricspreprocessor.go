@@ -0,0 +1,125 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"path/filepath"
+	"regexp"
+
+	"github.com/konveyor/move2kube/common"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/konveyor/move2kube/types/qaengine/commonqa"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+)
+
+// metricsEndpointRule detects a metrics endpoint using the dependencies declared in the build files
+type metricsEndpointRule struct {
+	files []string
+	// patterns must all be found in the same file
+	patterns []*regexp.Regexp
+	path     string
+}
+
+var (
+	javaBuildFiles       = []string{"pom.xml", "build.gradle", "build.gradle.kts"}
+	metricsEndpointRules = []metricsEndpointRule{
+		{files: javaBuildFiles, patterns: []*regexp.Regexp{regexp.MustCompile(`spring-boot-starter-actuator`), regexp.MustCompile(`micrometer-registry-prometheus`)}, path: "/actuator/prometheus"},
+		{files: javaBuildFiles, patterns: []*regexp.Regexp{regexp.MustCompile(`quarkus-micrometer-registry-prometheus|quarkus-smallrye-metrics`)}, path: "/q/metrics"},
+		{files: javaBuildFiles, patterns: []*regexp.Regexp{regexp.MustCompile(`micrometer-registry-prometheus|io\.prometheus`)}, path: "/metrics"},
+		{files: []string{"package.json"}, patterns: []*regexp.Regexp{regexp.MustCompile(`"(prom-client|express-prom-bundle)"`)}, path: "/metrics"},
+		{files: []string{"requirements*.txt", "pyproject.toml", "Pipfile", "setup.py"}, patterns: []*regexp.Regexp{regexp.MustCompile(`(?i)prometheus[-_](client|flask[-_]exporter|fastapi[-_]instrumentator)|django[-_]prometheus`)}, path: "/metrics"},
+		{files: []string{"go.mod"}, patterns: []*regexp.Regexp{regexp.MustCompile(`github\.com/prometheus/client_golang`)}, path: "/metrics"},
+		{files: []string{"*.csproj"}, patterns: []*regexp.Regexp{regexp.MustCompile(`(?i)prometheus-net`)}, path: "/metrics"},
+		{files: []string{"Gemfile"}, patterns: []*regexp.Regexp{regexp.MustCompile(`prometheus-client|yabeda-prometheus`)}, path: "/metrics"},
+	}
+)
+
+// metricsPreprocessor adds the Prometheus scrape annotations to the services that expose a metrics endpoint
+type metricsPreprocessor struct {
+}
+
+func (p metricsPreprocessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
+	annotations := map[string]map[string]string{}
+	for serviceName, service := range ir.Services {
+		if _, ok := service.Annotations[common.PrometheusScrapeAnnotation]; ok || len(service.Containers) == 0 {
+			continue
+		}
+		path := ""
+		for _, contextPath := range getBuildContextPaths(ir, service) {
+			if path = detectMetricsPath(contextPath); path != "" {
+				break
+			}
+		}
+		if path == "" {
+			continue
+		}
+		port := int32(0)
+		for _, container := range service.Containers {
+			if len(container.Ports) > 0 {
+				port = container.Ports[0].ContainerPort
+				break
+			}
+		}
+		if port == 0 {
+			logrus.Warnf("the metrics endpoint %s of the service %s was detected, but the service doesn't have any ports", path, serviceName)
+			continue
+		}
+		logrus.Infof("Detected the metrics endpoint %s on the port %d of the service %s", path, port, serviceName)
+		annotations[serviceName] = map[string]string{
+			common.PrometheusScrapeAnnotation: common.AnnotationLabelValue,
+			common.PrometheusPathAnnotation:   path,
+			common.PrometheusPortAnnotation:   cast.ToString(port),
+		}
+	}
+	if len(annotations) == 0 || commonqa.MonitoringStack() == commonqa.NoMonitoringStack {
+		return ir, nil
+	}
+	for serviceName, serviceAnnotations := range annotations {
+		service := ir.Services[serviceName]
+		if service.Annotations == nil {
+			service.Annotations = map[string]string{}
+		}
+		for k, v := range serviceAnnotations {
+			service.Annotations[k] = v
+		}
+		ir.Services[serviceName] = service
+	}
+	return ir, nil
+}
+
+// detectMetricsPath returns the path of the metrics endpoint if the dependency files in the build context use a Prometheus client library
+func detectMetricsPath(contextPath string) string {
+	contents := readBuildContextFiles(contextPath, isMetricsRuleFile)
+	for _, rule := range metricsEndpointRules {
+		for path, content := range contents {
+			if matchesAnyFilePattern(filepath.Base(path), rule.files) && matchesAllPatterns(content, rule.patterns) {
+				return rule.path
+			}
+		}
+	}
+	return ""
+}
+
+func isMetricsRuleFile(name string) bool {
+	for _, rule := range metricsEndpointRules {
+		if matchesAnyFilePattern(name, rule.files) {
+			return true
+		}
+	}
+	return false
+}
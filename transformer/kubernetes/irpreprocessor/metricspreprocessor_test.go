@@ -0,0 +1,55 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectMetricsPath(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{name: "no dependency files", files: map[string]string{"main.go": "package main\n"}, want: ""},
+		{name: "spring boot with actuator and prometheus registry", files: map[string]string{"pom.xml": "<artifactId>spring-boot-starter-actuator</artifactId>\n<artifactId>micrometer-registry-prometheus</artifactId>\n"}, want: "/actuator/prometheus"},
+		{name: "spring boot with only actuator", files: map[string]string{"pom.xml": "<artifactId>spring-boot-starter-actuator</artifactId>\n"}, want: ""},
+		{name: "node with prom-client in a sub directory", files: map[string]string{"server/package.json": `{"dependencies": {"prom-client": "^14.0.0"}}`}, want: "/metrics"},
+		{name: "go client in a vendored module is ignored", files: map[string]string{"vendor/x/go.mod": "require github.com/prometheus/client_golang v1.12.0\n"}, want: ""},
+		{name: "python requirements", files: map[string]string{"requirements-prod.txt": "flask\nprometheus_flask_exporter==0.20.0\n"}, want: "/metrics"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			contextPath := t.TempDir()
+			for path, content := range testCase.files {
+				fullPath := filepath.Join(contextPath, path)
+				if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+					t.Fatalf("failed to create the directory for %s . Error: %q", path, err)
+				}
+				if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+					t.Fatalf("failed to write the file %s . Error: %q", path, err)
+				}
+			}
+			if actual := detectMetricsPath(contextPath); actual != testCase.want {
+				t.Fatalf("expected the metrics path %q . Actual: %q", testCase.want, actual)
+			}
+		})
+	}
+}
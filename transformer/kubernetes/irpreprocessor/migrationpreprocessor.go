@@ -0,0 +1,207 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	jobMigration           = "job"
+	initContainerMigration = "initcontainer"
+	noMigration            = "none"
+
+	migrationContainerName = "migrate"
+	migrationJobSuffix     = "-migrate"
+)
+
+// migrationRule detects a migration tool and the command that applies the migrations
+type migrationRule struct {
+	tool  string
+	files []string
+	// patterns must all be found in the same file
+	patterns []*regexp.Regexp
+	// image runs the migrations. The image of the service is used when it is empty.
+	image   string
+	command []string
+}
+
+var (
+	// migrationHookAnnotations run the migration Job before the rest of the resources are deployed by Helm and Argo CD
+	migrationHookAnnotations = map[string]string{
+		"helm.sh/hook":                          "pre-install,pre-upgrade",
+		"helm.sh/hook-weight":                   "-5",
+		"helm.sh/hook-delete-policy":            "before-hook-creation",
+		"argocd.argoproj.io/hook":               "PreSync",
+		"argocd.argoproj.io/hook-delete-policy": "BeforeHookCreation",
+		"argocd.argoproj.io/sync-wave":          "-1",
+	}
+	// startupMigrationPatterns detect the frameworks that apply the migrations when the application starts
+	startupMigrationPatterns = []*regexp.Regexp{regexp.MustCompile(`spring-boot`), regexp.MustCompile(`flyway-core|liquibase-core`)}
+	migrationRules           = []migrationRule{
+		{tool: "django", files: []string{"manage.py"}, patterns: []*regexp.Regexp{regexp.MustCompile(`django`)}, command: []string{"python", "manage.py", "migrate", "--noinput"}},
+		{tool: "alembic", files: []string{"alembic.ini"}, command: []string{"alembic", "upgrade", "head"}},
+		{tool: "rails", files: []string{"Gemfile"}, patterns: []*regexp.Regexp{regexp.MustCompile(`gem\s+['"]rails['"]`)}, command: []string{"bundle", "exec", "rails", "db:migrate"}},
+		{tool: "knex", files: []string{"knexfile.js", "knexfile.ts"}, command: []string{"npx", "knex", "migrate:latest"}},
+		{tool: "prisma", files: []string{"schema.prisma"}, command: []string{"npx", "prisma", "migrate", "deploy"}},
+		{tool: "flyway", files: []string{"flyway.conf", "flyway.toml"}, image: "flyway/flyway", command: []string{"flyway", "migrate"}},
+		{tool: "liquibase", files: []string{"liquibase.properties"}, image: "liquibase/liquibase", command: []string{"liquibase", "update"}},
+	}
+)
+
+// migrationPreprocessor runs the database migrations of the services in an init container or a Job that runs before the deployment
+type migrationPreprocessor struct {
+}
+
+func (p migrationPreprocessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
+	serviceNames := []string{}
+	for serviceName := range ir.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		if len(service.Containers) == 0 {
+			continue
+		}
+		var rule *migrationRule
+		for _, contextPath := range getBuildContextPaths(ir, service) {
+			if rule = detectMigrationTool(contextPath); rule != nil {
+				break
+			}
+		}
+		if rule == nil {
+			continue
+		}
+		mode := qaengine.FetchSelectAnswer(
+			fmt.Sprintf(common.ConfigServicesMigrationKey, `"`+serviceName+`"`),
+			fmt.Sprintf("Select how the %s database migrations of the service %s should be run:", rule.tool, serviceName),
+			[]string{
+				"job runs the migrations once in a Job that is a Helm pre-install/pre-upgrade hook and an Argo CD PreSync hook",
+				"initcontainer runs the migrations in an init container before every pod of the service starts",
+			},
+			jobMigration,
+			[]string{jobMigration, initContainerMigration, noMigration},
+			nil,
+		)
+		if mode == noMigration {
+			continue
+		}
+		if rule.image != "" {
+			common.AddFidelityItem(serviceName, serviceName, "database migrations", common.FidelityManualAttention, fmt.Sprintf("the migration scripts and the database connection have to be made available to the %s image", rule.image))
+		}
+		container := getMigrationContainer(service, *rule)
+		if mode == initContainerMigration {
+			service.InitContainers = append(service.InitContainers, container)
+			ir.Services[serviceName] = service
+			continue
+		}
+		jobName := common.NormalizeForMetadataName(serviceName + migrationJobSuffix)
+		if _, ok := ir.Services[jobName]; ok {
+			logrus.Warnf("a service named %s already exists. Skipping the migration Job of the service %s", jobName, serviceName)
+			continue
+		}
+		ir.Services[jobName] = getMigrationJob(jobName, service, container)
+	}
+	return ir, nil
+}
+
+// detectMigrationTool returns the rule of the migration tool used by the application in the build context
+func detectMigrationTool(contextPath string) *migrationRule {
+	contents := readBuildContextFiles(contextPath, func(name string) bool {
+		if matchesAnyFilePattern(name, javaBuildFiles) {
+			return true
+		}
+		for _, rule := range migrationRules {
+			if matchesAnyFilePattern(name, rule.files) {
+				return true
+			}
+		}
+		return false
+	})
+	paths := []string{}
+	for path := range contents {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if matchesAnyFilePattern(filepath.Base(path), javaBuildFiles) && matchesAllPatterns(contents[path], startupMigrationPatterns) {
+			logrus.Debugf("the migrations of the application in %s are applied by Spring Boot when it starts", contextPath)
+			return nil
+		}
+	}
+	for i, rule := range migrationRules {
+		for _, path := range paths {
+			if matchesAnyFilePattern(filepath.Base(path), rule.files) && matchesAllPatterns(contents[path], rule.patterns) {
+				return &migrationRules[i]
+			}
+		}
+	}
+	return nil
+}
+
+// getMigrationContainer returns a container that runs the migrations with the same configuration as the application
+func getMigrationContainer(service irtypes.Service, rule migrationRule) core.Container {
+	app := service.Containers[0]
+	container := core.Container{
+		Name:         migrationContainerName,
+		Image:        app.Image,
+		Command:      rule.command,
+		Env:          append([]core.EnvVar{}, app.Env...),
+		EnvFrom:      append([]core.EnvFromSource{}, app.EnvFrom...),
+		VolumeMounts: append([]core.VolumeMount{}, app.VolumeMounts...),
+	}
+	if rule.image != "" {
+		container.Image = rule.image
+	}
+	return container
+}
+
+// getMigrationJob returns a service that runs to completion with the migration container.
+// Only the ConfigMap and Secret volumes are mounted since the claims of the application may not be shareable.
+func getMigrationJob(jobName string, service irtypes.Service, container core.Container) irtypes.Service {
+	job := irtypes.NewServiceWithName(jobName)
+	job.Annotations = common.MergeStringMaps(map[string]string{}, migrationHookAnnotations)
+	volumeNames := []string{}
+	for _, volume := range service.Volumes {
+		if volume.ConfigMap != nil || volume.Secret != nil {
+			job.Volumes = append(job.Volumes, volume)
+			volumeNames = append(volumeNames, volume.Name)
+		}
+	}
+	volumeMounts := []core.VolumeMount{}
+	for _, volumeMount := range container.VolumeMounts {
+		if common.IsPresent(volumeNames, volumeMount.Name) {
+			volumeMounts = append(volumeMounts, volumeMount)
+		}
+	}
+	container.VolumeMounts = volumeMounts
+	job.Containers = []core.Container{container}
+	job.ImagePullSecrets = service.ImagePullSecrets
+	job.ServiceAccountName = service.ServiceAccountName
+	job.RestartPolicy = core.RestartPolicyOnFailure
+	return job
+}
@@ -0,0 +1,60 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectMigrationTool(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{name: "no migration tool", files: map[string]string{"main.go": "package main\n"}, want: ""},
+		{name: "django", files: map[string]string{"manage.py": "os.environ.setdefault('DJANGO_SETTINGS_MODULE', 'app.settings')\nfrom django.core.management import execute_from_command_line\n"}, want: "django"},
+		{name: "rails", files: map[string]string{"Gemfile": "source 'https://rubygems.org'\ngem 'rails', '~> 7.0'\n"}, want: "rails"},
+		{name: "gemfile without rails", files: map[string]string{"Gemfile": "gem 'sinatra'\n"}, want: ""},
+		{name: "knex in a sub directory", files: map[string]string{"db/knexfile.js": "module.exports = {}\n"}, want: "knex"},
+		{name: "standalone flyway", files: map[string]string{"flyway.conf": "flyway.url=jdbc:postgresql://db/app\n"}, want: "flyway"},
+		{name: "spring boot runs flyway on startup", files: map[string]string{"pom.xml": "<artifactId>spring-boot-starter-web</artifactId>\n<artifactId>flyway-core</artifactId>\n", "flyway.conf": "flyway.url=jdbc:postgresql://db/app\n"}, want: ""},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			contextPath := t.TempDir()
+			for path, content := range testCase.files {
+				fullPath := filepath.Join(contextPath, path)
+				if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+					t.Fatalf("failed to create the directory for %s . Error: %q", path, err)
+				}
+				if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+					t.Fatalf("failed to write the file %s . Error: %q", path, err)
+				}
+			}
+			actual := ""
+			if rule := detectMigrationTool(contextPath); rule != nil {
+				actual = rule.tool
+			}
+			if actual != testCase.want {
+				t.Fatalf("expected the migration tool %q . Actual: %q", testCase.want, actual)
+			}
+		})
+	}
+}
@@ -80,6 +80,7 @@ func (p registryPreProcessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
 
 	// ask the user for the registry url where new images should be pushed
 
+	qaengine.StartGroup("Image registry")
 	registryToPushImagesTo := commonqa.ImageRegistry()
 	usedRegistries = common.AppendIfNotPresent(usedRegistries, registryToPushImagesTo)
 
@@ -117,6 +118,7 @@ func (p registryPreProcessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
 	imagePullSecrets := map[string]string{} // registry url -> pull secret name
 	registryNamespace := commonqa.ImageRegistryNamespace()
 	for _, registry := range usedRegistries {
+		qaengine.StartGroup(registry)
 		if _, ok := imagePullSecrets[registry]; !ok {
 			imagePullSecrets[registry] = common.NormalizeForMetadataName(strings.ReplaceAll(registry, ".", "-") + imagePullSecretSuffix)
 		}
@@ -166,12 +168,15 @@ func (p registryPreProcessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
 				})
 			}
 		}
+		qaengine.EndGroup()
 	}
+	qaengine.EndGroup()
 
 	for serviceName, service := range ir.Services {
 		for i, container := range service.Containers {
 			if common.IsPresent(newImageNames, container.Image) {
 				image, tag := common.GetImageNameAndTag(container.Image)
+				tag = commonqa.ImageTag(ir.ContainerImages[container.Image].Build.ContextPath, tag)
 				if registryToPushImagesTo != "" && registryNamespace != "" {
 					container.Image = registryToPushImagesTo + "/" + registryNamespace + "/" + image + ":" + tag
 				} else if registryNamespace != "" {
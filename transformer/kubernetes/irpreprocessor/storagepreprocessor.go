@@ -0,0 +1,297 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"k8s.io/apimachinery/pkg/api/resource"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	pvcVolumeType       = "pvc"
+	emptyDirVolumeType  = "emptydir"
+	configMapVolumeType = "configmap"
+	hostPathVolumeType  = "hostpath"
+
+	defaultStorageSize = "1Gi"
+	// maxConfigMapDataSize is the maximum size of the files loaded into a ConfigMap created from a directory
+	maxConfigMapDataSize = 1024 * 1024
+)
+
+var configMapKeyRegex = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// volumeInfo is a volume along with the services and the paths it is mounted at
+type volumeInfo struct {
+	name       string
+	hostPath   string
+	isClaim    bool
+	mountPaths []string
+	services   []string
+}
+
+// storagePreprocessor asks how each detected volume should be stored and converts the volumes and the claims accordingly
+type storagePreprocessor struct {
+}
+
+func (p storagePreprocessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
+	for _, volume := range getVolumeInfos(ir) {
+		ir = p.configureVolume(ir, volume)
+	}
+	return ir, nil
+}
+
+func (p storagePreprocessor) configureVolume(ir irtypes.IR, volume volumeInfo) irtypes.IR {
+	context := []string{fmt.Sprintf("The volume is mounted by the services %s at the paths %s", cast.ToString(volume.services), cast.ToString(volume.mountPaths))}
+	options := []string{pvcVolumeType, emptyDirVolumeType, configMapVolumeType}
+	def := pvcVolumeType
+	if !volume.isClaim {
+		options = append(options, hostPathVolumeType)
+		def = hostPathVolumeType
+	}
+	volumeType := qaengine.FetchSelectAnswer(
+		fmt.Sprintf(common.ConfigStoragesTypeKey, `"`+volume.name+`"`),
+		fmt.Sprintf("Select the type of volume to use for the volume %s:", volume.name),
+		append(context, "pvc stores the data in a persistent volume, emptydir keeps the data only for the lifetime of the pod, configmap mounts the files of the directory read only"),
+		def,
+		options,
+		nil,
+	)
+	switch volumeType {
+	case pvcVolumeType:
+		return p.convertToClaim(ir, volume, context)
+	case emptyDirVolumeType:
+		ir = removeStorage(ir, volume)
+		return replaceVolumeSource(ir, volume, core.VolumeSource{EmptyDir: &core.EmptyDirVolumeSource{}})
+	case configMapVolumeType:
+		ir = removeStorage(ir, volume)
+		configMapName := common.NormalizeForMetadataName(volume.name)
+		ir.AddStorage(irtypes.Storage{Name: configMapName, StorageType: irtypes.ConfigMapKind, Content: readConfigMapContent(volume.hostPath)})
+		ir = replaceVolumeSource(ir, volume, core.VolumeSource{ConfigMap: &core.ConfigMapVolumeSource{LocalObjectReference: core.LocalObjectReference{Name: configMapName}}})
+		return setVolumeMountsReadOnly(ir, volume)
+	}
+	return ir
+}
+
+// convertToClaim asks the size and the access mode of the claim and mounts the claim in place of the volume
+func (p storagePreprocessor) convertToClaim(ir irtypes.IR, volume volumeInfo, context []string) irtypes.IR {
+	storage := irtypes.Storage{Name: volume.name, StorageType: irtypes.PVCKind}
+	storageIdx := -1
+	for i, st := range ir.Storages {
+		if st.Name == volume.name && st.StorageType == irtypes.PVCKind {
+			storage, storageIdx = st, i
+			break
+		}
+	}
+	defaultSize := defaultStorageSize
+	if quantity, ok := storage.Resources.Requests[core.ResourceStorage]; ok {
+		defaultSize = quantity.String()
+	}
+	size := qaengine.FetchStringAnswer(
+		fmt.Sprintf(common.ConfigStoragesSizeKey, `"`+volume.name+`"`),
+		fmt.Sprintf("Enter the size of the persistent volume claim for the volume %s:", volume.name),
+		append(context, "Ex : 500Mi, 10Gi"),
+		defaultSize,
+		func(ans interface{}) error {
+			if _, err := resource.ParseQuantity(cast.ToString(ans)); err != nil {
+				return fmt.Errorf("the size %v is not a valid quantity . Error: %w", ans, err)
+			}
+			return nil
+		},
+	)
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		logrus.Errorf("failed to parse the size %s of the volume %s . Using the default %s . Error: %q", size, volume.name, defaultStorageSize, err)
+		quantity = resource.MustParse(defaultStorageSize)
+	}
+	defaultAccessMode := string(core.ReadWriteOnce)
+	if len(storage.AccessModes) > 0 {
+		defaultAccessMode = string(storage.AccessModes[0])
+	}
+	accessMode := qaengine.FetchSelectAnswer(
+		fmt.Sprintf(common.ConfigStoragesAccessModeKey, `"`+volume.name+`"`),
+		fmt.Sprintf("Select the access mode of the persistent volume claim for the volume %s:", volume.name),
+		append(context, "ReadWriteMany is required when the pods of the services run on different nodes and write to the volume"),
+		defaultAccessMode,
+		[]string{string(core.ReadWriteOnce), string(core.ReadWriteMany), string(core.ReadOnlyMany)},
+		nil,
+	)
+	storage.AccessModes = []core.PersistentVolumeAccessMode{core.PersistentVolumeAccessMode(accessMode)}
+	storage.Resources.Requests = core.ResourceList{core.ResourceStorage: quantity}
+	if storageIdx == -1 {
+		ir.Storages = append(ir.Storages, storage)
+	} else {
+		ir.Storages[storageIdx] = storage
+	}
+	if volume.isClaim {
+		return ir
+	}
+	return replaceVolumeSource(ir, volume, core.VolumeSource{PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{ClaimName: volume.name}})
+}
+
+// getVolumeInfos returns the persistent volume claim and host path volumes of all the services
+func getVolumeInfos(ir irtypes.IR) []volumeInfo {
+	volumes := map[string]*volumeInfo{}
+	serviceNames := []string{}
+	for serviceName := range ir.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		for _, volume := range service.Volumes {
+			name, hostPath := "", ""
+			if volume.PersistentVolumeClaim != nil {
+				name = volume.PersistentVolumeClaim.ClaimName
+			} else if volume.HostPath != nil {
+				name, hostPath = volume.Name, volume.HostPath.Path
+			} else {
+				continue
+			}
+			info, ok := volumes[name]
+			if !ok {
+				info = &volumeInfo{name: name, hostPath: hostPath, isClaim: volume.PersistentVolumeClaim != nil}
+				volumes[name] = info
+			}
+			if !common.IsPresent(info.services, serviceName) {
+				info.services = append(info.services, serviceName)
+			}
+			for _, container := range service.Containers {
+				for _, volumeMount := range container.VolumeMounts {
+					if volumeMount.Name == volume.Name && !common.IsPresent(info.mountPaths, volumeMount.MountPath) {
+						info.mountPaths = append(info.mountPaths, volumeMount.MountPath)
+					}
+				}
+			}
+		}
+	}
+	names := []string{}
+	for name := range volumes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	infos := []volumeInfo{}
+	for _, name := range names {
+		infos = append(infos, *volumes[name])
+	}
+	return infos
+}
+
+// isVolumeOf returns true if the volume of the service refers to the detected volume
+func isVolumeOf(volume core.Volume, info volumeInfo) bool {
+	if info.isClaim {
+		return volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == info.name
+	}
+	return volume.HostPath != nil && volume.Name == info.name
+}
+
+// replaceVolumeSource changes the source of the volume in all the services that mount it
+func replaceVolumeSource(ir irtypes.IR, info volumeInfo, source core.VolumeSource) irtypes.IR {
+	for _, serviceName := range info.services {
+		service := ir.Services[serviceName]
+		for i, volume := range service.Volumes {
+			if isVolumeOf(volume, info) {
+				service.Volumes[i].VolumeSource = source
+			}
+		}
+		ir.Services[serviceName] = service
+	}
+	return ir
+}
+
+// setVolumeMountsReadOnly marks the mounts of the volume in all the services as read only
+func setVolumeMountsReadOnly(ir irtypes.IR, info volumeInfo) irtypes.IR {
+	for _, serviceName := range info.services {
+		service := ir.Services[serviceName]
+		volumeNames := []string{}
+		for _, volume := range service.Volumes {
+			if volume.ConfigMap != nil && volume.ConfigMap.Name == common.NormalizeForMetadataName(info.name) {
+				volumeNames = append(volumeNames, volume.Name)
+			}
+		}
+		for i := range service.Containers {
+			for j, volumeMount := range service.Containers[i].VolumeMounts {
+				if common.IsPresent(volumeNames, volumeMount.Name) {
+					service.Containers[i].VolumeMounts[j].ReadOnly = true
+				}
+			}
+		}
+		ir.Services[serviceName] = service
+	}
+	return ir
+}
+
+// removeStorage removes the claim of the volume if there is one
+func removeStorage(ir irtypes.IR, info volumeInfo) irtypes.IR {
+	if !info.isClaim {
+		return ir
+	}
+	storages := []irtypes.Storage{}
+	for _, storage := range ir.Storages {
+		if storage.Name == info.name && storage.StorageType == irtypes.PVCKind {
+			continue
+		}
+		storages = append(storages, storage)
+	}
+	ir.Storages = storages
+	return ir
+}
+
+// readConfigMapContent loads the files of the host path directory to be used as the data of a ConfigMap
+func readConfigMapContent(hostPath string) map[string][]byte {
+	content := map[string][]byte{}
+	if hostPath == "" {
+		return content
+	}
+	entries, err := os.ReadDir(hostPath)
+	if err != nil {
+		logrus.Warnf("failed to read the directory %s to create the ConfigMap. The data has to be added manually. Error: %q", hostPath, err)
+		return content
+	}
+	size := 0
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		if !configMapKeyRegex.MatchString(entry.Name()) {
+			logrus.Warnf("ignoring the file %s since its name is not a valid ConfigMap key", entry.Name())
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(hostPath, entry.Name()))
+		if err != nil {
+			logrus.Errorf("failed to read the file %s . Error: %q", entry.Name(), err)
+			continue
+		}
+		if size+len(data) > maxConfigMapDataSize {
+			logrus.Warnf("ignoring the file %s since the ConfigMap for the directory %s would exceed %d bytes", entry.Name(), hostPath, maxConfigMapDataSize)
+			continue
+		}
+		size += len(data)
+		content[entry.Name()] = data
+	}
+	return content
+}
@@ -0,0 +1,75 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestGetVolumeInfos(t *testing.T) {
+	ir := irtypes.NewIR()
+	ir.Services["web"] = irtypes.Service{
+		Name: "web",
+		PodSpec: irtypes.PodSpec{
+			Containers: []core.Container{{Name: "web", VolumeMounts: []core.VolumeMount{{Name: "data", MountPath: "/data"}, {Name: "config", MountPath: "/etc/web"}}}},
+			Volumes: []core.Volume{
+				{Name: "data", VolumeSource: core.VolumeSource{PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+				{Name: "config", VolumeSource: core.VolumeSource{HostPath: &core.HostPathVolumeSource{Path: "/src/config"}}},
+				{Name: "cache", VolumeSource: core.VolumeSource{EmptyDir: &core.EmptyDirVolumeSource{}}},
+			},
+		},
+	}
+	ir.Services["worker"] = irtypes.Service{
+		Name: "worker",
+		PodSpec: irtypes.PodSpec{
+			Containers: []core.Container{{Name: "worker", VolumeMounts: []core.VolumeMount{{Name: "data", MountPath: "/var/data"}}}},
+			Volumes:    []core.Volume{{Name: "data", VolumeSource: core.VolumeSource{PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}}},
+		},
+	}
+	want := []volumeInfo{
+		{name: "config", hostPath: "/src/config", mountPaths: []string{"/etc/web"}, services: []string{"web"}},
+		{name: "data", isClaim: true, mountPaths: []string{"/data", "/var/data"}, services: []string{"web", "worker"}},
+	}
+	actual := getVolumeInfos(ir)
+	if !cmp.Equal(actual, want, cmp.AllowUnexported(volumeInfo{})) {
+		t.Fatalf("Failed to get the volumes properly. Differences:\n%s", cmp.Diff(want, actual, cmp.AllowUnexported(volumeInfo{})))
+	}
+}
+
+func TestReadConfigMapContent(t *testing.T) {
+	hostPath := t.TempDir()
+	files := map[string]string{"app.conf": "port=8080\n", "invalid key.conf": "x\n"}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(hostPath, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write the file %s . Error: %q", name, err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(hostPath, "subdir"), 0755); err != nil {
+		t.Fatalf("failed to create the sub directory. Error: %q", err)
+	}
+	want := map[string][]byte{"app.conf": []byte("port=8080\n")}
+	actual := readConfigMapContent(hostPath)
+	if !cmp.Equal(actual, want) {
+		t.Fatalf("Failed to read the ConfigMap content properly. Differences:\n%s", cmp.Diff(want, actual))
+	}
+}
@@ -0,0 +1,283 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package k8sschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/konveyor/move2kube/common"
+	"github.com/sirupsen/logrus"
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// openAPISpecURLFormat is the url of the OpenAPI spec published with each Kubernetes release
+	openAPISpecURLFormat = "https://raw.githubusercontent.com/kubernetes/kubernetes/%s/api/openapi-spec/swagger.json"
+	// gvkExtension lists the group, version and kind of the resources that a definition describes
+	gvkExtension = "x-kubernetes-group-version-kind"
+	// intOrStringFormat is the format of the fields that accept both integers and strings
+	intOrStringFormat = "int-or-string"
+	// quantityDefinition accepts numbers like cpu: 1 along with strings like memory: 1Gi
+	quantityDefinition = "io.k8s.apimachinery.pkg.api.resource.Quantity"
+)
+
+// ValidationError is a field of a Kubernetes resource that does not conform to the OpenAPI schema of the Kubernetes version
+type ValidationError struct {
+	// Document is the index of the yaml document containing the resource
+	Document   int    `yaml:"document"`
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Name       string `yaml:"name"`
+	// Field is the path to the invalid field, with the keys separated by dots. It is empty for errors about the whole resource.
+	Field   string `yaml:"field"`
+	Message string `yaml:"message"`
+}
+
+var (
+	validationMutex sync.Mutex
+	// openAPIDefinitions caches the definitions of the OpenAPI spec of each Kubernetes version
+	openAPIDefinitions = map[string]map[string]interface{}{}
+	// resourceSchemas caches the compiled schema of each resource, keyed by the version and the definition name
+	resourceSchemas = map[string]*gojsonschema.Schema{}
+)
+
+// ValidateResources validates the Kubernetes resources in the multi document yaml against the OpenAPI schema
+// of the Kubernetes version. Documents that can't be parsed and kinds that the version doesn't serve are also
+// reported as validation errors. An error is returned only if the OpenAPI schema can't be fetched.
+func ValidateResources(yamlStr, kubernetesVersion string) ([]ValidationError, error) {
+	version, err := normalizeKubernetesVersion(kubernetesVersion)
+	if err != nil {
+		return nil, err
+	}
+	validationMutex.Lock()
+	defer validationMutex.Unlock()
+	definitions, err := getOpenAPIDefinitions(version)
+	if err != nil {
+		return nil, err
+	}
+	return validateResources(yamlStr, version, definitions)
+}
+
+func validateResources(yamlStr, version string, definitions map[string]interface{}) ([]ValidationError, error) {
+	validationErrors := []ValidationError{}
+	decoder := yaml.NewDecoder(strings.NewReader(yamlStr))
+	for document := 0; ; document++ {
+		resource := map[string]interface{}{}
+		if err := decoder.Decode(&resource); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			validationErrors = append(validationErrors, ValidationError{Document: document, Message: fmt.Sprintf("failed to parse the yaml. Error: %s", err)})
+			break
+		}
+		if len(resource) == 0 {
+			continue
+		}
+		kind, apiVersion, name, err := GetInfoFromK8sResource(resource)
+		if err != nil {
+			validationErrors = append(validationErrors, ValidationError{Document: document, Message: err.Error()})
+			continue
+		}
+		newError := func(field, message string) ValidationError {
+			return ValidationError{Document: document, APIVersion: apiVersion, Kind: kind, Name: name, Field: field, Message: message}
+		}
+		definitionName := getDefinitionName(definitions, apiVersion, kind)
+		if definitionName == "" {
+			validationErrors = append(validationErrors, newError("", fmt.Sprintf("the kind %s with the apiVersion %s is not supported by Kubernetes %s", kind, apiVersion, version)))
+			continue
+		}
+		schema, err := getResourceSchema(version, definitionName, definitions)
+		if err != nil {
+			return validationErrors, err
+		}
+		result, err := schema.Validate(gojsonschema.NewGoLoader(removeNullValues(resource)))
+		if err != nil {
+			validationErrors = append(validationErrors, newError("", fmt.Sprintf("failed to validate the resource. Error: %s", err)))
+			continue
+		}
+		for _, resultErr := range result.Errors() {
+			field := resultErr.Field()
+			if field == gojsonschema.STRING_CONTEXT_ROOT {
+				field = ""
+			}
+			validationErrors = append(validationErrors, newError(field, resultErr.Description()))
+		}
+	}
+	return validationErrors, nil
+}
+
+// normalizeKubernetesVersion converts versions like 1.23 into the release tags like v1.23.0
+func normalizeKubernetesVersion(kubernetesVersion string) (string, error) {
+	version, err := semver.NewVersion(kubernetesVersion)
+	if err != nil {
+		return "", fmt.Errorf("the Kubernetes version '%s' is invalid. Error: %w", kubernetesVersion, err)
+	}
+	return "v" + version.String(), nil
+}
+
+// getOpenAPIDefinitions returns the definitions in the OpenAPI spec of the Kubernetes version, downloading it if it is not cached
+func getOpenAPIDefinitions(version string) (map[string]interface{}, error) {
+	if definitions, ok := openAPIDefinitions[version]; ok {
+		return definitions, nil
+	}
+	specPath := filepath.Join(common.TempPath, common.RemoteDir, "openapi", version+".json")
+	specBytes, err := os.ReadFile(specPath)
+	if err != nil {
+		specBytes, err = downloadOpenAPISpec(version, specPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	spec := struct {
+		Definitions map[string]interface{} `json:"definitions"`
+	}{}
+	if err := json.Unmarshal(specBytes, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse the OpenAPI spec of Kubernetes %s at %s . Error: %w", version, specPath, err)
+	}
+	definitions := prepareDefinitions(spec.Definitions)
+	openAPIDefinitions[version] = definitions
+	return definitions, nil
+}
+
+func downloadOpenAPISpec(version, specPath string) ([]byte, error) {
+	specURL := fmt.Sprintf(openAPISpecURLFormat, version)
+	logrus.Infof("Downloading the OpenAPI spec of Kubernetes %s from %s", version, specURL)
+	resp, err := http.Get(specURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download the OpenAPI spec of Kubernetes %s from %s . Error: %w", version, specURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("failed to download the OpenAPI spec of Kubernetes %s from %s . Status: %s", version, specURL, resp.Status)
+	}
+	specBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the OpenAPI spec of Kubernetes %s from %s . Error: %w", version, specURL, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(specPath), common.DefaultDirectoryPermission); err != nil {
+		logrus.Debugf("failed to create the directory %s . Error: %q", filepath.Dir(specPath), err)
+	} else if err := os.WriteFile(specPath, specBytes, common.DefaultFilePermission); err != nil {
+		logrus.Debugf("failed to cache the OpenAPI spec at %s . Error: %q", specPath, err)
+	}
+	return specBytes, nil
+}
+
+// prepareDefinitions makes the swagger definitions usable as a JSON schema. The fields that accept integers or strings
+// get both types, and the objects with known properties reject unknown fields like the API server does.
+func prepareDefinitions(definitions map[string]interface{}) map[string]interface{} {
+	for name, definition := range definitions {
+		prepareSchema(definition)
+		if name == quantityDefinition {
+			if definitionMap, ok := definition.(map[string]interface{}); ok {
+				definitionMap["type"] = []interface{}{"string", "number"}
+			}
+		}
+	}
+	return definitions
+}
+
+func prepareSchema(schema interface{}) {
+	schemaMap, ok := schema.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if schemaMap["format"] == intOrStringFormat {
+		schemaMap["type"] = []interface{}{"integer", "string"}
+		delete(schemaMap, "format")
+	}
+	if properties, ok := schemaMap["properties"].(map[string]interface{}); ok {
+		if _, ok := schemaMap["additionalProperties"]; !ok {
+			schemaMap["additionalProperties"] = false
+		}
+		for _, property := range properties {
+			prepareSchema(property)
+		}
+	}
+	prepareSchema(schemaMap["items"])
+	prepareSchema(schemaMap["additionalProperties"])
+}
+
+// getDefinitionName returns the name of the definition describing the kind, or empty string if the version doesn't serve it
+func getDefinitionName(definitions map[string]interface{}, apiVersion, kind string) string {
+	group, version := "", apiVersion
+	if i := strings.LastIndex(apiVersion, "/"); i != -1 {
+		group, version = apiVersion[:i], apiVersion[i+1:]
+	}
+	for name, definition := range definitions {
+		definitionMap, ok := definition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		gvks, ok := definitionMap[gvkExtension].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, gvkI := range gvks {
+			gvk, ok := gvkI.(map[string]interface{})
+			if ok && gvk["group"] == group && gvk["version"] == version && gvk["kind"] == kind {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+func getResourceSchema(version, definitionName string, definitions map[string]interface{}) (*gojsonschema.Schema, error) {
+	key := version + "/" + definitionName
+	if schema, ok := resourceSchemas[key]; ok {
+		return schema, nil
+	}
+	rootSchema := map[string]interface{}{
+		"$ref":        "#/definitions/" + definitionName,
+		"definitions": definitions,
+	}
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(rootSchema))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the schema of %s from the OpenAPI spec of Kubernetes %s . Error: %w", definitionName, version, err)
+	}
+	resourceSchemas[key] = schema
+	return schema, nil
+}
+
+// removeNullValues removes the keys with null values since the API server treats them like missing fields
+func removeNullValues(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if child == nil {
+				delete(v, key)
+				continue
+			}
+			v[key] = removeNullValues(child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = removeNullValues(child)
+		}
+	}
+	return value
+}
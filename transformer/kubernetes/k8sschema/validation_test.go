@@ -0,0 +1,135 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package k8sschema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testOpenAPIDefinitions = `{
+  "io.k8s.api.core.v1.Service": {
+    "type": "object",
+    "x-kubernetes-group-version-kind": [{"group": "", "version": "v1", "kind": "Service"}],
+    "properties": {
+      "apiVersion": {"type": "string"},
+      "kind": {"type": "string"},
+      "metadata": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"},
+      "spec": {"$ref": "#/definitions/io.k8s.api.core.v1.ServiceSpec"}
+    }
+  },
+  "io.k8s.api.core.v1.ServiceSpec": {
+    "type": "object",
+    "properties": {
+      "ports": {"type": "array", "items": {"$ref": "#/definitions/io.k8s.api.core.v1.ServicePort"}}
+    }
+  },
+  "io.k8s.api.core.v1.ServicePort": {
+    "type": "object",
+    "required": ["port"],
+    "properties": {
+      "port": {"type": "integer", "format": "int32"},
+      "targetPort": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.util.intstr.IntOrString"}
+    }
+  },
+  "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {
+    "type": "object",
+    "properties": {
+      "name": {"type": "string"},
+      "creationTimestamp": {"type": "string", "format": "date-time"}
+    }
+  },
+  "io.k8s.apimachinery.pkg.util.intstr.IntOrString": {"type": "string", "format": "int-or-string"}
+}`
+
+func TestValidateResources(t *testing.T) {
+	definitions := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(testOpenAPIDefinitions), &definitions); err != nil {
+		t.Fatalf("failed to parse the test definitions. Error: %q", err)
+	}
+	definitions = prepareDefinitions(definitions)
+
+	t.Run("valid resources have no errors", func(t *testing.T) {
+		manifests := `
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc1
+  creationTimestamp: null
+spec:
+  ports:
+  - port: 8080
+    targetPort: 8080
+  - port: 8443
+    targetPort: https
+`
+		validationErrors, err := validateResources(manifests, "v1.23.0", definitions)
+		if err != nil {
+			t.Fatalf("failed to validate the resources. Error: %q", err)
+		}
+		if len(validationErrors) != 0 {
+			t.Fatalf("expected no validation errors. Actual: %+v", validationErrors)
+		}
+	})
+
+	t.Run("invalid fields and unsupported kinds are reported", func(t *testing.T) {
+		manifests := `
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc1
+spec:
+  ports:
+  - targetPort: 8080
+    protocl: TCP
+---
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: ing1
+`
+		validationErrors, err := validateResources(manifests, "v1.23.0", definitions)
+		if err != nil {
+			t.Fatalf("failed to validate the resources. Error: %q", err)
+		}
+		fields := map[string]int{}
+		for _, validationError := range validationErrors {
+			fields[validationError.Field] = validationError.Document
+		}
+		if document, ok := fields["spec.ports.0"]; !ok || document != 0 {
+			t.Errorf("expected the missing port and the unknown field to be reported on spec.ports.0 of the first document. Actual: %+v", validationErrors)
+		}
+		if document, ok := fields[""]; !ok || document != 1 {
+			t.Errorf("expected the unsupported Ingress in the second document to be reported. Actual: %+v", validationErrors)
+		}
+	})
+}
+
+func TestNormalizeKubernetesVersion(t *testing.T) {
+	for version, expected := range map[string]string{"1.23": "v1.23.0", "v1.22.3": "v1.22.3"} {
+		actual, err := normalizeKubernetesVersion(version)
+		if err != nil {
+			t.Fatalf("failed to normalize the version %s . Error: %q", version, err)
+		}
+		if actual != expected {
+			t.Errorf("expected the version %s to be normalized to %s . Actual: %s", version, expected, actual)
+		}
+	}
+	if _, err := normalizeKubernetesVersion("latest"); err == nil {
+		t.Errorf("expected an error for an invalid version")
+	}
+}
@@ -78,6 +78,7 @@ func (t *Knative) Transform(newArtifacts []transformertypes.Artifact, alreadySee
 	logrus.Debugf("Translating IR using Kubernetes transformer")
 	pathMappings = []transformertypes.PathMapping{}
 	createdArtifacts = []transformertypes.Artifact{}
+	globalMetadata := apiresource.GetGlobalMetadata()
 	for _, a := range newArtifacts {
 		if a.Type != irtypes.IRArtifactType {
 			continue
@@ -105,7 +106,7 @@ func (t *Knative) Transform(newArtifacts []transformertypes.Artifact, alreadySee
 		logrus.Debugf("Starting Kubernetes transform")
 		logrus.Debugf("Total services to be transformed : %d", len(ir.Services))
 		apis := []apiresource.IAPIResource{&apiresource.KnativeService{}}
-		files, err := apiresource.TransformIRAndPersist(irtypes.NewEnhancedIRFromIR(ir), tempDest, apis, clusterConfig)
+		files, err := apiresource.TransformIRAndPersist(irtypes.NewEnhancedIRFromIR(ir), tempDest, apis, clusterConfig, globalMetadata)
 		if err != nil {
 			logrus.Errorf("Unable to transform and persist IR : %s", err)
 			return nil, nil, err
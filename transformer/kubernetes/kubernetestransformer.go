@@ -90,6 +90,7 @@ func (t *Kubernetes) Transform(newArtifacts []transformertypes.Artifact, already
 	logrus.Debugf("Translating IR using Kubernetes transformer")
 	pathMappings = []transformertypes.PathMapping{}
 	createdArtifacts = []transformertypes.Artifact{}
+	globalMetadata := apiresource.GetGlobalMetadata()
 	for _, newArtifact := range newArtifacts {
 		if newArtifact.Type != irtypes.IRArtifactType {
 			continue
@@ -130,12 +131,19 @@ func (t *Kubernetes) Transform(newArtifacts []transformertypes.Artifact, already
 		tempDest := filepath.Join(t.Env.TempPath, "k8s-yamls-"+common.GetRandomString())
 		logrus.Debugf("Starting Kubernetes transform")
 		logrus.Debugf("Total services to be transformed : %d", len(ir.Services))
-		apis := []apiresource.IAPIResource{new(apiresource.Deployment), new(apiresource.Storage), new(apiresource.Service), new(apiresource.ImageStream), new(apiresource.NetworkPolicy)}
-		files, err := apiresource.TransformIRAndPersist(irtypes.NewEnhancedIRFromIR(ir), tempDest, apis, clusterConfig)
+		serviceMesh := apiresource.GetServiceMesh()
+		apis := []apiresource.IAPIResource{&apiresource.Deployment{ServiceMesh: serviceMesh}, new(apiresource.Storage), &apiresource.Service{ServiceMesh: serviceMesh}, new(apiresource.ImageStream), new(apiresource.NetworkPolicy), &apiresource.ServiceMesh{ServiceMesh: serviceMesh}, new(apiresource.ServiceMonitor), new(apiresource.VerticalPodAutoscaler)}
+		enhancedIR := irtypes.NewEnhancedIRFromIR(ir)
+		files, err := apiresource.TransformIRAndPersist(enhancedIR, tempDest, apis, clusterConfig, globalMetadata)
 		if err != nil {
 			logrus.Errorf("Unable to transform and persist IR : %s", err)
 			return nil, nil, err
 		}
+		templatedFiles, err := apiresource.TransformIRUsingResourceTemplatesAndPersist(enhancedIR, t.Env.ProjectName, tempDest)
+		if err != nil {
+			logrus.Errorf("Unable to fill the resource templates from the customizations : %s", err)
+		}
+		files = append(files, templatedFiles...)
 		serviceFsPath := ""
 		if serviceFsPaths, ok := newArtifact.Paths[artifacts.ServiceDirPathType]; ok && len(serviceFsPaths) > 0 {
 			serviceFsPath = serviceFsPaths[0]
@@ -93,6 +93,7 @@ func (t *KubernetesVersionChanger) DirectoryDetect(dir string) (namedServices ma
 func (t *KubernetesVersionChanger) Transform(newArtifacts []transformertypes.Artifact, alreadySeenArtifacts []transformertypes.Artifact) (pathMappings []transformertypes.PathMapping, createdArtifacts []transformertypes.Artifact, err error) {
 	pathMappings = []transformertypes.PathMapping{}
 	apis := []apiresource.IAPIResource{new(apiresource.Deployment), new(apiresource.Service)}
+	globalMetadata := apiresource.GetGlobalMetadata()
 	for _, a := range newArtifacts {
 		yamlsPath := a.Paths[artifacts.KubernetesYamlsPathType][0]
 		var clusterConfig collecttypes.ClusterMetadata
@@ -123,7 +124,7 @@ func (t *KubernetesVersionChanger) Transform(newArtifacts []transformertypes.Art
 					return nil
 				}
 				if objs := k8sschema.GetKubernetesObjsInDir(path); len(objs) != 0 {
-					_, err := apiresource.TransformObjsAndPersist(path, filepath.Join(tempDest, relInputPath), apis, clusterConfig)
+					_, err := apiresource.TransformObjsAndPersist(path, filepath.Join(tempDest, relInputPath), apis, clusterConfig, globalMetadata)
 					if err != nil {
 						logrus.Errorf("Unable to transform objs at %s : %s", path, err)
 						return nil
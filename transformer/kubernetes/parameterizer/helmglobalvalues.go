@@ -0,0 +1,217 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/transformer/kubernetes/k8sschema"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// helmGlobalValuesKey is the key in the values.yaml under which the shared values are stored
+	helmGlobalValuesKey = "global"
+	// minResourcesForGlobalValue is the minimum number of resources that must share a value for it to be made global
+	minResourcesForGlobalValue = 2
+)
+
+// hoistSharedHelmValues moves the values that are identical across multiple resources, in every environment,
+// into the global section of the values.yaml and makes the helm templates of those resources refer to the global value.
+// Only values under the default per resource keys "<kind>"."<apiVersion>"."<name>" are considered.
+func hoistSharedHelmValues(envs []string, ks []k8sschema.K8sResourceT, namedValues map[string]HelmValuesT) {
+	if len(envs) == 0 {
+		return
+	}
+	prefixes := [][]string{}
+	seenPrefixes := map[string]bool{}
+	for _, k := range ks {
+		kind, apiVersion, metadataName, err := k8sschema.GetInfoFromK8sResource(k)
+		if err != nil {
+			continue
+		}
+		prefix := []string{kind, apiVersion, metadataName}
+		if seenPrefixes[strings.Join(prefix, "\n")] {
+			continue
+		}
+		seenPrefixes[strings.Join(prefix, "\n")] = true
+		prefixes = append(prefixes, prefix)
+	}
+	// group the resources by the keys of their values relative to the per resource keys
+	suffixes := map[string][]string{}
+	prefixesBySuffix := map[string][][]string{}
+	for _, prefix := range prefixes {
+		resourceValues, ok := getNested(namedValues[envs[0]], prefix).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, suffix := range getLeafKeys(resourceValues, nil) {
+			suffixKey := strings.Join(suffix, "\n")
+			suffixes[suffixKey] = suffix
+			prefixesBySuffix[suffixKey] = append(prefixesBySuffix[suffixKey], prefix)
+		}
+	}
+	suffixKeys := []string{}
+	for suffixKey := range suffixes {
+		suffixKeys = append(suffixKeys, suffixKey)
+	}
+	sort.Strings(suffixKeys)
+	replacements := map[string]string{}
+	for _, suffixKey := range suffixKeys {
+		suffix := suffixes[suffixKey]
+		sharingPrefixes := prefixesBySuffix[suffixKey]
+		if len(sharingPrefixes) < minResourcesForGlobalValue {
+			continue
+		}
+		globalKeys := append([]string{helmGlobalValuesKey}, suffix...)
+		if !isSharedHelmValue(envs, namedValues, sharingPrefixes, suffix, globalKeys) {
+			continue
+		}
+		logrus.Debugf("moving the helm value %s shared by %d resources to the global values", strings.Join(suffix, "."), len(sharingPrefixes))
+		for _, env := range envs {
+			value := getNested(namedValues[env], append(append([]string{}, sharingPrefixes[0]...), suffix...))
+			for _, prefix := range sharingPrefixes {
+				deleteNested(namedValues[env], append(append([]string{}, prefix...), suffix...))
+			}
+			setNested(namedValues[env], globalKeys, value)
+		}
+		for _, prefix := range sharingPrefixes {
+			replacements[getHelmIndexTemplate(append(append([]string{}, prefix...), suffix...))] = getHelmIndexTemplate(globalKeys)
+		}
+	}
+	if len(replacements) == 0 {
+		return
+	}
+	for _, k := range ks {
+		replaceInStrings(k, replacements)
+	}
+}
+
+// isSharedHelmValue checks that the value is the same for all the resources in all the environments
+// and that it does not collide with an existing global value
+func isSharedHelmValue(envs []string, namedValues map[string]HelmValuesT, prefixes [][]string, suffix []string, globalKeys []string) bool {
+	for _, env := range envs {
+		if getNested(namedValues[env], globalKeys) != nil {
+			return false
+		}
+		var firstValue interface{}
+		for i, prefix := range prefixes {
+			value := getNested(namedValues[env], append(append([]string{}, prefix...), suffix...))
+			if value == nil {
+				return false
+			}
+			if i == 0 {
+				firstValue = value
+				continue
+			}
+			if !reflect.DeepEqual(firstValue, value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func getHelmIndexTemplate(keys []string) string {
+	quotedKeys := []string{}
+	for _, key := range keys {
+		quotedKeys = append(quotedKeys, `"`+key+`"`)
+	}
+	return fmt.Sprintf(`{{ index .Values %s }}`, strings.Join(quotedKeys, " "))
+}
+
+// getLeafKeys returns the keys of all the non map values
+func getLeafKeys(values map[string]interface{}, parentKeys []string) [][]string {
+	leafKeys := [][]string{}
+	for key, value := range values {
+		keys := append(append([]string{}, parentKeys...), key)
+		if valueMap, ok := value.(map[string]interface{}); ok {
+			leafKeys = append(leafKeys, getLeafKeys(valueMap, keys)...)
+			continue
+		}
+		leafKeys = append(leafKeys, keys)
+	}
+	return leafKeys
+}
+
+func getNested(values map[string]interface{}, keys []string) interface{} {
+	var value interface{} = values
+	for _, key := range keys {
+		valueMap, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if value, ok = valueMap[key]; !ok {
+			return nil
+		}
+	}
+	return value
+}
+
+func setNested(values map[string]interface{}, keys []string, value interface{}) {
+	for _, key := range keys[:len(keys)-1] {
+		valueMap, ok := values[key].(map[string]interface{})
+		if !ok {
+			valueMap = map[string]interface{}{}
+			values[key] = valueMap
+		}
+		values = valueMap
+	}
+	values[keys[len(keys)-1]] = value
+}
+
+// deleteNested deletes the value and any maps that become empty as a result
+func deleteNested(values map[string]interface{}, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	if len(keys) == 1 {
+		delete(values, keys[0])
+		return
+	}
+	valueMap, ok := values[keys[0]].(map[string]interface{})
+	if !ok {
+		return
+	}
+	deleteNested(valueMap, keys[1:])
+	if len(valueMap) == 0 {
+		delete(values, keys[0])
+	}
+}
+
+// replaceInStrings replaces the substrings in all the string values inside the object
+func replaceInStrings(obj interface{}, replacements map[string]string) interface{} {
+	switch value := obj.(type) {
+	case string:
+		for oldStr, newStr := range replacements {
+			value = strings.ReplaceAll(value, oldStr, newStr)
+		}
+		return value
+	case map[string]interface{}:
+		for k, v := range value {
+			value[k] = replaceInStrings(v, replacements)
+		}
+	case []interface{}:
+		for i, v := range value {
+			value[i] = replaceInStrings(v, replacements)
+		}
+	}
+	return obj
+}
@@ -0,0 +1,65 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/transformer/kubernetes/k8sschema"
+)
+
+func TestHoistSharedHelmValues(t *testing.T) {
+	newDeployment := func(name string) k8sschema.K8sResourceT {
+		return k8sschema.K8sResourceT{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": `{{ index .Values "Deployment" "apps/v1" "` + name + `" "metadata" "namespace" }}`,
+			},
+			"spec": map[string]interface{}{
+				"replicas": `{{ index .Values "Deployment" "apps/v1" "` + name + `" "spec" "replicas" }}`,
+			},
+		}
+	}
+	ks := []k8sschema.K8sResourceT{newDeployment("web"), newDeployment("api")}
+	namedValues := map[string]HelmValuesT{}
+	for _, env := range []string{"dev", "prod"} {
+		namedValues[env] = HelmValuesT{"Deployment": map[string]interface{}{"apps/v1": map[string]interface{}{
+			"web": map[string]interface{}{"metadata": map[string]interface{}{"namespace": env}, "spec": map[string]interface{}{"replicas": 1}},
+			"api": map[string]interface{}{"metadata": map[string]interface{}{"namespace": env}, "spec": map[string]interface{}{"replicas": 2}},
+		}}}
+	}
+	hoistSharedHelmValues([]string{"dev", "prod"}, ks, namedValues)
+	wantDevValues := HelmValuesT{
+		"Deployment": map[string]interface{}{"apps/v1": map[string]interface{}{
+			"web": map[string]interface{}{"spec": map[string]interface{}{"replicas": 1}},
+			"api": map[string]interface{}{"spec": map[string]interface{}{"replicas": 2}},
+		}},
+		"global": map[string]interface{}{"metadata": map[string]interface{}{"namespace": "dev"}},
+	}
+	if !cmp.Equal(namedValues["dev"], wantDevValues) {
+		t.Fatalf("the values are incorrect. Differences:\n%s", cmp.Diff(wantDevValues, namedValues["dev"]))
+	}
+	for _, k := range ks {
+		want := `{{ index .Values "global" "metadata" "namespace" }}`
+		if actual := k["metadata"].(map[string]interface{})["namespace"]; actual != want {
+			t.Fatalf("the namespace template was not changed to refer to the global value. Actual: %s", actual)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer
+
+import (
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/transformer/kubernetes/k8sschema"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultNamespaceNameTemplate is used to name the namespace of each environment
+	defaultNamespaceNameTemplate = "{{ .ProjectName }}-{{ .Env }}"
+	helmNamespaceValuesKey       = "namespace"
+	helmNamespaceTemplateFile    = "namespace.yaml"
+	kustomizeNamespaceFile       = "namespace.yaml"
+	podSecurityLabelPrefix       = "pod-security.kubernetes.io/"
+)
+
+// helmNamespaceTemplate creates the namespace and resource quota using the values of the environment
+const helmNamespaceTemplate = `{{- with .Values.namespace }}
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: {{ .name }}
+  {{- with .labels }}
+  labels:
+    {{- toYaml . | nindent 4 }}
+  {{- end }}
+{{- if .quota }}
+---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: {{ .name }}-quota
+  namespace: {{ .name }}
+spec:
+  hard:
+    {{- toYaml .quota | nindent 4 }}
+{{- end }}
+{{- end }}
+`
+
+// NamespacesConfigT is the config for generating a namespace for each environment
+type NamespacesConfigT struct {
+	Enabled             bool              `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	NameTemplate        string            `yaml:"nameTemplate,omitempty" json:"nameTemplate,omitempty"`               // Can use {{ .ProjectName }} and {{ .Env }}
+	PodSecurityStandard string            `yaml:"podSecurityStandard,omitempty" json:"podSecurityStandard,omitempty"` // privileged, baseline or restricted
+	Quota               map[string]string `yaml:"quota,omitempty" json:"quota,omitempty"`                             // The hard limits of the ResourceQuota Ex: requests.cpu: "4"
+}
+
+// namespaceT is the namespace of a single environment
+type namespaceT struct {
+	Name   string
+	Labels map[string]string
+	Quota  map[string]string
+}
+
+// getNamespace returns the namespace for the environment
+func (c NamespacesConfigT) getNamespace(env string) namespaceT {
+	nameTemplate := c.NameTemplate
+	if nameTemplate == "" {
+		nameTemplate = defaultNamespaceNameTemplate
+	}
+	name, err := common.GetStringFromTemplate(nameTemplate, map[string]string{"ProjectName": common.ProjectName, "Env": env})
+	if err != nil {
+		logrus.Errorf("failed to fill the namespace name template %s for the environment %s . Error: %q", nameTemplate, env, err)
+		name = common.ProjectName + "-" + env
+	}
+	namespace := namespaceT{Name: common.NormalizeForMetadataName(name), Labels: map[string]string{}, Quota: c.Quota}
+	if c.PodSecurityStandard != "" {
+		for _, mode := range []string{"enforce", "audit", "warn"} {
+			namespace.Labels[podSecurityLabelPrefix+mode] = c.PodSecurityStandard
+		}
+	}
+	return namespace
+}
+
+// getHelmValues returns the values used by the helm namespace template
+func (n namespaceT) getHelmValues() map[string]interface{} {
+	values := map[string]interface{}{"name": n.Name}
+	if len(n.Labels) != 0 {
+		values["labels"] = n.Labels
+	}
+	if len(n.Quota) != 0 {
+		values["quota"] = n.Quota
+	}
+	return values
+}
+
+// getResources returns the Namespace and ResourceQuota resources
+func (n namespaceT) getResources() []k8sschema.K8sResourceT {
+	namespace := k8sschema.K8sResourceT{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]interface{}{"name": n.Name},
+	}
+	if len(n.Labels) != 0 {
+		namespace["metadata"].(map[string]interface{})["labels"] = n.Labels
+	}
+	resources := []k8sschema.K8sResourceT{namespace}
+	if len(n.Quota) != 0 {
+		resources = append(resources, k8sschema.K8sResourceT{
+			"apiVersion": "v1",
+			"kind":       "ResourceQuota",
+			"metadata":   map[string]interface{}{"name": n.Name + "-quota", "namespace": n.Name},
+			"spec":       map[string]interface{}{"hard": n.Quota},
+		})
+	}
+	return resources
+}
@@ -0,0 +1,152 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/transformer/kubernetes/k8sschema"
+)
+
+func TestGetNamespace(t *testing.T) {
+	oldProjectName := common.ProjectName
+	defer func() { common.ProjectName = oldProjectName }()
+	common.ProjectName = "myproject"
+	testCases := []struct {
+		name   string
+		config NamespacesConfigT
+		env    string
+		want   namespaceT
+	}{
+		{
+			name:   "default name template",
+			config: NamespacesConfigT{Enabled: true},
+			env:    "dev",
+			want:   namespaceT{Name: "myproject-dev", Labels: map[string]string{}},
+		},
+		{
+			name:   "custom name template is normalized",
+			config: NamespacesConfigT{Enabled: true, NameTemplate: "{{ .Env }}_{{ .ProjectName }}"},
+			env:    "Prod",
+			want:   namespaceT{Name: "prod-myproject", Labels: map[string]string{}},
+		},
+		{
+			name:   "invalid name template falls back to the default name",
+			config: NamespacesConfigT{Enabled: true, NameTemplate: "{{ .Env "},
+			env:    "staging",
+			want:   namespaceT{Name: "myproject-staging", Labels: map[string]string{}},
+		},
+		{
+			name:   "pod security standard and quota",
+			config: NamespacesConfigT{Enabled: true, PodSecurityStandard: "restricted", Quota: map[string]string{"requests.cpu": "4"}},
+			env:    "dev",
+			want: namespaceT{
+				Name: "myproject-dev",
+				Labels: map[string]string{
+					"pod-security.kubernetes.io/enforce": "restricted",
+					"pod-security.kubernetes.io/audit":   "restricted",
+					"pod-security.kubernetes.io/warn":    "restricted",
+				},
+				Quota: map[string]string{"requests.cpu": "4"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := testCase.config.getNamespace(testCase.env)
+			if !cmp.Equal(got, testCase.want) {
+				t.Fatalf("the namespace is incorrect. Differences:\n%s", cmp.Diff(testCase.want, got))
+			}
+		})
+	}
+}
+
+func TestNamespaceGetHelmValues(t *testing.T) {
+	testCases := []struct {
+		name      string
+		namespace namespaceT
+		want      map[string]interface{}
+	}{
+		{
+			name:      "only the name",
+			namespace: namespaceT{Name: "myproject-dev", Labels: map[string]string{}},
+			want:      map[string]interface{}{"name": "myproject-dev"},
+		},
+		{
+			name:      "labels and quota",
+			namespace: namespaceT{Name: "myproject-dev", Labels: map[string]string{"team": "a"}, Quota: map[string]string{"pods": "10"}},
+			want: map[string]interface{}{
+				"name":   "myproject-dev",
+				"labels": map[string]string{"team": "a"},
+				"quota":  map[string]string{"pods": "10"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := testCase.namespace.getHelmValues()
+			if !cmp.Equal(got, testCase.want) {
+				t.Fatalf("the helm values are incorrect. Differences:\n%s", cmp.Diff(testCase.want, got))
+			}
+		})
+	}
+}
+
+func TestNamespaceGetResources(t *testing.T) {
+	testCases := []struct {
+		name      string
+		namespace namespaceT
+		want      []k8sschema.K8sResourceT
+	}{
+		{
+			name:      "namespace without quota",
+			namespace: namespaceT{Name: "myproject-dev", Labels: map[string]string{}},
+			want: []k8sschema.K8sResourceT{{
+				"apiVersion": "v1",
+				"kind":       "Namespace",
+				"metadata":   map[string]interface{}{"name": "myproject-dev"},
+			}},
+		},
+		{
+			name:      "namespace with labels and quota",
+			namespace: namespaceT{Name: "myproject-dev", Labels: map[string]string{"team": "a"}, Quota: map[string]string{"pods": "10"}},
+			want: []k8sschema.K8sResourceT{
+				{
+					"apiVersion": "v1",
+					"kind":       "Namespace",
+					"metadata":   map[string]interface{}{"name": "myproject-dev", "labels": map[string]string{"team": "a"}},
+				},
+				{
+					"apiVersion": "v1",
+					"kind":       "ResourceQuota",
+					"metadata":   map[string]interface{}{"name": "myproject-dev-quota", "namespace": "myproject-dev"},
+					"spec":       map[string]interface{}{"hard": map[string]string{"pods": "10"}},
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := testCase.namespace.getResources()
+			if !cmp.Equal(got, testCase.want) {
+				t.Fatalf("the resources are incorrect. Differences:\n%s", cmp.Diff(testCase.want, got))
+			}
+		})
+	}
+}
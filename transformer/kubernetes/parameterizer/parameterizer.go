@@ -82,12 +82,18 @@ func Parameterize(srcDir, outDir string, packSpecConfig ParameterizerConfigT, ps
 		// helm chart with multiple values.yaml
 		helmChartName := normalizeForHelmChartName(packSpecConfig.ProjectName)
 		namedValues := map[string]HelmValuesT{}
+		// every environment gets a values file, even if nothing was parameterized
+		for _, env := range packSpecConfig.Envs {
+			namedValues[env] = HelmValuesT{}
+		}
 		helmChartDir := filepath.Join(cleanOutDir, packSpecConfig.Helm, helmChartName)
 
 		helmTemplatesDir := filepath.Join(helmChartDir, "templates")
 		if err := os.MkdirAll(helmTemplatesDir, common.DefaultDirectoryPermission); err != nil {
 			logrus.Errorf("Unable to create directory for helm : %s", err)
 		} else {
+			kPaths := []string{}
+			parameterizedKs := []k8sschema.K8sResourceT{}
 			for kPath, ks := range pathedKs {
 				for _, k := range ks {
 					k = deepcopy.DeepCopy(k).(k8sschema.K8sResourceT)
@@ -95,14 +101,32 @@ func Parameterize(srcDir, outDir string, packSpecConfig ParameterizerConfigT, ps
 						logrus.Errorf("Unable to parameterize for helm : %s", err)
 						continue
 					}
-					finalKPath := filepath.Join(helmTemplatesDir, kPath)
-					if err := writeResourceStripQuotesAndAppendToFile(k, finalKPath); err != nil {
-						logrus.Errorf("Unable to write parameterized file to %s : %s", finalKPath, err)
-						continue
-					}
+					kPaths = append(kPaths, kPath)
+					parameterizedKs = append(parameterizedKs, k)
+				}
+			}
+			if packSpecConfig.HelmGlobalValues {
+				hoistSharedHelmValues(packSpecConfig.Envs, parameterizedKs, namedValues)
+			}
+			if packSpecConfig.Namespaces.Enabled {
+				for _, env := range packSpecConfig.Envs {
+					namedValues[env][helmNamespaceValuesKey] = packSpecConfig.Namespaces.getNamespace(env).getHelmValues()
+				}
+				finalKPath := filepath.Join(helmTemplatesDir, helmNamespaceTemplateFile)
+				if err := os.WriteFile(finalKPath, []byte(helmNamespaceTemplate), common.DefaultFilePermission); err != nil {
+					logrus.Errorf("Unable to write the namespace template to %s : %s", finalKPath, err)
+				} else {
 					filesWritten = append(filesWritten, finalKPath)
 				}
 			}
+			for i, k := range parameterizedKs {
+				finalKPath := filepath.Join(helmTemplatesDir, kPaths[i])
+				if err := writeResourceStripQuotesAndAppendToFile(k, finalKPath); err != nil {
+					logrus.Errorf("Unable to write parameterized file to %s : %s", finalKPath, err)
+					continue
+				}
+				filesWritten = append(filesWritten, finalKPath)
+			}
 			for env, values := range namedValues {
 				finalKPath := filepath.Join(helmChartDir, "values-"+env+".yaml")
 				if shouldGenerateDefaultEnv && env == parameterizerDefaultEnvironment {
@@ -137,6 +161,10 @@ func Parameterize(srcDir, outDir string, packSpecConfig ParameterizerConfigT, ps
 			logrus.Errorf("Unable to create directory %s : %s", baseDir, err)
 		} else {
 			kustPatches := map[string]map[PatchMetadataT][]PatchT{}
+			// every environment gets an overlay, even if nothing was parameterized
+			for _, env := range packSpecConfig.Envs {
+				kustPatches[env] = map[PatchMetadataT][]PatchT{}
+			}
 			kPaths := []string{}
 			for kPath, ks := range pathedKs {
 				for _, k := range ks {
@@ -203,6 +231,18 @@ func Parameterize(srcDir, outDir string, packSpecConfig ParameterizerConfigT, ps
 					filesWritten = append(filesWritten, finalKPath)
 				}
 				kustomization := map[string]interface{}{"resources": []string{"../../base"}, "patches": metas}
+				if packSpecConfig.Namespaces.Enabled {
+					namespace := packSpecConfig.Namespaces.getNamespace(env)
+					finalKPath := filepath.Join(envDir, kustomizeNamespaceFile)
+					for _, k := range namespace.getResources() {
+						if err := writeResourceAppendToFile(k, finalKPath); err != nil {
+							logrus.Errorf("Unable to write the namespace for env %s (%s) : %s", env, finalKPath, err)
+						}
+					}
+					filesWritten = append(filesWritten, finalKPath)
+					kustomization["resources"] = []string{"../../base", kustomizeNamespaceFile}
+					kustomization["namespace"] = namespace.Name
+				}
 				finalKPath := filepath.Join(envDir, "kustomization.yaml")
 				if err := common.WriteYaml(finalKPath, kustomization); err != nil {
 					logrus.Errorf("Unable to write file %s : %s", finalKPath, err)
@@ -216,6 +256,10 @@ func Parameterize(srcDir, outDir string, packSpecConfig ParameterizerConfigT, ps
 		// openshift templates for each env
 		newKs := []k8sschema.K8sResourceT{}
 		ocParams := map[string]map[string]string{}
+		// every environment gets a parameters file, even if nothing was parameterized
+		for _, env := range packSpecConfig.Envs {
+			ocParams[env] = map[string]string{}
+		}
 		for _, ks := range pathedKs {
 			for _, k := range ks {
 				k = deepcopy.DeepCopy(k).(k8sschema.K8sResourceT)
@@ -227,12 +271,11 @@ func Parameterize(srcDir, outDir string, packSpecConfig ParameterizerConfigT, ps
 			}
 		}
 		singleSet := []OCParamT{}
-		if len(ocParams) > 0 {
-			for _, kvs := range ocParams {
-				for k, v := range kvs {
+		for _, env := range packSpecConfig.Envs {
+			for k, v := range ocParams[env] {
+				if common.FindIndex(singleSet, func(p OCParamT) bool { return p.Name == k }) == -1 {
 					singleSet = append(singleSet, OCParamT{Name: k, Value: v})
 				}
-				break
 			}
 		}
 		templ := map[string]interface{}{
@@ -434,6 +477,16 @@ func fillCustomTemplate(templ, kind, apiVersion, metadataName string, matches ma
 
 func parameterize(target ParamTargetT, envs []string, k k8sschema.K8sResourceT, ps []ParameterizerT, namedValues map[string]HelmValuesT, namedKustPatches map[string]map[string]PatchT, namedOCParams map[string]map[string]string) error {
 	for _, p := range ps {
+		if p.Starlark != nil {
+			generatedPs, err := p.Starlark.GetParameterizers(envs, k)
+			if err != nil {
+				return err
+			}
+			if err := parameterize(target, envs, k, generatedPs, namedValues, namedKustPatches, namedOCParams); err != nil {
+				return err
+			}
+			continue
+		}
 		ok, err := parameterizeFilter(envs, k, p)
 		if err != nil {
 			return err
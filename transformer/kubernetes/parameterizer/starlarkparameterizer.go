@@ -0,0 +1,94 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/transformer/kubernetes/k8sschema"
+	starutil "github.com/qri-io/starlib/util"
+	"go.starlark.net/starlark"
+)
+
+const (
+	// starlarkParameterizeFnName is the function that must be defined by Starlark parameterizers
+	// def parameterize(resource, envs): returns a list of parameterizers (same format as the yaml parameterizers) to apply on the resource
+	starlarkParameterizeFnName = "parameterize"
+)
+
+// StarlarkParameterizerT generates parameterizers for each k8s resource by calling a Starlark function
+type StarlarkParameterizerT struct {
+	StarFile    string
+	starThread  *starlark.Thread
+	parameterFn *starlark.Function
+}
+
+// NewStarlarkParameterizer loads a Starlark parameterizer from a file
+func NewStarlarkParameterizer(starFile string) (*StarlarkParameterizerT, error) {
+	s := &StarlarkParameterizerT{StarFile: starFile, starThread: &starlark.Thread{Name: filepath.Base(starFile)}}
+	globals, err := starlark.ExecFile(s.starThread, starFile, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute the starlark file %s . Error: %w", starFile, err)
+	}
+	fnValue, ok := globals[starlarkParameterizeFnName]
+	if !ok {
+		return nil, fmt.Errorf("no %s function found in the starlark file %s", starlarkParameterizeFnName, starFile)
+	}
+	if s.parameterFn, ok = fnValue.(*starlark.Function); !ok {
+		return nil, fmt.Errorf("%s is not a function in the starlark file %s", starlarkParameterizeFnName, starFile)
+	}
+	return s, nil
+}
+
+// GetParameterizers returns the parameterizers that the Starlark function generated for the k8s resource
+func (s *StarlarkParameterizerT) GetParameterizers(envs []string, k k8sschema.K8sResourceT) ([]ParameterizerT, error) {
+	starResource, err := starutil.Marshal(k)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert the k8s resource to a starlark value. Error: %w", err)
+	}
+	envsI := []interface{}{}
+	for _, env := range envs {
+		envsI = append(envsI, env)
+	}
+	starEnvs, err := starutil.Marshal(envsI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert the environments %+v to a starlark value. Error: %w", envs, err)
+	}
+	val, err := starlark.Call(s.starThread, s.parameterFn, starlark.Tuple{starResource, starEnvs}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call the function %s in the starlark file %s . Error: %w", starlarkParameterizeFnName, s.StarFile, err)
+	}
+	if val == starlark.None {
+		return nil, nil
+	}
+	valI, err := starutil.Unmarshal(val)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert the value returned by the starlark file %s . Error: %w", s.StarFile, err)
+	}
+	ps := []ParameterizerT{}
+	if err := common.GetObjFromInterface(valI, &ps); err != nil {
+		return nil, fmt.Errorf("the value returned by the starlark file %s is not a list of parameterizers. Error: %w", s.StarFile, err)
+	}
+	for _, p := range ps {
+		if p.Target == "" {
+			return nil, fmt.Errorf("one of the parameterizers returned by the starlark file %s has an empty target: %+v", s.StarFile, p)
+		}
+	}
+	return ps, nil
+}
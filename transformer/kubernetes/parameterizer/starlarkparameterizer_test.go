@@ -0,0 +1,62 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/transformer/kubernetes/k8sschema"
+	"github.com/konveyor/move2kube/transformer/kubernetes/parameterizer"
+)
+
+func TestStarlarkParameterizer(t *testing.T) {
+	starFile := filepath.Join(t.TempDir(), "params.star")
+	starScript := `
+def parameterize(resource, envs):
+    if resource["kind"] != "Deployment":
+        return []
+    name = resource["metadata"]["name"]
+    return [{"target": "spec.replicas", "template": "${" + name + ".replicas}", "default": len(envs)}]
+`
+	if err := os.WriteFile(starFile, []byte(starScript), 0644); err != nil {
+		t.Fatalf("failed to write the starlark file. Error: %q", err)
+	}
+	s, err := parameterizer.NewStarlarkParameterizer(starFile)
+	if err != nil {
+		t.Fatalf("failed to load the starlark parameterizer. Error: %q", err)
+	}
+	deployment := k8sschema.K8sResourceT{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": map[string]interface{}{"name": "web"}}
+	ps, err := s.GetParameterizers([]string{"dev", "prod"}, deployment)
+	if err != nil {
+		t.Fatalf("failed to get the parameterizers. Error: %q", err)
+	}
+	want := []parameterizer.ParameterizerT{{Target: "spec.replicas", Template: "${web.replicas}", Default: 2}}
+	if !cmp.Equal(ps, want) {
+		t.Fatalf("the generated parameterizers are incorrect. Differences:\n%s", cmp.Diff(want, ps))
+	}
+	service := k8sschema.K8sResourceT{"apiVersion": "v1", "kind": "Service", "metadata": map[string]interface{}{"name": "web"}}
+	ps, err = s.GetParameterizers([]string{"dev"}, service)
+	if err != nil {
+		t.Fatalf("failed to get the parameterizers. Error: %q", err)
+	}
+	if len(ps) != 0 {
+		t.Fatalf("expected no parameterizers for a service. Actual: %+v", ps)
+	}
+}
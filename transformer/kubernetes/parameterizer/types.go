@@ -33,11 +33,13 @@ type HelmValuesT map[string]interface{}
 
 // ParameterizerConfigT is the set of paths to be parameterized
 type ParameterizerConfigT struct {
-	ProjectName string   `yaml:"projectName,omitempty" json:"projectName,omitempty"`
-	Helm        string   `yaml:"helm,omitempty" json:"helm,omitempty"`
-	Kustomize   string   `yaml:"kustomize,omitempty" json:"kustomize,omitempty"`
-	OCTemplates string   `yaml:"openshiftTemplates,omitempty" json:"openshiftTemplates,omitempty"`
-	Envs        []string `yaml:"envs,omitempty" json:"envs,omitempty"`
+	ProjectName      string            `yaml:"projectName,omitempty" json:"projectName,omitempty"`
+	Helm             string            `yaml:"helm,omitempty" json:"helm,omitempty"`
+	HelmGlobalValues bool              `yaml:"helmGlobalValues,omitempty" json:"helmGlobalValues,omitempty"` // Move the values shared by multiple resources into the global values
+	Kustomize        string            `yaml:"kustomize,omitempty" json:"kustomize,omitempty"`
+	OCTemplates      string            `yaml:"openshiftTemplates,omitempty" json:"openshiftTemplates,omitempty"`
+	Envs             []string          `yaml:"envs,omitempty" json:"envs,omitempty"`
+	Namespaces       NamespacesConfigT `yaml:"namespaces,omitempty" json:"namespaces,omitempty"`
 }
 
 // ParameterizerFileT is the file format for the parameterizers
@@ -50,6 +52,8 @@ type ParameterizerFileT struct {
 // ParameterizerSpecT is the spec inside the parameterizers file
 type ParameterizerSpecT struct {
 	Parameterizers []ParameterizerT `yaml:"parameterizers" json:"parameterizers"`
+	// StarFile is the path (relative to the parameterizers file) of a Starlark script that generates parameterizers for each k8s resource
+	StarFile string `yaml:"starFile,omitempty" json:"starFile,omitempty"`
 }
 
 // ParameterizerT is a paramterizer
@@ -61,6 +65,8 @@ type ParameterizerT struct {
 	Question   *qaengine.Problem `yaml:"question,omitempty" json:"question,omitempty"`
 	Filters    []FilterT         `yaml:"filters,omitempty" json:"filters,omitempty"`
 	Parameters []ParameterT      `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+	// Starlark generates the parameterizers to apply for each k8s resource. Set when loaded from a Starlark script.
+	Starlark *StarlarkParameterizerT `yaml:"-" json:"-"`
 }
 
 // FilterT is used to choose the k8s resources that the parameterizer should be applied on
@@ -352,6 +352,18 @@ func CollectParamsFromPath(parameterizersDir string) (map[string][]Parameterizer
 		if err := common.ReadMove2KubeYamlStrict(yamlPath, &paramFile, ParameterizerKind); err == nil {
 			logrus.Debugf("found paramterizer yaml at path %s", yamlPath)
 			params[paramFile.ObjectMeta.Name] = paramFile.Spec.Parameterizers
+			if paramFile.Spec.StarFile != "" {
+				starFile := paramFile.Spec.StarFile
+				if !filepath.IsAbs(starFile) {
+					starFile = filepath.Join(filepath.Dir(yamlPath), starFile)
+				}
+				starParameterizer, err := NewStarlarkParameterizer(starFile)
+				if err != nil {
+					logrus.Errorf("failed to load the starlark parameterizer %s of the parameterizer yaml at path %s . Error: %q", starFile, yamlPath, err)
+					continue
+				}
+				params[paramFile.ObjectMeta.Name] = append(params[paramFile.ObjectMeta.Name], ParameterizerT{Starlark: starParameterizer})
+			}
 		}
 	}
 	return params, nil
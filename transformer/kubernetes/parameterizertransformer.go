@@ -23,6 +23,7 @@ import (
 
 	"github.com/konveyor/move2kube/common"
 	"github.com/konveyor/move2kube/environment"
+	"github.com/konveyor/move2kube/qaengine"
 	"github.com/konveyor/move2kube/transformer/kubernetes/k8sschema"
 	"github.com/konveyor/move2kube/transformer/kubernetes/parameterizer"
 	transformertypes "github.com/konveyor/move2kube/types/transformer"
@@ -46,11 +47,13 @@ type Parameterizer struct {
 
 // ParameterizerYamlConfig implements Parameterizer path config interface
 type ParameterizerYamlConfig struct {
-	HelmPath       string   `yaml:"helmPath" json:"helmPath"`
-	OCTemplatePath string   `yaml:"ocTemplatePath" json:"ocTemplatePath"`
-	KustomizePath  string   `yaml:"kustomizePath" json:"kustomizePath"`
-	ProjectName    string   `yaml:"projectName" json:"projectName"`
-	Envs           []string `yaml:"envs,omitempty" json:"envs,omitempty"`
+	HelmPath         string                          `yaml:"helmPath" json:"helmPath"`
+	HelmGlobalValues bool                            `yaml:"helmGlobalValues" json:"helmGlobalValues"`
+	OCTemplatePath   string                          `yaml:"ocTemplatePath" json:"ocTemplatePath"`
+	KustomizePath    string                          `yaml:"kustomizePath" json:"kustomizePath"`
+	ProjectName      string                          `yaml:"projectName" json:"projectName"`
+	Envs             []string                        `yaml:"envs,omitempty" json:"envs,omitempty"`
+	Namespaces       parameterizer.NamespacesConfigT `yaml:"namespaces,omitempty" json:"namespaces,omitempty"`
 }
 
 // ParameterizerPathTemplateConfig stores the template config
@@ -129,13 +132,25 @@ func (t *Parameterizer) Transform(newArtifacts []transformertypes.Artifact, alre
 			continue
 		}
 		pt := parameterizer.ParameterizerConfigT{
-			Helm:        "helm",
-			Kustomize:   "kustomize",
-			OCTemplates: "octemplates",
-			ProjectName: projectName,
-			Envs:        []string{},
+			Helm:             "helm",
+			HelmGlobalValues: t.ParameterizerConfig.HelmGlobalValues,
+			Kustomize:        "kustomize",
+			OCTemplates:      "octemplates",
+			ProjectName:      projectName,
+			Envs:             []string{},
+			Namespaces:       t.ParameterizerConfig.Namespaces,
 		}
-		if len(t.ParameterizerConfig.Envs) > 0 {
+		pt.Namespaces.Enabled = qaengine.FetchBoolAnswer(
+			common.ConfigTargetNamespacePerEnvironmentKey,
+			"Do you want to create a namespace for each environment?",
+			[]string{"The Helm chart and the Kustomize overlays will create and use the namespace of the environment."},
+			t.ParameterizerConfig.Namespaces.Enabled,
+			nil,
+		)
+		// the environments in the plan or the flag take precedence over the ones in the transformer config
+		if len(common.Environments) > 0 {
+			pt.Envs = common.Environments
+		} else if len(t.ParameterizerConfig.Envs) > 0 {
 			pt.Envs = t.ParameterizerConfig.Envs
 		}
 		if len(t.ParameterizerConfig.HelmPath) == 0 {
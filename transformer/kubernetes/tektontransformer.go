@@ -31,6 +31,7 @@ import (
 	"github.com/konveyor/move2kube/transformer/kubernetes/irpreprocessor"
 	collecttypes "github.com/konveyor/move2kube/types/collection"
 	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/konveyor/move2kube/types/qaengine/commonqa"
 	transformertypes "github.com/konveyor/move2kube/types/transformer"
 	"github.com/konveyor/move2kube/types/transformer/artifacts"
 	"github.com/sirupsen/logrus"
@@ -45,6 +46,8 @@ const (
 	knownHostsPlaceholder                  = "<TODO: insert the known host keys for your git repo>"
 	gitPrivateKeyPlaceholder               = "<TODO: insert the private ssh key for your git repo>"
 	dockerConfigJSONPlaceholder            = "<TODO: insert your docker config json>"
+	cosignKeyPlaceholder                   = "<TODO: insert the cosign private key>"
+	cosignPasswordPlaceholder              = "<TODO: insert the password of the cosign private key>"
 	baseGitSecretName                      = "git-repo"
 	baseWorkspaceName                      = "shared-data"
 	baseTektonTriggersServiceAccountName   = "tekton-triggers-admin"
@@ -53,6 +56,7 @@ const (
 	basePipelineName                       = "clone-build-push"
 	baseClonePushServiceAccountName        = "clone-push"
 	baseRegistrySecretName                 = "image-registry"
+	baseSigningSecretName                  = "cosign"
 	baseGitEventListenerName               = "git-repo"
 	baseGitEventIngressName                = "git-repo"
 	baseTektonTriggersAdminRoleName        = "tekton-triggers-admin"
@@ -103,6 +107,7 @@ func (t *Tekton) Transform(newArtifacts []transformertypes.Artifact, alreadySeen
 	logrus.Debugf("Translating IR using Kubernetes transformer")
 	pathMappings = []transformertypes.PathMapping{}
 	createdArtifacts = []transformertypes.Artifact{}
+	globalMetadata := apiresource.GetGlobalMetadata()
 	for _, newArtifact := range newArtifacts {
 		if newArtifact.Type != irtypes.IRArtifactType {
 			continue
@@ -138,8 +143,8 @@ func (t *Tekton) Transform(newArtifacts []transformertypes.Artifact, alreadySeen
 		deployCICDDir := t.TektonConfig.OutputPath
 		tempDest := filepath.Join(t.Env.TempPath, deployCICDDir)
 		logrus.Debugf("Generating Tekton pipeline for CI/CD")
-		enhancedIR := t.setupEnhancedIR(ir, t.Env.GetProjectName())
-		files, err := apiresource.TransformIRAndPersist(enhancedIR, tempDest, resources, clusterConfig)
+		enhancedIR := t.setupEnhancedIR(ir, t.Env.GetProjectName(), clusterConfig)
+		files, err := apiresource.TransformIRAndPersist(enhancedIR, tempDest, resources, clusterConfig, globalMetadata)
 		if err != nil {
 			logrus.Errorf("Unable to transform and persist IR : %s", err)
 			return nil, nil, err
@@ -170,7 +175,7 @@ func (t *Tekton) Transform(newArtifacts []transformertypes.Artifact, alreadySeen
 }
 
 // setupEnhancedIR returns EnhancedIR containing Tekton components
-func (t *Tekton) setupEnhancedIR(oldir irtypes.IR, name string) irtypes.EnhancedIR {
+func (t *Tekton) setupEnhancedIR(oldir irtypes.IR, name string, targetCluster collecttypes.ClusterMetadata) irtypes.EnhancedIR {
 	ir := irtypes.NewEnhancedIRFromIR(oldir)
 
 	// Prefix the project name and make the name a valid k8s name.
@@ -183,6 +188,7 @@ func (t *Tekton) setupEnhancedIR(oldir irtypes.IR, name string) irtypes.Enhanced
 	gitSecretNamePrefix := p(baseGitSecretName)
 	clonePushServiceAccountName := p(baseClonePushServiceAccountName)
 	registrySecretName := p(baseRegistrySecretName)
+	signingSecretName := p(baseSigningSecretName)
 	gitEventListenerName := p(baseGitEventListenerName)
 	triggerBindingName := p(baseTriggerBindingName)
 	tektonTriggersAdminServiceAccountName := p(baseTektonTriggersServiceAccountName)
@@ -194,6 +200,12 @@ func (t *Tekton) setupEnhancedIR(oldir irtypes.IR, name string) irtypes.Enhanced
 	// https://github.com/tektoncd/triggers/blob/master/docs/eventlisteners.md#how-does-the-eventlistener-work
 	gitEventListenerServiceName := "el-" + gitEventListenerName
 
+	// Use the storage class the target cluster would pick by default for the workspace volume
+	storageClassName := targetCluster.Spec.GetDefaultStorageClass()
+	if storageClassName == "" {
+		storageClassName = defaultStorageClassName
+	}
+
 	res := irtypes.TektonResources{}
 	res.EventListeners = []irtypes.EventListener{{
 		Name:                gitEventListenerName,
@@ -208,12 +220,17 @@ func (t *Tekton) setupEnhancedIR(oldir irtypes.IR, name string) irtypes.Enhanced
 		PipelineRunName:    pipelineName + "-$(uid)", // appends a random string to the name to make it unique
 		ServiceAccountName: clonePushServiceAccountName,
 		WorkspaceName:      workspaceName,
-		StorageClassName:   defaultStorageClassName,
+		StorageClassName:   storageClassName,
 	}}
-	res.Pipelines = []irtypes.Pipeline{{
+	signing := commonqa.Signing()
+	pipeline := irtypes.Pipeline{
 		Name:          pipelineName,
 		WorkspaceName: workspaceName,
-	}}
+	}
+	if signing.UsesKeyFile() {
+		pipeline.SigningSecretName = signingSecretName
+	}
+	res.Pipelines = []irtypes.Pipeline{pipeline}
 	ir.TektonResources = res
 	var port int32 = common.DefaultServicePort
 	ir.Services = map[string]irtypes.Service{gitEventIngressName: {
@@ -258,6 +275,16 @@ func (t *Tekton) setupEnhancedIR(oldir irtypes.IR, name string) irtypes.Enhanced
 	}
 
 	secrets := []irtypes.Storage{imageRegistrySecret}
+	if signing.UsesKeyFile() {
+		secrets = append(secrets, irtypes.Storage{
+			StorageType: irtypes.SecretKind,
+			Name:        signingSecretName,
+			Content: map[string][]byte{
+				apiresource.CosignKeySecretKey:      []byte(cosignKeyPlaceholder),
+				apiresource.CosignPasswordSecretKey: []byte(cosignPasswordPlaceholder),
+			},
+		})
+	}
 	gitDomains := []string{}
 	for _, container := range ir.ContainerImages {
 		if container.Build.ContextPath == "" {
@@ -0,0 +1,434 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package transformer
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/filesystem"
+	"github.com/konveyor/move2kube/qaengine"
+	"github.com/konveyor/move2kube/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	"github.com/konveyor/move2kube/types/transformer/artifacts"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// monorepoOutputLayout keeps the deployment artifacts, scripts and sources in a single repo with separate directories
+	monorepoOutputLayout = "monorepo"
+	// perServiceOutputLayout puts the source and the manifests of each service in its own directory that can be split into a separate repo
+	perServiceOutputLayout = "perservice"
+	// sourceAdjacentOutputLayout puts the sources at the root of the output, with the Dockerfiles next to them like in the original repo
+	sourceAdjacentOutputLayout = "sourceadjacent"
+
+	// servicesOutputDir is the directory containing the per service directories
+	servicesOutputDir = "services"
+	// kustomizationFile is the name of the kustomization file that lists the resources of a directory
+	kustomizationFile = "kustomization.yaml"
+	// maxLayoutRewriteFileSize is the size above which files are not checked for paths to adjust
+	maxLayoutRewriteFileSize = 1024 * 1024
+	// layoutServiceLabel is the label that contains the name of the service a generated resource belongs to
+	layoutServiceLabel = types.GroupName + "/service"
+	// k8sYamlsOutputDir is the default directory of the kubernetes manifests
+	k8sYamlsOutputDir = common.DeployDir + string(os.PathSeparator) + "yamls"
+	// knativeYamlsOutputDir is the default directory of the knative manifests
+	knativeYamlsOutputDir = common.DeployDir + string(os.PathSeparator) + "knative"
+)
+
+// outputLayouts are the supported layouts of the output directory
+var outputLayouts = []string{monorepoOutputLayout, perServiceOutputLayout, sourceAdjacentOutputLayout}
+
+// perServiceManifestDirs are the directories whose manifests are moved to the directory of the service they belong to
+var perServiceManifestDirs = []string{k8sYamlsOutputDir, knativeYamlsOutputDir}
+
+// outputMove is a path in the output directory that is moved to another path, both relative to the output directory
+type outputMove struct {
+	from string
+	to   string
+}
+
+// kustomization is the subset of a kustomization file that is generated for the rearranged manifests
+type kustomization struct {
+	APIVersion string   `yaml:"apiVersion"`
+	Kind       string   `yaml:"kind"`
+	Resources  []string `yaml:"resources"`
+}
+
+// applyOutputLayout rearranges the output directory as per the layout selected by the user
+// and adjusts the paths in the generated files that refer to the moved directories.
+func applyOutputLayout(planArtifacts []plantypes.PlanArtifact, sourceDir, outputPath string) {
+	layout := qaengine.FetchSelectAnswer(
+		common.ConfigTargetOutputLayoutKey,
+		"Select the layout of the output directory",
+		[]string{
+			monorepoOutputLayout + ": the deployment artifacts, scripts and sources are in separate directories of a single repo",
+			perServiceOutputLayout + ": each service gets a directory with its source and manifests, suitable for splitting into separate repos",
+			sourceAdjacentOutputLayout + ": the sources are at the root with the Dockerfiles next to them, like in the original repo",
+		},
+		monorepoOutputLayout,
+		outputLayouts,
+		nil,
+	)
+	var moves []outputMove
+	switch layout {
+	case perServiceOutputLayout:
+		moves = getPerServiceMoves(getServiceSourceDirs(planArtifacts, sourceDir), outputPath)
+	case sourceAdjacentOutputLayout:
+		moves = getSourceAdjacentMoves(outputPath)
+	default:
+		return
+	}
+	if len(moves) == 0 {
+		return
+	}
+	applied := []outputMove{}
+	for _, move := range moves {
+		if err := moveOutputPath(filepath.Join(outputPath, move.from), filepath.Join(outputPath, move.to)); err != nil {
+			logrus.Errorf("failed to move %s to %s in the output directory. Error: %q", move.from, move.to, err)
+			continue
+		}
+		applied = append(applied, move)
+	}
+	if layout == perServiceOutputLayout {
+		writePerServiceKustomizations(applied, outputPath)
+	}
+	if _, err := os.Stat(filepath.Join(outputPath, common.DefaultSourceDir)); os.IsNotExist(err) {
+		// the scripts go to the parent of the source directory before using the relative paths
+		applied = append(applied, outputMove{from: common.DefaultSourceDir + string(os.PathSeparator) + "..", to: "."})
+	}
+	rewriteMovedPaths(applied, outputPath)
+	logrus.Infof("Arranged the output directory using the %s layout", layout)
+}
+
+// getServiceSourceDirs returns the source directory of each service, relative to the source directory
+func getServiceSourceDirs(planArtifacts []plantypes.PlanArtifact, sourceDir string) map[string]string {
+	serviceSourceDirs := map[string]string{}
+	for _, planArtifact := range planArtifacts {
+		serviceDirs := planArtifact.Paths[artifacts.ServiceDirPathType]
+		if planArtifact.ServiceName == "" || len(serviceDirs) == 0 {
+			continue
+		}
+		if _, ok := serviceSourceDirs[planArtifact.ServiceName]; ok {
+			continue
+		}
+		serviceDir := serviceDirs[0]
+		if filepath.IsAbs(serviceDir) {
+			relServiceDir, err := filepath.Rel(sourceDir, serviceDir)
+			if err != nil || strings.HasPrefix(relServiceDir, "..") {
+				logrus.Debugf("the directory %s of the service %s is not inside the source directory %s", serviceDir, planArtifact.ServiceName, sourceDir)
+				continue
+			}
+			serviceDir = relServiceDir
+		}
+		serviceSourceDirs[planArtifact.ServiceName] = filepath.Clean(serviceDir)
+	}
+	return serviceSourceDirs
+}
+
+// getPerServiceMoves returns the moves that put the source and the manifests of each service in a directory of its own
+func getPerServiceMoves(serviceSourceDirs map[string]string, outputPath string) []outputMove {
+	serviceNames := []string{}
+	for serviceName := range serviceSourceDirs {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	// move the nested source directories out before their parents
+	sort.SliceStable(serviceNames, func(i, j int) bool {
+		di, dj := strings.Count(serviceSourceDirs[serviceNames[i]], string(os.PathSeparator)), strings.Count(serviceSourceDirs[serviceNames[j]], string(os.PathSeparator))
+		if di != dj {
+			return di > dj
+		}
+		return serviceNames[i] < serviceNames[j]
+	})
+	moves := []outputMove{}
+	for _, serviceName := range serviceNames {
+		serviceSourceDir := serviceSourceDirs[serviceName]
+		from := filepath.Join(common.DefaultSourceDir, serviceSourceDir)
+		if serviceSourceDir == "." {
+			logrus.Warnf("The service %s is at the root of the source directory. Leaving its source in the %s directory.", serviceName, common.DefaultSourceDir)
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(outputPath, from)); err != nil {
+			continue
+		}
+		moves = append(moves, outputMove{from: from, to: filepath.Join(servicesOutputDir, common.MakeFileNameCompliant(serviceName))})
+	}
+	for _, manifestDir := range perServiceManifestDirs {
+		moves = append(moves, getServiceManifestMoves(manifestDir, serviceSourceDirs, outputPath)...)
+	}
+	return moves
+}
+
+// getServiceManifestMoves returns the moves of the manifest files in the directory whose resources all belong to a single service
+func getServiceManifestMoves(manifestDir string, serviceSourceDirs map[string]string, outputPath string) []outputMove {
+	entries, err := os.ReadDir(filepath.Join(outputPath, manifestDir))
+	if err != nil {
+		return nil
+	}
+	moves := []outputMove{}
+	for _, entry := range entries {
+		if entry.IsDir() || !common.IsPresent([]string{".yaml", ".yml"}, filepath.Ext(entry.Name())) || entry.Name() == kustomizationFile {
+			continue
+		}
+		serviceName := getManifestService(filepath.Join(outputPath, manifestDir, entry.Name()))
+		if _, ok := serviceSourceDirs[serviceName]; !ok {
+			continue
+		}
+		moves = append(moves, outputMove{
+			from: filepath.Join(manifestDir, entry.Name()),
+			to:   filepath.Join(servicesOutputDir, common.MakeFileNameCompliant(serviceName), manifestDir, entry.Name()),
+		})
+	}
+	return moves
+}
+
+// getManifestService returns the service all the resources in the manifest file belong to.
+// Returns an empty string if the resources belong to different services or to none.
+func getManifestService(manifestPath string) string {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		logrus.Debugf("failed to read the manifest file %s . Error: %q", manifestPath, err)
+		return ""
+	}
+	docs, err := common.SplitYAML(data)
+	if err != nil {
+		logrus.Debugf("failed to split the file %s into yaml documents. Error: %q", manifestPath, err)
+		return ""
+	}
+	serviceName := ""
+	for _, doc := range docs {
+		resource := struct {
+			Metadata struct {
+				Labels map[string]string `yaml:"labels"`
+			} `yaml:"metadata"`
+		}{}
+		if err := yaml.Unmarshal(doc, &resource); err != nil {
+			return ""
+		}
+		currServiceName := resource.Metadata.Labels[layoutServiceLabel]
+		if currServiceName == "" || (serviceName != "" && serviceName != currServiceName) {
+			return ""
+		}
+		serviceName = currServiceName
+	}
+	return serviceName
+}
+
+// getSourceAdjacentMoves returns the moves that put the contents of the source directory at the root of the output
+func getSourceAdjacentMoves(outputPath string) []outputMove {
+	entries, err := os.ReadDir(filepath.Join(outputPath, common.DefaultSourceDir))
+	if err != nil {
+		return nil
+	}
+	moves := []outputMove{}
+	for _, entry := range entries {
+		if _, err := os.Stat(filepath.Join(outputPath, entry.Name())); err == nil {
+			logrus.Warnf("The output directory already contains %s . Leaving it in the %s directory.", entry.Name(), common.DefaultSourceDir)
+			continue
+		}
+		moves = append(moves, outputMove{from: filepath.Join(common.DefaultSourceDir, entry.Name()), to: entry.Name()})
+	}
+	return moves
+}
+
+// moveOutputPath moves the file or directory, merging it with the destination if it already exists
+func moveOutputPath(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), common.DefaultDirectoryPermission); err != nil {
+		return fmt.Errorf("failed to create the directory %s . Error: %w", filepath.Dir(to), err)
+	}
+	if _, err := os.Stat(to); os.IsNotExist(err) {
+		if err := os.Rename(from, to); err != nil {
+			return fmt.Errorf("failed to rename %s to %s . Error: %w", from, to, err)
+		}
+	} else {
+		if err := filesystem.Merge(from, to, true); err != nil {
+			return fmt.Errorf("failed to merge %s into %s . Error: %w", from, to, err)
+		}
+		if err := os.RemoveAll(from); err != nil {
+			return fmt.Errorf("failed to remove %s after merging it into %s . Error: %w", from, to, err)
+		}
+	}
+	removeEmptyParentDirs(filepath.Dir(from))
+	return nil
+}
+
+// removeEmptyParentDirs removes the directory and its parents as long as they are empty
+func removeEmptyParentDirs(dir string) {
+	for {
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			return
+		}
+		if err := os.Remove(dir); err != nil {
+			logrus.Debugf("failed to remove the empty directory %s . Error: %q", dir, err)
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// writePerServiceKustomizations writes a kustomization in the manifest directory of each service
+// and refers to them from the kustomization of the shared manifest directory they were moved out of.
+func writePerServiceKustomizations(moves []outputMove, outputPath string) {
+	serviceManifestDirs := map[string][]string{} // [shared manifest directory]service manifest directories
+	for _, move := range moves {
+		sharedManifestDir := filepath.Dir(move.from)
+		if !common.IsPresent(perServiceManifestDirs, sharedManifestDir) {
+			continue
+		}
+		serviceManifestDir := filepath.Dir(move.to)
+		if !common.IsPresent(serviceManifestDirs[sharedManifestDir], serviceManifestDir) {
+			serviceManifestDirs[sharedManifestDir] = append(serviceManifestDirs[sharedManifestDir], serviceManifestDir)
+		}
+	}
+	for sharedManifestDir, dirs := range serviceManifestDirs {
+		sort.Strings(dirs)
+		bases := []string{}
+		for _, serviceManifestDir := range dirs {
+			if err := writeKustomization(filepath.Join(outputPath, serviceManifestDir), nil); err != nil {
+				logrus.Errorf("failed to write the kustomization of the service manifests in %s . Error: %q", serviceManifestDir, err)
+				continue
+			}
+			base, err := filepath.Rel(sharedManifestDir, serviceManifestDir)
+			if err != nil {
+				logrus.Errorf("failed to make the path %s relative to %s . Error: %q", serviceManifestDir, sharedManifestDir, err)
+				continue
+			}
+			bases = append(bases, filepath.ToSlash(base))
+		}
+		if err := writeKustomization(filepath.Join(outputPath, sharedManifestDir), bases); err != nil {
+			logrus.Errorf("failed to write the kustomization of the manifests in %s . Error: %q", sharedManifestDir, err)
+		}
+	}
+}
+
+// writeKustomization writes a kustomization listing the manifest files in the directory and the bases.
+// An existing kustomization is left untouched.
+func writeKustomization(dir string, bases []string) error {
+	kustomizationPath := filepath.Join(dir, kustomizationFile)
+	if _, err := os.Stat(kustomizationPath); err == nil {
+		logrus.Debugf("the kustomization %s already exists", kustomizationPath)
+		return nil
+	}
+	if err := os.MkdirAll(dir, common.DefaultDirectoryPermission); err != nil {
+		return fmt.Errorf("failed to create the directory %s . Error: %w", dir, err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read the directory %s . Error: %w", dir, err)
+	}
+	resources := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && common.IsPresent([]string{".yaml", ".yml"}, filepath.Ext(entry.Name())) {
+			resources = append(resources, entry.Name())
+		}
+	}
+	resources = append(resources, bases...)
+	return common.WriteYaml(kustomizationPath, kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Resources:  resources,
+	})
+}
+
+// rewriteMovedPaths adjusts the paths to the moved directories in the generated files, like the build contexts in the scripts.
+// The moved sources themselves are not modified.
+func rewriteMovedPaths(moves []outputMove, outputPath string) {
+	replacers := getMovedPathReplacers(moves)
+	if len(replacers) == 0 {
+		return
+	}
+	sourceDests := []string{}
+	for _, move := range moves {
+		if common.IsParent(move.from, common.DefaultSourceDir) {
+			sourceDests = append(sourceDests, filepath.Join(outputPath, move.to))
+		}
+	}
+	sourceDests = append(sourceDests, filepath.Join(outputPath, common.DefaultSourceDir))
+	if err := filepath.WalkDir(outputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if common.IsPresent(sourceDests, path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info, err := d.Info(); err != nil || !info.Mode().IsRegular() || info.Size() > maxLayoutRewriteFileSize {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || bytes.IndexByte(data, 0) != -1 {
+			return nil
+		}
+		newData := string(data)
+		for _, replacer := range replacers {
+			newData = replacer.re.ReplaceAllString(newData, "${1}"+strings.ReplaceAll(replacer.to, "$", "$$")+"${2}")
+		}
+		if newData == string(data) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if err := os.WriteFile(path, []byte(newData), info.Mode()); err != nil {
+			logrus.Errorf("failed to adjust the paths in the file %s . Error: %q", path, err)
+		}
+		return nil
+	}); err != nil {
+		logrus.Errorf("failed to walk the output directory %s to adjust the moved paths. Error: %q", outputPath, err)
+	}
+}
+
+// movedPathReplacer replaces the references to a moved path
+type movedPathReplacer struct {
+	re *regexp.Regexp
+	to string
+}
+
+// getMovedPathReplacers returns the replacers for the unix and windows forms of the moved paths.
+// The longer paths are replaced first so that a moved parent does not shadow a moved child.
+func getMovedPathReplacers(moves []outputMove) []movedPathReplacer {
+	sortedMoves := append([]outputMove{}, moves...)
+	sort.SliceStable(sortedMoves, func(i, j int) bool { return len(sortedMoves[i].from) > len(sortedMoves[j].from) })
+	replacers := []movedPathReplacer{}
+	for _, move := range sortedMoves {
+		for _, convert := range []func(string) string{common.GetUnixPath, common.GetWindowsPath} {
+			from, to := convert(move.from), convert(move.to)
+			if from == to {
+				continue
+			}
+			re, err := regexp.Compile(`(^|[\s"'=:(,\[/\\])` + regexp.QuoteMeta(from) + `([\s"'/\\),\]]|$)`)
+			if err != nil {
+				logrus.Errorf("failed to compile the regex for the moved path %s . Error: %q", from, err)
+				continue
+			}
+			replacers = append(replacers, movedPathReplacer{re: re, to: to})
+		}
+	}
+	return replacers
+}
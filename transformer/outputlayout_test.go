@@ -0,0 +1,155 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package transformer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/konveyor/move2kube/types/transformer/artifacts"
+)
+
+func writeLayoutTestFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), common.DefaultDirectoryPermission); err != nil {
+		t.Fatalf("failed to create the directory for %s . Error: %q", path, err)
+	}
+	if err := os.WriteFile(path, []byte(data), common.DefaultFilePermission); err != nil {
+		t.Fatalf("failed to write the file %s . Error: %q", path, err)
+	}
+}
+
+func TestGetServiceSourceDirs(t *testing.T) {
+	sourceDir := t.TempDir()
+	newPlanArtifact := func(serviceName string, serviceDirs ...string) plantypes.PlanArtifact {
+		return plantypes.PlanArtifact{
+			ServiceName: serviceName,
+			Artifact:    transformertypes.Artifact{Paths: map[transformertypes.PathType][]string{artifacts.ServiceDirPathType: serviceDirs}},
+		}
+	}
+	planArtifacts := []plantypes.PlanArtifact{
+		newPlanArtifact("web", filepath.Join(sourceDir, "web")),
+		newPlanArtifact("web", filepath.Join(sourceDir, "other")),
+		newPlanArtifact("api", filepath.Join("api", "..", "api", "v1")),
+		newPlanArtifact("outside", filepath.Join(filepath.Dir(sourceDir), "outside")),
+		newPlanArtifact("nodirs"),
+		newPlanArtifact("", filepath.Join(sourceDir, "noname")),
+	}
+	want := map[string]string{"web": "web", "api": filepath.Join("api", "v1")}
+	if got := getServiceSourceDirs(planArtifacts, sourceDir); !cmp.Equal(got, want) {
+		t.Fatalf("the service source directories are incorrect. Differences:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestGetManifestService(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "single resource",
+			data: "kind: Service\nmetadata:\n  labels:\n    move2kube.konveyor.io/service: web\n",
+			want: "web",
+		},
+		{
+			name: "all the resources belong to the same service",
+			data: "kind: Service\nmetadata:\n  labels:\n    move2kube.konveyor.io/service: web\n---\nkind: Deployment\nmetadata:\n  labels:\n    move2kube.konveyor.io/service: web\n",
+			want: "web",
+		},
+		{
+			name: "resources of different services",
+			data: "kind: Service\nmetadata:\n  labels:\n    move2kube.konveyor.io/service: web\n---\nkind: Service\nmetadata:\n  labels:\n    move2kube.konveyor.io/service: api\n",
+		},
+		{
+			name: "resource without the service label",
+			data: "kind: Service\nmetadata:\n  labels:\n    move2kube.konveyor.io/service: web\n---\nkind: Ingress\nmetadata:\n  name: myproject\n",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			manifestPath := filepath.Join(t.TempDir(), "manifest.yaml")
+			writeLayoutTestFile(t, manifestPath, testCase.data)
+			if got := getManifestService(manifestPath); got != testCase.want {
+				t.Fatalf("expected the service to be %q. Actual: %q", testCase.want, got)
+			}
+		})
+	}
+	if got := getManifestService(filepath.Join(t.TempDir(), "missing.yaml")); got != "" {
+		t.Fatalf("expected no service for a missing manifest. Actual: %q", got)
+	}
+}
+
+func TestGetPerServiceMoves(t *testing.T) {
+	outputPath := t.TempDir()
+	for _, dir := range []string{"web", "api", filepath.Join("api", "nested")} {
+		writeLayoutTestFile(t, filepath.Join(outputPath, common.DefaultSourceDir, dir, "main.go"), "package main\n")
+	}
+	writeLayoutTestFile(t, filepath.Join(outputPath, k8sYamlsOutputDir, "web-deployment.yaml"), "kind: Deployment\nmetadata:\n  labels:\n    move2kube.konveyor.io/service: web\n")
+	writeLayoutTestFile(t, filepath.Join(outputPath, k8sYamlsOutputDir, "myproject-ingress.yaml"), "kind: Ingress\nmetadata:\n  name: myproject\n")
+	writeLayoutTestFile(t, filepath.Join(outputPath, k8sYamlsOutputDir, kustomizationFile), "kind: Kustomization\n")
+	serviceSourceDirs := map[string]string{
+		"web":     "web",
+		"api":     "api",
+		"nested":  filepath.Join("api", "nested"),
+		"root":    ".",
+		"missing": "missing",
+	}
+	want := []outputMove{
+		{from: filepath.Join(common.DefaultSourceDir, "api", "nested"), to: filepath.Join(servicesOutputDir, "nested")},
+		{from: filepath.Join(common.DefaultSourceDir, "api"), to: filepath.Join(servicesOutputDir, "api")},
+		{from: filepath.Join(common.DefaultSourceDir, "web"), to: filepath.Join(servicesOutputDir, "web")},
+		{from: filepath.Join(k8sYamlsOutputDir, "web-deployment.yaml"), to: filepath.Join(servicesOutputDir, "web", k8sYamlsOutputDir, "web-deployment.yaml")},
+	}
+	got := getPerServiceMoves(serviceSourceDirs, outputPath)
+	if !cmp.Equal(got, want, cmp.AllowUnexported(outputMove{})) {
+		t.Fatalf("the moves are incorrect. Differences:\n%s", cmp.Diff(want, got, cmp.AllowUnexported(outputMove{})))
+	}
+}
+
+func TestRewriteMovedPaths(t *testing.T) {
+	outputPath := t.TempDir()
+	scriptPath := filepath.Join(outputPath, "scripts", "builddockerimages.sh")
+	writeLayoutTestFile(t, scriptPath, "cd source/web\ndocker build -f source/web/Dockerfile source/web\ndocker build source/webapp\n")
+	batchPath := filepath.Join(outputPath, "scripts", "builddockerimages.bat")
+	writeLayoutTestFile(t, batchPath, "pushd source\\web\n")
+	movedSourcePath := filepath.Join(outputPath, servicesOutputDir, "web", "build.sh")
+	writeLayoutTestFile(t, movedSourcePath, "cd source/web\n")
+	rewriteMovedPaths([]outputMove{{from: filepath.Join(common.DefaultSourceDir, "web"), to: filepath.Join(servicesOutputDir, "web")}}, outputPath)
+	testCases := []struct {
+		path string
+		want string
+	}{
+		{path: scriptPath, want: "cd services/web\ndocker build -f services/web/Dockerfile services/web\ndocker build source/webapp\n"},
+		{path: batchPath, want: "pushd services\\web\n"},
+		{path: movedSourcePath, want: "cd source/web\n"},
+	}
+	for _, testCase := range testCases {
+		data, err := os.ReadFile(testCase.path)
+		if err != nil {
+			t.Fatalf("failed to read the file %s . Error: %q", testCase.path, err)
+		}
+		if string(data) != testCase.want {
+			t.Fatalf("the paths in the file %s were not adjusted correctly. Differences:\n%s", testCase.path, cmp.Diff(testCase.want, string(data)))
+		}
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/types"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// conflictPostprocessor reports the resources that can't be applied together because they have the same name
+// or request the same NodePort. The manifests in a directory are assumed to be applied together, so the same
+// resource in the yamls for different environments is not a conflict.
+type conflictPostprocessor struct {
+}
+
+// conflictManifest contains the fields of a manifest used to detect the conflicts
+type conflictManifest struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Ports []struct {
+			NodePort int `yaml:"nodePort"`
+		} `yaml:"ports"`
+	} `yaml:"spec"`
+}
+
+func (p conflictPostprocessor) postprocess(outputPath string) error {
+	files, err := getManifestFiles(outputPath)
+	if err != nil {
+		return err
+	}
+	conflicts := findConflicts(outputPath, files)
+	if len(conflicts) == 0 {
+		return nil
+	}
+	logrus.Warnf("Found %d conflicts in the generated manifests:\n  - %s", len(conflicts), strings.Join(conflicts, "\n  - "))
+	return nil
+}
+
+// findConflicts returns the duplicate resources and the clashing NodePorts in each directory
+func findConflicts(outputPath string, files []string) []string {
+	// [directory][resource or port][]user
+	resources := map[string]map[string][]string{}
+	nodePorts := map[string]map[string][]string{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			logrus.Debugf("failed to read the file %s . Error: %q", file, err)
+			continue
+		}
+		if strings.Contains(string(data), "{{") {
+			continue
+		}
+		docs, err := common.SplitYAML(data)
+		if err != nil {
+			logrus.Debugf("failed to split the file %s into yaml documents. Error: %q", file, err)
+			continue
+		}
+		relPath, err := filepath.Rel(outputPath, file)
+		if err != nil {
+			relPath = file
+		}
+		dir := filepath.Dir(relPath)
+		for _, doc := range docs {
+			manifest := conflictManifest{}
+			if err := yaml.Unmarshal(doc, &manifest); err != nil || manifest.Kind == "" || manifest.Metadata.Name == "" {
+				continue
+			}
+			if strings.HasPrefix(manifest.APIVersion, types.GroupName+"/") {
+				continue
+			}
+			group := strings.SplitN(manifest.APIVersion, "/", 2)[0]
+			if !strings.Contains(manifest.APIVersion, "/") {
+				group = ""
+			}
+			resource := fmt.Sprintf("%s %s", manifest.Kind, manifest.Metadata.Name)
+			if group != "" {
+				resource = fmt.Sprintf("%s.%s %s", manifest.Kind, group, manifest.Metadata.Name)
+			}
+			if manifest.Metadata.Namespace != "" {
+				resource += " in the namespace " + manifest.Metadata.Namespace
+			}
+			addConflictUser(resources, dir, resource, relPath)
+			if manifest.Kind != common.ServiceKind {
+				continue
+			}
+			for _, port := range manifest.Spec.Ports {
+				if port.NodePort != 0 {
+					addConflictUser(nodePorts, dir, fmt.Sprint(port.NodePort), fmt.Sprintf("Service %s in %s", manifest.Metadata.Name, relPath))
+				}
+			}
+		}
+	}
+	conflicts := []string{}
+	for dir, dirResources := range resources {
+		for resource, users := range dirResources {
+			if len(users) > 1 {
+				conflicts = append(conflicts, fmt.Sprintf("the %s is defined more than once in the directory %s : %s", resource, dir, strings.Join(users, ", ")))
+			}
+		}
+	}
+	for dir, dirNodePorts := range nodePorts {
+		for port, users := range dirNodePorts {
+			if len(users) > 1 {
+				conflicts = append(conflicts, fmt.Sprintf("the NodePort %s is used more than once in the directory %s : %s", port, dir, strings.Join(users, ", ")))
+			}
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+func addConflictUser(m map[string]map[string][]string, dir, key, user string) {
+	if m[dir] == nil {
+		m[dir] = map[string][]string{}
+	}
+	m[dir][key] = append(m[dir][key], user)
+}
@@ -0,0 +1,58 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindConflicts(t *testing.T) {
+	outputPath := t.TempDir()
+	files := map[string]string{
+		"deploy/yamls/web-service.yaml":                "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  type: NodePort\n  ports:\n    - port: 80\n      nodePort: 30080\n",
+		"deploy/yamls/api-service.yaml":                "apiVersion: v1\nkind: Service\nmetadata:\n  name: api\nspec:\n  type: NodePort\n  ports:\n    - port: 8080\n      nodePort: 30080\n",
+		"deploy/yamls/web-copy-service.yaml":           "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  ports:\n    - port: 80\n",
+		"deploy/yamls-parameterized/prod/service.yaml": "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  type: NodePort\n  ports:\n    - port: 80\n      nodePort: 30080\n",
+		"deploy/helm-chart/web/templates/service.yaml": "apiVersion: v1\nkind: Service\nmetadata:\n  name: {{ .Release.Name }}\n",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(outputPath, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("failed to create the directory for %s . Error: %q", path, err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write the file %s . Error: %q", path, err)
+		}
+	}
+	manifestFiles, err := getManifestFiles(outputPath)
+	if err != nil {
+		t.Fatalf("failed to get the manifest files. Error: %q", err)
+	}
+	conflicts := findConflicts(outputPath, manifestFiles)
+	if len(conflicts) != 2 {
+		t.Fatalf("expected 2 conflicts. Actual: %+v", conflicts)
+	}
+	if !strings.Contains(conflicts[0], "NodePort 30080") {
+		t.Errorf("expected the first conflict to be about the NodePort 30080. Actual: %s", conflicts[0])
+	}
+	if !strings.Contains(conflicts[1], "Service web") {
+		t.Errorf("expected the second conflict to be about the Service web. Actual: %s", conflicts[1])
+	}
+}
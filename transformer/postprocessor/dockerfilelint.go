@@ -0,0 +1,265 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/konveyor/move2kube/common"
+	dockerparser "github.com/moby/buildkit/frontend/dockerfile/parser"
+)
+
+const (
+	dockerfileLintError   = "error"
+	dockerfileLintWarning = "warning"
+	dockerfileLintInfo    = "info"
+	// dockerfileLintDefaultUser is the user added to Dockerfiles that don't set one
+	dockerfileLintDefaultUser = "1001"
+)
+
+var (
+	sudoRegex        = regexp.MustCompile(`(^|[;&|]\s*|\s)sudo\s`)
+	shellSplitRegex  = regexp.MustCompile(`&&|\|\||;|\|`)
+	windowsPathRegex = regexp.MustCompile(`^[A-Za-z]:`)
+	archiveExts      = []string{".tar", ".tar.gz", ".tgz", ".tar.bz2", ".tbz2", ".tar.xz", ".txz"}
+	// resolveImageDigest returns the digest the image reference currently points to
+	resolveImageDigest = func(image string) (string, error) {
+		return crane.Digest(image, crane.WithAuthFromKeychain(authn.DefaultKeychain))
+	}
+)
+
+// dockerfileLintFinding is a problem found in a Dockerfile by one of the lint rules.
+// The rule ids are the same as the equivalent hadolint rules.
+type dockerfileLintFinding struct {
+	Path    string `yaml:"path"`
+	Line    int    `yaml:"line"`
+	Rule    string `yaml:"rule"`
+	Level   string `yaml:"level"`
+	Message string `yaml:"message"`
+	Fixed   bool   `yaml:"fixed,omitempty"`
+}
+
+// dockerfileLintFix rewrites the lines of a Dockerfile to fix a finding
+type dockerfileLintFix func(lines []string) ([]string, bool)
+
+// dockerfileStage contains the state of a build stage needed by the rules that look at the whole stage
+type dockerfileStage struct {
+	lastUser        string
+	lastUserLine    int
+	cmdCount        int
+	entrypointCount int
+}
+
+// lintDockerfile runs the lint rules on the Dockerfile and returns the findings.
+// The fixes for the trivial findings are returned in the same order, nil if the finding can't be fixed automatically.
+func lintDockerfile(data []byte) ([]dockerfileLintFinding, []dockerfileLintFix, error) {
+	res, err := dockerparser.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the Dockerfile. Error: %w", err)
+	}
+	findings := []dockerfileLintFinding{}
+	fixes := []dockerfileLintFix{}
+	add := func(node *dockerparser.Node, rule, level, message string, fix dockerfileLintFix) {
+		findings = append(findings, dockerfileLintFinding{Line: node.StartLine, Rule: rule, Level: level, Message: message})
+		fixes = append(fixes, fix)
+	}
+	stageNames := []string{}
+	var stage *dockerfileStage
+	lastLine := 0
+	for _, node := range res.AST.Children {
+		lastLine = node.EndLine
+		args := []string{}
+		for n := node.Next; n != nil; n = n.Next {
+			args = append(args, n.Value)
+		}
+		switch strings.ToLower(node.Value) {
+		case "from":
+			stage = &dockerfileStage{}
+			if len(args) == 0 {
+				continue
+			}
+			image := args[0]
+			isStage := common.IsPresent(stageNames, strings.ToLower(image))
+			if len(args) >= 3 && strings.EqualFold(args[1], "as") {
+				alias := strings.ToLower(args[2])
+				if common.IsPresent(stageNames, alias) {
+					add(node, "DL3024", dockerfileLintError, fmt.Sprintf("The stage name %s is used more than once", args[2]), nil)
+				}
+				stageNames = append(stageNames, alias)
+			}
+			if isStage || image == "scratch" || strings.Contains(image, "$") || strings.Contains(image, "@") {
+				continue
+			}
+			tag := ""
+			if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+				tag = image[i+1:]
+			}
+			if tag == "" {
+				add(node, "DL3006", dockerfileLintWarning, fmt.Sprintf("Always tag the version of the image %s explicitly", image), pinImageFix(node.StartLine, image))
+			} else if tag == "latest" {
+				add(node, "DL3007", dockerfileLintWarning, fmt.Sprintf("The image %s uses the latest tag, pin it to a version or a digest", image), pinImageFix(node.StartLine, image))
+			}
+		case "maintainer":
+			maintainer := strings.Join(args, " ")
+			line := node.StartLine
+			add(node, "DL4000", dockerfileLintError, "MAINTAINER is deprecated, use a maintainer label instead", func(lines []string) ([]string, bool) {
+				lines[line-1] = "LABEL maintainer=" + strconv.Quote(maintainer)
+				return lines, true
+			})
+		case "workdir":
+			if len(args) > 0 && !strings.HasPrefix(args[0], "/") && !strings.HasPrefix(args[0], "$") && !windowsPathRegex.MatchString(args[0]) {
+				add(node, "DL3000", dockerfileLintError, fmt.Sprintf("Use an absolute WORKDIR instead of %s", args[0]), nil)
+			}
+		case "user":
+			if stage != nil && len(args) > 0 {
+				stage.lastUser = args[0]
+				stage.lastUserLine = node.StartLine
+			}
+		case "add":
+			for _, src := range sourceArgs(args) {
+				if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") || hasArchiveExt(src) {
+					continue
+				}
+				add(node, "DL3020", dockerfileLintError, fmt.Sprintf("Use COPY instead of ADD for the file or directory %s", src), nil)
+			}
+		case "cmd", "entrypoint":
+			instruction := strings.ToUpper(node.Value)
+			if !node.Attributes["json"] {
+				add(node, "DL3025", dockerfileLintWarning, fmt.Sprintf("Use the JSON notation for %s so that the process receives the OS signals", instruction), nil)
+			}
+			if stage == nil {
+				continue
+			}
+			if instruction == "CMD" {
+				stage.cmdCount++
+				if stage.cmdCount > 1 {
+					add(node, "DL4003", dockerfileLintWarning, "Only the last CMD in a stage takes effect", nil)
+				}
+			} else {
+				stage.entrypointCount++
+				if stage.entrypointCount > 1 {
+					add(node, "DL4004", dockerfileLintError, "Only the last ENTRYPOINT in a stage takes effect", nil)
+				}
+			}
+		case "run":
+			if node.Attributes["json"] || len(args) == 0 {
+				continue
+			}
+			lintRun(node, args[0], add)
+		case "expose":
+			for _, port := range args {
+				port = strings.SplitN(port, "/", 2)[0]
+				for _, p := range strings.SplitN(port, "-", 2) {
+					if n, err := strconv.Atoi(p); err == nil && (n < 0 || n > 65535) {
+						add(node, "DL3011", dockerfileLintError, fmt.Sprintf("The port %s is not a valid UNIX port", p), nil)
+					}
+				}
+			}
+		}
+	}
+	if stage != nil {
+		user := strings.SplitN(stage.lastUser, ":", 2)[0]
+		if stage.lastUser == "" {
+			node := &dockerparser.Node{StartLine: lastLine}
+			add(node, "DL3002", dockerfileLintWarning, "No USER is set so the container runs as root", func(lines []string) ([]string, bool) {
+				return append(lines, "USER "+dockerfileLintDefaultUser), true
+			})
+		} else if user == "root" || user == "0" {
+			node := &dockerparser.Node{StartLine: stage.lastUserLine}
+			add(node, "DL3002", dockerfileLintWarning, "The last USER should not be root", nil)
+		}
+	}
+	return findings, fixes, nil
+}
+
+// lintRun runs the lint rules for the shell form of the RUN instruction
+func lintRun(node *dockerparser.Node, command string, add func(*dockerparser.Node, string, string, string, dockerfileLintFix)) {
+	if sudoRegex.MatchString(command) {
+		add(node, "DL3004", dockerfileLintError, "Do not use sudo, use USER to change the user instead", nil)
+	}
+	for _, segment := range shellSplitRegex.Split(command, -1) {
+		fields := strings.Fields(segment)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "cd" {
+			add(node, "DL3003", dockerfileLintWarning, "Use WORKDIR to switch to a directory", nil)
+			continue
+		}
+		if !(common.IsPresent(fields, "apt-get") && common.IsPresent(fields, "install")) {
+			continue
+		}
+		if !common.IsPresent(fields, "-y") && !common.IsPresent(fields, "--yes") && !common.IsPresent(fields, "--assume-yes") && !common.IsPresent(fields, "-qq") {
+			add(node, "DL3014", dockerfileLintWarning, "Use the -y switch to avoid the apt-get install prompts", nil)
+		}
+		if !common.IsPresent(fields, "--no-install-recommends") {
+			add(node, "DL3015", dockerfileLintInfo, "Use --no-install-recommends to avoid installing additional packages", nil)
+		}
+	}
+}
+
+// pinImageFix returns a fix that pins the image in the FROM instruction to the digest it currently points to
+func pinImageFix(line int, image string) dockerfileLintFix {
+	return func(lines []string) ([]string, bool) {
+		digest, err := resolveImageDigest(image)
+		if err != nil {
+			return lines, false
+		}
+		lines[line-1] = strings.Replace(lines[line-1], image, image+"@"+digest, 1)
+		return lines, true
+	}
+}
+
+// fixDockerfile applies the fixes to the Dockerfile and marks the findings that were fixed
+func fixDockerfile(data []byte, findings []dockerfileLintFinding, fixes []dockerfileLintFix) ([]byte, bool) {
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	changed := false
+	for i, fix := range fixes {
+		if fix == nil {
+			continue
+		}
+		var ok bool
+		if lines, ok = fix(lines); ok {
+			findings[i].Fixed = true
+			changed = true
+		}
+	}
+	return []byte(strings.Join(lines, "\n") + "\n"), changed
+}
+
+// sourceArgs returns the sources of an ADD or COPY instruction
+func sourceArgs(args []string) []string {
+	if len(args) < 2 {
+		return nil
+	}
+	return args[:len(args)-1]
+}
+
+func hasArchiveExt(path string) bool {
+	for _, ext := range archiveExts {
+		if strings.HasSuffix(strings.ToLower(path), ext) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLintDockerfile(t *testing.T) {
+	dockerfile := `FROM golang AS builder
+MAINTAINER dev@example.com
+WORKDIR app
+RUN cd /src && sudo apt-get install curl
+ADD . /src
+ADD https://example.com/tool.tar.gz /opt/
+RUN go build -o /bin/app
+FROM builder AS test
+FROM registry.access.redhat.com/ubi8/ubi-minimal:latest
+EXPOSE 8080 70000
+COPY --from=builder /bin/app /bin/app
+CMD /bin/app
+`
+	findings, _, err := lintDockerfile([]byte(dockerfile))
+	if err != nil {
+		t.Fatalf("failed to lint the Dockerfile. Error: %q", err)
+	}
+	got := []string{}
+	for _, finding := range findings {
+		got = append(got, fmt.Sprintf("%d %s", finding.Line, finding.Rule))
+	}
+	want := []string{
+		"1 DL3006",
+		"2 DL4000",
+		"3 DL3000",
+		"4 DL3004",
+		"4 DL3003",
+		"4 DL3014",
+		"4 DL3015",
+		"5 DL3020",
+		"9 DL3007",
+		"10 DL3011",
+		"12 DL3025",
+		"12 DL3002",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("the findings are different from what was expected. Difference:\n%s", diff)
+	}
+}
+
+func TestFixDockerfile(t *testing.T) {
+	oldResolveImageDigest := resolveImageDigest
+	defer func() { resolveImageDigest = oldResolveImageDigest }()
+	resolveImageDigest = func(image string) (string, error) {
+		if image == "golang" {
+			return "sha256:1234", nil
+		}
+		return "", fmt.Errorf("the image %s was not found", image)
+	}
+	dockerfile := "FROM golang AS builder\nMAINTAINER dev@example.com\nFROM private/image:latest\nCMD [\"/bin/app\"]\n"
+	findings, fixes, err := lintDockerfile([]byte(dockerfile))
+	if err != nil {
+		t.Fatalf("failed to lint the Dockerfile. Error: %q", err)
+	}
+	fixed, changed := fixDockerfile([]byte(dockerfile), findings, fixes)
+	if !changed {
+		t.Fatalf("expected the Dockerfile to be changed")
+	}
+	want := "FROM golang@sha256:1234 AS builder\nLABEL maintainer=\"dev@example.com\"\nFROM private/image:latest\nCMD [\"/bin/app\"]\nUSER 1001\n"
+	if diff := cmp.Diff(want, string(fixed)); diff != "" {
+		t.Fatalf("the fixed Dockerfile is different from what was expected. Difference:\n%s", diff)
+	}
+	for _, finding := range findings {
+		if wantFixed := finding.Rule != "DL3007"; finding.Fixed != wantFixed {
+			t.Errorf("expected the finding %+v to have fixed set to %t", finding, wantFixed)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	"github.com/sirupsen/logrus"
+)
+
+// dockerfileLintPostprocessor lints the Dockerfiles in the output using hadolint style rules.
+// The Dockerfiles copied unchanged from the source are only linted if the user asks for it and are never modified.
+type dockerfileLintPostprocessor struct {
+	sourceDir string
+}
+
+// dockerfileLintReport contains the findings of all the Dockerfiles
+type dockerfileLintReport struct {
+	Findings []dockerfileLintFinding `yaml:"findings"`
+}
+
+func (p dockerfileLintPostprocessor) postprocess(outputPath string) error {
+	files, err := getDockerfiles(outputPath)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	if !qaengine.FetchBoolAnswer(common.ConfigDockerfileLintEnableKey, "Lint the Dockerfiles in the output?", nil, true, nil) {
+		return nil
+	}
+	includeSource := qaengine.FetchBoolAnswer(
+		common.ConfigDockerfileLintIncludeSourceKey,
+		"Lint the Dockerfiles that were copied from the source too?",
+		[]string{"By default only the generated Dockerfiles are linted"},
+		false,
+		nil,
+	)
+	autoFix := qaengine.FetchBoolAnswer(
+		common.ConfigDockerfileLintAutoFixKey,
+		"Automatically fix the trivial problems in the generated Dockerfiles?",
+		[]string{"Base images without a tag or with the latest tag are pinned to their current digest and a non-root USER is added if there is none"},
+		false,
+		nil,
+	)
+	sourceDockerfiles := map[[sha256.Size]byte]bool{}
+	if p.sourceDir != "" {
+		sourceFiles, err := getDockerfiles(p.sourceDir)
+		if err != nil {
+			logrus.Debugf("failed to find the Dockerfiles in the source directory. Error: %q", err)
+		}
+		for _, sourceFile := range sourceFiles {
+			if data, err := os.ReadFile(sourceFile); err == nil {
+				sourceDockerfiles[sha256.Sum256(data)] = true
+			}
+		}
+	}
+	report := dockerfileLintReport{Findings: []dockerfileLintFinding{}}
+	fixed := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			logrus.Errorf("failed to read the Dockerfile at path %s . Error: %q", file, err)
+			continue
+		}
+		fromSource := sourceDockerfiles[sha256.Sum256(data)]
+		if fromSource && !includeSource {
+			continue
+		}
+		findings, fixes, err := lintDockerfile(data)
+		if err != nil {
+			logrus.Debugf("failed to lint the Dockerfile at path %s . Error: %q", file, err)
+			continue
+		}
+		if autoFix && !fromSource {
+			if newData, changed := fixDockerfile(data, findings, fixes); changed {
+				if err := os.WriteFile(file, newData, common.DefaultFilePermission); err != nil {
+					logrus.Errorf("failed to write the fixed Dockerfile to path %s . Error: %q", file, err)
+				}
+			}
+		}
+		relPath, err := filepath.Rel(outputPath, file)
+		if err != nil {
+			relPath = file
+		}
+		for _, finding := range findings {
+			finding.Path = relPath
+			if finding.Fixed {
+				fixed++
+			}
+			report.Findings = append(report.Findings, finding)
+		}
+	}
+	if len(report.Findings) == 0 {
+		logrus.Infof("Linted the Dockerfiles in the output. No problems were found.")
+		return nil
+	}
+	reportPath := filepath.Join(outputPath, common.DockerfileLintReportFile)
+	if err := common.WriteYaml(reportPath, report); err != nil {
+		return fmt.Errorf("failed to write the Dockerfile lint report to the file at path %s . Error: %w", reportPath, err)
+	}
+	logrus.Warnf("Found %d problems in the Dockerfiles, %d of them were fixed automatically. See %s for details.", len(report.Findings), fixed, reportPath)
+	return nil
+}
+
+// getDockerfiles returns the Dockerfiles in the directory
+func getDockerfiles(dir string) ([]string, error) {
+	files := []string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := d.Name()
+		if name == common.DefaultDockerfileName || strings.HasPrefix(name, common.DefaultDockerfileName+".") || strings.HasSuffix(name, "."+common.DefaultDockerfileName) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk the directory %s . Error: %w", dir, err)
+	}
+	return files, nil
+}
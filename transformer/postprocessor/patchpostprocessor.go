@@ -0,0 +1,327 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/transformer/kubernetes/k8sschema"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+const (
+	// PatchesKind is the kind for the patch customization yamls
+	PatchesKind = "Patches"
+)
+
+// PatchType is the type of the patch
+type PatchType string
+
+const (
+	// StrategicMergePatchType is a strategic merge patch. This is the default.
+	StrategicMergePatchType PatchType = "StrategicMerge"
+	// JSON6902PatchType is a list of JSON patch (RFC 6902) operations
+	JSON6902PatchType PatchType = "JSON6902"
+)
+
+// PatchesFileT is the file format for the patches applied to the generated k8s yamls
+type PatchesFileT struct {
+	metav1.TypeMeta   `yaml:",inline" json:",inline"`
+	metav1.ObjectMeta `yaml:"metadata" json:"metadata"`
+	Spec              PatchesSpecT `yaml:"spec" json:"spec"`
+}
+
+// PatchesSpecT is the spec inside the patches file
+type PatchesSpecT struct {
+	Patches []PatchT `yaml:"patches" json:"patches"`
+}
+
+// PatchT is a patch along with the k8s resources it should be applied to
+type PatchT struct {
+	Type      PatchType    `yaml:"type,omitempty" json:"type,omitempty"`
+	Target    PatchTargetT `yaml:"target,omitempty" json:"target,omitempty"` // For strategic merge patches, defaults to the apiVersion, kind and name in the patch
+	Patch     interface{}  `yaml:"patch,omitempty" json:"patch,omitempty"`
+	PatchFile string       `yaml:"patchFile,omitempty" json:"patchFile,omitempty"` // Relative to the patches file
+}
+
+// PatchTargetT selects the k8s resources to patch. Empty fields match everything.
+type PatchTargetT struct {
+	APIVersion string `yaml:"apiVersion,omitempty" json:"apiVersion,omitempty"`
+	Kind       string `yaml:"kind,omitempty" json:"kind,omitempty"`
+	Name       string `yaml:"name,omitempty" json:"name,omitempty"` // Supports glob patterns
+}
+
+// patchPostprocessor applies the patches from the customizations to the generated k8s yamls
+type patchPostprocessor struct {
+}
+
+func (p patchPostprocessor) postprocess(outputPath string) error {
+	patches := getPatches()
+	if len(patches) == 0 {
+		return nil
+	}
+	return patchDeployDir(outputPath, patches)
+}
+
+// patchDeployDir applies the patches to the yamls in the deploy directory of the output.
+// The other directories are not patched since they contain the source and the CI/CD files copied as is.
+func patchDeployDir(outputPath string, patches []PatchT) error {
+	deployPath := filepath.Join(outputPath, common.DeployDir)
+	if _, err := os.Stat(deployPath); err != nil {
+		logrus.Debugf("skipping the patches since there is no deploy directory at %s . Error: %q", deployPath, err)
+		return nil
+	}
+	yamlPaths, err := common.GetFilesByExt(deployPath, []string{".yaml", ".yml"})
+	if err != nil {
+		return fmt.Errorf("failed to look for the yaml files in the deploy directory %s . Error: %w", deployPath, err)
+	}
+	for _, yamlPath := range yamlPaths {
+		yamlBytes, err := os.ReadFile(yamlPath)
+		if err != nil {
+			logrus.Errorf("failed to read the yaml file %s . Error: %q", yamlPath, err)
+			continue
+		}
+		patchedYaml, patched, err := patchYaml(string(yamlBytes), patches)
+		if err != nil {
+			logrus.Warnf("Skipping the patches for the file %s since it is not valid yaml, like the Helm templates. Error: %q", yamlPath, err)
+			continue
+		}
+		if !patched {
+			continue
+		}
+		if err := os.WriteFile(yamlPath, []byte(patchedYaml), common.DefaultFilePermission); err != nil {
+			logrus.Errorf("failed to write the patched k8s resources to the file %s . Error: %q", yamlPath, err)
+		}
+	}
+	return nil
+}
+
+// patchYaml applies the patches to each k8s resource in the multi document yaml. The documents that are not
+// k8s resources are kept as is. It returns true along with the patched yaml if any of the resources was patched.
+func patchYaml(yamlStr string, patches []PatchT) (string, bool, error) {
+	documents := []interface{}{}
+	patched := false
+	decoder := yaml.NewDecoder(strings.NewReader(yamlStr))
+	for {
+		var document interface{}
+		if err := decoder.Decode(&document); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return yamlStr, false, fmt.Errorf("failed to parse the yaml. Error: %w", err)
+		}
+		if document == nil {
+			continue
+		}
+		if resource, ok := toK8sResource(document); ok {
+			if newResource, resourcePatched := patchResource(resource, patches); resourcePatched {
+				document = newResource
+				patched = true
+			}
+		}
+		documents = append(documents, document)
+	}
+	if !patched {
+		return yamlStr, false, nil
+	}
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	for _, document := range documents {
+		if err := encoder.Encode(document); err != nil {
+			return yamlStr, false, fmt.Errorf("failed to encode the patched k8s resources to yaml. Error: %w", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return yamlStr, false, fmt.Errorf("failed to close the yaml encoder. Error: %w", err)
+	}
+	return b.String(), true, nil
+}
+
+// toK8sResource converts the yaml document to a k8s resource through json to avoid the types that json can't represent
+func toK8sResource(document interface{}) (k8sschema.K8sResourceT, bool) {
+	documentJSON, err := json.Marshal(document)
+	if err != nil {
+		return nil, false
+	}
+	resource := k8sschema.K8sResourceT{}
+	if err := json.Unmarshal(documentJSON, &resource); err != nil {
+		return nil, false
+	}
+	if _, _, _, err := k8sschema.GetInfoFromK8sResource(resource); err != nil {
+		return nil, false
+	}
+	return resource, true
+}
+
+// patchResource applies the matching patches to the k8s resource and returns true if any of them was applied
+func patchResource(resource k8sschema.K8sResourceT, patches []PatchT) (k8sschema.K8sResourceT, bool) {
+	kind, apiVersion, name, _ := k8sschema.GetInfoFromK8sResource(resource)
+	patched := false
+	for _, patch := range patches {
+		if !patch.Target.matches(apiVersion, kind, name) {
+			continue
+		}
+		newResource, err := applyPatch(resource, patch)
+		if err != nil {
+			logrus.Errorf("failed to apply the patch %+v to the %s %s . Error: %q", patch, kind, name, err)
+			continue
+		}
+		logrus.Debugf("applied a %s patch to the %s %s", patch.Type, kind, name)
+		resource = newResource
+		patched = true
+	}
+	return resource, patched
+}
+
+// getPatches collects the patches from the customizations
+func getPatches() []PatchT {
+	patches := []PatchT{}
+	yamlPaths, err := common.GetFilesByExt(common.GetCustomizationsAssetsPath(), []string{".yaml", ".yml"})
+	if err != nil {
+		logrus.Debugf("failed to look for patches in the customizations. Error: %q", err)
+	}
+	for _, yamlPath := range yamlPaths {
+		patchesFile := PatchesFileT{}
+		if err := common.ReadMove2KubeYamlStrict(yamlPath, &patchesFile, PatchesKind); err != nil {
+			continue
+		}
+		logrus.Debugf("found patches at path %s", yamlPath)
+		for _, patch := range patchesFile.Spec.Patches {
+			if patch.PatchFile != "" {
+				patchFilePath := patch.PatchFile
+				if !filepath.IsAbs(patchFilePath) {
+					patchFilePath = filepath.Join(filepath.Dir(yamlPath), patchFilePath)
+				}
+				patchBytes, err := os.ReadFile(patchFilePath)
+				if err != nil {
+					logrus.Errorf("failed to read the patch file at path %s . Error: %q", patchFilePath, err)
+					continue
+				}
+				if err := yaml.Unmarshal(patchBytes, &patch.Patch); err != nil {
+					logrus.Errorf("failed to parse the patch file at path %s . Error: %q", patchFilePath, err)
+					continue
+				}
+			}
+			if patch.Patch == nil {
+				logrus.Warnf("ignoring an empty patch in the file %s", yamlPath)
+				continue
+			}
+			if patch.Type == "" {
+				patch.Type = StrategicMergePatchType
+			}
+			if strings.EqualFold(string(patch.Type), string(StrategicMergePatchType)) {
+				patch.Type = StrategicMergePatchType
+				patch.Target = patch.Target.withDefaultsFrom(patch.Patch)
+			} else if strings.EqualFold(string(patch.Type), string(JSON6902PatchType)) {
+				patch.Type = JSON6902PatchType
+			} else {
+				logrus.Warnf("ignoring the patch with the unsupported type '%s' in the file %s . Supported types are %s and %s", patch.Type, yamlPath, StrategicMergePatchType, JSON6902PatchType)
+				continue
+			}
+			patches = append(patches, patch)
+		}
+	}
+	return patches
+}
+
+// withDefaultsFrom fills the empty fields of the target using the apiVersion, kind and name in the patch
+func (t PatchTargetT) withDefaultsFrom(patch interface{}) PatchTargetT {
+	patchMap, ok := patch.(map[string]interface{})
+	if !ok {
+		return t
+	}
+	if t.APIVersion == "" {
+		t.APIVersion, _ = patchMap["apiVersion"].(string)
+	}
+	if t.Kind == "" {
+		t.Kind, _ = patchMap["kind"].(string)
+	}
+	if metadata, ok := patchMap["metadata"].(map[string]interface{}); ok && t.Name == "" {
+		t.Name, _ = metadata["name"].(string)
+	}
+	return t
+}
+
+func (t PatchTargetT) matches(apiVersion, kind, name string) bool {
+	if t.APIVersion != "" && t.APIVersion != apiVersion {
+		return false
+	}
+	if t.Kind != "" && !strings.EqualFold(t.Kind, kind) {
+		return false
+	}
+	if t.Name != "" {
+		if matched, err := filepath.Match(t.Name, name); err != nil || !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// applyPatch applies the patch to the k8s resource and returns the patched resource
+func applyPatch(resource k8sschema.K8sResourceT, patch PatchT) (k8sschema.K8sResourceT, error) {
+	resourceJSON, err := json.Marshal(resource)
+	if err != nil {
+		return resource, fmt.Errorf("failed to marshal the k8s resource to json. Error: %w", err)
+	}
+	patchJSON, err := json.Marshal(patch.Patch)
+	if err != nil {
+		return resource, fmt.Errorf("failed to marshal the patch to json. Error: %w", err)
+	}
+	var patchedJSON []byte
+	switch patch.Type {
+	case JSON6902PatchType:
+		jsonPatch, err := jsonpatch.DecodePatch(patchJSON)
+		if err != nil {
+			return resource, fmt.Errorf("failed to decode the JSON 6902 patch. Error: %w", err)
+		}
+		if patchedJSON, err = jsonPatch.Apply(resourceJSON); err != nil {
+			return resource, fmt.Errorf("failed to apply the JSON 6902 patch. Error: %w", err)
+		}
+	default:
+		kind, apiVersion, _, _ := k8sschema.GetInfoFromK8sResource(resource)
+		if obj, err := k8sschema.GetSchema().New(schema.FromAPIVersionAndKind(apiVersion, kind)); err == nil {
+			if patchedJSON, err = strategicpatch.StrategicMergePatch(resourceJSON, patchJSON, obj); err != nil {
+				return resource, fmt.Errorf("failed to apply the strategic merge patch. Error: %w", err)
+			}
+		} else {
+			// custom resources do not have a schema with patch strategies, so fall back to a JSON merge patch
+			logrus.Debugf("no schema found for the %s %s . Applying the patch as a JSON merge patch", apiVersion, kind)
+			if patchedJSON, err = jsonpatch.MergePatch(resourceJSON, patchJSON); err != nil {
+				return resource, fmt.Errorf("failed to apply the JSON merge patch. Error: %w", err)
+			}
+		}
+	}
+	newResource := k8sschema.K8sResourceT{}
+	if err := json.Unmarshal(patchedJSON, &newResource); err != nil {
+		return resource, fmt.Errorf("failed to unmarshal the patched k8s resource. Error: %w", err)
+	}
+	return newResource, nil
+}
@@ -0,0 +1,192 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/transformer/kubernetes/k8sschema"
+)
+
+func getTestDeployment() k8sschema.K8sResourceT {
+	return k8sschema.K8sResourceT{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web"},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "web", "image": "web:latest"},
+						map[string]interface{}{"name": "sidecar", "image": "sidecar:latest"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestApplyStrategicMergePatch(t *testing.T) {
+	patch := PatchT{
+		Type: StrategicMergePatchType,
+		Patch: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "web", "resources": map[string]interface{}{"limits": map[string]interface{}{"memory": "512Mi"}}},
+						},
+					},
+				},
+			},
+		},
+	}
+	patched, err := applyPatch(getTestDeployment(), patch)
+	if err != nil {
+		t.Fatalf("failed to apply the patch. Error: %q", err)
+	}
+	want := getTestDeployment()
+	containers := want["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})
+	containers[0].(map[string]interface{})["resources"] = map[string]interface{}{"limits": map[string]interface{}{"memory": "512Mi"}}
+	if !cmp.Equal(patched, want) {
+		t.Fatalf("the patched resource is incorrect. Differences:\n%s", cmp.Diff(want, patched))
+	}
+}
+
+func TestApplyJSON6902Patch(t *testing.T) {
+	patch := PatchT{
+		Type:  JSON6902PatchType,
+		Patch: []interface{}{map[string]interface{}{"op": "remove", "path": "/spec/template/spec/containers/1"}},
+	}
+	patched, err := applyPatch(getTestDeployment(), patch)
+	if err != nil {
+		t.Fatalf("failed to apply the patch. Error: %q", err)
+	}
+	want := getTestDeployment()
+	podSpec := want["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+	podSpec["containers"] = podSpec["containers"].([]interface{})[:1]
+	if !cmp.Equal(patched, want) {
+		t.Fatalf("the patched resource is incorrect. Differences:\n%s", cmp.Diff(want, patched))
+	}
+}
+
+func TestPatchTargetMatches(t *testing.T) {
+	target := PatchTargetT{}.withDefaultsFrom(map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": map[string]interface{}{"name": "web-*"}})
+	if !target.matches("apps/v1", "Deployment", "web-frontend") {
+		t.Errorf("expected the target %+v to match the deployment web-frontend", target)
+	}
+	if target.matches("v1", "Service", "web-frontend") {
+		t.Errorf("expected the target %+v to not match the service web-frontend", target)
+	}
+}
+
+func TestPatchYaml(t *testing.T) {
+	patches := []PatchT{{
+		Type:   JSON6902PatchType,
+		Target: PatchTargetT{Kind: "Service"},
+		Patch:  []interface{}{map[string]interface{}{"op": "add", "path": "/spec/type", "value": "NodePort"}},
+	}}
+	multiDocumentYaml := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+    - port: 8080
+---
+notAResource: true
+`
+	want := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+    - port: 8080
+  type: NodePort
+---
+notAResource: true
+`
+	patched, ok, err := patchYaml(multiDocumentYaml, patches)
+	if err != nil {
+		t.Fatalf("failed to patch the yaml. Error: %q", err)
+	}
+	if !ok {
+		t.Fatalf("expected the service in the second document to be patched")
+	}
+	if patched != want {
+		t.Fatalf("the patched yaml is incorrect. Differences:\n%s", cmp.Diff(want, patched))
+	}
+
+	unmatched := []PatchT{{Type: JSON6902PatchType, Target: PatchTargetT{Kind: "Ingress"}, Patch: patches[0].Patch}}
+	if patched, ok, err := patchYaml(multiDocumentYaml, unmatched); err != nil || ok || patched != multiDocumentYaml {
+		t.Fatalf("expected the yaml to be unchanged. Actual: %t %q Error: %v", ok, patched, err)
+	}
+}
+
+func TestPatchDeployDir(t *testing.T) {
+	patches := []PatchT{{
+		Type:   JSON6902PatchType,
+		Target: PatchTargetT{Kind: "Service"},
+		Patch:  []interface{}{map[string]interface{}{"op": "add", "path": "/spec/type", "value": "NodePort"}},
+	}}
+	service := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n"
+	helmTemplate := "apiVersion: v1\nkind: Service\nmetadata:\n  name: {{ .Release.Name }}\n  {{- if .Values.labels }}\n"
+	outputPath := t.TempDir()
+	files := map[string]string{
+		filepath.Join(common.DeployDir, "yamls", "web-service.yaml"):                           service,
+		filepath.Join(common.DeployDir, "helm-charts", "app", "templates", "web-service.yaml"): helmTemplate,
+		filepath.Join(common.DefaultSourceDir, "web", "service.yaml"):                          service,
+	}
+	for path, content := range files {
+		path = filepath.Join(outputPath, path)
+		if err := os.MkdirAll(filepath.Dir(path), common.DefaultDirectoryPermission); err != nil {
+			t.Fatalf("failed to create the directory %s . Error: %q", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), common.DefaultFilePermission); err != nil {
+			t.Fatalf("failed to write the file %s . Error: %q", path, err)
+		}
+	}
+	if err := patchDeployDir(outputPath, patches); err != nil {
+		t.Fatalf("failed to patch the deploy directory. Error: %q", err)
+	}
+	for path, content := range files {
+		data, err := os.ReadFile(filepath.Join(outputPath, path))
+		if err != nil {
+			t.Fatalf("failed to read the file %s . Error: %q", path, err)
+		}
+		wantPatched := strings.HasPrefix(path, filepath.Join(common.DeployDir, "yamls"))
+		if patched := string(data) != content; patched != wantPatched {
+			t.Errorf("expected the file %s to be patched: %t Actual: %q", path, wantPatched, data)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// normalizeOutputModes sets the same modes on all the output files, keeping the executables executable
+	normalizeOutputModes = "normalize"
+	// preserveOutputModes keeps the modes the files were written or copied with, only making the scripts executable
+	preserveOutputModes = "preserve"
+	// autoOutputOwner gives the output files the owner of the directory containing the output directory
+	autoOutputOwner = "auto"
+
+	normalizedFileMode       os.FileMode = 0644
+	normalizedExecutableMode os.FileMode = 0755
+	normalizedDirectoryMode  os.FileMode = 0755
+	// executableBits are the bits added to the readable classes of a script to make it executable
+	executableBits os.FileMode = 0111
+)
+
+var scriptExts = []string{".sh", ".bash", ".ksh", ".zsh", ".py", ".pl", ".rb"}
+
+// permissionsPostprocessor makes the scripts in the output executable and applies the modes and the owner chosen by the user,
+// since the modes depend on the umask and the files copied from containers or archives, and the owner is root inside containers
+type permissionsPostprocessor struct {
+}
+
+func (p permissionsPostprocessor) postprocess(outputPath string) error {
+	modePolicy := qaengine.FetchSelectAnswer(
+		common.ConfigTargetOutputPermissionsModeKey,
+		"Select how the modes of the output files should be set",
+		[]string{
+			normalizeOutputModes + ": files get 0644, directories and executables get 0755",
+			preserveOutputModes + ": files keep their modes, scripts are made executable",
+		},
+		normalizeOutputModes,
+		[]string{normalizeOutputModes, preserveOutputModes},
+		nil,
+	)
+	owner := qaengine.FetchStringAnswer(
+		common.ConfigTargetOutputPermissionsOwnerKey,
+		"Enter the owner of the output files as uid:gid",
+		[]string{
+			"Leave empty to keep the owner of the current user",
+			autoOutputOwner + ": use the owner of the directory containing the output directory, useful when running in a container",
+		},
+		"",
+		func(answer interface{}) error {
+			owner, ok := answer.(string)
+			if !ok {
+				return fmt.Errorf("expected a string. Actual: %T", answer)
+			}
+			if owner == "" || owner == autoOutputOwner {
+				return nil
+			}
+			_, _, err := parseOwner(owner)
+			return err
+		},
+	)
+	uid, gid, chown, err := getOutputOwner(owner, outputPath)
+	if err != nil {
+		return err
+	}
+	return filepath.WalkDir(outputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat the path %s . Error: %w", path, err)
+		}
+		mode := getOutputMode(path, fi, modePolicy)
+		if mode != fi.Mode().Perm() {
+			if err := os.Chmod(path, mode); err != nil {
+				return fmt.Errorf("failed to change the mode of %s to %04o . Error: %w", path, mode, err)
+			}
+		}
+		if chown {
+			if err := os.Lchown(path, uid, gid); err != nil {
+				return fmt.Errorf("failed to change the owner of %s to %d:%d . Error: %w", path, uid, gid, err)
+			}
+		}
+		return nil
+	})
+}
+
+// getOutputMode returns the mode the path should have as per the policy
+func getOutputMode(path string, fi fs.FileInfo, policy string) os.FileMode {
+	mode := fi.Mode().Perm()
+	if fi.IsDir() {
+		if policy == normalizeOutputModes {
+			return normalizedDirectoryMode
+		}
+		return mode
+	}
+	if !fi.Mode().IsRegular() {
+		return mode
+	}
+	executable := mode&executableBits != 0 || isScript(path)
+	if policy == normalizeOutputModes {
+		if executable {
+			return normalizedExecutableMode
+		}
+		return normalizedFileMode
+	}
+	if executable {
+		// give the execute permission to everyone who can read the file
+		return mode | (mode&0444)>>2
+	}
+	return mode
+}
+
+// isScript returns true for files with a script extension or a shebang line
+func isScript(path string) bool {
+	if common.IsPresent(scriptExts, strings.ToLower(filepath.Ext(path))) {
+		return true
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		logrus.Debugf("failed to open the file %s to look for a shebang. Error: %q", path, err)
+		return false
+	}
+	defer f.Close()
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return false
+	}
+	return bytes.Equal(header, []byte("#!"))
+}
+
+// getOutputOwner returns the uid and gid the output files should be owned by and whether they need to be changed
+func getOutputOwner(owner, outputPath string) (int, int, bool, error) {
+	switch owner {
+	case "":
+		return 0, 0, false, nil
+	case autoOutputOwner:
+		parentDir := filepath.Dir(filepath.Clean(outputPath))
+		uid, gid, ok := getPathOwner(parentDir)
+		if !ok {
+			logrus.Warnf("failed to get the owner of the directory %s . The owner of the output files will not be changed.", parentDir)
+			return 0, 0, false, nil
+		}
+		return uid, gid, uid != os.Getuid() || gid != os.Getgid(), nil
+	}
+	uid, gid, err := parseOwner(owner)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	return uid, gid, true, nil
+}
+
+// parseOwner parses an owner of the form uid:gid
+func parseOwner(owner string) (int, int, error) {
+	parts := strings.Split(owner, ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("the owner '%s' is invalid. Expected uid:gid", owner)
+	}
+	uid, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || uid < 0 {
+		return 0, 0, fmt.Errorf("the uid in the owner '%s' is invalid. Expected a non negative number", owner)
+	}
+	gid, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil || gid < 0 {
+		return 0, 0, fmt.Errorf("the gid in the owner '%s' is invalid. Expected a non negative number", owner)
+	}
+	return uid, gid, nil
+}
@@ -0,0 +1,90 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetOutputMode(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]struct {
+		content string
+		mode    os.FileMode
+	}{
+		"deploy.yaml":     {content: "kind: Service\n", mode: 0600},
+		"buildimages.sh":  {content: "docker build .\n", mode: 0644},
+		"gradlew":         {content: "#!/bin/sh\n", mode: 0600},
+		"entrypoint":      {content: "binary", mode: 0700},
+		"buildimages.bat": {content: "docker build .\r\n", mode: 0664},
+	}
+	for name, file := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(file.content), file.mode); err != nil {
+			t.Fatalf("failed to write the file %s . Error: %q", name, err)
+		}
+		if err := os.Chmod(filepath.Join(dir, name), file.mode); err != nil {
+			t.Fatalf("failed to change the mode of the file %s . Error: %q", name, err)
+		}
+	}
+	testcases := []struct {
+		name   string
+		policy string
+		want   os.FileMode
+	}{
+		{name: "deploy.yaml", policy: normalizeOutputModes, want: 0644},
+		{name: "buildimages.sh", policy: normalizeOutputModes, want: 0755},
+		{name: "gradlew", policy: normalizeOutputModes, want: 0755},
+		{name: "entrypoint", policy: normalizeOutputModes, want: 0755},
+		{name: "buildimages.bat", policy: normalizeOutputModes, want: 0644},
+		{name: "deploy.yaml", policy: preserveOutputModes, want: 0600},
+		{name: "buildimages.sh", policy: preserveOutputModes, want: 0755},
+		{name: "gradlew", policy: preserveOutputModes, want: 0700},
+		{name: "entrypoint", policy: preserveOutputModes, want: 0700},
+		{name: "buildimages.bat", policy: preserveOutputModes, want: 0664},
+	}
+	for _, tc := range testcases {
+		path := filepath.Join(dir, tc.name)
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("failed to stat the file %s . Error: %q", path, err)
+		}
+		if got := getOutputMode(path, fi, tc.policy); got != tc.want {
+			t.Errorf("getOutputMode(%s, %s) = %04o , expected %04o", tc.name, tc.policy, got, tc.want)
+		}
+	}
+	fi, err := os.Stat(dir)
+	if err != nil {
+		t.Fatalf("failed to stat the directory %s . Error: %q", dir, err)
+	}
+	if got := getOutputMode(dir, fi, normalizeOutputModes); got != normalizedDirectoryMode {
+		t.Errorf("expected the directory mode to be normalized to %04o . Actual: %04o", normalizedDirectoryMode, got)
+	}
+}
+
+func TestParseOwner(t *testing.T) {
+	uid, gid, err := parseOwner("1000:100")
+	if err != nil || uid != 1000 || gid != 100 {
+		t.Fatalf("expected 1000:100 to be parsed. Actual: %d:%d Error: %v", uid, gid, err)
+	}
+	for _, owner := range []string{"1000", "user:group", "-1:0", "1:2:3"} {
+		if _, _, err := parseOwner(owner); err == nil {
+			t.Errorf("expected an error for the owner '%s'", owner)
+		}
+	}
+}
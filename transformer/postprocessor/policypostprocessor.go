@@ -0,0 +1,272 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	"github.com/konveyor/move2kube/types"
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// regoPolicyExt is the extension of the Rego policy files in the customizations
+	regoPolicyExt = ".rego"
+	// policyDenyRule is the rule containing the violations that can fail the transformation
+	policyDenyRule = "deny"
+	// policyWarnRule is the rule containing the violations that are only reported
+	policyWarnRule = "warn"
+	// serviceLabel is the label that contains the name of the service a generated resource belongs to
+	serviceLabel = types.GroupName + "/service"
+)
+
+// policyPostprocessor evaluates the Rego policies in the customizations against every generated resource.
+// Like conftest, each policy can have deny and warn rules that evaluate to a set of messages, with the resource as the input.
+type policyPostprocessor struct {
+}
+
+// policyViolation is a message returned by a deny or warn rule for a resource
+type policyViolation struct {
+	Rule     string
+	Path     string
+	Kind     string
+	Name     string
+	Message  string
+	Service  string
+	Document int
+}
+
+// policyQuery is a prepared query for a rule in a policy package
+type policyQuery struct {
+	rule  string
+	query rego.PreparedEvalQuery
+}
+
+func (p policyPostprocessor) postprocess(outputPath string) error {
+	policyPaths, err := common.GetFilesByExt(common.AssetsPath, []string{regoPolicyExt})
+	if err != nil {
+		return fmt.Errorf("failed to get the policies in %s . Error: %w", common.AssetsPath, err)
+	}
+	if len(policyPaths) == 0 {
+		return nil
+	}
+	ctx := context.Background()
+	queries, err := preparePolicyQueries(ctx, policyPaths)
+	if err != nil {
+		return err
+	}
+	files, err := getManifestFiles(outputPath)
+	if err != nil {
+		return err
+	}
+	violations := evaluatePolicies(ctx, outputPath, files, queries)
+	if len(violations) == 0 {
+		logrus.Infof("The generated resources conform to the %d policies.", len(policyPaths))
+		return nil
+	}
+	failOnViolation := qaengine.FetchBoolAnswer(
+		common.ConfigPolicyFailOnViolationKey,
+		"Should the transformation fail if the generated resources violate the deny rules of the policies?",
+		[]string{"The violations of the warn rules never fail the transformation."},
+		false,
+		nil,
+	)
+	report, denied := getPolicyReport(violations)
+	if denied && failOnViolation {
+		return fatalError{fmt.Errorf("the generated resources violate the policies:\n%s", report)}
+	}
+	logrus.Warnf("The generated resources violate the policies:\n%s", report)
+	return nil
+}
+
+// preparePolicyQueries compiles the policies and prepares the deny and warn queries of every package
+func preparePolicyQueries(ctx context.Context, policyPaths []string) ([]policyQuery, error) {
+	modules := []func(*rego.Rego){}
+	packages := []string{}
+	for _, policyPath := range policyPaths {
+		src, err := os.ReadFile(policyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the policy %s . Error: %w", policyPath, err)
+		}
+		module, err := ast.ParseModule(policyPath, string(src))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the policy %s . Error: %w", policyPath, err)
+		}
+		packages = common.AppendIfNotPresent(packages, module.Package.Path.String())
+		modules = append(modules, rego.Module(policyPath, string(src)))
+	}
+	sort.Strings(packages)
+	queries := []policyQuery{}
+	for _, pkg := range packages {
+		for _, rule := range []string{policyDenyRule, policyWarnRule} {
+			options := append([]func(*rego.Rego){rego.Query(pkg + "." + rule)}, modules...)
+			query, err := rego.New(options...).PrepareForEval(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compile the policies. Error: %w", err)
+			}
+			queries = append(queries, policyQuery{rule: rule, query: query})
+		}
+	}
+	return queries, nil
+}
+
+// evaluatePolicies evaluates the queries against every resource in the files
+func evaluatePolicies(ctx context.Context, outputPath string, files []string, queries []policyQuery) []policyViolation {
+	violations := []policyViolation{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			logrus.Debugf("failed to read the file %s . Error: %q", file, err)
+			continue
+		}
+		if strings.Contains(string(data), "{{") {
+			continue
+		}
+		docs, err := common.SplitYAML(data)
+		if err != nil {
+			logrus.Debugf("failed to split the file %s into yaml documents. Error: %q", file, err)
+			continue
+		}
+		relPath, err := filepath.Rel(outputPath, file)
+		if err != nil {
+			relPath = file
+		}
+		for i, doc := range docs {
+			header := manifestHeader{}
+			if err := yaml.Unmarshal(doc, &header); err != nil || header.APIVersion == "" || header.Kind == "" || strings.HasPrefix(header.APIVersion, types.GroupName+"/") {
+				continue
+			}
+			resource, err := getPolicyInput(doc)
+			if err != nil {
+				logrus.Debugf("failed to convert the document %d in the file %s to a policy input. Error: %q", i+1, file, err)
+				continue
+			}
+			for _, q := range queries {
+				results, err := q.query.Eval(ctx, rego.EvalInput(resource))
+				if err != nil {
+					logrus.Errorf("failed to evaluate the policies against the document %d in the file %s . Error: %q", i+1, relPath, err)
+					continue
+				}
+				for _, message := range getPolicyMessages(results) {
+					violations = append(violations, policyViolation{
+						Rule:     q.rule,
+						Path:     relPath,
+						Kind:     header.Kind,
+						Name:     header.Metadata.Name,
+						Message:  message,
+						Service:  getResourceService(resource),
+						Document: i + 1,
+					})
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// getPolicyInput converts the yaml document to a json compatible object that can be used as the policy input.
+// The json round trip avoids yaml timestamps being decoded as time.Time.
+func getPolicyInput(doc []byte) (map[string]interface{}, error) {
+	var resourceI interface{}
+	if err := yaml.Unmarshal(doc, &resourceI); err != nil {
+		return nil, err
+	}
+	resourceJSONBytes, err := json.Marshal(resourceI)
+	if err != nil {
+		return nil, err
+	}
+	resource := map[string]interface{}{}
+	err = json.Unmarshal(resourceJSONBytes, &resource)
+	return resource, err
+}
+
+// getPolicyMessages returns the messages in the results of a deny or warn query.
+// The rules can evaluate to a set of strings or a set of objects with a msg field.
+func getPolicyMessages(results rego.ResultSet) []string {
+	messages := []string{}
+	for _, result := range results {
+		for _, expression := range result.Expressions {
+			values, ok := expression.Value.([]interface{})
+			if !ok {
+				continue
+			}
+			for _, value := range values {
+				switch v := value.(type) {
+				case string:
+					messages = append(messages, v)
+				case map[string]interface{}:
+					if msg, ok := v["msg"].(string); ok {
+						messages = append(messages, msg)
+						continue
+					}
+					messages = append(messages, fmt.Sprintf("%v", v))
+				default:
+					messages = append(messages, fmt.Sprintf("%v", v))
+				}
+			}
+		}
+	}
+	return messages
+}
+
+// getResourceService returns the service the resource belongs to using the service label
+func getResourceService(resource map[string]interface{}) string {
+	metadata, _ := resource["metadata"].(map[string]interface{})
+	labels, _ := metadata["labels"].(map[string]interface{})
+	service, _ := labels[serviceLabel].(string)
+	return service
+}
+
+// getPolicyReport returns the violations grouped by service and whether any deny rules were violated
+func getPolicyReport(violations []policyViolation) (string, bool) {
+	byService := map[string][]policyViolation{}
+	services := []string{}
+	denied := false
+	for _, violation := range violations {
+		if violation.Rule == policyDenyRule {
+			denied = true
+		}
+		if _, ok := byService[violation.Service]; !ok {
+			services = append(services, violation.Service)
+		}
+		byService[violation.Service] = append(byService[violation.Service], violation)
+	}
+	sort.Strings(services)
+	report := []string{}
+	for _, service := range services {
+		if service == "" {
+			report = append(report, "resources without a service:")
+		} else {
+			report = append(report, fmt.Sprintf("service %s:", service))
+		}
+		for _, v := range byService[service] {
+			report = append(report, fmt.Sprintf("  - [%s] %s (document %d, %s %s): %s", v.Rule, v.Path, v.Document, v.Kind, v.Name, v.Message))
+		}
+	}
+	return strings.Join(report, "\n"), denied
+}
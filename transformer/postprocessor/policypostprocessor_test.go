@@ -0,0 +1,76 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEvaluatePolicies(t *testing.T) {
+	policyDir := t.TempDir()
+	policy := `package move2kube.guardrails
+
+deny[msg] {
+	input.kind == "Deployment"
+	input.spec.replicas < 2
+	msg := sprintf("the deployment %s must have at least 2 replicas", [input.metadata.name])
+}
+
+warn[{"msg": msg}] {
+	input.kind == "Service"
+	input.spec.type == "NodePort"
+	msg := "avoid NodePort services"
+}
+`
+	policyPath := filepath.Join(policyDir, "guardrails.rego")
+	if err := os.WriteFile(policyPath, []byte(policy), 0644); err != nil {
+		t.Fatalf("failed to write the policy. Error: %q", err)
+	}
+	outputPath := t.TempDir()
+	manifest := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n  labels:\n    move2kube.konveyor.io/service: web\nspec:\n  replicas: 1\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  type: NodePort\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\n  labels:\n    move2kube.konveyor.io/service: api\nspec:\n  replicas: 3\n"
+	manifestPath := filepath.Join(outputPath, "web.yaml")
+	if err := os.WriteFile(manifestPath, []byte(manifest), 0644); err != nil {
+		t.Fatalf("failed to write the manifest. Error: %q", err)
+	}
+	ctx := context.Background()
+	queries, err := preparePolicyQueries(ctx, []string{policyPath})
+	if err != nil {
+		t.Fatalf("failed to prepare the policy queries. Error: %q", err)
+	}
+	violations := evaluatePolicies(ctx, outputPath, []string{manifestPath}, queries)
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations. Actual: %+v", violations)
+	}
+	report, denied := getPolicyReport(violations)
+	if !denied {
+		t.Errorf("expected the deny rule to be violated")
+	}
+	for _, want := range []string{
+		"resources without a service:",
+		"[warn] web.yaml (document 2, Service web): avoid NodePort services",
+		"service web:",
+		"[deny] web.yaml (document 1, Deployment web): the deployment web must have at least 2 replicas",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected the report to contain %q . Actual:\n%s", want, report)
+		}
+	}
+}
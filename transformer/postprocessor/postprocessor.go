@@ -0,0 +1,57 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+// postprocessor modifies the generated output after all the transformers have run
+type postprocessor interface {
+	postprocess(outputPath string) error
+}
+
+// fatalError is returned by a postprocessor when the transformation should fail instead of continuing with a warning
+type fatalError struct {
+	error
+}
+
+// getPostprocessors returns the postprocessors in the order they should run
+func getPostprocessors(sourceDir string) []postprocessor {
+	var l = []postprocessor{&dockerfileLintPostprocessor{sourceDir: sourceDir}, new(patchPostprocessor), new(registryRewritePostprocessor), new(schemaValidationPostprocessor), new(conflictPostprocessor), new(policyPostprocessor), &todoPostprocessor{sourceDir: sourceDir}, new(permissionsPostprocessor)}
+	return l
+}
+
+// Postprocess runs all the postprocessors on the output directory
+func Postprocess(sourceDir, outputPath string) error {
+	logrus.Debug("Begin Postprocessing")
+	for _, p := range getPostprocessors(sourceDir) {
+		logrus.Debugf("[%T] Begin Postprocessing", p)
+		if err := p.postprocess(outputPath); err != nil {
+			if errors.As(err, &fatalError{}) {
+				return err
+			}
+			logrus.Warnf("[%T] Failed : %s", p, err.Error())
+		} else {
+			logrus.Debugf("[%T] Done", p)
+		}
+	}
+	logrus.Debug("Postprocessing done")
+	return nil
+}
@@ -0,0 +1,279 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RegistryRewriteRulesKind is the kind for the registry rewrite rules customization yamls
+	RegistryRewriteRulesKind = "RegistryRewriteRules"
+	// registryRewriteRuleSeparator separates the from and to parts of a rule given through QA
+	registryRewriteRuleSeparator = "="
+	dockerHubRegistry            = "docker.io"
+	dockerHubLibraryNamespace    = "library"
+	// registryPrefixProbeImage is appended to the registry url and namespace to find out how the rules rewrite them
+	registryPrefixProbeImage = "m2k-registry-probe"
+)
+
+var (
+	yamlImageLineRegex       = regexp.MustCompile(`(?m)^(\s*(?:-\s+)?image:\s*)(["']?)([^\s"'{}#$]+)(["']?)(\s*(?:#.*)?)$`)
+	dockerfileFromLineRegex  = regexp.MustCompile(`(?im)^(\s*FROM\s+(?:--\S+\s+)*)(\S+)(.*)$`)
+	dockerfileStageNameRegex = regexp.MustCompile(`(?i)\s+AS\s+(\S+)`)
+	// scriptRegistryURLRegex and scriptRegistryNamespaceRegex match the default registry of the build and push scripts
+	scriptRegistryURLRegex       = regexp.MustCompile(`(?im)^(\s*(?:SET\s+)?REGISTRY_URL=)([^\s"'{}$%]+)(\s*)$`)
+	scriptRegistryNamespaceRegex = regexp.MustCompile(`(?im)^(\s*(?:SET\s+)?REGISTRY_NAMESPACE=)([^\s"'{}$%]+)(\s*)$`)
+)
+
+// RegistryRewriteRulesFileT is the file format for the registry rewrite rules
+type RegistryRewriteRulesFileT struct {
+	metav1.TypeMeta   `yaml:",inline" json:",inline"`
+	metav1.ObjectMeta `yaml:"metadata" json:"metadata"`
+	Spec              RegistryRewriteRulesSpecT `yaml:"spec" json:"spec"`
+}
+
+// RegistryRewriteRulesSpecT is the spec inside the registry rewrite rules file
+type RegistryRewriteRulesSpecT struct {
+	Rules []RegistryRewriteRuleT `yaml:"rules" json:"rules"`
+}
+
+// RegistryRewriteRuleT rewrites image references matching From to To.
+// A trailing * matches any suffix. Example: docker.io/* -> internal-mirror.example.com/docker/*
+type RegistryRewriteRuleT struct {
+	From string `yaml:"from" json:"from"`
+	To   string `yaml:"to" json:"to"`
+}
+
+// registryRewritePostprocessor rewrites the image references in the generated yamls, Dockerfiles and scripts
+type registryRewritePostprocessor struct {
+}
+
+func (p registryRewritePostprocessor) postprocess(outputPath string) error {
+	rules := getRegistryRewriteRules()
+	if len(rules) == 0 {
+		return nil
+	}
+	return filepath.WalkDir(outputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		var rewrite func(string, []RegistryRewriteRuleT) string
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext == ".yaml" || ext == ".yml" {
+			rewrite = rewriteImagesInYaml
+		} else if isDockerfile(d.Name()) {
+			rewrite = rewriteImagesInDockerfile
+		} else if ext == ".sh" || ext == ".bat" || ext == ".ps1" {
+			rewrite = rewriteImagesInScript
+		} else {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read the file %s . Error: %w", path, err)
+		}
+		newData := rewrite(string(data), rules)
+		if newData == string(data) {
+			return nil
+		}
+		logrus.Debugf("rewrote the image references in the file %s", path)
+		fi, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat the file %s . Error: %w", path, err)
+		}
+		return os.WriteFile(path, []byte(newData), fi.Mode().Perm())
+	})
+}
+
+// getRegistryRewriteRules collects the rules from the customizations and lets the user modify them through QA
+func getRegistryRewriteRules() []RegistryRewriteRuleT {
+	rules := []RegistryRewriteRuleT{}
+	yamlPaths, err := common.GetFilesByExt(common.GetCustomizationsAssetsPath(), []string{".yaml", ".yml"})
+	if err != nil {
+		logrus.Debugf("failed to look for registry rewrite rules in the customizations. Error: %q", err)
+	}
+	for _, yamlPath := range yamlPaths {
+		rulesFile := RegistryRewriteRulesFileT{}
+		if err := common.ReadMove2KubeYamlStrict(yamlPath, &rulesFile, RegistryRewriteRulesKind); err != nil {
+			continue
+		}
+		logrus.Debugf("found registry rewrite rules at path %s", yamlPath)
+		rules = append(rules, rulesFile.Spec.Rules...)
+	}
+	defaultRules := []string{}
+	for _, rule := range rules {
+		defaultRules = append(defaultRules, rule.From+registryRewriteRuleSeparator+rule.To)
+	}
+	answer := qaengine.FetchMultilineInputAnswer(
+		common.ConfigImageRegistryRewriteRulesKey,
+		"Enter the image registry rewrite rules to apply to all the image references (one rule per line):",
+		[]string{"Each rule is of the form <from>=<to> . A trailing * matches any suffix.", "Example: docker.io/*=internal-mirror.example.com/docker/*"},
+		strings.Join(defaultRules, "\n"),
+		nil,
+	)
+	rules = []RegistryRewriteRuleT{}
+	for _, line := range strings.Split(answer, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, registryRewriteRuleSeparator, 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			logrus.Warnf("ignoring the invalid image registry rewrite rule '%s' . Expected the form <from>=<to>", line)
+			continue
+		}
+		rules = append(rules, RegistryRewriteRuleT{From: strings.TrimSpace(parts[0]), To: strings.TrimSpace(parts[1])})
+	}
+	return rules
+}
+
+func isDockerfile(filename string) bool {
+	lower := strings.ToLower(filename)
+	return strings.HasPrefix(lower, strings.ToLower(common.DefaultDockerfileName)) || strings.HasSuffix(lower, ".dockerfile")
+}
+
+func rewriteImagesInYaml(data string, rules []RegistryRewriteRuleT) string {
+	return yamlImageLineRegex.ReplaceAllStringFunc(data, func(line string) string {
+		matches := yamlImageLineRegex.FindStringSubmatch(line)
+		return matches[1] + matches[2] + rewriteImage(matches[3], rules) + matches[4] + matches[5]
+	})
+}
+
+func rewriteImagesInDockerfile(data string, rules []RegistryRewriteRuleT) string {
+	stageNames := []string{}
+	return dockerfileFromLineRegex.ReplaceAllStringFunc(data, func(line string) string {
+		matches := dockerfileFromLineRegex.FindStringSubmatch(line)
+		image := matches[2]
+		newLine := line
+		// images referring to earlier build stages, scratch and build args are left as is
+		if !strings.EqualFold(image, "scratch") && !strings.Contains(image, "$") && !common.IsPresent(stageNames, strings.ToLower(image)) {
+			newLine = matches[1] + rewriteImage(image, rules) + matches[3]
+		}
+		if stageName := dockerfileStageNameRegex.FindStringSubmatch(matches[3]); stageName != nil {
+			stageNames = append(stageNames, strings.ToLower(stageName[1]))
+		}
+		return newLine
+	})
+}
+
+// rewriteImagesInScript rewrites the default registry url and namespace that the build and push scripts tag the images with
+func rewriteImagesInScript(data string, rules []RegistryRewriteRuleT) string {
+	urlMatches := scriptRegistryURLRegex.FindStringSubmatch(data)
+	namespaceMatches := scriptRegistryNamespaceRegex.FindStringSubmatch(data)
+	if urlMatches == nil || namespaceMatches == nil {
+		return data
+	}
+	url, namespace := urlMatches[2], namespaceMatches[2]
+	newURL, newNamespace, ok := rewriteRegistry(url, namespace, rules)
+	if !ok {
+		return data
+	}
+	data = scriptRegistryURLRegex.ReplaceAllStringFunc(data, func(line string) string {
+		matches := scriptRegistryURLRegex.FindStringSubmatch(line)
+		if matches[2] != url {
+			return line
+		}
+		return matches[1] + newURL + matches[3]
+	})
+	return scriptRegistryNamespaceRegex.ReplaceAllStringFunc(data, func(line string) string {
+		matches := scriptRegistryNamespaceRegex.FindStringSubmatch(line)
+		if matches[2] != namespace {
+			return line
+		}
+		return matches[1] + newNamespace + matches[3]
+	})
+}
+
+// rewriteRegistry returns the registry url and namespace that the images in the namespace are rewritten to.
+// It returns false if the rules don't change them or if the rewritten images don't share a url and namespace.
+func rewriteRegistry(url, namespace string, rules []RegistryRewriteRuleT) (string, string, bool) {
+	image := url + "/" + namespace + "/" + registryPrefixProbeImage
+	newImage := rewriteImage(image, rules)
+	if newImage == image {
+		return url, namespace, false
+	}
+	newPrefix := strings.TrimSuffix(newImage, "/"+registryPrefixProbeImage)
+	parts := strings.SplitN(newPrefix, "/", 2)
+	if newPrefix == newImage || len(parts) != 2 {
+		logrus.Warnf("the image registry rewrite rules change the registry %s/%s of the scripts to %s which is not of the form <registry url>/<namespace>. Not rewriting the scripts.", url, namespace, newPrefix)
+		return url, namespace, false
+	}
+	return parts[0], parts[1], true
+}
+
+// rewriteImage applies the first matching rule to the image reference
+func rewriteImage(image string, rules []RegistryRewriteRuleT) string {
+	normalizedImage := normalizeImageName(image)
+	for _, rule := range rules {
+		if strings.HasSuffix(rule.From, common.MatchAll) {
+			prefix := strings.TrimSuffix(rule.From, common.MatchAll)
+			if strings.HasPrefix(normalizedImage, prefix) {
+				return strings.TrimSuffix(rule.To, common.MatchAll) + strings.TrimPrefix(normalizedImage, prefix)
+			}
+			continue
+		}
+		name, suffix := splitImageNameAndSuffix(normalizedImage)
+		if name == normalizeImageName(rule.From) {
+			return rule.To + suffix
+		}
+	}
+	return image
+}
+
+// normalizeImageName adds the implicit docker hub registry and library namespace to the image name
+func normalizeImageName(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 1 {
+		return dockerHubRegistry + "/" + dockerHubLibraryNamespace + "/" + image
+	}
+	if !strings.ContainsAny(parts[0], ".:") && parts[0] != "localhost" {
+		return dockerHubRegistry + "/" + image
+	}
+	return image
+}
+
+// splitImageNameAndSuffix splits the image into the name and the tag or digest suffix
+func splitImageNameAndSuffix(image string) (string, string) {
+	if idx := strings.Index(image, "@"); idx >= 0 {
+		return image[:idx], image[idx:]
+	}
+	lastSlash := strings.LastIndex(image, "/")
+	if idx := strings.LastIndex(image, ":"); idx > lastSlash {
+		return image[:idx], image[idx:]
+	}
+	return image, ""
+}
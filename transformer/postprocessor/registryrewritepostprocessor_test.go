@@ -0,0 +1,78 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"testing"
+)
+
+func TestRewriteImage(t *testing.T) {
+	rules := []RegistryRewriteRuleT{
+		{From: "docker.io/*", To: "mirror.example.com/docker/*"},
+		{From: "quay.io/konveyor/move2kube", To: "mirror.example.com/m2k"},
+	}
+	testcases := map[string]string{
+		"nginx":                          "mirror.example.com/docker/library/nginx",
+		"nginx:1.21":                     "mirror.example.com/docker/library/nginx:1.21",
+		"bitnami/redis@sha256:abcd":      "mirror.example.com/docker/bitnami/redis@sha256:abcd",
+		"quay.io/konveyor/move2kube:v1":  "mirror.example.com/m2k:v1",
+		"registry.example.com:5000/app":  "registry.example.com:5000/app",
+		"quay.io/konveyor/move2kube-api": "quay.io/konveyor/move2kube-api",
+	}
+	for image, want := range testcases {
+		if got := rewriteImage(image, rules); got != want {
+			t.Errorf("rewriteImage(%q) = %q , expected %q", image, got, want)
+		}
+	}
+}
+
+func TestRewriteImagesInFiles(t *testing.T) {
+	rules := []RegistryRewriteRuleT{{From: "docker.io/*", To: "mirror.example.com/*"}}
+	yaml := "spec:\n  containers:\n    - image: \"nginx:1.21\"\n      name: web\n    - name: sidecar\n      image: busybox # comment\n"
+	wantYaml := "spec:\n  containers:\n    - image: \"mirror.example.com/library/nginx:1.21\"\n      name: web\n    - name: sidecar\n      image: mirror.example.com/library/busybox # comment\n"
+	if got := rewriteImagesInYaml(yaml, rules); got != wantYaml {
+		t.Errorf("the yaml was not rewritten correctly. Expected:\n%s\nActual:\n%s", wantYaml, got)
+	}
+	multiDocumentYaml := "kind: Pod\nspec:\n  containers:\n    - image: nginx\n---\nkind: Job\nspec:\n  template:\n    spec:\n      containers:\n        - image: busybox\n"
+	wantMultiDocumentYaml := "kind: Pod\nspec:\n  containers:\n    - image: mirror.example.com/library/nginx\n---\nkind: Job\nspec:\n  template:\n    spec:\n      containers:\n        - image: mirror.example.com/library/busybox\n"
+	if got := rewriteImagesInYaml(multiDocumentYaml, rules); got != wantMultiDocumentYaml {
+		t.Errorf("the multi document yaml was not rewritten correctly. Expected:\n%s\nActual:\n%s", wantMultiDocumentYaml, got)
+	}
+	dockerfile := "FROM golang:1.18 AS builder\nRUN go build\nFROM builder AS test\nFROM --platform=linux/amd64 alpine\nFROM scratch\n"
+	wantDockerfile := "FROM mirror.example.com/library/golang:1.18 AS builder\nRUN go build\nFROM builder AS test\nFROM --platform=linux/amd64 mirror.example.com/library/alpine\nFROM scratch\n"
+	if got := rewriteImagesInDockerfile(dockerfile, rules); got != wantDockerfile {
+		t.Errorf("the Dockerfile was not rewritten correctly. Expected:\n%s\nActual:\n%s", wantDockerfile, got)
+	}
+}
+
+func TestRewriteImagesInScript(t *testing.T) {
+	rules := []RegistryRewriteRuleT{{From: "quay.io/*", To: "mirror.example.com/quay/*"}}
+	script := "REGISTRY_URL=quay.io\nREGISTRY_NAMESPACE=myproject\nif [ \"$#\" -gt 1 ]; then\n  REGISTRY_URL=$1\nfi\ndocker push ${REGISTRY_URL}/${REGISTRY_NAMESPACE}/web\n"
+	wantScript := "REGISTRY_URL=mirror.example.com\nREGISTRY_NAMESPACE=quay/myproject\nif [ \"$#\" -gt 1 ]; then\n  REGISTRY_URL=$1\nfi\ndocker push ${REGISTRY_URL}/${REGISTRY_NAMESPACE}/web\n"
+	if got := rewriteImagesInScript(script, rules); got != wantScript {
+		t.Errorf("the shell script was not rewritten correctly. Expected:\n%s\nActual:\n%s", wantScript, got)
+	}
+	batch := ":DEFAULT_REGISTRY\r\n    SET REGISTRY_URL=quay.io\r\n    SET REGISTRY_NAMESPACE=myproject\r\n"
+	wantBatch := ":DEFAULT_REGISTRY\r\n    SET REGISTRY_URL=mirror.example.com\r\n    SET REGISTRY_NAMESPACE=quay/myproject\r\n"
+	if got := rewriteImagesInScript(batch, rules); got != wantBatch {
+		t.Errorf("the batch script was not rewritten correctly. Expected:\n%q\nActual:\n%q", wantBatch, got)
+	}
+	otherRules := []RegistryRewriteRuleT{{From: "docker.io/*", To: "mirror.example.com/*"}}
+	if got := rewriteImagesInScript(script, otherRules); got != script {
+		t.Errorf("expected the script to be unchanged. Actual:\n%s", got)
+	}
+}
@@ -0,0 +1,239 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	"github.com/konveyor/move2kube/transformer/kubernetes/k8sschema"
+	"github.com/konveyor/move2kube/types"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+)
+
+const (
+	schemaValidationWarn = "warn"
+	schemaValidationFail = "fail"
+	schemaValidationSkip = "skip"
+	// defaultClusterQALabel and clusterTypeQASubKey form the key of the cluster type question asked by the cluster selector
+	defaultClusterQALabel = "default"
+	clusterTypeQASubKey   = "clustertype"
+	defaultClusterType    = "Kubernetes"
+)
+
+// schemaValidationPostprocessor validates the generated Kubernetes manifests against the schemas of the
+// Kubernetes, OpenShift and Tekton types built into move2kube and the API versions supported by the target cluster
+type schemaValidationPostprocessor struct {
+}
+
+// schemaValidationIssue is a problem found in a single document of a manifest
+type schemaValidationIssue struct {
+	Path     string
+	Document int
+	Kind     string
+	Name     string
+	Message  string
+}
+
+func (i schemaValidationIssue) String() string {
+	return fmt.Sprintf("%s (document %d, %s %s): %s", i.Path, i.Document, i.Kind, i.Name, i.Message)
+}
+
+// manifestHeader contains the fields used to identify a manifest
+type manifestHeader struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+}
+
+func (p schemaValidationPostprocessor) postprocess(outputPath string) error {
+	files, err := getManifestFiles(outputPath)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	mode := qaengine.FetchSelectAnswer(
+		common.ConfigSchemaValidationKey,
+		"How should the generated Kubernetes manifests be validated against the schemas?",
+		[]string{"warn reports the problems and continues. fail stops the transformation if there are any problems."},
+		schemaValidationWarn,
+		[]string{schemaValidationWarn, schemaValidationFail, schemaValidationSkip},
+		nil,
+	)
+	if mode == schemaValidationSkip {
+		return nil
+	}
+	cluster := getTargetClusterMetadata()
+	issues, validated := validateManifests(outputPath, files, cluster)
+	if len(issues) == 0 {
+		logrus.Infof("Validated %d Kubernetes manifests against the schemas. No problems were found.", validated)
+		return nil
+	}
+	report := []string{}
+	for _, issue := range issues {
+		report = append(report, "  - "+issue.String())
+	}
+	err = fmt.Errorf("found %d problems while validating %d Kubernetes manifests against the schemas:\n%s", len(issues), validated, strings.Join(report, "\n"))
+	if mode == schemaValidationFail {
+		return fatalError{err}
+	}
+	logrus.Warn(err)
+	return nil
+}
+
+// getManifestFiles returns the yaml files in the output directory
+func getManifestFiles(outputPath string) ([]string, error) {
+	files := []string{}
+	err := filepath.WalkDir(outputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk the output directory %s . Error: %w", outputPath, err)
+	}
+	return files, nil
+}
+
+// getTargetClusterMetadata returns the metadata of the target cluster chosen using the cluster selector
+func getTargetClusterMetadata() *collecttypes.ClusterMetadata {
+	filePaths, err := common.GetFilesByExt(common.AssetsPath, []string{".yml", ".yaml"})
+	if err != nil {
+		logrus.Debugf("failed to get the cluster metadata yamls in %s . Error: %q", common.AssetsPath, err)
+		return nil
+	}
+	clusters := map[string]collecttypes.ClusterMetadata{}
+	clusterTypes := []string{}
+	for _, filePath := range filePaths {
+		cm := collecttypes.ClusterMetadata{}
+		if err := common.ReadMove2KubeYaml(filePath, &cm); err != nil || cm.Kind != string(collecttypes.ClusterMetadataKind) {
+			continue
+		}
+		if _, ok := clusters[cm.Name]; ok {
+			continue
+		}
+		clusters[cm.Name] = cm
+		clusterTypes = append(clusterTypes, cm.Name)
+	}
+	if len(clusterTypes) == 0 {
+		return nil
+	}
+	sort.Strings(clusterTypes)
+	def := defaultClusterType
+	if !common.IsPresent(clusterTypes, def) {
+		def = clusterTypes[0]
+	}
+	clusterType := qaengine.FetchSelectAnswer(
+		common.JoinQASubKeys(common.ConfigTargetKey, `"`+defaultClusterQALabel+`"`, clusterTypeQASubKey),
+		"Choose the cluster type:",
+		[]string{"Choose the cluster type you would like to target"}, def, clusterTypes,
+		nil,
+	)
+	cluster, ok := clusters[clusterType]
+	if !ok {
+		return nil
+	}
+	return &cluster
+}
+
+// validateManifests validates all the Kubernetes manifests in the files and returns the problems found along with the number of manifests validated
+func validateManifests(outputPath string, files []string, cluster *collecttypes.ClusterMetadata) ([]schemaValidationIssue, int) {
+	scheme := k8sschema.GetSchema()
+	decoder := serializer.NewCodecFactory(scheme, serializer.EnableStrict).UniversalDeserializer()
+	issues := []schemaValidationIssue{}
+	validated := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			logrus.Debugf("failed to read the file %s . Error: %q", file, err)
+			continue
+		}
+		if strings.Contains(string(data), "{{") {
+			// Helm templates and other templates are not valid manifests until they are filled
+			continue
+		}
+		docs, err := common.SplitYAML(data)
+		if err != nil {
+			logrus.Debugf("failed to split the file %s into yaml documents. Error: %q", file, err)
+			continue
+		}
+		relPath, err := filepath.Rel(outputPath, file)
+		if err != nil {
+			relPath = file
+		}
+		for i, doc := range docs {
+			header := manifestHeader{}
+			if err := yaml.Unmarshal(doc, &header); err != nil || header.APIVersion == "" || header.Kind == "" {
+				continue
+			}
+			gv, err := schema.ParseGroupVersion(header.APIVersion)
+			if err != nil {
+				issues = append(issues, schemaValidationIssue{Path: relPath, Document: i + 1, Kind: header.Kind, Name: header.Metadata.Name, Message: err.Error()})
+				continue
+			}
+			if gv.Group == types.GroupName {
+				continue
+			}
+			validated++
+			for _, message := range validateManifest(doc, gv.WithKind(header.Kind), scheme, decoder, cluster) {
+				issues = append(issues, schemaValidationIssue{Path: relPath, Document: i + 1, Kind: header.Kind, Name: header.Metadata.Name, Message: message})
+			}
+		}
+	}
+	return issues, validated
+}
+
+// validateManifest validates a single manifest and returns the problems found
+func validateManifest(doc []byte, gvk schema.GroupVersionKind, scheme *runtime.Scheme, decoder runtime.Decoder, cluster *collecttypes.ClusterMetadata) []string {
+	problems := []string{}
+	if cluster != nil {
+		if versions, ok := cluster.Spec.APIKindVersionMap[gvk.Kind]; ok && !common.IsPresent(versions, gvk.GroupVersion().String()) {
+			problems = append(problems, fmt.Sprintf("the apiVersion %s is not supported by the target cluster %s . Supported versions: %s", gvk.GroupVersion().String(), cluster.Name, strings.Join(versions, ", ")))
+		}
+	}
+	if !scheme.Recognizes(gvk) {
+		logrus.Debugf("no schema found for %s , skipping the schema validation", gvk.String())
+		return problems
+	}
+	if _, _, err := decoder.Decode(doc, &gvk, nil); err != nil {
+		problems = append(problems, err.Error())
+	}
+	return problems
+}
@@ -0,0 +1,75 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+)
+
+func TestValidateManifests(t *testing.T) {
+	outputPath := t.TempDir()
+	files := map[string]string{
+		"deploy/yamls/web-deployment.yaml":                    "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  replicas: 2\n  selector:\n    matchLabels:\n      app: web\n  template:\n    metadata:\n      labels:\n        app: web\n    spec:\n      containers:\n        - name: web\n          image: nginx\n",
+		"deploy/yamls/web-service.yaml":                       "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  prots:\n    - port: 80\n",
+		"deploy/yamls/jobs.yaml":                              "apiVersion: batch/v1\nkind: CronJob\nmetadata:\n  name: cleanup\nspec:\n  schedule: '* * * * *'\n  jobTemplate:\n    spec:\n      template:\n        spec:\n          containers:\n            - name: cleanup\n              image: busybox\n          restartPolicy: Never\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  key: value\n",
+		"deploy/helm-chart/web/templates/web-deployment.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: {{ .Release.Name }}\n",
+		"deploy/helm-chart/web/values.yaml":                   "replicas: 2\n",
+		"m2kconfig.yaml":                                      "apiVersion: move2kube.konveyor.io/v1alpha1\nkind: QACache\nspec:\n  unknown: field\n",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(outputPath, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("failed to create the directory for %s . Error: %q", path, err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write the file %s . Error: %q", path, err)
+		}
+	}
+	manifestFiles, err := getManifestFiles(outputPath)
+	if err != nil {
+		t.Fatalf("failed to get the manifest files. Error: %q", err)
+	}
+	cluster := &collecttypes.ClusterMetadata{}
+	cluster.Name = "Kubernetes"
+	cluster.Spec.APIKindVersionMap = map[string][]string{"CronJob": {"batch/v1beta1"}, "Deployment": {"apps/v1"}}
+	issues, validated := validateManifests(outputPath, manifestFiles, cluster)
+	if validated != 4 {
+		t.Errorf("expected 4 manifests to be validated. Actual: %d", validated)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues. Actual: %+v", issues)
+	}
+	for _, issue := range issues {
+		switch issue.Kind {
+		case "Service":
+			if issue.Path != filepath.Join("deploy", "yamls", "web-service.yaml") || !strings.Contains(issue.Message, "prots") {
+				t.Errorf("expected the unknown field prots to be reported. Actual: %+v", issue)
+			}
+		case "CronJob":
+			if issue.Document != 1 || !strings.Contains(issue.Message, "batch/v1beta1") {
+				t.Errorf("expected the unsupported apiVersion to be reported. Actual: %+v", issue)
+			}
+		default:
+			t.Errorf("unexpected issue: %+v", issue)
+		}
+	}
+}
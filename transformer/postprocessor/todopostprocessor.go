@@ -0,0 +1,155 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	placeholderTODOKind = "placeholder"
+	annotationTODOKind  = "annotation"
+	commentTODOKind     = "comment"
+	// maxTODOScanFileSize is the size above which the generated files are not scanned for TODOs
+	maxTODOScanFileSize = 1024 * 1024
+)
+
+var (
+	placeholderTODORegex = regexp.MustCompile(`<TODO:\s*([^>]+)>`)
+	annotationTODORegex  = regexp.MustCompile(regexp.QuoteMeta(common.TODOAnnotation) + `([\w.-]+)["']?\s*:\s*(.*)`)
+	commentTODORegex     = regexp.MustCompile(`(?:^|\s)(?:#|//)\s*TODO\b:?\s*(.*)`)
+)
+
+// todoPostprocessor collects the placeholders, TODO annotations and TODO comments in the generated files,
+// along with the source fields that need manual attention, into a single file in the output directory
+type todoPostprocessor struct {
+	sourceDir string
+}
+
+// todoReport lists the manual actions needed to complete the output
+type todoReport struct {
+	Items []todoItem `yaml:"items"`
+}
+
+// todoItem is a manual action, along with the file and line it refers to.
+// The file is relative to the output directory, except for the source fields which are relative to the source directory.
+type todoItem struct {
+	File    string `yaml:"file"`
+	Line    int    `yaml:"line,omitempty"`
+	Kind    string `yaml:"kind"`
+	Service string `yaml:"service,omitempty"`
+	Message string `yaml:"message"`
+}
+
+func (p todoPostprocessor) postprocess(outputPath string) error {
+	items := findTODOs(outputPath)
+	for _, item := range common.GetFidelityReport(p.sourceDir).Items {
+		if item.Status == common.FidelityApproximated {
+			continue
+		}
+		message := item.Field
+		if item.Message != "" {
+			message += ": " + item.Message
+		}
+		items = append(items, todoItem{File: item.Source, Kind: string(item.Status), Service: item.Service, Message: message})
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	reportPath := filepath.Join(outputPath, common.TODOReportFile)
+	if err := common.WriteYaml(reportPath, todoReport{Items: items}); err != nil {
+		return err
+	}
+	logrus.Infof("%d manual actions are needed to complete the output. See %s for details.", len(items), reportPath)
+	return nil
+}
+
+// findTODOs returns the TODOs in the text files of the output directory, sorted by file and line
+func findTODOs(outputPath string) []todoItem {
+	items := []todoItem{}
+	reportFiles := []string{common.TODOReportFile, common.MigrationReportMarkdownFile, common.MigrationReportHTMLFile, common.QAReportMarkdownFile, common.QAReportHTMLFile}
+	if err := filepath.WalkDir(outputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != outputPath && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		relPath, err := filepath.Rel(outputPath, path)
+		if err != nil || common.IsPresent(reportFiles, relPath) {
+			return nil
+		}
+		if info, err := d.Info(); err != nil || info.Size() > maxTODOScanFileSize {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logrus.Debugf("failed to read the file %s . Error: %q", path, err)
+			return nil
+		}
+		if bytes.IndexByte(data, 0) != -1 {
+			return nil
+		}
+		items = append(items, findTODOsInFile(filepath.ToSlash(relPath), data)...)
+		return nil
+	}); err != nil {
+		logrus.Errorf("failed to walk the output directory %s . Error: %q", outputPath, err)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].File != items[j].File {
+			return items[i].File < items[j].File
+		}
+		return items[i].Line < items[j].Line
+	})
+	return items
+}
+
+func findTODOsInFile(file string, data []byte) []todoItem {
+	items := []todoItem{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxTODOScanFileSize)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if matches := placeholderTODORegex.FindAllStringSubmatch(text, -1); len(matches) > 0 {
+			for _, match := range matches {
+				items = append(items, todoItem{File: file, Line: line, Kind: placeholderTODOKind, Message: strings.TrimSpace(match[1])})
+			}
+			continue
+		}
+		if match := annotationTODORegex.FindStringSubmatch(text); match != nil {
+			items = append(items, todoItem{File: file, Line: line, Kind: annotationTODOKind, Message: strings.Trim(strings.TrimSpace(match[2]), `"'`)})
+			continue
+		}
+		if match := commentTODORegex.FindStringSubmatch(text); match != nil {
+			items = append(items, todoItem{File: file, Line: line, Kind: commentTODOKind, Message: strings.TrimSpace(match[1])})
+		}
+	}
+	return items
+}
@@ -0,0 +1,55 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package postprocessor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+)
+
+func TestFindTODOs(t *testing.T) {
+	outputPath := t.TempDir()
+	files := map[string]string{
+		"deploy/cicd/tekton/git-repo.yaml": "apiVersion: v1\nkind: Secret\nstringData:\n  known_hosts: <TODO: insert the known host keys for your git repo>\n",
+		"deploy/yamls/web-deployment.yaml": "metadata:\n  annotations:\n    " + common.TODOAnnotation + "storage: \"mount the volume manually\"\n",
+		"scripts/builddockerimages.sh":     "#!/bin/sh\n\n# TODO: login to the registry\ndocker build .\n",
+		"deploy/yamls/clean.yaml":          "kind: Service\n",
+		common.TODOReportFile:              "# TODO: this file is ignored\n",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(outputPath, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), common.DefaultDirectoryPermission); err != nil {
+			t.Fatalf("failed to create the directory for %s . Error: %q", path, err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), common.DefaultFilePermission); err != nil {
+			t.Fatalf("failed to write the file %s . Error: %q", path, err)
+		}
+	}
+	want := []todoItem{
+		{File: "deploy/cicd/tekton/git-repo.yaml", Line: 4, Kind: placeholderTODOKind, Message: "insert the known host keys for your git repo"},
+		{File: "deploy/yamls/web-deployment.yaml", Line: 3, Kind: annotationTODOKind, Message: "mount the volume manually"},
+		{File: "scripts/builddockerimages.sh", Line: 3, Kind: commentTODOKind, Message: "login to the registry"},
+	}
+	actual := findTODOs(outputPath)
+	if !cmp.Equal(actual, want) {
+		t.Fatalf("failed to find the TODOs properly. Differences:\n%s", cmp.Diff(want, actual))
+	}
+}
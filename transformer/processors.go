@@ -17,11 +17,16 @@
 package transformer
 
 import (
+	"bytes"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/konveyor/move2kube/common"
 	"github.com/konveyor/move2kube/filesystem"
+	"github.com/konveyor/move2kube/qaengine"
 	transformertypes "github.com/konveyor/move2kube/types/transformer"
 	"github.com/sirupsen/logrus"
 )
@@ -52,6 +57,7 @@ func processPathMappings(pms []transformertypes.PathMapping, sourcePath, outputP
 		}
 		copiedSourceDests[getpair(pm.SrcPath, pm.DestPath)] = true
 	}
+	licenseHeaders := getLicenseHeadersToPreserve(pms, outputPath)
 	copiedDefaultDests := map[pair]bool{}
 	for _, pm := range pms {
 		destPath := pm.DestPath
@@ -66,8 +72,13 @@ func processPathMappings(pms []transformertypes.PathMapping, sourcePath, outputP
 				logrus.Errorf("Error while copying sourcepath for %+v . Error: %q", pm, err)
 			}
 		case strings.ToLower(string(transformertypes.TemplatePathMappingType)):
-			if err := filesystem.TemplateCopy(pm.SrcPath, destPath,
-				filesystem.AddOnConfig{Config: pm.TemplateConfig}); err != nil {
+			addOnConfig := filesystem.AddOnConfig{Config: pm.TemplateConfig}
+			if pm.TemplateOptions != nil {
+				addOnConfig.OpeningDelimiter = pm.TemplateOptions.OpeningDelimiter
+				addOnConfig.ClosingDelimiter = pm.TemplateOptions.ClosingDelimiter
+				addOnConfig.VerbatimPaths = pm.TemplateOptions.VerbatimPaths
+			}
+			if err := filesystem.TemplateCopy(pm.SrcPath, destPath, addOnConfig); err != nil {
 				logrus.Errorf("Error while copying sourcepath for %+v . Error: %q", pm, err)
 			}
 		case strings.ToLower(string(transformertypes.SpecialTemplatePathMappingType)):
@@ -86,6 +97,7 @@ func processPathMappings(pms []transformertypes.PathMapping, sourcePath, outputP
 			}
 		}
 	}
+	restoreLicenseHeaders(licenseHeaders)
 
 	for _, pm := range pms {
 		if !strings.EqualFold(string(pm.Type), string(transformertypes.DeletePathMappingType)) {
@@ -104,3 +116,190 @@ func processPathMappings(pms []transformertypes.PathMapping, sourcePath, outputP
 	}
 	return nil
 }
+
+// getLicenseHeadersToPreserve returns the license headers of the existing files that will be overwritten by the path mappings
+func getLicenseHeadersToPreserve(pms []transformertypes.PathMapping, outputPath string) map[string]string {
+	licenseHeaders := map[string]string{}
+	for _, pm := range pms {
+		switch strings.ToLower(string(pm.Type)) {
+		case strings.ToLower(string(transformertypes.SourcePathMappingType)), strings.ToLower(string(transformertypes.DeletePathMappingType)):
+			continue
+		}
+		destPath := pm.DestPath
+		if !filepath.IsAbs(pm.DestPath) {
+			destPath = filepath.Join(outputPath, pm.DestPath)
+		}
+		if err := filepath.WalkDir(pm.SrcPath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			relPath, err := filepath.Rel(pm.SrcPath, path)
+			if err != nil {
+				return nil
+			}
+			destFilePath := filepath.Join(destPath, relPath)
+			if _, ok := licenseHeaders[destFilePath]; ok {
+				return nil
+			}
+			content, err := os.ReadFile(destFilePath)
+			if err != nil {
+				return nil
+			}
+			if header := common.GetLicenseHeader(string(content)); header != "" {
+				licenseHeaders[destFilePath] = header
+			}
+			return nil
+		}); err != nil {
+			logrus.Debugf("failed to walk the path %s to find the license headers to preserve. Error: %q", pm.SrcPath, err)
+		}
+	}
+	return licenseHeaders
+}
+
+// restoreLicenseHeaders adds back the license headers that were lost when the files were overwritten
+func restoreLicenseHeaders(licenseHeaders map[string]string) {
+	for path, header := range licenseHeaders {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		newContent := common.AddLicenseHeader(header, string(content))
+		if newContent == string(content) {
+			continue
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			logrus.Errorf("failed to stat the file %s . Error: %q", path, err)
+			continue
+		}
+		if err := os.WriteFile(path, []byte(newContent), fi.Mode()); err != nil {
+			logrus.Errorf("failed to restore the license header of the file %s . Error: %q", path, err)
+			continue
+		}
+		logrus.Debugf("restored the license header of the file %s", path)
+	}
+}
+
+const (
+	copyAllSourceFiles      = "all"
+	copySelectedSourceFiles = "selected"
+	copyNoSourceFiles       = "none"
+)
+
+// applySourceCopyPolicy removes the unmodified source files the user chose not to include in the output.
+// Files generated or modified by the transformers are always kept.
+func applySourceCopyPolicy(pms []transformertypes.PathMapping, sourcePath, outputPath string) {
+	sourceDests := map[string][]string{}
+	for _, pm := range pms {
+		if !strings.EqualFold(string(pm.Type), string(transformertypes.SourcePathMappingType)) {
+			continue
+		}
+		srcPath := pm.SrcPath
+		if filepath.IsAbs(pm.SrcPath) {
+			relSrcPath, err := filepath.Rel(sourcePath, pm.SrcPath)
+			if err != nil {
+				logrus.Debugf("failed to make the source path %s relative to %s . Error: %q", pm.SrcPath, sourcePath, err)
+				continue
+			}
+			srcPath = relSrcPath
+		}
+		srcPath = filepath.Clean(srcPath)
+		destPath := filepath.Join(outputPath, pm.DestPath)
+		if !common.IsPresent(sourceDests[srcPath], destPath) {
+			sourceDests[srcPath] = append(sourceDests[srcPath], destPath)
+		}
+	}
+	if len(sourceDests) == 0 {
+		return
+	}
+	srcPaths := []string{}
+	for srcPath := range sourceDests {
+		srcPaths = append(srcPaths, srcPath)
+	}
+	sort.Strings(srcPaths)
+	policy := qaengine.FetchSelectAnswer(
+		common.ConfigSourceFilesCopyPolicyKey,
+		"Select which of the original source files should be copied into the output",
+		[]string{"The files generated or modified by move2kube are always written to the output"},
+		copyAllSourceFiles,
+		[]string{copyAllSourceFiles, copySelectedSourceFiles, copyNoSourceFiles},
+		nil,
+	)
+	selectedSrcPaths := srcPaths
+	switch policy {
+	case copyAllSourceFiles:
+		return
+	case copyNoSourceFiles:
+		selectedSrcPaths = []string{}
+	case copySelectedSourceFiles:
+		selectedSrcPaths = qaengine.FetchMultiSelectAnswer(
+			common.ConfigSourceFilesSelectedKey,
+			"Select the source directories to copy into the output",
+			[]string{"The build scripts expect the source directories of the services they build"},
+			srcPaths,
+			srcPaths,
+			nil,
+		)
+	}
+	keptDests := []string{}
+	for _, srcPath := range selectedSrcPaths {
+		keptDests = append(keptDests, sourceDests[srcPath]...)
+	}
+	for _, srcPath := range srcPaths {
+		if common.IsPresent(selectedSrcPaths, srcPath) {
+			continue
+		}
+		for _, destPath := range sourceDests[srcPath] {
+			removeUnmodifiedSourceFiles(filepath.Join(sourcePath, srcPath), destPath, keptDests)
+		}
+	}
+}
+
+// removeUnmodifiedSourceFiles removes the files in the destination that are identical to the source files
+// and the directories that become empty, skipping the paths inside the destinations that are kept.
+func removeUnmodifiedSourceFiles(srcPath, destPath string, keptDests []string) {
+	dirs := []string{}
+	if err := filepath.WalkDir(srcPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		relPath, err := filepath.Rel(srcPath, path)
+		if err != nil {
+			return nil
+		}
+		destFilePath := filepath.Join(destPath, relPath)
+		for _, keptDest := range keptDests {
+			if common.IsParent(destFilePath, keptDest) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+		if d.IsDir() {
+			dirs = append(dirs, destFilePath)
+			return nil
+		}
+		srcContent, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		destContent, err := os.ReadFile(destFilePath)
+		if err != nil || !bytes.Equal(srcContent, destContent) {
+			return nil
+		}
+		if err := os.Remove(destFilePath); err != nil {
+			logrus.Errorf("failed to remove the source file %s from the output. Error: %q", destFilePath, err)
+		}
+		return nil
+	}); err != nil {
+		logrus.Errorf("failed to walk the source path %s . Error: %q", srcPath, err)
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if entries, err := os.ReadDir(dirs[i]); err == nil && len(entries) == 0 {
+			if err := os.Remove(dirs[i]); err != nil {
+				logrus.Debugf("failed to remove the empty directory %s . Error: %q", dirs[i], err)
+			}
+		}
+	}
+}
@@ -0,0 +1,316 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package sbom
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/transformer/dockerfilegenerator"
+	"github.com/konveyor/move2kube/types/source/maven"
+	dockerparser "github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/mod/modfile"
+)
+
+// ComponentTypeT is the type of a component
+type ComponentTypeT string
+
+const (
+	// LibraryComponentType is a dependency of the application
+	LibraryComponentType ComponentTypeT = "library"
+	// ContainerComponentType is a container image
+	ContainerComponentType ComponentTypeT = "container"
+)
+
+// Component is an image or package that is part of the image built for a service
+type Component struct {
+	Type    ComponentTypeT
+	Name    string
+	Version string
+	// PURL is the package url https://github.com/package-url/purl-spec
+	PURL string
+}
+
+// manifestParsers parses the dependency manifests found in the service directory
+var manifestParsers = map[string]func(string) ([]Component, error){
+	"package.json":     getNpmComponents,
+	"requirements.txt": getPipComponents,
+	"go.mod":           getGoComponents,
+	"pom.xml":          getMavenComponents,
+	"Cargo.toml":       getCargoComponents,
+	"composer.json":    getComposerComponents,
+}
+
+var requirementRegex = regexp.MustCompile(`^([A-Za-z0-9._-]+)(\[[^\]]*\])?\s*(===|==|~=)?\s*([^,;\s]*)`)
+
+// newPURL creates a package url
+func newPURL(pkgType, namespace, name, version string) string {
+	purl := "pkg:" + pkgType + "/"
+	if namespace != "" {
+		segments := []string{}
+		for _, segment := range strings.Split(namespace, "/") {
+			segments = append(segments, escapePURLSegment(segment))
+		}
+		purl += strings.Join(segments, "/") + "/"
+	}
+	purl += escapePURLSegment(name)
+	if version != "" {
+		purl += "@" + escapePURLSegment(version)
+	}
+	return purl
+}
+
+// escapePURLSegment percent encodes a segment. Unlike url.PathEscape, the @ and : separators are also encoded.
+func escapePURLSegment(segment string) string {
+	return strings.NewReplacer("@", "%40", ":", "%3A").Replace(url.PathEscape(segment))
+}
+
+// cleanVersion removes the range operators from a version constraint like ^1.2.0
+func cleanVersion(version string) string {
+	return strings.TrimLeft(strings.TrimSpace(version), "^~=<>v ")
+}
+
+// getBaseImageComponents returns the images used in the FROM instructions of the Dockerfile
+func getBaseImageComponents(dockerfilePath string) ([]Component, error) {
+	f, err := os.Open(dockerfilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the Dockerfile %s . Error: %w", dockerfilePath, err)
+	}
+	defer f.Close()
+	res, err := dockerparser.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the Dockerfile %s . Error: %w", dockerfilePath, err)
+	}
+	components := []Component{}
+	stageNames := []string{"scratch"}
+	for _, child := range res.AST.Children {
+		if !strings.EqualFold(child.Value, "from") || child.Next == nil {
+			continue
+		}
+		image := child.Next.Value
+		// an image can be built on top of an earlier stage
+		isStage := common.IsPresent(stageNames, strings.ToLower(image))
+		if child.Next.Next != nil && strings.EqualFold(child.Next.Next.Value, "as") && child.Next.Next.Next != nil {
+			stageNames = append(stageNames, strings.ToLower(child.Next.Next.Next.Value))
+		}
+		if isStage {
+			continue
+		}
+		components = append(components, newImageComponent(image))
+	}
+	return components, nil
+}
+
+func newImageComponent(image string) Component {
+	ref, digest := image, ""
+	if i := strings.Index(image, "@"); i != -1 {
+		ref, digest = image[:i], image[i+1:]
+	}
+	name, tag := ref, ""
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		name, tag = ref[:i], ref[i+1:]
+	}
+	version := tag
+	if digest != "" {
+		version = digest
+	}
+	namespace, baseName := path.Split(name)
+	return Component{
+		Type:    ContainerComponentType,
+		Name:    name,
+		Version: version,
+		PURL:    newPURL("docker", strings.TrimSuffix(namespace, "/"), baseName, version),
+	}
+}
+
+// getDependencyComponents returns the dependencies listed in the manifests in the service directory
+func getDependencyComponents(serviceDir string) []Component {
+	components := []Component{}
+	for manifestName, parse := range manifestParsers {
+		manifestPath := filepath.Join(serviceDir, manifestName)
+		if _, err := os.Stat(manifestPath); err != nil {
+			continue
+		}
+		manifestComponents, err := parse(manifestPath)
+		if err != nil {
+			logrus.Errorf("failed to get the dependencies from the file %s . Error: %q", manifestPath, err)
+			continue
+		}
+		components = append(components, manifestComponents...)
+	}
+	return sortComponents(components)
+}
+
+func getNpmComponents(manifestPath string) ([]Component, error) {
+	packageJSON := dockerfilegenerator.PackageJSON{}
+	if err := common.ReadJSON(manifestPath, &packageJSON); err != nil {
+		return nil, err
+	}
+	components := []Component{}
+	for name, version := range packageJSON.Dependencies {
+		version = cleanVersion(version)
+		namespace, baseName := "", name
+		if i := strings.Index(name, "/"); i != -1 {
+			namespace, baseName = name[:i], name[i+1:]
+		}
+		components = append(components, Component{Type: LibraryComponentType, Name: name, Version: version, PURL: newPURL("npm", namespace, baseName, version)})
+	}
+	return components, nil
+}
+
+func getPipComponents(manifestPath string) ([]Component, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the file %s . Error: %w", manifestPath, err)
+	}
+	components := []Component{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "#"); i != -1 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		matches := requirementRegex.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		name := strings.ToLower(matches[1])
+		version := ""
+		if matches[3] != "" {
+			version = matches[4]
+		}
+		components = append(components, Component{Type: LibraryComponentType, Name: name, Version: version, PURL: newPURL("pypi", "", name, version)})
+	}
+	return components, nil
+}
+
+func getGoComponents(manifestPath string) ([]Component, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the file %s . Error: %w", manifestPath, err)
+	}
+	modFile, err := modfile.Parse(manifestPath, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the go.mod file %s . Error: %w", manifestPath, err)
+	}
+	components := []Component{}
+	for _, require := range modFile.Require {
+		namespace, name := path.Split(require.Mod.Path)
+		components = append(components, Component{
+			Type:    LibraryComponentType,
+			Name:    require.Mod.Path,
+			Version: require.Mod.Version,
+			PURL:    newPURL("golang", strings.TrimSuffix(namespace, "/"), name, require.Mod.Version),
+		})
+	}
+	return components, nil
+}
+
+func getMavenComponents(manifestPath string) ([]Component, error) {
+	pom := maven.Pom{}
+	if err := pom.Load(manifestPath); err != nil {
+		return nil, err
+	}
+	components := []Component{}
+	if pom.Dependencies == nil {
+		return components, nil
+	}
+	for _, dependency := range *pom.Dependencies {
+		if dependency.Scope == "test" {
+			continue
+		}
+		version := dependency.Version
+		if strings.HasPrefix(version, "${") && strings.HasSuffix(version, "}") {
+			if value, err := pom.GetProperty(strings.TrimSuffix(strings.TrimPrefix(version, "${"), "}")); err == nil {
+				version = value
+			}
+		}
+		components = append(components, Component{
+			Type:    LibraryComponentType,
+			Name:    dependency.GroupID + ":" + dependency.ArtifactID,
+			Version: version,
+			PURL:    newPURL("maven", dependency.GroupID, dependency.ArtifactID, version),
+		})
+	}
+	return components, nil
+}
+
+func getCargoComponents(manifestPath string) ([]Component, error) {
+	cargoTOML := struct {
+		Dependencies map[string]interface{} `toml:"dependencies"`
+	}{}
+	if _, err := toml.DecodeFile(manifestPath, &cargoTOML); err != nil {
+		return nil, fmt.Errorf("failed to parse the file %s . Error: %w", manifestPath, err)
+	}
+	components := []Component{}
+	for name, value := range cargoTOML.Dependencies {
+		version := ""
+		switch value := value.(type) {
+		case string:
+			version = value
+		case map[string]interface{}:
+			version, _ = value["version"].(string)
+		}
+		version = cleanVersion(version)
+		components = append(components, Component{Type: LibraryComponentType, Name: name, Version: version, PURL: newPURL("cargo", "", name, version)})
+	}
+	return components, nil
+}
+
+func getComposerComponents(manifestPath string) ([]Component, error) {
+	composerJSON := struct {
+		Require map[string]string `json:"require"`
+	}{}
+	if err := common.ReadJSON(manifestPath, &composerJSON); err != nil {
+		return nil, err
+	}
+	components := []Component{}
+	for name, version := range composerJSON.Require {
+		// skip the platform requirements like php and ext-json
+		i := strings.Index(name, "/")
+		if i == -1 {
+			continue
+		}
+		version = cleanVersion(version)
+		components = append(components, Component{Type: LibraryComponentType, Name: name, Version: version, PURL: newPURL("composer", name[:i], name[i+1:], version)})
+	}
+	return components, nil
+}
+
+// sortComponents sorts the components by their package url and removes the duplicates
+func sortComponents(components []Component) []Component {
+	sort.Slice(components, func(i, j int) bool { return components[i].PURL < components[j].PURL })
+	uniqueComponents := []Component{}
+	for i, component := range components {
+		if i > 0 && component.PURL == components[i-1].PURL {
+			continue
+		}
+		uniqueComponents = append(uniqueComponents, component)
+	}
+	return uniqueComponents
+}
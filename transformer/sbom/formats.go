@@ -0,0 +1,183 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package sbom
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/konveyor/move2kube/types"
+	"github.com/konveyor/move2kube/types/info"
+)
+
+const (
+	cycloneDXSpecVersion = "1.4"
+	spdxVersion          = "SPDX-2.2"
+	spdxNoAssertion      = "NOASSERTION"
+	spdxDocumentID       = "SPDXRef-DOCUMENT"
+)
+
+// CycloneDXDocument is a CycloneDX json SBOM https://cyclonedx.org/docs/1.4/json/
+type CycloneDXDocument struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     CycloneDXMetadata    `json:"metadata"`
+	Components   []CycloneDXComponent `json:"components"`
+}
+
+// CycloneDXMetadata describes how and for what the SBOM was created
+type CycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     []CycloneDXTool    `json:"tools"`
+	Component CycloneDXComponent `json:"component"`
+}
+
+// CycloneDXTool is the tool that created the SBOM
+type CycloneDXTool struct {
+	Vendor  string `json:"vendor"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// CycloneDXComponent is a component in a CycloneDX SBOM
+type CycloneDXComponent struct {
+	BOMRef  string `json:"bom-ref"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	PURL    string `json:"purl,omitempty"`
+}
+
+// SPDXDocument is a SPDX json SBOM https://spdx.github.io/spdx-spec/v2.2.2/
+type SPDXDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      SPDXCreationInfo   `json:"creationInfo"`
+	Packages          []SPDXPackage      `json:"packages"`
+	Relationships     []SPDXRelationship `json:"relationships"`
+}
+
+// SPDXCreationInfo describes how the SBOM was created
+type SPDXCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+// SPDXPackage is a package in a SPDX SBOM
+type SPDXPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	ExternalRefs     []SPDXExternalRef `json:"externalRefs,omitempty"`
+}
+
+// SPDXExternalRef refers to the package using its package url
+type SPDXExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+// SPDXRelationship relates two elements in a SPDX SBOM
+type SPDXRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+func newCycloneDXComponent(component Component) CycloneDXComponent {
+	return CycloneDXComponent{BOMRef: component.PURL, Type: string(component.Type), Name: component.Name, Version: component.Version, PURL: component.PURL}
+}
+
+// newCycloneDXDocument creates a CycloneDX SBOM for the image
+func newCycloneDXDocument(subject Component, components []Component, timestamp time.Time) CycloneDXDocument {
+	doc := CycloneDXDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  cycloneDXSpecVersion,
+		SerialNumber: "urn:uuid:" + newUUID(),
+		Version:      1,
+		Metadata: CycloneDXMetadata{
+			Timestamp: timestamp.Format(time.RFC3339),
+			Tools:     []CycloneDXTool{{Vendor: "Konveyor", Name: types.AppName, Version: info.GetVersion()}},
+			Component: newCycloneDXComponent(subject),
+		},
+		Components: []CycloneDXComponent{},
+	}
+	for _, component := range components {
+		doc.Components = append(doc.Components, newCycloneDXComponent(component))
+	}
+	return doc
+}
+
+func newSPDXPackage(id string, component Component) SPDXPackage {
+	return SPDXPackage{
+		Name:             component.Name,
+		SPDXID:           id,
+		VersionInfo:      component.Version,
+		DownloadLocation: spdxNoAssertion,
+		LicenseConcluded: spdxNoAssertion,
+		LicenseDeclared:  spdxNoAssertion,
+		CopyrightText:    spdxNoAssertion,
+		ExternalRefs:     []SPDXExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: component.PURL}},
+	}
+}
+
+// newSPDXDocument creates a SPDX SBOM for the image
+func newSPDXDocument(projectName string, subject Component, components []Component, timestamp time.Time) SPDXDocument {
+	subjectID := "SPDXRef-Package-0"
+	doc := SPDXDocument{
+		SPDXVersion:       spdxVersion,
+		DataLicense:       "CC0-1.0",
+		SPDXID:            spdxDocumentID,
+		Name:              subject.Name,
+		DocumentNamespace: fmt.Sprintf("https://konveyor.io/spdxdocs/%s/%s-%s", projectName, subject.Name, newUUID()),
+		CreationInfo: SPDXCreationInfo{
+			Created:  timestamp.Format(time.RFC3339),
+			Creators: []string{"Organization: Konveyor", "Tool: " + types.AppName + "-" + info.GetVersion()},
+		},
+		Packages:      []SPDXPackage{newSPDXPackage(subjectID, subject)},
+		Relationships: []SPDXRelationship{{SPDXElementID: spdxDocumentID, RelationshipType: "DESCRIBES", RelatedSPDXElement: subjectID}},
+	}
+	for i, component := range components {
+		id := fmt.Sprintf("SPDXRef-Package-%d", i+1)
+		doc.Packages = append(doc.Packages, newSPDXPackage(id, component))
+		doc.Relationships = append(doc.Relationships, SPDXRelationship{SPDXElementID: subjectID, RelationshipType: "CONTAINS", RelatedSPDXElement: id})
+	}
+	return doc
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "00000000-0000-4000-8000-000000000000"
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,87 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package sbom
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetBaseImageComponents(t *testing.T) {
+	components, err := getBaseImageComponents(filepath.Join("testdata", "service", "Dockerfile"))
+	if err != nil {
+		t.Fatalf("failed to get the base images. Error: %q", err)
+	}
+	want := []Component{
+		{Type: ContainerComponentType, Name: "golang", Version: "1.18", PURL: "pkg:docker/golang@1.18"},
+		{Type: ContainerComponentType, Name: "registry.access.redhat.com/ubi8/ubi-minimal", Version: "latest", PURL: "pkg:docker/registry.access.redhat.com/ubi8/ubi-minimal@latest"},
+	}
+	if diff := cmp.Diff(want, components); diff != "" {
+		t.Fatalf("the base images are incorrect. Differences:\n%s", diff)
+	}
+}
+
+func TestGetDependencyComponents(t *testing.T) {
+	components := getDependencyComponents(filepath.Join("testdata", "service"))
+	purls := []string{}
+	for _, component := range components {
+		purls = append(purls, component.PURL)
+	}
+	want := []string{
+		"pkg:golang/github.com/gorilla/mux@v1.8.0",
+		"pkg:golang/github.com/sirupsen/logrus@v1.8.1",
+		"pkg:npm/%40babel/core@7.15.0",
+		"pkg:npm/express@4.17.1",
+		"pkg:pypi/flask@2.0.1",
+		"pkg:pypi/requests",
+	}
+	if diff := cmp.Diff(want, purls); diff != "" {
+		t.Fatalf("the dependencies are incorrect. Differences:\n%s", diff)
+	}
+}
+
+func TestDocuments(t *testing.T) {
+	subject := Component{Type: ContainerComponentType, Name: "myapp", Version: "latest", PURL: "pkg:docker/myapp@latest"}
+	components := []Component{{Type: LibraryComponentType, Name: "express", Version: "4.17.1", PURL: "pkg:npm/express@4.17.1"}}
+	timestamp := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cdx := newCycloneDXDocument(subject, components, timestamp)
+	if cdx.BOMFormat != "CycloneDX" || cdx.Metadata.Component.BOMRef != subject.PURL || len(cdx.Components) != 1 || cdx.Components[0].Type != "library" {
+		t.Fatalf("the CycloneDX document is incorrect. Actual: %+v", cdx)
+	}
+
+	spdx := newSPDXDocument("myproject", subject, components, timestamp)
+	if len(spdx.Packages) != 2 || len(spdx.Relationships) != 2 {
+		t.Fatalf("expected the image and its dependency in the SPDX document. Actual: %+v", spdx)
+	}
+	wantRelationship := SPDXRelationship{SPDXElementID: "SPDXRef-Package-0", RelationshipType: "CONTAINS", RelatedSPDXElement: "SPDXRef-Package-1"}
+	if diff := cmp.Diff(wantRelationship, spdx.Relationships[1]); diff != "" {
+		t.Fatalf("the SPDX relationship is incorrect. Differences:\n%s", diff)
+	}
+	if spdx.CreationInfo.Created != "2022-01-01T00:00:00Z" {
+		t.Fatalf("the SPDX creation time is incorrect. Actual: %s", spdx.CreationInfo.Created)
+	}
+}
+
+func TestGetFileName(t *testing.T) {
+	if name := GetFileName("quay.io/org/myapp:v1", CycloneDXFormat); name != "myapp.cdx.json" {
+		t.Fatalf("the file name is incorrect. Actual: %s", name)
+	}
+}
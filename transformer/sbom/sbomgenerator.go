@@ -0,0 +1,177 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/environment"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/konveyor/move2kube/types/transformer/artifacts"
+	"github.com/sirupsen/logrus"
+)
+
+// FormatT is the format of a SBOM
+type FormatT string
+
+const (
+	// CycloneDXFormat is the CycloneDX json format
+	CycloneDXFormat FormatT = "cyclonedx"
+	// SPDXFormat is the SPDX json format
+	SPDXFormat FormatT = "spdx"
+)
+
+var formatExtensions = map[FormatT]string{
+	CycloneDXFormat: ".cdx.json",
+	SPDXFormat:      ".spdx.json",
+}
+
+// SBOMGenerator implements Transformer interface
+type SBOMGenerator struct {
+	Config              transformertypes.Transformer
+	Env                 *environment.Environment
+	SBOMGeneratorConfig *SBOMGeneratorConfig
+}
+
+// SBOMGeneratorConfig stores the transformer specific configuration
+type SBOMGeneratorConfig struct {
+	OutputPath string    `yaml:"outputPath"`
+	Formats    []FormatT `yaml:"formats"`
+}
+
+// GetFileName returns the name of the SBOM file for the image
+func GetFileName(imageName string, format FormatT) string {
+	name, _ := common.GetImageNameAndTag(imageName)
+	return name + formatExtensions[format]
+}
+
+// Init Initializes the transformer
+func (t *SBOMGenerator) Init(tc transformertypes.Transformer, env *environment.Environment) (err error) {
+	t.Config = tc
+	t.Env = env
+	t.SBOMGeneratorConfig = &SBOMGeneratorConfig{}
+	if err := common.GetObjFromInterface(t.Config.Spec.Config, t.SBOMGeneratorConfig); err != nil {
+		logrus.Errorf("unable to load config for Transformer %+v into %T : %s", t.Config.Spec.Config, t.SBOMGeneratorConfig, err)
+		return err
+	}
+	if t.SBOMGeneratorConfig.OutputPath == "" {
+		t.SBOMGeneratorConfig.OutputPath = common.SBOMDir
+	}
+	if len(t.SBOMGeneratorConfig.Formats) == 0 {
+		t.SBOMGeneratorConfig.Formats = []FormatT{CycloneDXFormat, SPDXFormat}
+	}
+	for _, format := range t.SBOMGeneratorConfig.Formats {
+		if _, ok := formatExtensions[format]; !ok {
+			return fmt.Errorf("the SBOM format %s is not supported. Supported formats are %s and %s", format, CycloneDXFormat, SPDXFormat)
+		}
+	}
+	return nil
+}
+
+// GetConfig returns the transformer config
+func (t *SBOMGenerator) GetConfig() (transformertypes.Transformer, *environment.Environment) {
+	return t.Config, t.Env
+}
+
+// DirectoryDetect runs detect in each sub directory
+func (t *SBOMGenerator) DirectoryDetect(dir string) (namedServices map[string][]transformertypes.Artifact, err error) {
+	return nil, nil
+}
+
+// Transform generates a SBOM for the image built from each Dockerfile
+func (t *SBOMGenerator) Transform(newArtifacts []transformertypes.Artifact, alreadySeenArtifacts []transformertypes.Artifact) ([]transformertypes.PathMapping, []transformertypes.Artifact, error) {
+	tempDir := filepath.Join(t.Env.TempPath, "sbom-"+common.GetRandomString())
+	if err := os.MkdirAll(tempDir, common.DefaultDirectoryPermission); err != nil {
+		return nil, nil, fmt.Errorf("failed to create the directory %s . Error: %q", tempDir, err)
+	}
+	timestamp := time.Now().UTC()
+	processedImages := map[string]bool{}
+	for _, artifact := range append(alreadySeenArtifacts, newArtifacts...) {
+		if artifact.Type != artifacts.DockerfileArtifactType {
+			continue
+		}
+		dockerfilePaths := artifact.Paths[artifacts.DockerfilePathType]
+		if len(dockerfilePaths) == 0 {
+			continue
+		}
+		imageName := artifacts.ImageName{}
+		if err := artifact.GetConfig(artifacts.ImageNameConfigType, &imageName); err != nil {
+			logrus.Errorf("unable to load the imagename config from the artifact %+v . Error: %q", artifact, err)
+		}
+		if imageName.ImageName == "" {
+			imageName.ImageName = common.MakeStringContainerImageNameCompliant(artifact.Name)
+		}
+		if processedImages[imageName.ImageName] {
+			continue
+		}
+		processedImages[imageName.ImageName] = true
+		serviceDir := filepath.Dir(dockerfilePaths[0])
+		if serviceDirs := artifact.Paths[artifacts.ServiceDirPathType]; len(serviceDirs) > 0 {
+			serviceDir = serviceDirs[0]
+		}
+		components := []Component{}
+		for _, dockerfilePath := range dockerfilePaths {
+			baseImages, err := getBaseImageComponents(dockerfilePath)
+			if err != nil {
+				logrus.Errorf("failed to get the base images from the Dockerfile %s . Error: %q", dockerfilePath, err)
+				continue
+			}
+			components = append(components, baseImages...)
+		}
+		components = sortComponents(append(components, getDependencyComponents(serviceDir)...))
+		name, tag := common.GetImageNameAndTag(imageName.ImageName)
+		subject := Component{Type: ContainerComponentType, Name: name, Version: tag, PURL: newPURL("docker", "", name, tag)}
+		for _, format := range t.SBOMGeneratorConfig.Formats {
+			var doc interface{}
+			switch format {
+			case CycloneDXFormat:
+				doc = newCycloneDXDocument(subject, components, timestamp)
+			case SPDXFormat:
+				doc = newSPDXDocument(t.Env.GetProjectName(), subject, components, timestamp)
+			}
+			sbomPath := filepath.Join(tempDir, GetFileName(imageName.ImageName, format))
+			if err := writeDocument(sbomPath, doc); err != nil {
+				logrus.Errorf("failed to write the %s SBOM for the image %s . Error: %q", format, imageName.ImageName, err)
+			}
+		}
+		logrus.Debugf("generated the SBOM for the image %s with %d components", imageName.ImageName, len(components))
+	}
+	if len(processedImages) == 0 {
+		return nil, nil, nil
+	}
+	return []transformertypes.PathMapping{{
+		Type:     transformertypes.DefaultPathMappingType,
+		SrcPath:  tempDir,
+		DestPath: t.SBOMGeneratorConfig.OutputPath,
+	}}, nil, nil
+}
+
+func writeDocument(path string, doc interface{}) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the SBOM to json. Error: %w", err)
+	}
+	if err := os.WriteFile(path, data, common.DefaultFilePermission); err != nil {
+		return fmt.Errorf("failed to write the SBOM to the file %s . Error: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+module example.com/service
+
+go 1.18
+
+require (
+	github.com/gorilla/mux v1.8.0
+	github.com/sirupsen/logrus v1.8.1
+)
@@ -17,6 +17,7 @@
 package transformer
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -26,8 +27,14 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/deepcopy"
+	"github.com/konveyor/move2kube/common/eventbus"
+	"github.com/konveyor/move2kube/common/i18n"
+	"github.com/konveyor/move2kube/common/tracing"
 	"github.com/konveyor/move2kube/environment"
 	containertypes "github.com/konveyor/move2kube/environment/container"
 	"github.com/konveyor/move2kube/filesystem"
@@ -40,6 +47,8 @@ import (
 	"github.com/konveyor/move2kube/transformer/dockerfilegenerator/windows"
 	"github.com/konveyor/move2kube/transformer/external"
 	"github.com/konveyor/move2kube/transformer/kubernetes"
+	"github.com/konveyor/move2kube/transformer/postprocessor"
+	"github.com/konveyor/move2kube/transformer/sbom"
 	"github.com/konveyor/move2kube/types"
 	environmenttypes "github.com/konveyor/move2kube/types/environment"
 	graphtypes "github.com/konveyor/move2kube/types/graph"
@@ -48,6 +57,7 @@ import (
 	"github.com/konveyor/move2kube/types/transformer/artifacts"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cast"
+	"go.opentelemetry.io/otel/attribute"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
@@ -80,12 +90,20 @@ var (
 	transformers                 = []Transformer{}
 	invokedByDefaultTransformers = []Transformer{}
 	transformerMap               = map[string]Transformer{}
+	lastGraph                    *graphtypes.Graph
+	// transformerMutexes make each transformer process one directory or set of artifacts at a time while running in parallel
+	transformerMutexes sync.Map // [name]*sync.Mutex
+	// graphMutex guards the graph that the transformers running in parallel add their vertices and edges to
+	graphMutex sync.Mutex
 )
 
 func init() {
 	transformerObjs := []Transformer{
 		new(external.Starlark),
 		new(external.Executable),
+		new(external.WASM),
+		new(external.HTTP),
+		new(external.Python),
 
 		new(Router),
 
@@ -118,6 +136,8 @@ func init() {
 		new(CloudFoundry),
 
 		new(containerimage.ContainerImagesPushScript),
+		new(containerimage.ImageSigning),
+		new(sbom.SBOMGenerator),
 
 		new(kubernetes.ClusterSelectorTransformer),
 		new(kubernetes.Kubernetes),
@@ -148,7 +168,7 @@ func RegisterTransformer(tf Transformer) error {
 }
 
 // Init initializes the transformers
-func Init(assetsPath, sourcePath string, selector labels.Selector, outputPath, projName string) (map[string]string, error) {
+func Init(ctx context.Context, assetsPath, sourcePath string, selector labels.Selector, outputPath, projName string) (map[string]string, error) {
 	filePaths, err := common.GetFilesByExt(assetsPath, []string{".yml", ".yaml"})
 	if err != nil {
 		return nil, fmt.Errorf("failed to look for yaml files in the directory %s . Error: %q", assetsPath, err)
@@ -157,6 +177,11 @@ func Init(assetsPath, sourcePath string, selector labels.Selector, outputPath, p
 	for _, filePath := range filePaths {
 		tc, err := getTransformerConfig(filePath)
 		if err != nil {
+			schemaErr := &transformertypes.TransformerSchemaError{}
+			if errors.As(err, &schemaErr) {
+				logrus.Errorf("ignoring the transformer config since it is invalid. Error: %s", err)
+				continue
+			}
 			logrus.Debugf("failed to load the transformer config file at path %s . Error: %q", filePath, err)
 			continue
 		}
@@ -165,7 +190,7 @@ func Init(assetsPath, sourcePath string, selector labels.Selector, outputPath, p
 		}
 		transformerFiles[tc.Name] = filePath
 	}
-	deselectedTransformers, err := InitTransformers(transformerFiles, selector, sourcePath, outputPath, projName, false, false)
+	deselectedTransformers, err := InitTransformers(ctx, transformerFiles, selector, sourcePath, outputPath, projName, false, false)
 	if err != nil {
 		return deselectedTransformers, fmt.Errorf("failed to initialize the transformers. Error: %q", err)
 	}
@@ -173,10 +198,12 @@ func Init(assetsPath, sourcePath string, selector labels.Selector, outputPath, p
 }
 
 // InitTransformers initializes a subset of transformers
-func InitTransformers(transformerToInit map[string]string, selector labels.Selector, sourcePath, outputPath, projName string, logError, preExistingPlan bool) (map[string]string, error) {
+func InitTransformers(ctx context.Context, transformerToInit map[string]string, selector labels.Selector, sourcePath, outputPath, projName string, logError, preExistingPlan bool) (map[string]string, error) {
 	if initialized {
 		return nil, nil
 	}
+	ctx, span := tracing.Start(ctx, "InitTransformers")
+	defer span.End()
 	transformerFilterString := qaengine.FetchStringAnswer(
 		common.TransformerSelectorKey,
 		"Specify a Kubernetes style selector to select only the transformers that you want to run.",
@@ -228,70 +255,173 @@ func InitTransformers(transformerToInit map[string]string, selector labels.Selec
 			deselectedTransformers[t] = transformerToInit[t]
 		}
 	}
-	for _, selectedTransformerName := range selectedTransformerNames {
-		transformerConfig, ok := transformerConfigs[selectedTransformerName]
+	// the environments (and so the container images) of the transformers are created in parallel
+	initializedTransformers := make([]Transformer, len(selectedTransformerNames))
+	initErrs := make([]error, len(selectedTransformerNames))
+	common.RunInParallel(len(selectedTransformerNames), func(i int) {
+		transformerConfig, ok := transformerConfigs[selectedTransformerNames[i]]
 		if !ok {
-			logrus.Errorf("failed to find the transformer with the name: '%s'", selectedTransformerName)
-			continue
+			logrus.Errorf("failed to find the transformer with the name: '%s'", selectedTransformerNames[i])
+			return
 		}
-		transformerClass, ok := transformerTypes[transformerConfig.Spec.Class]
-		if !ok {
-			logrus.Errorf("failed to find the transformer class %s . Valid transformer classes are: %+v", transformerConfig.Spec.Class, transformerTypes)
+		initializedTransformers[i], initErrs[i] = initTransformer(ctx, transformerConfig, sourcePath, outputPath, projName, preExistingPlan)
+	})
+	for i, selectedTransformerName := range selectedTransformerNames {
+		if err := initErrs[i]; err != nil {
+			destroyTransformers(initializedTransformers[i+1:])
+			tracing.End(span, err)
+			return deselectedTransformers, err
+		}
+		transformer := initializedTransformers[i]
+		if transformer == nil {
 			continue
 		}
-		transformer := reflect.New(transformerClass).Interface().(Transformer)
-		transformerContextPath := filepath.Dir(transformerConfig.Spec.FilePath)
-		envInfo := environment.EnvInfo{
-			Name:            transformerConfig.Name,
-			ProjectName:     projName,
-			Isolated:        transformerConfig.Spec.Isolated,
-			Source:          sourcePath,
-			Output:          outputPath,
-			Context:         transformerContextPath,
-			RelTemplatesDir: transformerConfig.Spec.TemplatesDir,
-			EnvPlatformConfig: environmenttypes.EnvPlatformConfig{
-				Container: environmenttypes.Container{},
-				Platforms: []string{runtime.GOOS},
-			},
+		transformerConfig := transformerConfigs[selectedTransformerName]
+		transformers = append(transformers, transformer)
+		transformerMap[selectedTransformerName] = transformer
+		if transformerConfig.Spec.InvokedByDefault.Enabled {
+			invokedByDefaultTransformers = append(invokedByDefaultTransformers, transformer)
 		}
-		for src, dest := range transformerConfig.Spec.ExternalFiles {
-			if err := filesystem.Replicate(filepath.Join(transformerContextPath, src), filepath.Join(transformerContextPath, dest)); err != nil {
-				logrus.Errorf(
-					"failed to copy external files for transformer '%s' from source path '%s' to destination path '%s' . Error: %q",
-					transformerConfig.Name, src, dest, err,
-				)
-			}
+	}
+	initialized = true
+	return deselectedTransformers, nil
+}
+
+// initTransformer creates the environment of the transformer and initializes the transformer.
+// The error is only returned if the environment can't be created, the transformer is nil if it fails to initialize.
+func initTransformer(ctx context.Context, transformerConfig transformertypes.Transformer, sourcePath, outputPath, projName string, preExistingPlan bool) (Transformer, error) {
+	transformerClass, ok := transformerTypes[transformerConfig.Spec.Class]
+	if !ok {
+		logrus.Errorf("failed to find the transformer class %s . Valid transformer classes are: %+v", transformerConfig.Spec.Class, transformerTypes)
+		return nil, nil
+	}
+	transformer := reflect.New(transformerClass).Interface().(Transformer)
+	transformerContextPath := filepath.Dir(transformerConfig.Spec.FilePath)
+	envInfo := environment.EnvInfo{
+		Name:            transformerConfig.Name,
+		ProjectName:     projName,
+		Isolated:        transformerConfig.Spec.Isolated,
+		Source:          sourcePath,
+		Output:          outputPath,
+		Context:         transformerContextPath,
+		RelTemplatesDir: transformerConfig.Spec.TemplatesDir,
+		TemplateOptions: transformerConfig.Spec.TemplateOptions,
+		EnvPlatformConfig: environmenttypes.EnvPlatformConfig{
+			Container: environmenttypes.Container{},
+			Platforms: []string{runtime.GOOS},
+		},
+	}
+	for src, dest := range transformerConfig.Spec.ExternalFiles {
+		if err := filesystem.Replicate(filepath.Join(transformerContextPath, src), filepath.Join(transformerContextPath, dest)); err != nil {
+			logrus.Errorf(
+				"failed to copy external files for transformer '%s' from source path '%s' to destination path '%s' . Error: %q",
+				transformerConfig.Name, src, dest, err,
+			)
+		}
+	}
+	// the questions of the transformer can be localized using the catalogs in its locales directory
+	if err := i18n.AddCatalogs(filepath.Join(transformerContextPath, i18n.CatalogsDirName)); err != nil {
+		logrus.Errorf("failed to load the message catalogs of the transformer '%s' . Error: %q", transformerConfig.Name, err)
+	}
+	if preExistingPlan {
+		if v, ok := transformerConfig.Labels["move2kube.konveyor.io/container-based"]; ok && cast.ToBool(v) {
+			envInfo.SpawnContainers = true
+		}
+	}
+	_, envSpan := tracing.Start(ctx, "SetupEnvironment", attribute.String("transformer", transformerConfig.Name), attribute.Bool("spawnContainers", envInfo.SpawnContainers))
+	env, err := environment.NewEnvironment(envInfo, nil)
+	if err != nil {
+		err = fmt.Errorf("failed to create the environment %+v . Error: %w", envInfo, err)
+		tracing.End(envSpan, err)
+		return nil, err
+	}
+	if err := transformer.Init(transformerConfig, env); err != nil {
+		tracing.End(envSpan, err)
+		if errors.Is(err, containertypes.ErrNoContainerRuntime) {
+			logrus.Debugf("failed to initialize the transformer '%s' . Error: %q", transformerConfig.Name, err)
+		} else {
+			logrus.Errorf("failed to initialize the transformer '%s' . Error: %q", transformerConfig.Name, err)
 		}
-		if preExistingPlan {
-			if v, ok := transformerConfig.Labels["move2kube.konveyor.io/container-based"]; ok && cast.ToBool(v) {
-				envInfo.SpawnContainers = true
+		return nil, nil
+	}
+	envSpan.End()
+	return transformer, nil
+}
+
+// ReloadTransformers reads the configs of the initialized transformers whose directories contain any of the changed paths
+// again and replaces the transformers with newly initialized ones. It returns the names of the reloaded transformers.
+// A transformer that fails to reload is kept as it was.
+func ReloadTransformers(ctx context.Context, changedPaths []string, sourcePath, outputPath, projName string, preExistingPlan bool) []string {
+	reloaded := []string{}
+	for i, oldTransformer := range transformers {
+		oldConfig, oldEnv := oldTransformer.GetConfig()
+		transformerDir := filepath.Dir(oldConfig.Spec.FilePath)
+		changed := false
+		for _, changedPath := range changedPaths {
+			if changedPath == transformerDir || common.IsParent(changedPath, transformerDir) {
+				changed = true
+				break
 			}
 		}
-		env, err := environment.NewEnvironment(envInfo, nil)
+		if !changed {
+			continue
+		}
+		newConfig, err := getTransformerConfig(oldConfig.Spec.FilePath)
 		if err != nil {
-			return deselectedTransformers, fmt.Errorf("failed to create the environment %+v . Error: %w", envInfo, err)
+			logrus.Errorf("failed to reload the transformer '%s' from %s . Error: %q", oldConfig.Name, oldConfig.Spec.FilePath, err)
+			continue
 		}
-		if err := transformer.Init(transformerConfig, env); err != nil {
-			if errors.Is(err, containertypes.ErrNoContainerRuntime) {
-				logrus.Debugf("failed to initialize the transformer '%s' . Error: %q", transformerConfig.Name, err)
-			} else {
-				logrus.Errorf("failed to initialize the transformer '%s' . Error: %q", transformerConfig.Name, err)
-			}
+		if newConfig.Name != oldConfig.Name {
+			logrus.Errorf("the transformer '%s' at %s was renamed to '%s' . Plan again to use the renamed transformer.", oldConfig.Name, oldConfig.Spec.FilePath, newConfig.Name)
 			continue
 		}
-		transformers = append(transformers, transformer)
-		transformerMap[selectedTransformerName] = transformer
-		if transformerConfig.Spec.InvokedByDefault.Enabled {
-			invokedByDefaultTransformers = append(invokedByDefaultTransformers, transformer)
+		newTransformer, err := initTransformer(ctx, newConfig, sourcePath, outputPath, projName, preExistingPlan)
+		if err != nil {
+			logrus.Errorf("failed to reload the transformer '%s' . Error: %q", oldConfig.Name, err)
+			continue
+		}
+		if newTransformer == nil {
+			continue
+		}
+		if err := oldEnv.Destroy(); err != nil {
+			logrus.Errorf("failed to destroy the environment of the transformer '%s' . Error: %q", oldConfig.Name, err)
+		}
+		transformers[i] = newTransformer
+		transformerMap[newConfig.Name] = newTransformer
+		newInvokedByDefaultTransformers := []Transformer{}
+		for _, t := range invokedByDefaultTransformers {
+			if t != oldTransformer {
+				newInvokedByDefaultTransformers = append(newInvokedByDefaultTransformers, t)
+			}
+		}
+		if newConfig.Spec.InvokedByDefault.Enabled {
+			newInvokedByDefaultTransformers = append(newInvokedByDefaultTransformers, newTransformer)
 		}
+		invokedByDefaultTransformers = newInvokedByDefaultTransformers
+		reloaded = append(reloaded, newConfig.Name)
+		logrus.Infof("Reloaded the transformer '%s'", newConfig.Name)
 	}
-	initialized = true
-	return deselectedTransformers, nil
+	return reloaded
+}
+
+// lockTransformer waits until no other goroutine is using the transformer and returns the function to release it
+func lockTransformer(name string) func() {
+	mutex, _ := transformerMutexes.LoadOrStore(name, &sync.Mutex{})
+	mutex.(*sync.Mutex).Lock()
+	return mutex.(*sync.Mutex).Unlock
 }
 
 // Destroy destroys the transformers
 func Destroy() {
-	for _, t := range transformers {
+	destroyTransformers(transformers)
+}
+
+// destroyTransformers destroys the environments of the transformers, skipping the nil ones
+func destroyTransformers(ts []Transformer) {
+	for _, t := range ts {
+		if t == nil {
+			continue
+		}
 		_, env := t.GetConfig()
 		if err := env.Destroy(); err != nil {
 			logrus.Errorf("Unable to destroy environment : %s", err)
@@ -329,7 +459,9 @@ func GetInitializedTransformersF(filters labels.Selector) []Transformer {
 }
 
 // GetServices returns the list of services detected in a directory
-func GetServices(prjName string, dir string) (map[string][]plantypes.PlanArtifact, error) {
+func GetServices(ctx context.Context, prjName string, dir string) (map[string][]plantypes.PlanArtifact, error) {
+	ctx, span := tracing.Start(ctx, "DetectServices", attribute.String("directory", dir))
+	defer span.End()
 	planServices := map[string][]plantypes.PlanArtifact{}
 	logrus.Infoln("Planning started on the base directory")
 	logrus.Debugf("Transformers: %+v", transformers)
@@ -343,9 +475,16 @@ func GetServices(prjName string, dir string) (map[string][]plantypes.PlanArtifac
 			continue
 		}
 		logrus.Infof("[%s] Planning", config.Name)
+		common.SetLogContext(common.LogContext{Transformer: config.Name})
+		common.SetTransformerProgress(config.Name, common.TransformerRunning)
+		_, detectSpan := tracing.Start(ctx, "DirectoryDetect", attribute.String("transformer", config.Name), attribute.String("directory", dir))
+		common.TakeDetectionReasons()
 		newServices, err := transformer.DirectoryDetect(env.Encode(dir).(string))
+		tracing.End(detectSpan, err)
+		explainDetection(dir, dir, config.Name, newServices, err)
 		if err != nil {
 			logrus.Errorf("[%s] failed to look for services in the directory '%s' . Error: %q", config.Name, dir, err)
+			common.SetTransformerProgress(config.Name, common.TransformerFailed)
 			continue
 		}
 		newPlanServices := getPlanArtifactsFromArtifacts(*env.Decode(&newServices).(*map[string][]transformertypes.Artifact), config)
@@ -354,12 +493,14 @@ func GetServices(prjName string, dir string) (map[string][]plantypes.PlanArtifac
 			logrus.Infof(getNamedAndUnNamedServicesLogMessage(newPlanServices))
 		}
 		common.PlanProgressNumBaseDetectTransformers++
+		common.SetTransformerProgress(config.Name, common.TransformerDone)
 		logrus.Infof("[%s] Done", config.Name)
 	}
+	common.SetLogContext(common.LogContext{})
 	logrus.Infof("[Base Directory] %s", getNamedAndUnNamedServicesLogMessage(planServices))
 	logrus.Infoln("Planning finished on the base directory")
 	logrus.Infoln("Planning started on its sub directories")
-	nservices, err := walkForServices(dir, planServices)
+	nservices, err := walkForServices(ctx, dir, planServices)
 	if err != nil {
 		logrus.Errorf("Transformation planning - Directory Walk failed : %s", err)
 	} else {
@@ -372,12 +513,12 @@ func GetServices(prjName string, dir string) (map[string][]plantypes.PlanArtifac
 	return planServices, nil
 }
 
-func walkForServices(inputPath string, bservices map[string][]plantypes.PlanArtifact) (map[string][]plantypes.PlanArtifact, error) {
+func walkForServices(ctx context.Context, inputPath string, bservices map[string][]plantypes.PlanArtifact) (map[string][]plantypes.PlanArtifact, error) {
 	services := bservices
 	ignoreDirectories, ignoreContents := getIgnorePaths(inputPath)
 	knownServiceDirPaths := []string{}
 
-	err := filepath.WalkDir(inputPath, func(path string, info os.DirEntry, err error) error {
+	err := common.WalkDir(inputPath, func(path string, info os.DirEntry, err error) error {
 		if err != nil {
 			logrus.Warnf("Skipping path %q due to error. Error: %q", path, err)
 			return nil
@@ -387,37 +528,55 @@ func walkForServices(inputPath string, bservices map[string][]plantypes.PlanArti
 		}
 		for _, dirRegExp := range common.DefaultIgnoreDirRegexps {
 			if dirRegExp.Match([]byte(filepath.Base(path))) {
+				explainSkippedDirectory(inputPath, path, "the directory name matches the default ignore pattern "+dirRegExp.String())
 				return filepath.SkipDir
 			}
 		}
+		if pattern := common.GetExcludedDirPattern(inputPath, path); pattern != "" {
+			explainSkippedDirectory(inputPath, path, "the directory matches the excluded pattern "+pattern)
+			return filepath.SkipDir
+		}
+		if common.IsOverDetectDepth(inputPath, path) {
+			maxDepth, _ := common.GetDetectionLimits()
+			explainSkippedDirectory(inputPath, path, fmt.Sprintf("the directory is more than %d levels below the source directory", maxDepth))
+			return filepath.SkipDir
+		}
 		if common.IsPresent(knownServiceDirPaths, path) {
+			explainSkippedDirectory(inputPath, path, "the directory is part of a service detected in a parent directory")
 			return filepath.SkipDir // TODO: Should we go inside the directory in this case?
 		}
 		if common.IsPresent(ignoreDirectories, path) {
 			if common.IsPresent(ignoreContents, path) {
+				explainSkippedDirectory(inputPath, path, "the directory and its contents are ignored by a "+common.IgnoreFilename+" file")
 				return filepath.SkipDir
 			}
+			explainSkippedDirectory(inputPath, path, "the directory is ignored by a "+common.IgnoreFilename+" file")
 			return nil
 		}
 		common.PlanProgressNumDirectories++
 		logrus.Debugf("Planning in directory %s", path)
+		dirCtx, dirSpan := tracing.Start(ctx, "DetectServicesInDirectory", attribute.String("directory", path))
+		defer dirSpan.End()
 		numfound := 0
 		skipThisDir := false
-		for _, transformer := range transformers {
-			config, env := transformer.GetConfig()
-			logrus.Debugf("[%s] Planning in directory %s", config.Name, path)
-			if err := env.Reset(); err != nil {
-				logrus.Errorf("failed to reset the environment for the transformer %s . Error: %q", config.Name, err)
-				continue
-			}
-			if config.Spec.DirectoryDetect.Levels == 1 || config.Spec.DirectoryDetect.Levels == 0 {
-				continue
+		detections := make([]directoryDetection, len(transformers))
+		detect := func(i int) { detections[i] = detectInDirectory(dirCtx, inputPath, path, transformers[i]) }
+		if common.IsExplainDetectionEnabled() {
+			// the reasons recorded for the explanation are not per transformer, so the transformers detect one at a time
+			for i := range transformers {
+				detect(i)
 			}
-			newServicesToArtifacts, err := transformer.DirectoryDetect(env.Encode(path).(string))
-			if err != nil {
-				logrus.Warnf("[%s] directory detect failed. Error: %q", config.Name, err)
+		} else {
+			common.RunInParallel(len(transformers), detect)
+		}
+		// the detected services are merged in the order of the transformers, irrespective of the order they finished in
+		for i, transformer := range transformers {
+			if detections[i].skipped {
 				continue
 			}
+			newServicesToArtifacts := detections[i].services
+			config, env := transformer.GetConfig()
+			common.SetLogContext(common.LogContext{Transformer: config.Name})
 			for _, newServiceArtifacts := range newServicesToArtifacts {
 				for _, newServiceArtifact := range newServiceArtifacts {
 					knownServiceDirPaths = append(knownServiceDirPaths, newServiceArtifact.Paths[artifacts.ServiceDirPathType]...)
@@ -444,6 +603,7 @@ func walkForServices(inputPath string, bservices map[string][]plantypes.PlanArti
 				logrus.Infof("%s in %s", msg, relpath)
 			}
 		}
+		common.SetLogContext(common.LogContext{})
 		logrus.Debugf("planning finished for the directory %s and %d services were detected", path, numfound)
 		if skipThisDir || common.IsPresent(ignoreContents, path) {
 			return filepath.SkipDir
@@ -456,6 +616,38 @@ func walkForServices(inputPath string, bservices map[string][]plantypes.PlanArti
 	return services, nil
 }
 
+// directoryDetection is the result of a transformer detecting the services in a directory
+type directoryDetection struct {
+	// skipped is true if the transformer does not detect in sub directories or if the detection failed
+	skipped  bool
+	services map[string][]transformertypes.Artifact
+}
+
+// detectInDirectory runs the directory detection of the transformer on the directory
+func detectInDirectory(ctx context.Context, sourceDir, dir string, transformer Transformer) directoryDetection {
+	config, env := transformer.GetConfig()
+	defer lockTransformer(config.Name)()
+	common.SetLogContext(common.LogContext{Transformer: config.Name})
+	logrus.Debugf("[%s] Planning in directory %s", config.Name, dir)
+	if err := env.Reset(); err != nil {
+		logrus.Errorf("failed to reset the environment for the transformer %s . Error: %q", config.Name, err)
+		return directoryDetection{skipped: true}
+	}
+	if config.Spec.DirectoryDetect.Levels == 1 || config.Spec.DirectoryDetect.Levels == 0 {
+		return directoryDetection{skipped: true}
+	}
+	_, detectSpan := tracing.Start(ctx, "DirectoryDetect", attribute.String("transformer", config.Name), attribute.String("directory", dir))
+	common.TakeDetectionReasons()
+	services, err := transformer.DirectoryDetect(env.Encode(dir).(string))
+	tracing.End(detectSpan, err)
+	explainDetection(sourceDir, dir, config.Name, services, err)
+	if err != nil {
+		logrus.Warnf("[%s] directory detect failed. Error: %q", config.Name, err)
+		return directoryDetection{skipped: true}
+	}
+	return directoryDetection{services: services}
+}
+
 func summarizeArtifacts(artifacts []transformertypes.Artifact) []string {
 	arts := []string{}
 	for _, a := range artifacts {
@@ -472,8 +664,32 @@ func summarizePathMappings(pathMappings []transformertypes.PathMapping) string {
 	return strings.Join(paths, "\n")
 }
 
+func getPathMappingInfos(pathMappings []transformertypes.PathMapping) []graphtypes.PathMappingInfo {
+	infos := []graphtypes.PathMappingInfo{}
+	for _, pathMapping := range pathMappings {
+		infos = append(infos, graphtypes.PathMappingInfo{Type: string(pathMapping.Type), SrcPath: pathMapping.SrcPath, DestPath: pathMapping.DestPath})
+	}
+	return infos
+}
+
+func getArtifactInfo(artifact transformertypes.Artifact) graphtypes.ArtifactInfo {
+	info := graphtypes.ArtifactInfo{Name: artifact.Name, Type: string(artifact.Type)}
+	if len(artifact.Paths) != 0 {
+		info.Paths = map[string][]string{}
+		for pathType, paths := range artifact.Paths {
+			info.Paths[string(pathType)] = paths
+		}
+	}
+	return info
+}
+
+// GetGraph returns the graph of transformers and artifacts created by the last transformation
+func GetGraph() *graphtypes.Graph {
+	return lastGraph
+}
+
 // Transform transforms as per the plan
-func Transform(planArtifacts []plantypes.PlanArtifact, sourceDir, outputPath string) error {
+func Transform(ctx context.Context, planArtifacts []plantypes.PlanArtifact, sourceDir, outputPath string) error {
 	var allArtifacts []transformertypes.Artifact
 	newArtifactsToProcess := []transformertypes.Artifact{}
 	pathMappings := []transformertypes.PathMapping{}
@@ -481,15 +697,20 @@ func Transform(planArtifacts []plantypes.PlanArtifact, sourceDir, outputPath str
 	iteration := 1
 	// transform default transformers
 	graph := graphtypes.NewGraph()
+	lastGraph = graph
 	startVertexId := graph.AddVertex("start", iteration, nil)
-	for _, invokedByDefaultTransformer := range invokedByDefaultTransformers {
-		tDefaultConfig, defaultEnv := invokedByDefaultTransformer.GetConfig()
-		newPathMappings, defaultArtifacts, err := runSingleTransform(nil, nil, invokedByDefaultTransformer, tDefaultConfig, defaultEnv, graph, iteration)
+	defaultResults := make([]transformResult, len(invokedByDefaultTransformers))
+	common.RunInParallel(len(invokedByDefaultTransformers), func(i int) {
+		tDefaultConfig, defaultEnv := invokedByDefaultTransformers[i].GetConfig()
+		newPathMappings, defaultArtifacts, err := runSingleTransform(ctx, nil, nil, invokedByDefaultTransformers[i], tDefaultConfig, defaultEnv, graph, iteration)
 		if err != nil {
 			logrus.Errorf("failed to transform using the transformer %s. Error: %q", tDefaultConfig.Name, err)
 		}
-		defaultNewArtifactsToProcess = append(defaultNewArtifactsToProcess, defaultArtifacts...)
-		pathMappings = append(pathMappings, newPathMappings...)
+		defaultResults[i] = transformResult{pathMappings: newPathMappings, newArtifactsCreated: defaultArtifacts}
+	})
+	for _, defaultResult := range defaultResults {
+		defaultNewArtifactsToProcess = append(defaultNewArtifactsToProcess, defaultResult.newArtifactsCreated...)
+		pathMappings = append(pathMappings, defaultResult.pathMappings...)
 	}
 	logrus.Infof("Iteration %d", iteration)
 	for _, planArtifact := range planArtifacts {
@@ -521,14 +742,20 @@ func Transform(planArtifacts []plantypes.PlanArtifact, sourceDir, outputPath str
 	for {
 		iteration++
 		logrus.Infof("Iteration %d - %d artifacts to process", iteration, len(newArtifactsToProcess))
-		newPathMappings, newArtifacts, _ := transform(newArtifactsToProcess, allArtifacts, consume, nil, graph, iteration)
+		iterationCtx, iterationSpan := tracing.Start(ctx, "TransformIteration", attribute.Int("iteration", iteration), attribute.Int("artifacts", len(newArtifactsToProcess)))
+		newPathMappings, newArtifacts, _ := transform(iterationCtx, newArtifactsToProcess, allArtifacts, consume, nil, graph, iteration)
 		pathMappings = append(pathMappings, newPathMappings...)
 		if err := os.RemoveAll(outputPath); err != nil {
-			return fmt.Errorf("failed to remove the output directory %s . Error: %q", outputPath, err)
+			err = fmt.Errorf("failed to remove the output directory %s . Error: %q", outputPath, err)
+			tracing.End(iterationSpan, err)
+			return err
 		}
-		if err := processPathMappings(pathMappings, sourceDir, outputPath); err != nil {
-			return fmt.Errorf("failed to process the path mappings: %+v . Error: %q", pathMappings, err)
+		if err := processPathMappingsWithSpan(iterationCtx, pathMappings, sourceDir, outputPath); err != nil {
+			err = fmt.Errorf("failed to process the path mappings: %+v . Error: %q", pathMappings, err)
+			tracing.End(iterationSpan, err)
+			return err
 		}
+		iterationSpan.End()
 		if len(newArtifacts) == 0 {
 			break
 		}
@@ -536,6 +763,24 @@ func Transform(planArtifacts []plantypes.PlanArtifact, sourceDir, outputPath str
 		allArtifacts = append(allArtifacts, newArtifacts...)
 		newArtifactsToProcess = newArtifacts
 	}
+	applySourceCopyPolicy(pathMappings, sourceDir, outputPath)
+	applyOutputLayout(planArtifacts, sourceDir, outputPath)
+	if report := common.GetFidelityReport(sourceDir); len(report.Items) > 0 {
+		reportPath := filepath.Join(outputPath, common.FidelityReportFile)
+		if err := common.WriteYaml(reportPath, report); err != nil {
+			logrus.Errorf("failed to write the fidelity report to %s . Error: %q", reportPath, err)
+		} else {
+			logrus.Infof("%d fields in the source were dropped, approximated or need manual attention. See %s for details.", len(report.Items), reportPath)
+		}
+	}
+	common.SetProgressPhase(common.ProgressPhasePostprocessing)
+	_, postprocessSpan := tracing.Start(ctx, "Postprocess")
+	if err := postprocessor.Postprocess(sourceDir, outputPath); err != nil {
+		err = fmt.Errorf("failed to postprocess the output directory %s . Error: %w", outputPath, err)
+		tracing.End(postprocessSpan, err)
+		return err
+	}
+	postprocessSpan.End()
 
 	// logging
 	{
@@ -556,89 +801,165 @@ func Transform(planArtifacts []plantypes.PlanArtifact, sourceDir, outputPath str
 	return nil
 }
 
-func transform(newArtifactsToProcess, allArtifacts []transformertypes.Artifact, pt processType, depSel labels.Selector, graph *graphtypes.Graph, iteration int) (pathMappings []transformertypes.PathMapping, newArtifactsCreated, updatedArtifacts []transformertypes.Artifact) {
+func transform(ctx context.Context, newArtifactsToProcess, allArtifacts []transformertypes.Artifact, pt processType, depSel labels.Selector, graph *graphtypes.Graph, iteration int) (pathMappings []transformertypes.PathMapping, newArtifactsCreated, updatedArtifacts []transformertypes.Artifact) {
 	if pt == dependency && (depSel == nil || depSel.String() == "") {
 		return nil, nil, newArtifactsToProcess
 	}
+	if pt == consume {
+		// the transformers consume the artifacts independently of each other, so they run in parallel.
+		// The results are merged in the order of the transformers, irrespective of the order they finished in.
+		results := make([]transformResult, len(transformers))
+		common.RunInParallel(len(transformers), func(i int) {
+			artifactsToProcess := newArtifactsToProcess
+			if common.GetParallelism() > 1 {
+				// the configs of the artifacts are merged in place, so each transformer gets its own copy
+				artifactsToProcess = deepcopy.DeepCopy(newArtifactsToProcess).([]transformertypes.Artifact)
+			}
+			results[i] = transformUsing(ctx, transformers[i], artifactsToProcess, allArtifacts, pt, graph, iteration)
+		})
+		for _, result := range results {
+			pathMappings = append(pathMappings, result.pathMappings...)
+			newArtifactsCreated = append(newArtifactsCreated, result.newArtifactsCreated...)
+		}
+		logrus.Debugf("Created %d pathMappings and %d artifacts from transform.", len(pathMappings), len(newArtifactsCreated))
+		return pathMappings, newArtifactsCreated, nil
+	}
+	// while passing through or processing the dependencies, each transformer gets the artifacts updated by the previous ones
 	for _, transformer := range transformers {
-		tConfig, env := transformer.GetConfig()
+		tConfig, _ := transformer.GetConfig()
 		if pt == dependency && !depSel.Matches(labels.Set(tConfig.Labels)) {
 			continue
 		}
-		artifactsToProcess, artifactsToNotProcess := getArtifactsToProcess(newArtifactsToProcess, allArtifacts, tConfig, pt)
-		if len(artifactsToProcess) == 0 {
-			continue
+		result := transformUsing(ctx, transformer, newArtifactsToProcess, allArtifacts, pt, graph, iteration)
+		pathMappings = append(pathMappings, result.pathMappings...)
+		newArtifactsCreated = append(newArtifactsCreated, result.newArtifactsCreated...)
+		if result.processed {
+			newArtifactsToProcess = result.artifactsToProcess
 		}
+	}
+	logrus.Debugf("Created %d pathMappings, %d artifacts, %d updated artifacts from transform while passing through/dependency.", len(pathMappings), len(newArtifactsCreated), len(newArtifactsToProcess))
+	return pathMappings, newArtifactsCreated, newArtifactsToProcess
+}
 
-		logrus.Debugf("Transformer %s will be processing %d artifacts in %d mode", tConfig.Name, len(artifactsToProcess), pt)
+// transformResult is the result of a transformer processing the artifacts, along with the transformers they were passed through
+type transformResult struct {
+	// processed is true if the transformer processed the artifacts successfully
+	processed           bool
+	pathMappings        []transformertypes.PathMapping
+	newArtifactsCreated []transformertypes.Artifact
+	// artifactsToProcess are the artifacts left for the next transformers while passing through or processing the dependencies
+	artifactsToProcess []transformertypes.Artifact
+}
 
-		// Dependency processing
-		dependencyCreatedNewPathMappings, dependencyCreatedNewArtifacts, dependencyUpdatedArtifacts := transform(artifactsToProcess, allArtifacts, dependency, tConfig.Spec.DependencySelector, graph, iteration)
-		pathMappings = append(pathMappings, dependencyCreatedNewPathMappings...)
-		// Dependency processing
+// transformUsing processes the dependencies of the artifacts that the transformer can process, processes the artifacts
+// using the transformer and passes the produced artifacts through the other transformers
+func transformUsing(ctx context.Context, transformer Transformer, newArtifactsToProcess, allArtifacts []transformertypes.Artifact, pt processType, graph *graphtypes.Graph, iteration int) (result transformResult) {
+	tConfig, env := transformer.GetConfig()
+	artifactsToProcess, artifactsToNotProcess := getArtifactsToProcess(newArtifactsToProcess, allArtifacts, tConfig, pt)
+	if len(artifactsToProcess) == 0 {
+		return result
+	}
 
-		artifactsToConsume, artifactsToNotConsume := getArtifactsToProcess(dependencyUpdatedArtifacts, allArtifacts, tConfig, pt)
-		if len(artifactsToNotConsume) != 0 {
-			logrus.Errorf("Artifacts to not consume: %d. This should have been 0.", len(artifactsToNotConsume))
-		}
+	logrus.Debugf("Transformer %s will be processing %d artifacts in %d mode", tConfig.Name, len(artifactsToProcess), pt)
 
-		logrus.Infof("Transformer %s processing %d artifacts", tConfig.Name, len(artifactsToConsume))
+	// Dependency processing
+	dependencyCreatedNewPathMappings, dependencyCreatedNewArtifacts, dependencyUpdatedArtifacts := transform(ctx, artifactsToProcess, allArtifacts, dependency, tConfig.Spec.DependencySelector, graph, iteration)
+	result.pathMappings = append(result.pathMappings, dependencyCreatedNewPathMappings...)
+	// Dependency processing
 
-		producedNewPathMappings, producedNewArtifacts, err := runSingleTransform(artifactsToConsume, allArtifacts, transformer, tConfig, env, graph, iteration)
-		if err != nil {
-			logrus.Errorf("failed to run a single transformation using the transformer %+v on the artifacts %+v . Error: %q", tConfig, artifactsToConsume, err)
-			continue
-		}
+	artifactsToConsume, artifactsToNotConsume := getArtifactsToProcess(dependencyUpdatedArtifacts, allArtifacts, tConfig, pt)
+	if len(artifactsToNotConsume) != 0 {
+		logrus.Errorf("Artifacts to not consume: %d. This should have been 0.", len(artifactsToNotConsume))
+	}
 
-		pathMappings = append(pathMappings, producedNewPathMappings...)
-		artifactsToPassThrough := []transformertypes.Artifact{}
-		artifactsAlreadyPassedThrough := []transformertypes.Artifact{}
-		if pt == consume {
-			artifactsToPassThrough = append(dependencyCreatedNewArtifacts, producedNewArtifacts...)
-		} else if pt == passthrough || pt == dependency {
-			for _, a := range producedNewArtifacts {
-				if c, ok := tConfig.Spec.ConsumedArtifacts[a.Type]; ok && (c.Mode != transformertypes.MandatoryPassThrough && c.Mode != transformertypes.OnDemandPassThrough) {
-					artifactsToPassThrough = append(artifactsToPassThrough, a)
-				} else {
-					artifactsAlreadyPassedThrough = append(artifactsAlreadyPassedThrough, a)
-				}
+	logrus.Infof("Transformer %s processing %d artifacts", tConfig.Name, len(artifactsToConsume))
+
+	producedNewPathMappings, producedNewArtifacts, err := runSingleTransform(ctx, artifactsToConsume, allArtifacts, transformer, tConfig, env, graph, iteration)
+	if err != nil {
+		logrus.Errorf("failed to run a single transformation using the transformer %+v on the artifacts %+v . Error: %q", tConfig, artifactsToConsume, err)
+		return result
+	}
+	result.processed = true
+
+	result.pathMappings = append(result.pathMappings, producedNewPathMappings...)
+	artifactsToPassThrough := []transformertypes.Artifact{}
+	artifactsAlreadyPassedThrough := []transformertypes.Artifact{}
+	if pt == consume {
+		artifactsToPassThrough = append(dependencyCreatedNewArtifacts, producedNewArtifacts...)
+	} else if pt == passthrough || pt == dependency {
+		for _, a := range producedNewArtifacts {
+			if c, ok := tConfig.Spec.ConsumedArtifacts[a.Type]; ok && (c.Mode != transformertypes.MandatoryPassThrough && c.Mode != transformertypes.OnDemandPassThrough) {
+				artifactsToPassThrough = append(artifactsToPassThrough, a)
+			} else {
+				artifactsAlreadyPassedThrough = append(artifactsAlreadyPassedThrough, a)
 			}
 		}
+	}
 
-		passedThroughPathMappings, passedThroughNewArtifactsCreated, passedThroughUpdatedArtifacts := transform(artifactsToPassThrough, allArtifacts, passthrough, nil, graph, iteration)
+	passedThroughPathMappings, passedThroughNewArtifactsCreated, passedThroughUpdatedArtifacts := transform(ctx, artifactsToPassThrough, allArtifacts, passthrough, nil, graph, iteration)
 
-		pathMappings = append(pathMappings, passedThroughPathMappings...)
-		newArtifactsCreated = append(newArtifactsCreated, passedThroughNewArtifactsCreated...)
-		if pt == consume {
-			newArtifactsCreated = append(newArtifactsCreated, passedThroughUpdatedArtifacts...)
-		}
-		updatedArtifacts = append(updatedArtifacts, passedThroughUpdatedArtifacts...)
-		if pt == passthrough || pt == dependency {
-			newArtifactsToProcess = artifactsToNotProcess
-			newArtifactsToProcess = append(newArtifactsToProcess, passedThroughUpdatedArtifacts...)
-			newArtifactsToProcess = append(newArtifactsToProcess, artifactsAlreadyPassedThrough...)
-		}
-		logrus.Infof("Transformer %s Done", tConfig.Name)
+	result.pathMappings = append(result.pathMappings, passedThroughPathMappings...)
+	result.newArtifactsCreated = append(result.newArtifactsCreated, passedThroughNewArtifactsCreated...)
+	if pt == consume {
+		result.newArtifactsCreated = append(result.newArtifactsCreated, passedThroughUpdatedArtifacts...)
 	}
 	if pt == passthrough || pt == dependency {
-		logrus.Debugf("Created %d pathMappings, %d artifacts, %d updated artifacts from transform while passing through/dependency.", len(pathMappings), len(newArtifactsCreated), len(newArtifactsToProcess))
-		return pathMappings, newArtifactsCreated, newArtifactsToProcess
+		result.artifactsToProcess = artifactsToNotProcess
+		result.artifactsToProcess = append(result.artifactsToProcess, passedThroughUpdatedArtifacts...)
+		result.artifactsToProcess = append(result.artifactsToProcess, artifactsAlreadyPassedThrough...)
+	}
+	logrus.Infof("Transformer %s Done", tConfig.Name)
+	return result
+}
+
+// getLogContext returns the log context of a transformer processing the artifacts
+func getLogContext(transformerName string, artifactsToProcess []transformertypes.Artifact) common.LogContext {
+	logContext := common.LogContext{Transformer: transformerName}
+	for _, artifact := range artifactsToProcess {
+		if artifact.Name != "" {
+			logContext.Artifacts = common.AppendIfNotPresent(logContext.Artifacts, artifact.Name)
+		}
+		serviceConfig := artifacts.ServiceConfig{}
+		if err := artifact.GetConfig(artifacts.ServiceConfigType, &serviceConfig); err == nil && serviceConfig.ServiceName != "" {
+			logContext.Services = common.AppendIfNotPresent(logContext.Services, serviceConfig.ServiceName)
+		}
 	}
-	logrus.Debugf("Created %d pathMappings and %d artifacts from transform.", len(pathMappings), len(newArtifactsCreated))
-	return pathMappings, newArtifactsCreated, nil
+	return logContext
 }
 
-func runSingleTransform(artifactsToProcess, allArtifacts []transformertypes.Artifact, transformer Transformer, tconfig transformertypes.Transformer, env *environment.Environment, graph *graphtypes.Graph, iteration int) (newPathMappings []transformertypes.PathMapping, newArtifacts []transformertypes.Artifact, err error) {
+func runSingleTransform(ctx context.Context, artifactsToProcess, allArtifacts []transformertypes.Artifact, transformer Transformer, tconfig transformertypes.Transformer, env *environment.Environment, graph *graphtypes.Graph, iteration int) (newPathMappings []transformertypes.PathMapping, newArtifacts []transformertypes.Artifact, err error) {
+	ctx, span := tracing.Start(ctx, "RunTransformer",
+		attribute.String("transformer", tconfig.Name),
+		attribute.String("class", tconfig.Spec.Class),
+		attribute.Int("iteration", iteration),
+		attribute.Int("artifacts", len(artifactsToProcess)),
+	)
+	defer func() { tracing.End(span, err) }()
+	defer lockTransformer(tconfig.Name)()
 	if err := env.Reset(); err != nil {
 		return nil, nil, fmt.Errorf("failed to reset the environment: %+v Error: %q", env, err)
 	}
 
+	common.SetLogContext(getLogContext(tconfig.Name, artifactsToProcess))
+	defer common.SetLogContext(common.LogContext{})
+	common.SetTransformerProgress(tconfig.Name, common.TransformerRunning)
+	eventbus.Publish(eventbus.TransformerStartedEvent{Name: tconfig.Name, Class: tconfig.Spec.Class, Iteration: iteration, Artifacts: len(artifactsToProcess)})
+	startTime := time.Now()
 	newPathMappings, newArtifacts, err = transformer.Transform(
 		*env.Encode(&artifactsToProcess).(*[]transformertypes.Artifact),
 		*env.Encode(&allArtifacts).(*[]transformertypes.Artifact),
 	)
+	finishedEvent := eventbus.TransformerFinishedEvent{Name: tconfig.Name, Class: tconfig.Spec.Class, Iteration: iteration, PathMappings: len(newPathMappings), Artifacts: len(newArtifacts), Duration: time.Since(startTime)}
+	if err != nil {
+		common.SetTransformerProgress(tconfig.Name, common.TransformerFailed)
+		finishedEvent.Error = err.Error()
+	} else {
+		common.SetTransformerProgress(tconfig.Name, common.TransformerDone)
+	}
+	eventbus.Publish(finishedEvent)
 	// logging
 	{
+		graphMutex.Lock()
 		vertexName := fmt.Sprintf("iteration: %d\nclass: %s\nname: %s", iteration, tconfig.Spec.Class, tconfig.Name)
 		targetVertexId := graph.AddVertex(
 			vertexName,
@@ -649,6 +970,11 @@ func runSingleTransform(artifactsToProcess, allArtifacts []transformertypes.Arti
 				"pathMappings":      summarizePathMappings(newPathMappings),
 			},
 		)
+		graph.SetVertexTransformer(targetVertexId, graphtypes.TransformerInfo{
+			Name:         tconfig.Name,
+			Class:        tconfig.Spec.Class,
+			PathMappings: getPathMappingInfos(newPathMappings),
+		})
 		// transformers that are invoked by default has source vertex as start
 		if tconfig.Spec.InvokedByDefault.Enabled {
 			edgeName := fmt.Sprintf("%d -> %d (invoked by default)", 0, targetVertexId)
@@ -665,7 +991,8 @@ func runSingleTransform(artifactsToProcess, allArtifacts []transformertypes.Arti
 				sourceVertexId = processVertexId
 				edgeName = fmt.Sprintf("%d -> %d", processVertexId, targetVertexId)
 			}
-			graph.AddEdge(sourceVertexId, targetVertexId, edgeName, map[string]interface{}{"newArtifact": summarizeArtifacts([]transformertypes.Artifact{artifact})})
+			edgeId := graph.AddEdge(sourceVertexId, targetVertexId, edgeName, map[string]interface{}{"newArtifact": summarizeArtifacts([]transformertypes.Artifact{artifact})})
+			graph.SetEdgeArtifact(edgeId, getArtifactInfo(artifact))
 		}
 		for i, newArtifact := range newArtifacts {
 			if newArtifact.Configs == nil {
@@ -678,6 +1005,7 @@ func runSingleTransform(artifactsToProcess, allArtifacts []transformertypes.Arti
 			newArtifact.Configs[graphtypes.GraphSourceVertexKey] = targetVertexId
 			newArtifacts[i] = newArtifact
 		}
+		graphMutex.Unlock()
 	}
 	// logging
 
@@ -696,7 +1024,7 @@ func runSingleTransform(artifactsToProcess, allArtifacts []transformertypes.Arti
 	newArtifacts = filteredArtifacts
 	newPathMappings = env.ProcessPathMappings(newPathMappings)
 	newPathMappings = *env.DownloadAndDecode(&newPathMappings, true).(*[]transformertypes.PathMapping)
-	if err := processPathMappings(newPathMappings, env.Source, env.Output); err != nil {
+	if err := processPathMappingsWithSpan(ctx, newPathMappings, env.Source, env.Output); err != nil {
 		return newPathMappings, newArtifacts, fmt.Errorf("failed to process the path mappings: %+v . Error: %q", newPathMappings, err)
 	}
 	newArtifacts = *env.DownloadAndDecode(&newArtifacts, false).(*[]transformertypes.Artifact)
@@ -756,3 +1084,18 @@ func getArtifactsToProcess(newArtifactsToProcess, allArtifacts []transformertype
 
 	return artifactsToProcess, artifactsToNotProcess
 }
+
+// processPathMappingsWithSpan records the application of the path mappings as a span
+func processPathMappingsWithSpan(ctx context.Context, pathMappings []transformertypes.PathMapping, sourcePath, outputPath string) (err error) {
+	_, span := tracing.Start(ctx, "ApplyPathMappings", attribute.Int("pathMappings", len(pathMappings)), attribute.String("outputPath", outputPath))
+	defer func() { tracing.End(span, err) }()
+	if err := processPathMappings(pathMappings, sourcePath, outputPath); err != nil {
+		return err
+	}
+	if eventbus.HasSubscribers() {
+		for _, pathMapping := range pathMappings {
+			eventbus.Publish(eventbus.PathMappingAppliedEvent{PathMapping: pathMapping, SourcePath: sourcePath, OutputPath: outputPath})
+		}
+	}
+	return nil
+}
@@ -30,6 +30,7 @@ import (
 	transformertypes "github.com/konveyor/move2kube/types/transformer"
 	"github.com/konveyor/move2kube/types/transformer/artifacts"
 	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
@@ -46,6 +47,34 @@ func getTransformerConfig(path string) (tc transformertypes.Transformer, err err
 		logrus.Debug(err)
 		return tc, err
 	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tc, fmt.Errorf("failed to read the transformer config at path %s . Error: %w", path, err)
+	}
+	violations, err := transformertypes.ValidateTransformerYaml(data)
+	if err != nil {
+		return tc, fmt.Errorf("failed to validate the transformer config at path %s . Error: %w", path, err)
+	}
+	if len(violations) != 0 {
+		return tc, &transformertypes.TransformerSchemaError{Path: path, Violations: violations}
+	}
+	if tc.Spec.ConfigSchema != "" {
+		configSchemaPath := tc.Spec.ConfigSchema
+		if !filepath.IsAbs(configSchemaPath) {
+			configSchemaPath = filepath.Join(filepath.Dir(path), configSchemaPath)
+		}
+		configSchemaData, err := os.ReadFile(configSchemaPath)
+		if err != nil {
+			return tc, fmt.Errorf("failed to read the config schema of the transformer at path %s . Error: %w", configSchemaPath, err)
+		}
+		var configSchema interface{}
+		if err := yaml.Unmarshal(configSchemaData, &configSchema); err != nil {
+			return tc, fmt.Errorf("failed to parse the config schema of the transformer at path %s . Error: %w", configSchemaPath, err)
+		}
+		if err := transformertypes.ValidateTransformerConfigFile(path, configSchema); err != nil {
+			return tc, err
+		}
+	}
 	if tc.Labels == nil {
 		tc.Labels = map[string]string{}
 	}
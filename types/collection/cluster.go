@@ -75,9 +75,13 @@ func (c *ClusterMetadata) isEmpty() bool {
 
 // ClusterMetadataSpec stores the data
 type ClusterMetadataSpec struct {
-	StorageClasses    []string            `yaml:"storageClasses"`
-	APIKindVersionMap map[string][]string `yaml:"apiKindVersionMap"` //[kubernetes kind]["gv1", "gv2",...,"gvn"] prioritized group-version
-	Host              string              `yaml:"host,omitempty"`    // Optional field, either collected with move2kube collect or by asking the user.
+	StorageClasses      []string            `yaml:"storageClasses"`
+	DefaultStorageClass string              `yaml:"defaultStorageClass,omitempty"`
+	IngressClasses      []string            `yaml:"ingressClasses,omitempty"`
+	DefaultIngressClass string              `yaml:"defaultIngressClass,omitempty"`
+	KubernetesVersion   string              `yaml:"kubernetesVersion,omitempty"`
+	APIKindVersionMap   map[string][]string `yaml:"apiKindVersionMap"` //[kubernetes kind]["gv1", "gv2",...,"gvn"] prioritized group-version
+	Host                string              `yaml:"host,omitempty"`    // Optional field, either collected with move2kube collect or by asking the user.
 }
 
 // Merge helps merge clustermetadata
@@ -93,6 +97,33 @@ func (c *ClusterMetadataSpec) Merge(newc ClusterMetadataSpec) bool {
 	if len(c.StorageClasses) == 0 {
 		c.StorageClasses = []string{"default"}
 	}
+	if newc.DefaultStorageClass != "" {
+		c.DefaultStorageClass = newc.DefaultStorageClass
+	}
+	if !common.IsPresent(c.StorageClasses, c.DefaultStorageClass) {
+		c.DefaultStorageClass = ""
+	}
+	// Allow only intersection of ingress classes, if both the clusters report them
+	if len(c.IngressClasses) == 0 {
+		c.IngressClasses = newc.IngressClasses
+	} else if len(newc.IngressClasses) != 0 {
+		newslice = []string{}
+		for _, ic := range c.IngressClasses {
+			if common.IsPresent(newc.IngressClasses, ic) {
+				newslice = append(newslice, ic)
+			}
+		}
+		c.IngressClasses = newslice
+	}
+	if newc.DefaultIngressClass != "" {
+		c.DefaultIngressClass = newc.DefaultIngressClass
+	}
+	if !common.IsPresent(c.IngressClasses, c.DefaultIngressClass) {
+		c.DefaultIngressClass = ""
+	}
+	if newc.KubernetesVersion != "" {
+		c.KubernetesVersion = newc.KubernetesVersion
+	}
 	//TODO: Do Intelligent merge of version
 	apiversionkindmap := map[string][]string{}
 	for kindname, gvList := range newc.APIKindVersionMap {
@@ -115,6 +146,30 @@ func (c *ClusterMetadataSpec) GetSupportedVersions(kind string) []string {
 	return nil
 }
 
+// GetDefaultStorageClass returns the storage class to be used when the user has not chosen one.
+// It is the cluster default if one was collected, or the only storage class available in the cluster.
+func (c *ClusterMetadataSpec) GetDefaultStorageClass() string {
+	if c.DefaultStorageClass != "" {
+		return c.DefaultStorageClass
+	}
+	if len(c.StorageClasses) == 1 {
+		return c.StorageClasses[0]
+	}
+	return ""
+}
+
+// GetDefaultIngressClass returns the ingress class to be used when the user has not chosen one.
+// It is the cluster default if one was collected, or the only ingress class available in the cluster.
+func (c *ClusterMetadataSpec) GetDefaultIngressClass() string {
+	if c.DefaultIngressClass != "" {
+		return c.DefaultIngressClass
+	}
+	if len(c.IngressClasses) == 1 {
+		return c.IngressClasses[0]
+	}
+	return ""
+}
+
 // NewClusterMetadata creates a new cluster metadata instance
 func NewClusterMetadata(contextName string) ClusterMetadata {
 	return ClusterMetadata{
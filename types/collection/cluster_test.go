@@ -102,6 +102,70 @@ func TestMerge(t *testing.T) {
 			t.Fatalf("Failed to merge ClusterMetadata properly. Difference:\n%s:", cmp.Diff(want, cmeta1))
 		}
 	})
+
+	t.Run("merging ingress classes and defaults from filled metadata into filled metadata", func(t *testing.T) {
+		cmeta1 := collection.NewClusterMetadata("")
+		cmeta1.Spec.StorageClasses = []string{"gp2", "gp3"}
+		cmeta1.Spec.DefaultStorageClass = "gp2"
+		cmeta1.Spec.IngressClasses = []string{"nginx", "traefik"}
+		cmeta1.Spec.DefaultIngressClass = "traefik"
+
+		cmeta2 := collection.NewClusterMetadata("")
+		cmeta2.Spec.StorageClasses = []string{"gp3"}
+		cmeta2.Spec.DefaultStorageClass = "gp3"
+		cmeta2.Spec.IngressClasses = []string{"nginx"}
+		cmeta2.Spec.KubernetesVersion = "v1.24.2"
+
+		want := collection.NewClusterMetadata("")
+		want.Spec.StorageClasses = []string{"gp3"}
+		want.Spec.DefaultStorageClass = "gp3"
+		want.Spec.IngressClasses = []string{"nginx"}
+		want.Spec.KubernetesVersion = "v1.24.2"
+
+		if merged := cmeta1.Merge(cmeta2); !merged || !reflect.DeepEqual(cmeta1, want) {
+			t.Fatalf("Failed to merge ClusterMetadata properly. Difference:\n%s:", cmp.Diff(want, cmeta1))
+		}
+	})
+}
+
+func TestGetDefaultClasses(t *testing.T) {
+	t.Run("get the collected cluster defaults", func(t *testing.T) {
+		cmeta := collection.NewClusterMetadata("")
+		cmeta.Spec.StorageClasses = []string{"gp2", "gp3"}
+		cmeta.Spec.DefaultStorageClass = "gp3"
+		cmeta.Spec.IngressClasses = []string{"nginx", "traefik"}
+		cmeta.Spec.DefaultIngressClass = "nginx"
+		if sc := cmeta.Spec.GetDefaultStorageClass(); sc != "gp3" {
+			t.Fatalf("Expected the default storage class to be gp3. Actual: %q", sc)
+		}
+		if ic := cmeta.Spec.GetDefaultIngressClass(); ic != "nginx" {
+			t.Fatalf("Expected the default ingress class to be nginx. Actual: %q", ic)
+		}
+	})
+
+	t.Run("get the only class when there is no default", func(t *testing.T) {
+		cmeta := collection.NewClusterMetadata("")
+		cmeta.Spec.StorageClasses = []string{"gp2"}
+		cmeta.Spec.IngressClasses = []string{"nginx"}
+		if sc := cmeta.Spec.GetDefaultStorageClass(); sc != "gp2" {
+			t.Fatalf("Expected the default storage class to be gp2. Actual: %q", sc)
+		}
+		if ic := cmeta.Spec.GetDefaultIngressClass(); ic != "nginx" {
+			t.Fatalf("Expected the default ingress class to be nginx. Actual: %q", ic)
+		}
+	})
+
+	t.Run("get empty when there are multiple classes and no default", func(t *testing.T) {
+		cmeta := collection.NewClusterMetadata("")
+		cmeta.Spec.StorageClasses = []string{"gp2", "gp3"}
+		cmeta.Spec.IngressClasses = []string{"nginx", "traefik"}
+		if sc := cmeta.Spec.GetDefaultStorageClass(); sc != "" {
+			t.Fatalf("Expected no default storage class. Actual: %q", sc)
+		}
+		if ic := cmeta.Spec.GetDefaultIngressClass(); ic != "" {
+			t.Fatalf("Expected no default ingress class. Actual: %q", ic)
+		}
+	})
 }
 
 func TestGetSupportedVersions(t *testing.T) {
@@ -0,0 +1,220 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package graph
+
+import (
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ExportVersion is the version of the schema of the exported graph.
+const ExportVersion = "v1"
+
+// Export is the plan and the transformation graph in a stable format that can be consumed by external tools.
+// It mirrors the messages in export.proto and the json tags follow the proto3 JSON mapping.
+type Export struct {
+	Version      string              `json:"version,omitempty"`
+	Plan         *ExportPlan         `json:"plan,omitempty"`
+	Transformers []ExportTransformer `json:"transformers,omitempty"`
+	Artifacts    []ExportArtifact    `json:"artifacts,omitempty"`
+}
+
+// ExportPlan is the exported form of the plan.
+type ExportPlan struct {
+	Name      string          `json:"name,omitempty"`
+	SourceDir string          `json:"sourceDir,omitempty"`
+	Services  []ExportService `json:"services,omitempty"`
+}
+
+// ExportService is a service in the plan along with its transformation options.
+type ExportService struct {
+	Name    string                `json:"name,omitempty"`
+	Options []ExportServiceOption `json:"options,omitempty"`
+}
+
+// ExportServiceOption is a single transformation option for a service.
+type ExportServiceOption struct {
+	TransformerName string        `json:"transformerName,omitempty"`
+	ArtifactType    string        `json:"artifactType,omitempty"`
+	Paths           []ExportPaths `json:"paths,omitempty"`
+}
+
+// ExportPaths are the paths of a single path type.
+type ExportPaths struct {
+	Type  string   `json:"type,omitempty"`
+	Paths []string `json:"paths,omitempty"`
+}
+
+// ExportTransformer is a single transformer run.
+type ExportTransformer struct {
+	Id           int32             `json:"id,omitempty"`
+	Name         string            `json:"name,omitempty"`
+	Class        string            `json:"class,omitempty"`
+	Iteration    int32             `json:"iteration,omitempty"`
+	PathMappings []PathMappingInfo `json:"pathMappings,omitempty"`
+}
+
+// ExportArtifact is an artifact passed between two transformer runs.
+type ExportArtifact struct {
+	Id         int32         `json:"id,omitempty"`
+	Name       string        `json:"name,omitempty"`
+	Type       string        `json:"type,omitempty"`
+	ProducedBy int32         `json:"producedBy,omitempty"`
+	ConsumedBy int32         `json:"consumedBy,omitempty"`
+	Paths      []ExportPaths `json:"paths,omitempty"`
+}
+
+// NewExport creates an export from the plan and the graph. The graph can be nil if the transformation has not been run.
+func NewExport(plan ExportPlan, graph *Graph) Export {
+	export := Export{Version: ExportVersion, Plan: &plan}
+	if graph == nil {
+		return export
+	}
+	for _, vertex := range graph.Vertices {
+		transformer := ExportTransformer{Id: int32(vertex.Id), Name: vertex.Name, Iteration: int32(vertex.Iteration)}
+		if vertex.Transformer != nil {
+			transformer.Name = vertex.Transformer.Name
+			transformer.Class = vertex.Transformer.Class
+			transformer.PathMappings = vertex.Transformer.PathMappings
+		}
+		export.Transformers = append(export.Transformers, transformer)
+	}
+	sort.Slice(export.Transformers, func(i, j int) bool { return export.Transformers[i].Id < export.Transformers[j].Id })
+	for _, edge := range graph.Edges {
+		artifact := ExportArtifact{Id: int32(edge.Id), ProducedBy: int32(edge.From), ConsumedBy: int32(edge.To)}
+		if edge.Artifact == nil {
+			// edges without artifacts link the start to the transformers that are invoked by default
+			continue
+		}
+		artifact.Name = edge.Artifact.Name
+		artifact.Type = edge.Artifact.Type
+		artifact.Paths = NewExportPaths(edge.Artifact.Paths)
+		export.Artifacts = append(export.Artifacts, artifact)
+	}
+	sort.Slice(export.Artifacts, func(i, j int) bool { return export.Artifacts[i].Id < export.Artifacts[j].Id })
+	return export
+}
+
+// NewExportPaths converts a map of path types to paths into a list sorted by the path type.
+func NewExportPaths(paths map[string][]string) []ExportPaths {
+	exportPaths := []ExportPaths{}
+	for pathType, ps := range paths {
+		exportPaths = append(exportPaths, ExportPaths{Type: pathType, Paths: ps})
+	}
+	sort.Slice(exportPaths, func(i, j int) bool { return exportPaths[i].Type < exportPaths[j].Type })
+	return exportPaths
+}
+
+// MarshalProto encodes the export using the protobuf wire format of the Export message in export.proto.
+func (e Export) MarshalProto() []byte {
+	b := appendProtoString(nil, 1, e.Version)
+	if e.Plan != nil {
+		b = appendProtoMessage(b, 2, e.Plan.marshalProto())
+	}
+	for _, transformer := range e.Transformers {
+		b = appendProtoMessage(b, 3, transformer.marshalProto())
+	}
+	for _, artifact := range e.Artifacts {
+		b = appendProtoMessage(b, 4, artifact.marshalProto())
+	}
+	return b
+}
+
+func (p ExportPlan) marshalProto() []byte {
+	b := appendProtoString(nil, 1, p.Name)
+	b = appendProtoString(b, 2, p.SourceDir)
+	for _, service := range p.Services {
+		b = appendProtoMessage(b, 3, service.marshalProto())
+	}
+	return b
+}
+
+func (s ExportService) marshalProto() []byte {
+	b := appendProtoString(nil, 1, s.Name)
+	for _, option := range s.Options {
+		b = appendProtoMessage(b, 2, option.marshalProto())
+	}
+	return b
+}
+
+func (o ExportServiceOption) marshalProto() []byte {
+	b := appendProtoString(nil, 1, o.TransformerName)
+	b = appendProtoString(b, 2, o.ArtifactType)
+	for _, paths := range o.Paths {
+		b = appendProtoMessage(b, 3, paths.marshalProto())
+	}
+	return b
+}
+
+func (p ExportPaths) marshalProto() []byte {
+	b := appendProtoString(nil, 1, p.Type)
+	for _, path := range p.Paths {
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		b = protowire.AppendString(b, path)
+	}
+	return b
+}
+
+func (t ExportTransformer) marshalProto() []byte {
+	b := appendProtoInt32(nil, 1, t.Id)
+	b = appendProtoString(b, 2, t.Name)
+	b = appendProtoString(b, 3, t.Class)
+	b = appendProtoInt32(b, 4, t.Iteration)
+	for _, pathMapping := range t.PathMappings {
+		pb := appendProtoString(nil, 1, pathMapping.Type)
+		pb = appendProtoString(pb, 2, pathMapping.SrcPath)
+		pb = appendProtoString(pb, 3, pathMapping.DestPath)
+		b = appendProtoMessage(b, 5, pb)
+	}
+	return b
+}
+
+func (a ExportArtifact) marshalProto() []byte {
+	b := appendProtoInt32(nil, 1, a.Id)
+	b = appendProtoString(b, 2, a.Name)
+	b = appendProtoString(b, 3, a.Type)
+	b = appendProtoInt32(b, 4, a.ProducedBy)
+	b = appendProtoInt32(b, 5, a.ConsumedBy)
+	for _, paths := range a.Paths {
+		b = appendProtoMessage(b, 6, paths.marshalProto())
+	}
+	return b
+}
+
+// appendProtoString appends a string field, skipping the default value like proto3 does
+func appendProtoString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+// appendProtoInt32 appends an int32 field, skipping the default value like proto3 does
+func appendProtoInt32(b []byte, num protowire.Number, v int32) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(int64(v)))
+}
+
+func appendProtoMessage(b []byte, num protowire.Number, m []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, m)
+}
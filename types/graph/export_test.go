@@ -0,0 +1,101 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func getTestGraph() *Graph {
+	g := NewGraph()
+	start := g.AddVertex("start", 1, nil)
+	compose := g.AddVertex("iteration: 1\nclass: Compose\nname: DockerCompose", 1, nil)
+	g.SetVertexTransformer(compose, TransformerInfo{Name: "DockerCompose", Class: "Compose"})
+	k8s := g.AddVertex("iteration: 2\nclass: Kubernetes\nname: Kubernetes", 2, nil)
+	g.SetVertexTransformer(k8s, TransformerInfo{
+		Name:         "Kubernetes",
+		Class:        "Kubernetes",
+		PathMappings: []PathMappingInfo{{Type: "Default", SrcPath: "/tmp/deploy", DestPath: "deploy"}},
+	})
+	g.AddEdge(start, k8s, "0 -> 2 (invoked by default)", nil)
+	ir := g.AddEdge(compose, k8s, "1 -> 2", nil)
+	g.SetEdgeArtifact(ir, ArtifactInfo{Name: "web", Type: "IR", Paths: map[string][]string{"ComposeFile": {"docker-compose.yaml"}}})
+	return g
+}
+
+func TestNewExport(t *testing.T) {
+	plan := ExportPlan{Name: "myproject", SourceDir: "src"}
+	t.Run("without a graph", func(t *testing.T) {
+		want := Export{Version: ExportVersion, Plan: &plan}
+		if got := NewExport(plan, nil); !cmp.Equal(got, want) {
+			t.Fatalf("unexpected export. Differences: %s", cmp.Diff(want, got))
+		}
+	})
+	t.Run("with a graph", func(t *testing.T) {
+		want := Export{
+			Version: ExportVersion,
+			Plan:    &plan,
+			Transformers: []ExportTransformer{
+				{Id: 0, Name: "start", Iteration: 1},
+				{Id: 1, Name: "DockerCompose", Class: "Compose", Iteration: 1},
+				{Id: 2, Name: "Kubernetes", Class: "Kubernetes", Iteration: 2, PathMappings: []PathMappingInfo{{Type: "Default", SrcPath: "/tmp/deploy", DestPath: "deploy"}}},
+			},
+			Artifacts: []ExportArtifact{
+				{Id: 1, Name: "web", Type: "IR", ProducedBy: 1, ConsumedBy: 2, Paths: []ExportPaths{{Type: "ComposeFile", Paths: []string{"docker-compose.yaml"}}}},
+			},
+		}
+		if got := NewExport(plan, getTestGraph()); !cmp.Equal(got, want) {
+			t.Fatalf("unexpected export. Differences: %s", cmp.Diff(want, got))
+		}
+	})
+}
+
+func TestMarshalProto(t *testing.T) {
+	export := NewExport(ExportPlan{Name: "myproject"}, getTestGraph())
+	b := export.MarshalProto()
+	counts := map[protowire.Number]int{}
+	version := ""
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("failed to parse the tag. Error: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			t.Fatalf("expected all the top level fields to be length delimited. Actual type: %v", typ)
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			t.Fatalf("failed to parse the field %d . Error: %v", num, protowire.ParseError(n))
+		}
+		b = b[n:]
+		if num == 1 {
+			version = string(v)
+		}
+		counts[num]++
+	}
+	if version != ExportVersion {
+		t.Fatalf("expected the version %s . Actual: %s", ExportVersion, version)
+	}
+	want := map[protowire.Number]int{1: 1, 2: 1, 3: 3, 4: 1}
+	if !cmp.Equal(counts, want) {
+		t.Fatalf("unexpected number of fields. Differences: %s", cmp.Diff(want, counts))
+	}
+}
@@ -28,19 +28,42 @@ type Graph struct {
 
 // Vertex is a single transformer.
 type Vertex struct {
-	Id        int                    `json:"id"`
-	Iteration int                    `json:"iteration"`
-	Name      string                 `json:"name"`
-	Data      map[string]interface{} `json:"data,omitempty"`
+	Id          int                    `json:"id"`
+	Iteration   int                    `json:"iteration"`
+	Name        string                 `json:"name"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+	Transformer *TransformerInfo       `json:"transformer,omitempty"`
 }
 
 // Edge is an artifact.
 type Edge struct {
-	Id   int                    `json:"id"`
-	From int                    `json:"from"`
-	To   int                    `json:"to"`
-	Name string                 `json:"name"`
-	Data map[string]interface{} `json:"data,omitempty"`
+	Id       int                    `json:"id"`
+	From     int                    `json:"from"`
+	To       int                    `json:"to"`
+	Name     string                 `json:"name"`
+	Data     map[string]interface{} `json:"data,omitempty"`
+	Artifact *ArtifactInfo          `json:"artifact,omitempty"`
+}
+
+// TransformerInfo contains the details of the transformer run represented by a vertex.
+type TransformerInfo struct {
+	Name         string            `json:"name"`
+	Class        string            `json:"class"`
+	PathMappings []PathMappingInfo `json:"pathMappings,omitempty"`
+}
+
+// PathMappingInfo contains the details of a path mapping created by a transformer.
+type PathMappingInfo struct {
+	Type     string `json:"type,omitempty"`
+	SrcPath  string `json:"srcPath,omitempty"`
+	DestPath string `json:"destPath,omitempty"`
+}
+
+// ArtifactInfo contains the details of the artifact represented by an edge.
+type ArtifactInfo struct {
+	Name  string              `json:"name,omitempty"`
+	Type  string              `json:"type,omitempty"`
+	Paths map[string][]string `json:"paths,omitempty"`
 }
 
 // GraphT is the final graph used by the web server.
@@ -83,6 +106,30 @@ type Data struct {
 	PathMappings string `json:"pathMappings,omitempty"`
 }
 
+// Flow is the flow of the artifacts between the transformers, used when exporting the graph as json.
+type Flow struct {
+	Nodes []FlowNode `json:"nodes"`
+	Edges []FlowEdge `json:"edges"`
+}
+
+// FlowNode is a transformer run, or the start of the flow.
+type FlowNode struct {
+	Id        int    `json:"id"`
+	Label     string `json:"label"`
+	Iteration int    `json:"iteration"`
+	Name      string `json:"name,omitempty"`
+	Class     string `json:"class,omitempty"`
+}
+
+// FlowEdge is an artifact passed from one transformer to another.
+type FlowEdge struct {
+	From         int    `json:"from"`
+	To           int    `json:"to"`
+	Label        string `json:"label"`
+	ArtifactName string `json:"artifactName,omitempty"`
+	ArtifactType string `json:"artifactType,omitempty"`
+}
+
 const (
 	// GraphFileVersion is the version of the graph file that is generated.
 	GraphFileVersion = "1.0.0"
@@ -126,3 +173,23 @@ func (g *Graph) AddEdge(from, to int, name string, data map[string]interface{})
 	g.Edges[g.edgeId] = Edge{Id: g.edgeId, From: from, To: to, Name: name, Data: data}
 	return g.edgeId
 }
+
+// SetVertexTransformer sets the details of the transformer run represented by a vertex.
+func (g *Graph) SetVertexTransformer(id int, transformer TransformerInfo) {
+	vertex, ok := g.Vertices[id]
+	if !ok {
+		return
+	}
+	vertex.Transformer = &transformer
+	g.Vertices[id] = vertex
+}
+
+// SetEdgeArtifact sets the details of the artifact represented by an edge.
+func (g *Graph) SetEdgeArtifact(id int, artifact ArtifactInfo) {
+	edge, ok := g.Edges[id]
+	if !ok {
+		return
+	}
+	edge.Artifact = &artifact
+	g.Edges[id] = edge
+}
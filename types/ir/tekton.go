@@ -49,6 +49,7 @@ type TriggerTemplate struct {
 
 // Pipeline holds the details about the clone build push pipeline resource
 type Pipeline struct {
-	Name          string
-	WorkspaceName string
+	Name              string
+	WorkspaceName     string
+	SigningSecretName string
 }
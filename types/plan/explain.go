@@ -0,0 +1,39 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plan
+
+// DetectionExplanation records the detection decisions of the transformers in each directory
+type DetectionExplanation struct {
+	Directories []DirectoryExplanation `yaml:"directories"`
+}
+
+// DirectoryExplanation records why the transformers did or did not detect services in a directory
+type DirectoryExplanation struct {
+	Path string `yaml:"path"`
+	// Skipped is the reason the directory was not searched for services
+	Skipped      string                   `yaml:"skipped,omitempty"`
+	Transformers []TransformerExplanation `yaml:"transformers,omitempty"`
+}
+
+// TransformerExplanation records whether a transformer detected services in a directory and why
+type TransformerExplanation struct {
+	Name     string   `yaml:"name"`
+	Matched  bool     `yaml:"matched"`
+	Services []string `yaml:"services,omitempty"`
+	Reasons  []string `yaml:"reasons,omitempty"`
+	Error    string   `yaml:"error,omitempty"`
+}
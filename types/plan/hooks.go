@@ -0,0 +1,95 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plan
+
+import "fmt"
+
+// HookStageT is the stage of the transformation at which a hook is run
+type HookStageT string
+
+const (
+	// HookStagePreDetect hooks are run before the services are detected in the source directory
+	HookStagePreDetect HookStageT = "preDetect"
+	// HookStagePostTransform hooks are run once after the transformation has written the output
+	HookStagePostTransform HookStageT = "postTransform"
+	// HookStagePerService hooks are run once for each transformed service after the transformation
+	HookStagePerService HookStageT = "perService"
+)
+
+// Hooks are the commands that are run at the different stages of the transformation
+type Hooks struct {
+	PreDetect     []Hook `yaml:"preDetect,omitempty"`
+	PostTransform []Hook `yaml:"postTransform,omitempty"`
+	PerService    []Hook `yaml:"perService,omitempty"`
+}
+
+// Hook is a command or a script run at a stage of the transformation.
+// The paths of the source, the output and the service are passed to it as environment variables.
+type Hook struct {
+	// Name identifies the hook in the logs
+	Name string `yaml:"name,omitempty"`
+	// Command is the executable and its arguments, run without a shell
+	Command []string `yaml:"command,omitempty"`
+	// Script is run using sh, or cmd on Windows
+	Script string `yaml:"script,omitempty"`
+	// IgnoreFailure lets the transformation continue when the hook fails
+	IgnoreFailure bool `yaml:"ignoreFailure,omitempty"`
+}
+
+// GetStage returns the hooks of the stage
+func (hooks Hooks) GetStage(stage HookStageT) []Hook {
+	switch stage {
+	case HookStagePreDetect:
+		return hooks.PreDetect
+	case HookStagePostTransform:
+		return hooks.PostTransform
+	case HookStagePerService:
+		return hooks.PerService
+	}
+	return nil
+}
+
+// Merge appends the hooks of each stage of the other hooks to these hooks
+func (hooks Hooks) Merge(other Hooks) Hooks {
+	return Hooks{
+		PreDetect:     append(append([]Hook{}, hooks.PreDetect...), other.PreDetect...),
+		PostTransform: append(append([]Hook{}, hooks.PostTransform...), other.PostTransform...),
+		PerService:    append(append([]Hook{}, hooks.PerService...), other.PerService...),
+	}
+}
+
+// IsEmpty returns true if there are no hooks in any stage
+func (hooks Hooks) IsEmpty() bool {
+	return len(hooks.PreDetect) == 0 && len(hooks.PostTransform) == 0 && len(hooks.PerService) == 0
+}
+
+// GetCommandLine returns the executable and the arguments to run the hook on the operating system
+func (hook Hook) GetCommandLine(goos string) ([]string, error) {
+	if len(hook.Command) != 0 && hook.Script != "" {
+		return nil, fmt.Errorf("the hook '%s' has both a command and a script. Expected only one of them", hook.Name)
+	}
+	if len(hook.Command) != 0 {
+		return hook.Command, nil
+	}
+	if hook.Script == "" {
+		return nil, fmt.Errorf("the hook '%s' has neither a command nor a script", hook.Name)
+	}
+	if goos == "windows" {
+		return []string{"cmd", "/C", hook.Script}, nil
+	}
+	return []string{"sh", "-c", hook.Script}, nil
+}
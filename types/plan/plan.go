@@ -37,8 +37,23 @@ type Plan struct {
 type Spec struct {
 	SourceDir         string `yaml:"sourceDir"`
 	CustomizationsDir string `yaml:"customizationsDir,omitempty"`
+	// SourceURL is the git remote path or the archive url the source was fetched from. It is fetched again during the
+	// transformation, since the source directory that was fetched during planning is temporary.
+	SourceURL string `yaml:"sourceURL,omitempty"`
+	// CustomizationsURL is the git remote path or the archive url the customizations were fetched from
+	CustomizationsURL string `yaml:"customizationsURL,omitempty"`
+	// Environments are the target environments (like dev, staging and prod) for which parameterized output is generated
+	Environments []string `yaml:"environments,omitempty"`
+	// SourceLimits are the thresholds above which files and directories in the source are skipped during detection and copying
+	SourceLimits SourceLimits `yaml:"sourceLimits,omitempty"`
+	// DetectionLimits bound the directories in the source that are searched for services
+	DetectionLimits DetectionLimits `yaml:"detectionLimits,omitempty"`
 
 	Services map[string][]PlanArtifact `yaml:"services"` //[servicename]
+	// Readiness is the migration readiness of each service, to help prioritize which services to migrate first
+	Readiness map[string]ServiceReadiness `yaml:"readiness,omitempty"` //[servicename]
+	// Hooks are the commands that are run before detection, after the transformation and for each service
+	Hooks Hooks `yaml:"hooks,omitempty"`
 
 	TransformerSelector          metav1.LabelSelector `yaml:"transformerSelector,omitempty"`
 	Transformers                 map[string]string    `yaml:"transformers,omitempty" m2kpath:"normal"` //[name]filepath
@@ -46,6 +61,22 @@ type Spec struct {
 	DisabledTransformers         map[string]string    `yaml:"disabledTransformers,omitempty" m2kpath:"normal"` //[name]filepath
 }
 
+// SourceLimits are the thresholds above which files and directories in the source are skipped
+type SourceLimits struct {
+	// MaxFileSize is the size (like 100Mi) above which a file is skipped
+	MaxFileSize string `yaml:"maxFileSize,omitempty"`
+	// MaxDirectoryFiles is the number of entries above which a directory is skipped
+	MaxDirectoryFiles int `yaml:"maxDirectoryFiles,omitempty"`
+}
+
+// DetectionLimits bound the directories in the source that are searched for services
+type DetectionLimits struct {
+	// MaxDetectDepth is the depth below the source directory after which directories are not searched
+	MaxDetectDepth int `yaml:"maxDetectDepth,omitempty"`
+	// ExcludeDirs are the glob patterns of the directories that are not searched
+	ExcludeDirs []string `yaml:"excludeDirs,omitempty"`
+}
+
 // PlanArtifact stores the artifact with the transformerName
 type PlanArtifact struct {
 	ServiceName               string `yaml:"-"`
@@ -17,9 +17,12 @@
 package plan_test
 
 import (
+	"path/filepath"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/konveyor/move2kube/types/plan"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
 )
 
 func TestNewPlan(t *testing.T) {
@@ -28,3 +31,211 @@ func TestNewPlan(t *testing.T) {
 		t.Error("Failed to instantiate the plan fields properly. Actual:", p)
 	}
 }
+
+func TestNewServiceReadiness(t *testing.T) {
+	t.Run("service without findings", func(t *testing.T) {
+		readiness := plan.NewServiceReadiness(nil)
+		if readiness.Score != 100 || readiness.Complexity != plan.ComplexityLow || readiness.Findings != nil {
+			t.Fatalf("expected a ready service. Actual: %+v", readiness)
+		}
+	})
+	t.Run("service with findings", func(t *testing.T) {
+		findings := []plan.ReadinessFinding{
+			{Factor: plan.ReadinessFactorStatefulStorage, Message: "the service mounts the volume data", Path: "docker-compose.yaml"},
+			{Factor: plan.ReadinessFactorStatefulStorage, Message: "the service mounts the volume logs", Path: "docker-compose.yaml"},
+			{Factor: plan.ReadinessFactorPrivileged, Message: "the service runs in privileged mode", Path: "docker-compose.yaml"},
+			{Factor: plan.ReadinessFactorPrivileged, Message: "the service runs in privileged mode", Path: "docker-compose.yaml"},
+		}
+		readiness := plan.NewServiceReadiness(findings)
+		if readiness.Score != 65 || readiness.Complexity != plan.ComplexityMedium {
+			t.Fatalf("expected a score of 65 and medium complexity. Actual: %+v", readiness)
+		}
+		if len(readiness.Findings) != 3 {
+			t.Fatalf("expected the duplicate finding to be removed. Actual: %+v", readiness.Findings)
+		}
+	})
+	t.Run("penalties are capped per factor", func(t *testing.T) {
+		findings := []plan.ReadinessFinding{}
+		for _, field := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+			findings = append(findings, plan.ReadinessFinding{Factor: plan.ReadinessFactorUnsupportedFeature, Message: "the field " + field + " was dropped"})
+		}
+		findings = append(findings, plan.ReadinessFinding{Factor: plan.ReadinessFactorOSSpecific, Message: "the service uses a Windows base image", Path: "Dockerfile"})
+		readiness := plan.NewServiceReadiness(findings)
+		if readiness.Score != 40 || readiness.Complexity != plan.ComplexityHigh {
+			t.Fatalf("expected a score of 40 and high complexity. Actual: %+v", readiness)
+		}
+	})
+}
+
+func TestRenameService(t *testing.T) {
+	getPlan := func() plan.Plan {
+		p := plan.NewPlan()
+		p.Spec.Services["svc1"] = []plan.PlanArtifact{
+			{ServiceName: "svc1", TransformerName: "Golang-Dockerfile", Artifact: transformertypes.Artifact{Name: "svc1"}},
+			{ServiceName: "svc1", TransformerName: "Buildpacks", Artifact: transformertypes.Artifact{Name: "other"}},
+		}
+		p.Spec.Services["svc2"] = []plan.PlanArtifact{{ServiceName: "svc2", TransformerName: "Buildpacks"}}
+		p.Spec.Readiness = map[string]plan.ServiceReadiness{"svc1": {Score: 90}}
+		return p
+	}
+	t.Run("rename to a new name", func(t *testing.T) {
+		p := getPlan()
+		if err := p.RenameService("svc1", "web"); err != nil {
+			t.Fatalf("failed to rename the service. Error: %q", err)
+		}
+		if _, ok := p.Spec.Services["svc1"]; ok {
+			t.Fatalf("the old service name is still present in the plan")
+		}
+		artifacts := p.Spec.Services["web"]
+		if len(artifacts) != 2 || artifacts[0].Name != "web" || artifacts[1].Name != "other" || artifacts[0].ServiceName != "web" {
+			t.Fatalf("the artifacts were not renamed properly. Actual: %+v", artifacts)
+		}
+		if p.Spec.Readiness["web"].Score != 90 {
+			t.Fatalf("the readiness was not moved to the new service name. Actual: %+v", p.Spec.Readiness)
+		}
+	})
+	t.Run("rename to an existing name", func(t *testing.T) {
+		p := getPlan()
+		if err := p.RenameService("svc1", "svc2"); err == nil {
+			t.Fatalf("expected an error when renaming to an existing service name")
+		}
+	})
+}
+
+func TestSelectServiceTransformers(t *testing.T) {
+	p := plan.NewPlan()
+	p.Spec.Services["svc1"] = []plan.PlanArtifact{{TransformerName: "Golang-Dockerfile"}, {TransformerName: "Buildpacks"}}
+	p.Spec.Services["svc2"] = []plan.PlanArtifact{{TransformerName: "Buildpacks"}}
+	p.SelectServiceTransformers("svc1", []string{"Buildpacks"})
+	want := []plan.PlanArtifact{{TransformerName: "Buildpacks"}}
+	if !cmp.Equal(p.Spec.Services["svc1"], want) {
+		t.Fatalf("the transformers were not deselected properly. Differences:\n%s", cmp.Diff(want, p.Spec.Services["svc1"]))
+	}
+	p.SelectServiceTransformers("svc2", nil)
+	if _, ok := p.Spec.Services["svc2"]; ok {
+		t.Fatalf("expected the service without any selected transformers to be removed")
+	}
+}
+
+func TestSelectServices(t *testing.T) {
+	p := plan.NewPlan()
+	p.Spec.Services["svc1"] = []plan.PlanArtifact{{TransformerName: "Golang-Dockerfile"}}
+	p.Spec.Services["svc2"] = []plan.PlanArtifact{{TransformerName: "Buildpacks"}}
+	p.Spec.Services["svc3"] = []plan.PlanArtifact{{TransformerName: "Buildpacks"}}
+	p.Spec.Readiness = map[string]plan.ServiceReadiness{"svc2": {}}
+	if err := p.SelectServices([]string{"svc1", "svc4"}); err == nil {
+		t.Fatalf("expected an error when selecting a service that is not present in the plan")
+	}
+	if len(p.Spec.Services) != 3 {
+		t.Fatalf("expected the plan to be unchanged after the error. Actual: %+v", p.Spec.Services)
+	}
+	if err := p.SelectServices([]string{"svc1", "svc3"}); err != nil {
+		t.Fatalf("failed to select the services. Error: %q", err)
+	}
+	want := map[string][]plan.PlanArtifact{
+		"svc1": {{TransformerName: "Golang-Dockerfile"}},
+		"svc3": {{TransformerName: "Buildpacks"}},
+	}
+	if !cmp.Equal(p.Spec.Services, want) {
+		t.Fatalf("the services were not selected properly. Differences:\n%s", cmp.Diff(want, p.Spec.Services))
+	}
+	if _, ok := p.Spec.Readiness["svc2"]; ok {
+		t.Fatalf("expected the readiness of the deselected service to be removed")
+	}
+}
+
+func TestHookGetCommandLine(t *testing.T) {
+	t.Run("command is run without a shell", func(t *testing.T) {
+		hook := plan.Hook{Name: "format", Command: []string{"prettier", "--write", "."}}
+		commandLine, err := hook.GetCommandLine("linux")
+		if err != nil {
+			t.Fatalf("failed to get the command line. Error: %q", err)
+		}
+		if diff := cmp.Diff([]string{"prettier", "--write", "."}, commandLine); diff != "" {
+			t.Fatalf("unexpected command line. Differences:\n%s", diff)
+		}
+	})
+	t.Run("script is run using the shell of the operating system", func(t *testing.T) {
+		hook := plan.Hook{Name: "upload", Script: "echo $MOVE2KUBE_OUTPUT_DIR"}
+		commandLine, err := hook.GetCommandLine("linux")
+		if err != nil {
+			t.Fatalf("failed to get the command line. Error: %q", err)
+		}
+		if diff := cmp.Diff([]string{"sh", "-c", hook.Script}, commandLine); diff != "" {
+			t.Fatalf("unexpected command line. Differences:\n%s", diff)
+		}
+		commandLine, err = hook.GetCommandLine("windows")
+		if err != nil {
+			t.Fatalf("failed to get the command line. Error: %q", err)
+		}
+		if diff := cmp.Diff([]string{"cmd", "/C", hook.Script}, commandLine); diff != "" {
+			t.Fatalf("unexpected command line. Differences:\n%s", diff)
+		}
+	})
+	t.Run("hook needs exactly one of command and script", func(t *testing.T) {
+		if _, err := (plan.Hook{Name: "empty"}).GetCommandLine("linux"); err == nil {
+			t.Fatal("expected an error for a hook without a command and a script")
+		}
+		if _, err := (plan.Hook{Name: "both", Command: []string{"true"}, Script: "true"}).GetCommandLine("linux"); err == nil {
+			t.Fatal("expected an error for a hook with both a command and a script")
+		}
+	})
+}
+
+func TestHooksMerge(t *testing.T) {
+	planHooks := plan.Hooks{PostTransform: []plan.Hook{{Name: "scan", Command: []string{"trivy", "fs", "."}}}}
+	customizationHooks := plan.Hooks{
+		PreDetect:     []plan.Hook{{Name: "clean", Script: "rm -rf build"}},
+		PostTransform: []plan.Hook{{Name: "upload", Script: "./upload.sh"}},
+	}
+	merged := planHooks.Merge(customizationHooks)
+	want := plan.Hooks{
+		PreDetect:     customizationHooks.PreDetect,
+		PostTransform: []plan.Hook{planHooks.PostTransform[0], customizationHooks.PostTransform[0]},
+	}
+	if diff := cmp.Diff(want, merged, cmp.Transformer("nilToEmpty", func(hooks []plan.Hook) []plan.Hook {
+		if hooks == nil {
+			return []plan.Hook{}
+		}
+		return hooks
+	})); diff != "" {
+		t.Fatalf("unexpected merged hooks. Differences:\n%s", diff)
+	}
+	if len(planHooks.PostTransform) != 1 {
+		t.Fatalf("expected the merge to not modify the original hooks. Actual: %+v", planHooks)
+	}
+	if merged.IsEmpty() || !(plan.Hooks{}).IsEmpty() {
+		t.Fatal("IsEmpty returned the wrong result")
+	}
+}
+
+func TestWritePlanWithSourceURL(t *testing.T) {
+	fetchedDir := t.TempDir()
+	p := plan.NewPlan()
+	p.Spec.SourceDir = fetchedDir
+	p.Spec.SourceURL = "git+https://github.com/konveyor/move2kube-demos//samples/language-platforms?ref=main"
+	p.Spec.Services["web"] = []plan.PlanArtifact{{
+		TransformerName: "Golang",
+		Artifact:        transformertypes.Artifact{Paths: map[transformertypes.PathType][]string{"ServiceDirPath": {filepath.Join(fetchedDir, "web")}}},
+	}}
+	planPath := filepath.Join(t.TempDir(), "m2k.plan")
+	if err := plan.WritePlan(planPath, p); err != nil {
+		t.Fatalf("failed to write the plan. Error: %q", err)
+	}
+	written, err := plan.ReadPlan(planPath, "")
+	if err != nil {
+		t.Fatalf("failed to read the plan. Error: %q", err)
+	}
+	if written.Spec.SourceDir != "" || written.Spec.SourceURL != p.Spec.SourceURL {
+		t.Fatalf("expected only the source url to be written. Actual source directory: %s url: %s", written.Spec.SourceDir, written.Spec.SourceURL)
+	}
+	refetchedDir := t.TempDir()
+	refetched, err := plan.ReadPlan(planPath, refetchedDir)
+	if err != nil {
+		t.Fatalf("failed to read the plan. Error: %q", err)
+	}
+	want := []string{filepath.Join(refetchedDir, "web")}
+	if diff := cmp.Diff(want, refetched.Spec.Services["web"][0].Paths["ServiceDirPath"]); diff != "" {
+		t.Fatalf("the service paths were not moved to the fetched source. Difference:\n%s", diff)
+	}
+}
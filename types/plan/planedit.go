@@ -0,0 +1,91 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plan
+
+import (
+	"fmt"
+
+	"github.com/konveyor/move2kube/common"
+)
+
+// RenameService renames a service of the plan along with the artifacts named after it
+func (p *Plan) RenameService(oldName, newName string) error {
+	if oldName == newName {
+		return nil
+	}
+	artifacts, ok := p.Spec.Services[oldName]
+	if !ok {
+		return fmt.Errorf("the service %s is not present in the plan", oldName)
+	}
+	if newName == "" {
+		return fmt.Errorf("the new name of the service %s is empty", oldName)
+	}
+	if _, ok := p.Spec.Services[newName]; ok {
+		return fmt.Errorf("a service named %s is already present in the plan", newName)
+	}
+	for i, artifact := range artifacts {
+		artifacts[i].ServiceName = newName
+		if artifact.Name == oldName {
+			artifacts[i].Name = newName
+		}
+	}
+	delete(p.Spec.Services, oldName)
+	p.Spec.Services[newName] = artifacts
+	if readiness, ok := p.Spec.Readiness[oldName]; ok {
+		delete(p.Spec.Readiness, oldName)
+		p.Spec.Readiness[newName] = readiness
+	}
+	return nil
+}
+
+// SelectServiceTransformers keeps only the artifacts of the service that are processed by the selected transformers.
+// The service is removed from the plan if none of its transformers are selected.
+func (p *Plan) SelectServiceTransformers(serviceName string, transformerNames []string) {
+	artifacts := []PlanArtifact{}
+	for _, artifact := range p.Spec.Services[serviceName] {
+		if common.IsPresent(transformerNames, artifact.TransformerName) {
+			artifacts = append(artifacts, artifact)
+		}
+	}
+	if len(artifacts) == 0 {
+		delete(p.Spec.Services, serviceName)
+		delete(p.Spec.Readiness, serviceName)
+		return
+	}
+	p.Spec.Services[serviceName] = artifacts
+}
+
+// SelectServices keeps only the selected services in the plan.
+// It fails without changing the plan if any of the selected services is not present in the plan.
+func (p *Plan) SelectServices(serviceNames []string) error {
+	missingServiceNames := []string{}
+	for _, serviceName := range serviceNames {
+		if _, ok := p.Spec.Services[serviceName]; !ok {
+			missingServiceNames = append(missingServiceNames, serviceName)
+		}
+	}
+	if len(missingServiceNames) != 0 {
+		return fmt.Errorf("the services %+v are not present in the plan", missingServiceNames)
+	}
+	for serviceName := range p.Spec.Services {
+		if !common.IsPresent(serviceNames, serviceName) {
+			delete(p.Spec.Services, serviceName)
+			delete(p.Spec.Readiness, serviceName)
+		}
+	}
+	return nil
+}
@@ -64,10 +64,16 @@ func WritePlan(path string, plan Plan) error {
 		logrus.Errorf("Unable to get current working dir : %s", err)
 		return err
 	}
-	if plan.Spec.SourceDir != "" {
+	if plan.Spec.SourceURL != "" {
+		// the fetched source is temporary, so only the url is written
+		newPlan.Spec.SourceDir = ""
+	} else if plan.Spec.SourceDir != "" {
 		if newPlan.Spec.SourceDir, err = filepath.Rel(wd, plan.Spec.SourceDir); err != nil {
 			return err
 		}
 	}
+	if plan.Spec.CustomizationsURL != "" {
+		newPlan.Spec.CustomizationsDir = ""
+	}
 	return common.WriteYaml(path, newPlan)
 }
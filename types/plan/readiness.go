@@ -0,0 +1,132 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plan
+
+import "sort"
+
+// ReadinessFactorT is a kind of finding that makes a service harder to migrate
+type ReadinessFactorT string
+
+const (
+	// ReadinessFactorStatefulStorage means the service stores data locally and needs persistent volumes
+	ReadinessFactorStatefulStorage ReadinessFactorT = "StatefulStorage"
+	// ReadinessFactorOSSpecific means the service depends on a specific operating system like Windows
+	ReadinessFactorOSSpecific ReadinessFactorT = "OSSpecific"
+	// ReadinessFactorPrivileged means the service needs privileges, capabilities or host namespaces
+	ReadinessFactorPrivileged ReadinessFactorT = "Privileged"
+	// ReadinessFactorUnsupportedFeature means a feature of the source platform has no equivalent in the output
+	ReadinessFactorUnsupportedFeature ReadinessFactorT = "UnsupportedFeature"
+)
+
+// ComplexityT is a coarse grained measure of the effort needed to migrate a service
+type ComplexityT string
+
+const (
+	// ComplexityLow means the service is expected to migrate without manual changes
+	ComplexityLow ComplexityT = "low"
+	// ComplexityMedium means the service needs some manual changes
+	ComplexityMedium ComplexityT = "medium"
+	// ComplexityHigh means the service needs significant manual changes or redesign
+	ComplexityHigh ComplexityT = "high"
+)
+
+var (
+	readinessFactorPenalties = map[ReadinessFactorT]int{
+		ReadinessFactorStatefulStorage:    20,
+		ReadinessFactorOSSpecific:         30,
+		ReadinessFactorPrivileged:         15,
+		ReadinessFactorUnsupportedFeature: 5,
+	}
+	// readinessFactorMaxPenalties caps the penalty of each factor, so that a single factor found many times doesn't hide the others
+	readinessFactorMaxPenalties = map[ReadinessFactorT]int{
+		ReadinessFactorStatefulStorage:    30,
+		ReadinessFactorOSSpecific:         40,
+		ReadinessFactorPrivileged:         25,
+		ReadinessFactorUnsupportedFeature: 30,
+	}
+)
+
+// ReadinessFinding is a single reason why a service is harder to migrate
+type ReadinessFinding struct {
+	Factor  ReadinessFactorT `yaml:"factor" json:"factor"`
+	Message string           `yaml:"message" json:"message"`
+	Path    string           `yaml:"path,omitempty" json:"path,omitempty"`
+}
+
+// ServiceReadiness is the migration readiness of a service.
+// The score ranges from 0 to 100, where 100 means the service is ready to be migrated as is.
+type ServiceReadiness struct {
+	Score      int                `yaml:"score" json:"score"`
+	Complexity ComplexityT        `yaml:"complexity" json:"complexity"`
+	Findings   []ReadinessFinding `yaml:"findings,omitempty" json:"findings,omitempty"`
+}
+
+// NewServiceReadiness scores a service using its findings.
+// Each factor is penalized once per finding, but repeated findings about the same path are penalized only once.
+func NewServiceReadiness(findings []ReadinessFinding) ServiceReadiness {
+	uniqueFindings := []ReadinessFinding{}
+	seen := map[ReadinessFinding]bool{}
+	for _, finding := range findings {
+		if seen[finding] {
+			continue
+		}
+		seen[finding] = true
+		uniqueFindings = append(uniqueFindings, finding)
+	}
+	sort.SliceStable(uniqueFindings, func(i, j int) bool {
+		if uniqueFindings[i].Factor != uniqueFindings[j].Factor {
+			return uniqueFindings[i].Factor < uniqueFindings[j].Factor
+		}
+		if uniqueFindings[i].Path != uniqueFindings[j].Path {
+			return uniqueFindings[i].Path < uniqueFindings[j].Path
+		}
+		return uniqueFindings[i].Message < uniqueFindings[j].Message
+	})
+	penalties := map[ReadinessFactorT]int{}
+	penalizedPaths := map[ReadinessFinding]bool{}
+	for _, finding := range uniqueFindings {
+		key := ReadinessFinding{Factor: finding.Factor, Path: finding.Path}
+		if finding.Path != "" && penalizedPaths[key] {
+			continue
+		}
+		penalizedPaths[key] = true
+		penalties[finding.Factor] += readinessFactorPenalties[finding.Factor]
+		if max, ok := readinessFactorMaxPenalties[finding.Factor]; ok && penalties[finding.Factor] > max {
+			penalties[finding.Factor] = max
+		}
+	}
+	score := 100
+	for _, penalty := range penalties {
+		score -= penalty
+	}
+	if score < 0 {
+		score = 0
+	}
+	readiness := ServiceReadiness{Score: score, Findings: uniqueFindings}
+	switch {
+	case score >= 80:
+		readiness.Complexity = ComplexityLow
+	case score >= 50:
+		readiness.Complexity = ComplexityMedium
+	default:
+		readiness.Complexity = ComplexityHigh
+	}
+	if len(readiness.Findings) == 0 {
+		readiness.Findings = nil
+	}
+	return readiness
+}
@@ -18,8 +18,8 @@ package qaengine
 
 import (
 	"fmt"
+	"time"
 
-	"github.com/konveyor/move2kube/common"
 	"github.com/konveyor/move2kube/types"
 	"github.com/sirupsen/logrus"
 )
@@ -40,6 +40,16 @@ type CacheSpec struct {
 	persistPasswords bool   `yaml:"-"`
 	// Problems stores the list of problems with resolutions
 	Problems []Problem `yaml:"solutions"`
+	// Timeouts stores the problems that were answered with the default since they were not answered in time
+	Timeouts []CacheTimeout `yaml:"timeouts,omitempty"`
+}
+
+// CacheTimeout records a problem that was answered with the default after a timeout
+type CacheTimeout struct {
+	ID      string      `yaml:"id"`
+	Timeout string      `yaml:"timeout"`
+	Answer  interface{} `yaml:"answer,omitempty"`
+	Time    time.Time   `yaml:"time"`
 }
 
 // NewCache creates new cache instance
@@ -59,7 +69,7 @@ func NewCache(file string, persistPasswords bool) (cache *Cache) {
 // Load loads and merges cache
 func (cache *Cache) Load() error {
 	c := Cache{}
-	if err := common.ReadMove2KubeYaml(cache.Spec.file, &c); err != nil {
+	if err := readEncryptedMove2KubeYaml(cache.Spec.file, &c); err != nil {
 		logrus.Errorf("Unable to load the cache file at path %s Error: %q", cache.Spec.file, err)
 		return err
 	}
@@ -69,7 +79,7 @@ func (cache *Cache) Load() error {
 
 // Write writes cache to disk
 func (cache *Cache) Write() error {
-	err := common.WriteYaml(cache.Spec.file, cache)
+	err := writeEncryptedYaml(cache.Spec.file, cache)
 	if err != nil {
 		logrus.Warnf("Unable to write cache : %s", err)
 	}
@@ -107,6 +117,20 @@ func (cache *Cache) AddSolution(p Problem) error {
 	return nil
 }
 
+// AddTimeout records that the problem was answered with the default since it was not answered within the timeout
+func (cache *Cache) AddTimeout(p Problem, timeout time.Duration) error {
+	t := CacheTimeout{ID: p.ID, Timeout: timeout.String(), Answer: p.Answer, Time: time.Now()}
+	if !cache.Spec.persistPasswords && p.Type == PasswordSolutionFormType {
+		t.Answer = nil
+	}
+	cache.Spec.Timeouts = append(cache.Spec.Timeouts, t)
+	if err := cache.Write(); err != nil {
+		logrus.Errorf("Failed to write to the cache file. Error: %q", err)
+		return err
+	}
+	return nil
+}
+
 // GetSolution reads a solution for the problem
 func (cache *Cache) GetSolution(p Problem) (Problem, error) {
 	if p.Answer != nil {
@@ -123,6 +147,7 @@ func (cache *Cache) GetSolution(p Problem) (Problem, error) {
 }
 
 func (cache *Cache) merge(c Cache) {
+	cache.Spec.Timeouts = append(cache.Spec.Timeouts, c.Spec.Timeouts...)
 	for _, p := range c.Spec.Problems {
 		found := false
 		for _, op := range cache.Spec.Problems {
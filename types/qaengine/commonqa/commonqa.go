@@ -19,6 +19,7 @@ package commonqa
 import (
 	"fmt"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -30,6 +31,47 @@ import (
 	"github.com/spf13/cast"
 )
 
+const (
+	defaultImageTagSource   = "use the default tag"
+	gitCommitImageTagSource = "use the short git commit hash"
+	gitBranchImageTagSource = "use the git branch name"
+	gitTagImageTagSource    = "use the git tag"
+	shortCommitHashLength   = 7
+	maxImageTagLength       = 128
+)
+
+const (
+	// KeylessSigningMode signs using a short lived certificate bound to an OIDC identity
+	KeylessSigningMode = "keyless"
+	// KeySigningMode signs using a cosign key pair
+	KeySigningMode = "key"
+	// defaultSigningIssuer is the issuer of the identity tokens when signing interactively with the public Sigstore instance
+	defaultSigningIssuer = "https://oauth2.sigstore.dev/auth"
+)
+
+const (
+	// PrometheusOperatorMonitoringStack generates ServiceMonitors and PodMonitors along with the scrape annotations
+	PrometheusOperatorMonitoringStack = "prometheus-operator"
+	// AnnotationsMonitoringStack only adds the prometheus.io scrape annotations
+	AnnotationsMonitoringStack = "annotations"
+	// NoMonitoringStack doesn't configure the scraping of the metrics
+	NoMonitoringStack = "none"
+)
+
+// SigningConfig is how the images and manifests are signed with cosign
+type SigningConfig struct {
+	Enabled bool
+	Mode    string
+	// Key is the private key reference used for key based signing, like cosign.key or a KMS uri
+	Key string
+	// Identity is the email or uri of the signer for keyless signing. Empty means any identity.
+	Identity string
+	// Issuer is the OIDC issuer of the signer for keyless signing
+	Issuer string
+}
+
+var invalidImageTagCharsRegex = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
 // ImageRegistry returns Image Registry URL
 func ImageRegistry() string {
 	// DefaultRegistryURL points to the default registry url that will be used
@@ -70,6 +112,53 @@ func ImageRegistryNamespace() string {
 	return qaengine.FetchStringAnswer(common.ConfigImageRegistryNamespaceKey, "Enter the namespace where the new images should be pushed : ", []string{"Ex : " + common.ProjectName}, common.ProjectName, nil)
 }
 
+// ImageTag returns the tag to use for a new image built from the context path
+func ImageTag(contextPath, defaultTag string) string {
+	options := []string{defaultImageTagSource, gitCommitImageTagSource, gitBranchImageTagSource, gitTagImageTagSource}
+	tagSource := qaengine.FetchSelectAnswer(common.ConfigImageTagSourceKey, "How should the new images be tagged?", []string{"The git information is taken from the repo containing the build context of each image.", "The same tags are used in the generated CI pipelines."}, defaultImageTagSource, options, nil)
+	tag := ""
+	switch tagSource {
+	case gitCommitImageTagSource:
+		commitHash, _, err := common.GatherGitRevisionInfo(contextPath)
+		if err != nil {
+			logrus.Debugf("failed to get the git commit for the path %s . Error: %q", contextPath, err)
+		} else if len(commitHash) > shortCommitHashLength {
+			tag = commitHash[:shortCommitHashLength]
+		}
+	case gitBranchImageTagSource:
+		_, _, _, _, branch, err := common.GatherGitInfo(contextPath)
+		if err != nil {
+			logrus.Debugf("failed to get the git branch for the path %s . Error: %q", contextPath, err)
+		}
+		tag = branch
+	case gitTagImageTagSource:
+		_, gitTag, err := common.GatherGitRevisionInfo(contextPath)
+		if err != nil {
+			logrus.Debugf("failed to get the git tag for the path %s . Error: %q", contextPath, err)
+		}
+		tag = gitTag
+	}
+	tag = sanitizeImageTag(tag)
+	if tag == "" {
+		if tagSource != defaultImageTagSource {
+			logrus.Warnf("unable to get the image tag from git for the path %s . Using the tag %s instead.", contextPath, defaultTag)
+		}
+		return defaultTag
+	}
+	return tag
+}
+
+// sanitizeImageTag replaces the characters that are not allowed in image tags, like the / in branch names,
+// and trims the tag to the maximum length. Tags cannot start with a period or a dash.
+func sanitizeImageTag(tag string) string {
+	tag = invalidImageTagCharsRegex.ReplaceAllString(tag, "-")
+	tag = strings.TrimLeft(tag, ".-")
+	if len(tag) > maxImageTagLength {
+		tag = tag[:maxImageTagLength]
+	}
+	return tag
+}
+
 // IngressHost returns Ingress host
 func IngressHost(defaulthost string, clusterQaLabel string) string {
 	key := common.JoinQASubKeys(common.ConfigTargetKey, `"`+clusterQaLabel+`"`, common.ConfigIngressHostKeySuffix)
@@ -144,3 +233,32 @@ func GetPortForService(detectedPorts []int32, qaSubKey string) int32 {
 	}
 	return int32(selectedPort)
 }
+
+// UsesKeyFile returns true if a private key file is used for signing instead of keyless signing or a KMS key
+func (c SigningConfig) UsesKeyFile() bool {
+	return c.Enabled && c.Mode == KeySigningMode && !strings.Contains(c.Key, "://")
+}
+
+// Signing returns how the images and manifests should be signed with cosign
+func Signing() SigningConfig {
+	qaengine.StartGroup("Image signing")
+	defer qaengine.EndGroup()
+	config := SigningConfig{}
+	config.Enabled = qaengine.FetchBoolAnswer(common.ConfigSigningEnableKey, "Do you want to sign the images and manifests using cosign?", []string{"Signing steps are added to the CI pipelines along with scripts to sign locally and example verification policies."}, false, nil)
+	if !config.Enabled {
+		return config
+	}
+	config.Mode = qaengine.FetchSelectAnswer(common.ConfigSigningModeKey, "Select how the images and manifests should be signed:", []string{"keyless uses a short lived certificate bound to an OIDC identity. key uses a cosign key pair."}, KeylessSigningMode, []string{KeylessSigningMode, KeySigningMode}, nil)
+	if config.Mode == KeySigningMode {
+		config.Key = qaengine.FetchStringAnswer(common.ConfigSigningKeyRefKey, "Enter the cosign private key used for signing:", []string{"A file path or a KMS uri like awskms:///alias/mykey. Generate a key pair using cosign generate-key-pair."}, "cosign.key", nil)
+		return config
+	}
+	config.Identity = qaengine.FetchStringAnswer(common.ConfigSigningIdentityKey, "Enter the identity (email or uri) of the signer that the verification policies should allow:", []string{"Leave empty to allow any identity."}, "", nil)
+	config.Issuer = qaengine.FetchStringAnswer(common.ConfigSigningIssuerKey, "Enter the OIDC issuer of the signer:", []string{"Ex : https://token.actions.githubusercontent.com for GitHub Actions"}, defaultSigningIssuer, nil)
+	return config
+}
+
+// MonitoringStack returns how the metrics endpoints of the services should be scraped in the target cluster
+func MonitoringStack() string {
+	return qaengine.FetchSelectAnswer(common.ConfigTargetMonitoringStackKey, "Select the monitoring stack of the target cluster:", []string{"Metrics endpoints were detected in some of the services. prometheus-operator generates ServiceMonitors and PodMonitors in addition to the prometheus.io annotations."}, PrometheusOperatorMonitoringStack, []string{PrometheusOperatorMonitoringStack, AnnotationsMonitoringStack, NoMonitoringStack}, nil)
+}
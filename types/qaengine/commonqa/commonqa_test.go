@@ -0,0 +1,43 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package commonqa
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeImageTag(t *testing.T) {
+	testCases := []struct {
+		name string
+		tag  string
+		want string
+	}{
+		{name: "valid tag", tag: "v1.0.0", want: "v1.0.0"},
+		{name: "branch with slashes", tag: "feature/login", want: "feature-login"},
+		{name: "leading periods and dashes", tag: "-.release", want: "release"},
+		{name: "only invalid characters", tag: "/", want: ""},
+		{name: "too long", tag: strings.Repeat("a", 200), want: strings.Repeat("a", maxImageTagLength)},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := sanitizeImageTag(testCase.tag); got != testCase.want {
+				t.Fatalf("the image tag is incorrect. Expected: %q Actual: %q", testCase.want, got)
+			}
+		})
+	}
+}
@@ -19,7 +19,6 @@ package qaengine
 import (
 	"bytes"
 	"fmt"
-	"os"
 	"reflect"
 	"regexp"
 	"strings"
@@ -45,6 +44,8 @@ type Config struct {
 	writeYamlMap     mapT
 	OutputPath       string
 	persistPasswords bool
+	// Profile is the name of the profile whose answers override the shared answers in the config files
+	Profile string
 }
 
 var arrayIndexRegex = regexp.MustCompile(`^\[(\d+)\]$`)
@@ -55,14 +56,25 @@ var arrayIndexRegex = regexp.MustCompile(`^\[(\d+)\]$`)
 func (c *Config) Load() (err error) {
 	logrus.Debugf("Config.Load")
 	yamlDatas := []string{}
+	profileFound := false
 	// config files specified later override earlier config files
 	for _, configFile := range c.configFiles {
-		yamlData, err := os.ReadFile(configFile)
+		yamlData, err := readConfigFile(configFile)
 		if err != nil {
 			logrus.Errorf("Failed to read the config file %s Error: %q", configFile, err)
 			continue
 		}
-		yamlDatas = append(yamlDatas, string(yamlData))
+		// the profile in a config file overrides the shared answers in the same file
+		profileYamlData, found, err := applyProfile(string(yamlData), c.Profile)
+		if err != nil {
+			logrus.Errorf("Failed to apply the profile %s to the config file %s Error: %q", c.Profile, configFile, err)
+			profileYamlData = string(yamlData)
+		}
+		profileFound = profileFound || found
+		yamlDatas = append(yamlDatas, profileYamlData)
+	}
+	if c.Profile != "" && !profileFound {
+		logrus.Errorf("The profile %s was not found in any of the config files. Only the shared answers will be used.", c.Profile)
 	}
 	// config strings override config files
 	// config strings specified later override earlier config strings
@@ -90,7 +102,15 @@ func (c *Config) Load() (err error) {
 }
 
 func (c *Config) convertAnswer(p Problem, value interface{}) (Problem, error) {
-	p.Answer = value
+	if !c.templatesEnabled() {
+		p.Answer = value
+		return p, nil
+	}
+	renderedValue, err := c.renderAnswer(p, value)
+	if err != nil {
+		return p, fmt.Errorf("failed to evaluate the answer in the config for the problem %s . Error: %w", p.ID, err)
+	}
+	p.Answer = renderedValue
 	return p, nil
 }
 
@@ -185,7 +205,7 @@ func (c *Config) GetSolution(p Problem) (Problem, error) {
 // Write writes the config to disk
 func (c *Config) Write() error {
 	logrus.Debugf("Config.Write write the file out")
-	return common.WriteYaml(c.OutputPath, c.writeYamlMap)
+	return writeEncryptedYaml(c.OutputPath, c.writeYamlMap)
 }
 
 // AddSolution adds a problem to the config
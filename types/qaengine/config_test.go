@@ -0,0 +1,125 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/types/qaengine"
+)
+
+func TestConfigTemplatedAnswers(t *testing.T) {
+	configStrings := []string{
+		`move2kube.configtemplates=true`,
+		`move2kube.env="staging"`,
+		`move2kube.registry="quay.io/myorg-{{ answer \"move2kube.env\" }}"`,
+		`move2kube.image="{{ answer \"move2kube.registry\" }}/app:{{ .move2kube.env | upper }}"`,
+		`move2kube.enabled="{{ eq (answer \"move2kube.env\") \"staging\" }}"`,
+		`move2kube.services=["{{ answer \"move2kube.env\" }}-api", "web"]`,
+		`move2kube.cycle1="{{ answer \"move2kube.cycle2\" }}"`,
+		`move2kube.cycle2="{{ answer \"move2kube.cycle1\" }}"`,
+		`move2kube.home="{{ env \"HOME\" }}"`,
+	}
+	config := qaengine.NewConfig("", configStrings, nil, false)
+	if err := config.Load(); err != nil {
+		t.Fatalf("failed to load the config. Error: %q", err)
+	}
+
+	t.Run("input answer referencing other answers", func(t *testing.T) {
+		p, err := qaengine.NewInputProblem("move2kube.image", "", nil, "", nil)
+		if err != nil {
+			t.Fatalf("failed to create the problem. Error: %q", err)
+		}
+		p, err = config.GetSolution(p)
+		if err != nil {
+			t.Fatalf("failed to get the solution. Error: %q", err)
+		}
+		if want := "quay.io/myorg-staging/app:STAGING"; p.Answer != want {
+			t.Fatalf("expected answer %q, actual %q", want, p.Answer)
+		}
+	})
+
+	t.Run("confirm answer from an expression", func(t *testing.T) {
+		p, err := qaengine.NewConfirmProblem("move2kube.enabled", "", nil, false, nil)
+		if err != nil {
+			t.Fatalf("failed to create the problem. Error: %q", err)
+		}
+		p, err = config.GetSolution(p)
+		if err != nil {
+			t.Fatalf("failed to get the solution. Error: %q", err)
+		}
+		if p.Answer != true {
+			t.Fatalf("expected answer true, actual %v", p.Answer)
+		}
+	})
+
+	t.Run("multi-select answer with expressions", func(t *testing.T) {
+		p, err := qaengine.NewMultiSelectProblem("move2kube.services", "", nil, nil, []string{"staging-api", "web"}, nil)
+		if err != nil {
+			t.Fatalf("failed to create the problem. Error: %q", err)
+		}
+		p, err = config.GetSolution(p)
+		if err != nil {
+			t.Fatalf("failed to get the solution. Error: %q", err)
+		}
+		want := []interface{}{"staging-api", "web"}
+		if !cmp.Equal(p.Answer, want) {
+			t.Fatalf("expected answer %v, actual %v", want, p.Answer)
+		}
+	})
+
+	t.Run("non hermetic functions are not available", func(t *testing.T) {
+		p, err := qaengine.NewInputProblem("move2kube.home", "", nil, "", nil)
+		if err != nil {
+			t.Fatalf("failed to create the problem. Error: %q", err)
+		}
+		if _, err := config.GetSolution(p); err == nil {
+			t.Fatalf("expected an error for a template reading the environment variables")
+		}
+	})
+
+	t.Run("cyclic references", func(t *testing.T) {
+		p, err := qaengine.NewInputProblem("move2kube.cycle1", "", nil, "", nil)
+		if err != nil {
+			t.Fatalf("failed to create the problem. Error: %q", err)
+		}
+		if _, err := config.GetSolution(p); err == nil {
+			t.Fatalf("expected an error for answers that reference each other")
+		}
+	})
+}
+
+func TestConfigTemplatesOptIn(t *testing.T) {
+	config := qaengine.NewConfig("", []string{`move2kube.password="abc{{def"`, `move2kube.home="{{ env \"HOME\" }}"`}, nil, false)
+	if err := config.Load(); err != nil {
+		t.Fatalf("failed to load the config. Error: %q", err)
+	}
+	for key, want := range map[string]string{"move2kube.password": "abc{{def", "move2kube.home": `{{ env "HOME" }}`} {
+		p, err := qaengine.NewInputProblem(key, "", nil, "", nil)
+		if err != nil {
+			t.Fatalf("failed to create the problem. Error: %q", err)
+		}
+		p, err = config.GetSolution(p)
+		if err != nil {
+			t.Fatalf("failed to get the solution. Error: %q", err)
+		}
+		if p.Answer != want {
+			t.Fatalf("expected the answer to be used as is without the %s key. Expected: %q Actual: %q", "move2kube.configtemplates", want, p.Answer)
+		}
+	}
+}
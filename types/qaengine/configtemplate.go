@@ -0,0 +1,109 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+	"github.com/konveyor/move2kube/common"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+)
+
+const templateStartDelim = "{{"
+
+// templatesEnabled returns true if the config opts into evaluating the templates in the answers with move2kube.configtemplates: true.
+// Without it the answers containing {{ are used as is.
+func (c *Config) templatesEnabled() bool {
+	value, ok := c.Get(common.ConfigTemplatesKey)
+	if !ok {
+		return false
+	}
+	enabled, err := cast.ToBoolE(value)
+	if err != nil {
+		logrus.Warnf("the value of %s should be true or false. Actual: %v", common.ConfigTemplatesKey, value)
+		return false
+	}
+	return enabled
+}
+
+// renderValue evaluates the Go template expressions in a config value.
+// Apart from the hermetic sprig functions, a template can use `answer "move2kube.some.key"`
+// to reference another answer in the config and `.` refers to the whole config.
+// A literal {{ can be written as {{ "{{" }} .
+// Example: registry: 'quay.io/{{ answer "move2kube.target.env" }}'
+func (c *Config) renderValue(value interface{}, resolving []string) (interface{}, error) {
+	switch actualValue := value.(type) {
+	case string:
+		if !strings.Contains(actualValue, templateStartDelim) {
+			return actualValue, nil
+		}
+		return c.renderString(actualValue, resolving)
+	case []interface{}:
+		renderedValues := make([]interface{}, len(actualValue))
+		for i, v := range actualValue {
+			renderedValue, err := c.renderValue(v, resolving)
+			if err != nil {
+				return nil, err
+			}
+			renderedValues[i] = renderedValue
+		}
+		return renderedValues, nil
+	}
+	return value, nil
+}
+
+func (c *Config) renderString(tpl string, resolving []string) (string, error) {
+	// the hermetic functions can't read the environment variables or generate random values
+	funcs := sprig.HermeticTxtFuncMap()
+	funcs["answer"] = func(key string) (interface{}, error) {
+		newResolving := append(append([]string{}, resolving...), key)
+		if common.IsPresent(resolving, key) {
+			return nil, fmt.Errorf("the answers reference each other in a cycle: %s", strings.Join(newResolving, " -> "))
+		}
+		value, ok := c.Get(key)
+		if !ok {
+			return nil, fmt.Errorf("no answer found in the config for the key %s", key)
+		}
+		return c.renderValue(value, newResolving)
+	}
+	parsedTemplate, err := template.New("").Option("missingkey=error").Funcs(funcs).Parse(tpl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the template %q . Error: %w", tpl, err)
+	}
+	var renderedBuffer bytes.Buffer
+	if err := parsedTemplate.Execute(&renderedBuffer, c.yamlMap); err != nil {
+		return "", fmt.Errorf("failed to evaluate the template %q . Error: %w", tpl, err)
+	}
+	return renderedBuffer.String(), nil
+}
+
+// renderAnswer evaluates the expressions in the answer and converts it to the type expected by the problem
+func (c *Config) renderAnswer(p Problem, value interface{}) (interface{}, error) {
+	renderedValue, err := c.renderValue(value, []string{p.ID})
+	if err != nil {
+		return value, err
+	}
+	if renderedString, ok := renderedValue.(string); ok && p.Type == ConfirmSolutionFormType {
+		return cast.ToBoolE(strings.TrimSpace(renderedString))
+	}
+	return renderedValue, nil
+}
@@ -0,0 +1,163 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// QAPassphraseEnvVar is the environment variable containing the passphrase the config and the cache files are encrypted with
+	QAPassphraseEnvVar = "M2K_QA_PASSPHRASE"
+	// rsaEncryptedPrefix is the prefix of the files encrypted using a certificate.
+	// It is followed by the length of the encrypted data key as 2 bytes, the data key encrypted using the certificate
+	// and the data encrypted with the data key as the passphrase.
+	rsaEncryptedPrefix = "M2KRSA__"
+	dataKeyLength      = 32
+)
+
+// Encryption contains the keys used to encrypt the config and the cache files written to disk
+// and to decrypt the encrypted config and cache files that are read.
+type Encryption struct {
+	// Passphrase encrypts and decrypts the files using AES 256 CBC mode with Pbkdf key derivation
+	Passphrase string
+	// Certificate is a PEM encoded certificate whose RSA public key encrypts the files. It takes precedence over the passphrase.
+	Certificate string
+	// PrivateKey is a PEM encoded RSA private key that decrypts the files encrypted using the certificate
+	PrivateKey string
+}
+
+var encryption Encryption
+
+// SetEncryption sets the keys used to encrypt the config and the cache files
+func SetEncryption(e Encryption) error {
+	if e.Certificate != "" {
+		if _, err := common.EncryptRsaCert(e.Certificate, []byte("move2kube")); err != nil {
+			return fmt.Errorf("the certificate can't be used for encryption. Error: %w", err)
+		}
+	}
+	encryption = e
+	return nil
+}
+
+// IsEncrypted returns true if the data was encrypted using a passphrase or a certificate
+func IsEncrypted(data []byte) bool {
+	return common.IsOpenSSLSalted(data) || bytes.HasPrefix(data, []byte(rsaEncryptedPrefix))
+}
+
+// encrypt encrypts the data using the certificate or the passphrase. The data is returned as is if neither is set.
+func encrypt(data []byte) ([]byte, error) {
+	if encryption.Certificate != "" {
+		dataKeyBytes := make([]byte, dataKeyLength)
+		if _, err := rand.Read(dataKeyBytes); err != nil {
+			return nil, fmt.Errorf("failed to generate the data key. Error: %w", err)
+		}
+		dataKey := hex.EncodeToString(dataKeyBytes)
+		encryptedDataKey, err := common.EncryptRsaCert(encryption.Certificate, []byte(dataKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt the data key using the certificate. Error: %w", err)
+		}
+		encryptedData, err := common.EncryptAesCbcWithPbkdf(dataKey, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt the data using the data key. Error: %w", err)
+		}
+		out := bytes.NewBufferString(rsaEncryptedPrefix)
+		if err := binary.Write(out, binary.BigEndian, uint16(len(encryptedDataKey))); err != nil {
+			return nil, fmt.Errorf("failed to write the length of the data key. Error: %w", err)
+		}
+		out.Write(encryptedDataKey)
+		out.Write(encryptedData)
+		return out.Bytes(), nil
+	}
+	if encryption.Passphrase != "" {
+		return common.EncryptAesCbcWithPbkdf(encryption.Passphrase, data)
+	}
+	return data, nil
+}
+
+// decrypt decrypts the data if it is encrypted. The data is returned as is if it is not encrypted.
+func decrypt(data []byte) ([]byte, error) {
+	if bytes.HasPrefix(data, []byte(rsaEncryptedPrefix)) {
+		if encryption.PrivateKey == "" {
+			return nil, fmt.Errorf("the data was encrypted using a certificate. A private key is required to decrypt it")
+		}
+		rest := data[len(rsaEncryptedPrefix):]
+		if len(rest) < 2 {
+			return nil, fmt.Errorf("the encrypted data is truncated")
+		}
+		encryptedDataKeyLength := int(binary.BigEndian.Uint16(rest))
+		rest = rest[2:]
+		if len(rest) < encryptedDataKeyLength {
+			return nil, fmt.Errorf("the encrypted data is truncated")
+		}
+		dataKey, err := common.DecryptRsaPrivateKey(encryption.PrivateKey, rest[:encryptedDataKeyLength])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt the data key using the private key. Error: %w", err)
+		}
+		return common.DecryptAesCbcWithPbkdf(string(dataKey), rest[encryptedDataKeyLength:])
+	}
+	if common.IsOpenSSLSalted(data) {
+		if encryption.Passphrase == "" {
+			return nil, fmt.Errorf("the data was encrypted using a passphrase. Set the passphrase in the %s environment variable to decrypt it", QAPassphraseEnvVar)
+		}
+		return common.DecryptAesCbcWithPbkdf(encryption.Passphrase, data)
+	}
+	return data, nil
+}
+
+// writeEncryptedYaml writes the object as yaml, encrypting it if a passphrase or a certificate is set
+func writeEncryptedYaml(outputPath string, data interface{}) error {
+	if encryption.Certificate == "" && encryption.Passphrase == "" {
+		return common.WriteYaml(outputPath, data)
+	}
+	yamlBytes, err := common.ObjectToYamlBytes(data)
+	if err != nil {
+		logrus.Errorf("Failed to encode the object as a yaml string. Error: %q", err)
+		return err
+	}
+	encryptedBytes, err := encrypt(yamlBytes)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt the file %s . Error: %w", outputPath, err)
+	}
+	return os.WriteFile(outputPath, encryptedBytes, common.DefaultFilePermission)
+}
+
+// readEncryptedMove2KubeYaml reads the move2kube yaml file, decrypting it if it is encrypted
+func readEncryptedMove2KubeYaml(path string, out interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logrus.Errorf("Failed to read the yaml file at path %s Error: %q", path, err)
+		return err
+	}
+	if !IsEncrypted(data) {
+		return common.ReadMove2KubeYaml(path, out)
+	}
+	yamlData, err := decrypt(data)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt the file %s . Error: %w", path, err)
+	}
+	return yaml.Unmarshal(yamlData, out)
+}
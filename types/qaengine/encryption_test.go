@@ -0,0 +1,125 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/types/qaengine"
+)
+
+func TestEncryptedCache(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate the private key. Error: %q", err)
+	}
+	template := x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "move2kube"}, NotAfter: time.Now().Add(time.Hour)}
+	certDER, err := x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatalf("failed to create the certificate. Error: %q", err)
+	}
+	certificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}))
+	privateKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}))
+	defer qaengine.SetEncryption(qaengine.Encryption{})
+
+	testcases := []struct {
+		name       string
+		encryption qaengine.Encryption
+		decryption qaengine.Encryption
+	}{
+		{name: "passphrase", encryption: qaengine.Encryption{Passphrase: "passphrase"}, decryption: qaengine.Encryption{Passphrase: "passphrase"}},
+		{name: "certificate", encryption: qaengine.Encryption{Certificate: certificate}, decryption: qaengine.Encryption{PrivateKey: privateKeyPEM}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cachePath := filepath.Join(t.TempDir(), "m2kqacache.yaml")
+			if err := qaengine.SetEncryption(tc.encryption); err != nil {
+				t.Fatalf("failed to set the encryption. Error: %q", err)
+			}
+			prob, err := qaengine.NewInputProblem("move2kube.target.registry.url", "Enter the registry url", nil, "", nil)
+			if err != nil {
+				t.Fatalf("failed to create the problem. Error: %q", err)
+			}
+			prob.Answer = "registry.internal.example.com"
+			if err := qaengine.NewCache(cachePath, false).AddSolution(prob); err != nil {
+				t.Fatalf("failed to add the solution to the cache. Error: %q", err)
+			}
+			data, err := os.ReadFile(cachePath)
+			if err != nil {
+				t.Fatalf("failed to read the cache. Error: %q", err)
+			}
+			if !qaengine.IsEncrypted(data) || strings.Contains(string(data), "registry.internal.example.com") {
+				t.Fatalf("expected the cache to be encrypted. Actual: %q", data)
+			}
+
+			qaengine.SetEncryption(qaengine.Encryption{})
+			if err := qaengine.NewCache(cachePath, false).Load(); err == nil {
+				t.Fatalf("expected an error when loading the encrypted cache without the keys")
+			}
+			if err := qaengine.SetEncryption(tc.decryption); err != nil {
+				t.Fatalf("failed to set the decryption. Error: %q", err)
+			}
+			cache := qaengine.NewCache(cachePath, false)
+			if err := cache.Load(); err != nil {
+				t.Fatalf("failed to load the encrypted cache. Error: %q", err)
+			}
+			prob.Answer = nil
+			solved, err := cache.GetSolution(prob)
+			if err != nil || solved.Answer != "registry.internal.example.com" {
+				t.Fatalf("expected the answer from the encrypted cache. Actual: %+v Error: %v", solved.Answer, err)
+			}
+		})
+	}
+}
+
+func TestEncryptedConfig(t *testing.T) {
+	defer qaengine.SetEncryption(qaengine.Encryption{})
+	encrypted, err := common.EncryptAesCbcWithPbkdf("passphrase", []byte("move2kube:\n  target:\n    registry:\n      url: registry.internal.example.com\n"))
+	if err != nil {
+		t.Fatalf("failed to encrypt the config. Error: %q", err)
+	}
+	configPath := filepath.Join(t.TempDir(), "m2kconfig.yaml")
+	if err := os.WriteFile(configPath, encrypted, common.DefaultFilePermission); err != nil {
+		t.Fatalf("failed to write the config. Error: %q", err)
+	}
+	if err := qaengine.SetEncryption(qaengine.Encryption{Passphrase: "passphrase"}); err != nil {
+		t.Fatalf("failed to set the encryption. Error: %q", err)
+	}
+	config := qaengine.NewConfig("", nil, []string{configPath}, false)
+	if err := config.Load(); err != nil {
+		t.Fatalf("failed to load the encrypted config. Error: %q", err)
+	}
+	prob, err := qaengine.NewInputProblem("move2kube.target.registry.url", "Enter the registry url", nil, "", nil)
+	if err != nil {
+		t.Fatalf("failed to create the problem. Error: %q", err)
+	}
+	solved, err := config.GetSolution(prob)
+	if err != nil || solved.Answer != "registry.internal.example.com" {
+		t.Fatalf("expected the answer from the encrypted config. Actual: %+v Error: %v", solved.Answer, err)
+	}
+}
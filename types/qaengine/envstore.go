@@ -0,0 +1,110 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/sirupsen/logrus"
+)
+
+// EnvVarPrefix is the prefix of the environment variables that answer the QA problems
+const EnvVarPrefix = "M2K_QA_"
+
+var invalidEnvVarCharsRegex = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// EnvStore answers the problems using the environment variables named after the QA keys
+type EnvStore struct {
+	lookupEnv func(string) (string, bool)
+}
+
+// NewEnvStore creates a new store that answers using the environment variables
+func NewEnvStore() *EnvStore {
+	return &EnvStore{lookupEnv: os.LookupEnv}
+}
+
+// GetEnvVarName returns the name of the environment variable that answers the problem with the QA key.
+// Example: move2kube.target.imageregistry.url is answered by M2K_QA_TARGET_IMAGEREGISTRY_URL
+func GetEnvVarName(key string) string {
+	subKeys := getSubKeys(key)
+	if len(subKeys) > 1 && subKeys[0] == common.BaseKey {
+		subKeys = subKeys[1:]
+	}
+	name := strings.ToUpper(strings.Join(subKeys, "_"))
+	return EnvVarPrefix + invalidEnvVarCharsRegex.ReplaceAllString(name, "_")
+}
+
+// Load does nothing since the environment variables are looked up when the problems are asked
+func (*EnvStore) Load() error {
+	return nil
+}
+
+// GetSolution reads the answer from the environment variable of the problem.
+// The answers to multi select problems are comma separated, the answers to confirm problems are booleans
+// and the answers to table problems are json lists of rows.
+func (e *EnvStore) GetSolution(p Problem) (Problem, error) {
+	if strings.Contains(p.ID, common.Special) {
+		return p, fmt.Errorf("the problems with the %s selector can't be answered using environment variables: %+v", common.Special, p)
+	}
+	name := GetEnvVarName(p.ID)
+	value, ok := e.lookupEnv(name)
+	if !ok {
+		return p, fmt.Errorf("the environment variable %s is not set for the problem: %+v", name, p)
+	}
+	logrus.Debugf("answering the problem %s using the environment variable %s", p.ID, name)
+	switch p.Type {
+	case MultiSelectSolutionFormType:
+		answers := []string{}
+		for _, answer := range strings.Split(value, ",") {
+			if answer = strings.TrimSpace(answer); answer != "" {
+				answers = append(answers, answer)
+			}
+		}
+		p.Answer = answers
+	case ConfirmSolutionFormType:
+		answer, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return p, &ValidationError{Reason: fmt.Sprintf("the environment variable %s should be a boolean. Actual value: %s", name, value)}
+		}
+		p.Answer = answer
+	case TableSolutionFormType:
+		rows := []interface{}{}
+		if err := json.Unmarshal([]byte(value), &rows); err != nil {
+			return p, &ValidationError{Reason: fmt.Sprintf("the environment variable %s should be a json list of rows. Error: %s", name, err)}
+		}
+		p.Answer = rows
+	default:
+		p.Answer = value
+	}
+	return p, nil
+}
+
+// Write does nothing since the answers are not written to the environment
+func (*EnvStore) Write() error {
+	return nil
+}
+
+// AddSolution does nothing since the answers are not written to the environment
+func (*EnvStore) AddSolution(p Problem) error {
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/types/qaengine"
+)
+
+func TestGetEnvVarName(t *testing.T) {
+	testcases := map[string]string{
+		"move2kube.target.imageregistry.url":         "M2K_QA_TARGET_IMAGEREGISTRY_URL",
+		`move2kube.target."quay.io".logintype`:       "M2K_QA_TARGET_QUAY_IO_LOGINTYPE",
+		`move2kube.services."my-svc".dockerfileType`: "M2K_QA_SERVICES_MY_SVC_DOCKERFILETYPE",
+		"move2kube": "M2K_QA_MOVE2KUBE",
+		"other.key": "M2K_QA_OTHER_KEY",
+	}
+	for key, want := range testcases {
+		if name := qaengine.GetEnvVarName(key); name != want {
+			t.Errorf("the environment variable name for the key %s is incorrect. Expected: %s Actual: %s", key, want, name)
+		}
+	}
+}
+
+func TestEnvStore(t *testing.T) {
+	store := qaengine.NewEnvStore()
+
+	t.Run("input problem", func(t *testing.T) {
+		t.Setenv("M2K_QA_TARGET_IMAGEREGISTRY_URL", "docker.io")
+		problem, err := qaengine.NewInputProblem("move2kube.target.imageregistry.url", "desc", nil, "quay.io", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		problem, err = store.GetSolution(problem)
+		if err != nil {
+			t.Fatalf("failed to get the solution. Error: %q", err)
+		}
+		if problem.Answer != "docker.io" {
+			t.Fatalf("the answer is incorrect. Actual: %+v", problem.Answer)
+		}
+	})
+
+	t.Run("multi select problem", func(t *testing.T) {
+		t.Setenv("M2K_QA_SERVICES_SELECTED", "api, web,,")
+		problem, err := qaengine.NewMultiSelectProblem("move2kube.services.selected", "desc", nil, nil, []string{"api", "web", "db"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		problem, err = store.GetSolution(problem)
+		if err != nil {
+			t.Fatalf("failed to get the solution. Error: %q", err)
+		}
+		if !cmp.Equal(problem.Answer, []string{"api", "web"}) {
+			t.Fatalf("the answer is incorrect. Actual: %+v", problem.Answer)
+		}
+	})
+
+	t.Run("confirm problem", func(t *testing.T) {
+		t.Setenv("M2K_QA_SIGNING_ENABLE", "true")
+		problem, err := qaengine.NewConfirmProblem("move2kube.signing.enable", "desc", nil, false, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		problem, err = store.GetSolution(problem)
+		if err != nil {
+			t.Fatalf("failed to get the solution. Error: %q", err)
+		}
+		if problem.Answer != true {
+			t.Fatalf("the answer is incorrect. Actual: %+v", problem.Answer)
+		}
+		t.Setenv("M2K_QA_SIGNING_ENABLE", "maybe")
+		problem.Answer = nil
+		if _, err := store.GetSolution(problem); err == nil {
+			t.Fatalf("expected an error for a value that is not a boolean")
+		}
+	})
+
+	t.Run("unset environment variable", func(t *testing.T) {
+		problem, err := qaengine.NewInputProblem("move2kube.unset.key", "desc", nil, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.GetSolution(problem); err == nil {
+			t.Fatalf("expected an error when the environment variable is not set")
+		}
+	})
+}
@@ -17,7 +17,11 @@
 package qaengine
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -44,6 +48,10 @@ const (
 	PasswordSolutionFormType SolutionFormType = "Password"
 	// ConfirmSolutionFormType allows yes/no answers
 	ConfirmSolutionFormType SolutionFormType = "Confirm"
+	// FilePathSolutionFormType allows the path of a file or directory as the answer
+	FilePathSolutionFormType SolutionFormType = "FilePath"
+	// TableSolutionFormType allows a list of rows with typed columns as the answer
+	TableSolutionFormType SolutionFormType = "Table"
 )
 
 const (
@@ -51,16 +59,87 @@ const (
 	OtherAnswer = "Other (specify custom option)"
 )
 
-// Problem defines the QA problem
+// Problem defines the QA problem. The problem is asked only if its When expression is true, see EvaluateWhen.
+// The Timeout is a duration like 30s after which the default answer is used if an interactive engine has not answered.
 type Problem struct {
-	ID        string                  `yaml:"id" json:"id"`
-	Type      SolutionFormType        `yaml:"type,omitempty" json:"type,omitempty"`
-	Desc      string                  `yaml:"description,omitempty" json:"description,omitempty"`
-	Hints     []string                `yaml:"hints,omitempty" json:"hints,omitempty"`
-	Options   []string                `yaml:"options,omitempty" json:"options,omitempty"`
-	Default   interface{}             `yaml:"default,omitempty" json:"default,omitempty"`
-	Answer    interface{}             `yaml:"answer,omitempty" json:"answer,omitempty"`
-	Validator func(interface{}) error `yaml:"-" json:"-"`
+	ID                string                  `yaml:"id" json:"id"`
+	Type              SolutionFormType        `yaml:"type,omitempty" json:"type,omitempty"`
+	Desc              string                  `yaml:"description,omitempty" json:"description,omitempty"`
+	Hints             []string                `yaml:"hints,omitempty" json:"hints,omitempty"`
+	Options           []string                `yaml:"options,omitempty" json:"options,omitempty"`
+	Default           interface{}             `yaml:"default,omitempty" json:"default,omitempty"`
+	Answer            interface{}             `yaml:"answer,omitempty" json:"answer,omitempty"`
+	FilePathOptions   *FilePathOptions        `yaml:"filePathOptions,omitempty" json:"filePathOptions,omitempty"`
+	TableOptions      *TableOptions           `yaml:"tableOptions,omitempty" json:"tableOptions,omitempty"`
+	ValidationRegex   string                  `yaml:"validationRegex,omitempty" json:"validationRegex,omitempty"`
+	ValidationMessage string                  `yaml:"validationMessage,omitempty" json:"validationMessage,omitempty"`
+	Group             []string                `yaml:"group,omitempty" json:"group,omitempty"`
+	When              string                  `yaml:"when,omitempty" json:"when,omitempty"`
+	Timeout           string                  `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	Category          string                  `yaml:"category,omitempty" json:"category,omitempty"`
+	Validator         func(interface{}) error `yaml:"-" json:"-"`
+}
+
+// FilePathOptions restricts the paths accepted as the answer of a FilePath problem
+type FilePathOptions struct {
+	// MustExist rejects the paths that don't exist
+	MustExist bool `yaml:"mustExist,omitempty" json:"mustExist,omitempty"`
+	// Directory accepts only directories instead of only files
+	Directory bool `yaml:"directory,omitempty" json:"directory,omitempty"`
+	// Extensions are the accepted file extensions like .pem , any extension is accepted if empty
+	Extensions []string `yaml:"extensions,omitempty" json:"extensions,omitempty"`
+}
+
+// ValidatePath checks that the path is allowed by the options. An empty path is always allowed.
+func (o FilePathOptions) ValidatePath(path string) error {
+	if path == "" {
+		return nil
+	}
+	if !o.Directory && len(o.Extensions) > 0 && !o.HasExtension(path) {
+		return fmt.Errorf("the file %s does not have one of the extensions %s", path, strings.Join(o.Extensions, ", "))
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) && !o.MustExist {
+			return nil
+		}
+		return fmt.Errorf("the path %s does not exist. Error: %w", path, err)
+	}
+	if o.Directory && !fi.IsDir() {
+		return fmt.Errorf("the path %s is not a directory", path)
+	}
+	if !o.Directory && fi.IsDir() {
+		return fmt.Errorf("the path %s is a directory instead of a file", path)
+	}
+	return nil
+}
+
+// HasExtension returns true if the path has one of the extensions or if there are no extensions
+func (o FilePathOptions) HasExtension(path string) bool {
+	if len(o.Extensions) == 0 {
+		return true
+	}
+	ext := filepath.Ext(path)
+	for _, allowed := range o.Extensions {
+		if strings.EqualFold(ext, allowed) || strings.EqualFold(ext, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilePathEntry is a file or directory that can be chosen as the answer of a FilePath problem
+type FilePathEntry struct {
+	Name  string `yaml:"name" json:"name"`
+	Path  string `yaml:"path" json:"path"`
+	IsDir bool   `yaml:"isDir,omitempty" json:"isDir,omitempty"`
+}
+
+// FilePathListing is the contents of a directory browsed while answering a FilePath problem
+type FilePathListing struct {
+	Path    string          `yaml:"path" json:"path"`
+	Parent  string          `yaml:"parent,omitempty" json:"parent,omitempty"`
+	Entries []FilePathEntry `yaml:"entries" json:"entries"`
 }
 
 // NewProblem creates a new problem object from a GRPC problem
@@ -103,7 +182,7 @@ func InterfaceToArray(ansI interface{}, problemType SolutionFormType) (ans []str
 		return nil, fmt.Errorf("the answer is nil")
 	}
 	switch problemType {
-	case InputSolutionFormType, PasswordSolutionFormType, MultilineInputSolutionFormType, SelectSolutionFormType:
+	case InputSolutionFormType, PasswordSolutionFormType, MultilineInputSolutionFormType, SelectSolutionFormType, FilePathSolutionFormType:
 		ans, ok := ansI.(string)
 		if !ok {
 			return nil, fmt.Errorf("expected answer to be string. Actual value %+v is of type %T", ansI, ansI)
@@ -121,6 +200,28 @@ func InterfaceToArray(ansI interface{}, problemType SolutionFormType) (ans []str
 			return nil, fmt.Errorf("expected answer to be an array of strings. Error: %q", err)
 		}
 		return ans, nil
+	case TableSolutionFormType:
+		// each row is a json object
+		rows := []interface{}{}
+		switch actualRows := ansI.(type) {
+		case []map[string]interface{}:
+			for _, row := range actualRows {
+				rows = append(rows, row)
+			}
+		case []interface{}:
+			rows = actualRows
+		default:
+			return nil, fmt.Errorf("expected answer to be a list of rows. Actual value %+v is of type %T", ansI, ansI)
+		}
+		ans := []string{}
+		for _, row := range rows {
+			rowBytes, err := json.Marshal(row)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal the row %+v to json. Error: %q", row, err)
+			}
+			ans = append(ans, string(rowBytes))
+		}
+		return ans, nil
 	default:
 		return nil, fmt.Errorf("unsupported QA problem type %+v", problemType)
 	}
@@ -128,11 +229,11 @@ func InterfaceToArray(ansI interface{}, problemType SolutionFormType) (ans []str
 
 // ArrayToInterface converts the answer array to interface
 func ArrayToInterface(ans []string, problemType SolutionFormType) (ansI interface{}, err error) {
-	if ansI == nil {
+	if ans == nil {
 		return nil, nil
 	}
 	switch problemType {
-	case InputSolutionFormType, PasswordSolutionFormType, MultilineInputSolutionFormType, SelectSolutionFormType:
+	case InputSolutionFormType, PasswordSolutionFormType, MultilineInputSolutionFormType, SelectSolutionFormType, FilePathSolutionFormType:
 		if len(ans) == 0 {
 			return "", nil
 		}
@@ -144,23 +245,56 @@ func ArrayToInterface(ans []string, problemType SolutionFormType) (ansI interfac
 		return cast.ToBoolE(ans[0])
 	case MultiSelectSolutionFormType:
 		return ans, nil
+	case TableSolutionFormType:
+		rows := []map[string]interface{}{}
+		for _, rowStr := range ans {
+			row := map[string]interface{}{}
+			if err := json.Unmarshal([]byte(rowStr), &row); err != nil {
+				return nil, fmt.Errorf("expected the row %s to be a json object. Error: %q", rowStr, err)
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
 	default:
 		return nil, fmt.Errorf("unsupported QA problem type %+v", problemType)
 	}
 }
 
+// Validate checks the answer using the validation regex and the validator of the problem.
+// The validation regex only applies to the string answers and the validation message is the error when it doesn't match.
+func (p *Problem) Validate(ans interface{}) error {
+	if p.ValidationRegex != "" {
+		if ansStr, ok := ans.(string); ok {
+			reg, err := regexp.Compile(p.ValidationRegex)
+			if err != nil {
+				return fmt.Errorf("the validation regex %s of the problem %s is invalid. Error: %w", p.ValidationRegex, p.ID, err)
+			}
+			if !reg.MatchString(ansStr) {
+				if p.ValidationMessage != "" {
+					return errors.New(p.ValidationMessage)
+				}
+				return fmt.Errorf("the answer %s does not match the pattern %s", ansStr, p.ValidationRegex)
+			}
+		}
+	}
+	if p.Validator != nil {
+		return p.Validator(ans)
+	}
+	return nil
+}
+
 // SetAnswer sets the answer
 func (p *Problem) SetAnswer(ansI interface{}, validate bool) error {
 	if ansI == nil {
 		return fmt.Errorf("the answer is nil")
 	}
-	if validate && p.Validator != nil {
-		if err := p.Validator(ansI); err != nil {
+	if validate {
+		if err := p.Validate(ansI); err != nil {
 			return &ValidationError{Reason: err.Error()}
 		}
 	}
 	switch p.Type {
-	case InputSolutionFormType, PasswordSolutionFormType, MultilineInputSolutionFormType, SelectSolutionFormType:
+	case InputSolutionFormType, PasswordSolutionFormType, MultilineInputSolutionFormType, SelectSolutionFormType, FilePathSolutionFormType:
 		ans, ok := ansI.(string)
 		if !ok {
 			return fmt.Errorf("expected answer to be string. Actual value %+v is of type %T", ansI, ansI)
@@ -193,6 +327,15 @@ func (p *Problem) SetAnswer(ansI interface{}, validate bool) error {
 		}
 		p.Answer = filteredAns
 		logrus.Debugf("Answering multiselect question %s with %+v", p.ID, p.Answer)
+	case TableSolutionFormType:
+		if p.TableOptions == nil {
+			return fmt.Errorf("the table problem %s has no columns", p.ID)
+		}
+		rows, err := p.TableOptions.NormalizeRows(ansI)
+		if err != nil {
+			return err
+		}
+		p.Answer = rows
 	default:
 		return fmt.Errorf("unsupported QA problem type %+v", p.Type)
 	}
@@ -309,3 +452,57 @@ func NewPasswordProblem(probid, desc string, hints []string, validator func(inte
 		Validator: validator,
 	}, nil
 }
+
+// NewFilePathProblem creates a new instance of file path problem
+func NewFilePathProblem(probid, desc string, hints []string, def string, options FilePathOptions, validator func(interface{}) error) (Problem, error) {
+	return Problem{
+		ID:              probid,
+		Type:            FilePathSolutionFormType,
+		Desc:            desc,
+		Hints:           hints,
+		Options:         nil,
+		Default:         def,
+		Answer:          nil,
+		FilePathOptions: &options,
+		Validator: func(ans interface{}) error {
+			path, ok := ans.(string)
+			if !ok {
+				return fmt.Errorf("expected the answer to be a string. Actual value %+v is of type %T", ans, ans)
+			}
+			if err := options.ValidatePath(path); err != nil {
+				return err
+			}
+			if validator != nil {
+				return validator(ans)
+			}
+			return nil
+		},
+	}, nil
+}
+
+// NewTableProblem creates a new instance of table problem. The validator is called with the rows after they are normalized.
+func NewTableProblem(probid, desc string, hints []string, def []map[string]interface{}, options TableOptions, validator func(interface{}) error) (Problem, error) {
+	if err := options.Validate(); err != nil {
+		return Problem{}, fmt.Errorf("the columns of the table problem %s are invalid. Error: %w", probid, err)
+	}
+	return Problem{
+		ID:           probid,
+		Type:         TableSolutionFormType,
+		Desc:         desc,
+		Hints:        hints,
+		Options:      nil,
+		Default:      def,
+		Answer:       nil,
+		TableOptions: &options,
+		Validator: func(ans interface{}) error {
+			rows, err := options.NormalizeRows(ans)
+			if err != nil {
+				return err
+			}
+			if validator != nil {
+				return validator(rows)
+			}
+			return nil
+		},
+	}, nil
+}
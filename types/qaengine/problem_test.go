@@ -0,0 +1,78 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine_test
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/types/qaengine"
+)
+
+func TestValidationRegex(t *testing.T) {
+	prob, err := qaengine.NewInputProblem("move2kube.services.api.name", "Enter the name of the service", nil, "api", nil)
+	if err != nil {
+		t.Fatalf("failed to create the problem. Error: %q", err)
+	}
+	prob.ValidationRegex = "^[a-z0-9-]+$"
+
+	t.Run("matching answer", func(t *testing.T) {
+		p := prob
+		if err := p.SetAnswer("my-api", true); err != nil {
+			t.Fatalf("expected the answer to be accepted. Error: %q", err)
+		}
+	})
+
+	t.Run("answer that does not match", func(t *testing.T) {
+		p := prob
+		err := p.SetAnswer("My_API", true)
+		if _, ok := err.(*qaengine.ValidationError); !ok {
+			t.Fatalf("expected a validation error. Actual: %v", err)
+		}
+	})
+
+	t.Run("custom validation message", func(t *testing.T) {
+		p := prob
+		p.ValidationMessage = "the name must only contain lowercase letters, digits and dashes"
+		if err := p.Validate("My_API"); err == nil || err.Error() != p.ValidationMessage {
+			t.Fatalf("expected the error %q. Actual: %v", p.ValidationMessage, err)
+		}
+	})
+
+	t.Run("validation skipped", func(t *testing.T) {
+		p := prob
+		if err := p.SetAnswer("My_API", false); err != nil {
+			t.Fatalf("expected the answer to be accepted without validation. Error: %q", err)
+		}
+	})
+
+	t.Run("validator runs after the regex", func(t *testing.T) {
+		p := prob
+		called := false
+		p.Validator = func(interface{}) error { called = true; return nil }
+		if err := p.Validate("my-api"); err != nil || !called {
+			t.Fatalf("expected the validator to be called. Called: %t Error: %v", called, err)
+		}
+	})
+
+	t.Run("invalid regex", func(t *testing.T) {
+		p := prob
+		p.ValidationRegex = "[a-z"
+		if err := p.Validate("my-api"); err == nil {
+			t.Fatalf("expected an error for the invalid regex")
+		}
+	})
+}
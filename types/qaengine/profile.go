@@ -0,0 +1,81 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// ProfilesKey is the top level key of the config files containing the named profiles.
+// Example: With the config below and the profile prod, the answer to move2kube.minreplicas is 3
+//
+//	move2kube:
+//	  minreplicas: 1
+//	profiles:
+//	  prod:
+//	    move2kube:
+//	      minreplicas: 3
+const ProfilesKey = "profiles"
+
+// applyProfile layers the answers in the profile over the shared answers of the config file and removes the profiles.
+// It returns true if the config file has the profile.
+func applyProfile(yamlData, profile string) (string, bool, error) {
+	config := mapT{}
+	if err := yaml.Unmarshal([]byte(yamlData), &config); err != nil {
+		return yamlData, false, fmt.Errorf("failed to parse the config as yaml. Error: %w", err)
+	}
+	profilesI, ok := config[ProfilesKey]
+	if !ok {
+		return yamlData, false, nil
+	}
+	delete(config, ProfilesKey)
+	found := false
+	if profile != "" {
+		profiles, ok := profilesI.(mapT)
+		if !ok {
+			return yamlData, false, fmt.Errorf("expected the %s key to be a map of profile names to answers. Actual value is of type %T", ProfilesKey, profilesI)
+		}
+		if profileConfigI, ok := profiles[profile]; ok {
+			profileConfig, ok := profileConfigI.(mapT)
+			if !ok && profileConfigI != nil {
+				return yamlData, false, fmt.Errorf("expected the profile %s to be a map. Actual value is of type %T", profile, profileConfigI)
+			}
+			merge(config, profileConfig)
+			found = true
+		} else {
+			logrus.Debugf("the profile %s was not found in the config. Available profiles: %+v", profile, getProfileNames(profiles))
+		}
+	}
+	yamlBytes, err := yaml.Marshal(config)
+	if err != nil {
+		return yamlData, false, fmt.Errorf("failed to marshal the config with the profile %s applied. Error: %w", profile, err)
+	}
+	return string(yamlBytes), found, nil
+}
+
+func getProfileNames(profiles mapT) []string {
+	names := []string{}
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,119 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/move2kube/types/qaengine"
+)
+
+func TestConfigProfiles(t *testing.T) {
+	dir := t.TempDir()
+	sharedConfig := filepath.Join(dir, "shared.yaml")
+	sharedYaml := `move2kube:
+  minreplicas: 1
+  target:
+    imageregistry:
+      url: quay.io
+      namespace: team
+profiles:
+  dev:
+    move2kube:
+      target:
+        imageregistry:
+          namespace: team-dev
+  prod:
+    move2kube:
+      minreplicas: 3
+`
+	overrideConfig := filepath.Join(dir, "override.yaml")
+	overrideYaml := `move2kube:
+  target:
+    imageregistry:
+      url: us.icr.io
+profiles:
+  prod:
+    move2kube:
+      minreplicas: 5
+`
+	if err := os.WriteFile(sharedConfig, []byte(sharedYaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(overrideConfig, []byte(overrideYaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	testcases := []struct {
+		name    string
+		profile string
+		files   []string
+		want    map[string]interface{}
+	}{
+		{
+			name:  "without a profile only the shared answers are used",
+			files: []string{sharedConfig},
+			want:  map[string]interface{}{"move2kube.minreplicas": 1, "move2kube.target.imageregistry.namespace": "team", "profiles": nil},
+		},
+		{
+			name:    "the profile overrides the shared answers and keeps the others",
+			profile: "dev",
+			files:   []string{sharedConfig},
+			want:    map[string]interface{}{"move2kube.minreplicas": 1, "move2kube.target.imageregistry.namespace": "team-dev", "move2kube.target.imageregistry.url": "quay.io"},
+		},
+		{
+			name:    "later config files override the profile of earlier config files",
+			profile: "dev",
+			files:   []string{sharedConfig, overrideConfig},
+			want:    map[string]interface{}{"move2kube.target.imageregistry.namespace": "team-dev", "move2kube.target.imageregistry.url": "us.icr.io"},
+		},
+		{
+			name:    "the profile of later config files overrides the profile of earlier config files",
+			profile: "prod",
+			files:   []string{sharedConfig, overrideConfig},
+			want:    map[string]interface{}{"move2kube.minreplicas": 5, "move2kube.target.imageregistry.namespace": "team"},
+		},
+		{
+			name:    "a missing profile falls back to the shared answers",
+			profile: "stage",
+			files:   []string{sharedConfig},
+			want:    map[string]interface{}{"move2kube.minreplicas": 1, "move2kube.target.imageregistry.namespace": "team"},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := qaengine.NewConfig("", nil, tc.files, false)
+			config.Profile = tc.profile
+			if err := config.Load(); err != nil {
+				t.Fatalf("failed to load the config. Error: %q", err)
+			}
+			for key, want := range tc.want {
+				value, ok := config.Get(key)
+				if want == nil {
+					if ok {
+						t.Fatalf("expected the key %s to be removed. Actual: %+v", key, value)
+					}
+					continue
+				}
+				if !ok || value != want {
+					t.Fatalf("the value of the key %s is incorrect. Expected: %+v Actual: %+v", key, want, value)
+				}
+			}
+		})
+	}
+}
@@ -19,7 +19,10 @@ Package qaengine contains the types used for the question answering part of the
 */
 package qaengine
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Store helps store answers
 type Store interface {
@@ -30,6 +33,11 @@ type Store interface {
 	AddSolution(p Problem) error
 }
 
+// TimeoutStore is implemented by the stores that record the questions that were answered with the default after a timeout
+type TimeoutStore interface {
+	AddTimeout(p Problem, timeout time.Duration) error
+}
+
 // ValidationError is the error while validating answer in QA Engine
 type ValidationError struct {
 	Reason string
@@ -0,0 +1,177 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// RemoteConfigTokenEnvVar is the environment variable containing the bearer token sent while fetching the remote config files
+	RemoteConfigTokenEnvVar = "M2K_CONFIG_TOKEN"
+	// remoteConfigSHA256FragmentKey is the url fragment key used to specify the expected checksum of the config file
+	// Example: https://example.com/presets/base.yaml#sha256=9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+	remoteConfigSHA256FragmentKey = "sha256"
+	remoteConfigCacheDir          = "remoteconfigs"
+	remoteConfigTimeout           = 30 * time.Second
+)
+
+// IsRemoteConfigPath returns true if the config file is a http(s) url
+func IsRemoteConfigPath(configPath string) bool {
+	return strings.HasPrefix(configPath, "http://") || strings.HasPrefix(configPath, "https://")
+}
+
+// readConfigFile reads the local config file or fetches the remote one, decrypting it if it is encrypted
+func readConfigFile(configPath string) ([]byte, error) {
+	var data []byte
+	var err error
+	if !IsRemoteConfigPath(configPath) {
+		data, err = os.ReadFile(configPath)
+	} else {
+		data, err = fetchRemoteConfig(configPath)
+	}
+	if err != nil || !IsEncrypted(data) {
+		return data, err
+	}
+	return decrypt(data)
+}
+
+// fetchRemoteConfig downloads the config file, verifying the checksum if the url ends with #sha256=<hex digest>.
+// The config is cached in the user cache directory and revalidated using its ETag.
+// The cached config is used if the server can't be reached.
+func fetchRemoteConfig(configURL string) ([]byte, error) {
+	u, err := url.Parse(configURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the config url. Error: %w", err)
+	}
+	expectedSHA256 := ""
+	if u.Fragment != "" {
+		fragment, err := url.ParseQuery(u.Fragment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the fragment of the config url %s . Error: %w", u.Redacted(), err)
+		}
+		expectedSHA256 = strings.ToLower(fragment.Get(remoteConfigSHA256FragmentKey))
+		if _, err := hex.DecodeString(expectedSHA256); err != nil || (expectedSHA256 != "" && len(expectedSHA256) != sha256.Size*2) {
+			return nil, fmt.Errorf("the sha256 checksum %s in the config url %s is not a valid hex encoded sha256 digest", expectedSHA256, u.Redacted())
+		}
+		u.Fragment = ""
+	}
+	cachePath := getRemoteConfigCachePath(u.String())
+	var cachedData []byte
+	etag := ""
+	if cachePath != "" {
+		if cachedData, err = os.ReadFile(cachePath); err == nil {
+			if etagBytes, err := os.ReadFile(cachePath + ".etag"); err == nil {
+				etag = string(etagBytes)
+			}
+		}
+	}
+	data, newETag, err := downloadRemoteConfig(u, etag)
+	downloaded := err == nil && data != nil
+	if err != nil {
+		if cachedData == nil {
+			return nil, err
+		}
+		logrus.Warnf("Using the cached copy of the config %s since it could not be fetched. Error: %q", u.Redacted(), err)
+		data = cachedData
+	} else if data == nil {
+		logrus.Debugf("the cached copy of the config %s is up to date", u.Redacted())
+		data = cachedData
+	}
+	if expectedSHA256 != "" {
+		hash := sha256.Sum256(data)
+		if actual := hex.EncodeToString(hash[:]); actual != expectedSHA256 {
+			return nil, fmt.Errorf("the sha256 checksum of the config %s does not match. Expected: %s Actual: %s", u.Redacted(), expectedSHA256, actual)
+		}
+	}
+	if downloaded {
+		writeRemoteConfigCache(cachePath, data, newETag)
+	}
+	return data, nil
+}
+
+// downloadRemoteConfig downloads the config file. It returns nil data if the config has not changed since the one with the ETag.
+func downloadRemoteConfig(u *url.URL, etag string) (data []byte, newETag string, err error) {
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create the request to fetch the config %s . Error: %w", u.Redacted(), err)
+	}
+	if token := os.Getenv(RemoteConfigTokenEnvVar); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	logrus.Infof("Fetching the config %s", u.Redacted())
+	resp, err := (&http.Client{Timeout: remoteConfigTimeout}).Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch the config %s . Error: %w", u.Redacted(), err)
+	}
+	defer resp.Body.Close()
+	if etag != "" && resp.StatusCode == http.StatusNotModified {
+		return nil, etag, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, "", fmt.Errorf("failed to fetch the config %s . Status: %s", u.Redacted(), resp.Status)
+	}
+	data, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read the config %s . Error: %w", u.Redacted(), err)
+	}
+	return data, resp.Header.Get("ETag"), nil
+}
+
+// getRemoteConfigCachePath returns the path where the config downloaded from the url is cached, or empty string if there is no cache directory
+func getRemoteConfigCachePath(configURL string) string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		logrus.Debugf("failed to get the user cache directory. The remote configs will not be cached. Error: %q", err)
+		return ""
+	}
+	hash := sha256.Sum256([]byte(configURL))
+	return filepath.Join(cacheDir, types.AppName, remoteConfigCacheDir, hex.EncodeToString(hash[:])+".yaml")
+}
+
+func writeRemoteConfigCache(cachePath string, data []byte, etag string) {
+	if cachePath == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(cachePath), common.DefaultDirectoryPermission); err != nil {
+		logrus.Debugf("failed to create the directory %s . Error: %q", filepath.Dir(cachePath), err)
+		return
+	}
+	if err := os.WriteFile(cachePath, data, common.DefaultFilePermission); err != nil {
+		logrus.Debugf("failed to cache the config at %s . Error: %q", cachePath, err)
+		return
+	}
+	if err := os.WriteFile(cachePath+".etag", []byte(etag), common.DefaultFilePermission); err != nil {
+		logrus.Debugf("failed to cache the ETag of the config at %s . Error: %q", cachePath+".etag", err)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/konveyor/move2kube/types/qaengine"
+)
+
+func TestRemoteConfig(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(qaengine.RemoteConfigTokenEnvVar, "test-token")
+	configYaml := "move2kube:\n  target:\n    imageregistry:\n      url: quay.io\n"
+	notModified := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(configYaml))
+	}))
+	defer server.Close()
+	hash := sha256.Sum256([]byte(configYaml))
+	configURL := server.URL + "/presets/base.yaml#sha256=" + hex.EncodeToString(hash[:])
+	getRegistry := func(configURL string) interface{} {
+		config := qaengine.NewConfig("", nil, []string{configURL}, false)
+		if err := config.Load(); err != nil {
+			t.Fatalf("failed to load the config. Error: %q", err)
+		}
+		value, _ := config.Get("move2kube.target.imageregistry.url")
+		return value
+	}
+
+	t.Run("config is fetched", func(t *testing.T) {
+		if value := getRegistry(configURL); value != "quay.io" {
+			t.Fatalf("the answer is incorrect. Actual: %+v", value)
+		}
+	})
+
+	t.Run("cached config is revalidated", func(t *testing.T) {
+		if value := getRegistry(configURL); value != "quay.io" || notModified != 1 {
+			t.Fatalf("expected the cached config to be used after a not modified response. Actual: %+v Not modified responses: %d", value, notModified)
+		}
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		badURL := server.URL + "/presets/base.yaml#sha256=" + hex.EncodeToString(make([]byte, sha256.Size))
+		if value := getRegistry(badURL); value != nil {
+			t.Fatalf("expected the config with the wrong checksum to be ignored. Actual: %+v", value)
+		}
+	})
+
+	t.Run("cached config is used when the server is unreachable", func(t *testing.T) {
+		server.Close()
+		if value := getRegistry(configURL); value != "quay.io" {
+			t.Fatalf("expected the cached config to be used. Actual: %+v", value)
+		}
+	})
+}
@@ -0,0 +1,189 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/types"
+	"github.com/sirupsen/logrus"
+)
+
+// QASessionKind defines kind of QA Session
+const QASessionKind types.Kind = "QASession"
+
+// maskedPassword replaces the answers of the password problems recorded in the session
+const maskedPassword = "********"
+
+// SessionChangeType is the way a question changed between two sessions
+type SessionChangeType string
+
+const (
+	// SessionQuestionAdded is a question that was asked only in the new session
+	SessionQuestionAdded SessionChangeType = "added"
+	// SessionQuestionRemoved is a question that was asked only in the old session
+	SessionQuestionRemoved SessionChangeType = "removed"
+	// SessionQuestionChanged is a question that was asked in both sessions with a different description, options, default or answer
+	SessionQuestionChanged SessionChangeType = "changed"
+)
+
+// Session records the questions asked during a run along with their defaults and answers, in the order they were asked
+type Session struct {
+	types.TypeMeta   `yaml:",inline"`
+	types.ObjectMeta `yaml:"metadata,omitempty"`
+	Spec             SessionSpec `yaml:"spec,omitempty"`
+}
+
+// SessionSpec stores the session data
+type SessionSpec struct {
+	file string `yaml:"-"`
+	// Problems stores the list of problems in the order they were asked
+	Problems []Problem `yaml:"questions"`
+}
+
+// SessionFieldChange is a field of a question that is different between two sessions
+type SessionFieldChange struct {
+	Field string      `yaml:"field" json:"field"`
+	Old   interface{} `yaml:"old,omitempty" json:"old,omitempty"`
+	New   interface{} `yaml:"new,omitempty" json:"new,omitempty"`
+}
+
+// SessionDiff is a question that is different between two sessions
+type SessionDiff struct {
+	ID      string               `yaml:"id" json:"id"`
+	Change  SessionChangeType    `yaml:"change" json:"change"`
+	Fields  []SessionFieldChange `yaml:"fields,omitempty" json:"fields,omitempty"`
+	Problem Problem              `yaml:"problem" json:"problem"`
+}
+
+// NewSession creates new session instance
+func NewSession(file string) *Session {
+	return &Session{
+		TypeMeta: types.TypeMeta{
+			Kind:       string(QASessionKind),
+			APIVersion: types.SchemeGroupVersion.String(),
+		},
+		Spec: SessionSpec{file: file},
+	}
+}
+
+// ReadSession reads a session file
+func ReadSession(file string) (*Session, error) {
+	session := NewSession(file)
+	if err := session.Load(); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Load loads the session file so that its answers can be replayed
+func (session *Session) Load() error {
+	s := Session{}
+	if err := common.ReadMove2KubeYamlStrict(session.Spec.file, &s, string(QASessionKind)); err != nil {
+		return fmt.Errorf("failed to load the session file at path %s . Error: %w", session.Spec.file, err)
+	}
+	session.Spec.Problems = s.Spec.Problems
+	return nil
+}
+
+// Write writes the session to disk
+func (session *Session) Write() error {
+	return common.WriteYaml(session.Spec.file, session)
+}
+
+// AddSolution records the problem in the session. The answers to password problems are masked.
+func (session *Session) AddSolution(p Problem) error {
+	if p.Answer == nil {
+		return fmt.Errorf("unresolved problem. Not going to be added to the session")
+	}
+	if p.Type == PasswordSolutionFormType {
+		p.Answer = maskedPassword
+	}
+	p.Validator = nil
+	session.Spec.Problems = append(session.Spec.Problems, p)
+	if err := session.Write(); err != nil {
+		logrus.Errorf("Failed to write to the session file. Error: %q", err)
+		return err
+	}
+	return nil
+}
+
+// GetSolution replays the answer recorded for the problem. The password problems are never answered since their answers are masked.
+func (session *Session) GetSolution(p Problem) (Problem, error) {
+	if p.Type == PasswordSolutionFormType {
+		return p, fmt.Errorf("the answers to password problems are not replayed from the session")
+	}
+	for _, sp := range session.Spec.Problems {
+		if sp.ID == p.ID && sp.Type == p.Type && sp.Answer != nil {
+			p.Answer = sp.Answer
+			return p, nil
+		}
+	}
+	return p, fmt.Errorf("the problem %s was not found in the session", p.ID)
+}
+
+// DiffSessions returns the questions that were added, removed or changed in the new session compared to the old session.
+// The added and changed questions are in the order they were asked in the new session, followed by the removed questions.
+func DiffSessions(oldSession, newSession *Session) []SessionDiff {
+	diffs := []SessionDiff{}
+	oldProblems := map[string]Problem{}
+	for _, p := range oldSession.Spec.Problems {
+		if _, ok := oldProblems[p.ID]; !ok {
+			oldProblems[p.ID] = p
+		}
+	}
+	seen := map[string]bool{}
+	for _, newProblem := range newSession.Spec.Problems {
+		if seen[newProblem.ID] {
+			continue
+		}
+		seen[newProblem.ID] = true
+		oldProblem, ok := oldProblems[newProblem.ID]
+		if !ok {
+			diffs = append(diffs, SessionDiff{ID: newProblem.ID, Change: SessionQuestionAdded, Problem: newProblem})
+			continue
+		}
+		fields := []SessionFieldChange{}
+		for _, field := range []struct {
+			name     string
+			old, new interface{}
+		}{
+			{"type", oldProblem.Type, newProblem.Type},
+			{"description", oldProblem.Desc, newProblem.Desc},
+			{"options", oldProblem.Options, newProblem.Options},
+			{"default", oldProblem.Default, newProblem.Default},
+			{"answer", oldProblem.Answer, newProblem.Answer},
+		} {
+			if !reflect.DeepEqual(field.old, field.new) {
+				fields = append(fields, SessionFieldChange{Field: field.name, Old: field.old, New: field.new})
+			}
+		}
+		if len(fields) > 0 {
+			diffs = append(diffs, SessionDiff{ID: newProblem.ID, Change: SessionQuestionChanged, Fields: fields, Problem: newProblem})
+		}
+	}
+	for _, oldProblem := range oldSession.Spec.Problems {
+		if seen[oldProblem.ID] {
+			continue
+		}
+		seen[oldProblem.ID] = true
+		diffs = append(diffs, SessionDiff{ID: oldProblem.ID, Change: SessionQuestionRemoved, Problem: oldProblem})
+	}
+	return diffs
+}
@@ -0,0 +1,92 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/types/qaengine"
+)
+
+func TestSessionRecordAndReplay(t *testing.T) {
+	sessionPath := filepath.Join(t.TempDir(), "session.yaml")
+	session := qaengine.NewSession(sessionPath)
+	input, err := qaengine.NewInputProblem("move2kube.target.imageregistry.url", "Enter the registry", nil, "quay.io", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input.Answer = "us.icr.io"
+	password, err := qaengine.NewPasswordProblem("move2kube.target.imageregistry.password", "Enter the password", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	password.Answer = "secret"
+	for _, p := range []qaengine.Problem{input, password} {
+		if err := session.AddSolution(p); err != nil {
+			t.Fatalf("failed to record the problem %s . Error: %q", p.ID, err)
+		}
+	}
+
+	replay, err := qaengine.ReadSession(sessionPath)
+	if err != nil {
+		t.Fatalf("failed to read the session. Error: %q", err)
+	}
+	if len(replay.Spec.Problems) != 2 || replay.Spec.Problems[1].Answer != "********" {
+		t.Fatalf("expected the password to be masked in the session. Actual: %+v", replay.Spec.Problems)
+	}
+	input.Answer = nil
+	answered, err := replay.GetSolution(input)
+	if err != nil || answered.Answer != "us.icr.io" {
+		t.Fatalf("expected the recorded answer to be replayed. Actual: %+v Error: %v", answered.Answer, err)
+	}
+	password.Answer = nil
+	if _, err := replay.GetSolution(password); err == nil {
+		t.Fatalf("expected the password not to be replayed")
+	}
+}
+
+func TestDiffSessions(t *testing.T) {
+	oldSession := qaengine.NewSession("")
+	oldSession.Spec.Problems = []qaengine.Problem{
+		{ID: "move2kube.a", Type: qaengine.InputSolutionFormType, Desc: "A", Default: "x", Answer: "x"},
+		{ID: "move2kube.b", Type: qaengine.ConfirmSolutionFormType, Desc: "B", Default: true, Answer: true},
+		{ID: "move2kube.c", Type: qaengine.InputSolutionFormType, Desc: "C", Answer: "c"},
+	}
+	newSession := qaengine.NewSession("")
+	newSession.Spec.Problems = []qaengine.Problem{
+		{ID: "move2kube.d", Type: qaengine.InputSolutionFormType, Desc: "D", Answer: "d"},
+		{ID: "move2kube.a", Type: qaengine.InputSolutionFormType, Desc: "A", Default: "x", Answer: "x"},
+		{ID: "move2kube.b", Type: qaengine.ConfirmSolutionFormType, Desc: "B", Default: false, Answer: false},
+	}
+	want := []qaengine.SessionDiff{
+		{ID: "move2kube.d", Change: qaengine.SessionQuestionAdded, Problem: newSession.Spec.Problems[0]},
+		{ID: "move2kube.b", Change: qaengine.SessionQuestionChanged, Problem: newSession.Spec.Problems[2], Fields: []qaengine.SessionFieldChange{
+			{Field: "default", Old: true, New: false},
+			{Field: "answer", Old: true, New: false},
+		}},
+		{ID: "move2kube.c", Change: qaengine.SessionQuestionRemoved, Problem: oldSession.Spec.Problems[2]},
+	}
+	actual := qaengine.DiffSessions(oldSession, newSession)
+	if !cmp.Equal(actual, want) {
+		t.Fatalf("the diff is incorrect. Differences:\n%s", cmp.Diff(want, actual))
+	}
+	if diffs := qaengine.DiffSessions(oldSession, oldSession); len(diffs) != 0 {
+		t.Fatalf("expected no differences between the same sessions. Actual: %+v", diffs)
+	}
+}
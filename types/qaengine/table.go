@@ -0,0 +1,216 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/spf13/cast"
+)
+
+// TableColumnType is the type of the values in a column of a Table problem
+type TableColumnType string
+
+const (
+	// StringTableColumnType is a column of strings
+	StringTableColumnType TableColumnType = "string"
+	// IntegerTableColumnType is a column of integers like port numbers
+	IntegerTableColumnType TableColumnType = "integer"
+	// BooleanTableColumnType is a column of booleans
+	BooleanTableColumnType TableColumnType = "boolean"
+)
+
+// TableColumn is a column of the rows answering a Table problem
+type TableColumn struct {
+	Name string          `yaml:"name" json:"name"`
+	Type TableColumnType `yaml:"type,omitempty" json:"type,omitempty"`
+	// Options are the accepted values of the column, any value is accepted if empty
+	Options []string `yaml:"options,omitempty" json:"options,omitempty"`
+	// Required rejects the rows without a value for the column
+	Required bool `yaml:"required,omitempty" json:"required,omitempty"`
+}
+
+// TableOptions are the columns of the rows answering a Table problem
+type TableOptions struct {
+	Columns []TableColumn `yaml:"columns" json:"columns"`
+	// MinRows and MaxRows limit the number of rows, there is no limit if they are 0
+	MinRows int `yaml:"minRows,omitempty" json:"minRows,omitempty"`
+	MaxRows int `yaml:"maxRows,omitempty" json:"maxRows,omitempty"`
+}
+
+// Validate checks that the columns have unique names and known types
+func (o TableOptions) Validate() error {
+	if len(o.Columns) == 0 {
+		return fmt.Errorf("the table has no columns")
+	}
+	names := []string{}
+	for _, column := range o.Columns {
+		if column.Name == "" {
+			return fmt.Errorf("one of the columns of the table has no name")
+		}
+		if common.IsPresent(names, column.Name) {
+			return fmt.Errorf("the table has two columns named %s", column.Name)
+		}
+		names = append(names, column.Name)
+		switch column.Type {
+		case "", StringTableColumnType, IntegerTableColumnType, BooleanTableColumnType:
+		default:
+			return fmt.Errorf("the column %s has the unsupported type %s", column.Name, column.Type)
+		}
+	}
+	if o.MaxRows > 0 && o.MinRows > o.MaxRows {
+		return fmt.Errorf("the minimum number of rows %d is more than the maximum %d", o.MinRows, o.MaxRows)
+	}
+	return nil
+}
+
+// NormalizeRows converts the rows, like the ones read from yaml or json, into maps of the column names to the values
+// of the column types. The rows with unknown columns, missing required values or invalid values are rejected.
+func (o TableOptions) NormalizeRows(rowsI interface{}) ([]map[string]interface{}, error) {
+	rowIs := []interface{}{}
+	switch actualRows := rowsI.(type) {
+	case []map[string]interface{}:
+		for _, row := range actualRows {
+			rowIs = append(rowIs, row)
+		}
+	case []interface{}:
+		rowIs = actualRows
+	default:
+		return nil, fmt.Errorf("expected the answer to be a list of rows. Actual value %+v is of type %T", rowsI, rowsI)
+	}
+	if len(rowIs) < o.MinRows {
+		return nil, fmt.Errorf("expected at least %d rows. Actual: %d", o.MinRows, len(rowIs))
+	}
+	if o.MaxRows > 0 && len(rowIs) > o.MaxRows {
+		return nil, fmt.Errorf("expected at most %d rows. Actual: %d", o.MaxRows, len(rowIs))
+	}
+	rows := []map[string]interface{}{}
+	for i, rowI := range rowIs {
+		row, ok := rowI.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected the row %d to be a map of the column names to the values. Actual value %+v is of type %T", i+1, rowI, rowI)
+		}
+		normalizedRow := map[string]interface{}{}
+		for name := range row {
+			if common.FindIndex(o.Columns, func(column TableColumn) bool { return column.Name == name }) == -1 {
+				return nil, fmt.Errorf("the row %d has the unknown column %s", i+1, name)
+			}
+		}
+		for _, column := range o.Columns {
+			value, ok := row[column.Name]
+			if !ok || value == nil || value == "" {
+				if column.Required {
+					return nil, fmt.Errorf("the row %d has no value for the required column %s", i+1, column.Name)
+				}
+				continue
+			}
+			normalizedValue, err := column.normalizeValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("the value of the column %s in the row %d is invalid. Error: %w", column.Name, i+1, err)
+			}
+			normalizedRow[column.Name] = normalizedValue
+		}
+		rows = append(rows, normalizedRow)
+	}
+	return rows, nil
+}
+
+func (c TableColumn) normalizeValue(value interface{}) (interface{}, error) {
+	var normalizedValue interface{}
+	var err error
+	switch c.Type {
+	case IntegerTableColumnType:
+		var number float64
+		number, err = cast.ToFloat64E(value)
+		if err == nil && number != float64(int(number)) {
+			err = fmt.Errorf("%v is not an integer", value)
+		}
+		normalizedValue = int(number)
+	case BooleanTableColumnType:
+		normalizedValue, err = cast.ToBoolE(value)
+	default:
+		normalizedValue, err = cast.ToStringE(value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Options) > 0 && !common.IsPresent(c.Options, cast.ToString(normalizedValue)) {
+		return nil, fmt.Errorf("%v is not one of the options %+v", value, c.Options)
+	}
+	return normalizedValue, nil
+}
+
+// FormatRows formats the rows as comma separated values, one line per row with the values in the order of the columns
+func (o TableOptions) FormatRows(rows []map[string]interface{}) []string {
+	lines := []string{}
+	for _, row := range rows {
+		values := []string{}
+		for _, column := range o.Columns {
+			value, ok := row[column.Name]
+			if !ok || value == nil {
+				values = append(values, "")
+				continue
+			}
+			values = append(values, cast.ToString(value))
+		}
+		b := bytes.Buffer{}
+		w := csv.NewWriter(&b)
+		_ = w.Write(values)
+		w.Flush()
+		lines = append(lines, strings.TrimRight(b.String(), "\r\n"))
+	}
+	return lines
+}
+
+// ParseRows parses the lines of comma separated values in the order of the columns into rows. The empty lines are ignored.
+func (o TableOptions) ParseRows(lines []string) ([]map[string]interface{}, error) {
+	rowIs := []interface{}{}
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		r := csv.NewReader(strings.NewReader(line))
+		r.TrimLeadingSpace = true
+		r.FieldsPerRecord = -1
+		values, err := r.Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the line %d as comma separated values. Error: %w", i+1, err)
+		}
+		if len(values) > len(o.Columns) {
+			return nil, fmt.Errorf("the line %d has %d values but there are only %d columns", i+1, len(values), len(o.Columns))
+		}
+		row := map[string]interface{}{}
+		for j, value := range values {
+			row[o.Columns[j].Name] = strings.TrimSpace(value)
+		}
+		rowIs = append(rowIs, row)
+	}
+	return o.NormalizeRows(rowIs)
+}
+
+// GetColumnNames returns the names of the columns in order
+func (o TableOptions) GetColumnNames() []string {
+	names := []string{}
+	for _, column := range o.Columns {
+		names = append(names, column.Name)
+	}
+	return names
+}
@@ -0,0 +1,105 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/types/qaengine"
+)
+
+func TestTableProblem(t *testing.T) {
+	options := qaengine.TableOptions{
+		Columns: []qaengine.TableColumn{
+			{Name: "containerPort", Type: qaengine.IntegerTableColumnType, Required: true},
+			{Name: "servicePort", Type: qaengine.IntegerTableColumnType},
+			{Name: "protocol", Options: []string{"TCP", "UDP"}},
+			{Name: "expose", Type: qaengine.BooleanTableColumnType},
+		},
+		MaxRows: 2,
+	}
+	prob, err := qaengine.NewTableProblem("move2kube.services.api.ports", "Map the container ports to the service ports", nil, nil, options, nil)
+	if err != nil {
+		t.Fatalf("failed to create the problem. Error: %q", err)
+	}
+
+	t.Run("rows read from yaml are converted to the column types", func(t *testing.T) {
+		p := prob
+		answer := []interface{}{
+			map[string]interface{}{"containerPort": 8080, "servicePort": "80", "protocol": "TCP", "expose": "true"},
+			map[string]interface{}{"containerPort": float64(9090)},
+		}
+		if err := p.SetAnswer(answer, true); err != nil {
+			t.Fatalf("failed to set the answer. Error: %q", err)
+		}
+		want := []map[string]interface{}{
+			{"containerPort": 8080, "servicePort": 80, "protocol": "TCP", "expose": true},
+			{"containerPort": 9090},
+		}
+		if !cmp.Equal(p.Answer, want) {
+			t.Fatalf("the answer is incorrect. Differences:\n%s", cmp.Diff(want, p.Answer))
+		}
+		lines := options.FormatRows(want)
+		if !cmp.Equal(lines, []string{"8080,80,TCP,true", "9090,,,"}) {
+			t.Fatalf("the rows are formatted incorrectly. Actual: %+v", lines)
+		}
+		parsed, err := options.ParseRows(append(lines, ""))
+		if err != nil || !cmp.Equal(parsed, want) {
+			t.Fatalf("the formatted rows don't parse back to the same rows. Actual: %+v Error: %v", parsed, err)
+		}
+		array, err := qaengine.InterfaceToArray(want, qaengine.TableSolutionFormType)
+		if err != nil {
+			t.Fatalf("failed to convert the rows to an array. Error: %q", err)
+		}
+		converted, err := qaengine.ArrayToInterface(array, qaengine.TableSolutionFormType)
+		if err != nil {
+			t.Fatalf("failed to convert the array to rows. Error: %q", err)
+		}
+		if err := p.SetAnswer(converted, true); err != nil || !cmp.Equal(p.Answer, want) {
+			t.Fatalf("the rows changed after the round trip through the array. Actual: %+v Error: %v", p.Answer, err)
+		}
+	})
+
+	t.Run("invalid rows are rejected", func(t *testing.T) {
+		testcases := map[string]interface{}{
+			"missing required column": []interface{}{map[string]interface{}{"servicePort": 80}},
+			"not an integer":          []interface{}{map[string]interface{}{"containerPort": "http"}},
+			"fractional integer":      []interface{}{map[string]interface{}{"containerPort": 80.5}},
+			"not one of the options":  []interface{}{map[string]interface{}{"containerPort": 80, "protocol": "SCTP"}},
+			"unknown column":          []interface{}{map[string]interface{}{"containerPort": 80, "targetPort": 80}},
+			"too many rows":           []interface{}{map[string]interface{}{"containerPort": 1}, map[string]interface{}{"containerPort": 2}, map[string]interface{}{"containerPort": 3}},
+			"not a list":              "8080",
+		}
+		for name, answer := range testcases {
+			p := prob
+			if err := p.SetAnswer(answer, true); err == nil {
+				t.Errorf("expected the answer with the %s to be rejected. Actual: %+v", name, p.Answer)
+			}
+		}
+		if _, err := options.ParseRows([]string{"80,80,TCP,true,extra"}); err == nil {
+			t.Errorf("expected the line with more values than columns to be rejected")
+		}
+	})
+
+	t.Run("invalid columns are rejected", func(t *testing.T) {
+		duplicate := qaengine.TableOptions{Columns: []qaengine.TableColumn{{Name: "port"}, {Name: "port"}}}
+		if _, err := qaengine.NewTableProblem("move2kube.ports", "ports", nil, nil, duplicate, nil); err == nil {
+			t.Fatalf("expected the columns with the same name to be rejected")
+		}
+	})
+}
@@ -0,0 +1,318 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/spf13/cast"
+)
+
+// The when expression of a problem decides whether the problem is asked, using the answers to the earlier problems.
+// Example: move2kube.target.ingress.enabled && move2kube.target.ingress.tls != ""
+// The operands are the keys of the earlier problems, quoted strings, numbers, true and false.
+// The operators are ==, !=, contains (for the multi select answers), !, && and || along with parentheses.
+// A key alone is true if its answer is true, a non empty string or a non empty list. The keys that were not answered are false.
+
+type whenTokenType int
+
+const (
+	whenEOF whenTokenType = iota
+	whenKey
+	whenString
+	whenNumber
+	whenBool
+	whenOperator
+	whenLeftParen
+	whenRightParen
+)
+
+type whenToken struct {
+	tokenType whenTokenType
+	value     string
+}
+
+type whenParser struct {
+	tokens    []whenToken
+	pos       int
+	getAnswer func(key string) (interface{}, bool)
+}
+
+// EvaluateWhen evaluates the when expression using the answers returned by getAnswer
+func EvaluateWhen(expr string, getAnswer func(key string) (interface{}, bool)) (bool, error) {
+	tokens, err := tokenizeWhen(expr)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse the when expression '%s' . Error: %w", expr, err)
+	}
+	parser := &whenParser{tokens: tokens, getAnswer: getAnswer}
+	value, err := parser.parseOr()
+	if err != nil {
+		return false, fmt.Errorf("failed to parse the when expression '%s' . Error: %w", expr, err)
+	}
+	if token := parser.peek(); token.tokenType != whenEOF {
+		return false, fmt.Errorf("failed to parse the when expression '%s' . Error: unexpected '%s'", expr, token.value)
+	}
+	return isTruthy(value), nil
+}
+
+func tokenizeWhen(expr string) ([]whenToken, error) {
+	tokens := []whenToken{}
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, whenToken{whenLeftParen, "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, whenToken{whenRightParen, ")"})
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("the string starting at %d is not terminated", i)
+			}
+			value := string(runes[i+1 : end])
+			if r == '"' {
+				unquoted, err := strconv.Unquote(string(runes[i : end+1]))
+				if err != nil {
+					return nil, fmt.Errorf("the string %s is invalid. Error: %w", string(runes[i:end+1]), err)
+				}
+				value = unquoted
+			}
+			tokens = append(tokens, whenToken{whenString, value})
+			i = end + 1
+		case strings.ContainsRune("=!&|", r):
+			if i+1 < len(runes) && (runes[i+1] == '=' && (r == '=' || r == '!') || runes[i+1] == r && (r == '&' || r == '|')) {
+				tokens = append(tokens, whenToken{whenOperator, string(runes[i : i+2])})
+				i += 2
+				continue
+			}
+			if r != '!' {
+				return nil, fmt.Errorf("unexpected '%c' at %d", r, i)
+			}
+			tokens = append(tokens, whenToken{whenOperator, "!"})
+			i++
+		case unicode.IsDigit(r) || r == '-':
+			end := i + 1
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, whenToken{whenNumber, string(runes[i:end])})
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			// keys can have quoted sub keys like move2kube.services."api".enable
+			end := i
+			for end < len(runes) {
+				if runes[end] == '"' && end > i && runes[end-1] == '.' {
+					closing := strings.IndexRune(string(runes[end+1:]), '"')
+					if closing < 0 {
+						return nil, fmt.Errorf("the quoted sub key starting at %d is not terminated", end)
+					}
+					end += len([]rune(string(runes[end+1:])[:closing])) + 2
+					continue
+				}
+				if !(unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || strings.ContainsRune("_-.[]*", runes[end])) {
+					break
+				}
+				end++
+			}
+			word := string(runes[i:end])
+			switch word {
+			case "true", "false":
+				tokens = append(tokens, whenToken{whenBool, word})
+			case "contains":
+				tokens = append(tokens, whenToken{whenOperator, word})
+			default:
+				tokens = append(tokens, whenToken{whenKey, word})
+			}
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected '%c' at %d", r, i)
+		}
+	}
+	return tokens, nil
+}
+
+func (p *whenParser) peek() whenToken {
+	if p.pos >= len(p.tokens) {
+		return whenToken{tokenType: whenEOF}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *whenParser) next() whenToken {
+	token := p.peek()
+	if token.tokenType != whenEOF {
+		p.pos++
+	}
+	return token
+}
+
+func (p *whenParser) parseOr() (interface{}, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().tokenType == whenOperator && p.peek().value == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = isTruthy(left) || isTruthy(right)
+	}
+	return left, nil
+}
+
+func (p *whenParser) parseAnd() (interface{}, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().tokenType == whenOperator && p.peek().value == "&&" {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = isTruthy(left) && isTruthy(right)
+	}
+	return left, nil
+}
+
+func (p *whenParser) parseUnary() (interface{}, error) {
+	if token := p.peek(); token.tokenType == whenOperator && token.value == "!" {
+		p.next()
+		value, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return !isTruthy(value), nil
+	}
+	return p.parseComparison()
+}
+
+func (p *whenParser) parseComparison() (interface{}, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	token := p.peek()
+	if token.tokenType != whenOperator || (token.value != "==" && token.value != "!=" && token.value != "contains") {
+		return left, nil
+	}
+	p.next()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	switch token.value {
+	case "==":
+		return isEqual(left, right), nil
+	case "!=":
+		return !isEqual(left, right), nil
+	default:
+		values, err := common.ConvertInterfaceToSliceOfStrings(left)
+		if err != nil {
+			return false, nil
+		}
+		for _, value := range values {
+			if isEqual(value, right) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+func (p *whenParser) parseOperand() (interface{}, error) {
+	token := p.next()
+	switch token.tokenType {
+	case whenLeftParen:
+		value, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.tokenType != whenRightParen {
+			return nil, fmt.Errorf("expected ')' but found '%s'", closing.value)
+		}
+		return value, nil
+	case whenKey:
+		value, ok := p.getAnswer(token.value)
+		if !ok {
+			return nil, nil
+		}
+		return value, nil
+	case whenString:
+		return token.value, nil
+	case whenNumber:
+		number, err := strconv.ParseFloat(token.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("the number %s is invalid. Error: %w", token.value, err)
+		}
+		return number, nil
+	case whenBool:
+		return token.value == "true", nil
+	case whenEOF:
+		return nil, fmt.Errorf("unexpected end of the expression")
+	default:
+		return nil, fmt.Errorf("unexpected '%s'", token.value)
+	}
+}
+
+// isEqual compares the values as numbers if both are numbers and as strings otherwise, so that the answer "8080" equals 8080
+func isEqual(left, right interface{}) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+	leftNumber, leftErr := cast.ToFloat64E(left)
+	rightNumber, rightErr := cast.ToFloat64E(right)
+	if leftErr == nil && rightErr == nil {
+		return leftNumber == rightNumber
+	}
+	return cast.ToString(left) == cast.ToString(right)
+}
+
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case []string:
+		return len(v) > 0
+	case []interface{}:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
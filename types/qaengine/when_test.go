@@ -0,0 +1,74 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package qaengine_test
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/types/qaengine"
+)
+
+func TestEvaluateWhen(t *testing.T) {
+	answers := map[string]interface{}{
+		"move2kube.target.ingress.enabled": true,
+		"move2kube.target.ingress.host":    "example.com",
+		"move2kube.minreplicas":            "2",
+		"move2kube.services":               []interface{}{"api", "web"},
+		`move2kube.services."api".enable`:  false,
+	}
+	getAnswer := func(key string) (interface{}, bool) {
+		answer, ok := answers[key]
+		return answer, ok
+	}
+	testcases := []struct {
+		expr    string
+		want    bool
+		wantErr bool
+	}{
+		{expr: "move2kube.target.ingress.enabled", want: true},
+		{expr: "!move2kube.target.ingress.enabled", want: false},
+		{expr: "move2kube.target.ingress.missing", want: false},
+		{expr: `move2kube.target.ingress.host == "example.com"`, want: true},
+		{expr: `move2kube.target.ingress.host != 'example.com'`, want: false},
+		{expr: "move2kube.minreplicas == 2", want: true},
+		{expr: "move2kube.target.ingress.enabled == true && move2kube.minreplicas != 3", want: true},
+		{expr: `move2kube.services contains "web"`, want: true},
+		{expr: `move2kube.services contains "db" || (move2kube.target.ingress.missing || move2kube.services."api".enable)`, want: false},
+		{expr: `!(move2kube.services."api".enable)`, want: true},
+		{expr: "move2kube.target.ingress.enabled &&", wantErr: true},
+		{expr: "move2kube.target.ingress.enabled = true", wantErr: true},
+		{expr: `move2kube.target.ingress.host == "example.com`, wantErr: true},
+		{expr: "(move2kube.target.ingress.enabled", wantErr: true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.expr, func(t *testing.T) {
+			actual, err := qaengine.EvaluateWhen(tc.expr, getAnswer)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected the expression to be rejected. Actual: %v", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to evaluate the expression. Error: %q", err)
+			}
+			if actual != tc.want {
+				t.Fatalf("expected %v. Actual: %v", tc.want, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package transformer
+
+import (
+	"github.com/konveyor/move2kube/types"
+)
+
+// TransformerIndexKind represents the TransformerIndex kind
+const TransformerIndexKind = "TransformerIndex"
+
+// TransformerIndex lists the custom transformers that can be installed into a customizations directory
+type TransformerIndex struct {
+	types.TypeMeta   `yaml:",inline" json:",inline"`
+	types.ObjectMeta `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+	Spec             TransformerIndexSpec `yaml:"spec,omitempty" json:"spec,omitempty"`
+}
+
+// TransformerIndexSpec stores the transformers in the index
+type TransformerIndexSpec struct {
+	Transformers []TransformerIndexEntry `yaml:"transformers" json:"transformers"`
+}
+
+// TransformerIndexEntry is a single transformer in the index along with its versions
+type TransformerIndexEntry struct {
+	Name        string                    `yaml:"name" json:"name"`
+	Description string                    `yaml:"description,omitempty" json:"description,omitempty"`
+	Versions    []TransformerIndexVersion `yaml:"versions" json:"versions"`
+}
+
+// TransformerIndexVersion is a single version of a transformer in the index
+type TransformerIndexVersion struct {
+	Version     string `yaml:"version" json:"version"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Source is where the transformer directory is fetched from. It can be a git remote path, an archive url,
+	// an oci://<image> reference or a path relative to the index.
+	Source string `yaml:"source" json:"source"`
+}
@@ -38,8 +38,9 @@ const (
 
 // PathMapping is the mapping between source and intermediate files and output files
 type PathMapping struct {
-	Type           PathMappingType `yaml:"type,omitempty" json:"type,omitempty"` // Default - Normal copy
-	SrcPath        string          `yaml:"sourcePath" json:"sourcePath" m2kpath:"normal"`
-	DestPath       string          `yaml:"destinationPath" json:"destinationPath" m2kpath:"normal"` // Relative to output directory
-	TemplateConfig interface{}     `yaml:"templateConfig" json:"templateConfig"`
+	Type            PathMappingType  `yaml:"type,omitempty" json:"type,omitempty"` // Default - Normal copy
+	SrcPath         string           `yaml:"sourcePath" json:"sourcePath" m2kpath:"normal"`
+	DestPath        string           `yaml:"destinationPath" json:"destinationPath" m2kpath:"normal"` // Relative to output directory
+	TemplateConfig  interface{}      `yaml:"templateConfig" json:"templateConfig"`
+	TemplateOptions *TemplateOptions `yaml:"templateOptions,omitempty" json:"templateOptions,omitempty"` // Used only by the Template type, defaults to the template options of the transformer
 }
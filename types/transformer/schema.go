@@ -0,0 +1,320 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package transformer
+
+import (
+	_ "embed" // for embedding the schema
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../../scripts/generator/transformerschema.go transformer.schema.json
+
+//go:embed transformer.schema.json
+var transformerSchema []byte
+
+const (
+	// schemaPathSeparator separates the keys in the paths of the schema overrides
+	schemaPathSeparator = "/"
+	// violationPathSeparator separates the keys in the paths of the violations reported by the validator.
+	// It can't appear in the keys, unlike the slashes in the label keys.
+	violationPathSeparator = "\x00"
+	// anyKeyPattern matches all the keys of a map
+	anyKeyPattern = ".*"
+	// invalidPropertyPatternErrorType is reported on the map along with the violations of its values
+	invalidPropertyPatternErrorType = "invalid_property_pattern"
+	// configSchemaPath is the path of the config of the transformer classes in the schema
+	configSchemaPath = "spec/config"
+)
+
+// transformerSchemaOverrides add the constraints that can't be derived from the Go types, keyed by the path in the schema
+var transformerSchemaOverrides = map[string]map[string]interface{}{
+	"":              {"required": []string{"apiVersion", "kind", "metadata", "spec"}},
+	"metadata":      {"required": []string{"name"}},
+	"metadata/name": {"minLength": 1},
+	// the labels are decoded into strings, so unquoted booleans and numbers are allowed
+	"metadata/labels/*":                  {"type": []string{"string", "boolean", "number"}},
+	"spec":                               {"required": []string{"class"}},
+	"spec/class":                         {"minLength": 1},
+	"spec/directoryDetect/levels":        {"enum": []int{-1, 0, 1}},
+	"spec/consumes/*/mode":               {"enum": []ArtifactProcessingMode{Normal, MandatoryPassThrough, OnDemandPassThrough}},
+	"spec/templateOptions/verbatimPaths": {"uniqueItems": true},
+}
+
+// SchemaViolation is a part of a transformer yaml that does not conform to the schema
+type SchemaViolation struct {
+	// Field is the path to the invalid field, with the keys separated by dots
+	Field   string
+	Line    int
+	Column  int
+	Message string
+}
+
+// String returns the violation prefixed with its position in the yaml
+func (v SchemaViolation) String() string {
+	field := v.Field
+	if field == "" {
+		field = "(root)"
+	}
+	return fmt.Sprintf("line %d column %d: %s: %s", v.Line, v.Column, field, v.Message)
+}
+
+// TransformerSchemaError is returned when a transformer yaml does not conform to the schema
+type TransformerSchemaError struct {
+	Path       string
+	Violations []SchemaViolation
+}
+
+// Error implements the interface required for Error
+func (e *TransformerSchemaError) Error() string {
+	violations := []string{}
+	for _, violation := range e.Violations {
+		violations = append(violations, "  "+violation.String())
+	}
+	return fmt.Sprintf("the transformer config at path %s has %d schema violations:\n%s", e.Path, len(e.Violations), strings.Join(violations, "\n"))
+}
+
+// GetTransformerSchema returns the JSON schema for the transformer yamls
+func GetTransformerSchema() []byte {
+	return transformerSchema
+}
+
+// GenerateTransformerSchema generates the JSON schema for the transformer yamls from the Transformer type
+func GenerateTransformerSchema() map[string]interface{} {
+	schema := getTypeSchema(reflect.TypeOf(Transformer{}), "")
+	schema["$schema"] = "http://json-schema.org/draft-04/schema#"
+	schema["title"] = TransformerKind
+	return schema
+}
+
+// GenerateConfigSchema generates the JSON schema for the spec.config of a transformer class from the type its config is loaded into
+func GenerateConfigSchema(config interface{}) map[string]interface{} {
+	return getTypeSchema(reflect.TypeOf(config), configSchemaPath)
+}
+
+// getTypeSchema returns the schema of the type, using the yaml tags as the property names
+func getTypeSchema(t reflect.Type, path string) map[string]interface{} {
+	schema := map[string]interface{}{}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return getTypeSchema(t.Elem(), path)
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		addStructProperties(t, path, properties)
+		schema["type"] = "object"
+		schema["properties"] = properties
+		schema["additionalProperties"] = false
+	case reflect.Map:
+		// patternProperties is used instead of additionalProperties so that the keys are part of the paths of the violations
+		schema["type"] = "object"
+		schema["patternProperties"] = map[string]interface{}{anyKeyPattern: getTypeSchema(t.Elem(), joinSchemaPath(path, "*"))}
+	case reflect.Slice, reflect.Array:
+		schema["type"] = "array"
+		schema["items"] = getTypeSchema(t.Elem(), path)
+	case reflect.String:
+		schema["type"] = "string"
+	case reflect.Bool:
+		schema["type"] = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema["type"] = "integer"
+	case reflect.Float32, reflect.Float64:
+		schema["type"] = "number"
+	}
+	for k, v := range transformerSchemaOverrides[path] {
+		schema[k] = v
+	}
+	return schema
+}
+
+func addStructProperties(t reflect.Type, path string, properties map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("yaml"), ",")
+		name := tag[0]
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" && len(tag) > 1 && tag[1] == "inline" {
+			addStructProperties(field.Type, path, properties)
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		properties[name] = getTypeSchema(field.Type, joinSchemaPath(path, name))
+	}
+}
+
+func joinSchemaPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + schemaPathSeparator + key
+}
+
+// ValidateTransformerYaml validates the transformer yaml against the schema and returns all the violations,
+// ordered by their position in the yaml
+func ValidateTransformerYaml(data []byte) ([]SchemaViolation, error) {
+	root := yaml.Node{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse the yaml. Error: %w", err)
+	}
+	violations, err := validateYamlNode(&root, &root, nil, gojsonschema.NewBytesLoader(transformerSchema))
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate the yaml against the transformer schema. Error: %w", err)
+	}
+	return violations, nil
+}
+
+// ValidateTransformerConfig validates the spec.config of the transformer yaml against the JSON schema of the config
+// and returns all the violations, with the fields and positions relative to the transformer yaml
+func ValidateTransformerConfig(data []byte, configSchema interface{}) ([]SchemaViolation, error) {
+	root := yaml.Node{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse the yaml. Error: %w", err)
+	}
+	configKeys := strings.Split(configSchemaPath, schemaPathSeparator)
+	config := getYamlNode(&root, configKeys)
+	if config == nil {
+		config = &yaml.Node{}
+	}
+	violations, err := validateYamlNode(&root, config, configKeys, gojsonschema.NewGoLoader(configSchema))
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate the config against the schema. Error: %w", err)
+	}
+	return violations, nil
+}
+
+// ValidateTransformerConfigFile validates the spec.config of the transformer yaml at the path against the JSON schema
+// of the config and returns a TransformerSchemaError pointing to the invalid fields if it does not conform
+func ValidateTransformerConfigFile(path string, configSchema interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read the transformer config at path %s . Error: %w", path, err)
+	}
+	violations, err := ValidateTransformerConfig(data, configSchema)
+	if err != nil {
+		return fmt.Errorf("failed to validate the transformer config at path %s . Error: %w", path, err)
+	}
+	if len(violations) != 0 {
+		return &TransformerSchemaError{Path: path, Violations: violations}
+	}
+	return nil
+}
+
+// validateYamlNode validates the node against the schema. The keys of the violations are
+// prefixed with the keys of the node, to find their positions in the root.
+func validateYamlNode(root *yaml.Node, node *yaml.Node, nodeKeys []string, schemaLoader gojsonschema.JSONLoader) ([]SchemaViolation, error) {
+	var document interface{}
+	if err := node.Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to decode the yaml. Error: %w", err)
+	}
+	if document == nil {
+		document = map[string]interface{}{}
+	}
+	result, err := gojsonschema.Validate(schemaLoader, gojsonschema.NewGoLoader(document))
+	if err != nil {
+		return nil, err
+	}
+	violations := []SchemaViolation{}
+	for _, resultErr := range result.Errors() {
+		if resultErr.Type() == invalidPropertyPatternErrorType {
+			continue
+		}
+		keys := append([]string{}, nodeKeys...)
+		if context := strings.TrimPrefix(resultErr.Context().String(violationPathSeparator), gojsonschema.STRING_CONTEXT_ROOT); context != "" {
+			keys = append(keys, strings.Split(strings.TrimPrefix(context, violationPathSeparator), violationPathSeparator)...)
+		}
+		if property, ok := resultErr.Details()["property"].(string); ok && resultErr.Type() == "additional_property_not_allowed" {
+			keys = append(keys, property)
+		}
+		line, column := GetYamlNodePosition(root, keys)
+		violations = append(violations, SchemaViolation{
+			Field:   strings.Join(keys, "."),
+			Line:    line,
+			Column:  column,
+			Message: resultErr.Description(),
+		})
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Line != violations[j].Line {
+			return violations[i].Line < violations[j].Line
+		}
+		return violations[i].Column < violations[j].Column
+	})
+	return violations, nil
+}
+
+// GetYamlNodePosition returns the position of the deepest node found along the keys. The keys of sequences are the indexes.
+// For a key of a mapping the position of the key is returned, since the value can be on the next line.
+func GetYamlNodePosition(root *yaml.Node, keys []string) (int, int) {
+	_, _, line, column := findYamlNode(root, keys)
+	return line, column
+}
+
+// getYamlNode returns the node at the keys, nil if it does not exist
+func getYamlNode(root *yaml.Node, keys []string) *yaml.Node {
+	node, found, _, _ := findYamlNode(root, keys)
+	if found != len(keys) {
+		return nil
+	}
+	return node
+}
+
+// findYamlNode returns the deepest node found along the keys, the number of keys that were found and its position
+func findYamlNode(root *yaml.Node, keys []string) (*yaml.Node, int, int, int) {
+	node := root
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	line, column := node.Line, node.Column
+	found := 0
+	for _, key := range keys {
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == key {
+					line, column = node.Content[i].Line, node.Content[i].Column
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if index, err := strconv.Atoi(key); err == nil && index >= 0 && index < len(node.Content) {
+				next = node.Content[index]
+				line, column = next.Line, next.Column
+			}
+		}
+		if next == nil {
+			break
+		}
+		for next.Kind == yaml.AliasNode && next.Alias != nil {
+			next = next.Alias
+		}
+		node = next
+		found++
+	}
+	return node, found, line, column
+}
@@ -0,0 +1,193 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package transformer
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v3"
+)
+
+func TestTransformerSchemaIsUpToDate(t *testing.T) {
+	generatedBytes, err := json.Marshal(GenerateTransformerSchema())
+	if err != nil {
+		t.Fatalf("failed to marshal the generated schema. Error: %q", err)
+	}
+	var generated, embedded interface{}
+	if err := json.Unmarshal(generatedBytes, &generated); err != nil {
+		t.Fatalf("failed to unmarshal the generated schema. Error: %q", err)
+	}
+	if err := json.Unmarshal(GetTransformerSchema(), &embedded); err != nil {
+		t.Fatalf("failed to unmarshal the embedded schema. Error: %q", err)
+	}
+	if diff := cmp.Diff(generated, embedded); diff != "" {
+		t.Fatalf("the embedded schema is out of date, run go generate. Differences:\n%s", diff)
+	}
+}
+
+func TestBuiltInTransformersConformToSchema(t *testing.T) {
+	builtInPath := filepath.Join("..", "..", "assets", "built-in")
+	count := 0
+	if err := filepath.WalkDir(builtInPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || (filepath.Ext(path) != ".yaml" && filepath.Ext(path) != ".yml") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !isTransformerYaml(data) {
+			return nil
+		}
+		count++
+		violations, err := ValidateTransformerYaml(data)
+		if err != nil {
+			t.Errorf("failed to validate the transformer yaml %s . Error: %q", path, err)
+			return nil
+		}
+		for _, violation := range violations {
+			t.Errorf("%s %s", path, violation)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to walk the built-in assets. Error: %q", err)
+	}
+	if count == 0 {
+		t.Fatal("expected to find the built-in transformer yamls")
+	}
+}
+
+func TestValidateTransformerYaml(t *testing.T) {
+	data := []byte(`apiVersion: move2kube.konveyor.io/v1alpha1
+kind: Transformer
+metadata:
+  name: MyTransformer
+  labels:
+    move2kube.konveyor.io/built-in: [true]
+spec:
+  clas: Starlark
+  directoryDetect:
+    levels: 2
+  consumes:
+    Service:
+      merge: yes please
+      mode: PassThrough
+`)
+	violations, err := ValidateTransformerYaml(data)
+	if err != nil {
+		t.Fatalf("failed to validate the yaml. Error: %q", err)
+	}
+	type position struct {
+		Field string
+		Line  int
+	}
+	got := []position{}
+	for _, violation := range violations {
+		got = append(got, position{Field: violation.Field, Line: violation.Line})
+		if violation.Message == "" {
+			t.Errorf("expected a message for the violation %+v", violation)
+		}
+	}
+	want := []position{
+		{Field: "metadata.labels.move2kube.konveyor.io/built-in", Line: 6},
+		{Field: "spec", Line: 7},
+		{Field: "spec.clas", Line: 8},
+		{Field: "spec.directoryDetect.levels", Line: 10},
+		{Field: "spec.consumes.Service.merge", Line: 13},
+		{Field: "spec.consumes.Service.mode", Line: 14},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected violations. Differences:\n%s", diff)
+	}
+	valid := []byte("apiVersion: move2kube.konveyor.io/v1alpha1\nkind: Transformer\nmetadata:\n  name: MyTransformer\nspec:\n  class: Starlark\n  config:\n    anything: goes\n")
+	if violations, err := ValidateTransformerYaml(valid); err != nil || len(violations) != 0 {
+		t.Fatalf("expected the yaml to be valid. Violations: %+v Error: %v", violations, err)
+	}
+}
+
+func TestValidateTransformerConfig(t *testing.T) {
+	type buildConfig struct {
+		Dockerfile string `yaml:"dockerfile"`
+	}
+	type config struct {
+		StarFile string      `yaml:"starFile"`
+		Levels   int         `yaml:"levels"`
+		Build    buildConfig `yaml:"build"`
+	}
+	data := []byte(`apiVersion: move2kube.konveyor.io/v1alpha1
+kind: Transformer
+metadata:
+  name: MyTransformer
+spec:
+  class: Starlark
+  config:
+    starfile: transformer.star
+    levels: one
+    build:
+      dockerfile: [Dockerfile]
+`)
+	violations, err := ValidateTransformerConfig(data, GenerateConfigSchema(config{}))
+	if err != nil {
+		t.Fatalf("failed to validate the config. Error: %q", err)
+	}
+	type position struct {
+		Field string
+		Line  int
+	}
+	got := []position{}
+	for _, violation := range violations {
+		got = append(got, position{Field: violation.Field, Line: violation.Line})
+	}
+	want := []position{
+		{Field: "spec.config.starfile", Line: 8},
+		{Field: "spec.config.levels", Line: 9},
+		{Field: "spec.config.build.dockerfile", Line: 11},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected violations. Differences:\n%s", diff)
+	}
+	missing := []byte("apiVersion: move2kube.konveyor.io/v1alpha1\nkind: Transformer\nmetadata:\n  name: MyTransformer\nspec:\n  class: Starlark\n")
+	schema := GenerateConfigSchema(config{})
+	schema["required"] = []string{"starFile"}
+	violations, err = ValidateTransformerConfig(missing, schema)
+	if err != nil {
+		t.Fatalf("failed to validate the config. Error: %q", err)
+	}
+	if len(violations) != 1 || violations[0].Field != "spec.config" || violations[0].Line != 5 {
+		t.Fatalf("expected a violation for the missing config at the spec. Actual: %+v", violations)
+	}
+}
+
+// isTransformerYaml returns true if the yaml has the kind of a transformer
+func isTransformerYaml(data []byte) bool {
+	header := struct {
+		Kind string `yaml:"kind"`
+	}{}
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&header); err != nil {
+		return false
+	}
+	return header.Kind == TransformerKind
+}
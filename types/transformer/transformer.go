@@ -17,6 +17,9 @@
 package transformer
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/konveyor/move2kube/types"
 	"k8s.io/apimachinery/pkg/labels"
 )
@@ -36,6 +39,7 @@ type TransformerSpec struct {
 	FilePath           string                                 `yaml:"-" json:"-"`
 	Class              string                                 `yaml:"class" json:"class"`
 	Isolated           bool                                   `yaml:"isolated" json:"isolated"`
+	Timeout            string                                 `yaml:"timeout,omitempty" json:"timeout,omitempty"` // Duration like 30s or 5m, after which directory detect and transform are stopped
 	DirectoryDetect    DirectoryDetect                        `yaml:"directoryDetect" json:"directoryDetect"`
 	ExternalFiles      map[string]string                      `yaml:"externalFiles" json:"externalFiles"` // [source]destination
 	ConsumedArtifacts  map[ArtifactType]ArtifactProcessConfig `yaml:"consumes" json:"consumes"`
@@ -45,15 +49,39 @@ type TransformerSpec struct {
 	DependencySelector labels.Selector                        `yaml:"-" json:"-"`
 	OverrideSelector   labels.Selector                        `yaml:"-" json:"-"`
 	TemplatesDir       string                                 `yaml:"templates" json:"templates"` // Relative to yaml directory or working directory in image
+	TemplateOptions    TemplateOptions                        `yaml:"templateOptions,omitempty" json:"templateOptions,omitempty"`
 	Config             interface{}                            `yaml:"config" json:"config"`
+	ConfigSchema       string                                 `yaml:"configSchema,omitempty" json:"configSchema,omitempty"` // JSON schema of the config, relative to yaml directory
 	InvokedByDefault   InvokedByDefault                       `yaml:"invokedByDefault" json:"invokedByDefault"`
 }
 
+// GetTimeout returns the duration after which the directory detect and transform of the transformer are stopped, 0 if there is no timeout
+func (spec TransformerSpec) GetTimeout() (time.Duration, error) {
+	if spec.Timeout == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(spec.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the timeout '%s' . Error: %w", spec.Timeout, err)
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("the timeout '%s' is negative", spec.Timeout)
+	}
+	return timeout, nil
+}
+
 // InvokedByDefault stores config to toggle transformers invoke by default
 type InvokedByDefault struct {
 	Enabled bool `yaml:"enabled" json:"enabled"`
 }
 
+// TemplateOptions stores the config on how the files in the templates directory are filled
+type TemplateOptions struct {
+	OpeningDelimiter string   `yaml:"openingDelimiter,omitempty" json:"openingDelimiter,omitempty"` // default is {{
+	ClosingDelimiter string   `yaml:"closingDelimiter,omitempty" json:"closingDelimiter,omitempty"` // default is }}
+	VerbatimPaths    []string `yaml:"verbatimPaths,omitempty" json:"verbatimPaths,omitempty"`       // Glob patterns relative to the templates directory, matching files and directories that are copied without templating
+}
+
 // DirectoryDetect stores the config on how to iterate over the directories
 type DirectoryDetect struct {
 	Levels int `yaml:"levels"` // Supports only 0,1 and -1 currently - default behaviour is -1, when directory detect section is missing
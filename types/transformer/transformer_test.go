@@ -0,0 +1,54 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package transformer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetTimeout(t *testing.T) {
+	testCases := []struct {
+		name    string
+		timeout string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "no timeout", timeout: "", want: 0},
+		{name: "seconds", timeout: "30s", want: 30 * time.Second},
+		{name: "minutes and seconds", timeout: "1m30s", want: 90 * time.Second},
+		{name: "invalid duration", timeout: "thirty seconds", wantErr: true},
+		{name: "negative duration", timeout: "-5s", wantErr: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := TransformerSpec{Timeout: testCase.timeout}.GetTimeout()
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error for the timeout '%s' . Actual duration: %s", testCase.timeout, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get the timeout '%s' . Error: %q", testCase.timeout, err)
+			}
+			if got != testCase.want {
+				t.Fatalf("wrong timeout. Expected: %s Actual: %s", testCase.want, got)
+			}
+		})
+	}
+}